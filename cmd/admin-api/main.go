@@ -21,11 +21,13 @@ var version = "dev"
 
 func main() {
 	var configPath string
+	var profile string
 	var adminConfigPath string
 	var showVersion bool
 
 	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
 	fs.StringVar(&configPath, "config", "config/config.yaml", "Path to application YAML config")
+	fs.StringVar(&profile, "profile", os.Getenv(config.ProfileEnv), "Config profile overlaid on --config (default $"+config.ProfileEnv+")")
 	fs.StringVar(&adminConfigPath, "admin-config", "config/admin-api.yaml", "Path to admin API YAML config")
 	fs.BoolVar(&showVersion, "version", false, "Print version and exit")
 
@@ -39,7 +41,7 @@ func main() {
 		return
 	}
 
-	appCfg, err := config.Load(configPath)
+	appCfg, err := config.LoadProfile(configPath, profile)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
 		os.Exit(1)
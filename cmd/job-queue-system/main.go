@@ -2,12 +2,14 @@
 package main
 
 import (
+	"bufio"
 	"context"
-	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -17,6 +19,7 @@ import (
 	"github.com/flyingrobots/go-redis-work-queue/internal/producer"
 	"github.com/flyingrobots/go-redis-work-queue/internal/reaper"
 	"github.com/flyingrobots/go-redis-work-queue/internal/redisclient"
+	"github.com/flyingrobots/go-redis-work-queue/internal/scheduler"
 	"github.com/flyingrobots/go-redis-work-queue/internal/worker"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
@@ -31,38 +34,106 @@ func main() {
 	var adminQueue string
 	var adminN int
 	var adminYes bool
+	var adminTo string
+	var adminForce bool
+	var adminFilter string
+	var adminProject string
+	var adminScanLimit int64
+	var adminCursor string
+	var adminWindow time.Duration
+	var adminOutput string
+	var snapshotFile string
+	var scheduledWithin time.Duration
+	var scheduledMember string
+	var snapshotMode string
 	var benchCount int
 	var benchRate int
 	var benchPriority string
 	var benchTimeout time.Duration
 	var benchPayloadSize int
+	var benchMode string
 	var showVersion bool
+	var purgePattern string
+	var sloObjective float64
+	var sloLatency time.Duration
+	var sloWindow time.Duration
+	var fromFile string
+	var fromFileRate int
+	var profile string
+	var printConfig bool
 	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
 	fs.StringVar(&role, "role", "all", "Role to run: producer|worker|all|admin")
 	fs.StringVar(&configPath, "config", "config/config.yaml", "Path to YAML config")
-	fs.StringVar(&adminCmd, "admin-cmd", "", "Admin command: stats|peek|purge-dlq|purge-all|bench|stats-keys")
-	fs.StringVar(&adminQueue, "queue", "", "Queue alias or full key for admin peek (high|low|completed|dead_letter|jobqueue:...)")
-	fs.IntVar(&adminN, "n", 10, "Number of items for admin peek")
-	fs.BoolVar(&adminYes, "yes", false, "Automatic yes to prompts (dangerous operations)")
+	fs.StringVar(&profile, "profile", os.Getenv(config.ProfileEnv), "Config profile: deep-merge <config>.<profile>.yaml (e.g. config/config.prod.yaml) over --config (default $"+config.ProfileEnv+")")
+	fs.BoolVar(&printConfig, "print-config", false, "Print the effective config (base, profile overlay and env overrides merged) as YAML and exit")
+	fs.StringVar(&adminCmd, "admin-cmd", "", "Admin command: stats|peek|move|pause|resume|purge-dlq|trim-completed|dlq-analytics|throughput|time-to-drain|burn-rate|purge-all|purge-pattern|bench|stats-keys|workers|verify-consistency|repair-consistency|snapshot-export|snapshot-import|scheduled|cancel-scheduled|validate-config")
+	fs.StringVar(&adminQueue, "queue", "", "Queue alias or full key for admin peek, move, pause, resume or time-to-drain (high|low|completed|dead_letter|jobqueue:...), or for producer --from-file (default: producer.default_priority)")
+	fs.IntVar(&adminN, "n", 10, "Number of items for admin peek or move, or DLQ items to sample for dlq-analytics")
+	fs.StringVar(&adminTo, "to", "", "Destination queue alias or full key for admin move")
+	fs.BoolVar(&adminForce, "force", false, "Admin move: allow moving out of completed/dead_letter; purge-pattern: allow patterns without a literal prefix")
+	fs.StringVar(&adminFilter, "filter", "", "Admin peek: JSONPath filter expression (e.g. '$.user_id == \"123\"')")
+	fs.StringVar(&adminProject, "project", "", "Admin peek: comma-separated fields to include in each item")
+	fs.Int64Var(&adminScanLimit, "scan-limit", admin.DefaultPeekScanLimit, "Admin peek: most items a filtered peek inspects before returning the matches so far with a next_cursor")
+	fs.StringVar(&adminCursor, "cursor", "", "Admin peek: continue from the next_cursor of a previous peek")
+	fs.DurationVar(&adminWindow, "window", 10*time.Second, "Admin throughput: how long to sample rates")
+	fs.StringVar(&snapshotFile, "file", "-", "Admin snapshot-export/snapshot-import: NDJSON file path, - for stdout/stdin")
+	fs.StringVar(&snapshotMode, "mode", "merge", "Admin snapshot-import: merge|replace")
+	fs.DurationVar(&scheduledWithin, "within", 0, "Admin scheduled: only jobs due within this long (overdue ones included); 0 lists all")
+	fs.StringVar(&scheduledMember, "member", "", "Admin cancel-scheduled: the job's member as printed by scheduled")
+	fs.StringVar(&purgePattern, "pattern", "", "Admin purge-pattern: Redis glob of the keys to delete (e.g. 'jobqueue:tmp:*'), inside the config namespace if one is set")
+	fs.Float64Var(&sloObjective, "slo-objective", 0.95, "Admin burn-rate: fraction of jobs that must meet --slo-latency")
+	fs.DurationVar(&sloLatency, "slo-latency", 30*time.Second, "Admin burn-rate: creation-to-completion latency a job must meet")
+	fs.DurationVar(&sloWindow, "slo-window", time.Hour, "Admin burn-rate: long alerting window; the short window is 1/12 of it")
+	fs.StringVar(&fromFile, "from-file", "", "Producer: enqueue each line of this NDJSON file, - for stdin, then exit instead of scanning producer.scan_dir")
+	fs.IntVar(&fromFileRate, "rate", 0, "Producer --from-file: enqueue rate jobs/sec (0 = unlimited)")
+	fs.StringVar(&adminOutput, "output", outputJSON, "Admin and producer --from-file output format: json|json-compact|table|yaml")
+	fs.BoolVar(&adminYes, "yes", false, "Automatic yes to prompts (dangerous operations); without it, interactive runs ask you to type the queue or namespace name")
 	fs.BoolVar(&showVersion, "version", false, "Print version and exit")
 	fs.IntVar(&benchCount, "bench-count", 1000, "Admin bench: number of jobs")
 	fs.IntVar(&benchRate, "bench-rate", 500, "Admin bench: enqueue rate jobs/sec")
 	fs.StringVar(&benchPriority, "bench-priority", "low", "Admin bench: priority/queue alias")
 	fs.DurationVar(&benchTimeout, "bench-timeout", 60*time.Second, "Admin bench: timeout to wait for completion")
 	fs.IntVar(&benchPayloadSize, "bench-payload-size", 1024, "Admin bench: payload size in bytes")
+	fs.StringVar(&benchMode, "bench-mode", admin.BenchOpen, "Admin bench: open (enqueue at rate, wait for the completed count) or closed (track each job's enqueue-to-complete latency)")
 	_ = fs.Parse(os.Args[1:])
 
 	if showVersion {
 		fmt.Println(version)
 		return
 	}
+	if !validOutputFormat(adminOutput) {
+		fmt.Fprintf(os.Stderr, "invalid --output %q: want json, json-compact, table or yaml\n", adminOutput)
+		os.Exit(2)
+	}
+
+	// validate-config runs before Load so every problem is listed, not just
+	// the ones Load would stop on.
+	if role == "admin" && adminCmd == "validate-config" {
+		name := configPath
+		if profile != "" {
+			name += " (profile " + profile + ")"
+		}
+		if _, err := config.LoadStrictProfile(configPath, profile); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s: ok\n", name)
+		return
+	}
 
 	// Load configuration
-	cfg, err := config.Load(configPath)
+	cfg, err := config.LoadProfile(configPath, profile)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
 		os.Exit(1)
 	}
+	if printConfig {
+		if err := writeOutput(os.Stdout, outputYAML, config.Effective(cfg)); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to print config: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 	// Setup logging
 	logger, err := obs.NewLogger(cfg.Observability.LogLevel)
 	if err != nil {
@@ -72,25 +143,44 @@ func main() {
 	defer logger.Sync()
 
 	// Setup tracing (optional)
+	if cfg.Observability.Tracing.ServiceVersion == "" {
+		cfg.Observability.Tracing.ServiceVersion = version
+	}
 	tp, err := obs.MaybeInitTracing(cfg)
 	if err != nil {
 		logger.Warn("tracing init failed", obs.Err(err))
 	}
 	if tp != nil {
-		defer func() { _ = tp.Shutdown(context.Background()) }()
+		defer func() {
+			// Flush queued spans, but don't hang exit on a dead collector.
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := obs.TracerShutdown(ctx, tp); err != nil {
+				logger.Warn("tracing shutdown failed", obs.Err(err))
+			}
+		}()
 	}
 
 	// Redis client
 	rdb := redisclient.New(cfg)
 	defer rdb.Close()
+	// clients for the clusters named in cfg.Clusters; queues without a
+	// cluster_routes entry stay on rdb
+	router, err := redisclient.NewRouter(cfg, rdb)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to init redis clusters: %v\n", err)
+		os.Exit(1)
+	}
+	defer router.Close()
 
 	// HTTP server: metrics, healthz, readyz (skip for admin CLI)
 	if role != "admin" {
-		readyCheck := func(c context.Context) error {
-			_, err := rdb.Ping(c).Result()
-			return err
+		obs.RegisterHealthCheck("redis", obs.RedisHealthCheck(rdb, 100*time.Millisecond))
+		obs.RegisterHealthCheck("queues", obs.QueueHealthCheck(cfg, rdb))
+		if role == "worker" || role == "all" {
+			obs.RegisterHealthCheck("worker_heartbeats", workerHeartbeatCheck(cfg, rdb))
 		}
-		httpSrv := obs.StartHTTPServer(cfg, readyCheck)
+		httpSrv := obs.StartHTTPServer(cfg, nil)
 		defer func() { _ = httpSrv.Shutdown(context.Background()) }()
 	}
 
@@ -121,44 +211,162 @@ func main() {
 	switch role {
 	case "producer":
 		prod := producer.New(cfg, rdb, logger)
+		prod.SetRouter(router)
+		if fromFile != "" {
+			runFromFile(ctx, prod, logger, fromFile, adminQueue, fromFileRate, adminOutput)
+			return
+		}
 		if err := prod.Run(ctx); err != nil {
 			logger.Fatal("producer error", obs.Err(err))
 		}
 	case "worker":
 		wrk := worker.New(cfg, rdb, logger)
-		rep := reaper.New(cfg, rdb, logger)
-		go rep.Run(ctx)
+		wrk.SetRouter(router)
+		wrk.Use(worker.RecoverMiddleware(logger), worker.LatencyMiddleware())
+		if cfg.Worker.Mode != config.ModeStream {
+			// stream mode reclaims stalled jobs with XAUTOCLAIM instead
+			startReapers(ctx, cfg, router, logger)
+		}
+		sched := scheduler.New(cfg, rdb, logger)
+		sched.SetRouter(router)
+		go sched.Run(ctx)
+		sched.RunAging(ctx)
+		go wrk.WatchReload(ctx, reloadSignals(), reloadConfig(configPath, profile))
 		if err := wrk.Run(ctx); err != nil {
 			logger.Fatal("worker error", obs.Err(err))
 		}
 	case "all":
 		prod := producer.New(cfg, rdb, logger)
+		prod.SetRouter(router)
 		wrk := worker.New(cfg, rdb, logger)
-		rep := reaper.New(cfg, rdb, logger)
-		go rep.Run(ctx)
+		wrk.SetRouter(router)
+		wrk.Use(worker.RecoverMiddleware(logger), worker.LatencyMiddleware())
+		if cfg.Worker.Mode != config.ModeStream {
+			// stream mode reclaims stalled jobs with XAUTOCLAIM instead
+			startReapers(ctx, cfg, router, logger)
+		}
+		sched := scheduler.New(cfg, rdb, logger)
+		sched.SetRouter(router)
+		go sched.Run(ctx)
+		sched.RunAging(ctx)
 		go func() {
 			if err := prod.Run(ctx); err != nil {
 				logger.Error("producer error", obs.Err(err))
 				cancel()
 			}
 		}()
+		go wrk.WatchReload(ctx, reloadSignals(), reloadConfig(configPath, profile))
 		if err := wrk.Run(ctx); err != nil {
 			logger.Fatal("worker error", obs.Err(err))
 		}
 	case "admin":
-		runAdmin(ctx, cfg, rdb, logger, adminCmd, adminQueue, adminN, adminYes, benchCount, benchRate, benchPriority, benchPayloadSize, benchTimeout)
+		peekOpts := admin.PeekOptions{Filter: adminFilter, Project: admin.ParseProjection(adminProject), ScanLimit: adminScanLimit, Cursor: adminCursor}
+		if adminCmd == "snapshot-export" || adminCmd == "snapshot-import" {
+			runSnapshot(ctx, cfg, rdb, logger, adminCmd, snapshotFile, snapshotMode, adminYes)
+			return
+		}
+		if adminCmd == "scheduled" || adminCmd == "cancel-scheduled" {
+			runScheduled(ctx, cfg, rdb, logger, adminCmd, adminOutput, adminQueue, adminN, scheduledWithin, scheduledMember, adminYes)
+			return
+		}
+		if adminCmd == "burn-rate" {
+			target := admin.SLOTarget{Objective: sloObjective, Latency: sloLatency}
+			res, err := admin.BurnRate(ctx, cfg, router.For(cfg.Worker.CompletionStream.Stream), target, sloWindow)
+			if err != nil {
+				logger.Fatal("admin burn-rate error", obs.Err(err))
+			}
+			if err := writeOutput(os.Stdout, adminOutput, res); err != nil {
+				logger.Fatal("admin output encode error", obs.Err(err), obs.String("command", adminCmd), obs.String("output", adminOutput))
+			}
+			return
+		}
+		if adminCmd == "purge-pattern" {
+			runPurgePattern(ctx, cfg, rdb, logger, adminOutput, purgePattern, adminForce, adminYes)
+			return
+		}
+		runAdmin(ctx, cfg, rdb, logger, adminCmd, adminOutput, adminQueue, adminN, adminWindow, peekOpts, adminTo, adminForce, adminYes, benchCount, benchRate, benchPriority, benchPayloadSize, benchTimeout, benchMode)
 		return
 	default:
 		logger.Fatal("unknown role", obs.String("role", role))
 	}
 }
 
-func runAdmin(ctx context.Context, cfg *config.Config, rdb *redis.Client, logger *zap.Logger, cmd, queue string, n int, yes bool, benchCount, benchRate int, benchPriority string, benchPayloadSize int, benchTimeout time.Duration) {
+// reloadSignals delivers the SIGHUPs that ask a worker to reload its config.
+func reloadSignals() <-chan os.Signal {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGHUP)
+	return ch
+}
+
+// reloadConfig re-reads the config the process started with, for
+// Worker.WatchReload.
+func reloadConfig(configPath, profile string) func() (*config.Config, error) {
+	return func() (*config.Config, error) {
+		cfg, err := config.LoadProfile(configPath, profile)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Observability.Tracing.ServiceVersion == "" {
+			cfg.Observability.Tracing.ServiceVersion = version
+		}
+		return cfg, nil
+	}
+}
+
+// startReapers runs a reaper on every cluster, since each holds its own
+// processing lists, and reports the stalest one as the "reaper" health
+// check.
+func startReapers(ctx context.Context, cfg *config.Config, router *redisclient.Router, logger *zap.Logger) {
+	var reapers []*reaper.Reaper
+	for _, c := range router.Clients() {
+		r := reaper.New(cfg, c, logger)
+		reapers = append(reapers, r)
+		go r.Run(ctx)
+	}
+	oldestRun := func() time.Time {
+		var oldest time.Time
+		for _, r := range reapers {
+			st := r.Stats()
+			last := st.LastRun
+			if last.IsZero() {
+				last = st.StartedAt
+			}
+			if oldest.IsZero() || last.Before(oldest) {
+				oldest = last
+			}
+		}
+		return oldest
+	}
+	// a slow, throttled sweep should not fail the check before a stuck one would
+	maxAge := max(30*time.Second, 6*cfg.Worker.Reaper.Interval)
+	obs.RegisterHealthCheck("reaper", obs.FreshnessHealthCheck("last reaper scan", oldestRun, maxAge))
+}
+
+// workerHeartbeatCheck warns about workers whose processing list holds
+// jobs but whose heartbeat has expired: they most likely died mid-job.
+func workerHeartbeatCheck(cfg *config.Config, rdb *redis.Client) obs.HealthCheckFunc {
+	return func(ctx context.Context) error {
+		workers, err := admin.Workers(ctx, cfg, rdb)
+		if err != nil {
+			return err
+		}
+		var stale []string
+		for _, w := range workers {
+			if w.ReapCandidate {
+				stale = append(stale, w.ID)
+			}
+		}
+		if len(stale) > 0 {
+			return obs.HealthWarning("%d of %d workers hold jobs without a heartbeat: %v", len(stale), len(workers), stale)
+		}
+		return nil
+	}
+}
+
+func runAdmin(ctx context.Context, cfg *config.Config, rdb *redis.Client, logger *zap.Logger, cmd, output, queue string, n int, window time.Duration, peekOpts admin.PeekOptions, to string, force bool, yes bool, benchCount, benchRate int, benchPriority string, benchPayloadSize int, benchTimeout time.Duration, benchMode string) {
 	encode := func(label string, v any) {
-		enc := json.NewEncoder(os.Stdout)
-		enc.SetIndent("", "  ")
-		if err := enc.Encode(v); err != nil {
-			logger.Fatal("admin json encode error", obs.Err(err), obs.String("command", label))
+		if err := writeOutput(os.Stdout, output, v); err != nil {
+			logger.Fatal("admin output encode error", obs.Err(err), obs.String("command", label), obs.String("output", output))
 		}
 	}
 
@@ -173,22 +381,89 @@ func runAdmin(ctx context.Context, cfg *config.Config, rdb *redis.Client, logger
 		if queue == "" {
 			logger.Fatal("admin peek requires --queue")
 		}
-		res, err := admin.Peek(ctx, cfg, rdb, queue, int64(n))
+		res, err := admin.PeekWithOptions(ctx, cfg, rdb, queue, int64(n), peekOpts)
 		if err != nil {
 			logger.Fatal("admin peek error", obs.Err(err))
 		}
 		encode("peek", res)
+	case "move":
+		if queue == "" || to == "" {
+			logger.Fatal("admin move requires --queue and --to")
+		}
+		move := admin.MoveJobs
+		if force {
+			move = admin.MoveJobsForce
+		}
+		moved, err := move(ctx, cfg, rdb, queue, to, n)
+		if err != nil {
+			logger.Fatal("admin move error", obs.Err(err), obs.Int("moved", moved))
+		}
+		encode("move", struct {
+			From  string `json:"from"`
+			To    string `json:"to"`
+			Moved int    `json:"moved"`
+		}{From: queue, To: to, Moved: moved})
+	case "pause", "resume":
+		if queue == "" {
+			logger.Fatal("admin " + cmd + " requires --queue")
+		}
+		op := admin.PauseQueue
+		if cmd == "resume" {
+			op = admin.ResumeQueue
+		}
+		if err := op(ctx, cfg, rdb, queue); err != nil {
+			logger.Fatal("admin "+cmd+" error", obs.Err(err))
+		}
+		encode(cmd, struct {
+			Queue  string `json:"queue"`
+			Paused bool   `json:"paused"`
+		}{Queue: queue, Paused: cmd == "pause"})
 	case "purge-dlq":
-		if !yes {
-			logger.Fatal("refusing to purge without --yes")
+		if !confirmed(yes, "Purge every job in the dead letter list "+cfg.Worker.DeadLetterList+".", cfg.Worker.DeadLetterList) {
+			logger.Fatal("refusing to purge without --yes or a typed confirmation")
 		}
 		if err := admin.PurgeDLQ(ctx, cfg, rdb); err != nil {
 			logger.Fatal("admin purge-dlq error", obs.Err(err))
 		}
 		fmt.Println("dead letter queue purged")
+	case "trim-completed":
+		if !confirmed(yes, "Trim the completed list "+cfg.Worker.CompletedList+" to worker.completed_retention.", cfg.Worker.CompletedList) {
+			logger.Fatal("refusing to trim without --yes or a typed confirmation")
+		}
+		cr := cfg.Worker.CompletedRetention
+		if cr.KeepLast <= 0 && cr.MaxAge <= 0 {
+			logger.Fatal("set worker.completed_retention.keep_last or max_age to trim")
+		}
+		res, err := admin.TrimCompleted(ctx, cfg, rdb, cr.KeepLast, cr.MaxAge)
+		if err != nil {
+			logger.Fatal("admin trim-completed error", obs.Err(err))
+		}
+		encode("trim-completed", res)
+	case "dlq-analytics":
+		res, err := admin.DLQAnalytics(ctx, cfg, rdb, n)
+		if err != nil {
+			logger.Fatal("admin dlq-analytics error", obs.Err(err))
+		}
+		encode("dlq-analytics", res)
+	case "throughput":
+		res, err := admin.Throughput(ctx, cfg, rdb, window)
+		if err != nil {
+			logger.Fatal("admin throughput error", obs.Err(err))
+		}
+		encode("throughput", res)
+	case "time-to-drain":
+		if queue == "" {
+			logger.Fatal("admin time-to-drain requires --queue")
+		}
+		res, err := admin.TimeToDrain(ctx, cfg, rdb, queue)
+		if err != nil {
+			logger.Fatal("admin time-to-drain error", obs.Err(err))
+		}
+		encode("time-to-drain", res)
 	case "purge-all":
-		if !yes {
-			logger.Fatal("refusing to purge without --yes")
+		ns := keyNamespace(cfg)
+		if !confirmed(yes, "Purge every queue, the completed and dead letter lists and all processing lists under "+ns+".", ns) {
+			logger.Fatal("refusing to purge without --yes or a typed confirmation")
 		}
 		n, err := admin.PurgeAll(ctx, cfg, rdb)
 		if err != nil {
@@ -198,18 +473,193 @@ func runAdmin(ctx context.Context, cfg *config.Config, rdb *redis.Client, logger
 			Purged int64 `json:"purged"`
 		}{Purged: n})
 	case "bench":
-		res, err := admin.Bench(ctx, cfg, rdb, benchPriority, benchCount, benchRate, benchPayloadSize, benchTimeout)
+		res, err := admin.BenchWithOptions(ctx, cfg, rdb, benchPriority, benchCount, benchRate, benchPayloadSize, benchTimeout, admin.BenchOptions{Mode: benchMode})
 		if err != nil {
 			logger.Fatal("admin bench error", obs.Err(err))
 		}
 		encode("bench", res)
+	case "workers":
+		res, err := admin.Workers(ctx, cfg, rdb)
+		if err != nil {
+			logger.Fatal("admin workers error", obs.Err(err))
+		}
+		encode("workers", res)
 	case "stats-keys":
 		res, err := admin.StatsKeys(ctx, cfg, rdb)
 		if err != nil {
 			logger.Fatal("admin stats-keys error", obs.Err(err))
 		}
 		encode("stats-keys", res)
+	case "verify-consistency":
+		res, err := admin.VerifyConsistency(ctx, cfg, rdb)
+		if err != nil {
+			logger.Fatal("admin verify-consistency error", obs.Err(err))
+		}
+		encode("verify-consistency", res)
+		if !res.Healthy {
+			// non-zero exit lets CI health checks fail on the report
+			os.Exit(1)
+		}
+	case "repair-consistency":
+		if !yes {
+			logger.Fatal("refusing to repair without --yes")
+		}
+		res, err := admin.RepairConsistency(ctx, cfg, rdb)
+		if err != nil {
+			logger.Fatal("admin repair-consistency error", obs.Err(err))
+		}
+		encode("repair-consistency", res)
 	default:
 		logger.Fatal("unknown admin command", obs.String("cmd", cmd))
 	}
 }
+
+func runScheduled(ctx context.Context, cfg *config.Config, rdb *redis.Client, logger *zap.Logger, cmd, output, queue string, n int, within time.Duration, member string, yes bool) {
+	switch cmd {
+	case "scheduled":
+		jobs, err := admin.ListScheduledWithOptions(ctx, cfg, rdb, queue, n, admin.ScheduledOptions{DueWithin: within})
+		if err != nil {
+			logger.Fatal("admin scheduled error", obs.Err(err))
+		}
+		if err := writeOutput(os.Stdout, output, jobs); err != nil {
+			logger.Fatal("admin output encode error", obs.Err(err), obs.String("command", cmd), obs.String("output", output))
+		}
+	case "cancel-scheduled":
+		if queue == "" || member == "" {
+			logger.Fatal("admin cancel-scheduled requires --queue and --member")
+		}
+		if !confirmed(yes, "Cancel the scheduled job "+member+".", queue) {
+			logger.Fatal("refusing to cancel a scheduled job without --yes or a typed confirmation")
+		}
+		if err := admin.CancelScheduled(ctx, cfg, rdb, queue, member); err != nil {
+			logger.Fatal("admin cancel-scheduled error", obs.Err(err))
+		}
+		if err := writeOutput(os.Stdout, output, struct {
+			Queue     string `json:"queue"`
+			Cancelled bool   `json:"cancelled"`
+		}{Queue: queue, Cancelled: true}); err != nil {
+			logger.Fatal("admin output encode error", obs.Err(err), obs.String("command", cmd), obs.String("output", output))
+		}
+	}
+}
+
+// confirmed reports whether a destructive admin command may go ahead:
+// with --yes, or once the operator types expected at the terminal.
+func confirmed(yes bool, prompt, expected string) bool {
+	return yes || admin.ConfirmDestructive(prompt, expected)
+}
+
+// keyNamespace is the config's namespace or, without one, the prefix up to
+// the first colon of the dead letter list's key, which the queues share in
+// the usual layout; operators type it to confirm commands that sweep the
+// whole system.
+func keyNamespace(cfg *config.Config) string {
+	if cfg.Namespace != "" {
+		return cfg.Namespace
+	}
+	ns, _, _ := strings.Cut(cfg.Worker.DeadLetterList, ":")
+	return ns
+}
+
+// runPurgePattern counts the keys matching pattern, and deletes them only
+// with --yes or, at a terminal, once the operator types the pattern back.
+func runPurgePattern(ctx context.Context, cfg *config.Config, rdb *redis.Client, logger *zap.Logger, output, pattern string, force, yes bool) {
+	if pattern == "" {
+		logger.Fatal("admin purge-pattern requires --pattern")
+	}
+	purge := admin.PurgeMatching
+	if force {
+		purge = admin.PurgeMatchingForce
+	}
+	matched, deleted, err := purge(ctx, cfg, rdb, pattern, !yes)
+	if err == nil && !yes && matched > 0 && admin.ConfirmDestructive(fmt.Sprintf("Delete the %d keys matching %s.", matched, pattern), pattern) {
+		yes = true
+		matched, deleted, err = purge(ctx, cfg, rdb, pattern, false)
+	}
+	if err != nil {
+		logger.Fatal("admin purge-pattern error", obs.Err(err), obs.Int("deleted", deleted))
+	}
+	if err := writeOutput(os.Stdout, output, struct {
+		Pattern string `json:"pattern"`
+		DryRun  bool   `json:"dry_run"`
+		Matched int    `json:"matched"`
+		Deleted int    `json:"deleted"`
+	}{Pattern: pattern, DryRun: !yes, Matched: matched, Deleted: deleted}); err != nil {
+		logger.Fatal("admin output encode error", obs.Err(err), obs.String("command", "purge-pattern"), obs.String("output", output))
+	}
+}
+
+func runSnapshot(ctx context.Context, cfg *config.Config, rdb *redis.Client, logger *zap.Logger, cmd, file, mode string, yes bool) {
+	switch cmd {
+	case "snapshot-export":
+		out := io.Writer(os.Stdout)
+		if file != "-" {
+			f, err := os.Create(file)
+			if err != nil {
+				logger.Fatal("admin snapshot-export error", obs.Err(err))
+			}
+			defer f.Close()
+			out = f
+		}
+		bw := bufio.NewWriter(out)
+		if err := admin.ExportSnapshot(ctx, cfg, rdb, bw); err != nil {
+			logger.Fatal("admin snapshot-export error", obs.Err(err))
+		}
+		if err := bw.Flush(); err != nil {
+			logger.Fatal("admin snapshot-export error", obs.Err(err))
+		}
+	case "snapshot-import":
+		if admin.ImportMode(mode) == admin.ImportReplace && !yes {
+			// a snapshot read from stdin leaves no terminal to confirm on
+			ns := keyNamespace(cfg)
+			if file == "-" || !admin.ConfirmDestructive("Replace the queues under "+ns+" with the snapshot.", ns) {
+				logger.Fatal("refusing to replace queues without --yes or a typed confirmation")
+			}
+		}
+		in := io.Reader(os.Stdin)
+		if file != "-" {
+			f, err := os.Open(file)
+			if err != nil {
+				logger.Fatal("admin snapshot-import error", obs.Err(err))
+			}
+			defer f.Close()
+			in = f
+		}
+		if err := admin.ImportSnapshot(ctx, cfg, rdb, bufio.NewReader(in), admin.ImportMode(mode)); err != nil {
+			logger.Fatal("admin snapshot-import error", obs.Err(err))
+		}
+		logger.Info("snapshot imported", obs.String("mode", mode))
+	}
+}
+
+// runFromFile enqueues the NDJSON lines of file (- for stdin), logging
+// progress, and prints a summary. It exits with status 1 if any line was
+// malformed or rejected by a full queue.
+func runFromFile(ctx context.Context, prod *producer.Producer, logger *zap.Logger, file, queue string, rate int, output string) {
+	in := io.Reader(os.Stdin)
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			logger.Fatal("producer from-file error", obs.Err(err))
+		}
+		defer f.Close()
+		in = f
+	}
+	res, err := prod.EnqueueFrom(ctx, in, producer.EnqueueFromOptions{
+		Queue: queue,
+		Rate:  rate,
+		Progress: func(r producer.EnqueueFromResult) {
+			logger.Info("from-file progress", obs.String("queue", r.Queue), obs.Int("lines", r.Lines), obs.Int("enqueued", r.Enqueued),
+				obs.Int("malformed", r.Malformed), obs.Int("rejected", r.Rejected), zap.Duration("elapsed", r.Duration))
+		},
+	})
+	if werr := writeOutput(os.Stdout, output, res); werr != nil {
+		logger.Fatal("producer output encode error", obs.Err(werr), obs.String("output", output))
+	}
+	if err != nil {
+		logger.Fatal("producer from-file error", obs.Err(err), obs.Int("lines_read", res.Lines))
+	}
+	if res.Malformed > 0 || res.Rejected > 0 {
+		os.Exit(1)
+	}
+}
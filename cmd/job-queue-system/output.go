@@ -0,0 +1,281 @@
+// Copyright 2025 James Ross
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/flyingrobots/go-redis-work-queue/internal/admin"
+	"gopkg.in/yaml.v3"
+)
+
+// Admin output formats accepted by --output.
+const (
+	outputJSON        = "json"
+	outputJSONCompact = "json-compact"
+	outputTable       = "table"
+	outputYAML        = "yaml"
+)
+
+func validOutputFormat(format string) bool {
+	switch format {
+	case outputJSON, outputJSONCompact, outputTable, outputYAML:
+		return true
+	}
+	return false
+}
+
+// writeOutput renders an admin command result in format. JSON and YAML use
+// the result's json field names. Tables have dedicated layouts for stats,
+// stats-keys, peek and bench results; anything else is flattened into
+// key/value rows. Table headers are colored only when w is a terminal.
+func writeOutput(w io.Writer, format string, v any) error {
+	switch format {
+	case outputJSONCompact:
+		return json.NewEncoder(w).Encode(v)
+	case outputYAML:
+		doc, err := jsonDocument(v)
+		if err != nil {
+			return err
+		}
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(doc); err != nil {
+			return err
+		}
+		return enc.Close()
+	case outputTable:
+		return writeTable(w, v)
+	default:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+}
+
+// jsonDocument converts v to its generic JSON form, so other encoders see
+// the same field names and values as the JSON output.
+func jsonDocument(v any) (any, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return numbersToValues(doc), nil
+}
+
+// numbersToValues replaces json.Number with int64 or float64 so YAML prints
+// plain numbers rather than quoted strings.
+func numbersToValues(v any) any {
+	switch t := v.(type) {
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i
+		}
+		f, _ := t.Float64()
+		return f
+	case map[string]any:
+		for k, e := range t {
+			t[k] = numbersToValues(e)
+		}
+	case []any:
+		for i, e := range t {
+			t[i] = numbersToValues(e)
+		}
+	}
+	return v
+}
+
+// table is a set of aligned rows under a header.
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+func (t *table) add(cells ...string) { t.rows = append(t.rows, cells) }
+
+var (
+	tableHeaderColor = lipgloss.AdaptiveColor{Light: "#0969da", Dark: "#58a6ff"}
+	tableMutedColor  = lipgloss.AdaptiveColor{Light: "#656d76", Dark: "#8b949e"}
+)
+
+// render writes t with columns padded to their widest cell and trailing
+// spaces trimmed, so long values such as peeked payloads stay unpadded.
+func (t *table) render(w io.Writer, r *lipgloss.Renderer) {
+	widths := make([]int, len(t.header))
+	for _, row := range append([][]string{t.header}, t.rows...) {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+	header := r.NewStyle().Bold(true).Foreground(tableHeaderColor)
+	line := func(row []string, style *lipgloss.Style) {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			if i < len(row)-1 {
+				cell += strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+			}
+			if style != nil {
+				cell = style.Render(cell)
+			}
+			cells[i] = cell
+		}
+		fmt.Fprintln(w, strings.TrimRight(strings.Join(cells, "  "), " "))
+	}
+	line(t.header, &header)
+	for _, row := range t.rows {
+		line(row, nil)
+	}
+}
+
+func writeTable(w io.Writer, v any) error {
+	r := lipgloss.NewRenderer(w)
+	muted := r.NewStyle().Foreground(tableMutedColor)
+	var tables []*table
+	var notes []string
+
+	switch res := v.(type) {
+	case admin.StatsResult:
+		queues := &table{header: []string{"QUEUE", "LENGTH", "PAUSED"}}
+		for _, name := range sortedKeys(res.Queues) {
+			paused := ""
+			for key, p := range res.Paused {
+				if p && strings.HasSuffix(name, "("+key+")") {
+					paused = "yes"
+				}
+			}
+			queues.add(name, fmt.Sprint(res.Queues[name]), paused)
+		}
+		tables = append(tables, queues)
+		if len(res.ProcessingLists) > 0 {
+			processing := &table{header: []string{"PROCESSING LIST", "ITEMS"}}
+			for _, name := range sortedKeys(res.ProcessingLists) {
+				processing.add(name, fmt.Sprint(res.ProcessingLists[name]))
+			}
+			tables = append(tables, processing)
+		}
+		notes = append(notes, fmt.Sprintf("heartbeats: %d", res.Heartbeats))
+	case admin.KeysStats:
+		queues := &table{header: []string{"QUEUE", "LENGTH"}}
+		for _, name := range sortedKeys(res.QueueLengths) {
+			queues.add(name, fmt.Sprint(res.QueueLengths[name]))
+		}
+		keys := &table{header: []string{"KEY", "VALUE"}}
+		keys.add("processing_lists", fmt.Sprint(res.ProcessingLists))
+		keys.add("processing_items", fmt.Sprint(res.ProcessingItems))
+		keys.add("heartbeats", fmt.Sprint(res.Heartbeats))
+		if res.RateLimitKey != "" {
+			keys.add("rate_limit_key", res.RateLimitKey)
+			if res.RateLimitTTL != "" {
+				keys.add("rate_limit_ttl", res.RateLimitTTL)
+			}
+		}
+		tables = append(tables, queues, keys)
+	case admin.PeekResult:
+		items := &table{header: []string{"#", "ITEM"}}
+		for i, item := range res.Items {
+			items.add(fmt.Sprint(i+1), item)
+		}
+		tables = append(tables, items)
+		note := fmt.Sprintf("queue: %s  items: %d", res.Queue, len(res.Items))
+		if res.Scanned > 0 {
+			note += fmt.Sprintf("  scanned: %d", res.Scanned)
+		}
+		notes = append(notes, note)
+	case admin.BenchResult:
+		bench := &table{header: []string{"METRIC", "VALUE"}}
+		bench.add("count", fmt.Sprint(res.Count))
+		bench.add("duration", res.Duration.String())
+		bench.add("throughput", fmt.Sprintf("%.1f jobs/s", res.Throughput))
+		bench.add("p50_latency", res.P50.String())
+		bench.add("p95_latency", res.P95.String())
+		tables = append(tables, bench)
+	case []admin.WorkerInfo:
+		workers := &table{header: []string{"WORKER", "HOST", "PID", "LAST SEEN", "JOB", "QUEUE", "PROCESSING", "STATUS"}}
+		for _, wi := range res {
+			seen, pid, status := "-", "", "stale"
+			if !wi.LastHeartbeat.IsZero() {
+				seen = wi.LastHeartbeat.Format(time.RFC3339)
+			}
+			if wi.PID > 0 {
+				pid = fmt.Sprint(wi.PID)
+			}
+			switch {
+			case wi.Alive:
+				status = "alive"
+			case wi.ReapCandidate:
+				status = "REAP"
+			}
+			workers.add(wi.ID, wi.Host, pid, seen, wi.JobID, wi.Queue, fmt.Sprint(wi.Processing), status)
+		}
+		tables = append(tables, workers)
+		notes = append(notes, admin.WorkerSummary(res))
+	default:
+		doc, err := jsonDocument(v)
+		if err != nil {
+			return err
+		}
+		kv := &table{header: []string{"KEY", "VALUE"}}
+		flattenRows(kv, "", doc)
+		tables = append(tables, kv)
+	}
+
+	for i, t := range tables {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		t.render(w, r)
+	}
+	for _, note := range notes {
+		fmt.Fprintln(w, muted.Render(note))
+	}
+	return nil
+}
+
+// flattenRows adds one row per scalar in doc, keyed by its dotted path.
+func flattenRows(t *table, prefix string, doc any) {
+	join := func(k string) string {
+		if prefix == "" {
+			return k
+		}
+		return prefix + "." + k
+	}
+	switch d := doc.(type) {
+	case map[string]any:
+		for _, k := range sortedKeys(d) {
+			flattenRows(t, join(k), d[k])
+		}
+	case []any:
+		if len(d) == 0 {
+			t.add(prefix, "[]")
+		}
+		for i, e := range d {
+			flattenRows(t, fmt.Sprintf("%s[%d]", prefix, i), e)
+		}
+	case nil:
+		t.add(prefix, "")
+	default:
+		t.add(prefix, fmt.Sprint(d))
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 James Ross
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/admin"
+)
+
+func TestWriteOutputFormats(t *testing.T) {
+	res := admin.BenchResult{Count: 10, Duration: 2 * time.Second, Throughput: 5, P50: time.Millisecond, P95: 3 * time.Millisecond}
+
+	var buf bytes.Buffer
+	if err := writeOutput(&buf, outputJSONCompact, res); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); strings.Count(got, "\n") != 1 || !strings.Contains(got, `"throughput_jobs_per_sec":5`) {
+		t.Fatalf("expected one compact JSON line, got %q", got)
+	}
+
+	buf.Reset()
+	if err := writeOutput(&buf, outputYAML, res); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); !strings.Contains(got, "count: 10\n") || !strings.Contains(got, "throughput_jobs_per_sec: 5\n") {
+		t.Fatalf("expected YAML with json field names, got %q", got)
+	}
+
+	buf.Reset()
+	if err := writeOutput(&buf, outputTable, res); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); !strings.Contains(got, "p95_latency  3ms") || strings.Contains(got, "\x1b[") {
+		t.Fatalf("expected an uncolored aligned table when not a TTY, got %q", got)
+	}
+}
+
+func TestWriteOutputStatsTable(t *testing.T) {
+	res := admin.StatsResult{
+		Queues: map[string]int64{
+			"high (jobqueue:high_priority)":      3,
+			"dead_letter (jobqueue:dead_letter)": 12,
+		},
+		ProcessingLists: map[string]int64{"jobqueue:worker:w1:processing": 1},
+		Heartbeats:      1,
+		Paused:          map[string]bool{"jobqueue:high_priority": true},
+	}
+	var buf bytes.Buffer
+	if err := writeOutput(&buf, outputTable, res); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"QUEUE                               LENGTH  PAUSED",
+		"dead_letter (jobqueue:dead_letter)  12",
+		"high (jobqueue:high_priority)       3       yes",
+		"",
+		"PROCESSING LIST                ITEMS",
+		"jobqueue:worker:w1:processing  1",
+		"heartbeats: 1",
+		"",
+	}, "\n")
+	if got := buf.String(); got != want {
+		t.Fatalf("unexpected table:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteOutputWorkersTable(t *testing.T) {
+	seen := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	res := []admin.WorkerInfo{
+		{ID: "host-1-5-ab-0", Host: "host", PID: 1, LastHeartbeat: seen, JobID: "j1", Queue: "jobqueue:high_priority", Processing: 1, Alive: true},
+		{ID: "host-2-5-cd-0", Host: "host", PID: 2, Processing: 3, ReapCandidate: true},
+	}
+	var buf bytes.Buffer
+	if err := writeOutput(&buf, outputTable, res); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"WORKER         HOST  PID  LAST SEEN             JOB  QUEUE                   PROCESSING  STATUS",
+		"host-1-5-ab-0  host  1    2025-09-01T12:00:00Z  j1   jobqueue:high_priority  1           alive",
+		"host-2-5-cd-0  host  2    -                                                  3           REAP",
+		"workers: 2  alive: 1  reap candidates: 1",
+		"",
+	}, "\n")
+	if got := buf.String(); got != want {
+		t.Fatalf("unexpected table:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteOutputGenericTable(t *testing.T) {
+	var buf bytes.Buffer
+	v := struct {
+		From  string `json:"from"`
+		Moved int    `json:"moved"`
+	}{From: "high", Moved: 4}
+	if err := writeOutput(&buf, outputTable, v); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "KEY    VALUE\nfrom   high\nmoved  4\n" {
+		t.Fatalf("unexpected key/value table: %q", got)
+	}
+}
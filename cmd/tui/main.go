@@ -7,6 +7,7 @@ import (
 	"io"
 	"net"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -24,6 +25,7 @@ var version = "dev"
 
 func main() {
 	var configPath string
+	var profile string
 	var refresh time.Duration
 	var redisURL string
 	var cluster string
@@ -34,30 +36,48 @@ func main() {
 	var theme string
 	var fps int
 	var noMouse bool
+	var stateFile string
 
 	fs := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
 	fs.SetOutput(io.Discard)
 	fs.StringVar(&configPath, "config", "config/config.yaml", "Path to YAML config")
+	fs.StringVar(&profile, "profile", os.Getenv(config.ProfileEnv), "Config profile overlaid on --config (default $"+config.ProfileEnv+")")
 	fs.DurationVar(&refresh, "refresh", 2*time.Second, "Refresh interval for stats")
 	fs.StringVar(&redisURL, "redis-url", "", "Quick connect Redis URL (redis://[:pass@]host:port/db)")
-	fs.StringVar(&cluster, "cluster", "", "Named cluster from config")
-	fs.StringVar(&namespace, "namespace", "", "Key namespace/prefix")
+	fs.StringVar(&cluster, "cluster", "", "Named cluster from config (connects to it when listed under clusters)")
+	fs.StringVar(&namespace, "namespace", "", "Key namespace: only see the queues of the system using it (overrides an empty config namespace)")
 	fs.BoolVar(&readOnly, "read-only", false, "Force read-only mode (guardrails on)")
 	fs.StringVar(&metricsAddr, "metrics-addr", ":9090", "Prometheus metrics address")
 	fs.StringVar(&logLevel, "log-level", "info", "Log level: debug,info,warn,error")
 	fs.StringVar(&theme, "theme", "auto", "Theme: auto,dark,light,high-contrast")
 	fs.IntVar(&fps, "fps", 60, "FPS cap for rendering")
 	fs.BoolVar(&noMouse, "no-mouse", false, "Disable mouse handling")
+	fs.StringVar(&stateFile, "state-file", filepath.Join(defaultThemeDir(), "tui-state.json"), "File remembering layout, theme and queue filter between runs; empty to disable")
 	if err := fs.Parse(os.Args[1:]); err != nil {
 		fmt.Fprintf(os.Stderr, "failed to parse flags: %v\n", err)
 		os.Exit(2)
 	}
+	if fs.Arg(0) == "theme" {
+		os.Exit(runTheme(fs.Args()[1:], os.Stdout, os.Stderr))
+	}
 
-	cfg, err := config.Load(configPath)
+	cfg, err := config.LoadProfile(configPath, profile)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
 		os.Exit(1)
 	}
+	if namespace != "" && namespace != cfg.Namespace {
+		if cfg.Namespace != "" {
+			fmt.Fprintf(os.Stderr, "--namespace %q conflicts with namespace %q in the config\n", namespace, cfg.Namespace)
+			os.Exit(2)
+		}
+		cfg.Namespace = namespace
+		if err := config.Validate(cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid --namespace: %v\n", err)
+			os.Exit(2)
+		}
+		cfg.ApplyNamespace()
+	}
 
 	if redisURL != "" {
 		opts, err := redis.ParseURL(redisURL)
@@ -104,7 +124,13 @@ func main() {
 	}
 	defer logger.Sync()
 
-	rdb := redisclient.New(cfg)
+	// connect to --cluster when the config's clusters section lists it;
+	// other names only label the header
+	clusterName := ""
+	if _, ok := cfg.Clusters[cluster]; ok {
+		clusterName = cluster
+	}
+	rdb, _ := redisclient.NewForCluster(cfg, clusterName)
 	defer rdb.Close()
 	if _, err := rdb.Ping(context.Background()).Result(); err != nil {
 		fmt.Fprintf(os.Stderr, "redis ping failed: %v\n", err)
@@ -114,11 +140,12 @@ func main() {
 	tuiOpts := itui.Options{
 		RedisURL:    redisURL,
 		Cluster:     cluster,
-		Namespace:   namespace,
+		Namespace:   cfg.Namespace,
 		ReadOnly:    readOnly,
 		MetricsAddr: metricsAddr,
 		Theme:       theme,
 		FPS:         fps,
+		StatePath:   stateFile,
 	}
 
 	m := itui.New(cfg, rdb, logger, refresh, tuiOpts)
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	themeplayground "github.com/flyingrobots/go-redis-work-queue/internal/theme-playground"
+)
+
+// runTheme handles `tui theme preview [--width N] [--theme-dir DIR] [name ...]`,
+// which prints a sample dashboard in each named theme, or in every theme
+// when none is named. It returns the process exit code.
+func runTheme(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] != "preview" {
+		fmt.Fprintln(stderr, "usage: tui theme preview [--width N] [--theme-dir DIR] [name ...]")
+		return 2
+	}
+	var width int
+	var themeDir string
+	fs := flag.NewFlagSet("theme preview", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.IntVar(&width, "width", 80, "Columns to render the preview in")
+	fs.StringVar(&themeDir, "theme-dir", defaultThemeDir(), "Directory holding theme preferences and custom themes")
+	if err := fs.Parse(args[1:]); err != nil {
+		fmt.Fprintf(stderr, "failed to parse flags: %v\n", err)
+		return 2
+	}
+
+	tm := themeplayground.NewThemeManager(themeDir)
+	names := fs.Args()
+	if len(names) == 0 {
+		for _, t := range tm.ListThemes() {
+			names = append(names, t.Name)
+		}
+	}
+	for i, name := range names {
+		out, err := tm.RenderPreview(name, width)
+		if err != nil {
+			fmt.Fprintf(stderr, "theme %s: %v\n", name, err)
+			return 1
+		}
+		if i > 0 {
+			fmt.Fprintln(stdout)
+		}
+		fmt.Fprintln(stdout, out)
+	}
+	return 0
+}
+
+func defaultThemeDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return filepath.Join(".", "themes")
+	}
+	return filepath.Join(dir, "go-redis-work-queue")
+}
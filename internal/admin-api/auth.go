@@ -0,0 +1,421 @@
+// Copyright 2025 James Ross
+package adminapi
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Auth providers selectable with Config.AuthProvider
+const (
+	// AuthProviderBearer checks HS256 bearer tokens signed with JWTSecret
+	AuthProviderBearer = "bearer"
+	// AuthProviderJWT checks RS256/ES256 bearer tokens against the keys
+	// published at JWKSURL, as issued by an SSO identity provider
+	AuthProviderJWT = "jwt"
+	// AuthProviderMTLS trusts the verified TLS client certificate
+	AuthProviderMTLS = "mtls"
+)
+
+// jwksMinRefetch is the least time between JWKS fetches, so tokens signed
+// with unknown key IDs or an unreachable JWKS cannot make every request
+// fetch it.
+const jwksMinRefetch = time.Minute
+
+// Authenticator checks the credentials of a request and returns the claims
+// of its caller. Errors are *AuthError when the client should see a
+// specific code and message.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Claims, error)
+}
+
+// AuthError is an authentication failure as reported to the client. Err,
+// if set, is only logged.
+type AuthError struct {
+	Code    string
+	Message string
+	Err     error
+}
+
+func (e *AuthError) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *AuthError) Unwrap() error { return e.Err }
+
+func invalidToken(err error) *AuthError {
+	return &AuthError{Code: "AUTH_INVALID", Message: "Invalid or expired token", Err: err}
+}
+
+// NewAuthenticator returns the authenticator cfg.AuthProvider selects; an
+// empty provider is AuthProviderBearer.
+func NewAuthenticator(cfg *Config) (Authenticator, error) {
+	switch cfg.AuthProvider {
+	case "", AuthProviderBearer:
+		return &bearerAuthenticator{secret: cfg.JWTSecret, issuer: cfg.JWTIssuer, audience: cfg.JWTAudience}, nil
+	case AuthProviderJWT:
+		if cfg.JWKSURL == "" {
+			return nil, fmt.Errorf("auth_provider %q requires jwks_url", AuthProviderJWT)
+		}
+		refresh := cfg.JWKSRefreshInterval
+		if refresh <= 0 {
+			refresh = 10 * time.Minute
+		}
+		return &jwksAuthenticator{
+			url:      cfg.JWKSURL,
+			issuer:   cfg.JWTIssuer,
+			audience: cfg.JWTAudience,
+			refresh:  refresh,
+			client:   &http.Client{Timeout: 10 * time.Second},
+		}, nil
+	case AuthProviderMTLS:
+		if !cfg.TLSEnabled || cfg.TLSClientCAFile == "" {
+			return nil, fmt.Errorf("auth_provider %q requires tls_enabled and tls_client_ca_file", AuthProviderMTLS)
+		}
+		return &mtlsAuthenticator{scopes: cfg.MTLSSubjectScopes}, nil
+	default:
+		return nil, fmt.Errorf("unknown auth_provider %q (want %s, %s or %s)", cfg.AuthProvider, AuthProviderBearer, AuthProviderJWT, AuthProviderMTLS)
+	}
+}
+
+// bearerToken returns the bearer token of r: the Authorization header or,
+// where allowsQueryToken permits, the access_token query parameter.
+func bearerToken(r *http.Request) (string, error) {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" && allowsQueryToken(r) {
+		if token := r.URL.Query().Get("access_token"); token != "" {
+			authHeader = "Bearer " + token
+		}
+	}
+	if authHeader == "" {
+		return "", &AuthError{Code: "AUTH_MISSING", Message: "Authorization header required"}
+	}
+
+	parts := strings.SplitN(authHeader, " ", 2)
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return "", &AuthError{Code: "AUTH_INVALID", Message: "Invalid authorization format"}
+	}
+	return parts[1], nil
+}
+
+// checkClaims enforces the issuer and audience when they are configured
+func checkClaims(claims *Claims, issuer, audience string) error {
+	if issuer != "" && claims.Issuer != issuer {
+		return fmt.Errorf("unexpected issuer %q", claims.Issuer)
+	}
+	if audience != "" && !claims.Audience.contains(audience) {
+		return fmt.Errorf("token not issued for audience %q", audience)
+	}
+	return nil
+}
+
+// bearerAuthenticator implements AuthProviderBearer
+type bearerAuthenticator struct {
+	secret   string
+	issuer   string
+	audience string
+}
+
+func (a *bearerAuthenticator) Authenticate(r *http.Request) (*Claims, error) {
+	token, err := bearerToken(r)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := validateJWT(token, a.secret)
+	if err != nil {
+		return nil, invalidToken(err)
+	}
+	if err := checkClaims(claims, a.issuer, a.audience); err != nil {
+		return nil, invalidToken(err)
+	}
+	return claims, nil
+}
+
+// mtlsAuthenticator implements AuthProviderMTLS. The server asks for client
+// certificates without requiring them in the handshake, so CORS preflights,
+// which browsers send without one, still get an answer; requests that reach
+// auth without a verified certificate are refused here.
+type mtlsAuthenticator struct {
+	scopes map[string][]string
+}
+
+func (a *mtlsAuthenticator) Authenticate(r *http.Request) (*Claims, error) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, &AuthError{Code: "AUTH_MISSING", Message: "Client certificate required"}
+	}
+	cert := r.TLS.VerifiedChains[0][0]
+	subject := cert.Subject.CommonName
+	scopes, ok := a.scopes[subject]
+	if !ok {
+		scopes = a.scopes["*"]
+	}
+	return &Claims{
+		Subject:   subject,
+		Scopes:    append([]string(nil), scopes...),
+		IssuedAt:  cert.NotBefore.Unix(),
+		ExpiresAt: cert.NotAfter.Unix(),
+	}, nil
+}
+
+// clientTLSConfig returns the server TLS settings AuthProviderMTLS needs:
+// client certificates verified against caFile when presented.
+func clientTLSConfig(caFile string) (*tls.Config, error) {
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in client CA file %s", caFile)
+	}
+	return &tls.Config{
+		ClientCAs:  pool,
+		ClientAuth: tls.VerifyClientCertIfGiven,
+		MinVersion: tls.VersionTLS12,
+	}, nil
+}
+
+// jwksAuthenticator implements AuthProviderJWT. Keys are cached for the
+// refresh interval; a token signed with an unknown key ID triggers an
+// early refetch to pick up rotations. A stale key is still used while the
+// JWKS cannot be fetched.
+type jwksAuthenticator struct {
+	url      string
+	issuer   string
+	audience string
+	refresh  time.Duration
+	client   *http.Client
+
+	mu          sync.Mutex
+	keys        map[string]crypto.PublicKey
+	fetchedAt   time.Time
+	attemptedAt time.Time
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+func (a *jwksAuthenticator) Authenticate(r *http.Request) (*Claims, error) {
+	token, err := bearerToken(r)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, invalidToken(fmt.Errorf("invalid token format"))
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, invalidToken(err)
+	}
+	key, err := a.key(r.Context(), header.Kid)
+	if err != nil {
+		return nil, invalidToken(err)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, invalidToken(err)
+	}
+	if err := verifySignature(header.Alg, key, parts[0]+"."+parts[1], sig); err != nil {
+		return nil, invalidToken(err)
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, invalidToken(err)
+	}
+	if time.Now().Unix() > claims.ExpiresAt {
+		return nil, invalidToken(fmt.Errorf("token expired"))
+	}
+	if err := checkClaims(&claims, a.issuer, a.audience); err != nil {
+		return nil, invalidToken(err)
+	}
+	return &claims, nil
+}
+
+// key returns the verification key with ID kid, fetching the JWKS when the
+// cache is stale or does not know kid.
+func (a *jwksAuthenticator) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	key, ok := a.keys[kid]
+	if ok && time.Since(a.fetchedAt) < a.refresh {
+		return key, nil
+	}
+	if time.Since(a.attemptedAt) < jwksMinRefetch {
+		if ok {
+			return key, nil
+		}
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	a.attemptedAt = time.Now()
+	keys, err := a.fetch(ctx)
+	if err != nil {
+		if ok {
+			// Keep serving the cached key while the JWKS is unreachable.
+			return key, nil
+		}
+		return nil, err
+	}
+	a.keys, a.fetchedAt = keys, time.Now()
+	if key, ok := keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// fetch downloads the JWKS and parses its RSA and EC signing keys; others
+// are skipped.
+func (a *jwksAuthenticator) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch JWKS: status %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		if key, err := jwk.publicKey(); err == nil {
+			keys[jwk.Kid] = key
+		}
+	}
+	return keys, nil
+}
+
+func (k *jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// verifySignature checks a JWS signature over signed with key
+func verifySignature(alg string, key crypto.PublicKey, signed string, sig []byte) error {
+	var hash crypto.Hash
+	switch alg {
+	case "RS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512":
+		hash = crypto.SHA512
+	default:
+		return fmt.Errorf("unsupported algorithm %q", alg)
+	}
+	h := hash.New()
+	h.Write([]byte(signed))
+	digest := h.Sum(nil)
+
+	switch pub := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(alg, "RS") {
+			return fmt.Errorf("algorithm %s does not match RSA key", alg)
+		}
+		return rsa.VerifyPKCS1v15(pub, hash, digest, sig)
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if !strings.HasPrefix(alg, "ES") || len(sig) != 2*size {
+			return fmt.Errorf("invalid %s signature", alg)
+		}
+		r, s := new(big.Int).SetBytes(sig[:size]), new(big.Int).SetBytes(sig[size:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return fmt.Errorf("invalid signature")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported key type %T", key)
+	}
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,189 @@
+// Copyright 2025 James Ross
+package adminapi
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": kid})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("failed to marshal claims: %v", err)
+	}
+	message := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(message))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return message + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestJWKSAuthenticator(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fetches atomic.Int32
+	jwks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kid": "k1", "kty": "RSA", "use": "sig",
+			"n": base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	}))
+	defer jwks.Close()
+
+	cfg := DefaultConfig()
+	cfg.AuthProvider = AuthProviderJWT
+	cfg.JWKSURL = jwks.URL
+	cfg.JWTIssuer = "https://sso.example.com"
+	cfg.JWTAudience = "admin-api"
+	auth, err := NewAuthenticator(cfg)
+	if err != nil {
+		t.Fatalf("NewAuthenticator: %v", err)
+	}
+	handler := AuthenticatorMiddleware(auth, true, zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims := r.Context().Value(contextKeyClaims).(*Claims)
+		w.Write([]byte(claims.Subject))
+	}))
+
+	claims := func(aud interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"sub": "ops@example.com", "iss": "https://sso.example.com", "aud": aud,
+			"scopes": []string{"admin:all"}, "exp": time.Now().Add(time.Hour).Unix(),
+		}
+	}
+	tests := []struct {
+		name   string
+		token  string
+		status int
+	}{
+		{"valid", signRS256(t, key, "k1", claims("admin-api")), http.StatusOK},
+		{"audience list", signRS256(t, key, "k1", claims([]string{"other", "admin-api"})), http.StatusOK},
+		{"wrong audience", signRS256(t, key, "k1", claims("other")), http.StatusUnauthorized},
+		{"unknown key", signRS256(t, key, "k2", claims("admin-api")), http.StatusUnauthorized},
+		{"HS256 token", mustMakeScopedToken(t, "secret", nil), http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v1/stats", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if tt.status == http.StatusOK && w.Body.String() != "ops@example.com" {
+				t.Errorf("unexpected subject %q", w.Body.String())
+			}
+		})
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("expected the JWKS to be fetched once, got %d", n)
+	}
+}
+
+func TestMTLSAuthenticator(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.AuthProvider = AuthProviderMTLS
+	if _, err := NewAuthenticator(cfg); err == nil {
+		t.Fatal("expected mtls without a client CA to be rejected")
+	}
+	cfg.TLSEnabled = true
+	cfg.TLSClientCAFile = "ca.pem"
+	cfg.MTLSSubjectScopes = map[string][]string{"deployer": {"admin:all"}, "*": {"stats:read"}}
+	auth, err := NewAuthenticator(cfg)
+	if err != nil {
+		t.Fatalf("NewAuthenticator: %v", err)
+	}
+
+	withCert := func(cn string) *http.Request {
+		req := httptest.NewRequest("GET", "/api/v1/stats", nil)
+		cert := &x509.Certificate{Subject: pkix.Name{CommonName: cn}, NotAfter: time.Now().Add(time.Hour)}
+		req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+		return req
+	}
+	claims, err := auth.Authenticate(withCert("deployer"))
+	if err != nil || claims.Subject != "deployer" || len(claims.Scopes) != 1 || claims.Scopes[0] != "admin:all" {
+		t.Fatalf("unexpected claims %+v, err %v", claims, err)
+	}
+	claims, err = auth.Authenticate(withCert("dashboard"))
+	if err != nil || claims.Scopes[0] != "stats:read" {
+		t.Fatalf("expected the wildcard scopes, got %+v, err %v", claims, err)
+	}
+
+	_, err = auth.Authenticate(httptest.NewRequest("GET", "/api/v1/stats", nil))
+	if authErr, ok := err.(*AuthError); !ok || authErr.Code != "AUTH_MISSING" {
+		t.Fatalf("expected AUTH_MISSING without a certificate, got %v", err)
+	}
+}
+
+func TestServerAnswersPreflightBeforeAuth(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.JWTSecret = "secret"
+	cfg.AuditEnabled = false
+	cfg.RateLimitEnabled = false
+	cfg.CORSEnabled = true
+	cfg.CORSAllowOrigins = []string{"https://ui.example.com"}
+	cfg.CORSAllowHeaders = []string{"Authorization", "X-Custom"}
+	cfg.CORSAllowCredentials = true
+	server, err := NewServer(cfg, nil, nil, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	handler := server.applyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest("OPTIONS", "/api/v1/queues/dlq", nil)
+	req.Header.Set("Origin", "https://ui.example.com")
+	req.Header.Set("Access-Control-Request-Method", "DELETE")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected preflight to get 204 without a token, got %d", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Headers"); got != "Authorization, X-Custom" {
+		t.Errorf("unexpected allowed headers %q", got)
+	}
+	if w.Header().Get("Access-Control-Allow-Credentials") != "true" {
+		t.Error("expected credentials to be allowed")
+	}
+
+	// The actual request still needs a token, and keeps its CORS headers
+	req = httptest.NewRequest("GET", "/api/v1/stats", nil)
+	req.Header.Set("Origin", "https://ui.example.com")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without a token, got %d", w.Code)
+	}
+	if w.Header().Get("Access-Control-Allow-Origin") != "https://ui.example.com" {
+		t.Error("expected CORS headers on the 401 so the browser can read it")
+	}
+
+	cfg.AuthProvider = "basic"
+	if _, err := NewServer(cfg, nil, nil, zap.NewNop()); err == nil {
+		t.Error("expected an unknown auth provider to be rejected")
+	}
+}
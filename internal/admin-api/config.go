@@ -19,24 +19,93 @@ type Config struct {
 	RequireAuth   bool   `mapstructure:"require_auth"`
 	DenyByDefault bool   `mapstructure:"deny_by_default"`
 
+	// AuthProvider selects how callers authenticate: "bearer" (HS256
+	// tokens signed with JWTSecret), "jwt" (RS256/ES256 tokens verified
+	// against the keys at JWKSURL) or "mtls" (verified TLS client
+	// certificates; needs TLSEnabled and TLSClientCAFile). JWTIssuer and
+	// JWTAudience, when set, are required of bearer and jwt tokens.
+	// MTLSSubjectScopes grants scopes by certificate common name, "*"
+	// matching any other client.
+	AuthProvider        string              `mapstructure:"auth_provider"`
+	JWTAudience         string              `mapstructure:"jwt_audience"`
+	JWKSURL             string              `mapstructure:"jwks_url"`
+	JWKSRefreshInterval time.Duration       `mapstructure:"jwks_refresh_interval"`
+	TLSClientCAFile     string              `mapstructure:"tls_client_ca_file"`
+	MTLSSubjectScopes   map[string][]string `mapstructure:"mtls_subject_scopes"`
+
 	// Rate limiting
 	RateLimitEnabled   bool          `mapstructure:"rate_limit_enabled"`
 	RateLimitPerMinute int           `mapstructure:"rate_limit_per_minute"`
 	RateLimitBurst     int           `mapstructure:"rate_limit_burst"`
 	RateLimitWindow    time.Duration `mapstructure:"rate_limit_window"`
 
+	// Per-token quotas, shared across replicas through Redis. Read and
+	// destructive (purge, requeue, bench) endpoints are counted separately;
+	// TokenQuotas overrides either limit by token subject.
+	ReadQuota        Quota                 `mapstructure:"read_quota"`
+	DestructiveQuota Quota                 `mapstructure:"destructive_quota"`
+	TokenQuotas      map[string]TokenQuota `mapstructure:"token_quotas"`
+	QuotaKeyPrefix   string                `mapstructure:"quota_key_prefix"`
+	// DestructiveScopes lists token scopes, any one of which allows purge
+	// and requeue calls. Empty disables the check.
+	DestructiveScopes []string `mapstructure:"destructive_scopes"`
+
+	// Idempotency-Key support for destructive calls. Responses are kept for
+	// IdempotencyTTL (0 disables); a key stays locked for at most
+	// IdempotencyLockTTL while its first request runs.
+	IdempotencyTTL       time.Duration `mapstructure:"idempotency_ttl"`
+	IdempotencyLockTTL   time.Duration `mapstructure:"idempotency_lock_ttl"`
+	IdempotencyKeyPrefix string        `mapstructure:"idempotency_key_prefix"`
+
+	// Bulk job operations (POST /api/v1/jobs/bulk): at most BulkMaxItems
+	// per call, moved BulkBatchSize at a time with BulkConcurrency batches
+	// in flight. Archived jobs are pushed to BulkArchiveList, inside the
+	// queue config's namespace.
+	BulkMaxItems    int    `mapstructure:"bulk_max_items"`
+	BulkBatchSize   int    `mapstructure:"bulk_batch_size"`
+	BulkConcurrency int    `mapstructure:"bulk_concurrency"`
+	BulkArchiveList string `mapstructure:"bulk_archive_list"`
+
 	// Audit logging
 	AuditEnabled    bool   `mapstructure:"audit_enabled"`
 	AuditLogPath    string `mapstructure:"audit_log_path"`
 	AuditRotateSize int64  `mapstructure:"audit_rotate_size"`
 	AuditMaxBackups int    `mapstructure:"audit_max_backups"`
 
-	// Security
-	CORSEnabled      bool     `mapstructure:"cors_enabled"`
-	CORSAllowOrigins []string `mapstructure:"cors_allow_origins"`
-	TLSEnabled       bool     `mapstructure:"tls_enabled"`
-	TLSCertFile      string   `mapstructure:"tls_cert_file"`
-	TLSKeyFile       string   `mapstructure:"tls_key_file"`
+	// Security. CORS preflight requests are answered before auth.
+	CORSEnabled          bool          `mapstructure:"cors_enabled"`
+	CORSAllowOrigins     []string      `mapstructure:"cors_allow_origins"`
+	CORSAllowMethods     []string      `mapstructure:"cors_allow_methods"`
+	CORSAllowHeaders     []string      `mapstructure:"cors_allow_headers"`
+	CORSAllowCredentials bool          `mapstructure:"cors_allow_credentials"`
+	CORSMaxAge           time.Duration `mapstructure:"cors_max_age"`
+	TLSEnabled           bool          `mapstructure:"tls_enabled"`
+	TLSCertFile          string        `mapstructure:"tls_cert_file"`
+	TLSKeyFile           string        `mapstructure:"tls_key_file"`
+
+	// Stats stream (WebSocket)
+	StreamInterval       time.Duration `mapstructure:"stream_interval"`
+	StreamDeltaInterval  time.Duration `mapstructure:"stream_delta_interval"`
+	StreamDeltaThreshold int64         `mapstructure:"stream_delta_threshold"`
+	StreamMaxConnections int           `mapstructure:"stream_max_connections"`
+
+	// Prometheus metrics at /metrics: request counts and latencies, queue
+	// lengths and worker liveness. MetricsPublic serves them without auth,
+	// for scrapers that cannot send a token.
+	MetricsEnabled bool `mapstructure:"metrics_enabled"`
+	MetricsPublic  bool `mapstructure:"metrics_public"`
+
+	// DLQ alerts (Server-Sent Events at /api/v1/events/dlq): every
+	// DLQAlertInterval the DLQ length is checked against
+	// DLQAlertThresholds, and growth of more than DLQAlertGrowth within
+	// DLQAlertWindow raises an alert (0 disables). The last
+	// DLQAlertHistory events are kept for reconnecting clients.
+	// StreamMaxConnections also caps the subscribers.
+	DLQAlertInterval   time.Duration `mapstructure:"dlq_alert_interval"`
+	DLQAlertThresholds []int64       `mapstructure:"dlq_alert_thresholds"`
+	DLQAlertGrowth     int64         `mapstructure:"dlq_alert_growth"`
+	DLQAlertWindow     time.Duration `mapstructure:"dlq_alert_window"`
+	DLQAlertHistory    int           `mapstructure:"dlq_alert_history"`
 
 	// Destructive operation confirmations
 	RequireDoubleConfirm       bool   `mapstructure:"require_double_confirm"`
@@ -45,6 +114,19 @@ type Config struct {
 	PurgeAllConfirmationPhrase string `mapstructure:"purge_all_confirmation_phrase"`
 }
 
+// Quota allows Requests calls per Window. Zero Requests means unlimited.
+type Quota struct {
+	Requests int           `mapstructure:"requests"`
+	Window   time.Duration `mapstructure:"window"`
+}
+
+// TokenQuota overrides the default quotas for one token. Zero fields keep
+// the default.
+type TokenQuota struct {
+	Read        Quota `mapstructure:"read"`
+	Destructive Quota `mapstructure:"destructive"`
+}
+
 func DefaultConfig() *Config {
 	return &Config{
 		ListenAddr:      ":8080",
@@ -55,11 +137,28 @@ func DefaultConfig() *Config {
 		RequireAuth:   true,
 		DenyByDefault: true,
 
+		AuthProvider:        AuthProviderBearer,
+		JWKSRefreshInterval: 10 * time.Minute,
+
 		RateLimitEnabled:   true,
 		RateLimitPerMinute: 100,
 		RateLimitBurst:     10,
 		RateLimitWindow:    time.Minute,
 
+		ReadQuota:         Quota{Requests: 100, Window: time.Minute},
+		DestructiveQuota:  Quota{Requests: 5, Window: time.Minute},
+		QuotaKeyPrefix:    "admin-api:quota",
+		DestructiveScopes: []string{"queue:delete", "admin:all"},
+
+		IdempotencyTTL:       24 * time.Hour,
+		IdempotencyLockTTL:   time.Minute,
+		IdempotencyKeyPrefix: "admin-api:idempotency",
+
+		BulkMaxItems:    10000,
+		BulkBatchSize:   100,
+		BulkConcurrency: 4,
+		BulkArchiveList: "jobqueue:archive",
+
 		AuditEnabled:    true,
 		AuditLogPath:    "/var/log/admin-api/audit.log",
 		AuditRotateSize: 100 * 1024 * 1024, // 100MB
@@ -67,6 +166,22 @@ func DefaultConfig() *Config {
 
 		CORSEnabled:      false,
 		CORSAllowOrigins: []string{"*"},
+		CORSAllowMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		CORSAllowHeaders: []string{"Authorization", "Content-Type", "Idempotency-Key", "X-Request-ID"},
+		CORSMaxAge:       time.Hour,
+
+		StreamInterval:       5 * time.Second,
+		StreamDeltaInterval:  time.Second,
+		StreamDeltaThreshold: 10,
+		StreamMaxConnections: 100,
+
+		MetricsEnabled: true,
+
+		DLQAlertInterval:   5 * time.Second,
+		DLQAlertThresholds: []int64{100, 1000, 10000},
+		DLQAlertGrowth:     100,
+		DLQAlertWindow:     5 * time.Minute,
+		DLQAlertHistory:    100,
 
 		RequireDoubleConfirm:       true,
 		ConfirmationPhrase:         "CONFIRM_DELETE",
@@ -75,6 +190,17 @@ func DefaultConfig() *Config {
 	}
 }
 
+// CORSOptions returns the CORS settings for CORSMiddlewareWithOptions
+func (c *Config) CORSOptions() CORSOptions {
+	return CORSOptions{
+		AllowOrigins:     c.CORSAllowOrigins,
+		AllowMethods:     c.CORSAllowMethods,
+		AllowHeaders:     c.CORSAllowHeaders,
+		AllowCredentials: c.CORSAllowCredentials,
+		MaxAge:           c.CORSMaxAge,
+	}
+}
+
 // DLQPhrase returns the configured DLQ confirmation phrase, falling back to the legacy confirmation phrase if unset.
 func (c *Config) DLQPhrase() string {
 	if c == nil {
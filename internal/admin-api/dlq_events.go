@@ -0,0 +1,338 @@
+// Copyright 2025 James Ross
+package adminapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// dlqEventsLinger keeps the evaluator running after the last subscriber
+	// leaves, so a client reconnecting with Last-Event-ID misses nothing.
+	dlqEventsLinger    = 30 * time.Second
+	dlqEventsRetry     = 3 * time.Second
+	dlqEventsKeepAlive = 15 * time.Second
+	dlqEventsBuffer    = 16
+)
+
+// DLQ event types. Each is also the SSE event name.
+const (
+	// DLQEventThresholdCrossed: the DLQ length rose to or above Threshold.
+	DLQEventThresholdCrossed = "threshold_crossed"
+	// DLQEventThresholdCleared: the DLQ length fell back below Threshold.
+	DLQEventThresholdCleared = "threshold_cleared"
+	// DLQEventGrowth: the DLQ grew by more than dlq_alert_growth within
+	// Window. Raised at most once per window.
+	DLQEventGrowth = "growth"
+	// DLQEventStatus reports the current length on connect. It has no ID
+	// and is not replayed.
+	DLQEventStatus = "status"
+)
+
+// DLQEvent is the data of one /api/v1/events/dlq event.
+type DLQEvent struct {
+	ID        uint64    `json:"id,omitempty"`
+	Type      string    `json:"type"`
+	Length    int64     `json:"length"`
+	Threshold int64     `json:"threshold,omitempty"`
+	Growth    int64     `json:"growth,omitempty"`
+	Window    string    `json:"window,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type dlqSample struct {
+	at     time.Time
+	length int64
+}
+
+// dlqAlertHub samples the DLQ length for all event subscribers and keeps
+// the last dlq_alert_history events for Last-Event-ID replay. Event IDs
+// keep increasing across evaluator restarts.
+type dlqAlertHub struct {
+	cfg    *config.Config
+	apiCfg *Config
+	rdb    *redis.Client
+	logger *zap.Logger
+	linger time.Duration
+
+	mu      sync.Mutex
+	subs    map[chan DLQEvent]struct{}
+	history []DLQEvent
+	nextID  uint64
+	status  *DLQEvent
+	cancel  context.CancelFunc
+	stop    *time.Timer
+}
+
+func newDLQAlertHub(cfg *config.Config, apiCfg *Config, rdb *redis.Client, logger *zap.Logger) *dlqAlertHub {
+	return &dlqAlertHub{
+		cfg:    cfg,
+		apiCfg: apiCfg,
+		rdb:    rdb,
+		logger: logger,
+		linger: dlqEventsLinger,
+		subs:   make(map[chan DLQEvent]struct{}),
+	}
+}
+
+// subscribe registers a subscriber and returns, when hasLast is set, the
+// retained events after lastID, plus the latest status. It returns false
+// when the connection limit has been reached.
+func (hub *dlqAlertHub) subscribe(lastID uint64, hasLast bool) (chan DLQEvent, []DLQEvent, *DLQEvent, bool) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	if max := hub.apiCfg.StreamMaxConnections; max > 0 && len(hub.subs) >= max {
+		return nil, nil, nil, false
+	}
+	var replay []DLQEvent
+	if hasLast {
+		for _, ev := range hub.history {
+			if ev.ID > lastID {
+				replay = append(replay, ev)
+			}
+		}
+	}
+	ch := make(chan DLQEvent, dlqEventsBuffer)
+	hub.subs[ch] = struct{}{}
+	if hub.stop != nil {
+		hub.stop.Stop()
+		hub.stop = nil
+	}
+	if hub.cancel == nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		hub.cancel = cancel
+		go hub.run(ctx)
+	}
+	return ch, replay, hub.status, true
+}
+
+// unsubscribe removes ch; once no subscriber is left the evaluator stops
+// after hub.linger.
+func (hub *dlqAlertHub) unsubscribe(ch chan DLQEvent) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	delete(hub.subs, ch)
+	if len(hub.subs) > 0 || hub.cancel == nil || hub.stop != nil {
+		return
+	}
+	hub.stop = time.AfterFunc(hub.linger, func() {
+		hub.mu.Lock()
+		defer hub.mu.Unlock()
+		if len(hub.subs) == 0 && hub.cancel != nil {
+			hub.cancel()
+			hub.cancel = nil
+			hub.status = nil
+		}
+		hub.stop = nil
+	})
+}
+
+// emit numbers ev, retains it and delivers it. A subscriber whose buffer is
+// full is dropped, closing its channel, so its client reconnects and
+// replays what it missed. Status events are delivered but not numbered or
+// retained. Nothing is emitted once ctx has been canceled.
+func (hub *dlqAlertHub) emit(ctx context.Context, ev DLQEvent) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	if ctx.Err() != nil {
+		return
+	}
+	if ev.Type == DLQEventStatus {
+		hub.status = &ev
+	} else {
+		hub.nextID++
+		ev.ID = hub.nextID
+		hub.history = append(hub.history, ev)
+		if keep := max(hub.apiCfg.DLQAlertHistory, 1); len(hub.history) > keep {
+			hub.history = slices.Clone(hub.history[len(hub.history)-keep:])
+		}
+	}
+	for ch := range hub.subs {
+		select {
+		case ch <- ev:
+		default:
+			delete(hub.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+func (hub *dlqAlertHub) run(ctx context.Context) {
+	interval := hub.apiCfg.DLQAlertInterval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	window := hub.apiCfg.DLQAlertWindow
+	thresholds := dlqThresholds(hub.apiCfg.DLQAlertThresholds)
+
+	level := -1 // thresholds at or below the last length; -1 before the first sample
+	var samples []dlqSample
+	var lastGrowth time.Time
+	evaluate := func() {
+		pollCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+
+		n, err := hub.rdb.LLen(pollCtx, hub.cfg.Worker.DeadLetterList).Result()
+		if err != nil {
+			if ctx.Err() == nil {
+				hub.logger.Warn("DLQ alert poll failed", zap.Error(err))
+			}
+			return
+		}
+		now := time.Now()
+		newLevel := 0
+		for newLevel < len(thresholds) && n >= thresholds[newLevel] {
+			newLevel++
+		}
+		if level < 0 {
+			hub.emit(ctx, DLQEvent{Type: DLQEventStatus, Length: n, Timestamp: now})
+			level = newLevel
+		}
+		for ; level < newLevel; level++ {
+			hub.emit(ctx, DLQEvent{Type: DLQEventThresholdCrossed, Length: n, Threshold: thresholds[level], Timestamp: now})
+		}
+		for ; level > newLevel; level-- {
+			hub.emit(ctx, DLQEvent{Type: DLQEventThresholdCleared, Length: n, Threshold: thresholds[level-1], Timestamp: now})
+		}
+		hub.mu.Lock()
+		hub.status = &DLQEvent{Type: DLQEventStatus, Length: n, Timestamp: now}
+		hub.mu.Unlock()
+
+		growth := hub.apiCfg.DLQAlertGrowth
+		if growth <= 0 || window <= 0 {
+			return
+		}
+		samples = append(samples, dlqSample{at: now, length: n})
+		for len(samples) > 1 && now.Sub(samples[0].at) > window {
+			samples = samples[1:]
+		}
+		low := n
+		for _, s := range samples {
+			if s.length < low {
+				low = s.length
+			}
+		}
+		if n-low > growth && (lastGrowth.IsZero() || now.Sub(lastGrowth) >= window) {
+			hub.emit(ctx, DLQEvent{Type: DLQEventGrowth, Length: n, Growth: n - low, Window: window.String(), Timestamp: now})
+			lastGrowth = now
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	evaluate()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			evaluate()
+		}
+	}
+}
+
+// dlqThresholds returns the positive thresholds in ascending order without
+// duplicates.
+func dlqThresholds(in []int64) []int64 {
+	var out []int64
+	for _, t := range in {
+		if t > 0 {
+			out = append(out, t)
+		}
+	}
+	slices.Sort(out)
+	return slices.Compact(out)
+}
+
+// DLQEvents handles GET /api/v1/events/dlq with a Server-Sent Events
+// stream of DLQEvent. A reconnecting client sends the Last-Event-ID header
+// (or the last_event_id query parameter) and first receives the retained
+// events it missed. Since EventSource cannot set headers, the bearer token
+// may be passed as the access_token query parameter.
+func (h *Handler) DLQEvents(w http.ResponseWriter, r *http.Request) {
+	lastID, hasLast := lastEventID(r)
+	ch, replay, status, ok := h.dlqAlerts.subscribe(lastID, hasLast)
+	if !ok {
+		writeError(w, http.StatusServiceUnavailable, "STREAM_LIMIT", "Too many event stream connections")
+		return
+	}
+	defer h.dlqAlerts.unsubscribe(ch)
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	// write sends one chunk under a fresh deadline, since the server's
+	// WriteTimeout would otherwise end the stream
+	write := func(chunk string) bool {
+		_ = rc.SetWriteDeadline(time.Now().Add(streamWriteWait))
+		if _, err := fmt.Fprint(w, chunk); err != nil {
+			return false
+		}
+		return rc.Flush() == nil
+	}
+
+	if !write(fmt.Sprintf("retry: %d\n\n", dlqEventsRetry.Milliseconds())) {
+		return
+	}
+	for _, ev := range replay {
+		if !write(formatSSE(ev)) {
+			return
+		}
+	}
+	if status != nil && !write(formatSSE(*status)) {
+		return
+	}
+
+	keepAlive := time.NewTicker(dlqEventsKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev, ok := <-ch:
+			if !ok || !write(formatSSE(ev)) {
+				return
+			}
+		case <-keepAlive.C:
+			if !write(": keepalive\n\n") {
+				return
+			}
+		}
+	}
+}
+
+// lastEventID reads the ID a reconnecting client last saw.
+func lastEventID(r *http.Request) (uint64, bool) {
+	v := r.Header.Get("Last-Event-ID")
+	if v == "" {
+		v = r.URL.Query().Get("last_event_id")
+	}
+	id, err := strconv.ParseUint(v, 10, 64)
+	return id, err == nil
+}
+
+// formatSSE renders ev as one SSE message.
+func formatSSE(ev DLQEvent) string {
+	data, _ := json.Marshal(ev)
+	if ev.ID == 0 {
+		return fmt.Sprintf("event: %s\ndata: %s\n\n", ev.Type, data)
+	}
+	return fmt.Sprintf("id: %d\nevent: %s\ndata: %s\n\n", ev.ID, ev.Type, data)
+}
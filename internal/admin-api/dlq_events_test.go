@@ -0,0 +1,146 @@
+// Copyright 2025 James Ross
+package adminapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type sseMessage struct {
+	id    string
+	event string
+	data  DLQEvent
+}
+
+// openDLQEvents connects to the SSE endpoint and returns a channel of the
+// messages read from it.
+func openDLQEvents(t *testing.T, srv *httptest.Server, lastID string) (<-chan sseMessage, func()) {
+	t.Helper()
+	req, _ := http.NewRequest("GET", srv.URL+"/api/v1/events/dlq", nil)
+	if lastID != "" {
+		req.Header.Set("Last-Event-ID", lastID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	out := make(chan sseMessage, 32)
+	go func() {
+		defer close(out)
+		sc := bufio.NewScanner(resp.Body)
+		var msg sseMessage
+		for sc.Scan() {
+			line := sc.Text()
+			switch {
+			case line == "":
+				if msg.event != "" {
+					out <- msg
+				}
+				msg = sseMessage{}
+			case strings.HasPrefix(line, "id: "):
+				msg.id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				msg.event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				_ = json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &msg.data)
+			}
+		}
+	}()
+	return out, func() { resp.Body.Close() }
+}
+
+func nextSSE(t *testing.T, ch <-chan sseMessage) sseMessage {
+	t.Helper()
+	select {
+	case msg, ok := <-ch:
+		if !ok {
+			t.Fatal("event stream closed")
+		}
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for an event")
+	}
+	return sseMessage{}
+}
+
+func TestDLQEventsThresholdsGrowthAndReplay(t *testing.T) {
+	handler, _, cleanup := setupHandlerTest(t)
+	defer cleanup()
+	handler.apiCfg.DLQAlertInterval = 10 * time.Millisecond
+	handler.apiCfg.DLQAlertThresholds = []int64{4, 2, 4, 0}
+	handler.apiCfg.DLQAlertGrowth = 3
+	handler.apiCfg.DLQAlertWindow = time.Hour
+	handler.apiCfg.DLQAlertHistory = 10
+	ctx := context.Background()
+	dlq := handler.cfg.Worker.DeadLetterList
+
+	srv := httptest.NewServer(http.HandlerFunc(handler.DLQEvents))
+	defer srv.Close()
+	events, closeStream := openDLQEvents(t, srv, "")
+
+	if msg := nextSSE(t, events); msg.event != DLQEventStatus || msg.id != "" || msg.data.Length != 0 {
+		t.Fatalf("expected an initial status, got %+v", msg)
+	}
+
+	handler.rdb.LPush(ctx, dlq, "a", "b", "c")
+	if msg := nextSSE(t, events); msg.event != DLQEventThresholdCrossed || msg.id != "1" || msg.data.Threshold != 2 || msg.data.Length != 3 {
+		t.Fatalf("expected threshold 2 crossed, got %+v", msg)
+	}
+	handler.rdb.LPush(ctx, dlq, "d", "e")
+	if msg := nextSSE(t, events); msg.event != DLQEventThresholdCrossed || msg.data.Threshold != 4 {
+		t.Fatalf("expected threshold 4 crossed, got %+v", msg)
+	}
+	if msg := nextSSE(t, events); msg.event != DLQEventGrowth || msg.id != "3" || msg.data.Growth != 5 || msg.data.Window != "1h0m0s" {
+		t.Fatalf("expected growth, got %+v", msg)
+	}
+	closeStream()
+
+	// events raised while disconnected are replayed after Last-Event-ID
+	handler.rdb.Del(ctx, dlq)
+	time.Sleep(100 * time.Millisecond)
+	events, closeStream = openDLQEvents(t, srv, "3")
+	defer closeStream()
+	for _, want := range []struct {
+		id        string
+		threshold int64
+	}{{"4", 4}, {"5", 2}} {
+		msg := nextSSE(t, events)
+		if msg.event != DLQEventThresholdCleared || msg.id != want.id || msg.data.Threshold != want.threshold || msg.data.Length != 0 {
+			t.Fatalf("expected threshold %d cleared as %s, got %+v", want.threshold, want.id, msg)
+		}
+	}
+	if msg := nextSSE(t, events); msg.event != DLQEventStatus || msg.data.Length != 0 {
+		t.Fatalf("expected status after the replay, got %+v", msg)
+	}
+}
+
+func TestDLQEventsAcceptQueryToken(t *testing.T) {
+	secret := "test-secret"
+	token := mustMakeScopedToken(t, secret, []string{"stats:read"})
+	handler := AuthMiddleware(secret, true, zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for path, want := range map[string]int{
+		"/api/v1/events/dlq": http.StatusOK,
+		"/api/v1/stats":      http.StatusUnauthorized,
+	} {
+		req := httptest.NewRequest("GET", path+"?access_token="+token, nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != want {
+			t.Errorf("%s: expected %d, got %d", path, want, w.Code)
+		}
+	}
+}
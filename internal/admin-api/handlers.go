@@ -4,6 +4,7 @@ package adminapi
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -12,27 +13,32 @@ import (
 
 	"github.com/flyingrobots/go-redis-work-queue/internal/admin"
 	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
 // Handler holds the API handler dependencies
 type Handler struct {
-	cfg      *config.Config
-	apiCfg   *Config
-	rdb      *redis.Client
-	logger   *zap.Logger
-	auditLog *AuditLogger
+	cfg       *config.Config
+	apiCfg    *Config
+	rdb       *redis.Client
+	logger    *zap.Logger
+	auditLog  *AuditLogger
+	stream    *statsHub
+	dlqAlerts *dlqAlertHub
 }
 
 // NewHandler creates a new API handler
 func NewHandler(cfg *config.Config, apiCfg *Config, rdb *redis.Client, logger *zap.Logger, auditLog *AuditLogger) *Handler {
 	return &Handler{
-		cfg:      cfg,
-		apiCfg:   apiCfg,
-		rdb:      rdb,
-		logger:   logger,
-		auditLog: auditLog,
+		cfg:       cfg,
+		apiCfg:    apiCfg,
+		rdb:       rdb,
+		logger:    logger,
+		auditLog:  auditLog,
+		stream:    newStatsHub(cfg, apiCfg, rdb, logger),
+		dlqAlerts: newDLQAlertHub(cfg, apiCfg, rdb, logger),
 	}
 }
 
@@ -83,6 +89,9 @@ func (h *Handler) GetStatsKeys(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, response)
 }
 
+// maxPeekScanLimit is the largest scan_limit PeekQueue accepts.
+const maxPeekScanLimit = 100000
+
 // PeekQueue handles GET /api/v1/queues/{queue}/peek
 func (h *Handler) PeekQueue(w http.ResponseWriter, r *http.Request) {
 	// Extract queue name from path
@@ -104,7 +113,27 @@ func (h *Handler) PeekQueue(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 	defer cancel()
 
-	result, err := admin.Peek(ctx, h.cfg, h.rdb, queue, int64(count))
+	// A filtered peek stops after scanLimit items, or at the deadline, and
+	// hands back the matches so far with a cursor.
+	scanLimit := admin.DefaultPeekScanLimit
+	if sl := r.URL.Query().Get("scan_limit"); sl != "" {
+		if n, err := strconv.ParseInt(sl, 10, 64); err == nil && n > 0 && n <= maxPeekScanLimit {
+			scanLimit = n
+		}
+	}
+
+	opts := admin.PeekOptions{
+		Filter:    r.URL.Query().Get("filter"),
+		Project:   admin.ParseProjection(r.URL.Query().Get("project")),
+		ScanLimit: scanLimit,
+		Cursor:    r.URL.Query().Get("cursor"),
+	}
+
+	result, err := admin.PeekWithOptions(ctx, h.cfg, h.rdb, queue, int64(count), opts)
+	if errors.Is(err, admin.ErrInvalidCursor) {
+		writeError(w, http.StatusBadRequest, "INVALID_CURSOR", err.Error())
+		return
+	}
 	if err != nil {
 		h.logger.Error("Failed to peek queue", zap.Error(err), zap.String("queue", queue))
 		writeError(w, http.StatusBadRequest, "PEEK_ERROR", err.Error())
@@ -112,10 +141,13 @@ func (h *Handler) PeekQueue(w http.ResponseWriter, r *http.Request) {
 	}
 
 	response := PeekResponse{
-		Queue:     result.Queue,
-		Items:     result.Items,
-		Count:     len(result.Items),
-		Timestamp: time.Now(),
+		Queue:      result.Queue,
+		Items:      result.Items,
+		Count:      len(result.Items),
+		Scanned:    result.Scanned,
+		NextCursor: result.NextCursor,
+		HasMore:    result.HasMore,
+		Timestamp:  time.Now(),
 	}
 
 	writeJSON(w, http.StatusOK, response)
@@ -354,12 +386,16 @@ func (h *Handler) ListDLQ(w http.ResponseWriter, r *http.Request) {
 	}
 
 	items, next, err := admin.DLQList(ctx, h.cfg, h.rdb, ns, cursor, limit)
+	if errors.Is(err, admin.ErrInvalidCursor) {
+		writeError(w, http.StatusBadRequest, "INVALID_CURSOR", err.Error())
+		return
+	}
 	if err != nil {
 		h.logger.Error("Failed to list DLQ", zap.Error(err))
 		writeError(w, http.StatusInternalServerError, "DLQ_ERROR", "Failed to list DLQ")
 		return
 	}
-	out := DLQListResponse{Items: make([]DLQItem, 0, len(items)), NextCursor: next, Count: len(items), Timestamp: time.Now()}
+	out := DLQListResponse{Items: make([]DLQItem, 0, len(items)), NextCursor: next, HasMore: next != "", Count: len(items), Timestamp: time.Now()}
 	for _, it := range items {
 		out.Items = append(out.Items, DLQItem{
 			ID:        it.ID,
@@ -455,12 +491,89 @@ func (h *Handler) PurgeDLQItems(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, DLQPurgeSelectionResponse{Purged: n, Timestamp: time.Now()})
 }
 
+// BulkJobs handles POST /api/v1/jobs/bulk. Items are processed
+// independently: the response lists each one's outcome and is 200 even
+// when some of them failed.
+func (h *Handler) BulkJobs(w http.ResponseWriter, r *http.Request) {
+	var req BulkJobsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+	switch req.Action {
+	case admin.BulkRequeue, admin.BulkDelete, admin.BulkArchive:
+	default:
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "action must be requeue, delete or archive")
+		return
+	}
+	if len(req.Items) == 0 {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "items required")
+		return
+	}
+	if max := h.apiCfg.BulkMaxItems; max > 0 && len(req.Items) > max {
+		writeError(w, http.StatusBadRequest, "TOO_MANY_ITEMS", fmt.Sprintf("at most %d items per request", max))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
+	defer cancel()
+	res, err := admin.BulkJobs(ctx, h.cfg, h.rdb, req.Items, admin.BulkOptions{
+		Action:      req.Action,
+		Queue:       req.Queue,
+		DestQueue:   req.DestQueue,
+		ArchiveList: h.apiCfg.BulkArchiveList,
+		BatchSize:   h.apiCfg.BulkBatchSize,
+		Concurrency: h.apiCfg.BulkConcurrency,
+	})
+	if err != nil {
+		h.logger.Error("Failed to run bulk job operation", zap.String("action", req.Action), zap.Error(err))
+		writeError(w, http.StatusBadRequest, "BULK_ERROR", err.Error())
+		return
+	}
+
+	out := BulkJobsResponse{
+		Action:    res.Action,
+		Queue:     res.Queue,
+		Total:     len(res.Items),
+		Succeeded: res.Succeeded,
+		Failed:    res.Failed,
+		Results:   make([]BulkJobResult, 0, len(res.Items)),
+		Timestamp: time.Now(),
+	}
+	for _, it := range res.Items {
+		out.Results = append(out.Results, BulkJobResult(it))
+	}
+	if h.auditLog != nil {
+		result := "SUCCESS"
+		if res.Failed > 0 {
+			result = "PARTIAL"
+		}
+		entry := AuditEntry{
+			ID:        generateID(),
+			Timestamp: time.Now(),
+			Action:    "JOBS_BULK_" + strings.ToUpper(req.Action),
+			Resource:  res.Queue,
+			Result:    result,
+			Details: map[string]interface{}{
+				"succeeded": res.Succeeded,
+				"failed":    res.Failed,
+			},
+			IP:        getClientIP(r),
+			UserAgent: r.UserAgent(),
+		}
+		if claims, ok := r.Context().Value(contextKeyClaims).(*Claims); ok {
+			entry.User = claims.Subject
+		}
+		h.auditLog.Log(entry)
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
 // GetWorkers handles GET /api/v1/workers
 func (h *Handler) GetWorkers(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 	defer cancel()
-	ns := r.URL.Query().Get("ns")
-	list, err := admin.Workers(ctx, h.cfg, h.rdb, ns)
+	list, err := admin.Workers(ctx, h.cfg, h.rdb)
 	if err != nil {
 		h.logger.Error("Failed to get workers", zap.Error(err))
 		writeError(w, http.StatusInternalServerError, "WORKERS_ERROR", "Failed to retrieve workers")
@@ -476,11 +589,220 @@ func (h *Handler) GetWorkers(w http.ResponseWriter, r *http.Request) {
 			StartedAt:     wi.StartedAt,
 			Version:       wi.Version,
 			Host:          wi.Host,
+			Alive:         wi.Alive,
+			Processing:    wi.Processing,
+			ReapCandidate: wi.ReapCandidate,
 		})
 	}
 	writeJSON(w, http.StatusOK, out)
 }
 
+// GetJob handles GET /api/v1/jobs/{id}: it searches every queue,
+// processing list, scheduled set and the dead letter and completed lists
+// for the job and answers 404 when none holds it.
+func (h *Handler) GetJob(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/api/v1/jobs/")
+	if id == "" || strings.Contains(id, "/") {
+		writeError(w, http.StatusBadRequest, "INVALID_PATH", "Invalid path format")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+	loc, err := admin.FindJob(ctx, h.cfg, h.rdb, id)
+	if errors.Is(err, admin.ErrJobNotFound) {
+		writeError(w, http.StatusNotFound, "JOB_NOT_FOUND", fmt.Sprintf("Job %s not found", id))
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to find job", zap.Error(err), zap.String("job_id", id))
+		writeError(w, http.StatusInternalServerError, "FIND_JOB_ERROR", "Failed to search for the job")
+		return
+	}
+	writeJSON(w, http.StatusOK, JobLocationResponse{
+		JobID:     loc.JobID,
+		Kind:      loc.Kind,
+		Key:       loc.Key,
+		Position:  loc.Position,
+		StreamID:  loc.StreamID,
+		DueAt:     loc.DueAt,
+		Payload:   loc.Payload,
+		Timestamp: time.Now(),
+	})
+}
+
+// ListQueues handles GET /api/v1/queues
+func (h *Handler) ListQueues(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+	defs, err := admin.ListQueueDefinitions(ctx, h.cfg, h.rdb)
+	if err != nil {
+		h.logger.Error("Failed to list queue definitions", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "QUEUE_ERROR", "Failed to list queues")
+		return
+	}
+	resp := QueueDefinitionsResponse{Queues: make([]QueueDefinitionResponse, 0, len(defs)), Count: len(defs), Timestamp: time.Now()}
+	for _, def := range defs {
+		resp.Queues = append(resp.Queues, h.queueDefinitionResponse(ctx, &def))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// GetQueue handles GET /api/v1/queues/{name}
+func (h *Handler) GetQueue(w http.ResponseWriter, r *http.Request) {
+	name, ok := queueNameFromPath(w, r)
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+	def, err := admin.GetQueueDefinition(ctx, h.cfg, h.rdb, name)
+	if err != nil {
+		h.writeQueueError(w, err, name)
+		return
+	}
+	writeJSON(w, http.StatusOK, h.queueDefinitionResponse(ctx, def))
+}
+
+// CreateQueue handles POST /api/v1/queues
+func (h *Handler) CreateQueue(w http.ResponseWriter, r *http.Request) {
+	var req QueueDefinitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+	def, err := admin.CreateQueueDefinition(ctx, h.cfg, h.rdb, req.definition())
+	if err != nil {
+		h.writeQueueError(w, err, req.Name)
+		return
+	}
+	writeJSON(w, http.StatusCreated, h.queueDefinitionResponse(ctx, def))
+}
+
+// UpdateQueue handles PUT /api/v1/queues/{name}
+func (h *Handler) UpdateQueue(w http.ResponseWriter, r *http.Request) {
+	name, ok := queueNameFromPath(w, r)
+	if !ok {
+		return
+	}
+	var req QueueDefinitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+	if req.Name != "" && req.Name != name {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Body name does not match the path; queues cannot be renamed")
+		return
+	}
+	req.Name = name
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+	def, err := admin.UpdateQueueDefinition(ctx, h.cfg, h.rdb, req.definition())
+	if err != nil {
+		h.writeQueueError(w, err, name)
+		return
+	}
+	writeJSON(w, http.StatusOK, h.queueDefinitionResponse(ctx, def))
+}
+
+// DeleteQueue handles DELETE /api/v1/queues/{name}. A queue holding jobs
+// is only deleted, with its jobs, when force=true.
+func (h *Handler) DeleteQueue(w http.ResponseWriter, r *http.Request) {
+	name, ok := queueNameFromPath(w, r)
+	if !ok {
+		return
+	}
+	force, _ := strconv.ParseBool(r.URL.Query().Get("force"))
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+	deleted, err := admin.DeleteQueueDefinition(ctx, h.cfg, h.rdb, name, force)
+	if err != nil {
+		h.writeQueueError(w, err, name)
+		return
+	}
+
+	if h.auditLog != nil {
+		entry := AuditEntry{
+			ID:        generateID(),
+			Timestamp: time.Now(),
+			Action:    "DELETE_QUEUE",
+			Resource:  name,
+			Result:    "SUCCESS",
+			Details: map[string]interface{}{
+				"force":        force,
+				"jobs_deleted": deleted,
+			},
+			IP:        getClientIP(r),
+			UserAgent: r.UserAgent(),
+		}
+		if claims, ok := r.Context().Value(contextKeyClaims).(*Claims); ok {
+			entry.User = claims.Subject
+		}
+		h.auditLog.Log(entry)
+	}
+
+	writeJSON(w, http.StatusOK, QueueDeleteResponse{Success: true, Name: name, JobsDeleted: deleted, Timestamp: time.Now()})
+}
+
+func (req QueueDefinitionRequest) definition() queue.Definition {
+	return queue.Definition{
+		Name:      req.Name,
+		Priority:  req.Priority,
+		RateLimit: req.RateLimit,
+		Burst:     req.Burst,
+		MaxLength: req.MaxLength,
+		DeadLetter: queue.DeadLetterPolicy{
+			MaxRetries: req.DeadLetter.MaxRetries,
+			List:       req.DeadLetter.List,
+		},
+	}
+}
+
+func (h *Handler) queueDefinitionResponse(ctx context.Context, def *queue.Definition) QueueDefinitionResponse {
+	length, _ := h.rdb.LLen(ctx, def.Name).Result()
+	return QueueDefinitionResponse{
+		Name:      def.Name,
+		Priority:  def.Priority,
+		RateLimit: def.RateLimit,
+		Burst:     def.Burst,
+		MaxLength: def.MaxLength,
+		DeadLetter: QueueDeadLetterPolicy{
+			MaxRetries: def.DeadLetter.MaxRetries,
+			List:       def.DeadLetter.List,
+		},
+		Length:    length,
+		CreatedAt: def.CreatedAt,
+		UpdatedAt: def.UpdatedAt,
+	}
+}
+
+func queueNameFromPath(w http.ResponseWriter, r *http.Request) (string, bool) {
+	name := strings.TrimPrefix(r.URL.Path, "/api/v1/queues/")
+	if name == "" || strings.Contains(name, "/") {
+		writeError(w, http.StatusBadRequest, "INVALID_PATH", "Invalid path format")
+		return "", false
+	}
+	return name, true
+}
+
+func (h *Handler) writeQueueError(w http.ResponseWriter, err error, name string) {
+	switch {
+	case errors.Is(err, admin.ErrInvalidQueueDefinition):
+		writeError(w, http.StatusBadRequest, "INVALID_QUEUE", err.Error())
+	case errors.Is(err, admin.ErrQueueNotDefined):
+		writeError(w, http.StatusNotFound, "QUEUE_NOT_FOUND", fmt.Sprintf("Queue %s is not defined", name))
+	case errors.Is(err, admin.ErrQueueExists):
+		writeError(w, http.StatusConflict, "QUEUE_EXISTS", fmt.Sprintf("Queue %s is already defined", name))
+	case errors.Is(err, admin.ErrQueueNotEmpty):
+		writeError(w, http.StatusConflict, "QUEUE_NOT_EMPTY", err.Error())
+	default:
+		h.logger.Error("Queue definition operation failed", zap.Error(err), zap.String("queue", name))
+		writeError(w, http.StatusInternalServerError, "QUEUE_ERROR", "Queue operation failed")
+	}
+}
+
 // Helper functions
 
 func writeJSON(w http.ResponseWriter, status int, v interface{}) {
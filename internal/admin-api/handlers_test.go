@@ -4,8 +4,11 @@ package adminapi
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -135,6 +138,39 @@ func TestPeekQueue(t *testing.T) {
 	}
 }
 
+func TestPeekQueueScanLimitHandsBackCursor(t *testing.T) {
+	handler, mr, cleanup := setupHandlerTest(t)
+	defer cleanup()
+
+	for i := 0; i < 30; i++ {
+		mr.Lpush("jobqueue:high", fmt.Sprintf(`{"n":%d}`, i))
+	}
+
+	peek := func(query string) PeekResponse {
+		t.Helper()
+		w := httptest.NewRecorder()
+		handler.PeekQueue(w, httptest.NewRequest("GET", "/api/v1/queues/high/peek?"+query, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var resp PeekResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return resp
+	}
+
+	filter := url.QueryEscape(`$.n == 25`)
+	first := peek("filter=" + filter + "&scan_limit=20")
+	if first.Count != 0 || first.Scanned != 20 || !first.HasMore || first.NextCursor == "" {
+		t.Fatalf("expected an empty capped page with a cursor, got %+v", first)
+	}
+	next := peek("filter=" + filter + "&scan_limit=20&cursor=" + url.QueryEscape(first.NextCursor))
+	if next.Count != 1 || !strings.Contains(next.Items[0], `"n":25`) {
+		t.Fatalf("expected the match on the next page, got %+v", next)
+	}
+}
+
 func TestPurgeDLQ(t *testing.T) {
 	handler, mr, cleanup := setupHandlerTest(t)
 	defer cleanup()
@@ -322,3 +358,139 @@ func TestJWTValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestBulkJobsPartialSuccessAndIdempotency(t *testing.T) {
+	handler, mr, cleanup := setupHandlerTest(t)
+	defer cleanup()
+	handler.apiCfg.BulkMaxItems = 3
+	mr.Lpush("jobqueue:dead_letter", `{"id":"job-1","priority":"low"}`)
+	mr.Lpush("jobqueue:dead_letter", `{"id":"job-2","priority":"high"}`)
+
+	h := IdempotencyMiddleware(handler.rdb, DefaultConfig(), zap.NewNop())(http.HandlerFunc(handler.BulkJobs))
+	send := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/v1/jobs/bulk", bytes.NewReader([]byte(body)))
+		req.Header.Set(IdempotencyKeyHeader, "cleanup-1")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	body := `{"action":"requeue","items":["job-1","job-2","job-9"]}`
+	w := send(body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp BulkJobsResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Total != 3 || resp.Succeeded != 2 || resp.Failed != 1 || resp.Results[2].Error == "" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if resp.Results[0].Dest != "jobqueue:low" || resp.Results[1].Dest != "jobqueue:high" {
+		t.Fatalf("jobs should return to their priority queues: %+v", resp.Results)
+	}
+
+	// a retry with the same key replays the first result instead of
+	// reporting every item as missing
+	replay := send(body)
+	if replay.Header().Get(IdempotentReplayedHeader) != "true" || !bytes.Contains(replay.Body.Bytes(), []byte(`"succeeded":2`)) {
+		t.Fatalf("expected a replay, got %s", replay.Body.String())
+	}
+
+	for _, bad := range []string{`{"action":"nuke","items":["a"]}`, `{"action":"delete","items":[]}`, `{"action":"delete","items":["a","b","c","d"]}`} {
+		req := httptest.NewRequest("POST", "/api/v1/jobs/bulk", bytes.NewReader([]byte(bad)))
+		w := httptest.NewRecorder()
+		handler.BulkJobs(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d", bad, w.Code)
+		}
+	}
+}
+
+func TestGetJob(t *testing.T) {
+	handler, mr, cleanup := setupHandlerTest(t)
+	defer cleanup()
+	mr.Lpush("jobqueue:dead_letter", `{"id":"job-7","filepath":"/data/7"}`)
+
+	req := httptest.NewRequest("GET", "/api/v1/jobs/job-7", nil)
+	w := httptest.NewRecorder()
+	handler.GetJob(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp JobLocationResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.JobID != "job-7" || resp.Kind != "dead_letter" || resp.Key != "jobqueue:dead_letter" || resp.Position != 0 {
+		t.Errorf("Expected job-7 at jobqueue:dead_letter[0], got %+v", resp)
+	}
+
+	w = httptest.NewRecorder()
+	handler.GetJob(w, httptest.NewRequest("GET", "/api/v1/jobs/job-8", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown job, got %d", w.Code)
+	}
+}
+
+func TestQueueDefinitionCRUD(t *testing.T) {
+	handler, mr, cleanup := setupHandlerTest(t)
+	defer cleanup()
+
+	call := func(fn http.HandlerFunc, method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		fn(w, httptest.NewRequest(method, path, bytes.NewBufferString(body)))
+		return w
+	}
+
+	w := call(handler.CreateQueue, "POST", "/api/v1/queues", `{"name":"jobqueue:reports","priority":"low","rate_limit":5,"dead_letter":{"max_retries":1}}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if w = call(handler.CreateQueue, "POST", "/api/v1/queues", `{"name":"jobqueue:reports","priority":"low"}`); w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 for a duplicate, got %d", w.Code)
+	}
+	if w = call(handler.CreateQueue, "POST", "/api/v1/queues", `{"name":"dlq","priority":"low"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a reserved name, got %d", w.Code)
+	}
+
+	if w = call(handler.UpdateQueue, "PUT", "/api/v1/queues/jobqueue:reports", `{"name":"other","priority":"low"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a name that differs from the path, got %d", w.Code)
+	}
+	if w = call(handler.UpdateQueue, "PUT", "/api/v1/queues/jobqueue:missing", `{"priority":"low"}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown queue, got %d", w.Code)
+	}
+	if w = call(handler.UpdateQueue, "PUT", "/api/v1/queues/jobqueue:reports", `{"priority":"high","max_length":100}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	mr.Lpush("jobqueue:reports", "job1")
+	w = call(handler.GetQueue, "GET", "/api/v1/queues/jobqueue:reports", "")
+	var def QueueDefinitionResponse
+	if err := json.NewDecoder(w.Body).Decode(&def); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if def.Priority != "high" || def.MaxLength != 100 || def.RateLimit != 0 || def.Length != 1 {
+		t.Errorf("Expected the replaced definition with one job waiting, got %+v", def)
+	}
+
+	w = call(handler.ListQueues, "GET", "/api/v1/queues", "")
+	var list QueueDefinitionsResponse
+	if err := json.NewDecoder(w.Body).Decode(&list); err != nil || list.Count != 1 {
+		t.Errorf("Expected one definition, got %+v (%v)", list, err)
+	}
+
+	if w = call(handler.DeleteQueue, "DELETE", "/api/v1/queues/jobqueue:reports", ""); w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 for a non-empty queue, got %d", w.Code)
+	}
+	if w = call(handler.DeleteQueue, "DELETE", "/api/v1/queues/jobqueue:reports?force=true", ""); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 with force, got %d: %s", w.Code, w.Body.String())
+	}
+	if mr.Exists("jobqueue:reports") {
+		t.Error("Expected the queue's jobs deleted with force")
+	}
+	if w = call(handler.GetQueue, "GET", "/api/v1/queues/jobqueue:reports", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 after delete, got %d", w.Code)
+	}
+}
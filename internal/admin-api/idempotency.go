@@ -0,0 +1,203 @@
+// Copyright 2025 James Ross
+package adminapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// IdempotencyKeyHeader carries the client's key for a destructive call.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses served from the cache.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLen  = 255
+	maxIdempotentBodySize = 1 << 20
+)
+
+// idempotencyRecord is what is stored under a key: a placeholder while the
+// first request runs, then its response.
+type idempotencyRecord struct {
+	Done        bool   `json:"done"`
+	RequestHash string `json:"request_hash"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// IdempotencyMiddleware makes destructive calls that carry an
+// Idempotency-Key header safe to retry. The first request with a key runs
+// and its response is kept in Redis for IdempotencyTTL; later requests with
+// the same key get that response back, marked Idempotent-Replayed, without
+// running again. Keys are scoped to the token subject (client IP without
+// claims) and to the method and path, so two tokens or two endpoints never
+// share a result.
+//
+// A retry that arrives while the first request is still running gets 409
+// IDEMPOTENCY_IN_FLIGHT. Reusing a key with a different request body gets
+// 422 IDEMPOTENCY_KEY_REUSED. 5xx responses are not kept, so a failed call
+// can be retried with the same key. If Redis is unavailable the call is
+// refused with 503 rather than run without protection.
+func IdempotencyMiddleware(rdb *redis.Client, cfg *Config, logger *zap.Logger) func(http.Handler) http.Handler {
+	prefix := cfg.IdempotencyKeyPrefix
+	if prefix == "" {
+		prefix = "admin-api:idempotency"
+	}
+	lockTTL := cfg.IdempotencyLockTTL
+	if lockTTL <= 0 {
+		lockTTL = time.Minute
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			idemKey := r.Header.Get(IdempotencyKeyHeader)
+			if idemKey == "" || cfg.IdempotencyTTL <= 0 || !isDestructiveOperation(r.Method, r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(idemKey) > maxIdempotencyKeyLen {
+				writeError(w, http.StatusBadRequest, "INVALID_IDEMPOTENCY_KEY",
+					fmt.Sprintf("Idempotency-Key must be at most %d characters", maxIdempotencyKeyLen))
+				return
+			}
+
+			body, err := io.ReadAll(io.LimitReader(r.Body, maxIdempotentBodySize+1))
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "BAD_REQUEST", "Failed to read request body")
+				return
+			}
+			if len(body) > maxIdempotentBodySize {
+				writeError(w, http.StatusRequestEntityTooLarge, "BODY_TOO_LARGE", "Request body too large")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			var subject string
+			if claims, ok := r.Context().Value(contextKeyClaims).(*Claims); ok {
+				subject = claims.Subject
+			} else {
+				subject = getClientIP(r)
+			}
+			key := fmt.Sprintf("%s:%s:%s:%s:%s", prefix, subject, r.Method, r.URL.Path, hashHex([]byte(idemKey)))
+			reqHash := hashHex([]byte(r.URL.RawQuery + "\n" + string(body)))
+
+			placeholder, _ := json.Marshal(idempotencyRecord{RequestHash: reqHash})
+			ok, err := rdb.SetNX(r.Context(), key, placeholder, lockTTL).Result()
+			if err != nil {
+				logger.Warn("Idempotency check failed", zap.String("subject", subject), zap.Error(err))
+				writeError(w, http.StatusServiceUnavailable, "IDEMPOTENCY_UNAVAILABLE", "Idempotency store unavailable")
+				return
+			}
+			if !ok {
+				replayIdempotent(w, r, rdb, key, reqHash, logger)
+				return
+			}
+
+			rec := &idempotencyRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			// Store with a fresh context: the client may have gone away,
+			// which is exactly when it will retry.
+			ctx := context.WithoutCancel(r.Context())
+			if rec.status >= 500 {
+				if err := rdb.Del(ctx, key).Err(); err != nil {
+					logger.Warn("Failed to release idempotency key", zap.Error(err))
+				}
+				return
+			}
+			stored, _ := json.Marshal(idempotencyRecord{
+				Done:        true,
+				RequestHash: reqHash,
+				Status:      rec.status,
+				ContentType: rec.Header().Get("Content-Type"),
+				Body:        rec.body.Bytes(),
+			})
+			if err := rdb.Set(ctx, key, stored, cfg.IdempotencyTTL).Err(); err != nil {
+				logger.Warn("Failed to store idempotent response",
+					zap.String("subject", subject),
+					zap.String("path", r.URL.Path),
+					zap.Error(err))
+			}
+		})
+	}
+}
+
+// replayIdempotent answers a request whose key is already taken.
+func replayIdempotent(w http.ResponseWriter, r *http.Request, rdb *redis.Client, key, reqHash string, logger *zap.Logger) {
+	raw, err := rdb.Get(r.Context(), key).Bytes()
+	if err == redis.Nil {
+		// the first request failed and released the key in between
+		writeInFlight(w)
+		return
+	}
+	var prev idempotencyRecord
+	if err == nil {
+		err = json.Unmarshal(raw, &prev)
+	}
+	if err != nil {
+		logger.Warn("Idempotency lookup failed", zap.Error(err))
+		writeError(w, http.StatusServiceUnavailable, "IDEMPOTENCY_UNAVAILABLE", "Idempotency store unavailable")
+		return
+	}
+
+	if prev.RequestHash != reqHash {
+		writeError(w, http.StatusUnprocessableEntity, "IDEMPOTENCY_KEY_REUSED",
+			"Idempotency-Key was already used with a different request")
+		return
+	}
+	if !prev.Done {
+		writeInFlight(w)
+		return
+	}
+
+	if prev.ContentType != "" {
+		w.Header().Set("Content-Type", prev.ContentType)
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(prev.Status)
+	w.Write(prev.Body)
+}
+
+func writeInFlight(w http.ResponseWriter) {
+	w.Header().Set("Retry-After", "1")
+	writeError(w, http.StatusConflict, "IDEMPOTENCY_IN_FLIGHT",
+		"A request with this Idempotency-Key is still being processed")
+}
+
+func hashHex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// idempotencyRecorder passes the response through while keeping a copy.
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *idempotencyRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *idempotencyRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,179 @@
+// Copyright 2025 James Ross
+package adminapi
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// newIdempotencyHandler wraps next in IdempotencyMiddleware. Requests carry
+// no claims, so the client IP (X-Real-IP) stands in for the token subject.
+func newIdempotencyHandler(t *testing.T, next http.HandlerFunc) (http.Handler, *miniredis.Miniredis) {
+	t.Helper()
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(mr.Close)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	return IdempotencyMiddleware(rdb, DefaultConfig(), zap.NewNop())(next), mr
+}
+
+func idempotentRequest(h http.Handler, path, key, client, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", path, strings.NewReader(body))
+	req.Header.Set(IdempotencyKeyHeader, key)
+	req.Header.Set("X-Real-IP", client)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func countingHandler(calls *atomic.Int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"call":` + strconv.Itoa(int(n)) + `,"body":"` + string(body) + `"}`))
+	}
+}
+
+func TestIdempotencyReplaysResponse(t *testing.T) {
+	var calls atomic.Int32
+	h, _ := newIdempotencyHandler(t, countingHandler(&calls))
+
+	first := idempotentRequest(h, "/api/v1/dlq/requeue", "k1", "10.0.0.1", "x")
+	second := idempotentRequest(h, "/api/v1/dlq/requeue", "k1", "10.0.0.1", "x")
+
+	if calls.Load() != 1 {
+		t.Fatalf("handler ran %d times, want 1", calls.Load())
+	}
+	if second.Code != http.StatusCreated || second.Body.String() != first.Body.String() {
+		t.Fatalf("replay = %d %q, want %d %q", second.Code, second.Body.String(), first.Code, first.Body.String())
+	}
+	if second.Header().Get(IdempotentReplayedHeader) != "true" || first.Header().Get(IdempotentReplayedHeader) != "" {
+		t.Fatal("only the replay should be marked Idempotent-Replayed")
+	}
+	if second.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("replay lost Content-Type: %q", second.Header().Get("Content-Type"))
+	}
+}
+
+func TestIdempotencyKeysAreScoped(t *testing.T) {
+	var calls atomic.Int32
+	h, _ := newIdempotencyHandler(t, countingHandler(&calls))
+
+	idempotentRequest(h, "/api/v1/dlq/requeue", "k1", "10.0.0.1", "x")
+	idempotentRequest(h, "/api/v1/dlq/requeue", "k1", "10.0.0.2", "x") // another token
+	idempotentRequest(h, "/api/v1/dlq/purge", "k1", "10.0.0.1", "x")   // another endpoint
+	idempotentRequest(h, "/api/v1/dlq/requeue", "k2", "10.0.0.1", "x") // another key
+
+	if calls.Load() != 4 {
+		t.Fatalf("handler ran %d times, want 4", calls.Load())
+	}
+}
+
+func TestIdempotencyInFlight(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	h, _ := newIdempotencyHandler(t, func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- idempotentRequest(h, "/api/v1/dlq/purge", "k1", "10.0.0.1", "x") }()
+	<-started
+
+	w := idempotentRequest(h, "/api/v1/dlq/purge", "k1", "10.0.0.1", "x")
+	if w.Code != http.StatusConflict {
+		t.Fatalf("concurrent retry = %d, want 409", w.Code)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Fatal("409 should carry Retry-After")
+	}
+	close(release)
+	if first := <-done; first.Code != http.StatusOK {
+		t.Fatalf("first request = %d, want 200", first.Code)
+	}
+}
+
+func TestIdempotencyRejectsReusedKey(t *testing.T) {
+	var calls atomic.Int32
+	h, _ := newIdempotencyHandler(t, countingHandler(&calls))
+
+	idempotentRequest(h, "/api/v1/dlq/requeue", "k1", "10.0.0.1", "a")
+	w := idempotentRequest(h, "/api/v1/dlq/requeue", "k1", "10.0.0.1", "b")
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("reused key = %d, want 422", w.Code)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("handler ran %d times, want 1", calls.Load())
+	}
+}
+
+func TestIdempotencyDoesNotKeepServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	h, _ := newIdempotencyHandler(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "boom")
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	if w := idempotentRequest(h, "/api/v1/dlq/purge", "k1", "10.0.0.1", "x"); w.Code != http.StatusInternalServerError {
+		t.Fatalf("first = %d, want 500", w.Code)
+	}
+	if w := idempotentRequest(h, "/api/v1/dlq/purge", "k1", "10.0.0.1", "x"); w.Code != http.StatusOK {
+		t.Fatalf("retry after 500 = %d, want 200", w.Code)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("handler ran %d times, want 2", calls.Load())
+	}
+}
+
+func TestIdempotencyExpiresAndIgnoresReads(t *testing.T) {
+	var calls atomic.Int32
+	h, mr := newIdempotencyHandler(t, countingHandler(&calls))
+
+	idempotentRequest(h, "/api/v1/dlq/requeue", "k1", "10.0.0.1", "x")
+	mr.FastForward(25 * time.Hour)
+	idempotentRequest(h, "/api/v1/dlq/requeue", "k1", "10.0.0.1", "x")
+	if calls.Load() != 2 {
+		t.Fatalf("handler ran %d times after the TTL, want 2", calls.Load())
+	}
+
+	req := httptest.NewRequest("GET", "/api/v1/stats", nil)
+	req.Header.Set(IdempotencyKeyHeader, "k1")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if calls.Load() != 4 {
+		t.Fatalf("reads should not be deduplicated, handler ran %d times", calls.Load())
+	}
+}
+
+func TestIdempotencyUnavailable(t *testing.T) {
+	var calls atomic.Int32
+	h, mr := newIdempotencyHandler(t, countingHandler(&calls))
+	mr.Close()
+
+	if w := idempotentRequest(h, "/api/v1/dlq/purge", "k1", "10.0.0.1", "x"); w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("redis down = %d, want 503", w.Code)
+	}
+	if calls.Load() != 0 {
+		t.Fatal("handler must not run without the idempotency store")
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright 2025 James Ross
+package adminapi
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/admin"
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// metricsPath serves the admin API's Prometheus metrics.
+const metricsPath = "/metrics"
+
+// metricsScrapeTimeout bounds the Redis reads behind one scrape.
+const metricsScrapeTimeout = 5 * time.Second
+
+// apiMetrics holds the admin API's own registry: request counts and
+// latencies by route, the queue and worker gauges read from Redis on each
+// scrape, and the Go runtime and process collectors.
+type apiMetrics struct {
+	registry *prometheus.Registry
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+func newAPIMetrics(appCfg *config.Config, rdb *redis.Client, logger *zap.Logger) *apiMetrics {
+	m := &apiMetrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "admin_api_requests_total",
+			Help: "Admin API requests by route, method and status code",
+		}, []string{"route", "method", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "admin_api_request_duration_seconds",
+			Help:    "Admin API request latency by route and method",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"route", "method"}),
+	}
+	m.registry.MustRegister(
+		m.requests,
+		m.duration,
+		newQueueCollector(appCfg, rdb, logger),
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// handler serves the registry in the Prometheus exposition format. A scrape
+// whose Redis reads fail still returns the request metrics, with
+// admin_api_scrape_success at 0.
+func (m *apiMetrics) handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{ErrorHandling: promhttp.ContinueOnError})
+}
+
+// middleware records every request, including ones auth or rate limits
+// reject. The route label is the pattern routes would dispatch the request
+// to, so paths with IDs do not create new series; requests matching no
+// route are counted as "other".
+func (m *apiMetrics) middleware(routes *http.ServeMux) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := "other"
+			if routes != nil {
+				if _, pattern := routes.Handler(r); pattern != "" {
+					route = pattern
+				}
+			}
+			method := metricsMethod(r.Method)
+			rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			start := time.Now()
+			next.ServeHTTP(rw, r)
+			m.duration.WithLabelValues(route, method).Observe(time.Since(start).Seconds())
+			m.requests.WithLabelValues(route, method, strconv.Itoa(rw.statusCode)).Inc()
+		})
+	}
+}
+
+// metricsMethod keeps the method label to the standard methods.
+func metricsMethod(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions:
+		return method
+	}
+	return "other"
+}
+
+// queueCollector reads queue lengths and worker liveness from Redis at
+// scrape time. Queue labels are the keys of the configured priority queues
+// and the completed and dead letter lists, so series stay bounded.
+type queueCollector struct {
+	cfg    *config.Config
+	rdb    *redis.Client
+	logger *zap.Logger
+
+	queueLength *prometheus.Desc
+	processing  *prometheus.Desc
+	workers     *prometheus.Desc
+	success     *prometheus.Desc
+}
+
+func newQueueCollector(cfg *config.Config, rdb *redis.Client, logger *zap.Logger) *queueCollector {
+	return &queueCollector{
+		cfg:    cfg,
+		rdb:    rdb,
+		logger: logger,
+		queueLength: prometheus.NewDesc("queue_length",
+			"Current length of Redis queues", []string{"queue"}, nil),
+		processing: prometheus.NewDesc("admin_api_processing_jobs",
+			"Jobs held in worker processing lists, by the queue of the job each live worker is running; jobs of dead workers count as unknown",
+			[]string{"queue"}, nil),
+		workers: prometheus.NewDesc("admin_api_workers",
+			"Workers with a heartbeat (alive) or without one but still holding jobs (reap_candidate)",
+			[]string{"state"}, nil),
+		success: prometheus.NewDesc("admin_api_scrape_success",
+			"1 if the last scrape read every queue and worker from Redis, else 0", nil, nil),
+	}
+}
+
+func (c *queueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.queueLength
+	ch <- c.processing
+	ch <- c.workers
+	ch <- c.success
+}
+
+func (c *queueCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), metricsScrapeTimeout)
+	defer cancel()
+	ok := c.collectQueues(ctx, ch) && c.collectWorkers(ctx, ch)
+	success := 0.0
+	if ok {
+		success = 1
+	}
+	ch <- prometheus.MustNewConstMetric(c.success, prometheus.GaugeValue, success)
+}
+
+// queueKeys lists the queues whose length is exported.
+func (c *queueCollector) queueKeys() []string {
+	seen := map[string]bool{}
+	var keys []string
+	for _, p := range c.cfg.Worker.Priorities {
+		if key := c.cfg.Worker.Queues[p]; key != "" && !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	for _, key := range []string{c.cfg.Worker.CompletedList, c.cfg.Worker.DeadLetterList} {
+		if key != "" && !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func (c *queueCollector) collectQueues(ctx context.Context, ch chan<- prometheus.Metric) bool {
+	keys := c.queueKeys()
+	cmds := make([]*redis.IntCmd, len(keys))
+	_, _ = c.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			cmds[i] = pipe.LLen(ctx, key)
+		}
+		return nil
+	})
+	ok := true
+	for i, cmd := range cmds {
+		n, err := cmd.Result()
+		if err != nil {
+			c.logger.Warn("metrics queue length read failed", zap.String("queue", keys[i]), zap.Error(err))
+			ok = false
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(c.queueLength, prometheus.GaugeValue, float64(n), keys[i])
+	}
+	return ok
+}
+
+func (c *queueCollector) collectWorkers(ctx context.Context, ch chan<- prometheus.Metric) bool {
+	workers, err := admin.Workers(ctx, c.cfg, c.rdb)
+	if err != nil {
+		c.logger.Warn("metrics worker read failed", zap.Error(err))
+		return false
+	}
+	processing := map[string]int64{}
+	for _, key := range c.cfg.Worker.Queues {
+		processing[key] = 0
+	}
+	var alive, reap int
+	for _, w := range workers {
+		if w.Alive {
+			alive++
+		}
+		if w.ReapCandidate {
+			reap++
+		}
+		if w.Processing == 0 {
+			continue
+		}
+		queue := w.Queue
+		if _, ok := processing[queue]; !ok {
+			queue = "unknown"
+		}
+		processing[queue] += w.Processing
+	}
+	for queue, n := range processing {
+		ch <- prometheus.MustNewConstMetric(c.processing, prometheus.GaugeValue, float64(n), queue)
+	}
+	ch <- prometheus.MustNewConstMetric(c.workers, prometheus.GaugeValue, float64(alive), "alive")
+	ch <- prometheus.MustNewConstMetric(c.workers, prometheus.GaugeValue, float64(reap), "reap_candidate")
+	return true
+}
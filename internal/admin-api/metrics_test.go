@@ -0,0 +1,107 @@
+// Copyright 2025 James Ross
+package adminapi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+func setupMetricsTest(t *testing.T, cfg *Config) (http.Handler, *redis.Client) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	appCfg := &config.Config{
+		Worker: config.Worker{
+			Priorities:            []string{"high", "low"},
+			Queues:                map[string]string{"high": "jobqueue:high", "low": "jobqueue:low"},
+			CompletedList:         "jobqueue:completed",
+			DeadLetterList:        "jobqueue:dead_letter",
+			HeartbeatKeyPattern:   "jobqueue:processing:worker:%s",
+			ProcessingListPattern: "jobqueue:worker:%s:processing",
+		},
+	}
+	s, err := NewServer(cfg, appCfg, rdb, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s.applyMiddleware(s.SetupRoutes()), rdb
+}
+
+func scrape(t *testing.T, h http.Handler) (int, string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, metricsPath, nil))
+	body, _ := io.ReadAll(rec.Body)
+	return rec.Code, string(body)
+}
+
+func TestMetricsExportsQueuesWorkersAndRequests(t *testing.T) {
+	h, rdb := setupMetricsTest(t, &Config{MetricsEnabled: true})
+	ctx := context.Background()
+	rdb.LPush(ctx, "jobqueue:high", "a", "b")
+	rdb.LPush(ctx, "jobqueue:dead_letter", "x")
+	rdb.LPush(ctx, "jobqueue:worker:w1:processing", "j")
+
+	for _, path := range []string{"/api/v1/stats", "/api/v1/queues/jobqueue:high/peek", "/nope"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	code, body := scrape(t, h)
+	if code != http.StatusOK {
+		t.Fatalf("scrape returned %d: %s", code, body)
+	}
+	for _, want := range []string{
+		`queue_length{queue="jobqueue:high"} 2`,
+		`queue_length{queue="jobqueue:low"} 0`,
+		`queue_length{queue="jobqueue:dead_letter"} 1`,
+		`admin_api_processing_jobs{queue="unknown"} 1`,
+		`admin_api_workers{state="alive"} 0`,
+		`admin_api_workers{state="reap_candidate"} 1`,
+		`admin_api_scrape_success 1`,
+		`admin_api_requests_total{code="200",method="GET",route="/api/v1/stats"} 1`,
+		`admin_api_request_duration_seconds_count{method="GET",route="/api/v1/stats"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("missing %s", want)
+		}
+	}
+	if strings.Contains(body, `route="/nope"`) || strings.Contains(body, `route="/api/v1/queues/jobqueue:high/peek"`) {
+		t.Error("route labels should be registered patterns, not raw paths")
+	}
+}
+
+func TestMetricsAuthAndDisable(t *testing.T) {
+	authed := &Config{MetricsEnabled: true, RequireAuth: true, DenyByDefault: true, JWTSecret: "s"}
+	h, _ := setupMetricsTest(t, authed)
+	if code, _ := scrape(t, h); code != http.StatusUnauthorized {
+		t.Fatalf("expected metrics behind auth, got %d", code)
+	}
+
+	public := *authed
+	public.MetricsPublic = true
+	h, _ = setupMetricsTest(t, &public)
+	if code, _ := scrape(t, h); code != http.StatusOK {
+		t.Fatalf("expected public metrics, got %d", code)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/stats", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("public metrics must not open other routes, got %d", rec.Code)
+	}
+
+	h, _ = setupMetricsTest(t, &Config{})
+	if code, body := scrape(t, h); strings.Contains(body, "admin_api_requests_total") {
+		t.Fatalf("expected no metrics when disabled, got %d", code)
+	}
+}
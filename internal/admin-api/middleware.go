@@ -7,6 +7,7 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -26,31 +27,34 @@ const (
 	contextKeyScopes    contextKey = "scopes"
 )
 
-// AuthMiddleware validates JWT tokens
+// AuthMiddleware validates HS256 bearer tokens signed with secret. See
+// AuthenticatorMiddleware.
 func AuthMiddleware(secret string, denyByDefault bool, logger *zap.Logger) func(http.Handler) http.Handler {
+	return AuthenticatorMiddleware(&bearerAuthenticator{secret: secret}, denyByDefault, logger)
+}
+
+// AuthenticatorMiddleware authenticates requests with auth and puts the
+// caller's claims and scopes in the request context. The generated API
+// description and its Swagger UI are served without credentials so tooling
+// can fetch them.
+func AuthenticatorMiddleware(auth Authenticator, denyByDefault bool, logger *zap.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if !denyByDefault {
+			if !denyByDefault || isPublicDocsPath(r.URL.Path) {
 				next.ServeHTTP(w, r)
 				return
 			}
 
-			authHeader := r.Header.Get("Authorization")
-			if authHeader == "" {
-				writeError(w, http.StatusUnauthorized, "AUTH_MISSING", "Authorization header required")
-				return
-			}
-
-			parts := strings.SplitN(authHeader, " ", 2)
-			if len(parts) != 2 || parts[0] != "Bearer" {
-				writeError(w, http.StatusUnauthorized, "AUTH_INVALID", "Invalid authorization format")
-				return
-			}
-
-			claims, err := validateJWT(parts[1], secret)
+			claims, err := auth.Authenticate(r)
 			if err != nil {
-				logger.Warn("JWT validation failed", zap.Error(err))
-				writeError(w, http.StatusUnauthorized, "AUTH_INVALID", "Invalid or expired token")
+				var authErr *AuthError
+				if !errors.As(err, &authErr) {
+					authErr = invalidToken(err)
+				}
+				if authErr.Err != nil {
+					logger.Warn("Authentication failed", zap.Error(authErr.Err))
+				}
+				writeError(w, http.StatusUnauthorized, authErr.Code, authErr.Message)
 				return
 			}
 
@@ -149,25 +153,61 @@ func AuditMiddleware(auditLog *AuditLogger, logger *zap.Logger) func(http.Handle
 	}
 }
 
-// CORSMiddleware handles CORS headers
+// CORSOptions configures CORSMiddlewareWithOptions. Empty methods and
+// headers take the defaults of DefaultConfig.
+type CORSOptions struct {
+	AllowOrigins     []string
+	AllowMethods     []string
+	AllowHeaders     []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// CORSMiddleware handles CORS headers for allowedOrigins with the default
+// methods and headers
 func CORSMiddleware(allowedOrigins []string) func(http.Handler) http.Handler {
+	return CORSMiddlewareWithOptions(CORSOptions{AllowOrigins: allowedOrigins})
+}
+
+// CORSMiddlewareWithOptions sets CORS headers for allowed origins and
+// answers preflight (OPTIONS) requests itself, so it must wrap the auth
+// middleware: browsers send preflights without credentials.
+func CORSMiddlewareWithOptions(opts CORSOptions) func(http.Handler) http.Handler {
+	defaults := DefaultConfig()
+	if len(opts.AllowMethods) == 0 {
+		opts.AllowMethods = defaults.CORSAllowMethods
+	}
+	if len(opts.AllowHeaders) == 0 {
+		opts.AllowHeaders = defaults.CORSAllowHeaders
+	}
+	if opts.MaxAge <= 0 {
+		opts.MaxAge = defaults.CORSMaxAge
+	}
+	methods := strings.Join(opts.AllowMethods, ", ")
+	headers := strings.Join(opts.AllowHeaders, ", ")
+	maxAge := fmt.Sprintf("%d", int(opts.MaxAge.Seconds()))
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			origin := r.Header.Get("Origin")
 			allowed := false
 
-			for _, ao := range allowedOrigins {
+			for _, ao := range opts.AllowOrigins {
 				if ao == "*" || ao == origin {
 					allowed = true
 					break
 				}
 			}
 
-			if allowed {
+			w.Header().Add("Vary", "Origin")
+			if allowed && origin != "" {
 				w.Header().Set("Access-Control-Allow-Origin", origin)
-				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
-				w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
-				w.Header().Set("Access-Control-Max-Age", "3600")
+				w.Header().Set("Access-Control-Allow-Methods", methods)
+				w.Header().Set("Access-Control-Allow-Headers", headers)
+				w.Header().Set("Access-Control-Max-Age", maxAge)
+				if opts.AllowCredentials {
+					w.Header().Set("Access-Control-Allow-Credentials", "true")
+				}
 			}
 
 			if r.Method == "OPTIONS" {
@@ -264,6 +304,9 @@ func isDestructiveOperation(method, path string) bool {
 	destructivePaths := []string{
 		"/api/v1/queues/dlq",
 		"/api/v1/queues/all",
+		"/api/v1/dlq/requeue",
+		"/api/v1/dlq/purge",
+		"/api/v1/jobs/bulk",
 		"/api/v1/bench",
 	}
 
@@ -272,6 +315,10 @@ func isDestructiveOperation(method, path string) bool {
 			return true
 		}
 	}
+	// deleting a queue definition can drop the queue's jobs
+	if method == "DELETE" && strings.HasPrefix(path, "/api/v1/queues/") {
+		return true
+	}
 
 	return false
 }
@@ -335,3 +382,17 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.statusCode = code
 	rw.ResponseWriter.WriteHeader(code)
 }
+
+// Unwrap lets http.ResponseController reach the underlying writer, e.g. to
+// hijack the connection for WebSocket upgrades.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// allowsQueryToken reports whether r may carry its bearer token in the
+// access_token query parameter instead of the Authorization header. Only
+// the SSE endpoint allows it, since EventSource cannot set headers; query
+// strings end up in proxy logs, so nothing else should.
+func allowsQueryToken(r *http.Request) bool {
+	return r.Method == http.MethodGet && r.URL.Path == "/api/v1/events/dlq"
+}
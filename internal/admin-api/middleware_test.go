@@ -232,6 +232,9 @@ func TestIsDestructiveOperation(t *testing.T) {
 		{"DELETE", "/api/v1/queues/dlq", true},
 		{"DELETE", "/api/v1/queues/all", true},
 		{"POST", "/api/v1/bench", true},
+		{"POST", "/api/v1/dlq/requeue", true},
+		{"POST", "/api/v1/dlq/purge", true},
+		{"GET", "/api/v1/dlq", false},
 		{"POST", "/api/v1/stats", false},
 		{"DELETE", "/api/v1/other", false},
 	}
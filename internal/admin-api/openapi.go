@@ -27,6 +27,8 @@ tags:
     description: Queue management operations
   - name: dlq
     description: Dead Letter Queue listing and remediation
+  - name: jobs
+    description: Bulk operations on individual jobs
   - name: workers
     description: Worker fleet information
   - name: benchmark
@@ -75,6 +77,37 @@ paths:
         '500':
           $ref: '#/components/responses/InternalError'
 
+  /stream/stats:
+    get:
+      tags:
+        - stats
+      summary: Stream queue statistics over WebSocket
+      description: |
+        Upgrades to a WebSocket and pushes StatsStreamMessage frames: a full
+        snapshot on connect and every stream_interval, plus deltas when a
+        counter moves by at least stream_delta_threshold. The server pings
+        periodically and drops clients that stop answering.
+      operationId: streamStats
+      responses:
+        '101':
+          description: Switching protocols; frames follow the StatsStreamMessage schema
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/StatsStreamMessage'
+        '401':
+          $ref: '#/components/responses/Unauthorized'
+        '403':
+          description: Origin not allowed
+        '429':
+          $ref: '#/components/responses/RateLimited'
+        '503':
+          description: Stream connection limit reached
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/ErrorResponse'
+
   /queues/{queue}/peek:
     get:
       tags:
@@ -97,6 +130,21 @@ paths:
             minimum: 1
             maximum: 100
             default: 10
+        - name: filter
+          in: query
+          description: JSONPath expression selecting matching items (e.g. $.user_id == "123"); non-JSON items are skipped
+          schema:
+            type: string
+        - name: project
+          in: query
+          description: Comma-separated dotted field paths to include in each returned item
+          schema:
+            type: string
+        - name: cursor
+          in: query
+          description: next_cursor from the previous page; pages continue towards the head of the queue
+          schema:
+            type: string
       responses:
         '200':
           description: Queue items retrieved successfully
@@ -292,6 +340,63 @@ components:
           format: date-time
           description: When the stats were collected
 
+    StatsStreamMessage:
+      type: object
+      required:
+        - type
+        - timestamp
+      properties:
+        type:
+          type: string
+          enum: [snapshot, delta]
+        stats:
+          $ref: '#/components/schemas/StatsResponse'
+        queues:
+          type: object
+          additionalProperties:
+            type: integer
+          description: New lengths of queues that changed (delta only; removed keys report 0)
+        processing_lists:
+          type: object
+          additionalProperties:
+            type: integer
+          description: New lengths of processing lists that changed (delta only)
+        heartbeats:
+          type: integer
+          description: New heartbeat count when it changed (delta only)
+        timestamp:
+          type: string
+          format: date-time
+
+    DLQEvent:
+      type: object
+      required:
+        - type
+        - length
+        - timestamp
+      properties:
+        id:
+          type: integer
+          description: Increasing event ID, also sent as the SSE id (absent on status)
+        type:
+          type: string
+          enum: [threshold_crossed, threshold_cleared, growth, status]
+        length:
+          type: integer
+          description: DLQ length when the event was raised
+        threshold:
+          type: integer
+          description: Threshold crossed or cleared
+        growth:
+          type: integer
+          description: Growth within window (growth only)
+        window:
+          type: string
+          description: Growth window, e.g. 5m0s (growth only)
+        timestamp:
+          type: string
+          format: date-time
+
     StatsKeysResponse:
       type: object
       required:
@@ -344,6 +449,15 @@ components:
         count:
           type: integer
           description: Number of items returned
+        scanned:
+          type: integer
+          description: Number of items inspected when a filter is applied
+        next_cursor:
+          type: string
+          description: Opaque cursor for the next page; absent on the last page
+        has_more:
+          type: boolean
+          description: Whether more items follow this page
         timestamp:
           type: string
           format: date-time
@@ -463,7 +577,7 @@ components:
           required: false
           schema:
             type: string
-          description: Opaque cursor for pagination
+          description: Opaque cursor for pagination (next_cursor from the previous page)
         - name: limit
           in: query
           required: false
@@ -480,6 +594,8 @@ components:
             application/json:
               schema:
                 $ref: '#/components/schemas/DLQListResponse'
+        '400':
+          $ref: '#/components/responses/BadRequest'
         '401':
           $ref: '#/components/responses/Unauthorized'
         '429':
@@ -487,6 +603,48 @@ components:
         '500':
           $ref: '#/components/responses/InternalError'
 
+  /events/dlq:
+    get:
+      tags:
+        - dlq
+      summary: Stream DLQ alerts as Server-Sent Events
+      description: |
+        Streams DLQEvent messages as text/event-stream when the DLQ length
+        crosses or falls back below dlq_alert_thresholds, or grows by more
+        than dlq_alert_growth within dlq_alert_window. Reconnecting clients
+        send Last-Event-ID (or last_event_id) to receive the retained events
+        they missed. Each connection also gets a status event with the
+        current length.
+      operationId: streamDLQEvents
+      parameters:
+        - name: access_token
+          in: query
+          description: Bearer token, for EventSource clients that cannot set headers
+          schema:
+            type: string
+        - name: last_event_id
+          in: query
+          description: Replay retained events after this ID
+          schema:
+            type: integer
+      responses:
+        '200':
+          description: Event stream; each data line is a DLQEvent
+          content:
+            text/event-stream:
+              schema:
+                $ref: '#/components/schemas/DLQEvent'
+        '401':
+          $ref: '#/components/responses/Unauthorized'
+        '429':
+          $ref: '#/components/responses/RateLimited'
+        '503':
+          description: Stream connection limit reached
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/ErrorResponse'
+
   /dlq/requeue:
     post:
       tags:
@@ -543,19 +701,42 @@ components:
         '500':
           $ref: '#/components/responses/InternalError'
 
+  /jobs/bulk:
+    post:
+      tags:
+        - jobs
+      summary: Requeue, delete or archive many jobs by ID or list member
+      description: Items are processed independently; the response reports each one and is 200 even when some failed.
+      operationId: bulkJobs
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/BulkJobsRequest'
+      responses:
+        '200':
+          description: Per-item results and counts
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/BulkJobsResponse'
+        '400':
+          $ref: '#/components/responses/BadRequest'
+        '401':
+          $ref: '#/components/responses/Unauthorized'
+        '429':
+          $ref: '#/components/responses/RateLimited'
+        '500':
+          $ref: '#/components/responses/InternalError'
+
   /workers:
     get:
       tags:
         - workers
       summary: List workers
-      description: Returns summary of worker fleet
+      description: Returns each worker with a heartbeat or processing list, flagging dead workers that still hold jobs
       operationId: listWorkers
-      parameters:
-        - name: ns
-          in: query
-          required: false
-          schema:
-            type: string
       responses:
         '200':
           description: Workers list
@@ -601,6 +782,8 @@ components:
             $ref: '#/components/schemas/DLQItem'
         next_cursor:
           type: string
+        has_more:
+          type: boolean
         count:
           type: integer
         timestamp:
@@ -651,6 +834,62 @@ components:
           type: string
           format: date-time
 
+    BulkJobsRequest:
+      type: object
+      required: [action, items]
+      properties:
+        action:
+          type: string
+          enum: [requeue, delete, archive]
+        queue:
+          type: string
+          description: Source queue alias or key; defaults to the dead letter list
+        dest_queue:
+          type: string
+          description: Requeue target; defaults to each job's priority queue
+        items:
+          type: array
+          description: Job IDs or exact list members
+          items:
+            type: string
+
+    BulkJobResult:
+      type: object
+      required: [target, success]
+      properties:
+        target:
+          type: string
+        job_id:
+          type: string
+        success:
+          type: boolean
+        dest:
+          type: string
+        error:
+          type: string
+
+    BulkJobsResponse:
+      type: object
+      required: [action, queue, total, succeeded, failed, results, timestamp]
+      properties:
+        action:
+          type: string
+        queue:
+          type: string
+        total:
+          type: integer
+        succeeded:
+          type: integer
+        failed:
+          type: integer
+        results:
+          type: array
+          items:
+            $ref: '#/components/schemas/BulkJobResult'
+        timestamp:
+          type: string
+          format: date-time
+
     WorkerInfo:
       type: object
       required: [id, last_heartbeat]
@@ -671,6 +910,15 @@ components:
           type: string
         host:
           type: string
+        alive:
+          type: boolean
+          description: The worker's heartbeat key exists
+        processing:
+          type: integer
+          description: Items in the worker's processing list
+        reap_candidate:
+          type: boolean
+          description: No heartbeat but a non-empty processing list; the reaper will requeue it
 
     WorkersResponse:
       type: object
@@ -0,0 +1,370 @@
+// Copyright 2025 James Ross
+package adminapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// routeDoc describes one API operation for the generated OpenAPI document.
+// Request and Response are zero values of the Go types the handler decodes
+// and encodes; their schemas are derived from json and validate tags.
+type routeDoc struct {
+	Method      string
+	Path        string
+	OperationID string
+	Summary     string
+	Tag         string
+	Params      []paramDoc
+	Request     interface{}
+	Response    interface{}
+	Destructive bool
+}
+
+// paramDoc describes a path or query parameter.
+type paramDoc struct {
+	Name        string
+	In          string
+	Description string
+	Type        string
+	Required    bool
+}
+
+// routeRegistry records route docs as handlers are registered, so the
+// generated spec cannot list a route the mux does not serve.
+type routeRegistry struct {
+	mux    *http.ServeMux
+	routes []routeDoc
+}
+
+// handle registers fn for doc.Method on doc.Path and records the doc.
+func (rr *routeRegistry) handle(doc routeDoc, fn http.HandlerFunc) {
+	rr.mux.HandleFunc(doc.Path, methodHandler(doc.Method, fn))
+	rr.document(doc)
+}
+
+// document records a route whose dispatch is done by hand, such as the
+// per-queue routes below /api/v1/queues/.
+func (rr *routeRegistry) document(doc routeDoc) {
+	rr.routes = append(rr.routes, doc)
+}
+
+// buildOpenAPISpec renders routes as an OpenAPI 3 document.
+func buildOpenAPISpec(routes []routeDoc) map[string]interface{} {
+	sg := &schemaGen{schemas: map[string]interface{}{}}
+	sg.ref(reflect.TypeOf(ErrorResponse{}))
+
+	paths := map[string]interface{}{}
+	tagSet := map[string]bool{}
+	for _, rd := range routes {
+		item, ok := paths[rd.Path].(map[string]interface{})
+		if !ok {
+			item = map[string]interface{}{}
+			paths[rd.Path] = item
+		}
+		item[strings.ToLower(rd.Method)] = sg.operation(rd)
+		if rd.Tag != "" {
+			tagSet[rd.Tag] = true
+		}
+	}
+
+	tags := make([]interface{}, 0, len(tagSet))
+	for _, name := range sortedKeys(tagSet) {
+		tags = append(tags, map[string]interface{}{"name": name})
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       "Redis Work Queue Admin API",
+			"description": "Secure admin API for managing Redis work queues. Generated from the route registrations.",
+			"version":     "1.0.0",
+		},
+		"security": []interface{}{map[string]interface{}{"bearerAuth": []string{}}},
+		"tags":     tags,
+		"paths":    paths,
+		"components": map[string]interface{}{
+			"securitySchemes": map[string]interface{}{
+				"bearerAuth": map[string]interface{}{
+					"type":         "http",
+					"scheme":       "bearer",
+					"bearerFormat": "JWT",
+				},
+			},
+			"schemas": sg.schemas,
+		},
+	}
+}
+
+// schemaGen derives JSON schemas from Go types, collecting named structs
+// under components/schemas.
+type schemaGen struct {
+	schemas map[string]interface{}
+}
+
+func (sg *schemaGen) operation(rd routeDoc) map[string]interface{} {
+	op := map[string]interface{}{
+		"operationId": rd.OperationID,
+		"summary":     rd.Summary,
+	}
+	if rd.Tag != "" {
+		op["tags"] = []string{rd.Tag}
+	}
+
+	idempotent := isDestructiveOperation(rd.Method, rd.Path)
+	routeParams := rd.Params
+	if idempotent {
+		routeParams = append(append([]paramDoc(nil), rd.Params...), paramDoc{
+			Name: IdempotencyKeyHeader, In: "header",
+			Description: "Replays the first response for retries with the same key, per token and endpoint",
+		})
+	}
+	if len(routeParams) > 0 {
+		params := make([]interface{}, 0, len(routeParams))
+		for _, p := range routeParams {
+			typ := p.Type
+			if typ == "" {
+				typ = "string"
+			}
+			param := map[string]interface{}{
+				"name":     p.Name,
+				"in":       p.In,
+				"required": p.Required || p.In == "path",
+				"schema":   map[string]interface{}{"type": typ},
+			}
+			if p.Description != "" {
+				param["description"] = p.Description
+			}
+			params = append(params, param)
+		}
+		op["parameters"] = params
+	}
+
+	responses := map[string]interface{}{
+		"401": sg.errorResponse("Missing or invalid token"),
+		"429": sg.errorResponse("Quota exceeded"),
+		"500": sg.errorResponse("Internal error"),
+	}
+	if rd.Request != nil {
+		op["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": sg.ref(reflect.TypeOf(rd.Request))},
+			},
+		}
+	}
+	if rd.Request != nil || len(routeParams) > 0 {
+		responses["400"] = sg.errorResponse("Invalid request")
+	}
+	if rd.Destructive {
+		responses["403"] = sg.errorResponse("Token lacks a destructive scope")
+	}
+	if idempotent {
+		responses["409"] = sg.errorResponse("A request with this Idempotency-Key is still running")
+		responses["422"] = sg.errorResponse("Idempotency-Key reused with a different request")
+	}
+	ok := map[string]interface{}{"description": "Success"}
+	if rd.Response != nil {
+		ok["content"] = map[string]interface{}{
+			"application/json": map[string]interface{}{"schema": sg.ref(reflect.TypeOf(rd.Response))},
+		}
+	}
+	responses["200"] = ok
+	op["responses"] = responses
+	return op
+}
+
+func (sg *schemaGen) errorResponse(description string) map[string]interface{} {
+	return map[string]interface{}{
+		"description": description,
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{"schema": sg.ref(reflect.TypeOf(ErrorResponse{}))},
+		},
+	}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// ref returns the schema for t, registering named structs as components
+// and referring to them.
+func (sg *schemaGen) ref(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t == durationType:
+		return map[string]interface{}{"type": "integer", "format": "int64", "description": "Duration in nanoseconds"}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if t.Name() == "" {
+			return sg.object(t)
+		}
+		if _, ok := sg.schemas[t.Name()]; !ok {
+			// Reserve the name first so self-referencing types terminate.
+			sg.schemas[t.Name()] = map[string]interface{}{}
+			sg.schemas[t.Name()] = sg.object(t)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + t.Name()}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": sg.ref(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": sg.ref(t.Elem())}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	default:
+		// interface{} and anything else accepts any JSON value
+		return map[string]interface{}{}
+	}
+}
+
+// object builds an object schema from the exported fields of t. A struct
+// with validate tags is a request body, and only validate:"required" fields
+// are required; otherwise every field without omitempty is. min, max and
+// oneof become the matching schema constraints.
+func (sg *schemaGen) object(t reflect.Type) map[string]interface{} {
+	validated := false
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("validate") != "" {
+			validated = true
+		}
+	}
+
+	props := map[string]interface{}{}
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		schema := sg.ref(f.Type)
+		rules := f.Tag.Get("validate")
+		if rules != "" {
+			if _, isRef := schema["$ref"]; !isRef {
+				applyValidateRules(schema, rules)
+			}
+		}
+		props[name] = schema
+
+		if hasRule(rules, "required") || (!validated && !strings.Contains(opts, "omitempty")) {
+			required = append(required, name)
+		}
+	}
+
+	obj := map[string]interface{}{"type": "object", "properties": props}
+	if len(required) > 0 {
+		obj["required"] = required
+	}
+	return obj
+}
+
+// applyValidateRules maps go-playground/validator style rules onto schema.
+func applyValidateRules(schema map[string]interface{}, rules string) {
+	for _, rule := range strings.Split(rules, ",") {
+		key, val, _ := strings.Cut(rule, "=")
+		switch key {
+		case "min", "max":
+			n, err := strconv.Atoi(val)
+			if err != nil {
+				continue
+			}
+			bound := map[string]string{"min": "minimum", "max": "maximum"}[key]
+			switch schema["type"] {
+			case "string":
+				bound = map[string]string{"min": "minLength", "max": "maxLength"}[key]
+			case "array":
+				bound = map[string]string{"min": "minItems", "max": "maxItems"}[key]
+			}
+			schema[bound] = n
+		case "oneof":
+			schema["enum"] = strings.Fields(val)
+		}
+	}
+}
+
+func hasRule(rules, name string) bool {
+	for _, rule := range strings.Split(rules, ",") {
+		if rule == name {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// isPublicDocsPath reports whether path is the generated spec or its UI.
+func isPublicDocsPath(path string) bool {
+	return path == "/openapi.json" || path == "/docs"
+}
+
+// swaggerUIPage renders the generated spec with Swagger UI from a CDN.
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Redis Work Queue Admin API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
+
+// serveOpenAPI registers /openapi.json and /docs for the routes recorded
+// so far. The document is marshaled once; it only changes with the code.
+func (rr *routeRegistry) serveOpenAPI() {
+	spec, err := json.MarshalIndent(buildOpenAPISpec(rr.routes), "", "  ")
+	if err != nil {
+		panic("adminapi: marshal OpenAPI spec: " + err.Error())
+	}
+	rr.mux.HandleFunc("/openapi.json", methodHandler("GET", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(spec)
+	}))
+	rr.mux.HandleFunc("/docs", methodHandler("GET", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(swaggerUIPage))
+	}))
+}
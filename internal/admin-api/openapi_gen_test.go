@@ -0,0 +1,116 @@
+// Copyright 2025 James Ross
+package adminapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"go.uber.org/zap"
+)
+
+func fetchGeneratedSpec(t *testing.T, h http.Handler) map[string]interface{} {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 for /openapi.json, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("expected application/json, got %s", ct)
+	}
+	var spec map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &spec); err != nil {
+		t.Fatalf("spec is not valid JSON: %v", err)
+	}
+	return spec
+}
+
+func TestGeneratedOpenAPISpecCoversRoutes(t *testing.T) {
+	server, err := NewServer(DefaultConfig(), &config.Config{}, nil, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := fetchGeneratedSpec(t, server.SetupRoutes())
+
+	if spec["openapi"] != "3.0.3" {
+		t.Errorf("expected openapi 3.0.3, got %v", spec["openapi"])
+	}
+	paths := spec["paths"].(map[string]interface{})
+	for path, method := range map[string]string{
+		"/api/v1/stats":               "get",
+		"/api/v1/queues/{queue}/peek": "get",
+		"/api/v1/queues/dlq":          "delete",
+		"/api/v1/queues/all":          "delete",
+		"/api/v1/dlq/requeue":         "post",
+		"/api/v1/dlq/purge":           "post",
+		"/api/v1/bench":               "post",
+	} {
+		item, ok := paths[path].(map[string]interface{})
+		if !ok {
+			t.Errorf("expected path %s in spec", path)
+			continue
+		}
+		if _, ok := item[method]; !ok {
+			t.Errorf("expected %s %s in spec", method, path)
+		}
+	}
+
+	requeue := paths["/api/v1/dlq/requeue"].(map[string]interface{})["post"].(map[string]interface{})
+	if _, ok := requeue["responses"].(map[string]interface{})["403"]; !ok {
+		t.Error("expected destructive requeue to document 403")
+	}
+
+	components := spec["components"].(map[string]interface{})
+	if _, ok := components["securitySchemes"].(map[string]interface{})["bearerAuth"]; !ok {
+		t.Error("expected bearerAuth security scheme")
+	}
+	schemas := components["schemas"].(map[string]interface{})
+
+	purge := schemas["PurgeRequest"].(map[string]interface{})
+	if got := purge["required"]; len(got.([]interface{})) != 2 {
+		t.Errorf("expected confirmation and reason to be required, got %v", got)
+	}
+	reason := purge["properties"].(map[string]interface{})["reason"].(map[string]interface{})
+	if reason["minLength"] != float64(3) || reason["maxLength"] != float64(500) {
+		t.Errorf("expected reason length bounds from validate tags, got %v", reason)
+	}
+
+	bench := schemas["BenchRequest"].(map[string]interface{})["properties"].(map[string]interface{})
+	priority := bench["priority"].(map[string]interface{})
+	if enum, _ := priority["enum"].([]interface{}); len(enum) != 2 {
+		t.Errorf("expected priority enum from oneof, got %v", priority)
+	}
+
+	stats := schemas["StatsResponse"].(map[string]interface{})["properties"].(map[string]interface{})
+	if ts := stats["timestamp"].(map[string]interface{}); ts["format"] != "date-time" {
+		t.Errorf("expected timestamp as date-time, got %v", ts)
+	}
+}
+
+func TestGeneratedOpenAPIDocsArePublic(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.JWTSecret = "test-secret"
+	server, err := NewServer(cfg, &config.Config{}, nil, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := AuthMiddleware(cfg.JWTSecret, true, zap.NewNop())(server.SetupRoutes())
+
+	fetchGeneratedSpec(t, h)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/docs", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "/openapi.json") {
+		t.Errorf("expected Swagger UI pointing at /openapi.json, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/stats", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected API routes to still require a token, got %d", w.Code)
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2025 James Ross
+package adminapi
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	quotaClassRead        = "read"
+	quotaClassDestructive = "destructive"
+)
+
+// QuotaMiddleware enforces per-token request quotas with fixed-window
+// counters in Redis, so every replica draws on the same budget. Tokens are
+// identified by their subject, or by client IP when there are no claims.
+// Destructive endpoints count against DestructiveQuota and everything else
+// against ReadQuota. A request over quota gets 429 with Retry-After set to
+// the seconds left in the window.
+//
+// If Redis is unavailable, reads are let through and destructive calls are
+// refused with 503: a limiter outage must not open the door to a runaway
+// purge.
+func QuotaMiddleware(rdb *redis.Client, cfg *Config, logger *zap.Logger) func(http.Handler) http.Handler {
+	prefix := cfg.QuotaKeyPrefix
+	if prefix == "" {
+		prefix = "admin-api:quota"
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var subject string
+			if claims, ok := r.Context().Value(contextKeyClaims).(*Claims); ok {
+				subject = claims.Subject
+			} else {
+				subject = getClientIP(r)
+			}
+
+			class := quotaClassRead
+			if isDestructiveOperation(r.Method, r.URL.Path) {
+				class = quotaClassDestructive
+			}
+			quota := cfg.quotaFor(subject, class)
+			if quota.Requests <= 0 || quota.Window <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			now := time.Now()
+			windowStart := now.Truncate(quota.Window)
+			reset := windowStart.Add(quota.Window)
+			key := fmt.Sprintf("%s:%s:%s:%d", prefix, class, subject, windowStart.Unix())
+
+			pipe := rdb.TxPipeline()
+			incr := pipe.Incr(r.Context(), key)
+			pipe.PExpire(r.Context(), key, quota.Window)
+			if _, err := pipe.Exec(r.Context()); err != nil {
+				logger.Warn("Quota check failed",
+					zap.String("class", class),
+					zap.String("subject", subject),
+					zap.Error(err))
+				if class == quotaClassDestructive {
+					writeError(w, http.StatusServiceUnavailable, "QUOTA_UNAVAILABLE", "Quota service unavailable")
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			count := incr.Val()
+			remaining := int64(quota.Requests) - count
+			if remaining < 0 {
+				remaining = 0
+			}
+			w.Header().Set("X-RateLimit-Limit", fmt.Sprintf("%d", quota.Requests))
+			w.Header().Set("X-RateLimit-Remaining", fmt.Sprintf("%d", remaining))
+			w.Header().Set("X-RateLimit-Reset", fmt.Sprintf("%d", reset.Unix()))
+
+			if count > int64(quota.Requests) {
+				retryAfter := int(math.Ceil(reset.Sub(now).Seconds()))
+				if retryAfter < 1 {
+					retryAfter = 1
+				}
+				w.Header().Set("Retry-After", fmt.Sprintf("%d", retryAfter))
+				logger.Warn("Quota exceeded",
+					zap.String("class", class),
+					zap.String("subject", subject),
+					zap.String("path", r.URL.Path))
+				writeError(w, http.StatusTooManyRequests, "RATE_LIMIT", fmt.Sprintf("%s quota exceeded", class))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// DestructiveScopeMiddleware refuses purge and requeue calls from tokens
+// that hold none of scopes. Benchmarks are destructive for quota purposes
+// but only need a valid token. Requests without claims pass, since they can
+// only get here when authentication is disabled.
+func DestructiveScopeMiddleware(scopes []string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := r.Context().Value(contextKeyClaims).(*Claims)
+			if !ok || !isDestructiveOperation(r.Method, r.URL.Path) || strings.HasPrefix(r.URL.Path, "/api/v1/bench") {
+				next.ServeHTTP(w, r)
+				return
+			}
+			for _, have := range claims.Scopes {
+				for _, want := range scopes {
+					if have == want {
+						next.ServeHTTP(w, r)
+						return
+					}
+				}
+			}
+			writeError(w, http.StatusForbidden, "INSUFFICIENT_SCOPE",
+				fmt.Sprintf("Destructive operations require one of the scopes: %s", strings.Join(scopes, ", ")))
+		})
+	}
+}
+
+// quotaFor returns the quota for subject in class, applying any per-token
+// override. An override of -1 requests makes the token unlimited.
+func (c *Config) quotaFor(subject, class string) Quota {
+	q := c.ReadQuota
+	if class == quotaClassDestructive {
+		q = c.DestructiveQuota
+	}
+	o, ok := c.TokenQuotas[subject]
+	if !ok {
+		// viper lowercases map keys read from YAML
+		o, ok = c.TokenQuotas[strings.ToLower(subject)]
+	}
+	if ok {
+		override := o.Read
+		if class == quotaClassDestructive {
+			override = o.Destructive
+		}
+		if override.Requests != 0 {
+			q.Requests = override.Requests
+		}
+		if override.Window != 0 {
+			q.Window = override.Window
+		}
+	}
+	return q
+}
@@ -0,0 +1,122 @@
+// Copyright 2025 James Ross
+package adminapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	rbacandtokens "github.com/flyingrobots/go-redis-work-queue/internal/rbac-and-tokens"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+func newQuotaHandler(t *testing.T, cfg *Config) (http.Handler, *miniredis.Miniredis) {
+	t.Helper()
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(mr.Close)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler = QuotaMiddleware(rdb, cfg, zap.NewNop())(handler)
+	handler = DestructiveScopeMiddleware(cfg.DestructiveScopes)(handler)
+	handler = AuthMiddleware(cfg.JWTSecret, true, zap.NewNop())(handler)
+	return handler, mr
+}
+
+func quotaRequest(h http.Handler, method, path, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestQuotaSeparatesReadAndDestructive(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.JWTSecret = "test-secret"
+	cfg.ReadQuota = Quota{Requests: 3, Window: time.Hour}
+	cfg.DestructiveQuota = Quota{Requests: 1, Window: time.Hour}
+	h, _ := newQuotaHandler(t, cfg)
+	token := mustMakeScopedToken(t, cfg.JWTSecret, []string{string(rbacandtokens.PermQueueDelete)})
+
+	if w := quotaRequest(h, "POST", "/api/v1/dlq/purge", token); w.Code != http.StatusOK {
+		t.Fatalf("expected first purge to pass, got %d", w.Code)
+	}
+	w := quotaRequest(h, "POST", "/api/v1/dlq/purge", token)
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 for second purge, got %d", w.Code)
+	}
+	if s, err := strconv.Atoi(w.Header().Get("Retry-After")); err != nil || s < 1 || s > 3600 {
+		t.Fatalf("expected Retry-After within the window, got %q", w.Header().Get("Retry-After"))
+	}
+
+	// Reads have their own budget.
+	for i := 0; i < 3; i++ {
+		if w := quotaRequest(h, "GET", "/api/v1/stats", token); w.Code != http.StatusOK {
+			t.Fatalf("read %d: expected 200, got %d", i, w.Code)
+		}
+	}
+	if w := quotaRequest(h, "GET", "/api/v1/stats", token); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 once the read quota is used, got %d", w.Code)
+	}
+}
+
+func TestQuotaTokenOverrides(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.JWTSecret = "test-secret"
+	cfg.ReadQuota = Quota{Requests: 1, Window: time.Hour}
+	cfg.TokenQuotas = map[string]TokenQuota{"test@example.com": {Read: Quota{Requests: -1}}}
+	h, _ := newQuotaHandler(t, cfg)
+	token := mustMakeScopedToken(t, cfg.JWTSecret, nil)
+
+	for i := 0; i < 5; i++ {
+		if w := quotaRequest(h, "GET", "/api/v1/stats", token); w.Code != http.StatusOK {
+			t.Fatalf("read %d: expected unlimited override, got %d", i, w.Code)
+		}
+	}
+}
+
+func TestDestructiveScopeRequired(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.JWTSecret = "test-secret"
+	h, mr := newQuotaHandler(t, cfg)
+	token := mustMakeScopedToken(t, cfg.JWTSecret, []string{string(rbacandtokens.PermQueueRead)})
+
+	if w := quotaRequest(h, "DELETE", "/api/v1/queues/all", token); w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 without a destructive scope, got %d", w.Code)
+	}
+	if keys := mr.Keys(); len(keys) != 0 {
+		t.Fatalf("expected refused calls not to use quota, got keys %v", keys)
+	}
+	if w := quotaRequest(h, "POST", "/api/v1/bench", token); w.Code != http.StatusOK {
+		t.Fatalf("expected bench to need only a valid token, got %d", w.Code)
+	}
+	if w := quotaRequest(h, "GET", "/api/v1/stats", token); w.Code != http.StatusOK {
+		t.Fatalf("expected reads to pass, got %d", w.Code)
+	}
+}
+
+func TestQuotaFailsClosedForDestructiveWhenRedisIsDown(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.JWTSecret = "test-secret"
+	h, mr := newQuotaHandler(t, cfg)
+	token := mustMakeScopedToken(t, cfg.JWTSecret, []string{string(rbacandtokens.PermAdminAll)})
+	mr.Close()
+
+	if w := quotaRequest(h, "GET", "/api/v1/stats", token); w.Code != http.StatusOK {
+		t.Fatalf("expected reads to pass without Redis, got %d", w.Code)
+	}
+	if w := quotaRequest(h, "DELETE", "/api/v1/queues/dlq", token); w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 for destructive calls without Redis, got %d", w.Code)
+	}
+}
@@ -20,6 +20,8 @@ type Server struct {
 	logger   *zap.Logger
 	server   *http.Server
 	auditLog *AuditLogger
+	auth     Authenticator
+	metrics  *apiMetrics // nil unless MetricsEnabled
 }
 
 // NewServer creates a new admin API server
@@ -27,6 +29,11 @@ func NewServer(cfg *Config, appCfg *config.Config, rdb *redis.Client, logger *za
 	var auditLog *AuditLogger
 	var err error
 
+	auth, err := NewAuthenticator(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure auth: %w", err)
+	}
+
 	if cfg.AuditEnabled {
 		auditLog, err = NewAuditLogger(cfg.AuditLogPath, cfg.AuditRotateSize, cfg.AuditMaxBackups)
 		if err != nil {
@@ -34,12 +41,19 @@ func NewServer(cfg *Config, appCfg *config.Config, rdb *redis.Client, logger *za
 		}
 	}
 
+	var metrics *apiMetrics
+	if cfg.MetricsEnabled {
+		metrics = newAPIMetrics(appCfg, rdb, logger)
+	}
+
 	return &Server{
 		cfg:      cfg,
 		appCfg:   appCfg,
 		rdb:      rdb,
 		logger:   logger,
 		auditLog: auditLog,
+		auth:     auth,
+		metrics:  metrics,
 	}, nil
 }
 
@@ -57,9 +71,18 @@ func (s *Server) Start() error {
 		WriteTimeout: s.cfg.WriteTimeout,
 	}
 
+	if s.cfg.TLSEnabled && s.cfg.TLSClientCAFile != "" {
+		tlsConfig, err := clientTLSConfig(s.cfg.TLSClientCAFile)
+		if err != nil {
+			return err
+		}
+		s.server.TLSConfig = tlsConfig
+	}
+
 	s.logger.Info("Starting admin API server",
 		zap.String("addr", s.cfg.ListenAddr),
 		zap.Bool("auth_enabled", s.cfg.RequireAuth),
+		zap.String("auth_provider", s.cfg.AuthProvider),
 		zap.Bool("rate_limit_enabled", s.cfg.RateLimitEnabled))
 
 	if s.cfg.TLSEnabled {
@@ -93,32 +116,173 @@ func (s *Server) SetupRoutes() http.Handler {
 		w.Write([]byte(`{"status":"healthy"}`))
 	})
 
-	// API v1 endpoints
-    mux.HandleFunc("/api/v1/stats", methodHandler("GET", h.GetStats))
-    mux.HandleFunc("/api/v1/stats/keys", methodHandler("GET", h.GetStatsKeys))
-    // DLQ endpoints
-    mux.HandleFunc("/api/v1/dlq", methodHandler("GET", h.ListDLQ))
-    mux.HandleFunc("/api/v1/dlq/requeue", methodHandler("POST", h.RequeueDLQ))
-    mux.HandleFunc("/api/v1/dlq/purge", methodHandler("POST", h.PurgeDLQItems))
-    // Workers
-    mux.HandleFunc("/api/v1/workers", methodHandler("GET", h.GetWorkers))
+	// API v1 endpoints. Routes registered through rr are listed in the
+	// generated OpenAPI document served at /openapi.json.
+	rr := &routeRegistry{mux: mux}
+	rr.handle(routeDoc{
+		Method: "GET", Path: "/api/v1/stats", OperationID: "getStats", Tag: "stats",
+		Summary:  "Get queue, processing list and heartbeat counts",
+		Response: StatsResponse{},
+	}, h.GetStats)
+	rr.handle(routeDoc{
+		Method: "GET", Path: "/api/v1/stats/keys", OperationID: "getStatsKeys", Tag: "stats",
+		Summary:  "Get Redis key statistics",
+		Response: StatsKeysResponse{},
+	}, h.GetStatsKeys)
+	rr.handle(routeDoc{
+		Method: "GET", Path: "/api/v1/stream/stats", OperationID: "streamStats", Tag: "stats",
+		Summary: "Upgrade to a WebSocket streaming StatsStreamMessage frames",
+	}, h.StreamStats)
+	// DLQ endpoints
+	rr.handle(routeDoc{
+		Method: "GET", Path: "/api/v1/dlq", OperationID: "listDLQ", Tag: "dlq",
+		Summary: "List dead letter queue items",
+		Params: []paramDoc{
+			{Name: "ns", In: "query", Description: "Namespace"},
+			{Name: "cursor", In: "query", Description: "next_cursor from the previous page"},
+			{Name: "limit", In: "query", Type: "integer", Description: "Page size, 1-500 (default 100)"},
+		},
+		Response: DLQListResponse{},
+	}, h.ListDLQ)
+	rr.handle(routeDoc{
+		Method: "GET", Path: "/api/v1/events/dlq", OperationID: "streamDLQEvents", Tag: "dlq",
+		Summary: "Stream DLQ threshold and growth alerts as Server-Sent Events",
+		Params: []paramDoc{
+			{Name: "access_token", In: "query", Description: "Bearer token, for clients that cannot set headers"},
+			{Name: "last_event_id", In: "query", Type: "integer", Description: "Replay retained events after this ID (or send Last-Event-ID)"},
+		},
+	}, h.DLQEvents)
+	rr.handle(routeDoc{
+		Method: "POST", Path: "/api/v1/dlq/requeue", OperationID: "requeueDLQ", Tag: "dlq",
+		Summary: "Requeue selected dead letter queue items",
+		Request: DLQRequeueRequest{}, Response: DLQRequeueResponse{}, Destructive: true,
+	}, h.RequeueDLQ)
+	rr.handle(routeDoc{
+		Method: "POST", Path: "/api/v1/dlq/purge", OperationID: "purgeDLQItems", Tag: "dlq",
+		Summary: "Purge selected dead letter queue items",
+		Request: DLQPurgeSelectionRequest{}, Response: DLQPurgeSelectionResponse{}, Destructive: true,
+	}, h.PurgeDLQItems)
+	// Bulk job operations
+	rr.handle(routeDoc{
+		Method: "POST", Path: "/api/v1/jobs/bulk", OperationID: "bulkJobs", Tag: "jobs",
+		Summary: "Requeue, delete or archive many jobs by ID or list member",
+		Request: BulkJobsRequest{}, Response: BulkJobsResponse{}, Destructive: true,
+	}, h.BulkJobs)
+	rr.handle(routeDoc{
+		Method: "GET", Path: "/api/v1/jobs/{id}", OperationID: "getJob", Tag: "jobs",
+		Summary:  "Find where a job is: queue, processing list, scheduled set, DLQ or completed list",
+		Params:   []paramDoc{{Name: "id", In: "path", Description: "Job ID"}},
+		Response: JobLocationResponse{},
+	}, h.GetJob)
+	// Workers
+	rr.handle(routeDoc{
+		Method: "GET", Path: "/api/v1/workers", OperationID: "getWorkers", Tag: "workers",
+		Summary:  "List active workers",
+		Params:   []paramDoc{{Name: "ns", In: "query", Description: "Namespace"}},
+		Response: WorkersResponse{},
+	}, h.GetWorkers)
+	mux.HandleFunc("/api/v1/queues", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			h.ListQueues(w, r)
+		case "POST":
+			h.CreateQueue(w, r)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Method not allowed")
+		}
+	})
 	mux.HandleFunc("/api/v1/queues/", func(w http.ResponseWriter, r *http.Request) {
-		// Route based on path suffix
+		// Route based on path suffix; a single segment is a queue definition
 		path := r.URL.Path
 		switch {
 		case r.Method == "GET" && contains(path, "/peek"):
 			h.PeekQueue(w, r)
-		case r.Method == "DELETE" && contains(path, "/dlq"):
+		case r.Method == "DELETE" && path == "/api/v1/queues/dlq":
 			h.PurgeDLQ(w, r)
-		case r.Method == "DELETE" && contains(path, "/all"):
+		case r.Method == "DELETE" && path == "/api/v1/queues/all":
 			h.PurgeAll(w, r)
+		case r.Method == "GET":
+			h.GetQueue(w, r)
+		case r.Method == "PUT":
+			h.UpdateQueue(w, r)
+		case r.Method == "DELETE":
+			h.DeleteQueue(w, r)
 		default:
 			writeError(w, http.StatusNotFound, "NOT_FOUND", "Endpoint not found")
 		}
 	})
-	mux.HandleFunc("/api/v1/bench", methodHandler("POST", h.RunBenchmark))
+	rr.document(routeDoc{
+		Method: "GET", Path: "/api/v1/queues", OperationID: "listQueues", Tag: "queues",
+		Summary:  "List queue definitions",
+		Response: QueueDefinitionsResponse{},
+	})
+	rr.document(routeDoc{
+		Method: "POST", Path: "/api/v1/queues", OperationID: "createQueue", Tag: "queues",
+		Summary: "Define a queue that workers poll and producers cap at runtime",
+		Request: QueueDefinitionRequest{}, Response: QueueDefinitionResponse{},
+	})
+	rr.document(routeDoc{
+		Method: "GET", Path: "/api/v1/queues/{name}", OperationID: "getQueue", Tag: "queues",
+		Summary:  "Get a queue definition and its length",
+		Params:   []paramDoc{{Name: "name", In: "path", Description: "Queue name (its Redis key)"}},
+		Response: QueueDefinitionResponse{},
+	})
+	rr.document(routeDoc{
+		Method: "PUT", Path: "/api/v1/queues/{name}", OperationID: "updateQueue", Tag: "queues",
+		Summary:  "Replace a queue definition",
+		Params:   []paramDoc{{Name: "name", In: "path", Description: "Queue name (its Redis key)"}},
+		Request:  QueueDefinitionRequest{},
+		Response: QueueDefinitionResponse{},
+	})
+	rr.document(routeDoc{
+		Method: "DELETE", Path: "/api/v1/queues/{name}", OperationID: "deleteQueue", Tag: "queues",
+		Summary: "Delete a queue definition; a queue holding jobs needs force",
+		Params: []paramDoc{
+			{Name: "name", In: "path", Description: "Queue name (its Redis key)"},
+			{Name: "force", In: "query", Type: "boolean", Description: "Also delete the queue's jobs"},
+		},
+		Response: QueueDeleteResponse{}, Destructive: true,
+	})
+	rr.document(routeDoc{
+		Method: "GET", Path: "/api/v1/queues/{queue}/peek", OperationID: "peekQueue", Tag: "queues",
+		Summary: "Peek at jobs in a queue without removing them",
+		Params: []paramDoc{
+			{Name: "queue", In: "path", Description: "Queue alias or Redis key"},
+			{Name: "count", In: "query", Type: "integer", Description: "Items to return, 1-100 (default 10)"},
+			{Name: "filter", In: "query", Description: "Only return items matching this filter"},
+			{Name: "project", In: "query", Description: "Comma-separated fields to keep in each item"},
+			{Name: "cursor", In: "query", Description: "next_cursor from the previous page"},
+		},
+		Response: PeekResponse{},
+	})
+	rr.document(routeDoc{
+		Method: "DELETE", Path: "/api/v1/queues/dlq", OperationID: "purgeDLQ", Tag: "queues",
+		Summary: "Purge the whole dead letter queue",
+		Request: PurgeRequest{}, Response: PurgeResponse{}, Destructive: true,
+	})
+	rr.document(routeDoc{
+		Method: "DELETE", Path: "/api/v1/queues/all", OperationID: "purgeAll", Tag: "queues",
+		Summary: "Purge every queue",
+		Request: PurgeRequest{}, Response: PurgeResponse{}, Destructive: true,
+	})
+	rr.handle(routeDoc{
+		Method: "POST", Path: "/api/v1/bench", OperationID: "runBenchmark", Tag: "benchmark",
+		Summary: "Run a benchmark against a priority queue",
+		Request: BenchRequest{}, Response: BenchResponse{},
+	}, h.RunBenchmark)
+
+	// Prometheus scrape endpoint
+	if s.metrics != nil {
+		rr.handle(routeDoc{
+			Method: "GET", Path: metricsPath, OperationID: "getMetrics", Tag: "stats",
+			Summary: "Prometheus metrics: API requests, queue lengths, processing jobs and worker liveness",
+		}, s.metrics.handler().ServeHTTP)
+	}
 
-	// OpenAPI spec endpoint
+	// Generated OpenAPI document and Swagger UI
+	rr.serveOpenAPI()
+
+	// Hand-written OpenAPI spec endpoint
     mux.HandleFunc("/api/v1/openapi.yaml", func(w http.ResponseWriter, r *http.Request) {
         w.Header().Set("Content-Type", "application/x-yaml")
         w.Write([]byte(openAPISpec))
@@ -129,6 +293,8 @@ func (s *Server) SetupRoutes() http.Handler {
 
 // applyMiddleware applies the middleware chain
 func (s *Server) applyMiddleware(handler http.Handler) http.Handler {
+	routes, _ := handler.(*http.ServeMux)
+
 	// Apply in reverse order (outermost first)
 
 	// Recovery middleware (outermost)
@@ -137,29 +303,66 @@ func (s *Server) applyMiddleware(handler http.Handler) http.Handler {
 	// Request ID middleware
 	handler = RequestIDMiddleware()(handler)
 
-	// CORS middleware
-	if s.cfg.CORSEnabled {
-		handler = CORSMiddleware(s.cfg.CORSAllowOrigins)(handler)
-	}
-
 	// Audit middleware
 	if s.cfg.AuditEnabled && s.auditLog != nil {
 		handler = AuditMiddleware(s.auditLog, s.logger)(handler)
 	}
 
-	// Rate limiting middleware
+	// Rate limiting middleware: shared per-token quotas when Redis is
+	// available, otherwise the per-process token bucket
 	if s.cfg.RateLimitEnabled {
-		handler = RateLimitMiddleware(s.cfg.RateLimitPerMinute, s.cfg.RateLimitBurst, s.logger)(handler)
+		if s.rdb != nil {
+			handler = QuotaMiddleware(s.rdb, s.cfg, s.logger)(handler)
+		} else {
+			handler = RateLimitMiddleware(s.cfg.RateLimitPerMinute, s.cfg.RateLimitBurst, s.logger)(handler)
+		}
 	}
 
-	// Auth middleware
+	// Replays of an Idempotency-Key are answered before they use quota
+	if s.rdb != nil {
+		handler = IdempotencyMiddleware(s.rdb, s.cfg, s.logger)(handler)
+	}
+
+	// Scope check for destructive operations, before they use quota
+	if len(s.cfg.DestructiveScopes) > 0 {
+		handler = DestructiveScopeMiddleware(s.cfg.DestructiveScopes)(handler)
+	}
+
+	// Auth middleware; public metrics skip it
 	if s.cfg.RequireAuth {
-		handler = AuthMiddleware(s.cfg.JWTSecret, s.cfg.DenyByDefault, s.logger)(handler)
+		authed := AuthenticatorMiddleware(s.auth, s.cfg.DenyByDefault, s.logger)(handler)
+		if s.cfg.MetricsPublic && s.metrics != nil {
+			authed = bypassPath(metricsPath, handler, authed)
+		}
+		handler = authed
+	}
+
+	// CORS middleware wraps auth: browsers send preflight requests without
+	// credentials
+	if s.cfg.CORSEnabled {
+		handler = CORSMiddlewareWithOptions(s.cfg.CORSOptions())(handler)
+	}
+
+	// Request metrics (outermost, so rejected requests count too)
+	if s.metrics != nil {
+		handler = s.metrics.middleware(routes)(handler)
 	}
 
 	return handler
 }
 
+// bypassPath sends requests for path to public and everything else to
+// protected.
+func bypassPath(path string, public, protected http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == path {
+			public.ServeHTTP(w, r)
+			return
+		}
+		protected.ServeHTTP(w, r)
+	})
+}
+
 // Helper function to create method-specific handlers
 func methodHandler(method string, handler http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,290 @@
+// Copyright 2025 James Ross
+package adminapi
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/admin"
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/gorilla/websocket"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	streamWriteWait  = 10 * time.Second
+	streamPongWait   = 60 * time.Second
+	streamPingPeriod = (streamPongWait * 9) / 10
+	streamSendBuffer = 8
+)
+
+// StatsStreamMessage is pushed to /api/v1/stream/stats subscribers. Snapshots
+// carry the full StatsResponse; deltas carry the new values of only the
+// queues, processing lists or heartbeat count that changed significantly.
+type StatsStreamMessage struct {
+	Type            string           `json:"type"`
+	Stats           *StatsResponse   `json:"stats,omitempty"`
+	Queues          map[string]int64 `json:"queues,omitempty"`
+	ProcessingLists map[string]int64 `json:"processing_lists,omitempty"`
+	Heartbeats      *int64           `json:"heartbeats,omitempty"`
+	Timestamp       time.Time        `json:"timestamp"`
+}
+
+// statsHub polls Redis once for all stream subscribers. It runs only while
+// at least one subscriber is connected.
+type statsHub struct {
+	cfg    *config.Config
+	apiCfg *Config
+	rdb    *redis.Client
+	logger *zap.Logger
+
+	mu     sync.Mutex
+	subs   map[chan StatsStreamMessage]struct{}
+	last   *StatsResponse
+	cancel context.CancelFunc
+}
+
+func newStatsHub(cfg *config.Config, apiCfg *Config, rdb *redis.Client, logger *zap.Logger) *statsHub {
+	return &statsHub{
+		cfg:    cfg,
+		apiCfg: apiCfg,
+		rdb:    rdb,
+		logger: logger,
+		subs:   make(map[chan StatsStreamMessage]struct{}),
+	}
+}
+
+// subscribe registers a new subscriber, or returns false when the connection
+// limit has been reached.
+func (hub *statsHub) subscribe() (chan StatsStreamMessage, bool) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	if max := hub.apiCfg.StreamMaxConnections; max > 0 && len(hub.subs) >= max {
+		return nil, false
+	}
+	ch := make(chan StatsStreamMessage, streamSendBuffer)
+	hub.subs[ch] = struct{}{}
+	if hub.last != nil {
+		ch <- StatsStreamMessage{Type: "snapshot", Stats: hub.last, Timestamp: hub.last.Timestamp}
+	}
+	if hub.cancel == nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		hub.cancel = cancel
+		go hub.run(ctx)
+	}
+	return ch, true
+}
+
+func (hub *statsHub) unsubscribe(ch chan StatsStreamMessage) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	delete(hub.subs, ch)
+	if len(hub.subs) == 0 && hub.cancel != nil {
+		hub.cancel()
+		hub.cancel = nil
+		hub.last = nil
+	}
+}
+
+// publish records current as the latest stats and delivers msg (if any)
+// without blocking; a subscriber that is behind misses it and catches up on
+// the next snapshot. Nothing is published once ctx has been canceled.
+func (hub *statsHub) publish(ctx context.Context, current *StatsResponse, msg *StatsStreamMessage) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	if ctx.Err() != nil {
+		return
+	}
+	hub.last = current
+	if msg == nil {
+		return
+	}
+	for ch := range hub.subs {
+		select {
+		case ch <- *msg:
+		default:
+		}
+	}
+}
+
+func (hub *statsHub) run(ctx context.Context) {
+	interval, deltaInterval := hub.apiCfg.StreamInterval, hub.apiCfg.StreamDeltaInterval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	if deltaInterval <= 0 || deltaInterval > interval {
+		deltaInterval = interval
+	}
+	deltaTicker := time.NewTicker(deltaInterval)
+	defer deltaTicker.Stop()
+	snapshotTicker := time.NewTicker(interval)
+	defer snapshotTicker.Stop()
+
+	var sent *StatsResponse
+	poll := func(full bool) {
+		pollCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+
+		stats, err := admin.Stats(pollCtx, hub.cfg, hub.rdb)
+		if err != nil {
+			if ctx.Err() == nil {
+				hub.logger.Warn("Stats stream poll failed", zap.Error(err))
+			}
+			return
+		}
+		current := &StatsResponse{
+			Queues:          stats.Queues,
+			ProcessingLists: stats.ProcessingLists,
+			Heartbeats:      stats.Heartbeats,
+			Timestamp:       time.Now(),
+		}
+
+		if full || sent == nil {
+			hub.publish(ctx, current, &StatsStreamMessage{Type: "snapshot", Stats: current, Timestamp: current.Timestamp})
+			sent = current
+			return
+		}
+		if delta, ok := statsDelta(sent, current, hub.apiCfg.StreamDeltaThreshold); ok {
+			hub.publish(ctx, current, &delta)
+			sent = current
+			return
+		}
+		hub.publish(ctx, current, nil)
+	}
+
+	poll(true)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-snapshotTicker.C:
+			poll(true)
+		case <-deltaTicker.C:
+			poll(false)
+		}
+	}
+}
+
+// statsDelta reports the counters that moved by at least threshold since prev.
+// Keys missing from curr are reported as 0.
+func statsDelta(prev, curr *StatsResponse, threshold int64) (StatsStreamMessage, bool) {
+	if threshold < 1 {
+		threshold = 1
+	}
+	changed := func(a, b int64) bool {
+		d := a - b
+		if d < 0 {
+			d = -d
+		}
+		return d >= threshold
+	}
+	diff := func(before, after map[string]int64) map[string]int64 {
+		out := make(map[string]int64)
+		for k, v := range after {
+			if changed(v, before[k]) {
+				out[k] = v
+			}
+		}
+		for k, v := range before {
+			if _, ok := after[k]; !ok && changed(0, v) {
+				out[k] = 0
+			}
+		}
+		if len(out) == 0 {
+			return nil
+		}
+		return out
+	}
+
+	msg := StatsStreamMessage{
+		Type:            "delta",
+		Queues:          diff(prev.Queues, curr.Queues),
+		ProcessingLists: diff(prev.ProcessingLists, curr.ProcessingLists),
+		Timestamp:       curr.Timestamp,
+	}
+	if changed(curr.Heartbeats, prev.Heartbeats) {
+		hb := curr.Heartbeats
+		msg.Heartbeats = &hb
+	}
+	ok := msg.Queues != nil || msg.ProcessingLists != nil || msg.Heartbeats != nil
+	return msg, ok
+}
+
+// StreamStats handles GET /api/v1/stream/stats by upgrading to a WebSocket
+// and pushing StatsStreamMessage frames until the client goes away.
+func (h *Handler) StreamStats(w http.ResponseWriter, r *http.Request) {
+	ch, ok := h.stream.subscribe()
+	if !ok {
+		writeError(w, http.StatusServiceUnavailable, "STREAM_LIMIT", "Too many stats stream connections")
+		return
+	}
+	defer h.stream.unsubscribe(ch)
+
+	upgrader := websocket.Upgrader{CheckOrigin: h.checkStreamOrigin}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already written an HTTP error response.
+		h.logger.Debug("Stats stream upgrade failed", zap.Error(err))
+		return
+	}
+	defer conn.Close()
+
+	// Reader: handles pongs and notices disconnects.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		conn.SetReadLimit(512)
+		_ = conn.SetReadDeadline(time.Now().Add(streamPongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(streamPongWait))
+		})
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(streamPingPeriod)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case msg := <-ch:
+			_ = conn.SetWriteDeadline(time.Now().Add(streamWriteWait))
+			if err := conn.WriteJSON(msg); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(streamWriteWait)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// checkStreamOrigin applies the CORS allow-list to WebSocket handshakes and
+// otherwise requires a same-origin request (or a non-browser client).
+func (h *Handler) checkStreamOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if h.apiCfg.CORSEnabled {
+		for _, allowed := range h.apiCfg.CORSAllowOrigins {
+			if allowed == "*" || allowed == origin {
+				return true
+			}
+		}
+		return false
+	}
+	return origin == "http://"+r.Host || origin == "https://"+r.Host
+}
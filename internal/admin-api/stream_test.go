@@ -0,0 +1,127 @@
+// Copyright 2025 James Ross
+package adminapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func dialStatsStream(t *testing.T, srv *httptest.Server) *websocket.Conn {
+	t.Helper()
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/v1/stream/stats"
+	conn, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+		}
+		t.Fatalf("dial failed (HTTP %d): %v", status, err)
+	}
+	return conn
+}
+
+func readStreamMessage(t *testing.T, conn *websocket.Conn) StatsStreamMessage {
+	t.Helper()
+	var msg StatsStreamMessage
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Fatalf("read stream message: %v", err)
+	}
+	return msg
+}
+
+func TestStreamStatsSnapshotThenDelta(t *testing.T) {
+	handler, mr, cleanup := setupHandlerTest(t)
+	defer cleanup()
+	handler.apiCfg.StreamInterval = time.Hour
+	handler.apiCfg.StreamDeltaInterval = 20 * time.Millisecond
+	handler.apiCfg.StreamDeltaThreshold = 2
+
+	mr.Lpush("jobqueue:high", "job1")
+
+	srv := httptest.NewServer(http.HandlerFunc(handler.StreamStats))
+	defer srv.Close()
+	conn := dialStatsStream(t, srv)
+
+	msg := readStreamMessage(t, conn)
+	if msg.Type != "snapshot" || msg.Stats == nil {
+		t.Fatalf("expected initial snapshot, got %+v", msg)
+	}
+	if got := msg.Stats.Queues["high(jobqueue:high)"]; got != 1 {
+		t.Fatalf("expected high=1 in snapshot, got %d", got)
+	}
+
+	// A change below the threshold is not pushed; crossing it is.
+	mr.Lpush("jobqueue:high", "job2")
+	time.Sleep(100 * time.Millisecond)
+	mr.Lpush("jobqueue:high", "job3")
+
+	msg = readStreamMessage(t, conn)
+	if msg.Type != "delta" {
+		t.Fatalf("expected delta, got %+v", msg)
+	}
+	if got, ok := msg.Queues["high(jobqueue:high)"]; !ok || got != 3 {
+		t.Fatalf("expected high=3 in delta, got %v", msg.Queues)
+	}
+	if _, ok := msg.Queues["low(jobqueue:low)"]; ok {
+		t.Fatalf("unchanged queue should not appear in delta: %v", msg.Queues)
+	}
+
+	// Disconnecting releases the subscription and stops the poller.
+	conn.Close()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		handler.stream.mu.Lock()
+		n, running := len(handler.stream.subs), handler.stream.cancel != nil
+		handler.stream.mu.Unlock()
+		if n == 0 && !running {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("subscription not cleaned up: subs=%d running=%v", n, running)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestStreamStatsConnectionLimit(t *testing.T) {
+	handler, _, cleanup := setupHandlerTest(t)
+	defer cleanup()
+	handler.apiCfg.StreamMaxConnections = 1
+
+	srv := httptest.NewServer(http.HandlerFunc(handler.StreamStats))
+	defer srv.Close()
+	conn := dialStatsStream(t, srv)
+	defer conn.Close()
+
+	url := "ws" + strings.TrimPrefix(srv.URL, "http")
+	_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	if err == nil {
+		t.Fatal("expected second connection to be rejected")
+	}
+	if resp == nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %v", resp)
+	}
+}
+
+func TestStreamStatsRejectsCrossOrigin(t *testing.T) {
+	handler, _, cleanup := setupHandlerTest(t)
+	defer cleanup()
+
+	srv := httptest.NewServer(http.HandlerFunc(handler.StreamStats))
+	defer srv.Close()
+
+	url := "ws" + strings.TrimPrefix(srv.URL, "http")
+	_, resp, err := websocket.DefaultDialer.Dial(url, http.Header{"Origin": []string{"https://evil.example"}})
+	if err == nil {
+		t.Fatal("expected cross-origin handshake to fail")
+	}
+	if resp == nil || resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected 403, got %v", resp)
+	}
+}
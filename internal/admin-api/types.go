@@ -2,6 +2,7 @@
 package adminapi
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -58,10 +59,13 @@ type StatsKeysResponse struct {
 }
 
 type PeekResponse struct {
-	Queue     string    `json:"queue"`
-	Items     []string  `json:"items"`
-	Count     int       `json:"count"`
-	Timestamp time.Time `json:"timestamp"`
+	Queue      string    `json:"queue"`
+	Items      []string  `json:"items"`
+	Count      int       `json:"count"`
+	Scanned    int64     `json:"scanned,omitempty"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+	HasMore    bool      `json:"has_more"`
+	Timestamp  time.Time `json:"timestamp"`
 }
 
 type BenchResponse struct {
@@ -94,13 +98,14 @@ type DLQItem struct {
 type DLQListResponse struct {
 	Items      []DLQItem `json:"items"`
 	NextCursor string    `json:"next_cursor,omitempty"`
+	HasMore    bool      `json:"has_more"`
 	Count      int       `json:"count"`
 	Timestamp  time.Time `json:"timestamp"`
 }
 
 type DLQRequeueRequest struct {
 	Namespace string   `json:"ns"`
-	IDs       []string `json:"ids"`
+	IDs       []string `json:"ids" validate:"required,min=1"`
 	DestQueue string   `json:"dest_queue,omitempty"`
 }
 
@@ -111,7 +116,7 @@ type DLQRequeueResponse struct {
 
 type DLQPurgeSelectionRequest struct {
 	Namespace string   `json:"ns"`
-	IDs       []string `json:"ids"`
+	IDs       []string `json:"ids" validate:"required,min=1"`
 }
 
 type DLQPurgeSelectionResponse struct {
@@ -119,6 +124,32 @@ type DLQPurgeSelectionResponse struct {
 	Timestamp time.Time `json:"timestamp"`
 }
 
+// Bulk job operations
+type BulkJobsRequest struct {
+	Action    string   `json:"action" validate:"required,oneof=requeue delete archive"`
+	Queue     string   `json:"queue,omitempty"`                 // source alias or key; default dead letter list
+	DestQueue string   `json:"dest_queue,omitempty"`            // requeue target; default the job's priority queue
+	Items     []string `json:"items" validate:"required,min=1"` // job IDs or exact list members
+}
+
+type BulkJobResult struct {
+	Target  string `json:"target"`
+	JobID   string `json:"job_id,omitempty"`
+	Success bool   `json:"success"`
+	Dest    string `json:"dest,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+type BulkJobsResponse struct {
+	Action    string          `json:"action"`
+	Queue     string          `json:"queue"`
+	Total     int             `json:"total"`
+	Succeeded int             `json:"succeeded"`
+	Failed    int             `json:"failed"`
+	Results   []BulkJobResult `json:"results"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
 // Workers types
 type WorkerInfo struct {
 	ID            string     `json:"id"`
@@ -128,6 +159,9 @@ type WorkerInfo struct {
 	StartedAt     *time.Time `json:"started_at,omitempty"`
 	Version       string     `json:"version,omitempty"`
 	Host          string     `json:"host,omitempty"`
+	Alive         bool       `json:"alive"`
+	Processing    int64      `json:"processing"`
+	ReapCandidate bool       `json:"reap_candidate"`
 }
 
 type WorkersResponse struct {
@@ -135,6 +169,60 @@ type WorkersResponse struct {
 	Timestamp time.Time    `json:"timestamp"`
 }
 
+// JobLocationResponse is where GET /api/v1/jobs/{id} found a job. Kind is
+// queue, processing, scheduled, delayed, dead_letter, completed or stream.
+type JobLocationResponse struct {
+	JobID     string     `json:"job_id"`
+	Kind      string     `json:"kind"`
+	Key       string     `json:"key"`
+	Position  int64      `json:"position"`
+	StreamID  string     `json:"stream_id,omitempty"`
+	DueAt     *time.Time `json:"due_at,omitempty"`
+	Payload   string     `json:"payload"`
+	Timestamp time.Time  `json:"timestamp"`
+}
+
+// QueueDefinitionRequest creates (POST /api/v1/queues) or replaces
+// (PUT /api/v1/queues/{name}) a queue definition.
+type QueueDefinitionRequest struct {
+	Name       string                `json:"name,omitempty"` // required for POST; PUT takes it from the path
+	Priority   string                `json:"priority,omitempty"`
+	RateLimit  float64               `json:"rate_limit,omitempty"` // jobs per second across workers
+	Burst      int                   `json:"burst,omitempty"`
+	MaxLength  int64                 `json:"max_length,omitempty"`
+	DeadLetter QueueDeadLetterPolicy `json:"dead_letter"`
+}
+
+type QueueDeadLetterPolicy struct {
+	MaxRetries *int   `json:"max_retries,omitempty"`
+	List       string `json:"list,omitempty"`
+}
+
+type QueueDefinitionResponse struct {
+	Name       string                `json:"name"`
+	Priority   string                `json:"priority"`
+	RateLimit  float64               `json:"rate_limit,omitempty"`
+	Burst      int                   `json:"burst,omitempty"`
+	MaxLength  int64                 `json:"max_length,omitempty"`
+	DeadLetter QueueDeadLetterPolicy `json:"dead_letter"`
+	Length     int64                 `json:"length"` // jobs waiting in the list
+	CreatedAt  time.Time             `json:"created_at"`
+	UpdatedAt  time.Time             `json:"updated_at"`
+}
+
+type QueueDefinitionsResponse struct {
+	Queues    []QueueDefinitionResponse `json:"queues"`
+	Count     int                       `json:"count"`
+	Timestamp time.Time                 `json:"timestamp"`
+}
+
+type QueueDeleteResponse struct {
+	Success     bool      `json:"success"`
+	Name        string    `json:"name"`
+	JobsDeleted int64     `json:"jobs_deleted"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
 // Audit log entry
 type AuditEntry struct {
 	ID        string                 `json:"id"`
@@ -152,12 +240,41 @@ type AuditEntry struct {
 // JWT claims
 type Claims struct {
 	Subject   string   `json:"sub"`
+	Issuer    string   `json:"iss,omitempty"`
+	Audience  Audience `json:"aud,omitempty"`
 	Roles     []string `json:"roles"`
 	Scopes    []string `json:"scopes"`
 	ExpiresAt int64    `json:"exp"`
 	IssuedAt  int64    `json:"iat"`
 }
 
+// Audience is the JWT aud claim, which may be a string or a list
+type Audience []string
+
+// UnmarshalJSON accepts both forms of aud
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
+}
+
+func (a Audience) contains(audience string) bool {
+	for _, v := range a {
+		if v == audience {
+			return true
+		}
+	}
+	return false
+}
+
 // Rate limit info
 type RateLimitInfo struct {
 	Limit     int
@@ -13,6 +13,7 @@ import (
 
 	"github.com/flyingrobots/go-redis-work-queue/internal/config"
 	"github.com/flyingrobots/go-redis-work-queue/internal/distributed-tracing-integration"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -20,6 +21,7 @@ type StatsResult struct {
 	Queues          map[string]int64 `json:"queues"`
 	ProcessingLists map[string]int64 `json:"processing_lists"`
 	Heartbeats      int64            `json:"heartbeats"`
+	Paused          map[string]bool  `json:"paused,omitempty"`
 }
 
 func Stats(ctx context.Context, cfg *config.Config, rdb *redis.Client) (StatsResult, error) {
@@ -41,7 +43,7 @@ func Stats(ctx context.Context, cfg *config.Config, rdb *redis.Client) (StatsRes
 	// Scan processing lists
 	var cursor uint64
 	for {
-		keys, cur, err := rdb.Scan(ctx, cursor, "jobqueue:worker:*:processing", 200).Result()
+		keys, cur, err := rdb.Scan(ctx, cursor, processingListGlob(cfg), 200).Result()
 		if err != nil {
 			return res, err
 		}
@@ -58,7 +60,7 @@ func Stats(ctx context.Context, cfg *config.Config, rdb *redis.Client) (StatsRes
 	var hbc int64
 	cursor = 0
 	for {
-		keys, cur, err := rdb.Scan(ctx, cursor, "jobqueue:processing:worker:*", 500).Result()
+		keys, cur, err := rdb.Scan(ctx, cursor, heartbeatGlob(cfg), 500).Result()
 		if err != nil {
 			return res, err
 		}
@@ -69,15 +71,36 @@ func Stats(ctx context.Context, cfg *config.Config, rdb *redis.Client) (StatsRes
 		}
 	}
 	res.Heartbeats = hbc
+	paused, err := PausedQueues(ctx, cfg, rdb)
+	if err != nil {
+		return res, err
+	}
+	if len(paused) > 0 {
+		res.Paused = paused
+	}
 	return res, nil
 }
 
 type PeekResult struct {
-	Queue string   `json:"queue"`
-	Items []string `json:"items"`
+	Queue   string   `json:"queue"`
+	Items   []string `json:"items"`
+	Scanned int64    `json:"scanned,omitempty"`
+	// NextCursor continues the peek after Items when HasMore is set; pass
+	// it back in PeekOptions.Cursor.
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
 }
 
 func Peek(ctx context.Context, cfg *config.Config, rdb *redis.Client, queueAlias string, n int64) (PeekResult, error) {
+	return PeekWithOptions(ctx, cfg, rdb, queueAlias, n, PeekOptions{})
+}
+
+// PeekWithOptions is Peek with optional server-side filtering, field
+// projection and paging. With a filter, the queue is scanned from the
+// consuming end until n matching items are found or opts.ScanLimit items
+// have been inspected; n bounds matches, not items scanned. Pages continue
+// from opts.Cursor towards the head.
+func PeekWithOptions(ctx context.Context, cfg *config.Config, rdb *redis.Client, queueAlias string, n int64, opts PeekOptions) (PeekResult, error) {
 	qkey, err := resolveQueue(cfg, queueAlias)
 	if err != nil {
 		return PeekResult{}, err
@@ -85,12 +108,32 @@ func Peek(ctx context.Context, cfg *config.Config, rdb *redis.Client, queueAlias
 	if n <= 0 {
 		n = 10
 	}
-	// Items to be consumed next are at the right end; take last N
-	items, err := rdb.LRange(ctx, qkey, -n, -1).Result()
-	if err != nil {
-		return PeekResult{}, err
+	if opts.Filter == "" {
+		// Items to be consumed next are at the right end; take last N
+		page, err := readPage(ctx, rdb, qkey, opts.Cursor, n, true)
+		if err != nil {
+			return PeekResult{}, err
+		}
+		items := page.Items
+		for i := range items {
+			items[i] = decodeItem(items[i])
+		}
+		items, err = projectItems(items, opts.Project)
+		if err != nil {
+			return PeekResult{}, err
+		}
+		return PeekResult{Queue: qkey, Items: items, NextCursor: page.Next, HasMore: page.HasMore}, nil
+	}
+	return peekFiltered(ctx, rdb, qkey, n, opts)
+}
+
+// decodeItem returns the original payload of a compressed queue item, or
+// the item itself when it is not compressed or cannot be inflated.
+func decodeItem(raw string) string {
+	if out, err := queue.DecompressPayload(raw); err == nil {
+		return out
 	}
-	return PeekResult{Queue: qkey, Items: items}, nil
+	return raw
 }
 
 func PurgeDLQ(ctx context.Context, cfg *config.Config, rdb *redis.Client) error {
@@ -100,6 +143,64 @@ func PurgeDLQ(ctx context.Context, cfg *config.Config, rdb *redis.Client) error
 	return rdb.Del(ctx, cfg.Worker.DeadLetterList).Err()
 }
 
+// MoveJobs transfers up to count items from one queue to another, one
+// RPOPLPUSH (LMOVE RIGHT LEFT) at a time, so a crash mid-way never loses a
+// job and the moved jobs keep their relative consumption order. It refuses
+// to drain the completed or dead letter lists; use MoveJobsForce for that.
+// The returned count may be lower than requested if the source runs dry.
+func MoveJobs(ctx context.Context, cfg *config.Config, rdb *redis.Client, from, to string, count int) (int, error) {
+	return moveJobs(ctx, cfg, rdb, from, to, count, false)
+}
+
+// MoveJobsForce is MoveJobs without the completed/dead letter guard.
+func MoveJobsForce(ctx context.Context, cfg *config.Config, rdb *redis.Client, from, to string, count int) (int, error) {
+	return moveJobs(ctx, cfg, rdb, from, to, count, true)
+}
+
+func moveJobs(ctx context.Context, cfg *config.Config, rdb *redis.Client, from, to string, count int, force bool) (int, error) {
+	if count <= 0 {
+		return 0, fmt.Errorf("count must be > 0")
+	}
+	src, err := resolveQueue(cfg, from)
+	if err != nil {
+		return 0, err
+	}
+	dst, err := resolveQueue(cfg, to)
+	if err != nil {
+		return 0, err
+	}
+	if src == "" || dst == "" {
+		return 0, errors.New("source and destination queues must be configured")
+	}
+	if src == dst {
+		return 0, fmt.Errorf("source and destination are the same queue %q", src)
+	}
+	if !force && (src == cfg.Worker.CompletedList || src == cfg.Worker.DeadLetterList) {
+		return 0, fmt.Errorf("refusing to move from %q without force", src)
+	}
+	for _, key := range []string{src, dst} {
+		typ, err := rdb.Type(ctx, key).Result()
+		if err != nil {
+			return 0, err
+		}
+		if typ != "list" && typ != "none" {
+			return 0, fmt.Errorf("key %q is a %s, not a list", key, typ)
+		}
+	}
+
+	moved := 0
+	for moved < count {
+		if err := rdb.RPopLPush(ctx, src, dst).Err(); err != nil {
+			if err == redis.Nil {
+				break
+			}
+			return moved, err
+		}
+		moved++
+	}
+	return moved, nil
+}
+
 func resolveQueue(cfg *config.Config, alias string) (string, error) {
 	a := strings.ToLower(alias)
 	if a == "completed" {
@@ -111,9 +212,9 @@ func resolveQueue(cfg *config.Config, alias string) (string, error) {
 	if q, ok := cfg.Worker.Queues[a]; ok {
 		return q, nil
 	}
-	// Otherwise, assume full key
-	if strings.HasPrefix(alias, "jobqueue:") {
-		return alias, nil
+	// Otherwise, assume full key, inside the namespace
+	if key := cfg.Key(alias); strings.HasPrefix(key, cfg.Key("jobqueue:")) {
+		return key, nil
 	}
 	// Suggest options
 	keys := make([]string, 0, len(cfg.Worker.Queues))
@@ -126,18 +227,38 @@ func resolveQueue(cfg *config.Config, alias string) (string, error) {
 }
 
 type BenchResult struct {
+	Mode       string        `json:"mode"`
 	Count      int           `json:"count"`
 	Duration   time.Duration `json:"duration"`
 	Throughput float64       `json:"throughput_jobs_per_sec"`
 	P50        time.Duration `json:"p50_latency"`
 	P95        time.Duration `json:"p95_latency"`
+	// Completed and NotCompleted split Count by whether the job reached
+	// the completed list before the timeout.
+	Completed    int `json:"completed"`
+	NotCompleted int `json:"not_completed"`
+	// Latency is the enqueue-to-complete distribution of the completed
+	// jobs; only closed mode measures it.
+	Latency *LatencyStats `json:"latency,omitempty"`
 }
 
 // Bench enqueues count jobs to the chosen queue and waits for completion
 // (observing the completed list) up to timeout. It computes simple latency
 // stats using job creation_time vs. measurement time.
 func Bench(ctx context.Context, cfg *config.Config, rdb *redis.Client, priority string, count int, rate int, payloadSize int, timeout time.Duration) (BenchResult, error) {
-	res := BenchResult{Count: count}
+	return BenchWithOptions(ctx, cfg, rdb, priority, count, rate, payloadSize, timeout, BenchOptions{})
+}
+
+// BenchWithOptions is Bench with a choice of mode; see BenchOptions.
+func BenchWithOptions(ctx context.Context, cfg *config.Config, rdb *redis.Client, priority string, count int, rate int, payloadSize int, timeout time.Duration, opts BenchOptions) (BenchResult, error) {
+	res := BenchResult{Mode: BenchOpen, Count: count}
+	switch opts.Mode {
+	case "", BenchOpen:
+	case BenchClosed:
+		res.Mode = BenchClosed
+	default:
+		return res, fmt.Errorf("unknown bench mode %q; use %s or %s", opts.Mode, BenchOpen, BenchClosed)
+	}
 	if count <= 0 {
 		return res, fmt.Errorf("count must be > 0")
 	}
@@ -151,6 +272,9 @@ func Bench(ctx context.Context, cfg *config.Config, rdb *redis.Client, priority
 	if err != nil {
 		return res, err
 	}
+	if res.Mode == BenchClosed {
+		return benchClosed(ctx, cfg, rdb, res, qkey, priority, rate, payloadSize, timeout, opts.PollInterval)
+	}
 	// Clear completed
 	_ = rdb.Del(ctx, cfg.Worker.CompletedList).Err()
 
@@ -164,8 +288,7 @@ func Bench(ctx context.Context, cfg *config.Config, rdb *redis.Client, priority
 			return res, ctx.Err()
 		case <-ticker.C:
 		}
-		payload := fmt.Sprintf(`{"id":"bench-%d","filepath":"/bench/%d","filesize":%d,"priority":"%s","retries":0,"creation_time":"%s","trace_id":"","span_id":""}`,
-			i, i, payloadSize, priority, time.Now().UTC().Format(time.RFC3339Nano))
+		payload := benchPayload(fmt.Sprintf("bench-%d", i), i, payloadSize, priority, time.Now())
 		if err := rdb.LPush(ctx, qkey, payload).Err(); err != nil {
 			return res, err
 		}
@@ -187,13 +310,15 @@ func Bench(ctx context.Context, cfg *config.Config, rdb *redis.Client, priority
 
 	// Fetch and compute latencies
 	items, _ := rdb.LRange(ctx, cfg.Worker.CompletedList, 0, -1).Result()
+	res.Completed = min(len(items), count)
+	res.NotCompleted = count - res.Completed
 	lats := make([]float64, 0, len(items))
 	now := time.Now()
 	for _, it := range items {
 		var j struct {
 			CreationTime string `json:"creation_time"`
 		}
-		if err := json.Unmarshal([]byte(it), &j); err == nil {
+		if err := json.Unmarshal([]byte(decodeItem(it)), &j); err == nil {
 			if t, err2 := time.Parse(time.RFC3339Nano, j.CreationTime); err2 == nil {
 				lats = append(lats, now.Sub(t).Seconds())
 			}
@@ -240,7 +365,7 @@ func StatsKeys(ctx context.Context, cfg *config.Config, rdb *redis.Client) (Keys
 	// Processing lists
 	var cursor uint64
 	for {
-		keys, cur, err := rdb.Scan(ctx, cursor, "jobqueue:worker:*:processing", 500).Result()
+		keys, cur, err := rdb.Scan(ctx, cursor, processingListGlob(cfg), 500).Result()
 		if err != nil {
 			return out, err
 		}
@@ -257,7 +382,7 @@ func StatsKeys(ctx context.Context, cfg *config.Config, rdb *redis.Client) (Keys
 	// Heartbeats
 	cursor = 0
 	for {
-		keys, cur, err := rdb.Scan(ctx, cursor, "jobqueue:processing:worker:*", 1000).Result()
+		keys, cur, err := rdb.Scan(ctx, cursor, heartbeatGlob(cfg), 1000).Result()
 		if err != nil {
 			return out, err
 		}
@@ -311,10 +436,7 @@ func PurgeAll(ctx context.Context, cfg *config.Config, rdb *redis.Client) (int64
 		deleted += n
 	}
 	// Patterns: processing lists and heartbeats
-	patterns := []string{
-		"jobqueue:worker:*:processing",
-		"jobqueue:processing:worker:*",
-	}
+	patterns := []string{processingListGlob(cfg), heartbeatGlob(cfg)}
 	for _, pat := range patterns {
 		var cursor uint64
 		for {
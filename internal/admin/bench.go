@@ -0,0 +1,161 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/redis/go-redis/v9"
+)
+
+// Bench modes.
+const (
+	// BenchOpen enqueues at the given rate, waits for the completed list to
+	// hold count items and estimates latency from creation times.
+	BenchOpen = "open"
+	// BenchClosed follows each job from enqueue to the completed list and
+	// reports the latency distribution.
+	BenchClosed = "closed"
+)
+
+const defaultBenchPollInterval = 10 * time.Millisecond
+
+// BenchOptions selects how BenchWithOptions measures.
+type BenchOptions struct {
+	Mode string // BenchOpen (default) or BenchClosed
+	// PollInterval is how often closed mode reads the completed list, and
+	// so the resolution of its latencies; default 10ms.
+	PollInterval time.Duration
+}
+
+// LatencyStats summarizes a latency distribution.
+type LatencyStats struct {
+	Min  time.Duration `json:"min"`
+	Mean time.Duration `json:"mean"`
+	P50  time.Duration `json:"p50"`
+	P90  time.Duration `json:"p90"`
+	P95  time.Duration `json:"p95"`
+	P99  time.Duration `json:"p99"`
+	Max  time.Duration `json:"max"`
+}
+
+// benchPayload builds a bench job created at t.
+func benchPayload(id string, i, payloadSize int, priority string, t time.Time) string {
+	return fmt.Sprintf(`{"id":"%s","filepath":"/bench/%d","filesize":%d,"priority":"%s","retries":0,"creation_time":"%s","trace_id":"","span_id":""}`,
+		id, i, payloadSize, priority, t.UTC().Format(time.RFC3339Nano))
+}
+
+// benchClosed enqueues jobs with IDs unique to this run, remembering when
+// each was pushed, and while doing so and afterwards reads the completed
+// list as it grows. A job's latency runs from its push until the poll that
+// first sees it completed, so jobs may finish in any order. Jobs not seen
+// within timeout of the last push count as not completed. The completed
+// list is left alone; entries already there, or from other jobs, are
+// skipped.
+func benchClosed(ctx context.Context, cfg *config.Config, rdb *redis.Client, res BenchResult, qkey, priority string, rate, payloadSize int, timeout, poll time.Duration) (BenchResult, error) {
+	if poll <= 0 {
+		poll = defaultBenchPollInterval
+	}
+	list := cfg.Worker.CompletedList
+	seen, err := rdb.LLen(ctx, list).Result()
+	if err != nil {
+		return res, err
+	}
+
+	run := "bench-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	pending := make(map[string]time.Time, res.Count)
+	lats := make([]time.Duration, 0, res.Count)
+	var lastDone time.Time
+	// collect reads the entries pushed since the last call: LPUSH adds at
+	// the head, so everything before the seen oldest ones is new.
+	collect := func() error {
+		items, err := rdb.LRange(ctx, list, 0, -(seen + 1)).Result()
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		seen += int64(len(items))
+		for _, raw := range items {
+			job, err := queue.UnmarshalJob(decodeItem(raw))
+			if err != nil {
+				continue
+			}
+			if at, ok := pending[job.ID]; ok {
+				lats = append(lats, now.Sub(at))
+				delete(pending, job.ID)
+				lastDone = now
+			}
+		}
+		return nil
+	}
+
+	enqueue := time.NewTicker(time.Second / time.Duration(rate))
+	defer enqueue.Stop()
+	polls := time.NewTicker(poll)
+	defer polls.Stop()
+	enqueueC := enqueue.C
+	start := time.Now()
+	var doneBy time.Time
+	for sent := 0; sent < res.Count || (len(pending) > 0 && time.Now().Before(doneBy)); {
+		select {
+		case <-ctx.Done():
+			return res, ctx.Err()
+		case <-enqueueC:
+			id := fmt.Sprintf("%s-%d", run, sent)
+			now := time.Now()
+			pending[id] = now
+			if err := rdb.LPush(ctx, qkey, benchPayload(id, sent, payloadSize, priority, now)).Err(); err != nil {
+				return res, err
+			}
+			if sent++; sent == res.Count {
+				enqueueC = nil
+				doneBy = time.Now().Add(timeout)
+			}
+		case <-polls.C:
+			if err := collect(); err != nil {
+				return res, err
+			}
+		}
+	}
+
+	res.Completed = len(lats)
+	res.NotCompleted = len(pending)
+	if len(lats) == 0 {
+		res.Duration = time.Since(start)
+		return res, nil
+	}
+	res.Duration = lastDone.Sub(start)
+	if res.Duration > 0 {
+		res.Throughput = float64(res.Completed) / res.Duration.Seconds()
+	}
+	res.Latency = latencyStats(lats)
+	res.P50, res.P95 = res.Latency.P50, res.Latency.P95
+	return res, nil
+}
+
+// latencyStats sorts lats and summarizes them; lats must not be empty.
+func latencyStats(lats []time.Duration) *LatencyStats {
+	sort.Slice(lats, func(i, j int) bool { return lats[i] < lats[j] })
+	at := func(p float64) time.Duration {
+		return lats[int(math.Round(p*float64(len(lats)-1)))]
+	}
+	var sum time.Duration
+	for _, l := range lats {
+		sum += l
+	}
+	return &LatencyStats{
+		Min:  lats[0],
+		Mean: sum / time.Duration(len(lats)),
+		P50:  at(0.50),
+		P90:  at(0.90),
+		P95:  at(0.95),
+		P99:  at(0.99),
+		Max:  lats[len(lats)-1],
+	}
+}
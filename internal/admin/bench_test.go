@@ -0,0 +1,69 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+)
+
+func TestBenchClosedMeasuresOutOfOrderCompletions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cfg, rdb := newPeekFixture(t)
+	cfg.Worker.CompletedList = "jobqueue:completed"
+	rdb.LPush(ctx, cfg.Worker.CompletedList, `{"id":"earlier"}`)
+
+	// a fake worker that completes jobs in reverse batches of three and
+	// never completes the one whose index ends in 7
+	go func() {
+		var batch []string
+		for ctx.Err() == nil {
+			raw, err := rdb.RPop(ctx, "jobqueue:low_priority").Result()
+			if err != nil {
+				time.Sleep(5 * time.Millisecond)
+				continue
+			}
+			if strings.HasSuffix(mustJob(t, raw).ID, "-7") {
+				continue
+			}
+			if batch = append(batch, raw); len(batch) == 3 {
+				time.Sleep(20 * time.Millisecond)
+				for i := len(batch) - 1; i >= 0; i-- {
+					rdb.LPush(ctx, cfg.Worker.CompletedList, batch[i])
+				}
+				batch = nil
+			}
+		}
+	}()
+
+	res, err := BenchWithOptions(ctx, cfg, rdb, "low", 10, 200, 16, 300*time.Millisecond, BenchOptions{Mode: BenchClosed, PollInterval: 5 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Mode != BenchClosed || res.Completed != 9 || res.NotCompleted != 1 {
+		t.Fatalf("unexpected counts: %+v", res)
+	}
+	l := res.Latency
+	if l == nil || l.Min < 20*time.Millisecond || l.Min > l.P50 || l.P50 > l.P99 || l.P99 > l.Max || res.P95 != l.P95 {
+		t.Fatalf("unexpected latency stats: %+v", l)
+	}
+	if n := rdb.LLen(ctx, cfg.Worker.CompletedList).Val(); n != 10 {
+		t.Fatalf("closed mode must not clear the completed list, have %d", n)
+	}
+
+	if _, err := BenchWithOptions(ctx, cfg, rdb, "low", 1, 1, 1, time.Millisecond, BenchOptions{Mode: "half-open"}); err == nil {
+		t.Fatal("expected an unknown mode to be rejected")
+	}
+}
+
+func mustJob(t *testing.T, raw string) queue.Job {
+	job, err := queue.UnmarshalJob(raw)
+	if err != nil {
+		t.Error(err)
+	}
+	return job
+}
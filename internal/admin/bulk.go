@@ -0,0 +1,251 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/redis/go-redis/v9"
+)
+
+// Actions accepted by BulkJobs.
+const (
+	BulkRequeue = "requeue"
+	BulkDelete  = "delete"
+	BulkArchive = "archive"
+)
+
+const (
+	defaultBulkBatchSize   = 100
+	defaultBulkConcurrency = 4
+)
+
+// BulkOptions selects what BulkJobs does with its targets.
+type BulkOptions struct {
+	Action string
+	// Queue is the list the targets are taken from, an alias or key as for
+	// Peek; empty means the dead letter list.
+	Queue string
+	// DestQueue receives requeued jobs; empty sends each job to the queue
+	// of its priority, or the high queue when that is unknown.
+	DestQueue string
+	// ArchiveList receives archived jobs, inside the config's namespace;
+	// the archive action needs it.
+	ArchiveList string
+	BatchSize   int // targets per pipeline; default 100
+	Concurrency int // batches in flight at once; default 4
+}
+
+// BulkItemResult is the outcome for one target.
+type BulkItemResult struct {
+	Target  string `json:"target"` // job ID or member, as given
+	JobID   string `json:"job_id,omitempty"`
+	Success bool   `json:"success"`
+	Dest    string `json:"dest,omitempty"` // list the job was moved to
+	Error   string `json:"error,omitempty"`
+}
+
+// BulkResult reports a BulkJobs call. Items are in the order of the
+// targets.
+type BulkResult struct {
+	Action    string           `json:"action"`
+	Queue     string           `json:"queue"`
+	Items     []BulkItemResult `json:"items"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+}
+
+// BulkJobs requeues, deletes or archives specific items of one list. Each
+// target is either an exact list member or a job ID, matched against the
+// decoded payloads; a target that names several copies of a job takes one
+// of them. The list is read once in chunks to find the members, then the
+// items are moved in pipelined batches, several at a time. Requeue and
+// archive move each item with the same LREM-then-LPUSH script the
+// consistency repair uses, so an item another client took meanwhile is
+// reported as failed rather than duplicated. Failed items do not stop
+// the others; the error is only for bad options or failing to read the list.
+func BulkJobs(ctx context.Context, cfg *config.Config, rdb *redis.Client, targets []string, opts BulkOptions) (*BulkResult, error) {
+	switch opts.Action {
+	case BulkRequeue, BulkDelete:
+	case BulkArchive:
+		if opts.ArchiveList == "" {
+			return nil, errors.New("archive list not configured")
+		}
+	default:
+		return nil, fmt.Errorf("unknown bulk action %q; use %s, %s or %s", opts.Action, BulkRequeue, BulkDelete, BulkArchive)
+	}
+	source := cfg.Worker.DeadLetterList
+	if opts.Queue != "" {
+		var err error
+		if source, err = resolveQueue(cfg, opts.Queue); err != nil {
+			return nil, err
+		}
+	}
+	if source == "" {
+		return nil, errors.New("dead letter list not configured")
+	}
+	dest := ""
+	switch {
+	case opts.Action == BulkArchive:
+		dest = cfg.Key(opts.ArchiveList)
+	case opts.Action == BulkRequeue && opts.DestQueue != "":
+		var err error
+		if dest, err = resolveQueue(cfg, opts.DestQueue); err != nil {
+			return nil, err
+		}
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultBulkBatchSize
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = defaultBulkConcurrency
+	}
+
+	res := &BulkResult{Action: opts.Action, Queue: source, Items: make([]BulkItemResult, len(targets))}
+	members, err := findBulkMembers(ctx, rdb, source, targets)
+	if err != nil {
+		return nil, err
+	}
+
+	// work holds the indexes of the targets that were found
+	work := make([]int, 0, len(targets))
+	for i, t := range targets {
+		res.Items[i].Target = t
+		raw, ok := members[i]
+		if !ok {
+			res.Items[i].Error = "not found in " + source
+			continue
+		}
+		priority := ""
+		if job, err := queue.UnmarshalJob(decodeItem(raw)); err == nil {
+			res.Items[i].JobID, priority = job.ID, job.Priority
+		}
+		res.Items[i].Dest = dest
+		if opts.Action == BulkRequeue && dest == "" {
+			res.Items[i].Dest = priorityQueue(cfg, priority)
+		}
+		work = append(work, i)
+	}
+
+	if opts.Action != BulkDelete && len(work) > 0 {
+		if err := reclaimScript.Load(ctx, rdb).Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, opts.Concurrency)
+	for start := 0; start < len(work); start += opts.BatchSize {
+		batch := work[start:min(start+opts.BatchSize, len(work))]
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			runBulkBatch(ctx, rdb, source, opts.Action, members, batch, res.Items)
+		}()
+	}
+	wg.Wait()
+
+	for _, it := range res.Items {
+		if it.Success {
+			res.Succeeded++
+		} else {
+			res.Failed++
+		}
+	}
+	return res, nil
+}
+
+// runBulkBatch applies action to the targets at indexes batch in one
+// pipeline, recording each outcome in items.
+func runBulkBatch(ctx context.Context, rdb *redis.Client, source, action string, members map[int]string, batch []int, items []BulkItemResult) {
+	pipe := rdb.Pipeline()
+	cmds := make([]redis.Cmder, len(batch))
+	for j, i := range batch {
+		if action == BulkDelete {
+			cmds[j] = pipe.LRem(ctx, source, 1, members[i])
+		} else {
+			cmds[j] = reclaimScript.EvalSha(ctx, pipe, []string{source, items[i].Dest}, members[i])
+		}
+	}
+	_, _ = pipe.Exec(ctx) // per-command errors are reported below
+
+	for j, i := range batch {
+		var n int64
+		var err error
+		switch c := cmds[j].(type) {
+		case *redis.IntCmd:
+			n, err = c.Result()
+		case *redis.Cmd:
+			n, err = c.Int64()
+		}
+		switch {
+		case err != nil:
+			items[i].Error = err.Error()
+		case n == 0:
+			items[i].Error = "no longer in " + source
+		default:
+			items[i].Success = true
+		}
+		if !items[i].Success {
+			items[i].Dest = ""
+		}
+	}
+}
+
+// priorityQueue returns the queue jobs of priority go back to, falling back
+// to the high queue and then the low one.
+func priorityQueue(cfg *config.Config, priority string) string {
+	for _, p := range []string{priority, "high", "low"} {
+		if q := cfg.Worker.Queues[p]; q != "" {
+			return q
+		}
+	}
+	return ""
+}
+
+// findBulkMembers reads source in chunks and returns, by target index, the
+// member each target refers to: the target itself if it is a member, else
+// a member whose job ID is the target. Each member is handed out once.
+func findBulkMembers(ctx context.Context, rdb *redis.Client, source string, targets []string) (map[int]string, error) {
+	want := map[string][]int{} // target -> indexes still unresolved
+	for i, t := range targets {
+		if t != "" {
+			want[t] = append(want[t], i)
+		}
+	}
+	found := make(map[int]string, len(targets))
+	take := func(key, raw string) {
+		if idx := want[key]; len(idx) > 0 {
+			found[idx[0]] = raw
+			if want[key] = idx[1:]; len(want[key]) == 0 {
+				delete(want, key)
+			}
+		}
+	}
+
+	const chunk = 500
+	for start := int64(0); len(want) > 0; start += chunk {
+		batch, err := rdb.LRange(ctx, source, start, start+chunk-1).Result()
+		if err != nil {
+			return nil, err
+		}
+		for _, raw := range batch {
+			if _, ok := want[raw]; ok {
+				take(raw, raw)
+				continue
+			}
+			if job, err := queue.UnmarshalJob(decodeItem(raw)); err == nil && job.ID != "" {
+				take(job.ID, raw)
+			}
+		}
+		if len(batch) < chunk {
+			break
+		}
+	}
+	return found, nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+)
+
+func TestBulkJobsRequeuesByIDAndMemberWithPartialFailure(t *testing.T) {
+	ctx := context.Background()
+	cfg, rdb := newPeekFixture(t)
+	cfg.Worker.Queues["high"] = "jobqueue:high_priority"
+	cfg.Worker.DeadLetterList = "jobqueue:dead_letter"
+
+	var members []string
+	for i := 0; i < 7; i++ {
+		prio := "low"
+		if i%2 == 0 {
+			prio = "high"
+		}
+		raw, _ := queue.NewJob(fmt.Sprintf("job-%d", i), "/tmp/f", 1, prio, "", "").Marshal()
+		members = append(members, raw)
+		rdb.LPush(ctx, cfg.Worker.DeadLetterList, raw)
+	}
+
+	targets := []string{"job-0", members[1], "missing", "job-2", "job-3", "job-0"}
+	res, err := BulkJobs(ctx, cfg, rdb, targets, BulkOptions{Action: BulkRequeue, BatchSize: 2, Concurrency: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Succeeded != 4 || res.Failed != 2 || len(res.Items) != len(targets) {
+		t.Fatalf("unexpected counts: %+v", res)
+	}
+	if it := res.Items[0]; !it.Success || it.JobID != "job-0" || it.Dest != "jobqueue:high_priority" {
+		t.Fatalf("job-0 should go back to high: %+v", it)
+	}
+	if it := res.Items[1]; !it.Success || it.JobID != "job-1" || it.Dest != "jobqueue:low_priority" {
+		t.Fatalf("member target should resolve to job-1 on low: %+v", it)
+	}
+	if it := res.Items[2]; it.Success || it.Error == "" {
+		t.Fatalf("missing target should fail with a reason: %+v", it)
+	}
+	if it := res.Items[5]; it.Success || it.Dest != "" {
+		t.Fatalf("a second request for the only copy of job-0 should fail: %+v", it)
+	}
+
+	if n := rdb.LLen(ctx, cfg.Worker.DeadLetterList).Val(); n != 3 {
+		t.Fatalf("expected 3 items left in the DLQ, got %d", n)
+	}
+	if h, l := rdb.LLen(ctx, "jobqueue:high_priority").Val(), rdb.LLen(ctx, "jobqueue:low_priority").Val(); h != 2 || l != 2 {
+		t.Fatalf("expected 2 high and 2 low, got %d and %d", h, l)
+	}
+}
+
+func TestBulkJobsDeleteAndArchive(t *testing.T) {
+	ctx := context.Background()
+	cfg, rdb := newPeekFixture(t)
+	for i := 0; i < 3; i++ {
+		raw, _ := queue.NewJob(fmt.Sprintf("job-%d", i), "/tmp/f", 1, "low", "", "").Marshal()
+		rdb.LPush(ctx, "jobqueue:low_priority", raw)
+	}
+
+	res, err := BulkJobs(ctx, cfg, rdb, []string{"job-0"}, BulkOptions{Action: BulkDelete, Queue: "low"})
+	if err != nil || res.Succeeded != 1 {
+		t.Fatalf("delete: %+v, %v", res, err)
+	}
+	res, err = BulkJobs(ctx, cfg, rdb, []string{"job-1", "job-0"}, BulkOptions{Action: BulkArchive, Queue: "low", ArchiveList: "jobqueue:archive"})
+	if err != nil || res.Succeeded != 1 || res.Failed != 1 || res.Items[0].Dest != "jobqueue:archive" {
+		t.Fatalf("archive: %+v, %v", res, err)
+	}
+	if n := rdb.LLen(ctx, "jobqueue:archive").Val(); n != 1 {
+		t.Fatalf("expected 1 archived job, got %d", n)
+	}
+	if n := rdb.LLen(ctx, "jobqueue:low_priority").Val(); n != 1 {
+		t.Fatalf("expected 1 job left, got %d", n)
+	}
+
+	if _, err := BulkJobs(ctx, cfg, rdb, []string{"job-2"}, BulkOptions{Action: BulkArchive, Queue: "low"}); err == nil {
+		t.Fatal("archive without an archive list should fail")
+	}
+	if _, err := BulkJobs(ctx, cfg, rdb, []string{"job-2"}, BulkOptions{Action: "nuke", Queue: "low"}); err == nil {
+		t.Fatal("unknown action should fail")
+	}
+}
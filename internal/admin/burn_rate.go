@@ -0,0 +1,192 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/redis/go-redis/v9"
+)
+
+// Burn rate recommendations.
+const (
+	BurnRateOK     = "ok"
+	BurnRateTicket = "ticket"
+	BurnRatePage   = "page"
+)
+
+const (
+	// Default burn rate thresholds for a 30-day SLO, from the SRE
+	// workbook: 14.4x spends 2% of the budget in an hour, 6x spends 5% in
+	// six hours.
+	defaultPageBurnRate   = 14.4
+	defaultTicketBurnRate = 6
+	// burnRateShortFactor sizes the short window against the long one
+	// (5m for 1h).
+	burnRateShortFactor = 12
+	burnRateBatch       = 1000
+	// burnRateSampleMax caps how many completion events are read.
+	burnRateSampleMax = 100000
+)
+
+// SLOTarget is a latency objective such as "95% of jobs complete within
+// 30s". Dead-lettered jobs always count against it.
+type SLOTarget struct {
+	Objective float64       `json:"objective"` // fraction of good jobs, e.g. 0.95
+	Latency   time.Duration `json:"latency"`
+	// PageBurnRate and TicketBurnRate are the burn rates both windows must
+	// reach for each recommendation; defaults 14.4 and 6.
+	PageBurnRate   float64 `json:"page_burn_rate,omitempty"`
+	TicketBurnRate float64 `json:"ticket_burn_rate,omitempty"`
+}
+
+// BurnRateWindow is the error budget spend over one window.
+type BurnRateWindow struct {
+	Window    time.Duration `json:"window"`
+	Total     int           `json:"total"`
+	Bad       int           `json:"bad"`
+	ErrorRate float64       `json:"error_rate"`
+	// BurnRate is ErrorRate over the error budget (1 - Objective); 1 spends
+	// the budget exactly over the SLO period.
+	BurnRate float64 `json:"burn_rate"`
+}
+
+// BurnRateReport pairs a short and a long window for multi-window alerting.
+type BurnRateReport struct {
+	Target         SLOTarget      `json:"target"`
+	Short          BurnRateWindow `json:"short"`
+	Long           BurnRateWindow `json:"long"`
+	Recommendation string         `json:"recommendation"` // page, ticket or ok
+	// Truncated is true when the long window held more than the sample
+	// cap, so only its newest events were counted.
+	Truncated bool `json:"truncated"`
+}
+
+// BurnRate reads the worker completion stream for the last window (the
+// long window; the short one is window/12) and reports how fast each is
+// spending target's error budget. A job is bad when it was dead-lettered
+// or took longer than target.Latency from creation to completion, or to
+// finish its handler when the event carries no creation latency. The
+// recommendation is page when both windows burn at PageBurnRate or more,
+// ticket when both reach TicketBurnRate, and ok otherwise, so a short
+// spike that has ended or a slow burn that has stopped does not alert.
+func BurnRate(ctx context.Context, cfg *config.Config, rdb *redis.Client, target SLOTarget, window time.Duration) (*BurnRateReport, error) {
+	stream := cfg.Worker.CompletionStream.Stream
+	if stream == "" {
+		return nil, errors.New("burn rate needs worker.completion_stream.stream")
+	}
+	if target.Objective <= 0 || target.Objective >= 1 {
+		return nil, fmt.Errorf("slo objective must be between 0 and 1, got %v", target.Objective)
+	}
+	if target.Latency <= 0 {
+		return nil, errors.New("slo latency must be positive")
+	}
+	if window <= 0 {
+		return nil, errors.New("burn rate window must be positive")
+	}
+	if target.PageBurnRate <= 0 {
+		target.PageBurnRate = defaultPageBurnRate
+	}
+	if target.TicketBurnRate <= 0 {
+		target.TicketBurnRate = defaultTicketBurnRate
+	}
+
+	now := time.Now()
+	rep := &BurnRateReport{
+		Target: target,
+		Short:  BurnRateWindow{Window: window / burnRateShortFactor},
+		Long:   BurnRateWindow{Window: window},
+	}
+	shortStart := now.Add(-rep.Short.Window).UnixMilli()
+	start := strconv.FormatInt(now.Add(-window).UnixMilli(), 10)
+
+	// newest first, so hitting the cap keeps the most recent events
+	end := "+"
+	read := 0
+	for {
+		msgs, err := rdb.XRevRangeN(ctx, stream, end, start, burnRateBatch).Result()
+		if err != nil {
+			return nil, err
+		}
+		for _, msg := range msgs {
+			if read == burnRateSampleMax {
+				rep.Truncated = true
+				break
+			}
+			read++
+			bad, ok := burnRateBad(msg, target.Latency)
+			if !ok {
+				continue
+			}
+			for _, w := range []*BurnRateWindow{&rep.Long, &rep.Short} {
+				if w == &rep.Short && streamIDMillis(msg.ID) < shortStart {
+					continue
+				}
+				w.Total++
+				if bad {
+					w.Bad++
+				}
+			}
+		}
+		if rep.Truncated || len(msgs) < burnRateBatch {
+			break
+		}
+		end = "(" + msgs[len(msgs)-1].ID
+	}
+
+	budget := 1 - target.Objective
+	for _, w := range []*BurnRateWindow{&rep.Short, &rep.Long} {
+		if w.Total > 0 {
+			w.ErrorRate = float64(w.Bad) / float64(w.Total)
+			w.BurnRate = w.ErrorRate / budget
+		}
+	}
+	switch {
+	case rep.Short.BurnRate >= target.PageBurnRate && rep.Long.BurnRate >= target.PageBurnRate:
+		rep.Recommendation = BurnRatePage
+	case rep.Short.BurnRate >= target.TicketBurnRate && rep.Long.BurnRate >= target.TicketBurnRate:
+		rep.Recommendation = BurnRateTicket
+	default:
+		rep.Recommendation = BurnRateOK
+	}
+	return rep, nil
+}
+
+// burnRateEvent is the part of a worker.CompletionEvent BurnRate reads; the
+// worker package cannot be imported from here.
+type burnRateEvent struct {
+	Status     string `json:"status"`
+	DurationMs int64  `json:"duration_ms"`
+	LatencyMs  int64  `json:"latency_ms"`
+}
+
+// burnRateBad decodes a completion event and reports whether it misses the
+// latency objective. ok is false for entries that are not events.
+func burnRateBad(msg redis.XMessage, latency time.Duration) (bad, ok bool) {
+	raw, _ := msg.Values["event"].(string)
+	var ev burnRateEvent
+	if err := json.Unmarshal([]byte(raw), &ev); err != nil || ev.Status == "" {
+		return false, false
+	}
+	if ev.Status == "dead_letter" {
+		return true, true
+	}
+	took := ev.LatencyMs
+	if took == 0 {
+		took = ev.DurationMs
+	}
+	return time.Duration(took)*time.Millisecond > latency, true
+}
+
+// streamIDMillis returns the millisecond time part of a stream entry ID.
+func streamIDMillis(id string) int64 {
+	ms, _, _ := strings.Cut(id, "-")
+	n, _ := strconv.ParseInt(ms, 10, 64)
+	return n
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestBurnRateNeedsBothWindowsToAlert(t *testing.T) {
+	ctx := context.Background()
+	cfg, rdb := newPeekFixture(t)
+	cfg.Worker.CompletionStream.Stream = "jobqueue:completions"
+	target := SLOTarget{Objective: 0.95, Latency: 30 * time.Second}
+
+	now := time.Now()
+	seq := 0
+	add := func(n int, age time.Duration, event string) {
+		t.Helper()
+		for i := 0; i < n; i++ {
+			seq++
+			err := rdb.XAdd(ctx, &redis.XAddArgs{
+				Stream: cfg.Worker.CompletionStream.Stream,
+				ID:     fmt.Sprintf("%d-%d", now.Add(-age).UnixMilli(), seq),
+				Values: []interface{}{"event", event},
+			}).Err()
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	good := `{"status":"completed","duration_ms":100,"latency_ms":2000}`
+	slow := `{"status":"completed","duration_ms":100,"latency_ms":45000}`
+	slowHandler := `{"status":"completed","duration_ms":31000}`
+	dead := `{"status":"dead_letter","duration_ms":10}`
+
+	add(50, 2*time.Hour, dead) // outside the long window
+	add(100, 40*time.Minute, good)
+	add(1, 40*time.Minute, "not json")
+	add(3, 4*time.Minute, slow)
+	add(1, 3*time.Minute, slowHandler)
+	add(1, 2*time.Minute, dead)
+	add(5, time.Minute, good)
+
+	check := func(want string, short, long BurnRateWindow) {
+		t.Helper()
+		rep, err := BurnRate(ctx, cfg, rdb, target, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rep.Recommendation != want || rep.Short.Total != short.Total || rep.Short.Bad != short.Bad ||
+			rep.Long.Total != long.Total || rep.Long.Bad != long.Bad || rep.Short.Window != 5*time.Minute {
+			t.Fatalf("expected %s with short %d/%d and long %d/%d, got %+v", want, short.Bad, short.Total, long.Bad, long.Total, rep)
+		}
+	}
+
+	// a short spike alone does not alert
+	check(BurnRateOK, BurnRateWindow{Total: 10, Bad: 5}, BurnRateWindow{Total: 110, Bad: 5})
+
+	add(60, 30*time.Second, dead)
+	check(BurnRateTicket, BurnRateWindow{Total: 70, Bad: 65}, BurnRateWindow{Total: 170, Bad: 65})
+
+	// more than one read batch
+	add(1000, 10*time.Second, slow)
+	rep, err := BurnRate(ctx, cfg, rdb, target, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Recommendation != BurnRatePage || rep.Long.Total != 1170 || rep.Long.Bad != 1065 || rep.Truncated {
+		t.Fatalf("expected page over 1065/1170, got %+v", rep)
+	}
+	if want := 1065.0 / 1170 / 0.05; rep.Long.BurnRate < want-1e-9 || rep.Long.BurnRate > want+1e-9 {
+		t.Fatalf("expected long burn rate %v, got %v", want, rep.Long.BurnRate)
+	}
+
+	cfg.Worker.CompletionStream.Stream = ""
+	if _, err := BurnRate(ctx, cfg, rdb, target, time.Hour); err == nil {
+		t.Fatal("expected an error without a completion stream")
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/obs"
+	"github.com/redis/go-redis/v9"
+)
+
+// trimChunk is how many completed entries TrimCompleted reads per round
+// trip when trimming by age.
+const trimChunk = 500
+
+// TrimResult counts the completed entries TrimCompleted removed.
+type TrimResult struct {
+	ByCount int64 `json:"by_count"`
+	ByAge   int64 `json:"by_age"`
+}
+
+// TrimCompleted applies a retention policy to the completed list: it keeps
+// the keepLast newest entries (0 keeps any number), then removes every
+// entry whose job was created more than olderThan ago (0 skips this).
+// Completions are pushed in completion order, not creation order, so the
+// whole list is checked, from the old end, and an old entry is removed
+// wherever it sits. Entries without a readable creation_time are kept.
+// Workers push while it runs; only entries it has read are removed.
+func TrimCompleted(ctx context.Context, cfg *config.Config, rdb *redis.Client, keepLast int, olderThan time.Duration) (TrimResult, error) {
+	var res TrimResult
+	list := cfg.Worker.CompletedList
+	if list == "" {
+		return res, errors.New("completed list not configured")
+	}
+
+	if keepLast > 0 {
+		var llen *redis.IntCmd
+		// LTRIM keeps the head, where entries are pushed
+		_, err := rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			llen = pipe.LLen(ctx, list)
+			pipe.LTrim(ctx, list, 0, int64(keepLast)-1)
+			return nil
+		})
+		if err != nil {
+			return res, err
+		}
+		res.ByCount = max(0, llen.Val()-int64(keepLast))
+		obs.CompletedTrimmed.WithLabelValues("count").Add(float64(res.ByCount))
+	}
+
+	if olderThan > 0 {
+		cutoff := time.Now().Add(-olderThan)
+		n, err := trimCompletedByAge(ctx, rdb, list, cutoff)
+		res.ByAge = n
+		obs.CompletedTrimmed.WithLabelValues("age").Add(float64(n))
+		if err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// trimCompletedByAge walks list from the tail, trimChunk entries at a time,
+// and removes entries created before cutoff. Indexes count from the tail,
+// which pushes to the head do not move; kept counts the entries already
+// read and left in place.
+func trimCompletedByAge(ctx context.Context, rdb *redis.Client, list string, cutoff time.Time) (int64, error) {
+	var removed, kept int64
+	for {
+		items, err := rdb.LRange(ctx, list, -(kept + trimChunk), -(kept + 1)).Result()
+		if err != nil {
+			return removed, err
+		}
+		var old []string
+		for _, it := range items {
+			if completedBefore(it, cutoff) {
+				old = append(old, it)
+			}
+		}
+		if len(old) > 0 {
+			cmds := make([]*redis.IntCmd, len(old))
+			// LREM with a negative count searches from the tail, where
+			// these entries are
+			_, err := rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+				for i, it := range old {
+					cmds[i] = pipe.LRem(ctx, list, -1, it)
+				}
+				return nil
+			})
+			for _, cmd := range cmds {
+				removed += cmd.Val()
+			}
+			if err != nil {
+				return removed, err
+			}
+		}
+		kept += int64(len(items) - len(old))
+		if len(items) < trimChunk {
+			return removed, nil
+		}
+	}
+}
+
+// completedBefore reports whether the job in a completed entry was created
+// before cutoff.
+func completedBefore(item string, cutoff time.Time) bool {
+	var j struct {
+		CreationTime string `json:"creation_time"`
+	}
+	if json.Unmarshal([]byte(decodeItem(item)), &j) != nil {
+		return false
+	}
+	t, err := time.Parse(time.RFC3339Nano, j.CreationTime)
+	return err == nil && t.Before(cutoff)
+}
@@ -0,0 +1,84 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+)
+
+func TestTrimCompletedByCountAndAge(t *testing.T) {
+	ctx := context.Background()
+	cfg, rdb := newPeekFixture(t)
+	cfg.Worker.CompletedList = "jobqueue:completed"
+	now := time.Now()
+
+	// pushed in completion order, oldest push at the tail; creation times
+	// are out of order, as a long job completes after newer ones
+	ages := []time.Duration{3 * time.Hour, time.Minute, 5 * time.Hour, 2 * time.Minute, 4 * time.Hour, time.Second}
+	for i, age := range ages {
+		job := queue.NewJob(fmt.Sprintf("j%d", i), "/tmp/f", 1, "low", "", "")
+		job.CreationTime = now.Add(-age).UTC().Format(time.RFC3339Nano)
+		payload, _ := job.Marshal()
+		rdb.LPush(ctx, cfg.Worker.CompletedList, payload)
+	}
+	rdb.LPush(ctx, cfg.Worker.CompletedList, "not json")
+
+	res, err := TrimCompleted(ctx, cfg, rdb, 6, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// j0 falls off by count; j2 and j4 are old wherever they sit
+	if res.ByCount != 1 || res.ByAge != 2 {
+		t.Fatalf("expected 1 by count and 2 by age, got %+v", res)
+	}
+	items, _ := rdb.LRange(ctx, cfg.Worker.CompletedList, 0, -1).Result()
+	var ids []string
+	for _, it := range items {
+		if it == "not json" {
+			ids = append(ids, it)
+			continue
+		}
+		ids = append(ids, mustJob(t, it).ID)
+	}
+	if got := fmt.Sprint(ids); got != "[not json j5 j3 j1]" {
+		t.Fatalf("unexpected entries left: %s", got)
+	}
+
+	if res, err := TrimCompleted(ctx, cfg, rdb, 0, 0); err != nil || res != (TrimResult{}) {
+		t.Fatalf("expected a no-op without bounds, got %+v (%v)", res, err)
+	}
+}
+
+func TestTrimCompletedByAgeAcrossChunks(t *testing.T) {
+	ctx := context.Background()
+	cfg, rdb := newPeekFixture(t)
+	cfg.Worker.CompletedList = "jobqueue:completed"
+	old := time.Now().Add(-48 * time.Hour).UTC().Format(time.RFC3339Nano)
+
+	const total = 2*trimChunk + 37
+	want := 0
+	for i := 0; i < total; i++ {
+		job := queue.NewJob(fmt.Sprintf("j%d", i), "/tmp/f", 1, "low", "", "")
+		if i%3 == 0 {
+			job.CreationTime = old
+			want++
+		}
+		payload, _ := job.Marshal()
+		rdb.LPush(ctx, cfg.Worker.CompletedList, payload)
+	}
+
+	res, err := TrimCompleted(ctx, cfg, rdb, 0, 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.ByAge != int64(want) {
+		t.Fatalf("expected %d removed by age, got %+v", want, res)
+	}
+	if n, _ := rdb.LLen(ctx, cfg.Worker.CompletedList).Result(); n != int64(total-want) {
+		t.Fatalf("expected %d entries left, got %d", total-want, n)
+	}
+}
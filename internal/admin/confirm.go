@@ -0,0 +1,46 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ConfirmDestructive asks the operator at the terminal to type expected,
+// such as the queue or namespace about to be destroyed, before a
+// destructive command runs. prompt says what will happen. It reports
+// whether the exact text was typed; when stdin is not a terminal it does
+// not prompt and returns false, so scripts must pass --yes instead.
+func ConfirmDestructive(prompt, expected string) bool {
+	if !stdinIsTerminal() {
+		return false
+	}
+	return confirmTyped(os.Stdin, os.Stderr, prompt, expected)
+}
+
+// confirmTyped writes prompt to out and reads one line from in, which must
+// be expected exactly, surrounding spaces aside.
+func confirmTyped(in io.Reader, out io.Writer, prompt, expected string) bool {
+	if expected == "" {
+		return false
+	}
+	fmt.Fprintf(out, "%s\nThis cannot be undone. Type %q to confirm: ", prompt, expected)
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && line == "" {
+		fmt.Fprintln(out)
+		return false
+	}
+	if strings.TrimSpace(line) != expected {
+		fmt.Fprintln(out, "Confirmation did not match; nothing was changed.")
+		return false
+	}
+	return true
+}
+
+func stdinIsTerminal() bool {
+	fi, err := os.Stdin.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
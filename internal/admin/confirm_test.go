@@ -0,0 +1,34 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestConfirmTypedRequiresExactName(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected string
+		want     bool
+	}{
+		{"jobqueue:dead_letter\n", "jobqueue:dead_letter", true},
+		{"  jobqueue:dead_letter  \r\n", "jobqueue:dead_letter", true},
+		{"jobqueue:dead_letter", "jobqueue:dead_letter", true}, // EOF without newline
+		{"jobqueue:dead_lettr\n", "jobqueue:dead_letter", false},
+		{"JOBQUEUE:DEAD_LETTER\n", "jobqueue:dead_letter", false},
+		{"y\n", "jobqueue:dead_letter", false},
+		{"", "jobqueue:dead_letter", false},
+		{"\n", "", false},
+	}
+	for _, tc := range cases {
+		var out bytes.Buffer
+		if got := confirmTyped(strings.NewReader(tc.input), &out, "Purge the dead letter list.", tc.expected); got != tc.want {
+			t.Errorf("input %q for %q: got %v, want %v", tc.input, tc.expected, got, tc.want)
+		}
+		if tc.expected != "" && !strings.Contains(out.String(), `Type "`+tc.expected+`" to confirm`) {
+			t.Errorf("prompt should name %q, got %q", tc.expected, out.String())
+		}
+	}
+}
@@ -0,0 +1,376 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/redis/go-redis/v9"
+)
+
+// Consistency issue kinds.
+const (
+	IssueDuplicate = "duplicate" // the same job ID in more than one place
+	IssueOrphaned  = "orphaned"  // in a processing list with no live heartbeat
+	IssueMalformed = "malformed" // not a job payload, or one without an ID
+)
+
+const (
+	// consistencyChunk is how many list items are read per LRANGE.
+	consistencyChunk = 500
+	// maxConsistencyIssues caps the issues listed in a report; the counts
+	// always cover everything found.
+	maxConsistencyIssues = 1000
+	// maxIssueSample bounds the payload excerpt kept for malformed items.
+	maxIssueSample = 120
+)
+
+// ConsistencyIssue is one problem found by VerifyConsistency.
+type ConsistencyIssue struct {
+	Kind  string `json:"kind"`
+	Key   string `json:"key"`
+	JobID string `json:"job_id,omitempty"`
+	// AlsoIn lists the other keys holding a duplicated job ID.
+	AlsoIn []string `json:"also_in,omitempty"`
+	Sample string   `json:"sample,omitempty"`
+}
+
+// ConsistencyReport is the machine-readable result of VerifyConsistency.
+// Healthy is false whenever any issue was found, so CI can gate on it.
+type ConsistencyReport struct {
+	CheckedAt       time.Time          `json:"checked_at"`
+	Healthy         bool               `json:"healthy"`
+	QueuesScanned   int                `json:"queues_scanned"`
+	ProcessingLists int                `json:"processing_lists"`
+	ItemsScanned    int64              `json:"items_scanned"`
+	Duplicates      int                `json:"duplicates"`
+	Orphaned        int                `json:"orphaned"`
+	Malformed       int                `json:"malformed"`
+	Issues          []ConsistencyIssue `json:"issues"`
+	IssuesTruncated bool               `json:"issues_truncated,omitempty"`
+}
+
+// RepairResult reports what RepairConsistency changed.
+type RepairResult struct {
+	Before             *ConsistencyReport `json:"before"`
+	DuplicatesRemoved  int                `json:"duplicates_removed"`
+	OrphansReclaimed   int                `json:"orphans_reclaimed"`
+	OrphansDropped     int                `json:"orphans_dropped"` // orphan copies of a job that was also queued or running
+	MalformedLeftAlone int                `json:"malformed_left_alone"`
+}
+
+// consistencyList is a live queue or a worker's processing list.
+type consistencyList struct {
+	key        string
+	processing bool
+	live       bool // processing list whose worker still has a heartbeat
+}
+
+// occurrence is one copy of a job found during the detail pass.
+type occurrence struct {
+	list    *consistencyList
+	payload string
+}
+
+// consistencyScan holds what the detail pass needs for reporting and repair.
+type consistencyScan struct {
+	report  *ConsistencyReport
+	byID    map[string][]occurrence // duplicated IDs only
+	orphans []occurrence            // entries of orphaned lists whose ID is unique
+}
+
+// reclaimScript moves one copy of ARGV[1] from the processing list KEYS[1]
+// to the queue KEYS[2], doing nothing if the copy is already gone (the
+// reaper or the worker got there first).
+var reclaimScript = redis.NewScript(`
+if redis.call('LREM', KEYS[1], 1, ARGV[1]) > 0 then
+  redis.call('LPUSH', KEYS[2], ARGV[1])
+  return 1
+end
+return 0
+`)
+
+// VerifyConsistency scans the configured queues and every processing list
+// for job IDs held in more than one place, entries of processing lists
+// whose worker has no heartbeat, and items that are not job payloads.
+// Lists are read in chunks and keys found with SCAN; the first pass keeps
+// only a small hash per job ID, and payloads are kept only for the items a
+// repair would touch. The queues are live, so a busy system can show
+// transient results; run it twice before acting on a single finding.
+func VerifyConsistency(ctx context.Context, cfg *config.Config, rdb *redis.Client) (*ConsistencyReport, error) {
+	scan, err := scanConsistency(ctx, cfg, rdb)
+	if err != nil {
+		return nil, err
+	}
+	return scan.report, nil
+}
+
+// RepairConsistency runs VerifyConsistency and fixes what it found. Of a
+// duplicated job ID one copy is kept, preferring a running copy, then a
+// queued one, then an orphaned one; the others are removed. Orphaned
+// entries, including a kept orphaned copy, go back to their priority queue
+// as the reaper would do. Malformed items are left in place for
+// inspection. The CLI only calls it with --yes.
+func RepairConsistency(ctx context.Context, cfg *config.Config, rdb *redis.Client) (*RepairResult, error) {
+	scan, err := scanConsistency(ctx, cfg, rdb)
+	if err != nil {
+		return nil, err
+	}
+	res := &RepairResult{Before: scan.report, MalformedLeftAlone: scan.report.Malformed}
+
+	reclaim := scan.orphans
+	ids := make([]string, 0, len(scan.byID))
+	for id := range scan.byID {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		occ := scan.byID[id]
+		keep := 0
+		for i, o := range occ {
+			if occurrenceRank(o) > occurrenceRank(occ[keep]) {
+				keep = i
+			}
+		}
+		for i, o := range occ {
+			if i == keep {
+				continue
+			}
+			n, err := rdb.LRem(ctx, o.list.key, 1, o.payload).Result()
+			if err != nil {
+				return res, fmt.Errorf("remove duplicate %s from %s: %w", id, o.list.key, err)
+			}
+			if n == 0 {
+				continue
+			}
+			if o.list.processing && !o.list.live {
+				res.OrphansDropped++
+			} else {
+				res.DuplicatesRemoved++
+			}
+		}
+		// The surviving copy may itself be orphaned.
+		if kept := occ[keep]; kept.list.processing && !kept.list.live {
+			reclaim = append(reclaim, kept)
+		}
+	}
+
+	for _, o := range reclaim {
+		job, _ := queue.UnmarshalJob(o.payload)
+		dest := cfg.Worker.Queues[job.Priority]
+		if dest == "" {
+			dest = cfg.Worker.Queues[cfg.Producer.DefaultPriority]
+		}
+		if dest == "" {
+			continue
+		}
+		n, err := reclaimScript.Run(ctx, rdb, []string{o.list.key, dest}, o.payload).Int()
+		if err != nil {
+			return res, fmt.Errorf("reclaim %s from %s: %w", job.ID, o.list.key, err)
+		}
+		res.OrphansReclaimed += n
+	}
+	return res, nil
+}
+
+// occurrenceRank orders the copies of a duplicated job by which to keep.
+func occurrenceRank(o occurrence) int {
+	switch {
+	case o.list.processing && o.list.live:
+		return 2
+	case !o.list.processing:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func scanConsistency(ctx context.Context, cfg *config.Config, rdb *redis.Client) (*consistencyScan, error) {
+	lists, err := consistencyLists(ctx, cfg, rdb)
+	if err != nil {
+		return nil, err
+	}
+	rep := &ConsistencyReport{CheckedAt: time.Now().UTC(), Issues: []ConsistencyIssue{}}
+	for _, l := range lists {
+		if l.processing {
+			rep.ProcessingLists++
+		} else {
+			rep.QueuesScanned++
+		}
+	}
+
+	// Pass 1: count every job ID by hash. Counts saturate at 2; that is all
+	// duplicate detection needs.
+	counts := map[uint64]uint8{}
+	for _, l := range lists {
+		err := eachListItem(ctx, rdb, l.key, func(item string) {
+			rep.ItemsScanned++
+			job, err := queue.UnmarshalJob(item)
+			if err != nil || job.ID == "" {
+				rep.Malformed++
+				addIssue(rep, ConsistencyIssue{Kind: IssueMalformed, Key: l.key, Sample: truncateSample(item)})
+				return
+			}
+			if h := hashID(job.ID); counts[h] < 2 {
+				counts[h]++
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Pass 2: collect the copies of duplicated IDs and the orphaned entries.
+	scan := &consistencyScan{report: rep, byID: map[string][]occurrence{}}
+	for _, l := range lists {
+		err := eachListItem(ctx, rdb, l.key, func(item string) {
+			job, err := queue.UnmarshalJob(item)
+			if err != nil || job.ID == "" {
+				return
+			}
+			o := occurrence{list: l, payload: item}
+			if counts[hashID(job.ID)] > 1 {
+				scan.byID[job.ID] = append(scan.byID[job.ID], o)
+			} else if l.processing && !l.live {
+				scan.orphans = append(scan.orphans, o)
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ids := make([]string, 0, len(scan.byID))
+	for id := range scan.byID {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		occ := scan.byID[id]
+		if len(occ) < 2 {
+			// A hash collision, or the job moved between passes.
+			if o := occ[0]; o.list.processing && !o.list.live {
+				scan.orphans = append(scan.orphans, o)
+			}
+			delete(scan.byID, id)
+			continue
+		}
+		rep.Duplicates++
+		keys := make([]string, 0, len(occ))
+		for _, o := range occ {
+			keys = append(keys, o.list.key)
+		}
+		addIssue(rep, ConsistencyIssue{Kind: IssueDuplicate, Key: keys[0], JobID: id, AlsoIn: keys[1:]})
+	}
+	for _, o := range scan.orphans {
+		rep.Orphaned++
+		job, _ := queue.UnmarshalJob(o.payload)
+		addIssue(rep, ConsistencyIssue{Kind: IssueOrphaned, Key: o.list.key, JobID: job.ID})
+	}
+
+	rep.Healthy = rep.Duplicates == 0 && rep.Orphaned == 0 && rep.Malformed == 0
+	return scan, nil
+}
+
+// consistencyLists returns the configured queues followed by every
+// processing list found with SCAN, each marked live or not by its worker's
+// heartbeat.
+func consistencyLists(ctx context.Context, cfg *config.Config, rdb *redis.Client) ([]*consistencyList, error) {
+	var lists []*consistencyList
+	seen := map[string]bool{}
+	for _, p := range sortedQueueNames(cfg) {
+		key := cfg.Worker.Queues[p]
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		lists = append(lists, &consistencyList{key: key})
+	}
+
+	pattern := cfg.Worker.ProcessingListPattern
+	if pattern == "" || !strings.Contains(pattern, "%s") {
+		pattern = cfg.Key(defaultProcessingPattern)
+	}
+	prefix, suffix, _ := strings.Cut(pattern, "%s")
+	var procs []string
+	var cursor uint64
+	for {
+		keys, cur, err := rdb.Scan(ctx, cursor, prefix+"*"+suffix, 500).Result()
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range keys {
+			if !seen[k] && strings.HasPrefix(k, prefix) && strings.HasSuffix(k, suffix) && len(k) > len(prefix)+len(suffix) {
+				seen[k] = true
+				procs = append(procs, k)
+			}
+		}
+		cursor = cur
+		if cursor == 0 {
+			break
+		}
+	}
+	sort.Strings(procs)
+
+	for _, k := range procs {
+		workerID := k[len(prefix) : len(k)-len(suffix)]
+		n, err := rdb.Exists(ctx, fmt.Sprintf(cfg.Worker.HeartbeatKeyPattern, workerID)).Result()
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, &consistencyList{key: k, processing: true, live: n > 0})
+	}
+	return lists, nil
+}
+
+// eachListItem calls fn for every item of key, reading it in chunks.
+func eachListItem(ctx context.Context, rdb *redis.Client, key string, fn func(string)) error {
+	for start := int64(0); ; start += consistencyChunk {
+		items, err := rdb.LRange(ctx, key, start, start+consistencyChunk-1).Result()
+		if err != nil {
+			return err
+		}
+		for _, it := range items {
+			fn(it)
+		}
+		if len(items) < consistencyChunk {
+			return nil
+		}
+	}
+}
+
+func sortedQueueNames(cfg *config.Config) []string {
+	names := make([]string, 0, len(cfg.Worker.Queues))
+	for p := range cfg.Worker.Queues {
+		names = append(names, p)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func addIssue(rep *ConsistencyReport, issue ConsistencyIssue) {
+	if len(rep.Issues) >= maxConsistencyIssues {
+		rep.IssuesTruncated = true
+		return
+	}
+	rep.Issues = append(rep.Issues, issue)
+}
+
+func hashID(id string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(id))
+	return h.Sum64()
+}
+
+func truncateSample(s string) string {
+	if len(s) <= maxIssueSample {
+		return s
+	}
+	return s[:maxIssueSample] + "..."
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+)
+
+func TestVerifyAndRepairConsistency(t *testing.T) {
+	ctx := context.Background()
+	cfg, rdb := newPeekFixture(t)
+	cfg.Worker.Queues["high"] = "jobqueue:high_priority"
+	cfg.Worker.ProcessingListPattern = "jobqueue:worker:%s:processing"
+	cfg.Worker.HeartbeatKeyPattern = "jobqueue:processing:worker:%s"
+
+	push := func(key, id, prio string) {
+		t.Helper()
+		payload, _ := queue.NewJob(id, "/tmp/f", 1, prio, "", "").Marshal()
+		if err := rdb.LPush(ctx, key, payload).Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	low := cfg.Worker.Queues["low"]
+	live := "jobqueue:worker:live:processing"
+	dead := "jobqueue:worker:dead:processing"
+	if err := rdb.Set(ctx, "jobqueue:processing:worker:live", "x", time.Minute).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	push(low, "ok", "low")
+	push(low, "running", "low") // also held by a live worker
+	push(live, "running", "low")
+	push(low, "twice", "low") // queued twice
+	push(low, "twice", "low")
+	push(dead, "lost", "high") // dead worker, unique ID
+	push(dead, "stale", "low") // dead worker, but also queued
+	push(low, "stale", "low")
+	if err := rdb.LPush(ctx, low, "not json").Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	rep, err := VerifyConsistency(ctx, cfg, rdb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Healthy || rep.Duplicates != 3 || rep.Orphaned != 1 || rep.Malformed != 1 {
+		t.Fatalf("unexpected report: %+v", rep)
+	}
+	if rep.QueuesScanned != 2 || rep.ProcessingLists != 2 || rep.ItemsScanned != 9 {
+		t.Fatalf("unexpected scan counts: %+v", rep)
+	}
+	if _, err := json.Marshal(rep); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := RepairConsistency(ctx, cfg, rdb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.DuplicatesRemoved != 2 || res.OrphansDropped != 1 || res.OrphansReclaimed != 1 || res.MalformedLeftAlone != 1 {
+		t.Fatalf("unexpected repair result: %+v", res)
+	}
+	if n, _ := rdb.LLen(ctx, live).Result(); n != 1 {
+		t.Fatalf("expected the running copy to be kept, got %d", n)
+	}
+	if n, _ := rdb.LLen(ctx, cfg.Worker.Queues["high"]).Result(); n != 1 {
+		t.Fatalf("expected the lost job reclaimed to high, got %d", n)
+	}
+
+	after, err := VerifyConsistency(ctx, cfg, rdb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.Duplicates != 0 || after.Orphaned != 0 || after.Malformed != 1 || after.Healthy {
+		t.Fatalf("expected only the malformed item left, got %+v", after)
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/redis/go-redis/v9"
+)
+
+// defaultDLQSample is used when DLQAnalytics is called with sample <= 0.
+const defaultDLQSample = 100
+
+// unknownBucket collects items missing the grouped field.
+const unknownBucket = "unknown"
+
+// DLQBucket is one row of a DLQReport breakdown.
+type DLQBucket struct {
+	Name    string  `json:"name"`
+	Count   int     `json:"count"`
+	Percent float64 `json:"percent"`
+	// TopQueue is the queue contributing most items to a reason bucket.
+	TopQueue string `json:"top_queue,omitempty"`
+}
+
+// DLQReport summarizes a sample of the dead letter queue.
+type DLQReport struct {
+	Queue       string      `json:"queue"`
+	Total       int64       `json:"total"`
+	Sampled     int         `json:"sampled"`
+	ReasonField string      `json:"reason_field"`
+	Reasons     []DLQBucket `json:"reasons"`
+	Queues      []DLQBucket `json:"queues"`
+	Ages        []DLQBucket `json:"ages"`
+	Summary     string      `json:"summary,omitempty"`
+}
+
+// dlqAgeBuckets are the upper bounds of the age distribution, in order.
+var dlqAgeBuckets = []struct {
+	name string
+	max  time.Duration
+}{
+	{"<1m", time.Minute},
+	{"1m-1h", time.Hour},
+	{"1h-24h", 24 * time.Hour},
+	{"1d-7d", 7 * 24 * time.Hour},
+	{">7d", 0},
+}
+
+// DLQAnalytics samples up to sample of the most recently dead-lettered items
+// and groups them by cfg.Worker.DeadLetterReasonField (a dotted path, "error"
+// by default), by originating queue and by age. Items that are not JSON or
+// lack a field are counted under "unknown".
+func DLQAnalytics(ctx context.Context, cfg *config.Config, rdb *redis.Client, sample int) (*DLQReport, error) {
+	if cfg.Worker.DeadLetterList == "" {
+		return nil, errors.New("dead letter list not configured")
+	}
+	if sample <= 0 {
+		sample = defaultDLQSample
+	}
+	field := cfg.Worker.DeadLetterReasonField
+	if field == "" {
+		field = "error"
+	}
+
+	total, err := rdb.LLen(ctx, cfg.Worker.DeadLetterList).Result()
+	if err != nil {
+		return nil, err
+	}
+	// Dead-lettered jobs are LPUSHed, so the head holds the newest.
+	items, err := rdb.LRange(ctx, cfg.Worker.DeadLetterList, 0, int64(sample)-1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	reasons := map[string]int{}
+	queues := map[string]int{}
+	reasonQueues := map[string]map[string]int{}
+	ages := map[string]int{}
+	now := time.Now()
+	path := strings.Split(field, ".")
+	for _, item := range items {
+		var doc map[string]interface{}
+		_ = json.Unmarshal([]byte(decodeItem(item)), &doc)
+
+		reason := unknownBucket
+		if v, ok := lookupField(doc, path); ok {
+			if s := strings.TrimSpace(fmt.Sprint(v)); s != "" {
+				reason = s
+			}
+		}
+		q := dlqItemQueue(cfg, doc)
+		reasons[reason]++
+		queues[q]++
+		if reasonQueues[reason] == nil {
+			reasonQueues[reason] = map[string]int{}
+		}
+		reasonQueues[reason][q]++
+		ages[dlqItemAge(doc, now)]++
+	}
+
+	rep := &DLQReport{
+		Queue:       cfg.Worker.DeadLetterList,
+		Total:       total,
+		Sampled:     len(items),
+		ReasonField: field,
+		Reasons:     rankBuckets(reasons, len(items)),
+		Queues:      rankBuckets(queues, len(items)),
+	}
+	for i := range rep.Reasons {
+		if top := rankBuckets(reasonQueues[rep.Reasons[i].Name], 0); len(top) > 0 {
+			rep.Reasons[i].TopQueue = top[0].Name
+		}
+	}
+	for _, b := range dlqAgeBuckets {
+		rep.Ages = append(rep.Ages, DLQBucket{Name: b.name, Count: ages[b.name], Percent: percentOf(ages[b.name], len(items))})
+	}
+	if n := ages[unknownBucket]; n > 0 {
+		rep.Ages = append(rep.Ages, DLQBucket{Name: unknownBucket, Count: n, Percent: percentOf(n, len(items))})
+	}
+	if len(rep.Reasons) > 0 {
+		top := rep.Reasons[0]
+		rep.Summary = fmt.Sprintf("%.0f%% of sampled failures are %q, mostly on %s", top.Percent, top.Name, top.TopQueue)
+	}
+	return rep, nil
+}
+
+// lookupField walks a dotted path through nested JSON objects.
+func lookupField(doc map[string]interface{}, path []string) (interface{}, bool) {
+	var cur interface{} = doc
+	for _, p := range path {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = m[p]; !ok || cur == nil {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// dlqItemQueue reports an explicit "queue" field, else the configured queue
+// for the job's priority.
+func dlqItemQueue(cfg *config.Config, doc map[string]interface{}) string {
+	if q, ok := doc["queue"].(string); ok && q != "" {
+		return q
+	}
+	if p, ok := doc["priority"].(string); ok && p != "" {
+		if q, ok := cfg.Worker.Queues[p]; ok {
+			return q
+		}
+		return p
+	}
+	return unknownBucket
+}
+
+// dlqItemAge buckets an item by the time since its creation_time.
+func dlqItemAge(doc map[string]interface{}, now time.Time) string {
+	raw, _ := doc["creation_time"].(string)
+	created, err := time.Parse(time.RFC3339Nano, raw)
+	if err != nil {
+		return unknownBucket
+	}
+	age := now.Sub(created)
+	for _, b := range dlqAgeBuckets {
+		if b.max == 0 || age < b.max {
+			return b.name
+		}
+	}
+	return unknownBucket
+}
+
+// rankBuckets orders counts by descending count, then name.
+func rankBuckets(counts map[string]int, total int) []DLQBucket {
+	out := make([]DLQBucket, 0, len(counts))
+	for name, n := range counts {
+		out = append(out, DLQBucket{Name: name, Count: n, Percent: percentOf(n, total)})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+func percentOf(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) * 100 / float64(total)
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDLQAnalyticsGroupsReasonsQueuesAndAges(t *testing.T) {
+	ctx := context.Background()
+	cfg, rdb := newPeekFixture(t)
+	cfg.Worker.Queues["high"] = "jobqueue:high_priority"
+	cfg.Worker.DeadLetterList = "jobqueue:dead_letter"
+	cfg.Worker.DeadLetterReasonField = "failure.reason"
+
+	now := time.Now().UTC()
+	push := func(priority, reason string, age time.Duration) {
+		doc := map[string]interface{}{
+			"id":            "job",
+			"priority":      priority,
+			"creation_time": now.Add(-age).Format(time.RFC3339Nano),
+		}
+		if reason != "" {
+			doc["failure"] = map[string]interface{}{"reason": reason}
+		}
+		b, _ := json.Marshal(doc)
+		if err := rdb.LPush(ctx, cfg.Worker.DeadLetterList, b).Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 8; i++ {
+		push("low", "timeout", 30*time.Second)
+	}
+	push("high", "timeout", 2*time.Hour)
+	push("high", "", 10*24*time.Hour)
+	if err := rdb.LPush(ctx, cfg.Worker.DeadLetterList, "not json").Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	rep, err := DLQAnalytics(ctx, cfg, rdb, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Total != 11 || rep.Sampled != 10 {
+		t.Fatalf("total=%d sampled=%d", rep.Total, rep.Sampled)
+	}
+	// The sample holds the 10 newest items: the oldest timeout drops out.
+	if len(rep.Reasons) != 2 || rep.Reasons[0].Name != "timeout" || rep.Reasons[0].Count != 8 || rep.Reasons[0].TopQueue != "jobqueue:low_priority" {
+		t.Fatalf("unexpected reasons: %+v", rep.Reasons)
+	}
+	if rep.Reasons[1].Name != "unknown" || rep.Reasons[1].Count != 2 {
+		t.Fatalf("items without a reason should be unknown: %+v", rep.Reasons)
+	}
+	if rep.Queues[0].Name != "jobqueue:low_priority" || rep.Queues[0].Percent != 70 {
+		t.Fatalf("unexpected queues: %+v", rep.Queues)
+	}
+	ages := map[string]int{}
+	for _, b := range rep.Ages {
+		ages[b.Name] = b.Count
+	}
+	if ages["<1m"] != 7 || ages["1h-24h"] != 1 || ages[">7d"] != 1 || ages["unknown"] != 1 {
+		t.Fatalf("unexpected ages: %+v", rep.Ages)
+	}
+	if !strings.Contains(rep.Summary, `"timeout"`) {
+		t.Fatalf("unexpected summary: %q", rep.Summary)
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/redis/go-redis/v9"
+)
+
+// drainWindow is how long TimeToDrain samples a queue's flow.
+var drainWindow = 5 * time.Second
+
+// drainConfidence is the coverage of a DrainEstimate's range, and drainZ
+// the matching normal quantile.
+const (
+	drainConfidence = 0.90
+	drainZ          = 1.645
+)
+
+// DrainEstimate is when a queue will empty at its current flow.
+type DrainEstimate struct {
+	Priority       string  `json:"priority"`
+	Queue          string  `json:"queue"`
+	Length         int64   `json:"length"`
+	EnqueuePerSec  float64 `json:"enqueue_per_sec"`
+	CompletePerSec float64 `json:"complete_per_sec"`
+	// NetPerSec is CompletePerSec minus EnqueuePerSec; NetStdDev is the
+	// spread of the per-sample net rate it averages.
+	NetPerSec float64       `json:"net_per_sec"`
+	NetStdDev float64       `json:"net_stddev"`
+	Window    time.Duration `json:"window"`
+	Samples   int           `json:"samples"`
+	// Drains is false when the backlog is not shrinking; Growing says
+	// that is because jobs arrive faster than they complete, rather than
+	// nothing moving at all.
+	Drains  bool `json:"drains"`
+	Growing bool `json:"growing"`
+	// Estimate is the time to empty the queue at NetPerSec. Low and High
+	// bound it at Confidence given the net rate's variance; when the slow
+	// end of that range does not drain, High is 0 and HighUnbounded set.
+	Estimate      time.Duration `json:"estimate"`
+	Low           time.Duration `json:"low"`
+	High          time.Duration `json:"high"`
+	HighUnbounded bool          `json:"high_unbounded"`
+	Confidence    float64       `json:"confidence"`
+	DrainsAt      *time.Time    `json:"drains_at,omitempty"`
+	// Summary is the estimate for display: "~4m10s (3m20s to 5m30s)",
+	// "never (growing)", "never (stalled)" or "empty".
+	Summary string `json:"summary"`
+}
+
+// TimeToDrain estimates when a worker queue (alias or key) will empty. It
+// samples the queue's flow for a few seconds as Throughput does, averaging
+// the enqueue and completion rates and the variance of their difference
+// with an exponentially weighted moving average, and divides the current
+// backlog by the net rate. The range comes from the standard error of the
+// net rate.
+func TimeToDrain(ctx context.Context, cfg *config.Config, rdb *redis.Client, queue string) (*DrainEstimate, error) {
+	key, err := resolveWorkQueue(cfg, queue)
+	if err != nil {
+		return nil, err
+	}
+	q := QueueThroughput{Queue: key}
+	for _, p := range cfg.Worker.Priorities {
+		if cfg.Worker.Queues[p] == key {
+			q.Priority = p
+			break
+		}
+	}
+
+	est := &DrainEstimate{Priority: q.Priority, Queue: key, Window: drainWindow, Confidence: drainConfidence}
+	tau := (drainWindow / 4).Seconds()
+	var variance float64
+	_, err = sampleFlow(ctx, cfg, rdb, []QueueThroughput{q}, drainWindow, func(s flowSample) {
+		alpha := 1 - math.Exp(-s.dt/tau)
+		first := est.Samples == 0
+		net := (s.out[0] - s.in[0]) / s.dt
+		if !first {
+			d := net - est.NetPerSec
+			variance = (1 - alpha) * (variance + alpha*d*d)
+		}
+		est.EnqueuePerSec = ewma(est.EnqueuePerSec, s.in[0]/s.dt, alpha, first)
+		est.CompletePerSec = ewma(est.CompletePerSec, s.out[0]/s.dt, alpha, first)
+		est.NetPerSec = ewma(est.NetPerSec, net, alpha, first)
+		est.Length = s.lengths[0]
+		est.Samples++
+	})
+	if err != nil {
+		return nil, err
+	}
+	est.NetStdDev = math.Sqrt(variance)
+	est.finish(time.Now())
+	return est, nil
+}
+
+// finish derives the estimate, its range and the summary from the rates.
+func (est *DrainEstimate) finish(now time.Time) {
+	switch {
+	case est.Length == 0:
+		est.Drains = true
+		est.Summary = "empty"
+		return
+	case est.NetPerSec <= 0:
+		est.Growing = est.EnqueuePerSec > est.CompletePerSec
+		est.Summary = "never (stalled)"
+		if est.Growing {
+			est.Summary = "never (growing)"
+		}
+		return
+	}
+
+	est.Drains = true
+	toDrain := func(rate float64) time.Duration {
+		return time.Duration(float64(est.Length) / rate * float64(time.Second)).Round(time.Second)
+	}
+	margin := drainZ * est.NetStdDev / math.Sqrt(float64(est.Samples))
+	est.Estimate = toDrain(est.NetPerSec)
+	est.Low = toDrain(est.NetPerSec + margin)
+	if slow := est.NetPerSec - margin; slow > 0 {
+		est.High = toDrain(slow)
+	} else {
+		est.HighUnbounded = true
+	}
+	at := now.Add(est.Estimate)
+	est.DrainsAt = &at
+
+	if est.HighUnbounded {
+		est.Summary = fmt.Sprintf("~%s (%s or more)", est.Estimate, est.Low)
+	} else {
+		est.Summary = fmt.Sprintf("~%s (%s to %s)", est.Estimate, est.Low, est.High)
+	}
+}
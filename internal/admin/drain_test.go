@@ -0,0 +1,78 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestDrainEstimateRange(t *testing.T) {
+	now := time.Now()
+	est := &DrainEstimate{Length: 600, EnqueuePerSec: 2, CompletePerSec: 12, NetPerSec: 10, NetStdDev: 4, Samples: 4}
+	est.finish(now)
+	// margin = 1.645 * 4 / 2 = 3.29/s
+	if !est.Drains || est.Estimate != time.Minute || est.Low != 45*time.Second || est.High != 89*time.Second {
+		t.Fatalf("unexpected estimate %+v", est)
+	}
+	if est.DrainsAt == nil || !est.DrainsAt.Equal(now.Add(time.Minute)) || est.Summary != "~1m0s (45s to 1m29s)" {
+		t.Fatalf("unexpected summary %q at %v", est.Summary, est.DrainsAt)
+	}
+
+	noisy := &DrainEstimate{Length: 100, CompletePerSec: 1, NetPerSec: 1, NetStdDev: 5, Samples: 4}
+	noisy.finish(now)
+	if !noisy.HighUnbounded || noisy.High != 0 || noisy.Summary != "~1m40s (20s or more)" {
+		t.Fatalf("expected an open-ended range, got %+v", noisy)
+	}
+
+	growing := &DrainEstimate{Length: 100, EnqueuePerSec: 5, CompletePerSec: 3, NetPerSec: -2, Samples: 4}
+	growing.finish(now)
+	if growing.Drains || !growing.Growing || growing.Summary != "never (growing)" {
+		t.Fatalf("expected a growing queue, got %+v", growing)
+	}
+	stalled := &DrainEstimate{Length: 100, Samples: 4}
+	if stalled.finish(now); stalled.Growing || stalled.Summary != "never (stalled)" {
+		t.Fatalf("expected a stalled queue, got %+v", stalled)
+	}
+}
+
+func TestTimeToDrainSamplesQueue(t *testing.T) {
+	ctx := context.Background()
+	cfg, rdb := newPeekFixture(t)
+	cfg.Worker.Priorities = []string{"low"}
+	cfg.Worker.CompletedList = "jobqueue:completed"
+	defer func(w time.Duration) { drainWindow = w }(drainWindow)
+	drainWindow = 600 * time.Millisecond
+
+	if _, err := TimeToDrain(ctx, cfg, rdb, "completed"); err == nil {
+		t.Fatal("expected the completed list to be refused")
+	}
+
+	// enqueue 5 jobs every 50ms while nothing completes: the queue grows
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for n := 0; ; n++ {
+			select {
+			case <-stop:
+				return
+			case <-time.After(50 * time.Millisecond):
+			}
+			for i := 0; i < 5; i++ {
+				rdb.LPush(ctx, "jobqueue:low_priority", fmt.Sprintf(`{"id":"q%d-%d","priority":"low"}`, n, i))
+			}
+		}
+	}()
+
+	est, err := TimeToDrain(ctx, cfg, rdb, "low")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if est.Queue != "jobqueue:low_priority" || est.Priority != "low" || est.Samples < 3 {
+		t.Fatalf("unexpected estimate %+v", est)
+	}
+	if !est.Growing || est.Drains || est.Summary != "never (growing)" || est.EnqueuePerSec <= 0 {
+		t.Fatalf("expected a growing queue, got %+v", est)
+	}
+}
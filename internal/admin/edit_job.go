@@ -0,0 +1,96 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/redis/go-redis/v9"
+)
+
+// replaceScript pushes ARGV[2] onto KEYS[2] after removing one copy of
+// ARGV[1] from KEYS[1]; when the copy is already gone it pushes nothing
+// and returns 0. An empty ARGV[1] skips the removal.
+var replaceScript = redis.NewScript(`
+if ARGV[1] ~= '' and redis.call('LREM', KEYS[1], 1, ARGV[1]) == 0 then
+  return 0
+end
+redis.call('LPUSH', KEYS[2], ARGV[2])
+return 1
+`)
+
+// EditOptions says where ReenqueueEdited puts a corrected job.
+type EditOptions struct {
+	// DestQueue receives the corrected job, an alias or key as for Peek;
+	// empty sends it to the queue of its priority, or the high queue when
+	// that is unknown.
+	DestQueue string
+	// RemoveOriginal takes the original out of its list in the same step,
+	// so the job is not left to run, or sit in the dead letter list, twice.
+	RemoveOriginal bool
+}
+
+// ReenqueueEdited enqueues edited, a corrected copy of the job original
+// found in source. original is the item as Peek returned it, so a
+// compressed member is matched by its decoded payload. edited must be a
+// job payload. It returns the queue the job went to; with RemoveOriginal,
+// nothing is enqueued if the original has left source meanwhile.
+func ReenqueueEdited(ctx context.Context, cfg *config.Config, rdb *redis.Client, source, original, edited string, opts EditOptions) (string, error) {
+	src, err := resolveQueue(cfg, source)
+	if err != nil {
+		return "", err
+	}
+	job, err := queue.UnmarshalJob(edited)
+	if err != nil {
+		return "", fmt.Errorf("edited payload is not a job: %w", err)
+	}
+	dest := priorityQueue(cfg, job.Priority)
+	if opts.DestQueue != "" {
+		if dest, err = resolveQueue(cfg, opts.DestQueue); err != nil {
+			return "", err
+		}
+	}
+	if dest == "" || dest == cfg.Worker.CompletedList || dest == cfg.Worker.DeadLetterList {
+		return "", fmt.Errorf("cannot enqueue to %q", dest)
+	}
+
+	var member string
+	if opts.RemoveOriginal {
+		if member, err = findMember(ctx, rdb, src, original); err != nil {
+			return "", err
+		}
+	}
+	n, err := replaceScript.Run(ctx, rdb, []string{src, dest}, member, edited).Int()
+	if err != nil {
+		return "", err
+	}
+	if n == 0 {
+		return "", fmt.Errorf("original job is no longer in %s", src)
+	}
+	return dest, nil
+}
+
+// findMember returns the member of key that is item, or decodes to it.
+func findMember(ctx context.Context, rdb *redis.Client, key, item string) (string, error) {
+	if item == "" {
+		return "", errors.New("original job required")
+	}
+	const chunk = 500
+	for start := int64(0); ; start += chunk {
+		batch, err := rdb.LRange(ctx, key, start, start+chunk-1).Result()
+		if err != nil {
+			return "", err
+		}
+		for _, raw := range batch {
+			if raw == item || decodeItem(raw) == item {
+				return raw, nil
+			}
+		}
+		if len(batch) < chunk {
+			return "", fmt.Errorf("original job is no longer in %s", key)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+)
+
+func TestReenqueueEditedReplacesDeadLetter(t *testing.T) {
+	ctx := context.Background()
+	cfg, rdb := newPeekFixture(t)
+	cfg.Worker.Queues["high"] = "jobqueue:high_priority"
+	cfg.Worker.DeadLetterList = "jobqueue:dead_letter"
+
+	original, _ := queue.NewJob("job-1", "/tmp/bad/"+strings.Repeat("a", 200), 1, "low", "", "").Marshal()
+	member, err := queue.CompressPayload(original, "gzip", 1)
+	if err != nil || member == original {
+		t.Fatalf("expected a compressed member, got %v", err)
+	}
+	rdb.LPush(ctx, cfg.Worker.DeadLetterList, member)
+	edited := strings.Replace(original, "/tmp/bad", "/tmp/good", 1)
+
+	dest, err := ReenqueueEdited(ctx, cfg, rdb, "dlq", original, edited, EditOptions{RemoveOriginal: true})
+	if err != nil || dest != "jobqueue:low_priority" {
+		t.Fatalf("expected the job back on its priority queue, got %q %v", dest, err)
+	}
+	if n, _ := rdb.LLen(ctx, cfg.Worker.DeadLetterList).Result(); n != 0 {
+		t.Fatalf("expected the original removed from the DLQ, %d left", n)
+	}
+	if got, _ := rdb.LIndex(ctx, "jobqueue:low_priority", 0).Result(); got != edited {
+		t.Fatalf("expected the edited payload enqueued, got %q", got)
+	}
+
+	// the original is gone now, so a second replace must not enqueue
+	if _, err := ReenqueueEdited(ctx, cfg, rdb, "dlq", original, edited, EditOptions{RemoveOriginal: true}); err == nil {
+		t.Fatal("expected an error once the original has left the list")
+	}
+	if n, _ := rdb.LLen(ctx, "jobqueue:low_priority").Result(); n != 1 {
+		t.Fatalf("expected nothing more enqueued, got %d", n)
+	}
+
+	// keeping the original, to an explicit queue
+	if dest, err := ReenqueueEdited(ctx, cfg, rdb, "low", edited, edited, EditOptions{DestQueue: "high"}); err != nil || dest != "jobqueue:high_priority" {
+		t.Fatalf("expected the copy on high, got %q %v", dest, err)
+	}
+	if n, _ := rdb.LLen(ctx, "jobqueue:low_priority").Result(); n != 1 {
+		t.Fatalf("expected the original kept, got %d", n)
+	}
+
+	for _, bad := range []struct{ edited, dest string }{
+		{`{"id":`, ""},
+		{edited, "dlq"},
+	} {
+		if _, err := ReenqueueEdited(ctx, cfg, rdb, "low", edited, bad.edited, EditOptions{DestQueue: bad.dest}); err == nil {
+			t.Fatalf("expected an error for %+v", bad)
+		}
+	}
+}
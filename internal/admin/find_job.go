@@ -0,0 +1,194 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/flyingrobots/go-redis-work-queue/internal/scheduler"
+	"github.com/redis/go-redis/v9"
+)
+
+// Places FindJob can find a job.
+const (
+	LocationQueue      = "queue"
+	LocationProcessing = "processing"
+	LocationScheduled  = "scheduled"
+	LocationDelayed    = "delayed"
+	LocationDeadLetter = "dead_letter"
+	LocationCompleted  = "completed"
+	LocationStream     = "stream" // stream mode; the entry may already be acknowledged
+)
+
+// ErrJobNotFound is returned by FindJob when no key holds the job.
+var ErrJobNotFound = errors.New("job not found")
+
+// JobLocation is where FindJob found a job.
+type JobLocation struct {
+	JobID string `json:"job_id"`
+	Kind  string `json:"kind"` // one of the Location constants
+	Key   string `json:"key"`
+	// Position is the list index (0 is the head, where jobs are pushed, so
+	// the highest index pops first), the rank in a sorted set, soonest
+	// first, or the entry number in a stream.
+	Position int64      `json:"position"`
+	StreamID string     `json:"stream_id,omitempty"`
+	DueAt    *time.Time `json:"due_at,omitempty"` // scheduled and delayed jobs
+	Payload  string     `json:"payload"`          // decompressed
+}
+
+// FindJob looks for the job whose decoded id is jobID: in worker
+// processing lists first, then the queues in priority order, their
+// scheduled and delayed sets, the dead letter list, the completed list and,
+// in stream mode, the queue streams. Keys are read in chunks and
+// processing lists found with SCAN; the search stops at the first match.
+// Items are read as they are, so a job moving between keys during the
+// search can be missed; try again before concluding it is gone.
+func FindJob(ctx context.Context, cfg *config.Config, rdb *redis.Client, jobID string) (*JobLocation, error) {
+	if jobID == "" {
+		return nil, errors.New("job id is required")
+	}
+	// cheap substring test before decoding each item
+	needle, _ := json.Marshal(jobID)
+	match := func(item string) (string, bool) {
+		payload := decodeItem(item)
+		if !strings.Contains(payload, string(needle)) {
+			return "", false
+		}
+		job, err := queue.UnmarshalJob(payload)
+		return payload, err == nil && job.ID == jobID
+	}
+
+	lists, err := consistencyLists(ctx, cfg, rdb)
+	if err != nil {
+		return nil, err
+	}
+	queues, _ := scheduledQueues(cfg, "")
+	stream := cfg.Worker.Mode == config.ModeStream
+
+	// consistencyLists puts the queues first; search running jobs first
+	ordered := make([]*consistencyList, 0, len(lists))
+	for _, l := range lists {
+		if l.processing {
+			ordered = append(ordered, l)
+		}
+	}
+	if !stream {
+		for _, q := range queues {
+			ordered = append(ordered, &consistencyList{key: q})
+		}
+	}
+	for _, l := range ordered {
+		kind := LocationQueue
+		if l.processing {
+			kind = LocationProcessing
+		}
+		if loc, err := findInList(ctx, rdb, l.key, kind, match); loc != nil || err != nil {
+			return withJobID(loc, jobID), err
+		}
+	}
+
+	for _, q := range queues {
+		for _, set := range []struct{ key, kind string }{
+			{cfg.DerivedKey(scheduler.ScheduledKey, q), LocationScheduled},
+			{cfg.DerivedKey(scheduler.DelayedKey, q), LocationDelayed},
+		} {
+			if loc, err := findInSortedSet(ctx, rdb, set.key, set.kind, match); loc != nil || err != nil {
+				return withJobID(loc, jobID), err
+			}
+		}
+	}
+
+	for _, l := range []struct{ key, kind string }{
+		{cfg.Worker.DeadLetterList, LocationDeadLetter},
+		{cfg.Worker.CompletedList, LocationCompleted},
+	} {
+		if l.key == "" {
+			continue
+		}
+		if loc, err := findInList(ctx, rdb, l.key, l.kind, match); loc != nil || err != nil {
+			return withJobID(loc, jobID), err
+		}
+	}
+
+	if stream {
+		for _, q := range queues {
+			if loc, err := findInStream(ctx, rdb, queue.StreamKey(q), match); loc != nil || err != nil {
+				return withJobID(loc, jobID), err
+			}
+		}
+	}
+	return nil, ErrJobNotFound
+}
+
+func withJobID(loc *JobLocation, jobID string) *JobLocation {
+	if loc != nil {
+		loc.JobID = jobID
+	}
+	return loc
+}
+
+func findInList(ctx context.Context, rdb *redis.Client, key, kind string, match func(string) (string, bool)) (*JobLocation, error) {
+	for start := int64(0); ; start += consistencyChunk {
+		items, err := rdb.LRange(ctx, key, start, start+consistencyChunk-1).Result()
+		if err != nil {
+			return nil, err
+		}
+		for i, it := range items {
+			if payload, ok := match(it); ok {
+				return &JobLocation{Kind: kind, Key: key, Position: start + int64(i), Payload: payload}, nil
+			}
+		}
+		if len(items) < consistencyChunk {
+			return nil, nil
+		}
+	}
+}
+
+func findInSortedSet(ctx context.Context, rdb *redis.Client, key, kind string, match func(string) (string, bool)) (*JobLocation, error) {
+	for start := int64(0); ; start += consistencyChunk {
+		zs, err := rdb.ZRangeWithScores(ctx, key, start, start+consistencyChunk-1).Result()
+		if err != nil {
+			return nil, err
+		}
+		for i, z := range zs {
+			member, _ := z.Member.(string)
+			if payload, ok := match(member); ok {
+				sec, frac := math.Modf(z.Score)
+				due := time.Unix(int64(sec), int64(frac*1e9)).UTC()
+				return &JobLocation{Kind: kind, Key: key, Position: start + int64(i), DueAt: &due, Payload: payload}, nil
+			}
+		}
+		if len(zs) < consistencyChunk {
+			return nil, nil
+		}
+	}
+}
+
+func findInStream(ctx context.Context, rdb *redis.Client, key string, match func(string) (string, bool)) (*JobLocation, error) {
+	from := "-"
+	var n int64
+	for {
+		msgs, err := rdb.XRangeN(ctx, key, from, "+", consistencyChunk).Result()
+		if err != nil {
+			return nil, err
+		}
+		for _, msg := range msgs {
+			raw, _ := msg.Values[queue.StreamField].(string)
+			if payload, ok := match(raw); ok {
+				return &JobLocation{Kind: LocationStream, Key: key, Position: n, StreamID: msg.ID, Payload: payload}, nil
+			}
+			n++
+		}
+		if len(msgs) < consistencyChunk {
+			return nil, nil
+		}
+		from = "(" + msgs[len(msgs)-1].ID
+	}
+}
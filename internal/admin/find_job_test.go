@@ -0,0 +1,66 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/flyingrobots/go-redis-work-queue/internal/scheduler"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestFindJobAcrossKeys(t *testing.T) {
+	ctx := context.Background()
+	cfg, rdb := newPeekFixture(t)
+	cfg.Worker.ProcessingListPattern = "jobqueue:worker:%s:processing"
+	cfg.Worker.HeartbeatKeyPattern = "jobqueue:processing:worker:%s"
+	cfg.Worker.DeadLetterList = "jobqueue:dead_letter"
+	cfg.Worker.CompletedList = "jobqueue:completed"
+
+	job := func(id string) string {
+		return fmt.Sprintf(`{"id":%q,"filepath":"/data/%s"}`, id, id)
+	}
+	for i := 0; i < 1200; i++ {
+		rdb.LPush(ctx, "jobqueue:low_priority", job(fmt.Sprintf("queued-%d", i)))
+	}
+	gz, err := queue.CompressPayload(job("deep"), "gzip", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rdb.RPush(ctx, "jobqueue:low_priority", gz)
+	rdb.LPush(ctx, "jobqueue:worker:w1:processing", job("running"))
+	due := time.Now().Add(time.Hour).Truncate(time.Second)
+	rdb.ZAdd(ctx, scheduler.DelayedKey("jobqueue:low_priority"), redis.Z{Score: scheduler.Score(due), Member: job("later")})
+	rdb.LPush(ctx, "jobqueue:dead_letter", job("dead"), `{"note":"mentions \"dead\" but is not it"}`)
+	rdb.LPush(ctx, "jobqueue:completed", job("done"))
+
+	for _, tc := range []struct {
+		id, kind, key string
+		pos           int64
+	}{
+		{"running", LocationProcessing, "jobqueue:worker:w1:processing", 0},
+		{"queued-1199", LocationQueue, "jobqueue:low_priority", 0},
+		{"deep", LocationQueue, "jobqueue:low_priority", 1200},
+		{"later", LocationDelayed, scheduler.DelayedKey("jobqueue:low_priority"), 0},
+		{"dead", LocationDeadLetter, "jobqueue:dead_letter", 1},
+		{"done", LocationCompleted, "jobqueue:completed", 0},
+	} {
+		loc, err := FindJob(ctx, cfg, rdb, tc.id)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.id, err)
+		}
+		if loc.JobID != tc.id || loc.Kind != tc.kind || loc.Key != tc.key || loc.Position != tc.pos || loc.Payload != job(tc.id) {
+			t.Fatalf("%s: expected %s %s[%d], got %+v", tc.id, tc.kind, tc.key, tc.pos, loc)
+		}
+	}
+	if loc, _ := FindJob(ctx, cfg, rdb, "later"); loc.DueAt == nil || !loc.DueAt.Equal(due) {
+		t.Fatalf("expected due time %v, got %+v", due, loc)
+	}
+	if _, err := FindJob(ctx, cfg, rdb, "missing"); !errors.Is(err, ErrJobNotFound) {
+		t.Fatalf("expected ErrJobNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMoveJobsPreservesOrderAndStopsWhenEmpty(t *testing.T) {
+	ctx := context.Background()
+	cfg, rdb := newPeekFixture(t)
+	cfg.Worker.Queues["high"] = "jobqueue:high_priority"
+	cfg.Worker.DeadLetterList = "jobqueue:dead_letter"
+
+	for _, id := range []string{"a", "b", "c"} {
+		if err := rdb.LPush(ctx, "jobqueue:high_priority", id).Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	n, err := MoveJobs(ctx, cfg, rdb, "high", "low", 2)
+	if err != nil || n != 2 {
+		t.Fatalf("move: n=%d err=%v", n, err)
+	}
+	// The oldest jobs move first and stay next in line on the destination.
+	if got, _ := rdb.LRange(ctx, "jobqueue:low_priority", 0, -1).Result(); len(got) != 2 || got[0] != "b" || got[1] != "a" {
+		t.Fatalf("unexpected destination contents: %v", got)
+	}
+
+	n, err = MoveJobs(ctx, cfg, rdb, "high", "low", 10)
+	if err != nil || n != 1 {
+		t.Fatalf("drain: n=%d err=%v", n, err)
+	}
+
+	if _, err := MoveJobs(ctx, cfg, rdb, "low", "low", 1); err == nil {
+		t.Fatal("expected error moving a queue onto itself")
+	}
+	if _, err := MoveJobs(ctx, cfg, rdb, "dlq", "low", 1); err == nil {
+		t.Fatal("expected dead letter source to require force")
+	}
+	if n, err := MoveJobsForce(ctx, cfg, rdb, "dlq", "low", 1); err != nil || n != 0 {
+		t.Fatalf("forced move from empty DLQ: n=%d err=%v", n, err)
+	}
+
+	if err := rdb.Set(ctx, "jobqueue:not_a_list", "x", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := MoveJobs(ctx, cfg, rdb, "low", "jobqueue:not_a_list", 1); err == nil {
+		t.Fatal("expected type check to reject non-list destination")
+	}
+}
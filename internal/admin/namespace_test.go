@@ -0,0 +1,165 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/scheduler"
+	"github.com/redis/go-redis/v9"
+)
+
+func namespacedConfig(t *testing.T, ns string) *config.Config {
+	t.Helper()
+	t.Setenv("WORKQUEUE_NAMESPACE", ns)
+	cfg, err := config.Load("nonexistent.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+// seedNamespace gives cfg's system one queued job, one in flight on worker
+// w1 and w1's heartbeat.
+func seedNamespace(t *testing.T, cfg *config.Config, rdb *redis.Client) {
+	t.Helper()
+	ctx := context.Background()
+	proc := fmt.Sprintf(cfg.Worker.ProcessingListPattern, "w1")
+	hb := fmt.Sprintf(cfg.Worker.HeartbeatKeyPattern, "w1")
+	if err := rdb.LPush(ctx, cfg.Worker.Queues["high"], fmt.Sprintf(`{"id":"%s-queued"}`, cfg.Namespace)).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := rdb.LPush(ctx, proc, fmt.Sprintf(`{"id":"%s-running"}`, cfg.Namespace)).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := rdb.Set(ctx, hb, "1", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNamespacesDoNotSeeEachOthersJobs(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	billing := namespacedConfig(t, "billing")
+	search := namespacedConfig(t, "search")
+	seedNamespace(t, billing, rdb)
+	seedNamespace(t, search, rdb)
+
+	for _, cfg := range []*config.Config{billing, search} {
+		ns := cfg.Namespace + ":"
+		stats, err := Stats(ctx, cfg, rdb)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for name, n := range stats.Queues {
+			if !strings.Contains(name, "("+ns) {
+				t.Fatalf("%s stats list a queue outside the namespace: %s", cfg.Namespace, name)
+			}
+			if strings.HasPrefix(name, "high(") && n != 1 {
+				t.Fatalf("%s high queue: want 1 job, got %d", cfg.Namespace, n)
+			}
+		}
+		if len(stats.ProcessingLists) != 1 || stats.Heartbeats != 1 {
+			t.Fatalf("%s stats see other workers: %+v", cfg.Namespace, stats)
+		}
+		for k := range stats.ProcessingLists {
+			if !strings.HasPrefix(k, ns) {
+				t.Fatalf("%s stats list another namespace's processing list %s", cfg.Namespace, k)
+			}
+		}
+
+		peek, err := Peek(ctx, cfg, rdb, "high", 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(peek.Items) != 1 || !strings.Contains(peek.Items[0], cfg.Namespace+"-queued") {
+			t.Fatalf("%s peek: %v", cfg.Namespace, peek.Items)
+		}
+		if _, err := Peek(ctx, cfg, rdb, "jobqueue:high_priority", 10); err != nil {
+			t.Fatalf("%s peek by full key: %v", cfg.Namespace, err)
+		}
+	}
+
+	// a pattern that would match both systems only matches within one
+	matched, _, err := PurgeMatching(ctx, billing, rdb, "jobqueue:*", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if matched != 3 {
+		t.Fatalf("billing purge-pattern dry run matched %d keys, want its 3", matched)
+	}
+
+	if _, err := PurgeAll(ctx, billing, rdb); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(mr.Keys()); n != 3 {
+		t.Fatalf("after purging billing want search's 3 keys left, got %v", mr.Keys())
+	}
+	for _, k := range mr.Keys() {
+		if !strings.HasPrefix(k, "search:") {
+			t.Fatalf("key %s survived the billing purge", k)
+		}
+	}
+	peek, err := Peek(ctx, search, rdb, "high", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(peek.Items) != 1 {
+		t.Fatalf("search lost its job to the billing purge: %v", peek.Items)
+	}
+}
+
+func TestNamespaceLeadsEveryKeyItWrites(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	billing := namespacedConfig(t, "billing")
+	search := namespacedConfig(t, "search")
+	seedNamespace(t, search, rdb)
+
+	if err := PauseQueue(ctx, billing, rdb, "high"); err != nil {
+		t.Fatal(err)
+	}
+	high := billing.Worker.Queues["high"]
+	if err := rdb.ZAdd(ctx, billing.DerivedKey(scheduler.ScheduledKey, high), redis.Z{Score: 1, Member: `{"id":"later","priority":"high"}`}).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := rdb.LPush(ctx, billing.Worker.DeadLetterList, `{"id":"dead","priority":"high"}`).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := BulkJobs(ctx, billing, rdb, []string{"dead"}, BulkOptions{Action: BulkArchive, ArchiveList: "jobqueue:archive"}); err != nil {
+		t.Fatal(err)
+	}
+	paused, err := PausedQueues(ctx, billing, rdb)
+	if err != nil || !paused[high] {
+		t.Fatalf("billing high should read as paused: %v, %v", paused, err)
+	}
+	scheduled, err := ListScheduled(ctx, billing, rdb, "high", 10)
+	if err != nil || len(scheduled) != 1 {
+		t.Fatalf("billing scheduled jobs: %v, %v", scheduled, err)
+	}
+
+	for _, k := range []string{"billing:paused:jobqueue:high_priority", "billing:scheduled:jobqueue:high_priority", "billing:jobqueue:archive"} {
+		if !mr.Exists(k) {
+			t.Fatalf("expected %s, have %v", k, mr.Keys())
+		}
+	}
+	// everything billing wrote goes with a purge of its namespace
+	if _, _, err := PurgeMatchingForce(ctx, billing, rdb, "*", false); err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range mr.Keys() {
+		if !strings.HasPrefix(k, "search:") {
+			t.Fatalf("key %s survived the billing purge", k)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// pageChurnWindow is how far towards the tail a page's last item may move
+// between requests, as items are consumed or removed, and still be found.
+const pageChurnWindow int64 = 1000
+
+// ErrInvalidCursor is returned for a page cursor that is malformed or was
+// issued for another list.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// pageCursor is the decoded form of the opaque cursor handed out with a
+// page. It records the last item returned, by hash, and its offset from
+// the tail of the list. Offsets from the tail do not change as jobs are
+// pushed onto the head, only as items at or behind them are removed, so
+// the next page finds the item where it was or a little closer to the
+// tail and continues from it.
+type pageCursor struct {
+	Key    string `json:"k"`
+	Offset int64  `json:"o"`
+	Hash   string `json:"h"`
+}
+
+func itemHash(raw string) string {
+	sum := sha1.Sum([]byte(raw))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+func encodeCursor(c pageCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s, key string) (pageCursor, error) {
+	var c pageCursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || json.Unmarshal(data, &c) != nil || c.Offset < 0 || c.Hash == "" {
+		return c, ErrInvalidCursor
+	}
+	if c.Key != key {
+		return c, fmt.Errorf("%w: issued for %q", ErrInvalidCursor, c.Key)
+	}
+	return c, nil
+}
+
+// pageScript reads one page of KEYS[1]. ARGV[1] and ARGV[2] are the hash
+// and tail offset of the previous page's last item ("" on the first page),
+// ARGV[3] the page size, ARGV[4] the direction: "head" reads from the tail
+// (the consuming end) towards the head, "tail" from the head towards the
+// tail. ARGV[5] bounds the search for the previous item.
+//
+// When the previous item is gone it resumes where no unseen item can be
+// skipped: reading towards the head that is the tail, since items behind
+// the one consumed were consumed first; reading towards the tail it is
+// the item's old offset. Either may repeat items, but never skips one.
+//
+// It returns the page in list order (head first), the tail offset of its
+// last item in reading order, and 1 when more items follow.
+var pageScript = redis.NewScript(`
+local len = redis.call('LLEN', KEYS[1])
+local limit = tonumber(ARGV[3])
+local towardHead = ARGV[4] == 'head'
+local start
+if ARGV[1] == '' then
+  if towardHead then start = 0 else start = len - 1 end
+else
+  local pos = tonumber(ARGV[2])
+  local hi = math.min(pos, len - 1)
+  local lo = math.max(0, pos - tonumber(ARGV[5]))
+  local anchor = -1
+  if hi >= lo then
+    local items = redis.call('LRANGE', KEYS[1], -(hi + 1), -(lo + 1))
+    for j = 1, #items do
+      if string.sub(redis.sha1hex(items[j]), 1, 16) == ARGV[1] then
+        anchor = hi - (j - 1)
+        break
+      end
+    end
+  end
+  if towardHead then
+    start = anchor + 1
+  elseif anchor >= 0 then
+    start = anchor - 1
+  else
+    start = math.min(pos, len) - 1
+  end
+end
+if towardHead then
+  if start >= len then
+    return {{}, start - 1, 0}
+  end
+  local items = redis.call('LRANGE', KEYS[1], -(start + limit), -(start + 1))
+  local last = start + #items - 1
+  return {items, last, last < len - 1 and 1 or 0}
+end
+if start < 0 then
+  return {{}, 0, 0}
+end
+local items = redis.call('LRANGE', KEYS[1], -(start + 1), -(math.max(start - limit + 1, 0) + 1))
+local last = start - #items + 1
+return {items, last, last > 0 and 1 or 0}
+`)
+
+// listPage is one page of a list read with readPage.
+type listPage struct {
+	Items   []string // raw items, in list order
+	Next    string   // cursor for the following page; "" when HasMore is false
+	HasMore bool
+
+	key        string
+	last       int64 // tail offset of the last item in reading order
+	towardHead bool
+}
+
+// readPage reads up to limit items of key, continuing after cursor ("" for
+// the first page). towardHead reads from the consuming end, as Peek does;
+// otherwise it reads from the newest item, as DLQList does.
+func readPage(ctx context.Context, rdb *redis.Client, key, cursor string, limit int64, towardHead bool) (listPage, error) {
+	var c pageCursor
+	if cursor != "" {
+		var err error
+		if c, err = decodeCursor(cursor, key); err != nil {
+			return listPage{}, err
+		}
+	}
+	dir := "tail"
+	if towardHead {
+		dir = "head"
+	}
+	res, err := pageScript.Run(ctx, rdb, []string{key}, c.Hash, c.Offset, limit, dir, pageChurnWindow).Slice()
+	if err != nil {
+		return listPage{}, err
+	}
+	if len(res) != 3 {
+		return listPage{}, fmt.Errorf("unexpected page reply %v", res)
+	}
+	raw, _ := res[0].([]interface{})
+	more, _ := res[2].(int64)
+	page := listPage{Items: make([]string, 0, len(raw)), HasMore: more == 1, key: key, towardHead: towardHead}
+	page.last, _ = res[1].(int64)
+	for _, it := range raw {
+		s, _ := it.(string)
+		page.Items = append(page.Items, s)
+	}
+	if page.HasMore && len(page.Items) > 0 {
+		i := len(page.Items) - 1
+		if towardHead {
+			i = 0
+		}
+		page.Next = page.cursorAt(i)
+	}
+	return page, nil
+}
+
+// cursorAt returns a cursor continuing after Items[i], for callers that
+// stop part way through a page.
+func (p listPage) cursorAt(i int) string {
+	offset := p.last + int64(len(p.Items)-1-i)
+	if p.towardHead {
+		offset = p.last - int64(i)
+	}
+	return encodeCursor(pageCursor{Key: p.key, Offset: offset, Hash: itemHash(p.Items[i])})
+}
@@ -0,0 +1,149 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func pageIDs(t *testing.T, items []string) []string {
+	t.Helper()
+	ids := make([]string, len(items))
+	for i, it := range items {
+		var v struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal([]byte(it), &v); err != nil {
+			t.Fatalf("item %q: %v", it, err)
+		}
+		ids[i] = v.ID
+	}
+	return ids
+}
+
+func TestPeekPagesThroughConsumingQueue(t *testing.T) {
+	ctx := context.Background()
+	cfg, rdb := newPeekFixture(t)
+	key := "jobqueue:low_priority"
+	push := func(from, to int) {
+		for i := from; i < to; i++ {
+			if err := rdb.LPush(ctx, key, fmt.Sprintf(`{"id":"job-%d"}`, i)).Err(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	push(0, 25)
+
+	var seen []string
+	page := func(cursor string) PeekResult {
+		res, err := PeekWithOptions(ctx, cfg, rdb, "low", 10, PeekOptions{Cursor: cursor})
+		if err != nil {
+			t.Fatalf("peek: %v", err)
+		}
+		ids := pageIDs(t, res.Items)
+		for i := len(ids) - 1; i >= 0; i-- {
+			seen = append(seen, ids[i])
+		}
+		return res
+	}
+
+	res := page("")
+	if !res.HasMore || res.NextCursor == "" {
+		t.Fatalf("expected more after the first page, got %+v", res)
+	}
+	// workers consume from the tail and producers push onto the head
+	rdb.RPop(ctx, key)
+	rdb.RPop(ctx, key)
+	push(25, 30)
+	res = page(res.NextCursor)
+
+	// the consumer overtakes the peek: the page's last item is gone, so
+	// the next page starts at the new tail
+	for i := 0; i < 18; i++ {
+		rdb.RPop(ctx, key)
+	}
+	for res.HasMore {
+		res = page(res.NextCursor)
+	}
+	if res.NextCursor != "" {
+		t.Fatalf("expected no cursor on the last page, got %q", res.NextCursor)
+	}
+
+	want := make([]string, 30)
+	for i := range want {
+		want[i] = fmt.Sprintf("job-%d", i)
+	}
+	if fmt.Sprint(seen) != fmt.Sprint(want) {
+		t.Fatalf("expected every job once in order\n got %v\nwant %v", seen, want)
+	}
+}
+
+func TestDLQListCursorSurvivesNewAndRemovedEntries(t *testing.T) {
+	ctx := context.Background()
+	cfg, rdb := newPeekFixture(t)
+	cfg.Worker.DeadLetterList = "jobqueue:dead_letter"
+	entry := func(i int) string { return fmt.Sprintf(`{"id":"dlq-%d"}`, i) }
+	for i := 0; i < 12; i++ {
+		rdb.LPush(ctx, cfg.Worker.DeadLetterList, entry(i))
+	}
+	ids := func(items []DLQItem) string {
+		var out []string
+		for _, it := range items {
+			out = append(out, it.ID)
+		}
+		return fmt.Sprint(out)
+	}
+
+	items, next, err := DLQList(ctx, cfg, rdb, "", "", 5)
+	if err != nil || ids(items) != "[dlq-11 dlq-10 dlq-9 dlq-8 dlq-7]" || next == "" {
+		t.Fatalf("first page: %s %q %v", ids(items), next, err)
+	}
+	rdb.LPush(ctx, cfg.Worker.DeadLetterList, entry(12))
+	rdb.LRem(ctx, cfg.Worker.DeadLetterList, 0, entry(9))
+	items, next, err = DLQList(ctx, cfg, rdb, "", next, 5)
+	if err != nil || ids(items) != "[dlq-6 dlq-5 dlq-4 dlq-3 dlq-2]" || next == "" {
+		t.Fatalf("second page: %s %q %v", ids(items), next, err)
+	}
+	// requeueing the page's last entry resumes from where it was
+	rdb.LRem(ctx, cfg.Worker.DeadLetterList, 0, entry(2))
+	items, next, err = DLQList(ctx, cfg, rdb, "", next, 5)
+	if err != nil || ids(items) != "[dlq-1 dlq-0]" || next != "" {
+		t.Fatalf("last page: %s %q %v", ids(items), next, err)
+	}
+
+	_, peekNext, err := DLQList(ctx, cfg, rdb, "", "", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := PeekWithOptions(ctx, cfg, rdb, "low", 1, PeekOptions{Cursor: peekNext}); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("expected a DLQ cursor to be refused for another list, got %v", err)
+	}
+	if _, _, err := DLQList(ctx, cfg, rdb, "", "10", 5); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("expected a malformed cursor to be refused, got %v", err)
+	}
+}
+
+func TestFilteredPeekCursorResumesScan(t *testing.T) {
+	ctx := context.Background()
+	cfg, rdb := newPeekFixture(t)
+	for i := 0; i < 500; i++ {
+		user := "other"
+		if i%100 == 0 {
+			user = "123"
+		}
+		rdb.LPush(ctx, "jobqueue:low_priority", fmt.Sprintf(`{"id":"job-%d","user_id":%q}`, i, user))
+	}
+	opts := PeekOptions{Filter: `$.user_id == "123"`}
+	res, err := PeekWithOptions(ctx, cfg, rdb, "low", 3, opts)
+	if err != nil || fmt.Sprint(pageIDs(t, res.Items)) != "[job-200 job-100 job-0]" || !res.HasMore || res.Scanned != 201 {
+		t.Fatalf("first page: %+v %v", res, err)
+	}
+	opts.Cursor = res.NextCursor
+	res, err = PeekWithOptions(ctx, cfg, rdb, "low", 3, opts)
+	if err != nil || fmt.Sprint(pageIDs(t, res.Items)) != "[job-400 job-300]" || res.HasMore || res.NextCursor != "" || res.Scanned != 299 {
+		t.Fatalf("second page: %+v %v", res, err)
+	}
+}
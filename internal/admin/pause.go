@@ -0,0 +1,71 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/redis/go-redis/v9"
+)
+
+// PauseQueue stops workers from fetching from a priority queue without
+// stopping them. Jobs can still be enqueued and wait until ResumeQueue.
+// Workers notice within worker.pause_cache_ttl.
+func PauseQueue(ctx context.Context, cfg *config.Config, rdb *redis.Client, queueAlias string) error {
+	key, err := resolveWorkQueue(cfg, queueAlias)
+	if err != nil {
+		return err
+	}
+	return rdb.Set(ctx, cfg.DerivedKey(queue.PausedKey, key), time.Now().UTC().Format(time.RFC3339), 0).Err()
+}
+
+// ResumeQueue clears the flag set by PauseQueue.
+func ResumeQueue(ctx context.Context, cfg *config.Config, rdb *redis.Client, queueAlias string) error {
+	key, err := resolveWorkQueue(cfg, queueAlias)
+	if err != nil {
+		return err
+	}
+	return rdb.Del(ctx, cfg.DerivedKey(queue.PausedKey, key)).Err()
+}
+
+// PausedQueues reports which configured priority queues are paused, by key.
+func PausedQueues(ctx context.Context, cfg *config.Config, rdb *redis.Client) (map[string]bool, error) {
+	keys := make([]string, 0, len(cfg.Worker.Queues))
+	flags := make([]string, 0, len(cfg.Worker.Queues))
+	for _, key := range cfg.Worker.Queues {
+		keys = append(keys, key)
+		flags = append(flags, cfg.DerivedKey(queue.PausedKey, key))
+	}
+	paused := map[string]bool{}
+	if len(flags) == 0 {
+		return paused, nil
+	}
+	vals, err := rdb.MGet(ctx, flags...).Result()
+	if err != nil {
+		return nil, err
+	}
+	for i, v := range vals {
+		if v != nil {
+			paused[keys[i]] = true
+		}
+	}
+	return paused, nil
+}
+
+// resolveWorkQueue resolves an alias or key that workers fetch from;
+// the completed and dead letter lists cannot be paused.
+func resolveWorkQueue(cfg *config.Config, alias string) (string, error) {
+	key, err := resolveQueue(cfg, alias)
+	if err != nil {
+		return "", err
+	}
+	for _, q := range cfg.Worker.Queues {
+		if q == key {
+			return key, nil
+		}
+	}
+	return "", fmt.Errorf("%q is not a worker queue", alias)
+}
@@ -0,0 +1,38 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPauseAndResumeQueue(t *testing.T) {
+	ctx := context.Background()
+	cfg, rdb := newPeekFixture(t)
+	cfg.Worker.CompletedList = "jobqueue:completed"
+
+	if err := PauseQueue(ctx, cfg, rdb, "low"); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := rdb.Exists(ctx, "paused:jobqueue:low_priority").Result(); n != 1 {
+		t.Fatal("expected the paused flag to be set")
+	}
+	res, err := Stats(ctx, cfg, rdb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Paused["jobqueue:low_priority"] {
+		t.Fatalf("expected stats to report the queue paused, got %v", res.Paused)
+	}
+
+	if err := ResumeQueue(ctx, cfg, rdb, "jobqueue:low_priority"); err != nil {
+		t.Fatal(err)
+	}
+	if res, _ := Stats(ctx, cfg, rdb); len(res.Paused) != 0 {
+		t.Fatalf("expected no paused queues after resume, got %v", res.Paused)
+	}
+
+	if err := PauseQueue(ctx, cfg, rdb, "completed"); err == nil {
+		t.Fatal("expected pausing the completed list to fail")
+	}
+}
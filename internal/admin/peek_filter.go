@@ -0,0 +1,186 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/PaesslerAG/jsonpath"
+	"github.com/redis/go-redis/v9"
+)
+
+// peekScanBatch is how many items a filtered peek fetches per LRANGE.
+const peekScanBatch int64 = 200
+
+// DefaultPeekScanLimit is how many items a filtered peek page inspects
+// when PeekOptions.ScanLimit is zero.
+const DefaultPeekScanLimit int64 = 10000
+
+// PeekOptions narrows what Peek returns.
+type PeekOptions struct {
+	// Filter is a JSONPath expression evaluated against each decoded item,
+	// e.g. `$.user_id == "123"`. Boolean results select the item; any other
+	// result selects it when the path resolves. Non-JSON items never match.
+	Filter string
+	// Project lists dotted field paths to keep in each returned item,
+	// e.g. []string{"id", "payload.user_id"}. Empty keeps whole items.
+	Project []string
+	// ScanLimit caps how many items a filtered peek page inspects; zero
+	// means DefaultPeekScanLimit, so no page walks a whole long queue. A
+	// page stopped by the cap, or by ctx's deadline, returns the matches so
+	// far with a NextCursor to continue from.
+	ScanLimit int64
+	// Cursor continues a previous peek from its PeekResult.NextCursor.
+	Cursor string
+}
+
+// ParseProjection splits a comma-separated field list, dropping blanks.
+func ParseProjection(s string) []string {
+	var fields []string
+	for _, f := range strings.Split(s, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+func peekFiltered(ctx context.Context, rdb *redis.Client, qkey string, n int64, opts PeekOptions) (PeekResult, error) {
+	eval, err := jsonpath.New(opts.Filter)
+	if err != nil {
+		return PeekResult{}, fmt.Errorf("invalid filter %q: %w", opts.Filter, err)
+	}
+
+	res := PeekResult{Queue: qkey}
+	var matches []string
+	// Walk from the consuming (right) end, or the cursor, towards the head
+	// in batches. The cursor handed back follows the last item inspected,
+	// so the next page resumes the scan rather than the matches.
+	limit := opts.ScanLimit
+	if limit <= 0 {
+		limit = DefaultPeekScanLimit
+	}
+	cursor := opts.Cursor
+	for int64(len(matches)) < n {
+		remaining := limit - res.Scanned
+		if remaining <= 0 {
+			break
+		}
+		page, err := readPage(ctx, rdb, qkey, cursor, min(peekScanBatch, remaining), true)
+		if err != nil {
+			if ctx.Err() != nil {
+				// out of time: resume where this page would have started
+				res.HasMore = true
+				break
+			}
+			return PeekResult{}, err
+		}
+		from := cursor
+		cursor, res.HasMore = page.Next, page.HasMore
+		stopped := false
+		for i := len(page.Items) - 1; i >= 0; i-- {
+			if int64(len(matches)) == n || ctx.Err() != nil {
+				// resume at item i, after the last one inspected
+				cursor, res.HasMore, stopped = from, true, true
+				if i < len(page.Items)-1 {
+					cursor = page.cursorAt(i + 1)
+				}
+				break
+			}
+			res.Scanned++
+			item := decodeItem(page.Items[i])
+			var doc interface{}
+			if err := json.Unmarshal([]byte(item), &doc); err != nil {
+				continue
+			}
+			if filterMatches(ctx, eval, doc) {
+				matches = append(matches, item)
+			}
+		}
+		if stopped || !page.HasMore {
+			break // out of time, or reached the head of the list
+		}
+	}
+	if res.HasMore {
+		res.NextCursor = cursor
+	}
+
+	// Present matches in list order, like an unfiltered peek.
+	for i, j := 0, len(matches)-1; i < j; i, j = i+1, j-1 {
+		matches[i], matches[j] = matches[j], matches[i]
+	}
+	items, err := projectItems(matches, opts.Project)
+	if err != nil {
+		return PeekResult{}, err
+	}
+	res.Items = items
+	return res, nil
+}
+
+func filterMatches(ctx context.Context, eval func(context.Context, interface{}) (interface{}, error), doc interface{}) bool {
+	v, err := eval(ctx, doc)
+	if err != nil {
+		return false
+	}
+	switch t := v.(type) {
+	case bool:
+		return t
+	case nil:
+		return false
+	case []interface{}:
+		return len(t) > 0
+	default:
+		return true
+	}
+}
+
+// projectItems keeps only the requested fields of each JSON item. Items that
+// are not JSON objects are returned unchanged.
+func projectItems(items []string, fields []string) ([]string, error) {
+	if len(fields) == 0 {
+		return items, nil
+	}
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		var doc map[string]interface{}
+		if err := json.Unmarshal([]byte(item), &doc); err != nil {
+			out = append(out, item)
+			continue
+		}
+		projected := map[string]interface{}{}
+		for _, field := range fields {
+			copyField(projected, doc, strings.Split(field, "."))
+		}
+		b, err := json.Marshal(projected)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, string(b))
+	}
+	return out, nil
+}
+
+func copyField(dst, src map[string]interface{}, path []string) {
+	v, ok := src[path[0]]
+	if !ok {
+		return
+	}
+	if len(path) == 1 {
+		dst[path[0]] = v
+		return
+	}
+	child, ok := v.(map[string]interface{})
+	if !ok {
+		return
+	}
+	next, ok := dst[path[0]].(map[string]interface{})
+	if !ok {
+		next = map[string]interface{}{}
+	}
+	copyField(next, child, path[1:])
+	if len(next) > 0 {
+		dst[path[0]] = next
+	}
+}
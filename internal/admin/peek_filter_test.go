@@ -0,0 +1,187 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/redis/go-redis/v9"
+)
+
+func newPeekFixture(t *testing.T) (*config.Config, *redis.Client) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	cfg := &config.Config{Worker: config.Worker{Queues: map[string]string{"low": "jobqueue:low_priority"}}}
+	return cfg, rdb
+}
+
+func TestPeekWithOptionsFilterHonorsLimitOnMatches(t *testing.T) {
+	ctx := context.Background()
+	cfg, rdb := newPeekFixture(t)
+
+	// LPUSH puts the newest item at the head; job-0 is consumed first.
+	for i := 0; i < 500; i++ {
+		user := "other"
+		if i%100 == 0 {
+			user = "123"
+		}
+		payload := fmt.Sprintf(`{"id":"job-%d","user_id":%q,"meta":{"size":%d,"tag":"x"}}`, i, user, i)
+		if err := rdb.LPush(ctx, "jobqueue:low_priority", payload).Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rdb.LPush(ctx, "jobqueue:low_priority", "not json").Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := PeekWithOptions(ctx, cfg, rdb, "low", 3, PeekOptions{
+		Filter:  `$.user_id == "123"`,
+		Project: []string{"id", "meta.size"},
+	})
+	if err != nil {
+		t.Fatalf("peek: %v", err)
+	}
+	want := []string{
+		`{"id":"job-200","meta":{"size":200}}`,
+		`{"id":"job-100","meta":{"size":100}}`,
+		`{"id":"job-0","meta":{"size":0}}`,
+	}
+	if len(res.Items) != len(want) {
+		t.Fatalf("expected %d items, got %d: %v", len(want), len(res.Items), res.Items)
+	}
+	for i := range want {
+		if res.Items[i] != want[i] {
+			t.Errorf("item %d: got %s want %s", i, res.Items[i], want[i])
+		}
+	}
+	if res.Scanned != 201 {
+		t.Errorf("expected 201 items scanned, got %d", res.Scanned)
+	}
+}
+
+func TestPeekWithOptionsFilterExcludesNonJSON(t *testing.T) {
+	ctx := context.Background()
+	cfg, rdb := newPeekFixture(t)
+	rdb.LPush(ctx, "jobqueue:low_priority", "plain text", `{"id":"a"}`)
+
+	res, err := PeekWithOptions(ctx, cfg, rdb, "low", 10, PeekOptions{Filter: `$.id`})
+	if err != nil {
+		t.Fatalf("peek: %v", err)
+	}
+	if len(res.Items) != 1 || res.Items[0] != `{"id":"a"}` {
+		t.Fatalf("unexpected items: %v", res.Items)
+	}
+
+	unfiltered, err := PeekWithOptions(ctx, cfg, rdb, "low", 10, PeekOptions{Project: []string{"id"}})
+	if err != nil {
+		t.Fatalf("peek: %v", err)
+	}
+	if len(unfiltered.Items) != 2 {
+		t.Fatalf("projection without filter should keep non-JSON items, got %v", unfiltered.Items)
+	}
+}
+
+func TestPeekWithOptionsScanLimit(t *testing.T) {
+	ctx := context.Background()
+	cfg, rdb := newPeekFixture(t)
+	for i := 0; i < 50; i++ {
+		rdb.LPush(ctx, "jobqueue:low_priority", fmt.Sprintf(`{"n":%d}`, i))
+	}
+
+	res, err := PeekWithOptions(ctx, cfg, rdb, "low", 10, PeekOptions{Filter: `$.n == 40`, ScanLimit: 20})
+	if err != nil {
+		t.Fatalf("peek: %v", err)
+	}
+	if len(res.Items) != 0 || res.Scanned != 20 {
+		t.Fatalf("expected no matches after 20 scanned, got %v (scanned %d)", res.Items, res.Scanned)
+	}
+}
+
+func TestPeekWithOptionsBoundsEveryPage(t *testing.T) {
+	ctx := context.Background()
+	cfg, rdb := newPeekFixture(t)
+	total := DefaultPeekScanLimit + 50
+	for i := int64(0); i < total; i += 1000 {
+		batch := make([]interface{}, 0, 1000)
+		for j := i; j < min(i+1000, total); j++ {
+			batch = append(batch, fmt.Sprintf(`{"n":%d}`, j))
+		}
+		rdb.LPush(ctx, "jobqueue:low_priority", batch...)
+	}
+
+	// the only match is past a default page's worth of items
+	opts := PeekOptions{Filter: fmt.Sprintf(`$.n == %d`, total-1)}
+	res, err := PeekWithOptions(ctx, cfg, rdb, "low", 10, opts)
+	if err != nil {
+		t.Fatalf("peek: %v", err)
+	}
+	if len(res.Items) != 0 || res.Scanned != DefaultPeekScanLimit || !res.HasMore {
+		t.Fatalf("expected the page to stop at the default scan limit, got %d items, scanned %d", len(res.Items), res.Scanned)
+	}
+	opts.Cursor = res.NextCursor
+	res, err = PeekWithOptions(ctx, cfg, rdb, "low", 10, opts)
+	if err != nil {
+		t.Fatalf("peek: %v", err)
+	}
+	if len(res.Items) != 1 || res.Scanned != 50 || res.HasMore {
+		t.Fatalf("expected the match on the next page, got %v, scanned %d", res.Items, res.Scanned)
+	}
+}
+
+func TestPeekWithOptionsOutOfTimeReturnsMatchesSoFar(t *testing.T) {
+	cfg, rdb := newPeekFixture(t)
+	for i := 0; i < 50; i++ {
+		rdb.LPush(context.Background(), "jobqueue:low_priority", fmt.Sprintf(`{"n":%d}`, i))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	res, err := PeekWithOptions(ctx, cfg, rdb, "low", 10, PeekOptions{Filter: `$.n == 40`})
+	if err != nil {
+		t.Fatalf("expected the matches so far rather than an error: %v", err)
+	}
+	if len(res.Items) != 0 || !res.HasMore {
+		t.Fatalf("expected an empty page with more to come, got %+v", res)
+	}
+}
+
+func TestPeekWithOptionsInvalidFilter(t *testing.T) {
+	cfg, rdb := newPeekFixture(t)
+	if _, err := PeekWithOptions(context.Background(), cfg, rdb, "low", 1, PeekOptions{Filter: "$[?("}); err == nil {
+		t.Fatal("expected error for invalid filter")
+	}
+}
+
+func TestPeekWithOptionsDecodesCompressedItems(t *testing.T) {
+	ctx := context.Background()
+	cfg, rdb := newPeekFixture(t)
+	payload := `{"id":"big","user":"alice","blob":"` + strings.Repeat("a", 2000) + `"}`
+	compressed, err := queue.CompressPayload(payload, queue.CompressionGzip, 0)
+	if err != nil || !queue.IsCompressed(compressed) {
+		t.Fatalf("expected compressed payload: %v", err)
+	}
+	rdb.LPush(ctx, "jobqueue:low_priority", compressed)
+
+	res, err := PeekWithOptions(ctx, cfg, rdb, "low", 10, PeekOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Items) != 1 || res.Items[0] != payload {
+		t.Fatalf("expected decoded payload, got %v", res.Items)
+	}
+
+	res, err = PeekWithOptions(ctx, cfg, rdb, "low", 10, PeekOptions{Filter: `$.user == "alice"`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Items) != 1 || res.Items[0] != payload {
+		t.Fatalf("expected filter to match decoded payload, got %d items", len(res.Items))
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/redis/go-redis/v9"
+)
+
+// purgeBatch is both the SCAN count hint and the most keys per UNLINK.
+const purgeBatch = 500
+
+// PurgeMatching deletes every key matching the Redis glob pattern. Keys are
+// found with SCAN, never KEYS, and removed with UNLINK one scan page at a
+// time, so neither step blocks the server on a large keyspace. With dryRun
+// nothing is deleted and only matched is counted. Patterns without a literal
+// prefix, such as "*" or "*:processing", would sweep keys this system does
+// not own and are refused; use PurgeMatchingForce for those. With a config
+// namespace the pattern is taken inside it, so only that namespace's keys
+// can match. Keys written while the scan runs may or may not be included.
+func PurgeMatching(ctx context.Context, cfg *config.Config, rdb *redis.Client, pattern string, dryRun bool) (matched int, deleted int, err error) {
+	return purgeMatching(ctx, cfg, rdb, pattern, dryRun, false)
+}
+
+// PurgeMatchingForce is PurgeMatching without the literal prefix guard.
+func PurgeMatchingForce(ctx context.Context, cfg *config.Config, rdb *redis.Client, pattern string, dryRun bool) (matched int, deleted int, err error) {
+	return purgeMatching(ctx, cfg, rdb, pattern, dryRun, true)
+}
+
+func purgeMatching(ctx context.Context, cfg *config.Config, rdb *redis.Client, pattern string, dryRun, force bool) (int, int, error) {
+	if pattern == "" {
+		return 0, 0, errors.New("purge pattern is empty")
+	}
+	if !force && patternPrefix(pattern) == "" {
+		return 0, 0, fmt.Errorf("refusing to purge %q without force: pattern has no literal prefix", pattern)
+	}
+	pattern = cfg.Key(pattern)
+
+	// SCAN may return a key more than once; count each one once
+	seen := map[string]struct{}{}
+	matched, deleted := 0, 0
+	var cursor uint64
+	for {
+		keys, cur, err := rdb.Scan(ctx, cursor, pattern, purgeBatch).Result()
+		if err != nil {
+			return matched, deleted, err
+		}
+		cursor = cur
+		batch := keys[:0]
+		for _, k := range keys {
+			if _, ok := seen[k]; !ok {
+				seen[k] = struct{}{}
+				batch = append(batch, k)
+			}
+		}
+		matched += len(batch)
+		if !dryRun && len(batch) > 0 {
+			n, err := rdb.Unlink(ctx, batch...).Result()
+			if err != nil {
+				return matched, deleted, err
+			}
+			deleted += int(n)
+		}
+		if cursor == 0 {
+			break
+		}
+	}
+	return matched, deleted, nil
+}
+
+// patternPrefix returns the part of a glob pattern before its first special
+// character.
+func patternPrefix(pattern string) string {
+	if i := strings.IndexAny(pattern, `*?[\`); i >= 0 {
+		return pattern[:i]
+	}
+	return pattern
+}
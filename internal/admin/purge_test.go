@@ -0,0 +1,62 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestPurgeMatchingDryRunThenDelete(t *testing.T) {
+	ctx := context.Background()
+	cfg, rdb := newPeekFixture(t)
+	for i := 0; i < 1200; i++ {
+		rdb.Set(ctx, fmt.Sprintf("jobqueue:tmp:%d", i), "x", 0)
+	}
+	rdb.Set(ctx, "jobqueue:keep", "x", 0)
+	rdb.Set(ctx, "other:tmp:1", "x", 0)
+
+	matched, deleted, err := PurgeMatching(ctx, cfg, rdb, "jobqueue:tmp:*", true)
+	if err != nil || matched != 1200 || deleted != 0 {
+		t.Fatalf("dry run: matched=%d deleted=%d err=%v", matched, deleted, err)
+	}
+	if n := rdb.DBSize(ctx).Val(); n != 1202 {
+		t.Fatalf("dry run must not delete, have %d keys", n)
+	}
+
+	// 1, 10-19, 100-199 and 1000-1199; kept to one scan page because
+	// miniredis cursors are offsets that shift when keys are deleted
+	matched, deleted, err = PurgeMatching(ctx, cfg, rdb, "jobqueue:tmp:1*", false)
+	if err != nil || matched != 311 || deleted != 311 {
+		t.Fatalf("purge: matched=%d deleted=%d err=%v", matched, deleted, err)
+	}
+	if n := rdb.DBSize(ctx).Val(); n != 1202-311 {
+		t.Fatalf("expected only the unmatched keys to remain, have %d", n)
+	}
+	if rdb.Exists(ctx, "jobqueue:tmp:2", "jobqueue:keep").Val() != 2 {
+		t.Fatal("unmatched keys were deleted")
+	}
+}
+
+func TestPurgeMatchingRefusesPatternsWithoutPrefix(t *testing.T) {
+	ctx := context.Background()
+	cfg, rdb := newPeekFixture(t)
+	rdb.Set(ctx, "a:processing", "x", 0)
+
+	for _, pat := range []string{"", "*", "*:processing", "?:processing", "[ab]:processing"} {
+		if _, _, err := PurgeMatching(ctx, cfg, rdb, pat, false); err == nil {
+			t.Fatalf("expected %q to be refused", pat)
+		}
+	}
+	if n := rdb.DBSize(ctx).Val(); n != 1 {
+		t.Fatal("refused purges must not delete anything")
+	}
+
+	if _, _, err := PurgeMatchingForce(ctx, cfg, rdb, "", false); err == nil {
+		t.Fatal("an empty pattern is refused even with force")
+	}
+	matched, deleted, err := PurgeMatchingForce(ctx, cfg, rdb, "*:processing", false)
+	if err != nil || matched != 1 || deleted != 1 {
+		t.Fatalf("forced purge: matched=%d deleted=%d err=%v", matched, deleted, err)
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/flyingrobots/go-redis-work-queue/internal/scheduler"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// ErrInvalidQueueDefinition wraps every validation failure of a
+	// queue definition.
+	ErrInvalidQueueDefinition = errors.New("invalid queue definition")
+	ErrQueueExists            = errors.New("queue already defined")
+	ErrQueueNotDefined        = errors.New("queue not defined")
+	// ErrQueueNotEmpty is returned by DeleteQueueDefinition without force
+	// while the queue still holds jobs.
+	ErrQueueNotEmpty = errors.New("queue is not empty")
+)
+
+var queueNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9:._-]{0,199}$`)
+
+// ListQueueDefinitions returns every queue definition in cfg's namespace,
+// by name.
+func ListQueueDefinitions(ctx context.Context, cfg *config.Config, rdb *redis.Client) ([]queue.Definition, error) {
+	defs, err := queue.LoadDefinitions(ctx, rdb, cfg.Key(queue.DefinitionsKey))
+	if err != nil {
+		return nil, err
+	}
+	out := make([]queue.Definition, 0, len(defs))
+	for _, d := range defs {
+		out = append(out, d)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// GetQueueDefinition returns the definition of the queue name, a key in
+// cfg's namespace.
+func GetQueueDefinition(ctx context.Context, cfg *config.Config, rdb *redis.Client, name string) (*queue.Definition, error) {
+	return getQueueDefinition(ctx, cfg, rdb, cfg.Key(name))
+}
+
+func getQueueDefinition(ctx context.Context, cfg *config.Config, rdb redis.Cmdable, name string) (*queue.Definition, error) {
+	raw, err := rdb.HGet(ctx, cfg.Key(queue.DefinitionsKey), name).Result()
+	if err == redis.Nil {
+		return nil, fmt.Errorf("%w: %s", ErrQueueNotDefined, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var def queue.Definition
+	if err := json.Unmarshal([]byte(raw), &def); err != nil {
+		return nil, fmt.Errorf("decode definition of %s: %w", name, err)
+	}
+	def.Name = name
+	return &def, nil
+}
+
+// CreateQueueDefinition stores a new queue definition. An empty priority
+// becomes producer.default_priority. Workers and producers pick it up
+// within worker.pause_cache_ttl.
+func CreateQueueDefinition(ctx context.Context, cfg *config.Config, rdb *redis.Client, def queue.Definition) (*queue.Definition, error) {
+	if err := validateQueueDefinition(cfg, &def); err != nil {
+		return nil, err
+	}
+	def.CreatedAt = time.Now().UTC()
+	def.UpdatedAt = def.CreatedAt
+	b, _ := json.Marshal(def)
+	ok, err := rdb.HSetNX(ctx, cfg.Key(queue.DefinitionsKey), def.Name, b).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrQueueExists, def.Name)
+	}
+	return &def, nil
+}
+
+// UpdateQueueDefinition replaces the definition of an existing queue,
+// keeping its creation time.
+func UpdateQueueDefinition(ctx context.Context, cfg *config.Config, rdb *redis.Client, def queue.Definition) (*queue.Definition, error) {
+	if err := validateQueueDefinition(cfg, &def); err != nil {
+		return nil, err
+	}
+	defsKey := cfg.Key(queue.DefinitionsKey)
+	for attempt := 0; attempt < 3; attempt++ {
+		err := rdb.Watch(ctx, func(tx *redis.Tx) error {
+			cur, err := getQueueDefinition(ctx, cfg, tx, def.Name)
+			if err != nil {
+				return err
+			}
+			def.CreatedAt = cur.CreatedAt
+			def.UpdatedAt = time.Now().UTC()
+			b, _ := json.Marshal(def)
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.HSet(ctx, defsKey, def.Name, b)
+				return nil
+			})
+			return err
+		}, defsKey)
+		if err != redis.TxFailedErr {
+			if err != nil {
+				return nil, err
+			}
+			return &def, nil
+		}
+	}
+	return nil, fmt.Errorf("update %s: definitions changed concurrently, try again", def.Name)
+}
+
+// DeleteQueueDefinition removes the definition of the queue name, a key in
+// cfg's namespace. A queue that only exists through its definition must be
+// empty (list, scheduled and delayed sets, and stream) unless force is
+// set, in which case its jobs are deleted with it; the number deleted is
+// returned. Removing the definition of a configured queue only drops its
+// overrides.
+func DeleteQueueDefinition(ctx context.Context, cfg *config.Config, rdb *redis.Client, name string, force bool) (int64, error) {
+	name = cfg.Key(name)
+	defsKey := cfg.Key(queue.DefinitionsKey)
+	configured := false
+	for _, key := range cfg.Worker.Queues {
+		configured = configured || key == name
+	}
+	keys := []string{name, cfg.DerivedKey(scheduler.ScheduledKey, name), cfg.DerivedKey(scheduler.DelayedKey, name), queue.StreamKey(name)}
+
+	var deleted int64
+	err := rdb.Watch(ctx, func(tx *redis.Tx) error {
+		if _, err := getQueueDefinition(ctx, cfg, tx, name); err != nil {
+			return err
+		}
+		if !configured {
+			n, err := queueJobCount(ctx, cfg, tx, name)
+			if err != nil {
+				return err
+			}
+			if n > 0 && !force {
+				return fmt.Errorf("%w: %s holds %d jobs; delete with force to drop them", ErrQueueNotEmpty, name, n)
+			}
+			deleted = n
+		}
+		_, err := tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HDel(ctx, defsKey, name)
+			if !configured {
+				pipe.Del(ctx, append(keys, cfg.DerivedKey(queue.RateLimitKey, name))...)
+			}
+			return nil
+		})
+		return err
+	}, append(keys, defsKey)...)
+	if err == redis.TxFailedErr {
+		return 0, fmt.Errorf("delete %s: the queue changed while deleting, try again", name)
+	}
+	return deleted, err
+}
+
+// queueJobCount counts the jobs waiting in the queue name in every form.
+func queueJobCount(ctx context.Context, cfg *config.Config, tx *redis.Tx, name string) (int64, error) {
+	var n int64
+	for _, cmd := range []*redis.IntCmd{
+		tx.LLen(ctx, name),
+		tx.ZCard(ctx, cfg.DerivedKey(scheduler.ScheduledKey, name)),
+		tx.ZCard(ctx, cfg.DerivedKey(scheduler.DelayedKey, name)),
+		tx.XLen(ctx, queue.StreamKey(name)),
+	} {
+		c, err := cmd.Result()
+		if err != nil {
+			return 0, err
+		}
+		n += c
+	}
+	return n, nil
+}
+
+// validateQueueDefinition checks def, moves its keys into cfg's namespace
+// and fills in its priority.
+func validateQueueDefinition(cfg *config.Config, def *queue.Definition) error {
+	invalid := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: %s", ErrInvalidQueueDefinition, fmt.Sprintf(format, args...))
+	}
+	if !queueNamePattern.MatchString(def.Name) {
+		return invalid("name %q must be 1-200 letters, digits, ':', '.', '_' or '-', starting with a letter or digit", def.Name)
+	}
+	if _, ok := cfg.Worker.Queues[def.Name]; ok {
+		return invalid("name %q is a priority alias; use its queue key %q to override that queue", def.Name, cfg.Worker.Queues[def.Name])
+	}
+	switch name := cfg.Key(def.Name); def.Name {
+	case "dlq", "all", "completed", "dead_letter":
+		return invalid("name %q is reserved", def.Name)
+	default:
+		if name == cfg.Worker.CompletedList || name == cfg.Worker.DeadLetterList {
+			return invalid("name %q is reserved", def.Name)
+		}
+		def.Name = name
+	}
+	if def.Priority == "" {
+		def.Priority = cfg.Producer.DefaultPriority
+	}
+	if def.RateLimit < 0 {
+		return invalid("rate_limit must be >= 0, got %g", def.RateLimit)
+	}
+	if def.Burst < 0 {
+		return invalid("burst must be >= 0, got %d", def.Burst)
+	}
+	if def.MaxLength < 0 {
+		return invalid("max_length must be >= 0, got %d", def.MaxLength)
+	}
+	if n := def.DeadLetter.MaxRetries; n != nil && *n < 0 {
+		return invalid("dead_letter.max_retries must be >= 0, got %d", *n)
+	}
+	if l := def.DeadLetter.List; l != "" && (cfg.Key(l) == def.Name || !queueNamePattern.MatchString(l)) {
+		return invalid("dead_letter.list %q must be a valid key other than the queue", l)
+	}
+	def.DeadLetter.List = cfg.Key(def.DeadLetter.List)
+	return nil
+}
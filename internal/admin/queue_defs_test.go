@@ -0,0 +1,102 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/flyingrobots/go-redis-work-queue/internal/scheduler"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestQueueDefinitionLifecycle(t *testing.T) {
+	ctx := context.Background()
+	cfg, rdb := newPeekFixture(t)
+	cfg.Producer.DefaultPriority = "low"
+
+	created, err := CreateQueueDefinition(ctx, cfg, rdb, queue.Definition{Name: "jobqueue:reports", RateLimit: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Priority != "low" || created.CreatedAt.IsZero() {
+		t.Fatalf("expected the default priority and a creation time, got %+v", created)
+	}
+	if _, err := CreateQueueDefinition(ctx, cfg, rdb, queue.Definition{Name: "jobqueue:reports"}); !errors.Is(err, ErrQueueExists) {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	retries := 1
+	updated, err := UpdateQueueDefinition(ctx, cfg, rdb, queue.Definition{Name: "jobqueue:reports", Priority: "low", DeadLetter: queue.DeadLetterPolicy{MaxRetries: &retries}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !updated.CreatedAt.Equal(created.CreatedAt) || !updated.UpdatedAt.After(created.UpdatedAt) || updated.RateLimit != 0 {
+		t.Fatalf("expected the definition replaced and its creation time kept, got %+v", updated)
+	}
+	if _, err := UpdateQueueDefinition(ctx, cfg, rdb, queue.Definition{Name: "jobqueue:missing"}); !errors.Is(err, ErrQueueNotDefined) {
+		t.Fatalf("expected not defined, got %v", err)
+	}
+
+	defs, err := ListQueueDefinitions(ctx, cfg, rdb)
+	if err != nil || len(defs) != 1 || *defs[0].DeadLetter.MaxRetries != 1 {
+		t.Fatalf("unexpected definitions %+v (%v)", defs, err)
+	}
+
+	rdb.LPush(ctx, "jobqueue:reports", "a", "b")
+	rdb.ZAdd(ctx, scheduler.DelayedKey("jobqueue:reports"), redis.Z{Score: 1, Member: "c"})
+	if _, err := DeleteQueueDefinition(ctx, cfg, rdb, "jobqueue:reports", false); !errors.Is(err, ErrQueueNotEmpty) {
+		t.Fatalf("expected a non-empty queue to be kept, got %v", err)
+	}
+	n, err := DeleteQueueDefinition(ctx, cfg, rdb, "jobqueue:reports", true)
+	if err != nil || n != 3 {
+		t.Fatalf("expected 3 jobs deleted with force, got %d (%v)", n, err)
+	}
+	if k, _ := rdb.Exists(ctx, "jobqueue:reports", scheduler.DelayedKey("jobqueue:reports"), queue.DefinitionsKey).Result(); k != 0 {
+		t.Fatalf("expected the queue and its definition gone, %d keys left", k)
+	}
+	if _, err := GetQueueDefinition(ctx, cfg, rdb, "jobqueue:reports"); !errors.Is(err, ErrQueueNotDefined) {
+		t.Fatalf("expected not defined after delete, got %v", err)
+	}
+}
+
+func TestDeleteConfiguredQueueDefinitionKeepsJobs(t *testing.T) {
+	ctx := context.Background()
+	cfg, rdb := newPeekFixture(t)
+	if _, err := CreateQueueDefinition(ctx, cfg, rdb, queue.Definition{Name: "jobqueue:low_priority", Priority: "low", MaxLength: 10}); err != nil {
+		t.Fatal(err)
+	}
+	rdb.LPush(ctx, "jobqueue:low_priority", "a")
+	if n, err := DeleteQueueDefinition(ctx, cfg, rdb, "jobqueue:low_priority", false); err != nil || n != 0 {
+		t.Fatalf("expected only the override dropped, got %d (%v)", n, err)
+	}
+	if l, _ := rdb.LLen(ctx, "jobqueue:low_priority").Result(); l != 1 {
+		t.Fatalf("expected the configured queue untouched, got length %d", l)
+	}
+}
+
+func TestValidateQueueDefinition(t *testing.T) {
+	cfg, _ := newPeekFixture(t)
+	cfg.Worker.DeadLetterList = "jobqueue:dead_letter"
+	neg := -1
+	for _, def := range []queue.Definition{
+		{Name: ""},
+		{Name: "-x"},
+		{Name: "has space"},
+		{Name: "dlq"},
+		{Name: "jobqueue:dead_letter"},
+		{Name: "low"},
+		{Name: "q", RateLimit: -1},
+		{Name: "q", Burst: -1},
+		{Name: "q", MaxLength: -1},
+		{Name: "q", DeadLetter: queue.DeadLetterPolicy{MaxRetries: &neg}},
+		{Name: "q", DeadLetter: queue.DeadLetterPolicy{List: "q"}},
+	} {
+		if err := validateQueueDefinition(cfg, &def); !errors.Is(err, ErrInvalidQueueDefinition) {
+			t.Errorf("expected %+v to be rejected, got %v", def, err)
+		}
+	}
+}
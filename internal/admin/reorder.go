@@ -0,0 +1,80 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/redis/go-redis/v9"
+)
+
+// reorderScript moves one copy of ARGV[1] within the list KEYS[1]: to the
+// consuming (right) end when ARGV[2] is "promote", else to the far (left)
+// end. Removing and pushing in one script means no worker can pop the item
+// while it is out of the list. It returns 0 when the item is gone.
+var reorderScript = redis.NewScript(`
+if redis.call('LREM', KEYS[1], 1, ARGV[1]) == 0 then
+  return 0
+end
+if ARGV[2] == 'promote' then
+  redis.call('RPUSH', KEYS[1], ARGV[1])
+else
+  redis.call('LPUSH', KEYS[1], ARGV[1])
+end
+return 1
+`)
+
+// Promote moves the job jobID, or the item equal to jobID, to the front of
+// the line in queue (an alias or key as for Peek), so it is the next one a
+// worker pops. Other items keep their order. It returns ErrJobNotFound when
+// the queue does not hold the job, including when a worker took it while
+// it was being looked up.
+func Promote(ctx context.Context, cfg *config.Config, rdb *redis.Client, queue, jobID string) error {
+	return reorderJob(ctx, cfg, rdb, queue, jobID, true)
+}
+
+// Demote moves the job jobID to the back of the line in queue, so every
+// item already there runs before it. Errors are as for Promote.
+func Demote(ctx context.Context, cfg *config.Config, rdb *redis.Client, queue, jobID string) error {
+	return reorderJob(ctx, cfg, rdb, queue, jobID, false)
+}
+
+func reorderJob(ctx context.Context, cfg *config.Config, rdb *redis.Client, queueAlias, jobID string, promote bool) error {
+	if jobID == "" {
+		return errors.New("job id is required")
+	}
+	key, err := resolveQueue(cfg, queueAlias)
+	if err != nil {
+		return err
+	}
+	if cfg.Worker.Mode == config.ModeStream {
+		for _, q := range cfg.Worker.Queues {
+			if q == key {
+				return fmt.Errorf("%s is a stream in stream mode and cannot be reordered", key)
+			}
+		}
+	}
+
+	members, err := findBulkMembers(ctx, rdb, key, []string{jobID})
+	if err != nil {
+		return err
+	}
+	member, ok := members[0]
+	if !ok {
+		return fmt.Errorf("%w in %s: %s", ErrJobNotFound, key, jobID)
+	}
+	direction := "demote"
+	if promote {
+		direction = "promote"
+	}
+	moved, err := reorderScript.Run(ctx, rdb, []string{key}, member, direction).Int()
+	if err != nil {
+		return err
+	}
+	if moved == 0 {
+		return fmt.Errorf("%w in %s: %s was taken before it could be moved", ErrJobNotFound, key, jobID)
+	}
+	return nil
+}
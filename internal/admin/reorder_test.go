@@ -0,0 +1,55 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+)
+
+func TestPromoteAndDemoteReorderOneJob(t *testing.T) {
+	ctx := context.Background()
+	cfg, rdb := newPeekFixture(t)
+	key := cfg.Worker.Queues["low"]
+	for _, id := range []string{"a", "b", "c", "d"} {
+		raw, _ := queue.NewJob(id, "/tmp/f", 1, "low", "", "").Marshal()
+		rdb.LPush(ctx, key, raw)
+	}
+	order := func() string {
+		items, _ := rdb.LRange(ctx, key, 0, -1).Result()
+		ids := ""
+		for _, it := range items {
+			job, _ := queue.UnmarshalJob(it)
+			ids += job.ID
+		}
+		return ids
+	}
+	// a was pushed first, so it sits at the consuming (right) end
+	if got := order(); got != "dcba" {
+		t.Fatalf("unexpected initial order %q", got)
+	}
+
+	if err := Promote(ctx, cfg, rdb, "low", "c"); err != nil {
+		t.Fatal(err)
+	}
+	if got := order(); got != "dbac" {
+		t.Fatalf("expected c to pop next, got %q", got)
+	}
+	if err := Demote(ctx, cfg, rdb, key, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if got := order(); got != "adbc" {
+		t.Fatalf("expected a to pop last, got %q", got)
+	}
+
+	if err := Promote(ctx, cfg, rdb, "low", "missing"); !errors.Is(err, ErrJobNotFound) {
+		t.Fatalf("expected ErrJobNotFound, got %v", err)
+	}
+	cfg.Worker.Mode = config.ModeStream
+	if err := Promote(ctx, cfg, rdb, "low", "c"); err == nil {
+		t.Fatal("expected stream mode queues to be rejected")
+	}
+}
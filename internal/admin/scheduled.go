@@ -0,0 +1,144 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/scheduler"
+	"github.com/redis/go-redis/v9"
+)
+
+// maxScheduledPreview bounds the payload preview of a scheduled job.
+const maxScheduledPreview = 120
+
+// ScheduledJob is a job waiting in a queue's scheduled: or delayed: sorted
+// set for the scheduler to promote it.
+type ScheduledJob struct {
+	Queue   string    `json:"queue"`   // queue key the job will join
+	Set     string    `json:"set"`     // sorted set holding it
+	DueAt   time.Time `json:"due_at"`  // in the past when the scheduler is behind
+	Member  string    `json:"member"`  // raw sorted set member, as CancelScheduled takes it
+	Preview string    `json:"preview"` // decoded payload, truncated
+}
+
+// ScheduledOptions narrows ListScheduledWithOptions.
+type ScheduledOptions struct {
+	// DueWithin keeps only jobs due before now plus DueWithin, overdue
+	// ones included. Zero lists everything.
+	DueWithin time.Duration
+}
+
+// ListScheduled returns up to n jobs waiting to be promoted onto queue,
+// soonest first, from both its scheduled: and delayed: sets. queue is a
+// priority alias or queue key; an empty queue covers every configured
+// queue.
+func ListScheduled(ctx context.Context, cfg *config.Config, rdb *redis.Client, queue string, n int) ([]ScheduledJob, error) {
+	return ListScheduledWithOptions(ctx, cfg, rdb, queue, n, ScheduledOptions{})
+}
+
+// ListScheduledWithOptions is ListScheduled with a due-within filter.
+func ListScheduledWithOptions(ctx context.Context, cfg *config.Config, rdb *redis.Client, queue string, n int, opts ScheduledOptions) ([]ScheduledJob, error) {
+	if n <= 0 {
+		n = 10
+	}
+	queues, err := scheduledQueues(cfg, queue)
+	if err != nil {
+		return nil, err
+	}
+	until := "+inf"
+	if opts.DueWithin > 0 {
+		until = strconv.FormatFloat(scheduler.Score(time.Now().Add(opts.DueWithin)), 'f', -1, 64)
+	}
+
+	// Each set is already in due order, so n from each is enough to merge.
+	jobs := make([]ScheduledJob, 0)
+	for _, q := range queues {
+		for _, set := range []string{cfg.DerivedKey(scheduler.ScheduledKey, q), cfg.DerivedKey(scheduler.DelayedKey, q)} {
+			zs, err := rdb.ZRangeByScoreWithScores(ctx, set, &redis.ZRangeBy{Min: "-inf", Max: until, Count: int64(n)}).Result()
+			if err != nil {
+				return nil, err
+			}
+			for _, z := range zs {
+				member, _ := z.Member.(string)
+				sec, frac := math.Modf(z.Score)
+				jobs = append(jobs, ScheduledJob{
+					Queue:   q,
+					Set:     set,
+					DueAt:   time.Unix(int64(sec), int64(frac*1e9)).UTC(),
+					Member:  member,
+					Preview: previewPayload(decodeItem(member)),
+				})
+			}
+		}
+	}
+	sort.SliceStable(jobs, func(i, j int) bool { return jobs[i].DueAt.Before(jobs[j].DueAt) })
+	if len(jobs) > n {
+		jobs = jobs[:n]
+	}
+	return jobs, nil
+}
+
+// CancelScheduled removes one scheduled or delayed job from queue before the
+// scheduler promotes it. member is ScheduledJob.Member. It fails when the
+// job is no longer waiting, e.g. because it was already promoted.
+func CancelScheduled(ctx context.Context, cfg *config.Config, rdb *redis.Client, queue, member string) error {
+	key, err := resolveWorkQueue(cfg, queue)
+	if err != nil {
+		return err
+	}
+	var scheduled, delayed *redis.IntCmd
+	if _, err := rdb.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		scheduled = p.ZRem(ctx, cfg.DerivedKey(scheduler.ScheduledKey, key), member)
+		delayed = p.ZRem(ctx, cfg.DerivedKey(scheduler.DelayedKey, key), member)
+		return nil
+	}); err != nil {
+		return err
+	}
+	if scheduled.Val()+delayed.Val() == 0 {
+		return fmt.Errorf("job is not scheduled on %s; it may already have been promoted", key)
+	}
+	return nil
+}
+
+// scheduledQueues resolves the queue argument of ListScheduled.
+func scheduledQueues(cfg *config.Config, queue string) ([]string, error) {
+	if queue != "" {
+		key, err := resolveWorkQueue(cfg, queue)
+		if err != nil {
+			return nil, err
+		}
+		return []string{key}, nil
+	}
+	// priority order first, then any queue not listed in priorities
+	keys := make([]string, 0, len(cfg.Worker.Queues))
+	seen := map[string]bool{}
+	for _, p := range cfg.Worker.Priorities {
+		if key := cfg.Worker.Queues[p]; key != "" && !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	rest := make([]string, 0)
+	for _, key := range cfg.Worker.Queues {
+		if key != "" && !seen[key] {
+			seen[key] = true
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+	return append(keys, rest...), nil
+}
+
+func previewPayload(s string) string {
+	r := []rune(s)
+	if len(r) <= maxScheduledPreview {
+		return s
+	}
+	return string(r[:maxScheduledPreview]) + "..."
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/flyingrobots/go-redis-work-queue/internal/scheduler"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestListScheduledMergesSetsInDueOrder(t *testing.T) {
+	ctx := context.Background()
+	cfg, rdb := newPeekFixture(t)
+	cfg.Worker.Queues["high"] = "jobqueue:high_priority"
+	cfg.Worker.Priorities = []string{"high", "low"}
+	now := time.Now()
+
+	zadd := func(key, member string, at time.Time) {
+		t.Helper()
+		if err := rdb.ZAdd(ctx, key, redis.Z{Score: scheduler.Score(at), Member: member}).Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	zadd(scheduler.ScheduledKey("jobqueue:low_priority"), `{"id":"later"}`, now.Add(2*time.Hour))
+	zadd(scheduler.DelayedKey("jobqueue:low_priority"), `{"id":"soon"}`, now.Add(time.Minute))
+	zadd(scheduler.ScheduledKey("jobqueue:high_priority"), `{"id":"overdue"}`, now.Add(-time.Second))
+	compressed, err := queue.CompressPayload(`{"id":"packed","blob":"`+strings.Repeat("x", 500)+`"}`, "gzip", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zadd(scheduler.DelayedKey("jobqueue:high_priority"), compressed, now.Add(30*time.Minute))
+
+	jobs, err := ListScheduled(ctx, cfg, rdb, "", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{`{"id":"overdue"}`, `{"id":"soon"}`, `{"id":"packed"`, `{"id":"later"}`}
+	if len(jobs) != len(want) {
+		t.Fatalf("expected %d jobs, got %+v", len(want), jobs)
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(jobs[i].Preview, prefix) {
+			t.Fatalf("job %d: want %s..., got %s", i, prefix, jobs[i].Preview)
+		}
+	}
+	if jobs[2].Member != compressed || !strings.HasSuffix(jobs[2].Preview, "...") {
+		t.Fatalf("expected the raw member and a truncated decoded preview, got %+v", jobs[2])
+	}
+	if jobs[0].Queue != "jobqueue:high_priority" || jobs[0].Set != "scheduled:jobqueue:high_priority" {
+		t.Fatalf("unexpected queue/set: %+v", jobs[0])
+	}
+	if d := jobs[1].DueAt.Sub(now.Add(time.Minute)); d > time.Millisecond || d < -time.Millisecond {
+		t.Fatalf("due time off by %s", d)
+	}
+
+	within, err := ListScheduledWithOptions(ctx, cfg, rdb, "low", 10, ScheduledOptions{DueWithin: 5 * time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(within) != 1 || within[0].Preview != `{"id":"soon"}` {
+		t.Fatalf("due within 5m on low: %+v", within)
+	}
+
+	if limited, _ := ListScheduled(ctx, cfg, rdb, "", 2); len(limited) != 2 || limited[1].Preview != `{"id":"soon"}` {
+		t.Fatalf("expected the 2 soonest, got %+v", limited)
+	}
+	if _, err := ListScheduled(ctx, cfg, rdb, "completed", 10); err == nil {
+		t.Fatal("expected an error for a non-worker queue")
+	}
+}
+
+func TestCancelScheduled(t *testing.T) {
+	ctx := context.Background()
+	cfg, rdb := newPeekFixture(t)
+	key := scheduler.DelayedKey("jobqueue:low_priority")
+	rdb.ZAdd(ctx, key, redis.Z{Score: scheduler.Score(time.Now().Add(time.Hour)), Member: `{"id":"a"}`})
+	rdb.ZAdd(ctx, key, redis.Z{Score: scheduler.Score(time.Now().Add(time.Hour)), Member: `{"id":"b"}`})
+
+	if err := CancelScheduled(ctx, cfg, rdb, "low", `{"id":"a"}`); err != nil {
+		t.Fatal(err)
+	}
+	if members, _ := rdb.ZRange(ctx, key, 0, -1).Result(); len(members) != 1 || members[0] != `{"id":"b"}` {
+		t.Fatalf("expected only b left, got %v", members)
+	}
+	if err := CancelScheduled(ctx, cfg, rdb, "low", `{"id":"a"}`); err == nil {
+		t.Fatal("expected an error cancelling a job that is no longer scheduled")
+	}
+}
@@ -0,0 +1,297 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/scheduler"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// SnapshotFormat identifies the first line of a snapshot stream.
+	SnapshotFormat = "go-redis-work-queue/snapshot"
+	// SnapshotVersion is the version written by ExportSnapshot. ImportSnapshot
+	// accepts this and every earlier version.
+	SnapshotVersion = 1
+
+	snapshotBatch = 1000
+)
+
+// ImportMode controls how ImportSnapshot treats keys that already exist.
+type ImportMode string
+
+const (
+	// ImportMerge appends list items after existing ones and adds zset
+	// members, updating the score of members already present.
+	ImportMerge ImportMode = "merge"
+	// ImportReplace deletes each key in the snapshot before restoring it.
+	// Keys that are not in the snapshot are left alone.
+	ImportReplace ImportMode = "replace"
+)
+
+// snapshotRecord is one line of a snapshot. Type is "header", "key", "item"
+// or "end"; unknown types are skipped on import so later versions can add
+// records without breaking older readers.
+type snapshotRecord struct {
+	Type string `json:"type"`
+
+	// header
+	Format    string            `json:"format,omitempty"`
+	Version   int               `json:"version,omitempty"`
+	CreatedAt *time.Time        `json:"created_at,omitempty"`
+	Queues    map[string]string `json:"queues,omitempty"`
+
+	// key and item
+	Key   string `json:"key,omitempty"`
+	Kind  string `json:"kind,omitempty"`   // key: list or zset
+	Count int64  `json:"count,omitempty"`  // key: items that follow; end: total items
+	TTLMs int64  `json:"ttl_ms,omitempty"` // key: remaining TTL, 0 for none
+
+	Value *string  `json:"value,omitempty"`
+	Score *float64 `json:"score,omitempty"`
+
+	// end
+	Keys int `json:"keys,omitempty"`
+}
+
+// ExportSnapshot streams every managed queue as newline-delimited JSON: the
+// priority queues and their scheduled and delayed sets, completed and dead
+// letter lists, worker processing lists and any other list or sorted set
+// under the jobqueue: prefix. Each item records the key it came from. Items are read in
+// batches, so memory use does not grow with queue size. The snapshot is not
+// atomic; pause producers and workers for an exact copy.
+func ExportSnapshot(ctx context.Context, cfg *config.Config, rdb *redis.Client, w io.Writer) error {
+	keys, err := snapshotKeys(ctx, cfg, rdb)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	now := time.Now().UTC()
+	if err := enc.Encode(snapshotRecord{Type: "header", Format: SnapshotFormat, Version: SnapshotVersion, CreatedAt: &now, Queues: cfg.Worker.Queues}); err != nil {
+		return err
+	}
+
+	var total int64
+	written := 0
+	for _, key := range keys {
+		kind, err := rdb.Type(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+		var n int64
+		switch kind {
+		case "list":
+			n, err = rdb.LLen(ctx, key).Result()
+		case "zset":
+			n, err = rdb.ZCard(ctx, key).Result()
+		default:
+			continue // gone or not a queue
+		}
+		if err != nil {
+			return err
+		}
+		ttl, err := rdb.PTTL(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+		rec := snapshotRecord{Type: "key", Key: key, Kind: kind, Count: n}
+		if ttl > 0 {
+			rec.TTLMs = ttl.Milliseconds()
+		}
+		if err := enc.Encode(rec); err != nil {
+			return err
+		}
+
+		for start := int64(0); start < n; start += snapshotBatch {
+			stop := start + snapshotBatch - 1
+			if kind == "list" {
+				items, err := rdb.LRange(ctx, key, start, stop).Result()
+				if err != nil {
+					return err
+				}
+				for i := range items {
+					if err := enc.Encode(snapshotRecord{Type: "item", Key: key, Value: &items[i]}); err != nil {
+						return err
+					}
+				}
+				total += int64(len(items))
+				continue
+			}
+			members, err := rdb.ZRangeWithScores(ctx, key, start, stop).Result()
+			if err != nil {
+				return err
+			}
+			for i := range members {
+				v := fmt.Sprint(members[i].Member)
+				if err := enc.Encode(snapshotRecord{Type: "item", Key: key, Value: &v, Score: &members[i].Score}); err != nil {
+					return err
+				}
+			}
+			total += int64(len(members))
+		}
+		written++
+	}
+	return enc.Encode(snapshotRecord{Type: "end", Keys: written, Count: total})
+}
+
+// ImportSnapshot restores a stream written by ExportSnapshot. Items are
+// written in pipelined batches as they are read, so a failed import may have
+// restored some keys; the error says which record failed. A stream without
+// its end record is reported as truncated.
+func ImportSnapshot(ctx context.Context, cfg *config.Config, rdb *redis.Client, r io.Reader, mode ImportMode) error {
+	if mode != ImportMerge && mode != ImportReplace {
+		return fmt.Errorf("unknown import mode %q (want merge or replace)", mode)
+	}
+	dec := json.NewDecoder(r)
+
+	var header snapshotRecord
+	if err := dec.Decode(&header); err != nil {
+		return fmt.Errorf("read snapshot header: %w", err)
+	}
+	if header.Type != "header" || header.Format != SnapshotFormat {
+		return errors.New("not a queue snapshot: missing header")
+	}
+	if header.Version < 1 || header.Version > SnapshotVersion {
+		return fmt.Errorf("unsupported snapshot version %d (this build reads up to %d)", header.Version, SnapshotVersion)
+	}
+
+	kinds := map[string]string{}
+	ttls := map[string]int64{}
+	pipe := rdb.Pipeline()
+	pending := 0
+	flush := func() error {
+		if pending == 0 {
+			return nil
+		}
+		pending = 0
+		_, err := pipe.Exec(ctx)
+		return err
+	}
+
+	var items int64
+	for line := 2; ; line++ {
+		var rec snapshotRecord
+		if err := dec.Decode(&rec); err != nil {
+			if err == io.EOF {
+				return errors.New("truncated snapshot: missing end record")
+			}
+			return fmt.Errorf("record %d: %w", line, err)
+		}
+		switch rec.Type {
+		case "key":
+			if rec.Kind != "list" && rec.Kind != "zset" {
+				return fmt.Errorf("record %d: key %q has unsupported kind %q", line, rec.Key, rec.Kind)
+			}
+			if err := flush(); err != nil {
+				return fmt.Errorf("record %d: %w", line, err)
+			}
+			if mode == ImportReplace {
+				if err := rdb.Del(ctx, rec.Key).Err(); err != nil {
+					return fmt.Errorf("record %d: %w", line, err)
+				}
+			} else if typ, err := rdb.Type(ctx, rec.Key).Result(); err != nil {
+				return fmt.Errorf("record %d: %w", line, err)
+			} else if typ != "none" && typ != rec.Kind {
+				return fmt.Errorf("record %d: key %q is a %s in Redis but a %s in the snapshot", line, rec.Key, typ, rec.Kind)
+			}
+			kinds[rec.Key] = rec.Kind
+			if rec.TTLMs > 0 {
+				ttls[rec.Key] = rec.TTLMs
+			}
+		case "item":
+			kind, ok := kinds[rec.Key]
+			if !ok {
+				return fmt.Errorf("record %d: item for undeclared key %q", line, rec.Key)
+			}
+			if rec.Value == nil {
+				return fmt.Errorf("record %d: item without value", line)
+			}
+			if kind == "list" {
+				pipe.RPush(ctx, rec.Key, *rec.Value)
+			} else {
+				if rec.Score == nil {
+					return fmt.Errorf("record %d: zset item without score", line)
+				}
+				pipe.ZAdd(ctx, rec.Key, redis.Z{Score: *rec.Score, Member: *rec.Value})
+			}
+			items++
+			if pending++; pending >= snapshotBatch {
+				if err := flush(); err != nil {
+					return fmt.Errorf("record %d: %w", line, err)
+				}
+			}
+		case "end":
+			for key, ms := range ttls {
+				pipe.PExpire(ctx, key, time.Duration(ms)*time.Millisecond)
+				pending++
+			}
+			if err := flush(); err != nil {
+				return err
+			}
+			if rec.Count != items {
+				return fmt.Errorf("snapshot declares %d items but contained %d", rec.Count, items)
+			}
+			return nil
+		}
+	}
+}
+
+// snapshotKeys lists the configured queues with their scheduled: and
+// delayed: sets, plus every list or sorted set under the jobqueue: prefix
+// in cfg's namespace, sorted for a stable export order.
+func snapshotKeys(ctx context.Context, cfg *config.Config, rdb *redis.Client) ([]string, error) {
+	seen := map[string]struct{}{}
+	add := func(k string) {
+		if k != "" {
+			seen[k] = struct{}{}
+		}
+	}
+	for _, q := range cfg.Worker.Queues {
+		add(q)
+		add(cfg.DerivedKey(scheduler.ScheduledKey, q))
+		add(cfg.DerivedKey(scheduler.DelayedKey, q))
+	}
+	add(cfg.Worker.CompletedList)
+	add(cfg.Worker.DeadLetterList)
+
+	patterns := []string{cfg.Key("jobqueue:*")}
+	if p := cfg.Worker.ProcessingListPattern; p != "" && !strings.HasPrefix(p, cfg.Key("jobqueue:")) {
+		patterns = append(patterns, strings.ReplaceAll(p, "%s", "*"))
+	}
+	for _, pat := range patterns {
+		var cursor uint64
+		for {
+			keys, cur, err := rdb.Scan(ctx, cursor, pat, 500).Result()
+			if err != nil {
+				return nil, err
+			}
+			for _, k := range keys {
+				if k == cfg.Producer.RateLimitKey {
+					continue
+				}
+				add(k)
+			}
+			cursor = cur
+			if cursor == 0 {
+				break
+			}
+		}
+	}
+
+	out := make([]string, 0, len(seen))
+	for k := range seen {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out, nil
+}
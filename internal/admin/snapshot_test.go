@@ -0,0 +1,94 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	cfg, rdb := newPeekFixture(t)
+	cfg.Worker.CompletedList = "jobqueue:completed"
+	cfg.Worker.DeadLetterList = "jobqueue:dead_letter"
+	cfg.Worker.ProcessingListPattern = "jobqueue:worker:%s:processing"
+	cfg.Producer.RateLimitKey = "jobqueue:rate_limit:producer"
+
+	rdb.RPush(ctx, "jobqueue:low_priority", "a", "b", "c")
+	rdb.RPush(ctx, "jobqueue:dead_letter", `{"id":"dead"}`)
+	rdb.RPush(ctx, "jobqueue:worker:w1:processing", "inflight")
+	rdb.ZAdd(ctx, "jobqueue:scheduled", redis.Z{Score: 10, Member: "later"}, redis.Z{Score: 5, Member: "sooner"})
+	rdb.PExpire(ctx, "jobqueue:scheduled", time.Hour)
+	rdb.Set(ctx, "jobqueue:processing:worker:w1", "hb", 0)
+	rdb.Set(ctx, "jobqueue:rate_limit:producer", "3", 0)
+
+	var buf bytes.Buffer
+	if err := ExportSnapshot(ctx, cfg, rdb, &buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if !strings.Contains(lines[0], `"version":1`) || !strings.Contains(lines[len(lines)-1], `"type":"end"`) {
+		t.Fatalf("missing header or end record:\n%s", buf.String())
+	}
+	if strings.Contains(buf.String(), "rate_limit") || strings.Contains(buf.String(), `"hb"`) {
+		t.Fatalf("non-queue keys should not be exported:\n%s", buf.String())
+	}
+	snapshot := buf.String()
+
+	// Replace restores exact contents, dropping items added since the export.
+	rdb.RPush(ctx, "jobqueue:low_priority", "d")
+	rdb.ZRem(ctx, "jobqueue:scheduled", "sooner")
+	if err := ImportSnapshot(ctx, cfg, rdb, strings.NewReader(snapshot), ImportReplace); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := rdb.LRange(ctx, "jobqueue:low_priority", 0, -1).Result(); strings.Join(got, ",") != "a,b,c" {
+		t.Fatalf("replace: low = %v", got)
+	}
+	if got, _ := rdb.ZRangeWithScores(ctx, "jobqueue:scheduled", 0, -1).Result(); len(got) != 2 || got[0].Member != "sooner" || got[0].Score != 5 {
+		t.Fatalf("replace: scheduled = %v", got)
+	}
+	if ttl, _ := rdb.PTTL(ctx, "jobqueue:scheduled").Result(); ttl <= 0 {
+		t.Fatalf("ttl not restored: %v", ttl)
+	}
+	if got, _ := rdb.LRange(ctx, "jobqueue:worker:w1:processing", 0, -1).Result(); len(got) != 1 || got[0] != "inflight" {
+		t.Fatalf("processing list not restored: %v", got)
+	}
+
+	// Merge appends after existing list items.
+	if err := ImportSnapshot(ctx, cfg, rdb, strings.NewReader(snapshot), ImportMerge); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := rdb.LRange(ctx, "jobqueue:low_priority", 0, -1).Result(); strings.Join(got, ",") != "a,b,c,a,b,c" {
+		t.Fatalf("merge: low = %v", got)
+	}
+	if n, _ := rdb.ZCard(ctx, "jobqueue:scheduled").Result(); n != 2 {
+		t.Fatalf("merge: zset members should be upserted, got %d", n)
+	}
+}
+
+func TestImportSnapshotRejectsBadStreams(t *testing.T) {
+	ctx := context.Background()
+	cfg, rdb := newPeekFixture(t)
+	header := `{"type":"header","format":"go-redis-work-queue/snapshot","version":1}` + "\n"
+
+	cases := map[string]string{
+		"no header":   `{"type":"key","key":"jobqueue:x","kind":"list","count":1}` + "\n",
+		"future":      `{"type":"header","format":"go-redis-work-queue/snapshot","version":99}` + "\n",
+		"truncated":   header + `{"type":"key","key":"jobqueue:x","kind":"list","count":1}` + "\n",
+		"undeclared":  header + `{"type":"item","key":"jobqueue:x","value":"v"}` + "\n",
+		"count check": header + `{"type":"key","key":"jobqueue:x","kind":"list","count":2}` + "\n" + `{"type":"item","key":"jobqueue:x","value":"v"}` + "\n" + `{"type":"end","keys":1,"count":2}` + "\n",
+	}
+	for name, in := range cases {
+		if err := ImportSnapshot(ctx, cfg, rdb, strings.NewReader(in), ImportMerge); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	if err := ImportSnapshot(ctx, cfg, rdb, strings.NewReader(header), "overwrite"); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+}
@@ -0,0 +1,285 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultThroughputWindow = 10 * time.Second
+	// throughputAttributeMax caps how many new completed or dead-lettered
+	// items are read per sample to attribute them to a queue. Items past
+	// the cap still count toward the totals.
+	throughputAttributeMax = 1000
+)
+
+// QueueThroughput is the measured flow through one priority queue.
+type QueueThroughput struct {
+	Priority       string  `json:"priority"`
+	Queue          string  `json:"queue"`
+	Length         int64   `json:"length"`
+	EnqueuePerSec  float64 `json:"enqueue_per_sec"`
+	CompletePerSec float64 `json:"complete_per_sec"`
+	// Draining is true when jobs leave faster than they arrive (or the
+	// queue is empty); TimeToDrain is then the time to empty it at the
+	// current net rate.
+	Draining    bool          `json:"draining"`
+	TimeToDrain time.Duration `json:"time_to_drain"`
+}
+
+// ThroughputReport summarizes enqueue and completion rates over a window.
+type ThroughputReport struct {
+	Window           time.Duration     `json:"window"`
+	Samples          int               `json:"samples"`
+	CompletedPerSec  float64           `json:"completed_per_sec"`
+	DeadLetterPerSec float64           `json:"dead_letter_per_sec"`
+	Queues           []QueueThroughput `json:"queues"`
+	// CursorResets counts samples where the completed or dead letter list
+	// was trimmed or replaced past the last item seen, so growth there is
+	// a lower bound.
+	CursorResets int `json:"cursor_resets"`
+}
+
+// Throughput samples queue lengths and the completed and dead letter lists
+// for window and returns exponentially weighted rates; see sampleFlow for
+// how arrivals and finished jobs are counted.
+func Throughput(ctx context.Context, cfg *config.Config, rdb *redis.Client, window time.Duration) (*ThroughputReport, error) {
+	if window <= 0 {
+		window = defaultThroughputWindow
+	}
+	// Samples older than about a quarter of the window have little weight.
+	tau := (window / 4).Seconds()
+
+	var queues []QueueThroughput
+	for _, p := range cfg.Worker.Priorities {
+		if key := cfg.Worker.Queues[p]; key != "" {
+			queues = append(queues, QueueThroughput{Priority: p, Queue: key})
+		}
+	}
+
+	rep := &ThroughputReport{Window: window}
+	resets, err := sampleFlow(ctx, cfg, rdb, queues, window, func(s flowSample) {
+		alpha := 1 - math.Exp(-s.dt/tau)
+		first := rep.Samples == 0
+		rep.CompletedPerSec = ewma(rep.CompletedPerSec, s.completed/s.dt, alpha, first)
+		rep.DeadLetterPerSec = ewma(rep.DeadLetterPerSec, s.dead/s.dt, alpha, first)
+		for i := range queues {
+			q := &queues[i]
+			q.EnqueuePerSec = ewma(q.EnqueuePerSec, s.in[i]/s.dt, alpha, first)
+			q.CompletePerSec = ewma(q.CompletePerSec, s.out[i]/s.dt, alpha, first)
+			q.Length = s.lengths[i]
+		}
+		rep.Samples++
+	})
+	if err != nil {
+		return nil, err
+	}
+	rep.CursorResets = resets
+
+	for _, q := range queues {
+		net := q.CompletePerSec - q.EnqueuePerSec
+		switch {
+		case q.Length == 0:
+			q.Draining = true
+		case net > 0:
+			q.Draining = true
+			q.TimeToDrain = time.Duration(float64(q.Length) / net * float64(time.Second)).Round(time.Second)
+		}
+		rep.Queues = append(rep.Queues, q)
+	}
+	return rep, nil
+}
+
+// ewma folds sample into an exponentially weighted average; the first
+// sample seeds it.
+func ewma(prev, sample, alpha float64, first bool) float64 {
+	if first {
+		return sample
+	}
+	return prev + alpha*(sample-prev)
+}
+
+// flowSample is the flow through the sampled queues since the previous
+// sample.
+type flowSample struct {
+	dt      float64 // seconds since the previous sample
+	lengths []int64
+	// in and out are the jobs that arrived in and finished from each queue.
+	in, out []float64
+	// completed and dead are everything that reached the completed and
+	// dead letter lists, whichever queue it came from.
+	completed, dead float64
+}
+
+// sampleFlow samples the lengths of queues and the completed and dead
+// letter lists for window, calling fn with each sample in turn, and returns
+// how many samples lost a finished list's cursor. Finished jobs are found
+// by remembering the newest item of each finished list and locating it
+// again on the next sample, so trimming or rotating those lists does not
+// read as negative throughput. Arrivals are inferred from each queue's
+// length change plus the jobs from it that finished; jobs still in flight
+// at the end of the window are not counted.
+func sampleFlow(ctx context.Context, cfg *config.Config, rdb *redis.Client, queues []QueueThroughput, window time.Duration, fn func(flowSample)) (int, error) {
+	interval := window / 5
+	if interval > time.Second {
+		interval = time.Second
+	}
+	if interval < 100*time.Millisecond {
+		interval = 100 * time.Millisecond
+	}
+
+	completed := &listCursor{key: cfg.Worker.CompletedList}
+	dead := &listCursor{key: cfg.Worker.DeadLetterList}
+	lengths := func() ([]int64, error) {
+		out := make([]int64, len(queues))
+		for i, q := range queues {
+			n, err := rdb.LLen(ctx, q.Queue).Result()
+			if err != nil {
+				return nil, err
+			}
+			out[i] = n
+		}
+		return out, nil
+	}
+
+	prevLen, err := lengths()
+	if err != nil {
+		return 0, err
+	}
+	for _, c := range []*listCursor{completed, dead} {
+		if _, err := c.advance(ctx, rdb); err != nil {
+			return 0, err
+		}
+	}
+	last := time.Now()
+	deadline := last.Add(window)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	resets, samples := 0, 0
+	for time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return resets, ctx.Err()
+		case <-ticker.C:
+		}
+		now := time.Now()
+		s := flowSample{dt: now.Sub(last).Seconds()}
+		last = now
+
+		if s.lengths, err = lengths(); err != nil {
+			return resets, err
+		}
+		done := map[string]float64{}
+		totals := [2]float64{}
+		for i, c := range []*listCursor{completed, dead} {
+			step, err := c.advance(ctx, rdb)
+			if err != nil {
+				return resets, err
+			}
+			if step.reset {
+				resets++
+			}
+			totals[i] = float64(step.count)
+			for _, item := range step.items {
+				done[throughputPriority(item)]++
+			}
+		}
+		s.completed, s.dead = totals[0], totals[1]
+		s.in = make([]float64, len(queues))
+		s.out = make([]float64, len(queues))
+		for i, q := range queues {
+			s.out[i] = done[q.Priority]
+			s.in[i] = math.Max(0, float64(s.lengths[i]-prevLen[i])+s.out[i])
+		}
+		prevLen = s.lengths
+		fn(s)
+		samples++
+	}
+	if samples == 0 {
+		return resets, errors.New("throughput window too short to take a sample")
+	}
+	return resets, nil
+}
+
+// listCursor tracks the newest item of a list that grows at the head.
+type listCursor struct {
+	key     string
+	head    string
+	started bool
+}
+
+// cursorStep is what arrived at the head of a list since the last sample.
+type cursorStep struct {
+	count int64
+	// items holds up to throughputAttributeMax of the new items, newest first.
+	items []string
+	// reset is true when the previous head was gone, so count is only
+	// the current length.
+	reset bool
+}
+
+// advance finds the previous head in the list; everything in front of it is
+// new. The head, its position and the length are read in one transaction so
+// items pushed between reads are not counted twice. The first call only
+// records the head.
+func (c *listCursor) advance(ctx context.Context, rdb *redis.Client) (cursorStep, error) {
+	var step cursorStep
+	if c.key == "" {
+		return step, nil
+	}
+	pipe := rdb.TxPipeline()
+	headCmd := pipe.LIndex(ctx, c.key, 0)
+	lenCmd := pipe.LLen(ctx, c.key)
+	var posCmd *redis.IntCmd
+	if c.started && c.head != "" {
+		posCmd = pipe.LPos(ctx, c.key, c.head, redis.LPosArgs{})
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return step, err
+	}
+	head := headCmd.Val()
+	if !c.started {
+		c.started, c.head = true, head
+		return step, nil
+	}
+
+	switch {
+	case head == c.head:
+		return step, nil
+	case posCmd == nil:
+		// The list was empty: all of it is new.
+		step.count = lenCmd.Val()
+	case posCmd.Err() == redis.Nil:
+		step.reset = true
+		step.count = lenCmd.Val()
+	case posCmd.Err() != nil:
+		return step, posCmd.Err()
+	default:
+		step.count = posCmd.Val()
+	}
+	c.head = head
+	if n := min(step.count, throughputAttributeMax); n > 0 {
+		items, err := rdb.LRange(ctx, c.key, 0, n-1).Result()
+		if err != nil {
+			return step, err
+		}
+		step.items = items
+	}
+	return step, nil
+}
+
+// throughputPriority reads the priority of a finished job payload.
+func throughputPriority(item string) string {
+	var job struct {
+		Priority string `json:"priority"`
+	}
+	_ = json.Unmarshal([]byte(decodeItem(item)), &job)
+	return job.Priority
+}
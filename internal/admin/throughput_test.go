@@ -0,0 +1,101 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestListCursorSurvivesTrimAndReplace(t *testing.T) {
+	ctx := context.Background()
+	_, rdb := newPeekFixture(t)
+	key := "jobqueue:completed"
+	push := func(from, to int) {
+		for i := from; i < to; i++ {
+			rdb.LPush(ctx, key, fmt.Sprintf(`{"id":"%d","priority":"low"}`, i))
+		}
+	}
+
+	c := &listCursor{key: key}
+	push(0, 10)
+	if _, err := c.advance(ctx, rdb); err != nil {
+		t.Fatal(err)
+	}
+
+	// Trimming the tail keeps the head findable.
+	push(10, 13)
+	rdb.LTrim(ctx, key, 0, 4)
+	step, err := c.advance(ctx, rdb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if step.count != 3 || step.reset || len(step.items) != 3 {
+		t.Fatalf("expected 3 new items without reset, got %+v", step)
+	}
+
+	if step, _ = c.advance(ctx, rdb); step.count != 0 {
+		t.Fatalf("expected no new items, got %d", step.count)
+	}
+
+	// A rotated list loses the cursor; its length is the lower bound.
+	rdb.Del(ctx, key)
+	push(100, 102)
+	if step, _ = c.advance(ctx, rdb); step.count != 2 || !step.reset {
+		t.Fatalf("expected reset with 2 items, got %+v", step)
+	}
+}
+
+func TestThroughputRates(t *testing.T) {
+	ctx := context.Background()
+	cfg, rdb := newPeekFixture(t)
+	cfg.Worker.Priorities = []string{"low"}
+	cfg.Worker.CompletedList = "jobqueue:completed"
+	cfg.Worker.DeadLetterList = "jobqueue:dead_letter"
+	for i := 0; i < 50; i++ {
+		rdb.LPush(ctx, "jobqueue:low_priority", fmt.Sprintf(`{"id":"q%d","priority":"low"}`, i))
+	}
+
+	// Complete 5 jobs every 50ms while enqueuing none: the queue drains.
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		n := 0
+		for {
+			select {
+			case <-stop:
+				return
+			case <-time.After(50 * time.Millisecond):
+			}
+			for i := 0; i < 5; i++ {
+				if rdb.RPop(ctx, "jobqueue:low_priority").Err() != nil {
+					return
+				}
+				rdb.LPush(ctx, cfg.Worker.CompletedList, fmt.Sprintf(`{"id":"c%d","priority":"low"}`, n))
+				n++
+			}
+		}
+	}()
+
+	rep, err := Throughput(ctx, cfg, rdb, 600*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Samples < 3 {
+		t.Fatalf("expected several samples, got %d", rep.Samples)
+	}
+	if rep.CompletedPerSec <= 0 {
+		t.Fatalf("expected positive completion rate, got %f", rep.CompletedPerSec)
+	}
+	if len(rep.Queues) != 1 {
+		t.Fatalf("expected one queue, got %+v", rep.Queues)
+	}
+	q := rep.Queues[0]
+	if q.CompletePerSec <= 0 || q.EnqueuePerSec > q.CompletePerSec/2 {
+		t.Fatalf("expected completions to dominate, got %+v", q)
+	}
+	if !q.Draining {
+		t.Fatalf("expected queue to be draining, got %+v", q)
+	}
+}
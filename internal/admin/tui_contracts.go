@@ -4,9 +4,6 @@ import (
     "context"
     "encoding/json"
     "errors"
-    "fmt"
-    "sort"
-    "strings"
     "time"
 
     "github.com/flyingrobots/go-redis-work-queue/internal/config"
@@ -35,8 +32,9 @@ type DLQService interface {
     DLQPurge(ctx context.Context, cfg *config.Config, rdb *redis.Client, namespace string, ids []string) (int, error)
 }
 
-// DLQList returns a page of DLQ items along with an opaque cursor for the next page.
-// The cursor semantics are implementation‑defined and should be treated as opaque by callers.
+// DLQList returns a page of DLQ items, newest first, along with an opaque cursor
+// for the next page ("" on the last page). Cursors survive new dead letters and
+// removals of entries already paged past; see readPage.
 func DLQList(ctx context.Context, cfg *config.Config, rdb *redis.Client, namespace string, cursor string, limit int) ([]DLQItem, string, error) {
     if cfg.Worker.DeadLetterList == "" {
         return nil, "", errors.New("dead letter list not configured")
@@ -44,22 +42,11 @@ func DLQList(ctx context.Context, cfg *config.Config, rdb *redis.Client, namespa
     if limit <= 0 || limit > 500 {
         limit = 100
     }
-    // Cursor is a simple decimal offset into the list
-    var offset int64
-    if cursor != "" {
-        var parsed int64
-        _, err := fmt.Sscan(cursor, &parsed)
-        if err == nil && parsed >= 0 {
-            offset = parsed
-        }
-    }
-    // Compute stop index and fetch
-    start := offset
-    stop := offset + int64(limit) - 1
-    items, err := rdb.LRange(ctx, cfg.Worker.DeadLetterList, start, stop).Result()
+    page, err := readPage(ctx, rdb, cfg.Worker.DeadLetterList, cursor, int64(limit), false)
     if err != nil {
         return nil, "", err
     }
+    items := page.Items
     out := make([]DLQItem, 0, len(items))
     for _, raw := range items {
         var meta struct {
@@ -68,11 +55,12 @@ func DLQList(ctx context.Context, cfg *config.Config, rdb *redis.Client, namespa
             Attempts     int    `json:"retries"`
             CreationTime string `json:"creation_time"`
         }
-        _ = json.Unmarshal([]byte(raw), &meta)
+        payload := decodeItem(raw)
+        _ = json.Unmarshal([]byte(payload), &meta)
         it := DLQItem{
             ID:       meta.ID,
             Queue:    "", // unknown from payload; left blank
-            Payload:  []byte(raw),
+            Payload:  []byte(payload),
             Reason:   meta.Reason,
             Attempts: meta.Attempts,
         }
@@ -82,12 +70,7 @@ func DLQList(ctx context.Context, cfg *config.Config, rdb *redis.Client, namespa
         }
         out = append(out, it)
     }
-    // Determine next cursor
-    if len(items) < limit {
-        return out, "", nil
-    }
-    next := fmt.Sprintf("%d", offset+int64(len(items)))
-    return out, next, nil
+    return out, page.Next, nil
 }
 
 // DLQRequeue moves the specified DLQ item IDs back to a destination queue.
@@ -129,7 +112,7 @@ func DLQRequeue(ctx context.Context, cfg *config.Config, rdb *redis.Client, name
         }
         for _, raw := range batch {
             var meta struct{ ID string `json:"id"` }
-            if err := json.Unmarshal([]byte(raw), &meta); err != nil {
+            if err := json.Unmarshal([]byte(decodeItem(raw)), &meta); err != nil {
                 continue
             }
             if _, ok := idset[meta.ID]; !ok {
@@ -179,7 +162,7 @@ func DLQPurge(ctx context.Context, cfg *config.Config, rdb *redis.Client, namesp
         }
         for _, raw := range batch {
             var meta struct{ ID string `json:"id"` }
-            if err := json.Unmarshal([]byte(raw), &meta); err != nil {
+            if err := json.Unmarshal([]byte(decodeItem(raw)), &meta); err != nil {
                 continue
             }
             if _, ok := idset[meta.ID]; !ok {
@@ -198,94 +181,9 @@ func DLQPurge(ctx context.Context, cfg *config.Config, rdb *redis.Client, namesp
     return purged, nil
 }
 
-// WorkerInfo summarizes a worker’s status for the TUI Workers tab.
-type WorkerInfo struct {
-    ID            string     `json:"id"`
-    LastHeartbeat time.Time  `json:"last_heartbeat"`
-    Queue         string     `json:"queue,omitempty"`
-    JobID         string     `json:"job_id,omitempty"`
-    StartedAt     *time.Time `json:"started_at,omitempty"`
-    Version       string     `json:"version,omitempty"`
-    Host          string     `json:"host,omitempty"`
-}
-
 // WorkerService defines the contract for querying worker status.
 type WorkerService interface {
-    Workers(ctx context.Context, cfg *config.Config, rdb *redis.Client, namespace string) ([]WorkerInfo, error)
-}
-
-// Workers lists currently known workers in the given namespace.
-func Workers(ctx context.Context, cfg *config.Config, rdb *redis.Client, namespace string) ([]WorkerInfo, error) {
-    // Discover workers from heartbeat and processing keys
-    hbPattern := "jobqueue:processing:worker:*"
-    plPattern := "jobqueue:worker:*:processing"
-
-    workerMap := map[string]*WorkerInfo{}
-
-    // Heartbeats: presence implies online; we don’t have timestamps stored yet, so set to now
-    var cursor uint64
-    for {
-        keys, cur, err := rdb.Scan(ctx, cursor, hbPattern, 500).Result()
-        if err != nil {
-            return nil, err
-        }
-        cursor = cur
-        for _, k := range keys {
-            id := k[strings.LastIndex(k, ":")+1:]
-            wi := workerMap[id]
-            if wi == nil {
-                wi = &WorkerInfo{ID: id}
-                workerMap[id] = wi
-            }
-            wi.LastHeartbeat = time.Now()
-        }
-        if cursor == 0 {
-            break
-        }
-    }
-
-    // Processing lists: derive worker IDs and attempt to read active job
-    cursor = 0
-    for {
-        keys, cur, err := rdb.Scan(ctx, cursor, plPattern, 500).Result()
-        if err != nil {
-            return nil, err
-        }
-        cursor = cur
-        for _, k := range keys {
-            // Format: jobqueue:worker:<id>:processing
-            parts := strings.Split(k, ":")
-            id := ""
-            if len(parts) >= 3 {
-                id = parts[2]
-            }
-            if id == "" {
-                continue
-            }
-            wi := workerMap[id]
-            if wi == nil {
-                wi = &WorkerInfo{ID: id}
-                workerMap[id] = wi
-            }
-            // Peek last item as the most recent
-            raw, err := rdb.LIndex(ctx, k, -1).Result()
-            if err == nil && raw != "" {
-                var meta struct{ ID string `json:"id"` }
-                _ = json.Unmarshal([]byte(raw), &meta)
-                wi.JobID = meta.ID
-            }
-        }
-        if cursor == 0 {
-            break
-        }
-    }
-
-    out := make([]WorkerInfo, 0, len(workerMap))
-    for _, wi := range workerMap {
-        out = append(out, *wi)
-    }
-    sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
-    return out, nil
+    Workers(ctx context.Context, cfg *config.Config, rdb *redis.Client) ([]WorkerInfo, error)
 }
 
 // JobEvent is a timeline event for a job used by the Time Travel debugger.
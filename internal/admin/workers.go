@@ -0,0 +1,167 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/redis/go-redis/v9"
+)
+
+// WorkerInfo summarizes one worker goroutine from its heartbeat key and
+// processing list. Workers only hold a heartbeat while they run a job, so an
+// idle worker with an empty processing list does not appear at all.
+type WorkerInfo struct {
+	ID string `json:"id"`
+	// LastHeartbeat is when the worker last wrote its heartbeat, derived
+	// from the key's remaining TTL; zero when there is none.
+	LastHeartbeat time.Time  `json:"last_heartbeat"`
+	Queue         string     `json:"queue,omitempty"`  // queue of the current job
+	JobID         string     `json:"job_id,omitempty"` // current job, from the heartbeat
+	StartedAt     *time.Time `json:"started_at,omitempty"`
+	Version       string     `json:"version,omitempty"`
+	Host          string     `json:"host,omitempty"`
+	PID           int        `json:"pid,omitempty"`
+
+	Alive          bool   `json:"alive"` // heartbeat key present
+	ProcessingList string `json:"processing_list,omitempty"`
+	Processing     int64  `json:"processing"` // items in the processing list
+	// ReapCandidate marks a worker with no heartbeat whose processing list
+	// still holds jobs: it most likely died mid-job and the reaper will
+	// requeue the list.
+	ReapCandidate bool `json:"reap_candidate"`
+}
+
+// Workers lists the workers that have a heartbeat key or a processing list,
+// sorted by ID.
+func Workers(ctx context.Context, cfg *config.Config, rdb *redis.Client) ([]WorkerInfo, error) {
+	hbPrefix, hbSuffix := splitKeyPattern(cfg.Worker.HeartbeatKeyPattern, cfg.Key(defaultHeartbeatPattern))
+	plPrefix, plSuffix := splitKeyPattern(cfg.Worker.ProcessingListPattern, cfg.Key(defaultProcessingPattern))
+
+	ids := map[string]bool{}
+	for _, kp := range [][2]string{{hbPrefix, hbSuffix}, {plPrefix, plSuffix}} {
+		prefix, suffix := kp[0], kp[1]
+		var cursor uint64
+		for {
+			keys, cur, err := rdb.Scan(ctx, cursor, prefix+"*"+suffix, 500).Result()
+			if err != nil {
+				return nil, err
+			}
+			for _, k := range keys {
+				if strings.HasPrefix(k, prefix) && strings.HasSuffix(k, suffix) && len(k) > len(prefix)+len(suffix) {
+					ids[k[len(prefix):len(k)-len(suffix)]] = true
+				}
+			}
+			cursor = cur
+			if cursor == 0 {
+				break
+			}
+		}
+	}
+
+	type workerCmds struct {
+		ttl  *redis.DurationCmd
+		hb   *redis.StringCmd
+		proc *redis.IntCmd
+	}
+	out := make([]WorkerInfo, 0, len(ids))
+	cmds := make([]workerCmds, 0, len(ids))
+	pipe := rdb.Pipeline()
+	for id := range ids {
+		hbKey := hbPrefix + id + hbSuffix
+		out = append(out, WorkerInfo{ID: id, ProcessingList: plPrefix + id + plSuffix})
+		cmds = append(cmds, workerCmds{
+			ttl:  pipe.PTTL(ctx, hbKey),
+			hb:   pipe.Get(ctx, hbKey),
+			proc: pipe.LLen(ctx, plPrefix+id+plSuffix),
+		})
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range out {
+		wi, c := &out[i], cmds[i]
+		wi.Host, wi.PID, wi.StartedAt = parseWorkerID(wi.ID)
+		wi.Processing = c.proc.Val()
+		if payload, err := c.hb.Result(); err == nil {
+			wi.Alive = true
+			if ttl := c.ttl.Val(); ttl > 0 && ttl <= cfg.Worker.HeartbeatTTL {
+				wi.LastHeartbeat = now.Add(ttl - cfg.Worker.HeartbeatTTL).UTC()
+			}
+			if job, err := queue.UnmarshalJob(payload); err == nil {
+				wi.JobID = job.ID
+				wi.Queue = cfg.Worker.Queues[job.Priority]
+			}
+		}
+		wi.ReapCandidate = !wi.Alive && wi.Processing > 0
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+// Key patterns used when the config has none.
+const (
+	defaultProcessingPattern = "jobqueue:worker:%s:processing"
+	defaultHeartbeatPattern  = "jobqueue:processing:worker:%s"
+)
+
+// processingListGlob matches every worker's processing list.
+func processingListGlob(cfg *config.Config) string {
+	prefix, suffix := splitKeyPattern(cfg.Worker.ProcessingListPattern, cfg.Key(defaultProcessingPattern))
+	return prefix + "*" + suffix
+}
+
+// heartbeatGlob matches every worker's heartbeat key.
+func heartbeatGlob(cfg *config.Config) string {
+	prefix, suffix := splitKeyPattern(cfg.Worker.HeartbeatKeyPattern, cfg.Key(defaultHeartbeatPattern))
+	return prefix + "*" + suffix
+}
+
+// splitKeyPattern returns the parts of a key pattern such as
+// "jobqueue:worker:%s:processing" around the worker ID.
+func splitKeyPattern(pattern, fallback string) (prefix, suffix string) {
+	if !strings.Contains(pattern, "%s") {
+		pattern = fallback
+	}
+	prefix, suffix, _ = strings.Cut(pattern, "%s")
+	return prefix, suffix
+}
+
+// parseWorkerID splits the "<host>-<pid>-<start nanos>-<rand>-<n>" IDs the
+// worker assigns. Anything else yields zero values.
+func parseWorkerID(id string) (host string, pid int, started *time.Time) {
+	parts := strings.Split(id, "-")
+	if len(parts) < 5 {
+		return "", 0, nil
+	}
+	n := len(parts)
+	p, err1 := strconv.Atoi(parts[n-4])
+	nanos, err2 := strconv.ParseInt(parts[n-3], 10, 64)
+	if _, err3 := strconv.Atoi(parts[n-1]); err1 != nil || err2 != nil || err3 != nil {
+		return "", 0, nil
+	}
+	at := time.Unix(0, nanos).UTC()
+	return strings.Join(parts[:n-4], "-"), p, &at
+}
+
+// WorkerSummary counts a Workers result for one-line reports.
+func WorkerSummary(workers []WorkerInfo) string {
+	alive, reap := 0, 0
+	for _, w := range workers {
+		if w.Alive {
+			alive++
+		}
+		if w.ReapCandidate {
+			reap++
+		}
+	}
+	return fmt.Sprintf("workers: %d  alive: %d  reap candidates: %d", len(workers), alive, reap)
+}
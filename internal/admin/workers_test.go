@@ -0,0 +1,61 @@
+// Copyright 2025 James Ross
+package admin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+)
+
+func TestWorkersReportsLivenessAndReapCandidates(t *testing.T) {
+	ctx := context.Background()
+	cfg, rdb := newPeekFixture(t)
+	cfg.Worker.ProcessingListPattern = "jobqueue:worker:%s:processing"
+	cfg.Worker.HeartbeatKeyPattern = "jobqueue:processing:worker:%s"
+	cfg.Worker.HeartbeatTTL = 30 * time.Second
+
+	live := "web-1-4242-1756728000000000000-ab12cd34-0"
+	dead := "web-2-99-1756728000000000000-ef56ab78-1"
+	job, _ := queue.NewJob("job-7", "/tmp/f", 1, "low", "", "").Marshal()
+	if err := rdb.Set(ctx, "jobqueue:processing:worker:"+live, job, 20*time.Second).Err(); err != nil {
+		t.Fatal(err)
+	}
+	rdb.LPush(ctx, "jobqueue:worker:"+live+":processing", job)
+	rdb.LPush(ctx, "jobqueue:worker:"+dead+":processing", "a", "b")
+
+	workers, err := Workers(ctx, cfg, rdb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(workers) != 2 {
+		t.Fatalf("expected 2 workers, got %+v", workers)
+	}
+	w := workers[0]
+	if w.ID != live || !w.Alive || w.ReapCandidate || w.Host != "web-1" || w.PID != 4242 ||
+		w.JobID != "job-7" || w.Queue != cfg.Worker.Queues["low"] || w.Processing != 1 {
+		t.Fatalf("unexpected live worker: %+v", w)
+	}
+	if ago := time.Since(w.LastHeartbeat); ago < 9*time.Second || ago > 12*time.Second {
+		t.Fatalf("expected last heartbeat ~10s ago, got %s", ago)
+	}
+	if w.StartedAt == nil || w.StartedAt.Unix() != 1756728000 {
+		t.Fatalf("unexpected start time: %v", w.StartedAt)
+	}
+	d := workers[1]
+	if d.ID != dead || d.Alive || !d.ReapCandidate || d.Processing != 2 || !d.LastHeartbeat.IsZero() {
+		t.Fatalf("unexpected dead worker: %+v", d)
+	}
+	if got := WorkerSummary(workers); got != "workers: 2  alive: 1  reap candidates: 1" {
+		t.Fatalf("unexpected summary: %q", got)
+	}
+}
+
+func TestParseWorkerIDRejectsForeignIDs(t *testing.T) {
+	for _, id := range []string{"w1", "host-x-1-ab-0", "a-b-c-d"} {
+		if host, pid, started := parseWorkerID(id); host != "" || pid != 0 || started != nil {
+			t.Fatalf("%s: expected zero values, got %q %d %v", id, host, pid, started)
+		}
+	}
+}
@@ -37,12 +37,40 @@ func New(window time.Duration, cooldown time.Duration, failureThresh float64, mi
 	return &CircuitBreaker{state: Closed, window: window, cooldown: cooldown, failureThresh: failureThresh, minSamples: minSamples, lastTransition: time.Now()}
 }
 
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case HalfOpen:
+		return "half_open"
+	case Open:
+		return "open"
+	default:
+		return "unknown"
+	}
+}
+
 func (cb *CircuitBreaker) State() State {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
 	return cb.state
 }
 
+// Ready reports whether Allow would currently admit a request, without
+// claiming the half-open probe.
+func (cb *CircuitBreaker) Ready() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	switch cb.state {
+	case Open:
+		return time.Since(cb.lastTransition) >= cb.cooldown
+	case HalfOpen:
+		return !cb.halfOpenInFlight
+	default:
+		return true
+	}
+}
+
 func (cb *CircuitBreaker) Allow() bool {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
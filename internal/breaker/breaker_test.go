@@ -33,3 +33,24 @@ func TestBreakerTransitions(t *testing.T) {
 		t.Fatal("expected closed after probe success")
 	}
 }
+
+func TestBreakerReadyDoesNotClaimProbe(t *testing.T) {
+	cb := New(2*time.Second, 50*time.Millisecond, 0.5, 1)
+	cb.Record(false)
+	if cb.Ready() {
+		t.Fatal("open breaker should not be ready during cooldown")
+	}
+	time.Sleep(60 * time.Millisecond)
+	if !cb.Ready() || !cb.Ready() {
+		t.Fatal("breaker should stay ready after cooldown until a probe is claimed")
+	}
+	if !cb.Allow() {
+		t.Fatal("expected half-open probe")
+	}
+	if cb.Ready() {
+		t.Fatal("breaker should not be ready while the probe is in flight")
+	}
+	if got := cb.State().String(); got != "half_open" {
+		t.Fatalf("state = %q", got)
+	}
+}
@@ -54,7 +54,11 @@ func NewManager(config *Config, redis *redis.Client, logger *slog.Logger) *Manag
 
 	// Initialize components
 	manager.router = NewRedisRouter(redis, logger)
-	manager.collector = NewRedisMetricsCollector(redis, logger)
+	if config.PrometheusURL != "" {
+		manager.collector = NewPrometheusMetricsCollector(config.PrometheusURL, logger)
+	} else {
+		manager.collector = NewRedisMetricsCollector(redis, logger)
+	}
 	manager.alerter = NewWebhookAlerter(config.WebhookURLs, logger)
 	manager.workers = NewWorkerRegistry(redis, logger)
 
@@ -158,7 +162,8 @@ func (m *Manager) CreateDeployment(ctx context.Context, config *CanaryConfig) (*
 	m.logger.Info("Created canary deployment",
 		"deployment_id", deploymentID,
 		"queue", deployment.QueueName,
-		"strategy", config.RoutingStrategy)
+		"strategy", config.RoutingStrategy,
+		"shadow", config.ShadowMode)
 
 	return deployment, nil
 }
@@ -213,6 +218,12 @@ func (m *Manager) UpdateDeploymentPercentage(ctx context.Context, id string, per
 		return NewCanaryError(CodeDeploymentNotActive, "deployment is not active")
 	}
 
+	// Shadow deployments never take real traffic
+	if deployment.Config.ShadowMode && percentage > 0 {
+		m.mu.Unlock()
+		return NewCanaryError(CodeInvalidConfiguration, "shadow deployments do not route live traffic")
+	}
+
 	// Check if percentage exceeds configured maximum
 	if percentage > m.config.MaxCanaryPercentage {
 		m.mu.Unlock()
@@ -220,11 +231,16 @@ func (m *Manager) UpdateDeploymentPercentage(ctx context.Context, id string, per
 			fmt.Sprintf("percentage exceeds maximum allowed (%d%%)", m.config.MaxCanaryPercentage))
 	}
 
+	previous := deployment.CurrentPercent
 	deployment.CurrentPercent = percentage
 	deployment.TargetPercent = percentage
 	deployment.LastUpdate = time.Now()
 	m.mu.Unlock()
 
+	// Snapshot the stage being left before traffic moves
+	metadata := m.stageMetadata(ctx, id, previous)
+	metadata["to_percent"] = percentage
+
 	// Update routing
 	if err := m.router.UpdateRoutingPercentage(ctx, deployment.QueueName, percentage); err != nil {
 		return fmt.Errorf("failed to update routing: %w", err)
@@ -236,8 +252,8 @@ func (m *Manager) UpdateDeploymentPercentage(ctx context.Context, id string, per
 	}
 
 	// Emit event
-	m.emitEvent(deployment, "percentage_updated",
-		fmt.Sprintf("Traffic split updated to %d%%", percentage))
+	m.emitEventWithMetadata(deployment, "percentage_updated",
+		fmt.Sprintf("Traffic split updated to %d%%", percentage), metadata)
 
 	m.logger.Info("Updated deployment percentage",
 		"deployment_id", id,
@@ -260,6 +276,11 @@ func (m *Manager) PromoteDeployment(ctx context.Context, id string) error {
 		return NewCanaryError(CodeDeploymentNotActive, "deployment is not active")
 	}
 
+	if deployment.Config.ShadowMode {
+		m.mu.Unlock()
+		return NewCanaryError(CodePromotionBlocked, "shadow deployments cannot be promoted; start a routed canary instead")
+	}
+
 	deployment.Status = StatusPromoting
 	deployment.LastUpdate = time.Now()
 	m.mu.Unlock()
@@ -305,15 +326,24 @@ func (m *Manager) RollbackDeployment(ctx context.Context, id string, reason stri
 
 	deployment.Status = StatusRollingBack
 	deployment.LastUpdate = time.Now()
+	previous := deployment.CurrentPercent
 	m.mu.Unlock()
 
+	metadata := m.stageMetadata(ctx, id, previous)
+	metadata["reason"] = reason
+
 	// Set to 0%
 	if err := m.router.UpdateRoutingPercentage(ctx, deployment.QueueName, 0); err != nil {
 		return fmt.Errorf("failed to set 0%% traffic: %w", err)
 	}
 
-	// Drain canary jobs if using split queue strategy
-	if deployment.Config.RoutingStrategy == SplitQueueStrategy {
+	// Drain canary jobs if using split queue strategy. Shadow copies are
+	// discarded instead: stable already processed the originals.
+	if deployment.Config.ShadowMode {
+		if err := m.discardShadowJobs(ctx, deployment); err != nil {
+			m.logger.Warn("Failed to discard shadow jobs", "error", err)
+		}
+	} else if deployment.Config.RoutingStrategy == SplitQueueStrategy {
 		if err := m.drainCanaryQueue(ctx, deployment); err != nil {
 			m.logger.Warn("Failed to drain canary queue", "error", err)
 		}
@@ -335,8 +365,8 @@ func (m *Manager) RollbackDeployment(ctx context.Context, id string, reason stri
 	}
 
 	// Emit event
-	m.emitEvent(deployment, "deployment_rolled_back",
-		fmt.Sprintf("Canary deployment rolled back: %s", reason))
+	m.emitEventWithMetadata(deployment, "deployment_rolled_back",
+		fmt.Sprintf("Canary deployment rolled back: %s", reason), metadata)
 
 	// Send alert
 	alert := &Alert{
@@ -356,21 +386,30 @@ func (m *Manager) RollbackDeployment(ctx context.Context, id string, reason stri
 	return nil
 }
 
-// DeleteDeployment removes a deployment
+// DeleteDeployment removes a deployment. A finished deployment is archived
+// first, so it stays in ListHistory.
 func (m *Manager) DeleteDeployment(ctx context.Context, id string) error {
-	m.mu.Lock()
+	m.mu.RLock()
 	deployment, exists := m.deployments[id]
 	if !exists {
-		m.mu.Unlock()
+		m.mu.RUnlock()
 		return NewDeploymentNotFoundError(id)
 	}
+	status := deployment.Status
+	m.mu.RUnlock()
 
 	// Can only delete completed or failed deployments
-	if deployment.Status == StatusActive || deployment.Status == StatusPromoting {
-		m.mu.Unlock()
+	if status == StatusActive || status == StatusPromoting {
 		return NewCanaryError(CodeDeploymentInProgress, "cannot delete active deployment")
 	}
 
+	if status == StatusCompleted || status == StatusFailed {
+		if err := m.ensureArchived(ctx, id); err != nil {
+			return fmt.Errorf("failed to archive deployment: %w", err)
+		}
+	}
+
+	m.mu.Lock()
 	delete(m.deployments, id)
 	m.mu.Unlock()
 
@@ -432,7 +471,7 @@ func (m *Manager) GetDeploymentMetrics(ctx context.Context, id string) (*Metrics
 
 // GetDeploymentEvents returns events for a deployment
 func (m *Manager) GetDeploymentEvents(ctx context.Context, id string) ([]*DeploymentEvent, error) {
-	events, err := m.loadEventsFromRedis(ctx, id)
+	events, err := m.loadEventsFromRedis(ctx, id, 100)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load events: %w", err)
 	}
@@ -489,8 +528,12 @@ func (m *Manager) checkActiveDeployments() {
 	m.mu.RUnlock()
 
 	for _, deployment := range activeDeployments {
-		m.checkDeploymentHealth(deployment)
-		m.checkAutoPromotion(deployment)
+		if deployment.Config.Ramp != nil {
+			m.checkRamp(deployment)
+		} else {
+			m.checkDeploymentHealth(deployment)
+			m.checkAutoPromotion(deployment)
+		}
 		m.checkTimeout(deployment)
 	}
 }
@@ -499,27 +542,24 @@ func (m *Manager) checkDeploymentHealth(deployment *CanaryDeployment) {
 	ctx, cancel := context.WithTimeout(m.ctx, 30*time.Second)
 	defer cancel()
 
-	health, err := m.GetDeploymentHealth(ctx, deployment.ID)
+	stable, canary, err := m.GetDeploymentMetrics(ctx, deployment.ID)
 	if err != nil {
 		m.logger.Error("Failed to check deployment health",
 			"deployment_id", deployment.ID,
 			"error", err)
 		return
 	}
+	health := m.evaluateHealth(deployment, stable, canary)
+	m.recordHealth(deployment, health)
 
 	// Check for rollback conditions
 	if health.OverallStatus == FailingCanary {
-		reason := health.GetFailureReason()
-		if err := m.RollbackDeployment(ctx, deployment.ID, reason); err != nil {
-			m.logger.Error("Failed to rollback deployment",
-				"deployment_id", deployment.ID,
-				"error", err)
-		}
+		m.autoRollback(ctx, deployment, health.GetFailureReason(), health, stable, canary)
 	}
 }
 
 func (m *Manager) checkAutoPromotion(deployment *CanaryDeployment) {
-	if !deployment.Config.AutoPromotion {
+	if !deployment.Config.AutoPromotion || deployment.Config.ShadowMode {
 		return
 	}
 
@@ -537,7 +577,8 @@ func (m *Manager) checkAutoPromotion(deployment *CanaryDeployment) {
 	// Check promotion stages
 	for _, stage := range deployment.Config.PromotionStages {
 		if deployment.CurrentPercent < stage.Percentage {
-			if m.evaluatePromotionConditions(stableMetrics, canaryMetrics, stage.Conditions) {
+			switch m.evaluatePromotionConditions(stableMetrics, canaryMetrics, stage.Conditions, deployment.Config.MinConfidence) {
+			case DecisionPromote:
 				if err := m.UpdateDeploymentPercentage(ctx, deployment.ID, stage.Percentage); err != nil {
 					m.logger.Error("Failed to auto-promote deployment",
 						"deployment_id", deployment.ID,
@@ -548,6 +589,10 @@ func (m *Manager) checkAutoPromotion(deployment *CanaryDeployment) {
 						"deployment_id", deployment.ID,
 						"percentage", stage.Percentage)
 				}
+			case DecisionInconclusive:
+				m.logger.Debug("Auto-promotion inconclusive, waiting for more data",
+					"deployment_id", deployment.ID,
+					"target_percentage", stage.Percentage)
 			}
 			break // Only check the next stage
 		}
@@ -559,11 +604,9 @@ func (m *Manager) checkTimeout(deployment *CanaryDeployment) {
 		ctx, cancel := context.WithTimeout(m.ctx, 30*time.Second)
 		defer cancel()
 
-		if err := m.RollbackDeployment(ctx, deployment.ID, "deployment timeout"); err != nil {
-			m.logger.Error("Failed to rollback timed-out deployment",
-				"deployment_id", deployment.ID,
-				"error", err)
-		}
+		// Metrics only enrich the rollback webhook; a timeout rolls back regardless.
+		stable, canary, _ := m.GetDeploymentMetrics(ctx, deployment.ID)
+		m.autoRollback(ctx, deployment, "deployment timeout", nil, stable, canary)
 	}
 }
 
@@ -573,34 +616,46 @@ func (m *Manager) evaluateHealth(deployment *CanaryDeployment, stable, canary *M
 	}
 
 	thresholds := deployment.Config.RollbackThresholds
+	minConfidence := deployment.Config.MinConfidence
 
 	// Error rate check
 	if stable != nil && canary != nil {
-		errorRateIncrease := canary.ErrorRate - stable.ErrorRate
+		deltas := metricDeltas(stable, canary)
+		errorRateIncrease := deltas.ErrorRateIncrease
 		health.ErrorRateCheck = HealthCheck{
 			Name:      "Error Rate",
 			Passing:   errorRateIncrease <= thresholds.MaxErrorRateIncrease,
 			Message:   fmt.Sprintf("Error rate increase: %.2f%% (threshold: %.2f%%)", errorRateIncrease, thresholds.MaxErrorRateIncrease),
 			Timestamp: time.Now(),
 		}
+		// With a confidence threshold, an error rate regression only fails
+		// the canary once there are enough jobs and the difference is
+		// statistically significant.
+		if !health.ErrorRateCheck.Passing && minConfidence > 0 {
+			if canary.JobCount < int64(thresholds.RequiredSampleSize) {
+				health.ErrorRateCheck.Inconclusive = true
+				health.ErrorRateCheck.Message += fmt.Sprintf("; inconclusive: %d of %d required jobs", canary.JobCount, thresholds.RequiredSampleSize)
+			} else if confidence := errorRegressionConfidence(stable, canary, thresholds.MaxErrorRateIncrease); confidence < minConfidence {
+				health.ErrorRateCheck.Inconclusive = true
+				health.ErrorRateCheck.Message += fmt.Sprintf("; inconclusive: %.1f%% confidence (required: %.1f%%)", confidence, minConfidence)
+			}
+		}
 
 		// Latency check
-		latencyIncrease := float64(0)
-		if stable.P95Latency > 0 {
-			latencyIncrease = (canary.P95Latency - stable.P95Latency) / stable.P95Latency * 100
-		}
+		latencyIncrease := deltas.LatencyIncrease
 		health.LatencyCheck = HealthCheck{
 			Name:      "P95 Latency",
 			Passing:   latencyIncrease <= thresholds.MaxLatencyIncrease,
 			Message:   fmt.Sprintf("P95 latency increase: %.2f%% (threshold: %.2f%%)", latencyIncrease, thresholds.MaxLatencyIncrease),
 			Timestamp: time.Now(),
 		}
+		if !health.LatencyCheck.Passing && minConfidence > 0 && canary.JobCount < int64(thresholds.RequiredSampleSize) {
+			health.LatencyCheck.Inconclusive = true
+			health.LatencyCheck.Message += fmt.Sprintf("; inconclusive: %d of %d required jobs", canary.JobCount, thresholds.RequiredSampleSize)
+		}
 
 		// Throughput check
-		throughputDecrease := float64(0)
-		if stable.JobsPerSecond > 0 {
-			throughputDecrease = (stable.JobsPerSecond - canary.JobsPerSecond) / stable.JobsPerSecond * 100
-		}
+		throughputDecrease := deltas.ThroughputDecrease
 		health.ThroughputCheck = HealthCheck{
 			Name:      "Throughput",
 			Passing:   throughputDecrease <= thresholds.MaxThroughputDecrease,
@@ -629,7 +684,8 @@ func (m *Manager) evaluateHealth(deployment *CanaryDeployment, stable, canary *M
 	// Overall status
 	if health.AllChecksPass() {
 		health.OverallStatus = HealthyCanary
-	} else if health.ErrorRateCheck.Passing && health.LatencyCheck.Passing {
+	} else if (health.ErrorRateCheck.Passing || health.ErrorRateCheck.Inconclusive) &&
+		(health.LatencyCheck.Passing || health.LatencyCheck.Inconclusive) {
 		health.OverallStatus = WarningCanary
 	} else {
 		health.OverallStatus = FailingCanary
@@ -638,31 +694,40 @@ func (m *Manager) evaluateHealth(deployment *CanaryDeployment, stable, canary *M
 	return health
 }
 
-func (m *Manager) evaluatePromotionConditions(stable, canary *MetricsSnapshot, conditions SLOThresholds) bool {
+// evaluatePromotionConditions decides whether the canary may advance to the
+// next stage. Besides the absolute thresholds, a non-zero minConfidence
+// requires a two-proportion z-test to show with that confidence that the
+// canary error rate is within MaxErrorRateIncrease of stable.
+func (m *Manager) evaluatePromotionConditions(stable, canary *MetricsSnapshot, conditions SLOThresholds, minConfidence float64) PromotionDecision {
 	if stable == nil || canary == nil {
-		return false
+		return DecisionInconclusive
+	}
+
+	// Wait for real data rather than promoting on an empty window.
+	if stable.Insufficient || canary.Insufficient {
+		return DecisionInconclusive
 	}
 
 	if canary.JobCount < int64(conditions.RequiredSampleSize) {
-		return false
+		return DecisionInconclusive
 	}
 
 	// Check error rate
 	errorRateIncrease := canary.ErrorRate - stable.ErrorRate
 	if errorRateIncrease > conditions.MaxErrorRateIncrease {
-		return false
+		return DecisionHold
 	}
 
 	// Check success rate
 	if canary.SuccessRate < conditions.MinSuccessRate {
-		return false
+		return DecisionHold
 	}
 
 	// Check latency
 	if stable.P95Latency > 0 {
 		latencyIncrease := (canary.P95Latency - stable.P95Latency) / stable.P95Latency * 100
 		if latencyIncrease > conditions.MaxLatencyIncrease {
-			return false
+			return DecisionHold
 		}
 	}
 
@@ -670,11 +735,15 @@ func (m *Manager) evaluatePromotionConditions(stable, canary *MetricsSnapshot, c
 	if stable.JobsPerSecond > 0 {
 		throughputDecrease := (stable.JobsPerSecond - canary.JobsPerSecond) / stable.JobsPerSecond * 100
 		if throughputDecrease > conditions.MaxThroughputDecrease {
-			return false
+			return DecisionHold
 		}
 	}
 
-	return true
+	if minConfidence > 0 && errorParityConfidence(stable, canary, conditions.MaxErrorRateIncrease) < minConfidence {
+		return DecisionInconclusive
+	}
+
+	return DecisionPromote
 }
 
 func (m *Manager) processAlerts() {
@@ -700,9 +769,21 @@ func (m *Manager) processEvents() {
 		case <-m.ctx.Done():
 			return
 		case event := <-m.eventChan:
-			if err := m.saveEventToRedis(m.ctx, event); err != nil {
-				m.logger.Error("Failed to save event", "event_id", event.ID, "error", err)
-			}
+			m.handleEvent(m.ctx, event)
+		}
+	}
+}
+
+// handleEvent saves event and archives its deployment when it closes one.
+// Events are handled in order, so the deployment's whole trail is in Redis
+// by the time its closing event is.
+func (m *Manager) handleEvent(ctx context.Context, event *DeploymentEvent) {
+	if err := m.saveEventToRedis(ctx, event); err != nil {
+		m.logger.Error("Failed to save event", "event_id", event.ID, "error", err)
+	}
+	if event.Type == "deployment_promoted" || event.Type == "deployment_rolled_back" {
+		if err := m.archiveDeployment(ctx, event.DeploymentID); err != nil {
+			m.logger.Error("Failed to archive deployment", "deployment_id", event.DeploymentID, "error", err)
 		}
 	}
 }
@@ -718,8 +799,10 @@ func (m *Manager) cleanupExpiredData() {
 		case <-m.ctx.Done():
 			return
 		case <-ticker.C:
+			m.archiveFinishedDeployments()
 			m.cleanupOldMetrics()
 			m.cleanupOldEvents()
+			m.cleanupOldHistory()
 		}
 	}
 }
@@ -775,12 +858,17 @@ func (m *Manager) cleanupOldEvents() {
 }
 
 func (m *Manager) emitEvent(deployment *CanaryDeployment, eventType, message string) {
+	m.emitEventWithMetadata(deployment, eventType, message, nil)
+}
+
+func (m *Manager) emitEventWithMetadata(deployment *CanaryDeployment, eventType, message string, metadata map[string]interface{}) {
 	event := &DeploymentEvent{
 		ID:           "event_" + uuid.New().String(),
 		DeploymentID: deployment.ID,
 		Type:         eventType,
 		Message:      message,
 		Timestamp:    time.Now(),
+		Metadata:     metadata,
 	}
 
 	select {
@@ -793,6 +881,47 @@ func (m *Manager) emitEvent(deployment *CanaryDeployment, eventType, message str
 	}
 }
 
+// stageMetadata describes the stage a deployment leaves at fromPercent for
+// its percentage_updated or deployment_rolled_back event: the percentage and,
+// when they can be collected, the stable and canary metrics of the window
+// that just ended. Reports rebuild their per-stage snapshots from it.
+func (m *Manager) stageMetadata(ctx context.Context, id string, fromPercent int) map[string]interface{} {
+	metadata := map[string]interface{}{"from_percent": fromPercent}
+	stable, canary, err := m.GetDeploymentMetrics(ctx, id)
+	if err != nil {
+		m.logger.Warn("Failed to snapshot stage metrics",
+			"deployment_id", id,
+			"percentage", fromPercent,
+			"error", err)
+		return metadata
+	}
+	metadata["stable_metrics"] = stable
+	metadata["canary_metrics"] = canary
+	return metadata
+}
+
+// recordHealth emits a health_changed event with the checks when the overall
+// health of deployment differs from its previous evaluation, so reports get
+// a health history without an event per monitoring tick.
+func (m *Manager) recordHealth(deployment *CanaryDeployment, health *CanaryHealthStatus) {
+	m.mu.Lock()
+	current, exists := m.deployments[deployment.ID]
+	if !exists || current.LastHealth == health.OverallStatus {
+		m.mu.Unlock()
+		return
+	}
+	previous := current.LastHealth
+	current.LastHealth = health.OverallStatus
+	m.mu.Unlock()
+
+	m.emitEventWithMetadata(deployment, "health_changed",
+		fmt.Sprintf("Canary health changed to %s: %s", health.OverallStatus, health.GetFailureReason()),
+		map[string]interface{}{
+			"previous": previous,
+			"health":   health,
+		})
+}
+
 func (m *Manager) copyDeployment(deployment *CanaryDeployment) *CanaryDeployment {
 	// Create a deep copy to prevent external modification
 	copy := *deployment
@@ -896,10 +1025,12 @@ func (m *Manager) saveEventToRedis(ctx context.Context, event *DeploymentEvent)
 	}).Err()
 }
 
-func (m *Manager) loadEventsFromRedis(ctx context.Context, deploymentID string) ([]*DeploymentEvent, error) {
+// loadEventsFromRedis loads the events of a deployment newest first, up to
+// the ZREVRANGE stop index; -1 loads every event.
+func (m *Manager) loadEventsFromRedis(ctx context.Context, deploymentID string, stop int64) ([]*DeploymentEvent, error) {
 	key := fmt.Sprintf("canary:events:%s", deploymentID)
 
-	results, err := m.redis.ZRevRange(ctx, key, 0, 100).Result()
+	results, err := m.redis.ZRevRange(ctx, key, 0, stop).Result()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load events: %w", err)
 	}
@@ -19,11 +19,18 @@ type Config struct {
 	MetricsInterval    time.Duration `json:"metrics_interval" yaml:"metrics_interval"`
 	HealthCheckInterval time.Duration `json:"health_check_interval" yaml:"health_check_interval"`
 	WorkerTimeout      time.Duration `json:"worker_timeout" yaml:"worker_timeout"`
+	// PrometheusURL switches SLO metrics collection from Redis to the
+	// Prometheus HTTP API at this base URL when set.
+	PrometheusURL      string        `json:"prometheus_url" yaml:"prometheus_url"`
 
 	// Performance tuning
 	MaxConcurrentDeployments int           `json:"max_concurrent_deployments" yaml:"max_concurrent_deployments"`
 	MetricsRetention        time.Duration `json:"metrics_retention" yaml:"metrics_retention"`
 	EventRetention          time.Duration `json:"event_retention" yaml:"event_retention"`
+	// HistoryRetention is how long records of finished deployments are
+	// kept for ListHistory, independent of the metrics and events
+	// retention; negative keeps them forever.
+	HistoryRetention        time.Duration `json:"history_retention" yaml:"history_retention"`
 
 	// Safety limits
 	MaxCanaryPercentage     int           `json:"max_canary_percentage" yaml:"max_canary_percentage"`
@@ -68,6 +75,10 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("max_concurrent_deployments must be positive")
 	}
 
+	if c.HistoryRetention > 0 && c.HistoryRetention < c.EventRetention {
+		return fmt.Errorf("history_retention must be at least event_retention, or negative to keep history forever")
+	}
+
 	if c.MaxCanaryPercentage < 1 || c.MaxCanaryPercentage > 100 {
 		return fmt.Errorf("max_canary_percentage must be between 1 and 100")
 	}
@@ -113,6 +124,10 @@ func (c *Config) SetDefaults() {
 		c.EventRetention = 7 * 24 * time.Hour
 	}
 
+	if c.HistoryRetention == 0 {
+		c.HistoryRetention = 365 * 24 * time.Hour
+	}
+
 	if c.MaxCanaryPercentage == 0 {
 		c.MaxCanaryPercentage = 50
 	}
@@ -170,6 +185,14 @@ func (cc *CanaryConfig) Validate() error {
 		return fmt.Errorf("invalid routing_strategy: %s", cc.RoutingStrategy)
 	}
 
+	if cc.MinConfidence < 0 || cc.MinConfidence >= 100 {
+		return fmt.Errorf("min_confidence must be at least 0 and below 100")
+	}
+
+	if cc.ShadowMode && cc.RoutingStrategy != SplitQueueStrategy {
+		return fmt.Errorf("shadow_mode requires the %s routing strategy", SplitQueueStrategy)
+	}
+
 	// Validate promotion stages
 	for i, stage := range cc.PromotionStages {
 		if stage.Percentage < 0 || stage.Percentage > 100 {
@@ -188,6 +211,27 @@ func (cc *CanaryConfig) Validate() error {
 		return fmt.Errorf("rollback_thresholds: %w", err)
 	}
 
+	if cc.RollbackWebhook != nil {
+		if err := cc.RollbackWebhook.Validate(); err != nil {
+			return fmt.Errorf("rollback_webhook: %w", err)
+		}
+	}
+
+	if cc.Ramp != nil {
+		if cc.ShadowMode {
+			return fmt.Errorf("ramp cannot be used with shadow_mode")
+		}
+		if err := cc.Ramp.Validate(); err != nil {
+			return fmt.Errorf("ramp: %w", err)
+		}
+	}
+
+	if cc.CostGuard != nil {
+		if err := cc.CostGuard.Validate(); err != nil {
+			return fmt.Errorf("cost_guard: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -324,6 +368,7 @@ func DefaultCanaryConfig() *CanaryConfig {
 		MinCanaryDuration: 5 * time.Minute,
 		DrainTimeout:      5 * time.Minute,
 		MetricsWindow:     5 * time.Minute,
+		MinConfidence:     95.0,
 		RollbackThresholds: SLOThresholds{
 			MaxErrorRateIncrease:  10.0, // 10 percentage points
 			MaxLatencyIncrease:    100.0, // 100% increase
@@ -348,6 +393,7 @@ func ConservativeCanaryConfig() *CanaryConfig {
 		MinCanaryDuration: 15 * time.Minute,
 		DrainTimeout:      10 * time.Minute,
 		MetricsWindow:     10 * time.Minute,
+		MinConfidence:     99.0,
 		PromotionStages: []PromotionStage{
 			{
 				Percentage:  2,
@@ -397,6 +443,7 @@ func AggressiveCanaryConfig() *CanaryConfig {
 		MinCanaryDuration: 2 * time.Minute,
 		DrainTimeout:      2 * time.Minute,
 		MetricsWindow:     2 * time.Minute,
+		MinConfidence:     90.0,
 		PromotionStages: []PromotionStage{
 			{
 				Percentage:  10,
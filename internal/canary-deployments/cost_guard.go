@@ -0,0 +1,250 @@
+package canary_deployments
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// CostGuardConfig caps what the canary lane may spend per window. Each job
+// routed or mirrored to the canary consumes its estimated cost from the
+// window's budget; once a job would overrun it, jobs go to stable until the
+// next window, whatever the canary percentage.
+type CostGuardConfig struct {
+	// Budget is the cost the canary may consume per Window.
+	Budget float64       `json:"budget"`
+	Window time.Duration `json:"window"`
+	// CostField is the payload field holding a job's estimated cost, as a
+	// dotted path (e.g. "billing.estimated_cost"); numbers and numeric
+	// strings are accepted.
+	CostField string `json:"cost_field,omitempty"`
+	// JobCost is the cost of a job without CostField, or with an unusable
+	// value in it.
+	JobCost float64 `json:"job_cost"`
+}
+
+// Validate checks the budget and cost settings.
+func (cg *CostGuardConfig) Validate() error {
+	if cg.Budget <= 0 {
+		return fmt.Errorf("budget must be positive")
+	}
+	if cg.Window <= 0 {
+		return fmt.Errorf("window must be positive")
+	}
+	if cg.JobCost < 0 {
+		return fmt.Errorf("job_cost must not be negative")
+	}
+	if cg.CostField == "" && cg.JobCost == 0 {
+		return fmt.Errorf("cost_field or job_cost is required")
+	}
+	return nil
+}
+
+// JobCostOf estimates the cost of running job on the canary.
+func (cg *CostGuardConfig) JobCostOf(job *Job) float64 {
+	if cg.CostField == "" {
+		return cg.JobCost
+	}
+	var value interface{} = job.Payload
+	for _, part := range strings.Split(cg.CostField, ".") {
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return cg.JobCost
+		}
+		value = fields[part]
+	}
+	var cost float64
+	switch v := value.(type) {
+	case float64:
+		cost = v
+	case int:
+		cost = float64(v)
+	case int64:
+		cost = float64(v)
+	case string:
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return cg.JobCost
+		}
+		cost = parsed
+	default:
+		return cg.JobCost
+	}
+	if cost < 0 {
+		return cg.JobCost
+	}
+	return cost
+}
+
+// CostBudgetStatus is a deployment's canary spend in the current window.
+type CostBudgetStatus struct {
+	DeploymentID string    `json:"deployment_id"`
+	Budget       float64   `json:"budget"`
+	Spent        float64   `json:"spent"`
+	Remaining    float64   `json:"remaining"`
+	Exhausted    bool      `json:"exhausted"`
+	WindowStart  time.Time `json:"window_start"`
+	WindowEnd    time.Time `json:"window_end"`
+}
+
+// admitCostScript charges ARGV[1] to the spend counter KEYS[1] unless that
+// would take it past the budget ARGV[2]. It returns 1 when charged, 0 when
+// refused, and -1 for the first refusal of the window, which it records in
+// KEYS[2]. Both keys expire after ARGV[3] milliseconds.
+var admitCostScript = redis.NewScript(`
+local spent = tonumber(redis.call('GET', KEYS[1]) or '0')
+if spent + tonumber(ARGV[1]) > tonumber(ARGV[2]) then
+  if redis.call('SET', KEYS[2], '1', 'NX', 'PX', ARGV[3]) then
+    return -1
+  end
+  return 0
+end
+redis.call('INCRBYFLOAT', KEYS[1], ARGV[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+return 1
+`)
+
+// CostGuard tracks a deployment's canary spend in Redis, in fixed windows
+// aligned to the epoch, so every router sharing the Redis shares the budget.
+type CostGuard struct {
+	redis        *redis.Client
+	deploymentID string
+	config       *CostGuardConfig
+}
+
+// NewCostGuard creates a guard for a deployment's cost budget
+func NewCostGuard(redis *redis.Client, deploymentID string, config *CostGuardConfig) *CostGuard {
+	return &CostGuard{redis: redis, deploymentID: deploymentID, config: config}
+}
+
+func (g *CostGuard) window(now time.Time) (time.Time, time.Time) {
+	start := now.Truncate(g.config.Window)
+	return start, start.Add(g.config.Window)
+}
+
+func (g *CostGuard) keys(start time.Time) (spent, exhausted string) {
+	base := fmt.Sprintf("canary:cost:%s:%d", g.deploymentID, start.Unix())
+	return base, base + ":exhausted"
+}
+
+// Admit charges job's cost to the current window and reports whether it
+// fit the budget; firstRefusal is set for the first job refused in the
+// window.
+func (g *CostGuard) Admit(ctx context.Context, job *Job, now time.Time) (admitted, firstRefusal bool, err error) {
+	start, _ := g.window(now)
+	spentKey, exhaustedKey := g.keys(start)
+	cost := g.config.JobCostOf(job)
+	ttl := 2 * g.config.Window
+	res, err := admitCostScript.Run(ctx, g.redis, []string{spentKey, exhaustedKey},
+		strconv.FormatFloat(cost, 'f', -1, 64),
+		strconv.FormatFloat(g.config.Budget, 'f', -1, 64),
+		ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, false, fmt.Errorf("failed to charge canary cost: %w", err)
+	}
+	return res == 1, res == -1, nil
+}
+
+// Status returns the spend in the window containing now.
+func (g *CostGuard) Status(ctx context.Context, now time.Time) (*CostBudgetStatus, error) {
+	start, end := g.window(now)
+	spentKey, exhaustedKey := g.keys(start)
+	spent, err := g.redis.Get(ctx, spentKey).Float64()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to read canary cost: %w", err)
+	}
+	refused, err := g.redis.Exists(ctx, exhaustedKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read canary cost: %w", err)
+	}
+	remaining := g.config.Budget - spent
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &CostBudgetStatus{
+		DeploymentID: g.deploymentID,
+		Budget:       g.config.Budget,
+		Spent:        spent,
+		Remaining:    remaining,
+		Exhausted:    refused > 0,
+		WindowStart:  start,
+		WindowEnd:    end,
+	}, nil
+}
+
+// costGuardedDeployment returns the active deployment for queue that has a
+// cost guard, if any
+func (m *Manager) costGuardedDeployment(queue string) *CanaryDeployment {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, deployment := range m.deployments {
+		if deployment.QueueName == queue && deployment.Config != nil && deployment.Config.CostGuard != nil &&
+			(deployment.Status == StatusActive || deployment.Status == StatusPromoting) {
+			return m.copyDeployment(deployment)
+		}
+	}
+	return nil
+}
+
+// admitCanaryCost charges job to the canary budget of its queue's
+// deployment and reports whether the canary may take it. Jobs of queues
+// without a cost guard are always admitted. When Redis cannot be reached
+// the job is refused, since stable can always take it.
+func (m *Manager) admitCanaryCost(ctx context.Context, job *Job) bool {
+	deployment := m.costGuardedDeployment(job.Queue)
+	if deployment == nil {
+		return true
+	}
+	guard := NewCostGuard(m.redis, deployment.ID, deployment.Config.CostGuard)
+	admitted, firstRefusal, err := guard.Admit(ctx, job, time.Now())
+	if err != nil {
+		m.logger.Warn("Failed to check canary cost budget; routing to stable",
+			"deployment_id", deployment.ID,
+			"job_id", job.ID,
+			"error", err)
+		return false
+	}
+	if firstRefusal {
+		m.emitEventWithMetadata(deployment, "cost_budget_exhausted",
+			fmt.Sprintf("Canary cost budget of %g per %s used up; routing to stable until the window ends",
+				deployment.Config.CostGuard.Budget, deployment.Config.CostGuard.Window),
+			map[string]interface{}{
+				"budget": deployment.Config.CostGuard.Budget,
+				"window": deployment.Config.CostGuard.Window.String(),
+			})
+	}
+	return admitted
+}
+
+// recordCostCapped counts a job, or shadow copy, the cost guard kept off
+// the canary. The router's canary count still includes jobs it picked for
+// the canary before the guard sent them back to stable.
+func (m *Manager) recordCostCapped(ctx context.Context, queue string) {
+	key := fmt.Sprintf("canary:stats:%s:cost_capped", queue)
+	pipe := m.redis.Pipeline()
+	pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, 24*time.Hour)
+	if _, err := pipe.Exec(ctx); err != nil {
+		m.logger.Debug("Failed to update cost cap stats",
+			"queue", queue,
+			"error", err)
+	}
+}
+
+// GetCostBudgetStatus returns a deployment's canary spend in the current
+// cost window.
+func (m *Manager) GetCostBudgetStatus(ctx context.Context, id string) (*CostBudgetStatus, error) {
+	deployment, err := m.GetDeployment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if deployment.Config == nil || deployment.Config.CostGuard == nil {
+		return nil, NewCanaryError(CodeInvalidConfiguration, "deployment has no cost guard")
+	}
+	return NewCostGuard(m.redis, deployment.ID, deployment.Config.CostGuard).Status(ctx, time.Now())
+}
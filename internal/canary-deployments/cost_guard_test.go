@@ -0,0 +1,128 @@
+//go:build canary_deployments_tests
+// +build canary_deployments_tests
+
+package canary_deployments
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupCostManager(t *testing.T, guard *CostGuardConfig, shadow bool) (*Manager, *redis.Client, *CanaryDeployment) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	config := &Config{MaxConcurrentDeployments: 5, MaxCanaryPercentage: 100, HealthCheckInterval: time.Second}
+	config.SetDefaults()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	manager := NewManager(config, rdb, logger)
+
+	canaryConfig := DefaultCanaryConfig()
+	canaryConfig.ShadowMode = shadow
+	canaryConfig.CostGuard = guard
+	deployment, err := manager.CreateDeployment(context.Background(), canaryConfig)
+	require.NoError(t, err)
+	manager.deployments[deployment.ID].QueueName = "orders"
+	if !shadow {
+		require.NoError(t, manager.router.UpdateRoutingPercentage(context.Background(), "orders", 100))
+	}
+	return manager, rdb, deployment
+}
+
+func TestCostGuardConfig_JobCostOf(t *testing.T) {
+	cg := &CostGuardConfig{Budget: 10, Window: time.Hour, CostField: "billing.cost", JobCost: 1}
+	require.NoError(t, cg.Validate())
+
+	job := func(payload map[string]interface{}) *Job { return &Job{Payload: payload} }
+	assert.Equal(t, 2.5, cg.JobCostOf(job(map[string]interface{}{"billing": map[string]interface{}{"cost": 2.5}})))
+	assert.Equal(t, 4.0, cg.JobCostOf(job(map[string]interface{}{"billing": map[string]interface{}{"cost": "4"}})))
+	assert.Equal(t, 1.0, cg.JobCostOf(job(map[string]interface{}{"billing": map[string]interface{}{"cost": "lots"}})))
+	assert.Equal(t, 1.0, cg.JobCostOf(job(map[string]interface{}{"billing": 3})))
+	assert.Equal(t, 1.0, cg.JobCostOf(job(nil)))
+
+	for _, bad := range []*CostGuardConfig{
+		{Budget: 0, Window: time.Hour, JobCost: 1},
+		{Budget: 10, Window: 0, JobCost: 1},
+		{Budget: 10, Window: time.Hour, JobCost: -1},
+		{Budget: 10, Window: time.Hour},
+	} {
+		assert.Error(t, bad.Validate(), "%+v", bad)
+	}
+	canaryConfig := DefaultCanaryConfig()
+	canaryConfig.CostGuard = &CostGuardConfig{Budget: 10, Window: time.Hour}
+	assert.Error(t, canaryConfig.Validate())
+}
+
+func TestManager_CostGuardCapsCanaryBelowPercentage(t *testing.T) {
+	manager, rdb, deployment := setupCostManager(t, &CostGuardConfig{Budget: 5, Window: time.Hour, CostField: "cost", JobCost: 2}, false)
+	ctx := context.Background()
+
+	var targets []string
+	for i, cost := range []interface{}{2, nil, 3, 1} {
+		payload := map[string]interface{}{}
+		if cost != nil {
+			payload["cost"] = cost
+		}
+		target, err := manager.RouteJob(ctx, &Job{ID: fmt.Sprintf("job-%d", i), Queue: "orders", Payload: payload})
+		require.NoError(t, err)
+		targets = append(targets, target)
+	}
+	// 2 + 2 fit the budget of 5; 3 would overrun it, and 1 still fits
+	assert.Equal(t, []string{"orders@canary", "orders@canary", "orders", "orders@canary"}, targets)
+
+	status, err := manager.GetCostBudgetStatus(ctx, deployment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 5.0, status.Spent)
+	assert.Equal(t, 0.0, status.Remaining)
+	assert.True(t, status.Exhausted)
+	assert.Equal(t, time.Hour, status.WindowEnd.Sub(status.WindowStart))
+
+	capped, err := rdb.Get(ctx, "canary:stats:orders:cost_capped").Int64()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), capped)
+
+	var exhausted int
+	for len(manager.eventChan) > 0 {
+		if event := <-manager.eventChan; event.Type == "cost_budget_exhausted" {
+			exhausted++
+		}
+	}
+	assert.Equal(t, 1, exhausted)
+
+	// A new window starts with the full budget
+	guard := NewCostGuard(rdb, deployment.ID, manager.deployments[deployment.ID].Config.CostGuard)
+	admitted, first, err := guard.Admit(ctx, &Job{Payload: map[string]interface{}{"cost": 5}}, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.True(t, admitted)
+	assert.False(t, first)
+}
+
+func TestManager_CostGuardStopsShadowMirroring(t *testing.T) {
+	manager, rdb, _ := setupCostManager(t, &CostGuardConfig{Budget: 1, Window: time.Hour, JobCost: 1}, true)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		target, err := manager.RouteJob(ctx, &Job{ID: fmt.Sprintf("job-%d", i), Queue: "orders"})
+		require.NoError(t, err)
+		assert.Equal(t, "orders", target)
+	}
+
+	mirrored, err := rdb.LLen(ctx, "orders@canary").Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), mirrored)
+
+	stats, err := manager.router.GetRoutingStats(ctx, "orders")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats["shadow_mirrored"])
+	assert.Equal(t, int64(2), stats["cost_capped"])
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -41,6 +42,9 @@ func (h *HTTPHandler) RegisterRoutes(router *mux.Router) {
 	api.HandleFunc("/deployments/{id}/health", h.getDeploymentHealth).Methods("GET")
 	api.HandleFunc("/deployments/{id}/metrics", h.getDeploymentMetrics).Methods("GET")
 	api.HandleFunc("/deployments/{id}/events", h.getDeploymentEvents).Methods("GET")
+	api.HandleFunc("/deployments/{id}/report", h.getDeploymentReport).Methods("GET")
+	api.HandleFunc("/deployments/{id}/cost", h.getDeploymentCost).Methods("GET")
+	api.HandleFunc("/history", h.listHistory).Methods("GET")
 
 	// Worker management
 	api.HandleFunc("/workers", h.listWorkers).Methods("GET")
@@ -241,6 +245,74 @@ func (h *HTTPHandler) getDeploymentEvents(w http.ResponseWriter, r *http.Request
 	h.writeJSON(w, http.StatusOK, response)
 }
 
+func (h *HTTPHandler) getDeploymentReport(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	format := r.URL.Query().Get("format")
+
+	report, err := h.manager.GenerateReport(r.Context(), id, format)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if format == ReportFormatMarkdown {
+		w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	} else {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(report)
+}
+
+func (h *HTTPHandler) getDeploymentCost(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	status, err := h.manager.GetCostBudgetStatus(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, status)
+}
+
+// listHistory serves archived deployments, filtered by the queue, tenant,
+// outcome, since and until (RFC 3339) and limit query parameters.
+func (h *HTTPHandler) listHistory(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := HistoryFilter{
+		QueueName: query.Get("queue"),
+		TenantID:  query.Get("tenant"),
+		Outcome:   ReportOutcome(query.Get("outcome")),
+	}
+	for name, dst := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if v := query.Get(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				h.writeError(w, NewValidationError(name, "must be an RFC 3339 time"))
+				return
+			}
+			*dst = t
+		}
+	}
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			h.writeError(w, NewValidationError("limit", "must be an integer"))
+			return
+		}
+		filter.Limit = limit
+	}
+
+	history, err := h.manager.ListHistory(r.Context(), filter)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, HistoryResponse{History: history, Count: len(history)})
+}
+
 // Worker endpoints
 
 func (h *HTTPHandler) listWorkers(w http.ResponseWriter, r *http.Request) {
@@ -394,6 +466,7 @@ type CreateDeploymentRequest struct {
 	MinDuration     string          `json:"min_duration,omitempty"`
 	DrainTimeout    string          `json:"drain_timeout,omitempty"`
 	MetricsWindow   string          `json:"metrics_window,omitempty"`
+	MinConfidence   *float64        `json:"min_confidence,omitempty"` // 0 disables the significance check
 	CreatedBy       string          `json:"created_by,omitempty"`
 	Profile         string          `json:"profile,omitempty"` // "default", "conservative", "aggressive"
 }
@@ -431,6 +504,9 @@ func (req *CreateDeploymentRequest) ToCanaryConfig() *CanaryConfig {
 	if req.AutoPromotion {
 		config.AutoPromotion = req.AutoPromotion
 	}
+	if req.MinConfidence != nil {
+		config.MinConfidence = *req.MinConfidence
+	}
 
 	// Parse durations
 	if req.MaxDuration != "" {
@@ -536,6 +612,11 @@ type EventsResponse struct {
 	Count  int                `json:"count"`
 }
 
+type HistoryResponse struct {
+	History []*DeploymentHistory `json:"history"`
+	Count   int                  `json:"count"`
+}
+
 type WorkersResponse struct {
 	Workers []*WorkerInfo `json:"workers"`
 	Count   int           `json:"count"`
@@ -0,0 +1,234 @@
+package canary_deployments
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// History keys: a sorted set of archived deployment IDs scored by
+// completion time (Unix seconds), and a hash of their records by ID.
+const (
+	historyIndexKey = "canary:history"
+	historyDataKey  = "canary:history:data"
+)
+
+// historyBatch is how many records ListHistory reads per round trip.
+const historyBatch = 100
+
+// DeploymentHistory is the audit record of a finished deployment: its
+// report (outcome and reason, final metrics, stages, health history and
+// every event) with the configuration it ran under. It outlives the
+// deployment, its events and its live metrics.
+type DeploymentHistory struct {
+	DeploymentReport
+	CreatedBy  string        `json:"created_by,omitempty"`
+	Config     *CanaryConfig `json:"config"`
+	ArchivedAt time.Time     `json:"archived_at"`
+}
+
+// HistoryFilter selects archived deployments for ListHistory. Zero fields
+// match everything; Since and Until bound the completion time, inclusive.
+type HistoryFilter struct {
+	QueueName string        `json:"queue_name,omitempty"`
+	TenantID  string        `json:"tenant_id,omitempty"`
+	Outcome   ReportOutcome `json:"outcome,omitempty"`
+	Since     time.Time     `json:"since,omitempty"`
+	Until     time.Time     `json:"until,omitempty"`
+	Limit     int           `json:"limit,omitempty"`
+}
+
+// Validate checks the filter's outcome and limit
+func (f *HistoryFilter) Validate() error {
+	switch f.Outcome {
+	case "", OutcomePromoted, OutcomeRolledBack:
+	default:
+		return NewValidationError("outcome", fmt.Sprintf("unknown outcome %q (use promoted or rolled_back)", f.Outcome))
+	}
+	if f.Limit < 0 {
+		return NewValidationError("limit", "must not be negative")
+	}
+	if !f.Since.IsZero() && !f.Until.IsZero() && f.Until.Before(f.Since) {
+		return NewValidationError("until", "must not be before since")
+	}
+	return nil
+}
+
+func (f *HistoryFilter) matches(entry *DeploymentHistory) bool {
+	return (f.QueueName == "" || entry.QueueName == f.QueueName) &&
+		(f.TenantID == "" || entry.TenantID == f.TenantID) &&
+		(f.Outcome == "" || entry.Outcome == f.Outcome)
+}
+
+// ListHistory returns the archived deployments matching filter, most
+// recently completed first, up to filter.Limit (0 for all).
+func (m *Manager) ListHistory(ctx context.Context, filter HistoryFilter) ([]*DeploymentHistory, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	rng := &redis.ZRangeBy{Min: "-inf", Max: "+inf"}
+	if !filter.Since.IsZero() {
+		rng.Min = strconv.FormatInt(filter.Since.Unix(), 10)
+	}
+	if !filter.Until.IsZero() {
+		rng.Max = strconv.FormatInt(filter.Until.Unix(), 10)
+	}
+	ids, err := m.redis.ZRevRangeByScore(ctx, historyIndexKey, rng).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list history: %w", err)
+	}
+
+	history := make([]*DeploymentHistory, 0)
+	for start := 0; start < len(ids); start += historyBatch {
+		end := start + historyBatch
+		if end > len(ids) {
+			end = len(ids)
+		}
+		values, err := m.redis.HMGet(ctx, historyDataKey, ids[start:end]...).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load history: %w", err)
+		}
+		for i, value := range values {
+			data, ok := value.(string)
+			if !ok {
+				continue
+			}
+			var entry DeploymentHistory
+			if err := json.Unmarshal([]byte(data), &entry); err != nil {
+				m.logger.Warn("Failed to unmarshal history record", "deployment_id", ids[start+i], "error", err)
+				continue
+			}
+			if !filter.matches(&entry) {
+				continue
+			}
+			history = append(history, &entry)
+			if filter.Limit > 0 && len(history) == filter.Limit {
+				return history, nil
+			}
+		}
+	}
+	return history, nil
+}
+
+// archiveDeployment writes the history record of a completed or failed
+// deployment, replacing any earlier one.
+func (m *Manager) archiveDeployment(ctx context.Context, id string) error {
+	deployment, err := m.GetDeployment(ctx, id)
+	if err != nil {
+		return err
+	}
+	if deployment.Status != StatusCompleted && deployment.Status != StatusFailed {
+		return NewCanaryError(CodeDeploymentInProgress, "only finished deployments are archived")
+	}
+	report, err := m.buildReport(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	entry := &DeploymentHistory{
+		DeploymentReport: *report,
+		CreatedBy:        deployment.CreatedBy,
+		Config:           deployment.Config,
+		ArchivedAt:       time.Now(),
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal history record: %w", err)
+	}
+
+	completed := deployment.LastUpdate
+	if deployment.CompletedAt != nil {
+		completed = *deployment.CompletedAt
+	}
+	_, err = m.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, historyDataKey, id, data)
+		pipe.ZAdd(ctx, historyIndexKey, redis.Z{Score: float64(completed.Unix()), Member: id})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save history record: %w", err)
+	}
+
+	m.logger.Info("Archived canary deployment",
+		"deployment_id", id,
+		"outcome", report.Outcome)
+	return nil
+}
+
+// ensureArchived archives a finished deployment unless it already is.
+func (m *Manager) ensureArchived(ctx context.Context, id string) error {
+	archived, err := m.redis.HExists(ctx, historyDataKey, id).Result()
+	if err != nil {
+		return fmt.Errorf("failed to check history: %w", err)
+	}
+	if archived {
+		return nil
+	}
+	return m.archiveDeployment(ctx, id)
+}
+
+// archiveFinishedDeployments archives the finished deployments that have
+// no history record yet, such as those whose closing event was dropped,
+// before their events age out.
+func (m *Manager) archiveFinishedDeployments() {
+	m.mu.RLock()
+	finished := make([]string, 0)
+	for id, deployment := range m.deployments {
+		if deployment.Status == StatusCompleted || deployment.Status == StatusFailed {
+			finished = append(finished, id)
+		}
+	}
+	m.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(m.ctx, 5*time.Minute)
+	defer cancel()
+
+	for _, id := range finished {
+		if err := m.ensureArchived(ctx, id); err != nil {
+			m.logger.Error("Failed to archive deployment", "deployment_id", id, "error", err)
+		}
+	}
+}
+
+// cleanupOldHistory drops history records of deployments completed longer
+// than HistoryRetention ago; a negative retention keeps them forever.
+func (m *Manager) cleanupOldHistory() {
+	if m.config.HistoryRetention < 0 {
+		return
+	}
+	cutoff := strconv.FormatInt(time.Now().Add(-m.config.HistoryRetention).Unix(), 10)
+
+	ctx, cancel := context.WithTimeout(m.ctx, 5*time.Minute)
+	defer cancel()
+
+	ids, err := m.redis.ZRangeByScore(ctx, historyIndexKey, &redis.ZRangeBy{Min: "-inf", Max: "(" + cutoff}).Result()
+	if err != nil {
+		m.logger.Error("Failed to list history for cleanup", "error", err)
+		return
+	}
+	for start := 0; start < len(ids); start += historyBatch {
+		end := start + historyBatch
+		if end > len(ids) {
+			end = len(ids)
+		}
+		batch := ids[start:end]
+		members := make([]interface{}, len(batch))
+		for i, id := range batch {
+			members[i] = id
+		}
+		_, err := m.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HDel(ctx, historyDataKey, batch...)
+			pipe.ZRem(ctx, historyIndexKey, members...)
+			return nil
+		})
+		if err != nil {
+			m.logger.Error("Failed to clean up history", "error", err)
+			return
+		}
+	}
+}
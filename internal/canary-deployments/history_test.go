@@ -0,0 +1,126 @@
+//go:build canary_deployments_tests
+// +build canary_deployments_tests
+
+package canary_deployments
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_History(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	config := &Config{MaxConcurrentDeployments: 5, MaxCanaryPercentage: 100, HealthCheckInterval: time.Second}
+	config.SetDefaults()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	manager := NewManager(config, rdb, logger)
+	manager.collector = staticCollector{
+		"v1": {JobCount: 100, ErrorRate: 1, P95Latency: 100, JobsPerSecond: 10},
+		"v2": {JobCount: 100, ErrorRate: 1, P95Latency: 100, JobsPerSecond: 10},
+	}
+
+	ctx := context.Background()
+	// flush handles the emitted events as the event processor would
+	flush := func() {
+		for {
+			select {
+			case ev := <-manager.eventChan:
+				manager.handleEvent(ctx, ev)
+			default:
+				return
+			}
+		}
+	}
+	create := func(queue string) string {
+		deployment, err := manager.CreateDeployment(ctx, DefaultCanaryConfig())
+		require.NoError(t, err)
+		live := manager.deployments[deployment.ID]
+		live.QueueName = queue
+		live.StableVersion, live.CanaryVersion = "v1", "v2"
+		return deployment.ID
+	}
+
+	promoted := create("orders")
+	require.NoError(t, manager.UpdateDeploymentPercentage(ctx, promoted, 20))
+	require.NoError(t, manager.PromoteDeployment(ctx, promoted))
+	flush()
+
+	rolledBack := create("billing")
+	require.NoError(t, manager.RollbackDeployment(ctx, rolledBack, "error spike"))
+	flush()
+
+	history, err := manager.ListHistory(ctx, HistoryFilter{})
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+
+	history, err = manager.ListHistory(ctx, HistoryFilter{Outcome: OutcomeRolledBack})
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, rolledBack, history[0].DeploymentID)
+	assert.Equal(t, "error spike", history[0].Reason)
+
+	history, err = manager.ListHistory(ctx, HistoryFilter{QueueName: "orders"})
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	entry := history[0]
+	assert.Equal(t, OutcomePromoted, entry.Outcome)
+	assert.Equal(t, 100, entry.FinalPercent)
+	require.NotNil(t, entry.Config)
+	assert.Equal(t, DefaultCanaryConfig().RoutingStrategy, entry.Config.RoutingStrategy)
+	require.NotNil(t, entry.Stable, "final metrics are kept")
+	assert.NotEmpty(t, entry.Events)
+	assert.Equal(t, "deployment_promoted", entry.Events[len(entry.Events)-1].Type)
+
+	// the record outlives the deployment and its events
+	require.NoError(t, manager.DeleteDeployment(ctx, promoted))
+	require.NoError(t, rdb.Del(ctx, fmt.Sprintf("canary:events:%s", promoted)).Err())
+	history, err = manager.ListHistory(ctx, HistoryFilter{QueueName: "orders"})
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.NotEmpty(t, history[0].Events)
+
+	history, err = manager.ListHistory(ctx, HistoryFilter{Since: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	assert.Empty(t, history)
+
+	_, err = manager.ListHistory(ctx, HistoryFilter{Outcome: "exploded"})
+	assert.True(t, IsCode(err, CodeValidationFailed))
+
+	// a deployment whose closing event was dropped is archived by the sweep
+	dropped := create("search")
+	require.NoError(t, manager.RollbackDeployment(ctx, dropped, "timeout"))
+	for len(manager.eventChan) > 0 {
+		<-manager.eventChan
+	}
+	manager.archiveFinishedDeployments()
+	history, err = manager.ListHistory(ctx, HistoryFilter{QueueName: "search"})
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, OutcomeRolledBack, history[0].Outcome)
+
+	// history past its retention is trimmed
+	old := float64(time.Now().Add(-2 * config.HistoryRetention).Unix())
+	require.NoError(t, rdb.ZAdd(ctx, historyIndexKey, redis.Z{Score: old, Member: rolledBack}).Err())
+	manager.cleanupOldHistory()
+	history, err = manager.ListHistory(ctx, HistoryFilter{})
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	for _, entry := range history {
+		assert.NotEqual(t, rolledBack, entry.DeploymentID)
+	}
+	exists, err := rdb.HExists(ctx, historyDataKey, rolledBack).Result()
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
@@ -0,0 +1,220 @@
+package canary_deployments
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PrometheusQueries holds the PromQL templates used to build a snapshot.
+// Templates may reference {{queue}}, {{version}} and {{interval}}; each query
+// should aggregate to a single series.
+type PrometheusQueries struct {
+	JobRate     string `json:"job_rate" yaml:"job_rate"`         // jobs per second
+	FailureRate string `json:"failure_rate" yaml:"failure_rate"` // failed jobs per second
+	P95Latency  string `json:"p95_latency" yaml:"p95_latency"`   // seconds
+}
+
+// DefaultPrometheusQueries returns queries against the worker's standard metrics
+// with a version label attached by the canary workers.
+func DefaultPrometheusQueries() PrometheusQueries {
+	sel := `queue="{{queue}}",version="{{version}}"`
+	return PrometheusQueries{
+		JobRate:     `sum(rate({__name__=~"jobs_completed_total|jobs_failed_total",` + sel + `}[{{interval}}]))`,
+		FailureRate: `sum(rate(jobs_failed_total{` + sel + `}[{{interval}}]))`,
+		P95Latency:  `histogram_quantile(0.95, sum by (le) (rate(job_processing_duration_seconds_bucket{` + sel + `}[{{interval}}])))`,
+	}
+}
+
+// PrometheusMetricsCollector implements MetricsCollector using PromQL range
+// queries against the Prometheus HTTP API.
+type PrometheusMetricsCollector struct {
+	apiURL  string
+	client  *http.Client
+	queries PrometheusQueries
+	logger  *slog.Logger
+}
+
+// NewPrometheusMetricsCollector creates a collector for the Prometheus server at apiURL
+func NewPrometheusMetricsCollector(apiURL string, logger *slog.Logger) *PrometheusMetricsCollector {
+	return &PrometheusMetricsCollector{
+		apiURL:  strings.TrimRight(apiURL, "/"),
+		client:  &http.Client{Timeout: 10 * time.Second},
+		queries: DefaultPrometheusQueries(),
+		logger:  logger,
+	}
+}
+
+// WithQueries overrides the PromQL templates
+func (pmc *PrometheusMetricsCollector) WithQueries(q PrometheusQueries) *PrometheusMetricsCollector {
+	pmc.queries = q
+	return pmc
+}
+
+// CollectSnapshot collects a metrics snapshot for a specific queue and version.
+// When Prometheus has no samples for the window the snapshot is flagged
+// Insufficient instead of reporting zeros.
+func (pmc *PrometheusMetricsCollector) CollectSnapshot(ctx context.Context, queue string, version string, window time.Duration) (*MetricsSnapshot, error) {
+	if window <= 0 {
+		return nil, ErrInvalidMetricsWindow
+	}
+	end := time.Now()
+	start := end.Add(-window)
+	step := promStep(window)
+
+	snapshot := &MetricsSnapshot{
+		Timestamp:   end,
+		WindowStart: start,
+		WindowEnd:   end,
+		Version:     version,
+	}
+
+	jobRate, ok, err := pmc.queryAverage(ctx, pmc.render(pmc.queries.JobRate, queue, version, step), start, end, step)
+	if err != nil {
+		return nil, fmt.Errorf("%w: job rate: %v", ErrMetricsCollectionFailed, err)
+	}
+	if !ok || jobRate <= 0 {
+		snapshot.Insufficient = true
+		pmc.logger.Debug("Insufficient Prometheus data for snapshot",
+			"queue", queue,
+			"version", version,
+			"window", window)
+		return snapshot, nil
+	}
+
+	// An absent failure series means no failures were recorded.
+	failureRate, _, err := pmc.queryAverage(ctx, pmc.render(pmc.queries.FailureRate, queue, version, step), start, end, step)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failure rate: %v", ErrMetricsCollectionFailed, err)
+	}
+	p95, p95ok, err := pmc.queryAverage(ctx, pmc.render(pmc.queries.P95Latency, queue, version, step), start, end, step)
+	if err != nil {
+		return nil, fmt.Errorf("%w: p95 latency: %v", ErrMetricsCollectionFailed, err)
+	}
+
+	snapshot.JobsPerSecond = jobRate
+	snapshot.JobCount = int64(math.Round(jobRate * window.Seconds()))
+	snapshot.ErrorCount = int64(math.Round(failureRate * window.Seconds()))
+	if snapshot.ErrorCount > snapshot.JobCount {
+		snapshot.ErrorCount = snapshot.JobCount
+	}
+	snapshot.SuccessCount = snapshot.JobCount - snapshot.ErrorCount
+	snapshot.ErrorRate = math.Min(failureRate/jobRate*100, 100)
+	snapshot.SuccessRate = 100 - snapshot.ErrorRate
+	if p95ok {
+		snapshot.P95Latency = p95 * 1000
+	}
+
+	pmc.logger.Debug("Collected Prometheus metrics snapshot",
+		"queue", queue,
+		"version", version,
+		"window", window,
+		"job_count", snapshot.JobCount,
+		"error_rate", snapshot.ErrorRate,
+		"p95_latency_ms", snapshot.P95Latency)
+
+	return snapshot, nil
+}
+
+// GetHistoricalMetrics returns a single snapshot covering since..now;
+// Prometheus already keeps the underlying history.
+func (pmc *PrometheusMetricsCollector) GetHistoricalMetrics(ctx context.Context, queue string, version string, since time.Time) ([]*MetricsSnapshot, error) {
+	snapshot, err := pmc.CollectSnapshot(ctx, queue, version, time.Since(since))
+	if err != nil {
+		return nil, err
+	}
+	return []*MetricsSnapshot{snapshot}, nil
+}
+
+func (pmc *PrometheusMetricsCollector) render(tmpl, queue, version string, step time.Duration) string {
+	interval := step
+	if interval < time.Minute {
+		interval = time.Minute
+	}
+	return strings.NewReplacer(
+		"{{queue}}", escapeLabelValue(queue),
+		"{{version}}", escapeLabelValue(version),
+		"{{interval}}", fmt.Sprintf("%ds", int64(interval.Seconds())),
+	).Replace(tmpl)
+}
+
+// promRangeResponse is the subset of the /api/v1/query_range response we use
+type promRangeResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Values [][2]interface{} `json:"values"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// queryAverage runs a range query and averages every finite sample. The
+// boolean is false when the query returned no usable samples.
+func (pmc *PrometheusMetricsCollector) queryAverage(ctx context.Context, query string, start, end time.Time, step time.Duration) (float64, bool, error) {
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("start", strconv.FormatInt(start.Unix(), 10))
+	params.Set("end", strconv.FormatInt(end.Unix(), 10))
+	params.Set("step", strconv.FormatInt(int64(step.Seconds()), 10))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pmc.apiURL+"/api/v1/query_range?"+params.Encode(), nil)
+	if err != nil {
+		return 0, false, err
+	}
+	resp, err := pmc.client.Do(req)
+	if err != nil {
+		return 0, false, err
+	}
+	defer resp.Body.Close()
+
+	var body promRangeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return 0, false, fmt.Errorf("decode response (HTTP %d): %w", resp.StatusCode, err)
+	}
+	if body.Status != "success" {
+		return 0, false, fmt.Errorf("query failed (HTTP %d): %s", resp.StatusCode, body.Error)
+	}
+
+	var sum float64
+	var n int
+	for _, series := range body.Data.Result {
+		for _, pair := range series.Values {
+			raw, ok := pair[1].(string)
+			if !ok {
+				continue
+			}
+			v, err := strconv.ParseFloat(raw, 64)
+			if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+				continue
+			}
+			sum += v
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, false, nil
+	}
+	return sum / float64(n), true, nil
+}
+
+// promStep splits the window into roughly 60 points, never below 15s
+func promStep(window time.Duration) time.Duration {
+	step := (window / 60).Truncate(time.Second)
+	if step < 15*time.Second {
+		step = 15 * time.Second
+	}
+	return step
+}
+
+func escapeLabelValue(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
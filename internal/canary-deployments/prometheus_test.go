@@ -0,0 +1,92 @@
+//go:build canary_deployments_tests
+// +build canary_deployments_tests
+
+package canary_deployments
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPromServer answers range queries with the values of the first rule whose
+// marker appears in the query, or an empty result when none match.
+func newPromServer(t *testing.T, rules [][2]string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v1/query_range", r.URL.Path)
+		q := r.URL.Query().Get("query")
+		values := ""
+		for _, rule := range rules {
+			if strings.Contains(q, rule[0]) {
+				values = rule[1]
+				break
+			}
+		}
+		result := "[]"
+		if values != "" {
+			result = `[{"metric":{},"values":` + values + `}]`
+		}
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":` + result + `}}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestPrometheusMetricsCollector_CollectSnapshot(t *testing.T) {
+	srv := newPromServer(t, [][2]string{
+		{"histogram_quantile", `[[1,"0.2"],[2,"0.4"]]`},
+		{"__name__", `[[1,"9"],[2,"11"]]`},
+		{"jobs_failed_total", `[[1,"1"],[2,"NaN"]]`},
+	})
+	collector := NewPrometheusMetricsCollector(srv.URL+"/", slog.Default())
+
+	snap, err := collector.CollectSnapshot(context.Background(), "jobs", "v2", 100*time.Second)
+	require.NoError(t, err)
+
+	assert.False(t, snap.Insufficient)
+	assert.InDelta(t, 10, snap.JobsPerSecond, 1e-9)
+	assert.Equal(t, int64(1000), snap.JobCount)
+	assert.Equal(t, int64(100), snap.ErrorCount)
+	assert.InDelta(t, 10, snap.ErrorRate, 1e-9)
+	assert.InDelta(t, 90, snap.SuccessRate, 1e-9)
+	assert.InDelta(t, 300, snap.P95Latency, 1e-9)
+}
+
+func TestPrometheusMetricsCollector_NoSamplesIsInsufficient(t *testing.T) {
+	srv := newPromServer(t, nil)
+	collector := NewPrometheusMetricsCollector(srv.URL, slog.Default())
+
+	snap, err := collector.CollectSnapshot(context.Background(), "jobs", "v2", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, snap.Insufficient)
+
+	stable := &MetricsSnapshot{JobCount: 1000, SuccessRate: 100}
+	conditions := SLOThresholds{MaxErrorRateIncrease: 100, MaxLatencyIncrease: 100, MaxThroughputDecrease: 100}
+	assert.Equal(t, DecisionInconclusive, (&Manager{}).evaluatePromotionConditions(stable, snap, conditions, 0))
+}
+
+func TestPrometheusMetricsCollector_QueryError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"parse error"}`))
+	}))
+	defer srv.Close()
+
+	_, err := NewPrometheusMetricsCollector(srv.URL, slog.Default()).CollectSnapshot(context.Background(), "jobs", "v2", time.Minute)
+	require.ErrorIs(t, err, ErrMetricsCollectionFailed)
+	assert.Contains(t, err.Error(), "parse error")
+}
+
+func TestPrometheusMetricsCollector_RenderEscapesLabels(t *testing.T) {
+	collector := NewPrometheusMetricsCollector("http://prom", slog.Default())
+	q := collector.render(`x{queue="{{queue}}",version="{{version}}"}[{{interval}}]`, `a"b`, "v1", 15*time.Second)
+	assert.Equal(t, `x{queue="a\"b",version="v1"}[60s]`, q)
+}
@@ -0,0 +1,152 @@
+package canary_deployments
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Ramp curves.
+const (
+	RampLinear      = "linear"
+	RampExponential = "exponential"
+)
+
+// RampConfig raises the canary percentage automatically from Start to
+// Target over Duration, one step per health check tick. Time only counts
+// while the canary is healthy, so a paused ramp resumes where it stopped.
+type RampConfig struct {
+	Start    int           `json:"start"`
+	Target   int           `json:"target"`
+	Duration time.Duration `json:"duration"`
+	// Curve is linear (default) or exponential, which spends longer at
+	// small percentages.
+	Curve string `json:"curve,omitempty"`
+}
+
+// Validate checks the ramp schedule.
+func (rc *RampConfig) Validate() error {
+	if rc.Start < 0 || rc.Start > 100 {
+		return fmt.Errorf("start must be between 0 and 100")
+	}
+	if rc.Target <= rc.Start || rc.Target > 100 {
+		return fmt.Errorf("target must be above start and at most 100")
+	}
+	if rc.Duration <= 0 {
+		return fmt.Errorf("duration must be positive")
+	}
+	switch rc.Curve {
+	case "", RampLinear, RampExponential:
+	default:
+		return fmt.Errorf("invalid curve: %s", rc.Curve)
+	}
+	return nil
+}
+
+// PercentAt returns the scheduled canary percentage after elapsed healthy
+// ramp time.
+func (rc *RampConfig) PercentAt(elapsed time.Duration) int {
+	if elapsed <= 0 {
+		return rc.Start
+	}
+	if elapsed >= rc.Duration {
+		return rc.Target
+	}
+	f := float64(elapsed) / float64(rc.Duration)
+	if rc.Curve == RampExponential {
+		// Exponential growth cannot start from zero; grow from 1% instead.
+		from := math.Max(float64(rc.Start), 1)
+		return int(math.Floor(from * math.Pow(float64(rc.Target)/from, f)))
+	}
+	return rc.Start + int(float64(rc.Target-rc.Start)*f)
+}
+
+// checkRamp advances deployment along its ramp. A failing canary is rolled
+// back. A regression that is not yet failing (an inconclusive error rate or
+// latency check, or a throughput drop) pauses the ramp; the duration and
+// sample size checks do not, since the canary only gains time and jobs by
+// ramping. Each step is capped at the manager's MaxCanaryPercentage and
+// recorded as a ramp_step event.
+func (m *Manager) checkRamp(deployment *CanaryDeployment) {
+	ramp := deployment.Config.Ramp
+	ctx, cancel := context.WithTimeout(m.ctx, 30*time.Second)
+	defer cancel()
+
+	stable, canary, err := m.GetDeploymentMetrics(ctx, deployment.ID)
+	if err != nil {
+		m.logger.Error("Failed to get metrics for ramp check",
+			"deployment_id", deployment.ID,
+			"error", err)
+		return
+	}
+	health := m.evaluateHealth(deployment, stable, canary)
+	m.recordHealth(deployment, health)
+	if health.OverallStatus == FailingCanary {
+		m.autoRollback(ctx, deployment, health.GetFailureReason(), health, stable, canary)
+		return
+	}
+	paused := !health.ErrorRateCheck.Passing || !health.LatencyCheck.Passing || !health.ThroughputCheck.Passing
+
+	now := time.Now()
+	elapsed := deployment.RampElapsed
+	if !paused && !deployment.RampCheckedAt.IsZero() {
+		elapsed += now.Sub(deployment.RampCheckedAt)
+	}
+
+	m.mu.Lock()
+	current, exists := m.deployments[deployment.ID]
+	if !exists || (current.Status != StatusActive && current.Status != StatusPromoting) {
+		m.mu.Unlock()
+		return
+	}
+	wasPaused := current.RampPaused
+	current.RampElapsed = elapsed
+	current.RampCheckedAt = now
+	current.RampPaused = paused
+	percent := current.CurrentPercent
+	m.mu.Unlock()
+
+	if paused {
+		if !wasPaused {
+			m.emitEvent(deployment, "ramp_paused",
+				fmt.Sprintf("Ramp paused at %d%%: %s", percent, health.GetFailureReason()))
+			if err := m.saveDeployment(ctx, current); err != nil {
+				m.logger.Error("Failed to save paused ramp",
+					"deployment_id", deployment.ID,
+					"error", err)
+			}
+		}
+		return
+	}
+	if wasPaused {
+		m.emitEvent(deployment, "ramp_resumed", fmt.Sprintf("Ramp resumed at %d%%", percent))
+	}
+
+	next := ramp.PercentAt(elapsed)
+	capped := next > m.config.MaxCanaryPercentage
+	if capped {
+		next = m.config.MaxCanaryPercentage
+	}
+	if next <= percent {
+		return
+	}
+	if err := m.UpdateDeploymentPercentage(ctx, deployment.ID, next); err != nil {
+		m.logger.Error("Failed to advance ramp",
+			"deployment_id", deployment.ID,
+			"target_percentage", next,
+			"error", err)
+		return
+	}
+	m.emitEventWithMetadata(deployment, "ramp_step",
+		fmt.Sprintf("Ramp advanced from %d%% to %d%%", percent, next),
+		map[string]interface{}{
+			"from_percent": percent,
+			"to_percent":   next,
+			"elapsed":      elapsed.String(),
+			"capped":       capped,
+		})
+	m.logger.Info("Ramped deployment",
+		"deployment_id", deployment.ID,
+		"percentage", next)
+}
@@ -0,0 +1,136 @@
+//go:build canary_deployments_tests
+// +build canary_deployments_tests
+
+package canary_deployments
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticCollector map[string]*MetricsSnapshot
+
+func (c staticCollector) CollectSnapshot(ctx context.Context, queue string, version string, window time.Duration) (*MetricsSnapshot, error) {
+	s := *c[version]
+	return &s, nil
+}
+
+func (c staticCollector) GetHistoricalMetrics(ctx context.Context, queue string, version string, since time.Time) ([]*MetricsSnapshot, error) {
+	return nil, nil
+}
+
+func TestRampConfig_PercentAt(t *testing.T) {
+	linear := &RampConfig{Start: 0, Target: 40, Duration: 40 * time.Minute}
+	assert.Equal(t, 0, linear.PercentAt(0))
+	assert.Equal(t, 10, linear.PercentAt(10*time.Minute))
+	assert.Equal(t, 40, linear.PercentAt(time.Hour))
+
+	exp := &RampConfig{Start: 1, Target: 64, Duration: 60 * time.Minute, Curve: RampExponential}
+	assert.Equal(t, 1, exp.PercentAt(0))
+	assert.Equal(t, 8, exp.PercentAt(30*time.Minute))
+	assert.Equal(t, 64, exp.PercentAt(60*time.Minute))
+
+	cfg := DefaultCanaryConfig()
+	cfg.Ramp = &RampConfig{Start: 10, Target: 5, Duration: time.Minute}
+	assert.Error(t, cfg.Validate())
+	cfg.Ramp = &RampConfig{Start: 5, Target: 50, Duration: time.Minute}
+	require.NoError(t, cfg.Validate())
+	cfg.ShadowMode = true
+	assert.Error(t, cfg.Validate(), "shadow deployments take no traffic to ramp")
+}
+
+func TestManager_RampAdvancesPausesAndRollsBack(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	config := &Config{MaxConcurrentDeployments: 5, MaxCanaryPercentage: 50, HealthCheckInterval: time.Second}
+	config.SetDefaults()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	manager := NewManager(config, rdb, logger)
+	metrics := staticCollector{
+		"v1": {ErrorRate: 1, P95Latency: 100, JobsPerSecond: 10},
+		"v2": {ErrorRate: 1, P95Latency: 100, JobsPerSecond: 10},
+	}
+	manager.collector = metrics
+
+	canaryConfig := DefaultCanaryConfig()
+	canaryConfig.Ramp = &RampConfig{Start: 10, Target: 90, Duration: 80 * time.Minute}
+	deployment, err := manager.CreateDeployment(context.Background(), canaryConfig)
+	require.NoError(t, err)
+	live := manager.deployments[deployment.ID]
+	live.QueueName = "orders"
+	live.StableVersion, live.CanaryVersion = "v1", "v2"
+	<-manager.eventChan // deployment_created
+
+	nextEvent := func() *DeploymentEvent {
+		t.Helper()
+		for {
+			select {
+			case ev := <-manager.eventChan:
+				if ev.Type != "percentage_updated" && ev.Type != "health_changed" {
+					return ev
+				}
+			default:
+				t.Fatal("expected a ramp event")
+				return nil
+			}
+		}
+	}
+	// tick pretends ago of ramp time passed since the last check
+	tick := func(ago time.Duration) {
+		manager.mu.Lock()
+		if !live.RampCheckedAt.IsZero() {
+			live.RampCheckedAt = live.RampCheckedAt.Add(-ago)
+		}
+		manager.mu.Unlock()
+		manager.checkActiveDeployments()
+	}
+
+	tick(0)
+	assert.Equal(t, 10, live.CurrentPercent, "first tick starts the ramp")
+	ev := nextEvent()
+	assert.Equal(t, "ramp_step", ev.Type)
+	assert.Equal(t, 0, ev.Metadata["from_percent"])
+	assert.Equal(t, 10, ev.Metadata["to_percent"])
+
+	tick(20 * time.Minute)
+	assert.Equal(t, 30, live.CurrentPercent)
+	assert.Equal(t, 30, nextEvent().Metadata["to_percent"])
+
+	// a throughput drop is a warning: the ramp holds and its clock stops
+	metrics["v2"].JobsPerSecond = 4
+	tick(time.Hour)
+	assert.Equal(t, 30, live.CurrentPercent)
+	assert.True(t, live.RampPaused)
+	assert.Equal(t, "ramp_paused", nextEvent().Type)
+
+	metrics["v2"].JobsPerSecond = 10
+	tick(10 * time.Minute)
+	assert.Equal(t, "ramp_resumed", nextEvent().Type)
+	assert.Equal(t, 40, live.CurrentPercent, "paused time does not count")
+	assert.Equal(t, 40, nextEvent().Metadata["to_percent"])
+
+	// the schedule would pass 50%, the manager maximum
+	tick(time.Hour)
+	assert.Equal(t, 50, live.CurrentPercent)
+	ev = nextEvent()
+	assert.Equal(t, 50, ev.Metadata["to_percent"])
+	assert.Equal(t, true, ev.Metadata["capped"])
+
+	// a significant error rate regression fails the canary
+	metrics["v1"].JobCount, metrics["v2"].JobCount = 5000, 5000
+	metrics["v1"].ErrorCount, metrics["v2"].ErrorCount = 50, 1000
+	metrics["v2"].ErrorRate = 20
+	tick(time.Minute)
+	assert.Equal(t, StatusFailed, live.Status)
+	assert.Equal(t, 0, live.CurrentPercent)
+}
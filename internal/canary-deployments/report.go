@@ -0,0 +1,297 @@
+package canary_deployments
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Report formats accepted by GenerateReport
+const (
+	ReportFormatJSON     = "json"
+	ReportFormatMarkdown = "markdown"
+)
+
+// ReportOutcome is how a canary deployment ended
+type ReportOutcome string
+
+const (
+	OutcomePromoted   ReportOutcome = "promoted"
+	OutcomeRolledBack ReportOutcome = "rolled_back"
+	OutcomeInProgress ReportOutcome = "in_progress"
+)
+
+// DeploymentReport compares the canary of a deployment with stable over its
+// whole lifetime.
+type DeploymentReport struct {
+	DeploymentID  string           `json:"deployment_id"`
+	QueueName     string           `json:"queue_name"`
+	TenantID      string           `json:"tenant_id,omitempty"`
+	StableVersion string           `json:"stable_version"`
+	CanaryVersion string           `json:"canary_version"`
+	Status        DeploymentStatus `json:"status"`
+	Outcome       ReportOutcome    `json:"outcome"`
+	Reason        string           `json:"reason,omitempty"`
+	StartedAt     time.Time        `json:"started_at"`
+	CompletedAt   *time.Time       `json:"completed_at,omitempty"`
+	Duration      time.Duration    `json:"duration"`
+	FinalPercent  int              `json:"final_percent"`
+
+	// Stable and Canary are the latest metrics: the current window of an
+	// active deployment, otherwise those of the last recorded stage.
+	Stable       *MetricsSnapshot `json:"stable_metrics,omitempty"`
+	Canary       *MetricsSnapshot `json:"canary_metrics,omitempty"`
+	MetricDeltas *MetricDeltas    `json:"metric_deltas,omitempty"`
+
+	Stages        []StageReport      `json:"stages"`
+	HealthHistory []HealthRecord     `json:"health_history"`
+	Events        []*DeploymentEvent `json:"events"` // oldest first
+	GeneratedAt   time.Time          `json:"generated_at"`
+}
+
+// StageReport is one traffic percentage a deployment held, with the metrics
+// of the window that ended when it moved on.
+type StageReport struct {
+	Percent      int              `json:"percent"`
+	StartedAt    time.Time        `json:"started_at"`
+	EndedAt      time.Time        `json:"ended_at"`
+	Stable       *MetricsSnapshot `json:"stable_metrics,omitempty"`
+	Canary       *MetricsSnapshot `json:"canary_metrics,omitempty"`
+	MetricDeltas *MetricDeltas    `json:"metric_deltas,omitempty"`
+}
+
+// HealthRecord is a change of the overall canary health.
+type HealthRecord struct {
+	Timestamp time.Time           `json:"timestamp"`
+	Status    CanaryHealth        `json:"status"`
+	Reason    string              `json:"reason"`
+	Checks    *CanaryHealthStatus `json:"checks,omitempty"`
+}
+
+// GenerateReport builds a stable vs canary comparison of a deployment from
+// its events and metrics, as JSON ("json" or empty) or Markdown
+// ("markdown"). Stages come from the metrics recorded on each
+// percentage_updated and deployment_rolled_back event, and the health
+// history from health_changed events.
+func (m *Manager) GenerateReport(ctx context.Context, id string, format string) ([]byte, error) {
+	if format != "" && format != ReportFormatJSON && format != ReportFormatMarkdown {
+		return nil, NewValidationError("format", fmt.Sprintf("unsupported report format %q (use json or markdown)", format))
+	}
+
+	report, err := m.buildReport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == ReportFormatMarkdown {
+		return []byte(renderMarkdownReport(report)), nil
+	}
+	return json.MarshalIndent(report, "", "  ")
+}
+
+func (m *Manager) buildReport(ctx context.Context, id string) (*DeploymentReport, error) {
+	deployment, err := m.GetDeployment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	events, err := m.loadEventsFromRedis(ctx, id, -1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load events: %w", err)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+
+	now := time.Now()
+	report := &DeploymentReport{
+		DeploymentID:  deployment.ID,
+		QueueName:     deployment.QueueName,
+		TenantID:      deployment.TenantID,
+		StableVersion: deployment.StableVersion,
+		CanaryVersion: deployment.CanaryVersion,
+		Status:        deployment.Status,
+		Outcome:       OutcomeInProgress,
+		StartedAt:     deployment.StartTime,
+		CompletedAt:   deployment.CompletedAt,
+		FinalPercent:  deployment.CurrentPercent,
+		Stages:        []StageReport{},
+		HealthHistory: []HealthRecord{},
+		Events:        events,
+		GeneratedAt:   now,
+	}
+	end := now
+	if deployment.CompletedAt != nil {
+		end = *deployment.CompletedAt
+	}
+	report.Duration = end.Sub(deployment.StartTime)
+
+	stageStart := deployment.StartTime
+	for _, event := range events {
+		switch event.Type {
+		case "percentage_updated", "deployment_rolled_back":
+			var from int
+			if !decodeMetadata(event.Metadata, "from_percent", &from) {
+				continue
+			}
+			stage := StageReport{Percent: from, StartedAt: stageStart, EndedAt: event.Timestamp}
+			decodeMetadata(event.Metadata, "stable_metrics", &stage.Stable)
+			decodeMetadata(event.Metadata, "canary_metrics", &stage.Canary)
+			stage.MetricDeltas = metricDeltas(stage.Stable, stage.Canary)
+			report.Stages = append(report.Stages, stage)
+			stageStart = event.Timestamp
+
+			if event.Type == "deployment_rolled_back" {
+				report.Outcome = OutcomeRolledBack
+				if !decodeMetadata(event.Metadata, "reason", &report.Reason) {
+					report.Reason = event.Message
+				}
+				report.FinalPercent = from
+			}
+		case "deployment_promoted":
+			report.Outcome = OutcomePromoted
+			report.Reason = event.Message
+		case "health_changed":
+			record := HealthRecord{Timestamp: event.Timestamp}
+			if decodeMetadata(event.Metadata, "health", &record.Checks) && record.Checks != nil {
+				record.Status = record.Checks.OverallStatus
+				record.Reason = record.Checks.GetFailureReason()
+			} else {
+				record.Reason = event.Message
+			}
+			report.HealthHistory = append(report.HealthHistory, record)
+		}
+	}
+
+	// The status is authoritative when the closing event is missing, e.g.
+	// dropped from a full event channel.
+	switch deployment.Status {
+	case StatusCompleted:
+		report.Outcome = OutcomePromoted
+	case StatusFailed:
+		report.Outcome = OutcomeRolledBack
+	case StatusActive, StatusPromoting:
+		report.Outcome = OutcomeInProgress
+		stage := StageReport{Percent: deployment.CurrentPercent, StartedAt: stageStart, EndedAt: now}
+		if stable, canary, err := m.GetDeploymentMetrics(ctx, id); err == nil {
+			stage.Stable, stage.Canary = stable, canary
+			stage.MetricDeltas = metricDeltas(stable, canary)
+		} else {
+			m.logger.Warn("Failed to collect current metrics for report",
+				"deployment_id", id,
+				"error", err)
+		}
+		report.Stages = append(report.Stages, stage)
+	}
+
+	for i := len(report.Stages) - 1; i >= 0; i-- {
+		if stage := report.Stages[i]; stage.Stable != nil && stage.Canary != nil {
+			report.Stable, report.Canary, report.MetricDeltas = stage.Stable, stage.Canary, stage.MetricDeltas
+			break
+		}
+	}
+
+	return report, nil
+}
+
+// decodeMetadata decodes metadata[key] into out. Event metadata holds typed
+// values until the event is saved and generic JSON values once loaded from
+// Redis, so it round-trips through JSON either way.
+func decodeMetadata(metadata map[string]interface{}, key string, out interface{}) bool {
+	value, ok := metadata[key]
+	if !ok {
+		return false
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, out) == nil
+}
+
+// renderMarkdownReport formats report for pasting into a PR or incident doc
+func renderMarkdownReport(report *DeploymentReport) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Canary report: %s %s → %s\n\n", report.QueueName, report.StableVersion, report.CanaryVersion)
+	b.WriteString("| | |\n|---|---|\n")
+	fmt.Fprintf(&b, "| Deployment | `%s` |\n", report.DeploymentID)
+	if report.TenantID != "" {
+		fmt.Fprintf(&b, "| Tenant | %s |\n", mdCell(report.TenantID))
+	}
+	fmt.Fprintf(&b, "| Status | %s |\n", report.Status)
+	fmt.Fprintf(&b, "| Outcome | **%s** |\n", report.Outcome)
+	if report.Reason != "" {
+		fmt.Fprintf(&b, "| Reason | %s |\n", mdCell(report.Reason))
+	}
+	fmt.Fprintf(&b, "| Final percentage | %d%% |\n", report.FinalPercent)
+	fmt.Fprintf(&b, "| Started | %s |\n", report.StartedAt.UTC().Format(time.RFC3339))
+	if report.CompletedAt != nil {
+		fmt.Fprintf(&b, "| Completed | %s |\n", report.CompletedAt.UTC().Format(time.RFC3339))
+	}
+	fmt.Fprintf(&b, "| Duration | %s |\n", report.Duration.Truncate(time.Second))
+
+	b.WriteString("\n## Stable vs canary\n\n")
+	if report.Stable == nil || report.Canary == nil {
+		b.WriteString("No metrics were recorded.\n")
+	} else {
+		writeMarkdownComparison(&b, report.Stable, report.Canary, report.MetricDeltas)
+	}
+
+	b.WriteString("\n## Stages\n\n")
+	if len(report.Stages) == 0 {
+		b.WriteString("No stages were recorded.\n")
+	} else {
+		b.WriteString("| Canary % | Started | Duration | Jobs (stable / canary) | Error rate (stable / canary) | P95 ms (stable / canary) | Jobs/s (stable / canary) |\n")
+		b.WriteString("|---|---|---|---|---|---|---|\n")
+		for _, stage := range report.Stages {
+			fmt.Fprintf(&b, "| %d%% | %s | %s |", stage.Percent,
+				stage.StartedAt.UTC().Format(time.RFC3339), stage.EndedAt.Sub(stage.StartedAt).Truncate(time.Second))
+			if stage.Stable == nil || stage.Canary == nil {
+				b.WriteString(" - | - | - | - |\n")
+				continue
+			}
+			fmt.Fprintf(&b, " %d / %d | %.2f%% / %.2f%% | %.1f / %.1f | %.2f / %.2f |\n",
+				stage.Stable.JobCount, stage.Canary.JobCount,
+				stage.Stable.ErrorRate, stage.Canary.ErrorRate,
+				stage.Stable.P95Latency, stage.Canary.P95Latency,
+				stage.Stable.JobsPerSecond, stage.Canary.JobsPerSecond)
+		}
+	}
+
+	b.WriteString("\n## Health history\n\n")
+	if len(report.HealthHistory) == 0 {
+		b.WriteString("No health changes were recorded.\n")
+	} else {
+		b.WriteString("| Time | Health | Reason |\n|---|---|---|\n")
+		for _, record := range report.HealthHistory {
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", record.Timestamp.UTC().Format(time.RFC3339), record.Status, mdCell(record.Reason))
+		}
+	}
+
+	b.WriteString("\n## Events\n\n")
+	for _, event := range report.Events {
+		fmt.Fprintf(&b, "- %s `%s` %s\n", event.Timestamp.UTC().Format(time.RFC3339), event.Type, event.Message)
+	}
+
+	return b.String()
+}
+
+func writeMarkdownComparison(b *strings.Builder, stable, canary *MetricsSnapshot, deltas *MetricDeltas) {
+	b.WriteString("| Metric | Stable | Canary | Delta |\n|---|---|---|---|\n")
+	fmt.Fprintf(b, "| Jobs | %d | %d | |\n", stable.JobCount, canary.JobCount)
+	fmt.Fprintf(b, "| Error rate | %.2f%% | %.2f%% | %+.2f pp |\n", stable.ErrorRate, canary.ErrorRate, deltas.ErrorRateIncrease)
+	fmt.Fprintf(b, "| Success rate | %.2f%% | %.2f%% | |\n", stable.SuccessRate, canary.SuccessRate)
+	fmt.Fprintf(b, "| P50 latency | %.1f ms | %.1f ms | |\n", stable.P50Latency, canary.P50Latency)
+	fmt.Fprintf(b, "| P95 latency | %.1f ms | %.1f ms | %+.1f%% |\n", stable.P95Latency, canary.P95Latency, deltas.LatencyIncrease)
+	fmt.Fprintf(b, "| P99 latency | %.1f ms | %.1f ms | |\n", stable.P99Latency, canary.P99Latency)
+	fmt.Fprintf(b, "| Throughput | %.2f jobs/s | %.2f jobs/s | %+.1f%% |\n", stable.JobsPerSecond, canary.JobsPerSecond, 0-deltas.ThroughputDecrease)
+	fmt.Fprintf(b, "| Dead letters | %d | %d | |\n", stable.DeadLetters, canary.DeadLetters)
+}
+
+// mdCell keeps text from breaking a Markdown table row
+func mdCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
@@ -0,0 +1,111 @@
+//go:build canary_deployments_tests
+// +build canary_deployments_tests
+
+package canary_deployments
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_GenerateReport(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	config := &Config{MaxConcurrentDeployments: 5, MaxCanaryPercentage: 50, HealthCheckInterval: time.Second}
+	config.SetDefaults()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	manager := NewManager(config, rdb, logger)
+	metrics := staticCollector{
+		"v1": {JobCount: 100, ErrorRate: 1, P95Latency: 100, JobsPerSecond: 10},
+		"v2": {JobCount: 10, ErrorRate: 1, P95Latency: 110, JobsPerSecond: 10},
+	}
+	manager.collector = metrics
+
+	ctx := context.Background()
+	deployment, err := manager.CreateDeployment(ctx, DefaultCanaryConfig())
+	require.NoError(t, err)
+	live := manager.deployments[deployment.ID]
+	live.QueueName = "orders"
+	live.StableVersion, live.CanaryVersion = "v1", "v2"
+
+	// flush saves the emitted events as the event processor would
+	flush := func() {
+		for {
+			select {
+			case ev := <-manager.eventChan:
+				require.NoError(t, manager.saveEventToRedis(ctx, ev))
+			default:
+				return
+			}
+		}
+	}
+
+	require.NoError(t, manager.UpdateDeploymentPercentage(ctx, deployment.ID, 10))
+	metrics["v2"].JobCount = 40
+	require.NoError(t, manager.UpdateDeploymentPercentage(ctx, deployment.ID, 30))
+	flush()
+
+	var report DeploymentReport
+	data, err := manager.GenerateReport(ctx, deployment.ID, ReportFormatJSON)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &report))
+	assert.Equal(t, OutcomeInProgress, report.Outcome)
+	require.Len(t, report.Stages, 3)
+	assert.Equal(t, 30, report.Stages[2].Percent, "the current stage is reported live")
+
+	// a significant error rate regression fails the canary
+	metrics["v1"].JobCount, metrics["v2"].JobCount = 5000, 5000
+	metrics["v1"].ErrorCount, metrics["v2"].ErrorCount = 50, 1000
+	metrics["v2"].ErrorRate = 20
+	manager.checkDeploymentHealth(manager.copyDeployment(live))
+	require.Equal(t, StatusFailed, live.Status)
+	flush()
+
+	data, err = manager.GenerateReport(ctx, deployment.ID, "")
+	require.NoError(t, err)
+	report = DeploymentReport{}
+	require.NoError(t, json.Unmarshal(data, &report))
+	assert.Equal(t, OutcomeRolledBack, report.Outcome)
+	assert.Contains(t, report.Reason, "Error rate increase: 19.00%")
+	assert.Equal(t, 30, report.FinalPercent)
+
+	require.Len(t, report.Stages, 3)
+	for i, percent := range []int{0, 10, 30} {
+		assert.Equal(t, percent, report.Stages[i].Percent)
+		require.NotNil(t, report.Stages[i].Canary)
+	}
+	assert.Equal(t, int64(10), report.Stages[0].Canary.JobCount)
+	assert.Equal(t, int64(40), report.Stages[1].Canary.JobCount)
+	assert.Equal(t, int64(5000), report.Stages[2].Canary.JobCount)
+	require.NotNil(t, report.MetricDeltas)
+	assert.InDelta(t, 19.0, report.MetricDeltas.ErrorRateIncrease, 0.001)
+	assert.InDelta(t, 10.0, report.MetricDeltas.LatencyIncrease, 0.001)
+
+	require.Len(t, report.HealthHistory, 1)
+	assert.Equal(t, FailingCanary, report.HealthHistory[0].Status)
+	assert.NotEmpty(t, report.Events)
+
+	md, err := manager.GenerateReport(ctx, deployment.ID, ReportFormatMarkdown)
+	require.NoError(t, err)
+	assert.Contains(t, string(md), "# Canary report: orders v1 → v2")
+	assert.Contains(t, string(md), "| Outcome | **rolled_back** |")
+	assert.Contains(t, string(md), "| Error rate | 1.00% | 20.00% | +19.00 pp |")
+	assert.Contains(t, string(md), "| 30% |")
+	assert.Contains(t, string(md), "`deployment_rolled_back`")
+
+	_, err = manager.GenerateReport(ctx, deployment.ID, "pdf")
+	assert.Equal(t, CodeValidationFailed, GetCanaryError(err).Code)
+	_, err = manager.GenerateReport(ctx, "missing", ReportFormatJSON)
+	assert.Equal(t, CodeDeploymentNotFound, GetCanaryError(err).Code)
+}
@@ -0,0 +1,274 @@
+package canary_deployments
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	eventhooks "github.com/flyingrobots/go-redis-work-queue/internal/event-hooks"
+	"github.com/google/uuid"
+)
+
+// RollbackWebhookEvent is the event name of rollback webhook payloads and
+// of their dead letter hooks.
+const RollbackWebhookEvent = "canary_rollback"
+
+// RollbackWebhookSubscription is the subscription ID rollback webhook
+// deliveries are dead-lettered under in the event hooks DLH.
+const RollbackWebhookSubscription = "canary_rollback_webhook"
+
+// defaultRollbackWebhookTimeout bounds one delivery attempt.
+const defaultRollbackWebhookTimeout = 10 * time.Second
+
+// RollbackWebhookConfig configures the webhook fired when a canary is rolled
+// back automatically, by a failing health check or a timeout.
+type RollbackWebhookConfig struct {
+	URL string `json:"url"`
+	// Secret signs each payload with HMAC-SHA256 in the X-Webhook-Signature
+	// header, as event hook subscriptions do; empty sends it unsigned.
+	Secret  string        `json:"secret,omitempty"`
+	Timeout time.Duration `json:"timeout,omitempty"` // per attempt; default 10s
+	// RetryPolicy defaults to the event hooks' exponential backoff.
+	RetryPolicy *eventhooks.RetryPolicy `json:"retry_policy,omitempty"`
+}
+
+// Validate checks the rollback webhook settings.
+func (rw *RollbackWebhookConfig) Validate() error {
+	u, err := url.Parse(rw.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url must be an http or https URL, got %q", rw.URL)
+	}
+	if rw.Timeout < 0 {
+		return fmt.Errorf("timeout must not be negative")
+	}
+	if rw.RetryPolicy != nil && rw.RetryPolicy.MaxRetries < 0 {
+		return fmt.Errorf("retry_policy.max_retries must not be negative")
+	}
+	return nil
+}
+
+// RollbackWebhookPayload is the JSON body of a rollback webhook.
+type RollbackWebhookPayload struct {
+	Event         string `json:"event"` // always canary_rollback
+	DeploymentID  string `json:"deployment_id"`
+	QueueName     string `json:"queue_name"`
+	TenantID      string `json:"tenant_id,omitempty"`
+	StableVersion string `json:"stable_version"`
+	CanaryVersion string `json:"canary_version"`
+	Reason        string `json:"reason"`
+	// Stage is the index of the last promotion stage the canary reached,
+	// -1 when it had not reached the first; CanaryPercent is the traffic
+	// share it had when rolled back.
+	Stage         int           `json:"stage"`
+	CanaryPercent int           `json:"canary_percent"`
+	FailingChecks []HealthCheck `json:"failing_checks"`
+	MetricDeltas  *MetricDeltas `json:"metric_deltas,omitempty"`
+	StartedAt     time.Time     `json:"started_at"`
+	RolledBackAt  time.Time     `json:"rolled_back_at"`
+}
+
+// MetricDeltas compares the canary with stable over the metrics window.
+type MetricDeltas struct {
+	ErrorRateIncrease  float64 `json:"error_rate_increase"`  // percentage points
+	LatencyIncrease    float64 `json:"p95_latency_increase"` // percent
+	ThroughputDecrease float64 `json:"throughput_decrease"`  // percent
+	StableJobs         int64   `json:"stable_jobs"`
+	CanaryJobs         int64   `json:"canary_jobs"`
+}
+
+// metricDeltas returns how far canary is from stable, or nil without both.
+func metricDeltas(stable, canary *MetricsSnapshot) *MetricDeltas {
+	if stable == nil || canary == nil {
+		return nil
+	}
+	d := &MetricDeltas{
+		ErrorRateIncrease: canary.ErrorRate - stable.ErrorRate,
+		StableJobs:        stable.JobCount,
+		CanaryJobs:        canary.JobCount,
+	}
+	if stable.P95Latency > 0 {
+		d.LatencyIncrease = (canary.P95Latency - stable.P95Latency) / stable.P95Latency * 100
+	}
+	if stable.JobsPerSecond > 0 {
+		d.ThroughputDecrease = (stable.JobsPerSecond - canary.JobsPerSecond) / stable.JobsPerSecond * 100
+	}
+	return d
+}
+
+// newRollbackWebhookPayload describes the automatic rollback of deployment,
+// as it was before the rollback. health may be nil, e.g. on a timeout.
+func newRollbackWebhookPayload(deployment *CanaryDeployment, reason string, health *CanaryHealthStatus, stable, canary *MetricsSnapshot) *RollbackWebhookPayload {
+	payload := &RollbackWebhookPayload{
+		Event:         RollbackWebhookEvent,
+		DeploymentID:  deployment.ID,
+		QueueName:     deployment.QueueName,
+		TenantID:      deployment.TenantID,
+		StableVersion: deployment.StableVersion,
+		CanaryVersion: deployment.CanaryVersion,
+		Reason:        reason,
+		Stage:         -1,
+		CanaryPercent: deployment.CurrentPercent,
+		FailingChecks: []HealthCheck{},
+		MetricDeltas:  metricDeltas(stable, canary),
+		StartedAt:     deployment.StartTime,
+		RolledBackAt:  time.Now(),
+	}
+	for i, stage := range deployment.Config.PromotionStages {
+		if stage.Percentage <= deployment.CurrentPercent {
+			payload.Stage = i
+		}
+	}
+	if health != nil {
+		for _, check := range []HealthCheck{health.ErrorRateCheck, health.LatencyCheck, health.ThroughputCheck, health.DurationCheck, health.SampleSizeCheck} {
+			if !check.Passing && !check.Inconclusive {
+				payload.FailingChecks = append(payload.FailingChecks, check)
+			}
+		}
+	}
+	return payload
+}
+
+// autoRollback rolls deployment back on the manager's own decision and
+// fires its rollback webhook, if one is configured.
+func (m *Manager) autoRollback(ctx context.Context, deployment *CanaryDeployment, reason string, health *CanaryHealthStatus, stable, canary *MetricsSnapshot) {
+	if err := m.RollbackDeployment(ctx, deployment.ID, reason); err != nil {
+		m.logger.Error("Failed to rollback deployment",
+			"deployment_id", deployment.ID,
+			"reason", reason,
+			"error", err)
+		return
+	}
+
+	hook := deployment.Config.RollbackWebhook
+	if hook == nil || hook.URL == "" {
+		return
+	}
+	payload := newRollbackWebhookPayload(deployment, reason, health, stable, canary)
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		if err := m.deliverRollbackWebhook(m.ctx, hook, payload); err != nil {
+			m.logger.Error("Rollback webhook not delivered",
+				"deployment_id", payload.DeploymentID,
+				"error", err)
+		}
+	}()
+}
+
+// deliverRollbackWebhook POSTs payload to hook.URL, retrying with backoff
+// per hook.RetryPolicy. When every attempt fails the delivery is stored as
+// a dead letter hook under RollbackWebhookSubscription, where the event
+// hooks DLH endpoints list it.
+func (m *Manager) deliverRollbackWebhook(ctx context.Context, hook *RollbackWebhookConfig, payload *RollbackWebhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal rollback webhook payload: %w", err)
+	}
+	policy := eventhooks.DefaultRetryPolicy()
+	if hook.RetryPolicy != nil {
+		policy = *hook.RetryPolicy
+	}
+	timeout := hook.Timeout
+	if timeout == 0 {
+		timeout = defaultRollbackWebhookTimeout
+	}
+
+	event := eventhooks.JobEvent{
+		Event:     eventhooks.EventType(RollbackWebhookEvent),
+		Timestamp: payload.RolledBackAt,
+		Queue:     payload.QueueName,
+		Payload:   payload,
+	}
+	deliveryID := uuid.New().String()
+	var attempts []eventhooks.DeliveryAttempt
+	for n := 1; ; n++ {
+		attempt := m.postRollbackWebhook(ctx, hook, body, deliveryID, timeout)
+		attempt.ID = uuid.New().String()
+		attempt.SubscriptionID = RollbackWebhookSubscription
+		attempt.Event = event
+		attempt.AttemptNumber = n
+		attempts = append(attempts, attempt)
+		if attempt.Success {
+			m.logger.Info("Rollback webhook delivered",
+				"deployment_id", payload.DeploymentID,
+				"attempts", n)
+			return nil
+		}
+		if n > policy.MaxRetries {
+			break
+		}
+
+		delay := eventhooks.RetryDelay(policy, n)
+		m.logger.Warn("Rollback webhook failed, retrying",
+			"deployment_id", payload.DeploymentID,
+			"attempt", n,
+			"delay", delay,
+			"error", attempt.ErrorMessage)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+
+	finalError := attempts[len(attempts)-1].ErrorMessage
+	dlh := &eventhooks.DeadLetterHook{
+		ID:             uuid.New().String(),
+		SubscriptionID: RollbackWebhookSubscription,
+		Event:          event,
+		Attempts:       attempts,
+		FinalError:     finalError,
+		CreatedAt:      time.Now(),
+	}
+	if err := eventhooks.StoreDeadLetterHook(ctx, m.redis, dlh); err != nil {
+		return fmt.Errorf("%s after %d attempts; dead-lettering failed: %w", finalError, len(attempts), err)
+	}
+	return fmt.Errorf("%s after %d attempts; dead-lettered as %s", finalError, len(attempts), dlh.ID)
+}
+
+// postRollbackWebhook makes one delivery attempt and records its outcome.
+func (m *Manager) postRollbackWebhook(ctx context.Context, hook *RollbackWebhookConfig, body []byte, deliveryID string, timeout time.Duration) eventhooks.DeliveryAttempt {
+	now := time.Now()
+	attempt := eventhooks.DeliveryAttempt{
+		ScheduledAt: now,
+		AttemptedAt: &now,
+		DeliveryID:  deliveryID,
+		RequestURL:  hook.URL,
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "POST", hook.URL, bytes.NewReader(body))
+	if err != nil {
+		attempt.ErrorMessage = err.Error()
+		return attempt
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "canary-deployment-rollback/1.0")
+	req.Header.Set("X-Webhook-Event", RollbackWebhookEvent)
+	req.Header.Set("X-Webhook-Delivery", deliveryID)
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(now.Unix(), 10))
+	if hook.Secret != "" {
+		req.Header.Set("X-Webhook-Signature", eventhooks.SignPayload(body, hook.Secret))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	attempt.ResponseTime = time.Since(now)
+	if err != nil {
+		attempt.ErrorMessage = err.Error()
+		return attempt
+	}
+	resp.Body.Close()
+	attempt.StatusCode = resp.StatusCode
+	if resp.StatusCode >= 300 {
+		attempt.ErrorMessage = fmt.Sprintf("webhook returned status %d", resp.StatusCode)
+		return attempt
+	}
+	attempt.Success = true
+	return attempt
+}
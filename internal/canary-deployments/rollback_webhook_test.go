@@ -0,0 +1,109 @@
+//go:build canary_deployments_tests
+// +build canary_deployments_tests
+
+package canary_deployments
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	eventhooks "github.com/flyingrobots/go-redis-work-queue/internal/event-hooks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollbackWebhookPayloadDescribesFailure(t *testing.T) {
+	cfg := DefaultCanaryConfig()
+	cfg.PromotionStages = []PromotionStage{{Percentage: 5}, {Percentage: 25}, {Percentage: 50}}
+	deployment := &CanaryDeployment{ID: "d1", QueueName: "orders", CurrentPercent: 25, Config: cfg}
+	health := &CanaryHealthStatus{
+		ErrorRateCheck:  HealthCheck{Name: "Error Rate", Message: "too many errors"},
+		LatencyCheck:    HealthCheck{Name: "P95 Latency", Inconclusive: true},
+		ThroughputCheck: HealthCheck{Name: "Throughput", Passing: true},
+		DurationCheck:   HealthCheck{Name: "Duration", Passing: true},
+		SampleSizeCheck: HealthCheck{Name: "Sample Size", Passing: true},
+	}
+	stable := &MetricsSnapshot{ErrorRate: 1, P95Latency: 100, JobsPerSecond: 10, JobCount: 500}
+	canary := &MetricsSnapshot{ErrorRate: 6, P95Latency: 150, JobsPerSecond: 8, JobCount: 120}
+
+	p := newRollbackWebhookPayload(deployment, "too many errors", health, stable, canary)
+	assert.Equal(t, RollbackWebhookEvent, p.Event)
+	assert.Equal(t, 1, p.Stage)
+	assert.Equal(t, 25, p.CanaryPercent)
+	require.Len(t, p.FailingChecks, 1, "inconclusive checks did not cause the rollback")
+	assert.Equal(t, "Error Rate", p.FailingChecks[0].Name)
+	require.NotNil(t, p.MetricDeltas)
+	assert.InDelta(t, 5, p.MetricDeltas.ErrorRateIncrease, 1e-9)
+	assert.InDelta(t, 50, p.MetricDeltas.LatencyIncrease, 1e-9)
+	assert.InDelta(t, 20, p.MetricDeltas.ThroughputDecrease, 1e-9)
+	assert.Equal(t, int64(120), p.MetricDeltas.CanaryJobs)
+}
+
+func TestDeliverRollbackWebhookSignsAndRetries(t *testing.T) {
+	manager, _, _ := setupShadowManager(t)
+	var calls int32
+	var got RollbackWebhookPayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, eventhooks.SignPayload(body, "s3cret"), r.Header.Get("X-Webhook-Signature"))
+		assert.Equal(t, RollbackWebhookEvent, r.Header.Get("X-Webhook-Event"))
+		assert.NoError(t, json.Unmarshal(body, &got))
+	}))
+	defer srv.Close()
+
+	hook := &RollbackWebhookConfig{
+		URL:         srv.URL,
+		Secret:      "s3cret",
+		RetryPolicy: &eventhooks.RetryPolicy{Strategy: "fixed", InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, MaxRetries: 2},
+	}
+	payload := &RollbackWebhookPayload{Event: RollbackWebhookEvent, DeploymentID: "d1", Reason: "deployment timeout"}
+	require.NoError(t, manager.deliverRollbackWebhook(context.Background(), hook, payload))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	assert.Equal(t, "d1", got.DeploymentID)
+}
+
+func TestDeliverRollbackWebhookDeadLetters(t *testing.T) {
+	manager, rdb, _ := setupShadowManager(t)
+	ctx := context.Background()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	hook := &RollbackWebhookConfig{
+		URL:         srv.URL,
+		RetryPolicy: &eventhooks.RetryPolicy{Strategy: "fixed", InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, MaxRetries: 1},
+	}
+	payload := &RollbackWebhookPayload{Event: RollbackWebhookEvent, DeploymentID: "d1", QueueName: "orders"}
+	require.Error(t, manager.deliverRollbackWebhook(ctx, hook, payload))
+
+	ids, err := rdb.LRange(ctx, "event_hooks:dlh_index:"+RollbackWebhookSubscription, 0, -1).Result()
+	require.NoError(t, err)
+	require.Len(t, ids, 1)
+	data, err := rdb.Get(ctx, "event_hooks:dlh:"+ids[0]).Result()
+	require.NoError(t, err)
+	var dlh eventhooks.DeadLetterHook
+	require.NoError(t, json.Unmarshal([]byte(data), &dlh))
+	assert.Len(t, dlh.Attempts, 2)
+	assert.Equal(t, 500, dlh.Attempts[1].StatusCode)
+	assert.Equal(t, eventhooks.EventType(RollbackWebhookEvent), dlh.Event.Event)
+	assert.Equal(t, "orders", dlh.Event.Queue)
+}
+
+func TestRollbackWebhookConfigValidate(t *testing.T) {
+	cfg := DefaultCanaryConfig()
+	cfg.RollbackWebhook = &RollbackWebhookConfig{URL: "ftp://example.com"}
+	assert.Error(t, cfg.Validate())
+	cfg.RollbackWebhook.URL = "https://hooks.example.com/canary"
+	assert.NoError(t, cfg.Validate())
+}
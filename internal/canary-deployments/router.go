@@ -125,6 +125,20 @@ func (r *RedisRouter) GetRoutingStats(ctx context.Context, queue string) (map[st
 	}
 	stats["canary_routed"] = canaryCount
 
+	shadowKey := fmt.Sprintf("canary:stats:%s:shadow", queue)
+	shadowCount, err := r.redis.Get(ctx, shadowKey).Int64()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to get shadow mirror count: %w", err)
+	}
+	stats["shadow_mirrored"] = shadowCount
+
+	cappedKey := fmt.Sprintf("canary:stats:%s:cost_capped", queue)
+	cappedCount, err := r.redis.Get(ctx, cappedKey).Int64()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to get cost capped count: %w", err)
+	}
+	stats["cost_capped"] = cappedCount
+
 	// Calculate current percentage
 	total := stableCount + canaryCount
 	if total > 0 {
@@ -0,0 +1,114 @@
+package canary_deployments
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Metadata keys set on mirrored copies of jobs in shadow mode
+const (
+	// MetadataShadow is "true" on a mirrored copy of a job
+	MetadataShadow = "canary_shadow"
+	// MetadataShadowOf holds the ID of the job the copy mirrors
+	MetadataShadowOf = "canary_shadow_of"
+	// MetadataDryRun is "true" when a handler must not write external state
+	// (send mail, charge cards, call webhooks). Shadow copies always set it
+	// because the stable lane already performs those effects.
+	MetadataDryRun = "dry_run"
+)
+
+// IsShadowJob reports whether job is a mirrored copy whose result is only
+// used for comparison. Workers should check it, or MetadataDryRun, before
+// producing side effects.
+func IsShadowJob(job *Job) bool {
+	return job != nil && job.Metadata[MetadataShadow] == "true"
+}
+
+// RouteJob returns the queue that should process job for real. When a shadow
+// deployment is active for the job's queue, the job always goes to the
+// stable queue and a tagged copy is also pushed onto the canary queue, so the
+// canary's metrics can be compared without its output ever being used. A
+// deployment with a cost guard only gets jobs, or copies, that fit its
+// budget; the rest stay on stable.
+func (m *Manager) RouteJob(ctx context.Context, job *Job) (string, error) {
+	deployment := m.shadowDeployment(job.Queue)
+	if deployment == nil || IsShadowJob(job) {
+		target, err := m.router.RouteJob(ctx, job)
+		if err != nil || target != job.Queue+"@canary" || m.admitCanaryCost(ctx, job) {
+			return target, err
+		}
+		m.recordCostCapped(ctx, job.Queue)
+		return job.Queue, nil
+	}
+
+	if !m.admitCanaryCost(ctx, job) {
+		m.recordCostCapped(ctx, job.Queue)
+		return job.Queue, nil
+	}
+	if err := m.mirrorJob(ctx, deployment, job); err != nil {
+		// Shadowing must never affect the real job
+		m.logger.Warn("Failed to mirror job to canary",
+			"deployment_id", deployment.ID,
+			"job_id", job.ID,
+			"error", err)
+	}
+	return job.Queue, nil
+}
+
+// shadowDeployment returns the active shadow deployment for queue, if any
+func (m *Manager) shadowDeployment(queue string) *CanaryDeployment {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, deployment := range m.deployments {
+		if deployment.QueueName == queue && deployment.Config != nil && deployment.Config.ShadowMode &&
+			(deployment.Status == StatusActive || deployment.Status == StatusPromoting) {
+			return m.copyDeployment(deployment)
+		}
+	}
+	return nil
+}
+
+func (m *Manager) mirrorJob(ctx context.Context, deployment *CanaryDeployment, job *Job) error {
+	data, err := json.Marshal(shadowCopy(job))
+	if err != nil {
+		return fmt.Errorf("failed to marshal shadow job: %w", err)
+	}
+
+	pipe := m.redis.TxPipeline()
+	pipe.LPush(ctx, deployment.QueueName+"@canary", data)
+	statsKey := fmt.Sprintf("canary:stats:%s:shadow", deployment.QueueName)
+	pipe.Incr(ctx, statsKey)
+	pipe.Expire(ctx, statsKey, 24*time.Hour)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to enqueue shadow job: %w", err)
+	}
+	return nil
+}
+
+// shadowCopy returns a canary-lane copy of job tagged as a dry run
+func shadowCopy(job *Job) *Job {
+	shadow := *job
+	shadow.ID = job.ID + ":shadow"
+	shadow.Lane = "canary"
+	shadow.Metadata = make(map[string]string, len(job.Metadata)+3)
+	for k, v := range job.Metadata {
+		shadow.Metadata[k] = v
+	}
+	shadow.Metadata[MetadataShadow] = "true"
+	shadow.Metadata[MetadataShadowOf] = job.ID
+	shadow.Metadata[MetadataDryRun] = "true"
+	return &shadow
+}
+
+// discardShadowJobs drops mirrored jobs still waiting on the canary queue.
+// In shadow mode the canary queue only ever holds copies, so nothing is lost
+// and nothing is moved onto the stable queue.
+func (m *Manager) discardShadowJobs(ctx context.Context, deployment *CanaryDeployment) error {
+	if err := m.redis.Del(ctx, deployment.QueueName+"@canary").Err(); err != nil {
+		return fmt.Errorf("failed to discard shadow jobs: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+//go:build canary_deployments_tests
+// +build canary_deployments_tests
+
+package canary_deployments
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupShadowManager(t *testing.T) (*Manager, *redis.Client, *CanaryDeployment) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	config := &Config{MaxConcurrentDeployments: 5, MaxCanaryPercentage: 50, HealthCheckInterval: time.Second}
+	config.SetDefaults()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	manager := NewManager(config, rdb, logger)
+
+	canaryConfig := DefaultCanaryConfig()
+	canaryConfig.ShadowMode = true
+	deployment, err := manager.CreateDeployment(context.Background(), canaryConfig)
+	require.NoError(t, err)
+	manager.deployments[deployment.ID].QueueName = "orders"
+	return manager, rdb, deployment
+}
+
+func TestManager_ShadowModeMirrorsToCanary(t *testing.T) {
+	manager, rdb, _ := setupShadowManager(t)
+	ctx := context.Background()
+
+	job := &Job{ID: "job-1", Queue: "orders", Metadata: map[string]string{"tenant": "a"}}
+	target, err := manager.RouteJob(ctx, job)
+	require.NoError(t, err)
+	assert.Equal(t, "orders", target, "real processing stays on stable")
+	assert.NotContains(t, job.Metadata, MetadataShadow, "original job must not be tagged")
+
+	raw, err := rdb.LPop(ctx, "orders@canary").Result()
+	require.NoError(t, err)
+	var shadow Job
+	require.NoError(t, json.Unmarshal([]byte(raw), &shadow))
+	assert.True(t, IsShadowJob(&shadow))
+	assert.Equal(t, "job-1", shadow.Metadata[MetadataShadowOf])
+	assert.Equal(t, "true", shadow.Metadata[MetadataDryRun])
+	assert.Equal(t, "a", shadow.Metadata["tenant"])
+	assert.Equal(t, "canary", shadow.Lane)
+
+	// A shadow copy is never mirrored again
+	_, err = manager.RouteJob(ctx, &shadow)
+	require.NoError(t, err)
+	n, _ := rdb.LLen(ctx, "orders@canary").Result()
+	assert.Zero(t, n)
+
+	stats, err := manager.router.GetRoutingStats(ctx, "orders")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats["shadow_mirrored"])
+}
+
+func TestManager_ShadowModeRejectsTrafficAndPromotion(t *testing.T) {
+	manager, _, deployment := setupShadowManager(t)
+	ctx := context.Background()
+
+	err := manager.UpdateDeploymentPercentage(ctx, deployment.ID, 10)
+	assert.True(t, IsCode(err, CodeInvalidConfiguration), "got %v", err)
+
+	err = manager.PromoteDeployment(ctx, deployment.ID)
+	assert.True(t, IsCode(err, CodePromotionBlocked), "got %v", err)
+}
+
+func TestManager_ShadowRollbackLeavesStableAlone(t *testing.T) {
+	manager, rdb, deployment := setupShadowManager(t)
+	ctx := context.Background()
+
+	require.NoError(t, rdb.LPush(ctx, "orders", "real").Err())
+	_, err := manager.RouteJob(ctx, &Job{ID: "job-1", Queue: "orders"})
+	require.NoError(t, err)
+
+	require.NoError(t, manager.RollbackDeployment(ctx, deployment.ID, "canary failing"))
+
+	stable, _ := rdb.LRange(ctx, "orders", 0, -1).Result()
+	assert.Equal(t, []string{"real"}, stable, "shadow copies must not be drained into stable")
+	n, _ := rdb.LLen(ctx, "orders@canary").Result()
+	assert.Zero(t, n)
+
+	// Mirroring stops once the deployment has failed
+	_, err = manager.RouteJob(ctx, &Job{ID: "job-2", Queue: "orders"})
+	require.NoError(t, err)
+	n, _ = rdb.LLen(ctx, "orders@canary").Result()
+	assert.Zero(t, n)
+}
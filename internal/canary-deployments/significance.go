@@ -0,0 +1,50 @@
+package canary_deployments
+
+import "math"
+
+// PromotionDecision is the outcome of comparing a canary against stable
+// before advancing to the next promotion stage
+type PromotionDecision string
+
+const (
+	// DecisionPromote means every threshold passed with enough confidence
+	DecisionPromote PromotionDecision = "promote"
+	// DecisionHold means an absolute threshold failed
+	DecisionHold PromotionDecision = "hold"
+	// DecisionInconclusive means there is not yet enough data to decide
+	DecisionInconclusive PromotionDecision = "inconclusive"
+)
+
+// errorRateRegressionZ is the two-proportion z statistic for the canary
+// error rate exceeding the stable one by more than margin percentage points.
+// Proportions use the Agresti-Caffo adjustment (one extra success and
+// failure per lane), which keeps the test defined when a lane has seen no
+// errors at all and stops tiny samples from looking certain.
+func errorRateRegressionZ(stable, canary *MetricsSnapshot, margin float64) float64 {
+	ps, ns := adjustedProportion(stable.ErrorCount, stable.JobCount)
+	pc, nc := adjustedProportion(canary.ErrorCount, canary.JobCount)
+	se := math.Sqrt(ps*(1-ps)/ns + pc*(1-pc)/nc)
+	return (pc - ps - margin/100) / se
+}
+
+func adjustedProportion(errors, jobs int64) (p, n float64) {
+	n = float64(jobs) + 2
+	return (float64(errors) + 1) / n, n
+}
+
+// errorRegressionConfidence is the confidence (0-100) that the canary error
+// rate is worse than stable by more than margin percentage points.
+func errorRegressionConfidence(stable, canary *MetricsSnapshot, margin float64) float64 {
+	return normalCDF(errorRateRegressionZ(stable, canary, margin)) * 100
+}
+
+// errorParityConfidence is the confidence (0-100) that the canary error rate
+// is no more than margin percentage points worse than stable. Success rate
+// is the complement of error rate, so this covers it as well.
+func errorParityConfidence(stable, canary *MetricsSnapshot, margin float64) float64 {
+	return normalCDF(-errorRateRegressionZ(stable, canary, margin)) * 100
+}
+
+func normalCDF(z float64) float64 {
+	return 0.5 * math.Erfc(-z/math.Sqrt2)
+}
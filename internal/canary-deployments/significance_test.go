@@ -0,0 +1,76 @@
+//go:build canary_deployments_tests
+// +build canary_deployments_tests
+
+package canary_deployments
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func snapshotWithErrors(jobs, errors int64) *MetricsSnapshot {
+	return &MetricsSnapshot{
+		JobCount:     jobs,
+		SuccessCount: jobs - errors,
+		ErrorCount:   errors,
+		ErrorRate:    float64(errors) / float64(jobs) * 100,
+		SuccessRate:  float64(jobs-errors) / float64(jobs) * 100,
+	}
+}
+
+func TestEvaluatePromotionConditions_Significance(t *testing.T) {
+	m := &Manager{}
+	stable := snapshotWithErrors(1000, 10)
+	conditions := SLOThresholds{
+		MaxErrorRateIncrease:  1,
+		MaxLatencyIncrease:    100,
+		MaxThroughputDecrease: 100,
+		MinSuccessRate:        90,
+		RequiredSampleSize:    20,
+	}
+
+	// Below the required sample size nothing is decided.
+	assert.Equal(t, DecisionInconclusive, m.evaluatePromotionConditions(stable, snapshotWithErrors(10, 0), conditions, 95))
+
+	// 30 clean jobs pass the absolute thresholds but prove little.
+	small := snapshotWithErrors(30, 0)
+	assert.Equal(t, DecisionPromote, m.evaluatePromotionConditions(stable, small, conditions, 0))
+	assert.Equal(t, DecisionInconclusive, m.evaluatePromotionConditions(stable, small, conditions, 95))
+
+	// A large sample at the stable error rate is significant.
+	assert.Equal(t, DecisionPromote, m.evaluatePromotionConditions(stable, snapshotWithErrors(5000, 50), conditions, 95))
+
+	// Failing an absolute threshold holds regardless of confidence.
+	assert.Equal(t, DecisionHold, m.evaluatePromotionConditions(stable, snapshotWithErrors(5000, 500), conditions, 95))
+}
+
+func TestEvaluateHealth_RollbackRequiresSignificance(t *testing.T) {
+	m := &Manager{}
+	config := DefaultCanaryConfig()
+	config.RollbackThresholds.MaxErrorRateIncrease = 10
+	config.RollbackThresholds.RequiredSampleSize = 10
+	deployment := &CanaryDeployment{Config: config}
+	stable := snapshotWithErrors(1000, 10)
+
+	// 3 errors in 12 jobs is over the threshold but not significant.
+	health := m.evaluateHealth(deployment, stable, snapshotWithErrors(12, 3))
+	assert.False(t, health.ErrorRateCheck.Passing)
+	assert.True(t, health.ErrorRateCheck.Inconclusive)
+	assert.Equal(t, WarningCanary, health.OverallStatus)
+
+	// Too few jobs is inconclusive as well.
+	health = m.evaluateHealth(deployment, stable, snapshotWithErrors(5, 5))
+	assert.True(t, health.ErrorRateCheck.Inconclusive)
+	assert.Equal(t, WarningCanary, health.OverallStatus)
+
+	// The same rate over 200 jobs rolls back.
+	health = m.evaluateHealth(deployment, stable, snapshotWithErrors(200, 50))
+	assert.False(t, health.ErrorRateCheck.Inconclusive)
+	assert.Equal(t, FailingCanary, health.OverallStatus)
+
+	// Without a confidence threshold the small sample fails immediately.
+	config.MinConfidence = 0
+	health = m.evaluateHealth(deployment, stable, snapshotWithErrors(12, 3))
+	assert.Equal(t, FailingCanary, health.OverallStatus)
+}
@@ -79,6 +79,16 @@ type CanaryDeployment struct {
 	LastUpdate      time.Time         `json:"last_update"`
 	CompletedAt     *time.Time        `json:"completed_at,omitempty"`
 
+	// Ramp progress: healthy time ramped so far, when it was last checked
+	// and whether a regression is holding it
+	RampElapsed     time.Duration     `json:"ramp_elapsed,omitempty"`
+	RampCheckedAt   time.Time         `json:"ramp_checked_at"`
+	RampPaused      bool              `json:"ramp_paused,omitempty"`
+
+	// LastHealth is the overall health of the latest evaluation; changes
+	// are recorded as health_changed events
+	LastHealth      CanaryHealth      `json:"last_health,omitempty"`
+
 	// Metrics
 	StableMetrics   *MetricsSnapshot  `json:"stable_metrics,omitempty"`
 	CanaryMetrics   *MetricsSnapshot  `json:"canary_metrics,omitempty"`
@@ -105,6 +115,18 @@ type CanaryConfig struct {
 	MetricsWindow       time.Duration     `json:"metrics_window"`
 	AlertWebhooks       []string          `json:"alert_webhooks,omitempty"`
 	Exemptions          []string          `json:"exemptions,omitempty"`
+	// ShadowMode mirrors every job to the canary lane while stable keeps
+	// processing all real traffic; canary results are only compared.
+	ShadowMode          bool              `json:"shadow_mode"`
+	// MinConfidence is the statistical confidence (percentage) an error rate
+	// difference must reach before it can promote or roll back; 0 disables.
+	MinConfidence       float64           `json:"min_confidence"`
+	// RollbackWebhook is notified of every automatic rollback.
+	RollbackWebhook     *RollbackWebhookConfig `json:"rollback_webhook,omitempty"`
+	// Ramp raises the percentage on a schedule instead of PromotionStages.
+	Ramp                *RampConfig       `json:"ramp,omitempty"`
+	// CostGuard caps the canary's spend per window on top of the percentage.
+	CostGuard           *CostGuardConfig  `json:"cost_guard,omitempty"`
 }
 
 // PromotionStage defines a stage in automatic promotion
@@ -160,6 +182,9 @@ type MetricsSnapshot struct {
 	// Additional context
 	WorkerCount     int           `json:"worker_count"`
 	Version         string        `json:"version"`
+
+	// Insufficient is set when the collector had no samples for the window
+	Insufficient    bool          `json:"insufficient,omitempty"`
 }
 
 // PromotionRule interface for defining promotion conditions
@@ -314,6 +339,9 @@ type HealthCheck struct {
 	Message     string    `json:"message"`
 	Timestamp   time.Time `json:"timestamp"`
 	Duration    time.Duration `json:"duration"`
+	// Inconclusive marks a failing check whose data is too thin or too noisy
+	// to act on yet
+	Inconclusive bool         `json:"inconclusive,omitempty"`
 }
 
 // CanaryHealthStatus represents the overall health of a canary
@@ -415,6 +443,9 @@ type CanaryManager interface {
 	GetDeploymentHealth(ctx context.Context, id string) (*CanaryHealthStatus, error)
 	GetDeploymentMetrics(ctx context.Context, id string) (*MetricsSnapshot, *MetricsSnapshot, error)
 	GetDeploymentEvents(ctx context.Context, id string) ([]*DeploymentEvent, error)
+	GenerateReport(ctx context.Context, id string, format string) ([]byte, error)
+	GetCostBudgetStatus(ctx context.Context, id string) (*CostBudgetStatus, error)
+	ListHistory(ctx context.Context, filter HistoryFilter) ([]*DeploymentHistory, error)
 
 	// Worker management
 	RegisterWorker(ctx context.Context, info *WorkerInfo) error
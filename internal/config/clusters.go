@@ -0,0 +1,49 @@
+// Copyright 2025 James Ross
+package config
+
+import (
+	"sort"
+	"strings"
+)
+
+// DefaultCluster names the instance configured by the top-level redis
+// section. Keys no route matches live there.
+const DefaultCluster = "default"
+
+// ClusterFor returns the name of the cluster holding key. A cluster_routes
+// entry matches key when it is the key itself, the worker.queues priority
+// whose queue is key, or a prefix ending in "*"; exact and priority entries
+// win over prefixes, and the longest prefix wins among those. Without a
+// match, or without any routes, the answer is DefaultCluster.
+func (c *Config) ClusterFor(key string) string {
+	if len(c.ClusterRoutes) == 0 {
+		return DefaultCluster
+	}
+	if name, ok := c.ClusterRoutes[key]; ok {
+		return name
+	}
+	priorities := make([]string, 0, len(c.Worker.Queues))
+	for p, q := range c.Worker.Queues {
+		if q == key {
+			priorities = append(priorities, p)
+		}
+	}
+	sort.Strings(priorities)
+	for _, p := range priorities {
+		if name, ok := c.ClusterRoutes[p]; ok {
+			return name
+		}
+	}
+
+	best, bestLen := DefaultCluster, -1
+	for route, name := range c.ClusterRoutes {
+		prefix, ok := strings.CutSuffix(route, "*")
+		if !ok || !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if len(prefix) > bestLen || (len(prefix) == bestLen && name < best) {
+			best, bestLen = name, len(prefix)
+		}
+	}
+	return best
+}
@@ -0,0 +1,29 @@
+// Copyright 2025 James Ross
+package config
+
+import "testing"
+
+func TestClusterFor(t *testing.T) {
+	cfg := defaultConfig()
+	if got := cfg.ClusterFor("jobqueue:high_priority"); got != DefaultCluster {
+		t.Fatalf("no routes: got %q", got)
+	}
+	cfg.ClusterRoutes = map[string]string{
+		"high":                  "fast",
+		"jobqueue:*":            "bulk",
+		"jobqueue:reports:*":    "reports",
+		"jobqueue:reports:eu:1": "eu",
+	}
+	for key, want := range map[string]string{
+		"jobqueue:high_priority": "fast",    // by priority
+		"jobqueue:low_priority":  "bulk",    // by prefix
+		"jobqueue:reports:daily": "reports", // longest prefix
+		"jobqueue:reports:eu:1":  "eu",      // exact key
+		"scheduled:jobqueue:low": DefaultCluster,
+		"jobqueue:dead_letter":   "bulk",
+	} {
+		if got := cfg.ClusterFor(key); got != want {
+			t.Errorf("ClusterFor(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
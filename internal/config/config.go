@@ -4,6 +4,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -30,28 +31,277 @@ type Backoff struct {
 }
 
 type Worker struct {
-	Count                 int               `mapstructure:"count"`
-	HeartbeatTTL          time.Duration     `mapstructure:"heartbeat_ttl"`
-	MaxRetries            int               `mapstructure:"max_retries"`
-	Backoff               Backoff           `mapstructure:"backoff"`
-	Priorities            []string          `mapstructure:"priorities"`
-	Queues                map[string]string `mapstructure:"queues"`
-	ProcessingListPattern string            `mapstructure:"processing_list_pattern"`
-	HeartbeatKeyPattern   string            `mapstructure:"heartbeat_key_pattern"`
-	CompletedList         string            `mapstructure:"completed_list"`
-	DeadLetterList        string            `mapstructure:"dead_letter_list"`
-	BRPopLPushTimeout     time.Duration     `mapstructure:"brpoplpush_timeout"`
-	BreakerPause          time.Duration     `mapstructure:"breaker_pause"`
+	Mode                  string               `mapstructure:"mode"` // list (default) or stream
+	Count                 int                  `mapstructure:"count"`
+	HeartbeatTTL          time.Duration        `mapstructure:"heartbeat_ttl"`
+	MaxRetries            int                  `mapstructure:"max_retries"`
+	UnclassifiedErrors    string               `mapstructure:"unclassified_errors"` // retry (default) or dead_letter
+	Backoff               Backoff              `mapstructure:"backoff"`
+	Priorities            []string             `mapstructure:"priorities"`
+	Queues                map[string]string    `mapstructure:"queues"`
+	ProcessingListPattern string               `mapstructure:"processing_list_pattern"`
+	HeartbeatKeyPattern   string               `mapstructure:"heartbeat_key_pattern"`
+	CompletedList         string               `mapstructure:"completed_list"`
+	DeadLetterList        string               `mapstructure:"dead_letter_list"`
+	DeadLetterReasonField string               `mapstructure:"dead_letter_reason_field"`
+	BRPopLPushTimeout     time.Duration        `mapstructure:"brpoplpush_timeout"`
+	BreakerPause          time.Duration        `mapstructure:"breaker_pause"`
+	PauseCacheTTL         time.Duration        `mapstructure:"pause_cache_ttl"`
+	SchedulerInterval     time.Duration        `mapstructure:"scheduler_interval"`
+	SchedulerBatch        int                  `mapstructure:"scheduler_batch"`
+	CircuitBreaker        WorkerBreaker        `mapstructure:"circuit_breaker"`
+	Stream                WorkerStream         `mapstructure:"stream"`
+	Dedup                 WorkerDedup          `mapstructure:"dedup"`
+	Coalesce              WorkerCoalesce       `mapstructure:"coalesce"`
+	ReclaimBackoff        ReclaimBackoff       `mapstructure:"reclaim_backoff"`
+	Reaper                ReaperConfig         `mapstructure:"reaper"`
+	CompletedRetention    CompletedRetention   `mapstructure:"completed_retention"`
+	Autoscale             WorkerAutoscale      `mapstructure:"autoscale"`
+	CompletionStream      WorkerCompletion     `mapstructure:"completion_stream"`
+	FairScheduling        WorkerFairScheduling `mapstructure:"fair_scheduling"`
+	SilenceAlert          WorkerSilenceAlert   `mapstructure:"silence_alert"`
+	// QueueRateLimits caps jobs per second fetched from a priority's queue,
+	// across all workers; a throttled queue is skipped, not waited on.
+	QueueRateLimits map[string]float64 `mapstructure:"queue_rate_limits"`
+	// QueueTimeouts tunes, per priority, how long a dequeued job stays
+	// invisible to the reaper and how long its handler may run.
+	QueueTimeouts map[string]QueueTimeouts `mapstructure:"queue_timeouts"`
+	// PausedQueues lists priorities whose queues this process does not
+	// poll, on top of queues paused in Redis with admin pause.
+	PausedQueues []string `mapstructure:"paused_queues"`
+}
+
+// QueueTimeouts are the per-queue timeouts under worker.queue_timeouts.
+type QueueTimeouts struct {
+	// VisibilityTimeout is how long a job stays in its worker's processing
+	// list before the reaper may return it to the queue once the worker's
+	// heartbeat is gone, in place of heartbeat_ttl plus
+	// reaper.grace_period; 0 keeps those.
+	VisibilityTimeout time.Duration `mapstructure:"visibility_timeout"`
+	// JobTimeout cancels the handler's context after this long; 0 for none.
+	JobTimeout time.Duration `mapstructure:"job_timeout"`
+}
+
+// Visibility returns how long a job dequeued from priority's queue stays
+// invisible: its visibility_timeout, or heartbeat_ttl plus
+// reaper.grace_period when none is set.
+func (w Worker) Visibility(priority string) time.Duration {
+	if vt := w.QueueTimeouts[priority].VisibilityTimeout; vt > 0 {
+		return vt
+	}
+	return w.HeartbeatTTL + w.Reaper.GracePeriod
+}
+
+// Worker modes.
+const (
+	// ModeList moves jobs between lists with BRPOPLPUSH; the reaper
+	// recovers jobs from dead workers' processing lists.
+	ModeList = "list"
+	// ModeStream reads Redis Streams through a consumer group; jobs stay in
+	// the pending entries list until acknowledged and stalled ones are
+	// reclaimed with XAUTOCLAIM.
+	ModeStream = "stream"
+)
+
+// How workers treat handler errors that are neither worker.Retryable nor
+// worker.Permanent.
+const (
+	UnclassifiedRetry      = "retry"
+	UnclassifiedDeadLetter = "dead_letter"
+)
+
+// WorkerStream tunes stream mode.
+type WorkerStream struct {
+	Group         string        `mapstructure:"group"`          // consumer group shared by all workers
+	ClaimIdle     time.Duration `mapstructure:"claim_idle"`     // pending this long without progress before another worker reclaims it
+	ClaimInterval time.Duration `mapstructure:"claim_interval"` // how often each worker looks for stalled entries
+	MaxLen        int64         `mapstructure:"max_len"`        // soft cap on entries per stream, 0 for none; entries not yet acked are never trimmed
+}
+
+// WorkerDedup turns on completion dedup: a job ID that is already done or
+// in progress is acked without running the handler again.
+type WorkerDedup struct {
+	Queues []string      `mapstructure:"queues"` // priorities to dedup, e.g. ["high"]
+	TTL    time.Duration `mapstructure:"ttl"`    // how long done:{id} markers are kept
+}
+
+// WorkerCoalesce turns on coalescing: a job whose dedup key matches one
+// that is in flight or completed within TTL is marked completed without
+// running the handler, sharing the first job's result.
+type WorkerCoalesce struct {
+	Queues []string      `mapstructure:"queues"` // priorities to coalesce, e.g. ["low"]
+	TTL    time.Duration `mapstructure:"ttl"`    // how long coalesce:{queue}:{key} markers are kept
+}
+
+// WorkerCompletion publishes an event for every job that completes or is
+// dead-lettered to a Redis Stream, a pub/sub channel, or both. Nothing is
+// published while Stream and Channel are empty.
+type WorkerCompletion struct {
+	Stream  string `mapstructure:"stream"`  // XADD target
+	Channel string `mapstructure:"channel"` // PUBLISH target
+	MaxLen  int64  `mapstructure:"max_len"` // approximate cap on stream entries, 0 for none
+	// Transactional writes the event and the job's entry in the completed
+	// or dead letter list in one script, and the entry is not written if
+	// the event fails. Otherwise publishing is best-effort: failures are
+	// logged and counted, never fatal.
+	Transactional bool `mapstructure:"transactional"`
+}
+
+// WorkerFairScheduling chooses the priority each fetch tries first. Strict
+// scheduling polls priorities in order, so lower ones only run while every
+// higher one is empty. Weighted scheduling (list mode) interleaves the
+// non-empty queues in proportion to Weights: with high 4 and low 1, a
+// worker serves low on every fifth pull even while high has a backlog.
+type WorkerFairScheduling struct {
+	Mode    string         `mapstructure:"mode"`    // strict (default) or weighted
+	Weights map[string]int `mapstructure:"weights"` // per priority; missing ones weigh 1
+}
+
+// Fair scheduling modes.
+const (
+	SchedulingStrict   = "strict"
+	SchedulingWeighted = "weighted"
+)
+
+// ReclaimBackoff throttles jobs the reaper keeps reclaiming. After
+// Threshold immediate requeues, a reclaimed job goes to delayed:{queue} for
+// Base times its reclaim count, capped at Max. A zero Base disables it.
+type ReclaimBackoff struct {
+	Threshold int           `mapstructure:"threshold"` // reclaims requeued straight away
+	Base      time.Duration `mapstructure:"base"`
+	Max       time.Duration `mapstructure:"max"`
+}
+
+// ReaperConfig paces the reaper's sweeps of processing lists. Each sweep
+// SCANs for them ScanCount keys at a time and spends at most
+// MaxOpsPerSecond Redis commands per second (0 for no limit) checking
+// heartbeats and moving jobs. A processing list is reclaimed once it has
+// been without a worker heartbeat for GracePeriod, counted from the first
+// sweep that found it so: a live worker's list briefly has none between
+// dequeuing a job and setting its heartbeat. 0 reclaims on first sight.
+type ReaperConfig struct {
+	Interval        time.Duration `mapstructure:"interval"`
+	ScanCount       int64         `mapstructure:"scan_count"`
+	MaxOpsPerSecond float64       `mapstructure:"max_ops_per_second"`
+	GracePeriod     time.Duration `mapstructure:"grace_period"`
+}
+
+// CompletedRetention bounds the completed list. Every Interval workers keep
+// only its KeepLast newest entries and drop those whose job was created
+// more than MaxAge ago. Zero KeepLast and MaxAge disable the trimmer.
+type CompletedRetention struct {
+	KeepLast int           `mapstructure:"keep_last"`
+	MaxAge   time.Duration `mapstructure:"max_age"`
+	Interval time.Duration `mapstructure:"interval"`
+}
+
+// WorkerAutoscale resizes the worker pool between MinConcurrency and
+// MaxConcurrency. Count is the starting size. The pool grows while the
+// backlog exceeds BacklogPerWorker jobs per goroutine and job latency is not
+// falling, and halves after the queues have been empty for ScaleDownDelay.
+type WorkerAutoscale struct {
+	Enabled          bool          `mapstructure:"enabled"`
+	MinConcurrency   int           `mapstructure:"min_concurrency"`
+	MaxConcurrency   int           `mapstructure:"max_concurrency"`
+	Interval         time.Duration `mapstructure:"interval"`           // how often backlog and latency are sampled
+	BacklogPerWorker int64         `mapstructure:"backlog_per_worker"` // queued jobs one goroutine is expected to absorb
+	ScaleDownDelay   time.Duration `mapstructure:"scale_down_delay"`   // idle time before each scale-down step
+}
+
+// WorkerSilenceAlert is a dead man's switch on queue completions. Workers
+// learn each queue's completion rate over BaselineWindow and alert when a
+// queue that usually completes jobs goes longer than BaselineFactor times
+// its usual gap between completions, and at least MinSilence, without
+// one. A queue whose threshold would exceed BaselineWindow is not expected
+// to be active and never alerts.
+type WorkerSilenceAlert struct {
+	Enabled        bool          `mapstructure:"enabled"`
+	WebhookURLs    []string      `mapstructure:"webhook_urls"`   // POSTed queue_silent and queue_recovered alerts
+	WebhookSecret  string        `mapstructure:"webhook_secret"` // signs bodies in X-Webhook-Signature when set
+	CheckInterval  time.Duration `mapstructure:"check_interval"`
+	MinSilence     time.Duration `mapstructure:"min_silence"`
+	BaselineFactor float64       `mapstructure:"baseline_factor"`
+	BaselineWindow time.Duration `mapstructure:"baseline_window"`
+}
+
+// WorkerBreaker tunes the worker's per-queue circuit breakers. Zero fields
+// fall back to the top-level circuit_breaker settings.
+type WorkerBreaker struct {
+	FailureThreshold float64       `mapstructure:"failure_threshold"` // failure rate (0..1) that opens a breaker
+	MinRequests      int           `mapstructure:"min_requests"`      // jobs within Window before the rate counts
+	Window           time.Duration `mapstructure:"window"`            // sliding window while closed
+	OpenTimeout      time.Duration `mapstructure:"open_timeout"`      // time open before a half-open probe
+	RequeueDelay     time.Duration `mapstructure:"requeue_delay"`     // hold on a job rejected by an open breaker before requeueing it
 }
 
 type Producer struct {
-	ScanDir          string   `mapstructure:"scan_dir"`
-	IncludeGlobs     []string `mapstructure:"include_globs"`
-	ExcludeGlobs     []string `mapstructure:"exclude_globs"`
-	DefaultPriority  string   `mapstructure:"default_priority"`
-	HighPriorityExts []string `mapstructure:"high_priority_exts"`
-	RateLimitPerSec  int      `mapstructure:"rate_limit_per_sec"`
-	RateLimitKey     string   `mapstructure:"rate_limit_key"`
+	ScanDir          string      `mapstructure:"scan_dir"`
+	IncludeGlobs     []string    `mapstructure:"include_globs"`
+	ExcludeGlobs     []string    `mapstructure:"exclude_globs"`
+	DefaultPriority  string      `mapstructure:"default_priority"`
+	HighPriorityExts []string    `mapstructure:"high_priority_exts"`
+	RateLimitPerSec  int         `mapstructure:"rate_limit_per_sec"`
+	RateLimitKey     string      `mapstructure:"rate_limit_key"`
+	Compression      Compression `mapstructure:"compression"`
+	// MaxQueueLength caps each list queue; 0 leaves queues unbounded.
+	MaxQueueLength int64        `mapstructure:"max_queue_length"`
+	Backpressure   Backpressure `mapstructure:"backpressure"`
+	// MaxBatchSize caps how many jobs one EnqueueBatch writes atomically.
+	MaxBatchSize int `mapstructure:"max_batch_size"`
+	// PriorityAging promotes, per priority, jobs that have waited too long
+	// to a higher priority queue; empty ages nothing.
+	PriorityAging map[string]PriorityAging `mapstructure:"priority_aging"`
+}
+
+// PriorityAging is one queue's entry under producer.priority_aging. The
+// producer records when it enqueues each job onto the queue, and the
+// scheduler moves jobs that have waited longer than After to the consuming
+// end of PromoteTo's queue, so no job waits much past After however busy
+// the higher priorities are.
+type PriorityAging struct {
+	After         time.Duration `mapstructure:"after"`
+	CheckInterval time.Duration `mapstructure:"check_interval"`
+	// PromoteTo is the priority aged jobs move to; empty is the first of
+	// worker.priorities.
+	PromoteTo string `mapstructure:"promote_to"`
+}
+
+// AgingTarget returns the priority that priority's aged jobs move to.
+func (p *Producer) AgingTarget(priority string, w *Worker) string {
+	if to := p.PriorityAging[priority].PromoteTo; to != "" {
+		return to
+	}
+	if len(w.Priorities) > 0 {
+		return w.Priorities[0]
+	}
+	return ""
+}
+
+// Backpressure policies for an enqueue onto a queue at max_queue_length.
+const (
+	BackpressureBlock    = "block"    // wait for space, up to BlockTimeout
+	BackpressureReject   = "reject"   // fail with producer.ErrQueueFull
+	BackpressureOverflow = "overflow" // push to OverflowQueue instead
+)
+
+// Backpressure decides what an enqueue does when its queue is full.
+type Backpressure struct {
+	Policy        string        `mapstructure:"policy"`         // block, reject or overflow
+	OverflowQueue string        `mapstructure:"overflow_queue"` // priority alias or key, for overflow
+	BlockTimeout  time.Duration `mapstructure:"block_timeout"`  // 0 blocks until space or cancellation
+	PollInterval  time.Duration `mapstructure:"poll_interval"`  // how often a blocked enqueue retries
+	// LengthCacheTTL is how long a queue seen full is taken to still be
+	// full without asking Redis, so blocked and rejected enqueues do not
+	// hammer it.
+	LengthCacheTTL time.Duration `mapstructure:"length_cache_ttl"`
+}
+
+// Compression makes the producer compress job payloads of at least MinSize
+// bytes with Codec (none, gzip or zstd). Workers and admin tools inflate
+// compressed payloads whatever this is set to.
+type Compression struct {
+	Codec   string `mapstructure:"codec"`
+	MinSize int    `mapstructure:"min_size"`
 }
 
 type CircuitBreaker struct {
@@ -64,11 +314,17 @@ type CircuitBreaker struct {
 type TracingConfig struct {
 	Enabled             bool              `mapstructure:"enabled"`
 	Endpoint            string            `mapstructure:"endpoint"`
+	// Protocol is grpc or http/protobuf; empty defers to
+	// OTEL_EXPORTER_OTLP_TRACES_PROTOCOL / OTEL_EXPORTER_OTLP_PROTOCOL.
+	Protocol            string            `mapstructure:"protocol"`
+	ServiceName         string            `mapstructure:"service_name"`
+	ServiceVersion      string            `mapstructure:"service_version"`
 	Environment         string            `mapstructure:"environment"`
 	SamplingStrategy    string            `mapstructure:"sampling_strategy"`
 	SamplingRate        float64           `mapstructure:"sampling_rate"`
 	BatchTimeout        time.Duration     `mapstructure:"batch_timeout"`
 	MaxExportBatchSize  int               `mapstructure:"max_export_batch_size"`
+	MaxQueueSize        int               `mapstructure:"max_queue_size"`
 	Headers             map[string]string `mapstructure:"headers"`
 	Insecure            bool              `mapstructure:"insecure"`
 	PropagationFormat   string            `mapstructure:"propagation_format"`
@@ -85,17 +341,27 @@ type ObservabilityConfig struct {
 	LogLevel            string        `mapstructure:"log_level"`
 	Tracing             TracingConfig `mapstructure:"tracing"`
 	QueueSampleInterval time.Duration `mapstructure:"queue_sample_interval"`
+	// HealthCheckTimeout bounds each /healthz and /readyz dependency check.
+	HealthCheckTimeout time.Duration `mapstructure:"health_check_timeout"`
 }
 
 // Observability is a backwards-compatible alias
 type Observability = ObservabilityConfig
 
 type Config struct {
+	// Namespace prefixes every Redis key this system uses, so independent
+	// queue systems can share one Redis; see ApplyNamespace. Empty uses
+	// the configured keys as they are.
+	Namespace      string              `mapstructure:"namespace"`
 	Redis          Redis               `mapstructure:"redis"`
 	Worker         Worker              `mapstructure:"worker"`
 	Producer       Producer            `mapstructure:"producer"`
 	CircuitBreaker CircuitBreaker      `mapstructure:"circuit_breaker"`
 	Observability  Observability       `mapstructure:"observability"`
+	// Clusters are extra Redis instances by name; ClusterRoutes sends
+	// queues to them. See ClusterFor.
+	Clusters      map[string]Redis  `mapstructure:"clusters"`
+	ClusterRoutes map[string]string `mapstructure:"cluster_routes"`
 	// ExactlyOnce    exactlyonce.Config  `mapstructure:"exactly_once"`
 }
 
@@ -111,9 +377,11 @@ func defaultConfig() *Config {
 			MaxRetries:         3,
 		},
 		Worker: Worker{
+			Mode:                  ModeList,
 			Count:                 16,
 			HeartbeatTTL:          30 * time.Second,
 			MaxRetries:            3,
+			UnclassifiedErrors:    UnclassifiedRetry,
 			Backoff:               Backoff{Base: 500 * time.Millisecond, Max: 10 * time.Second},
 			Priorities:            []string{"high", "low"},
 			Queues:                map[string]string{"high": "jobqueue:high_priority", "low": "jobqueue:low_priority"},
@@ -121,8 +389,23 @@ func defaultConfig() *Config {
 			HeartbeatKeyPattern:   "jobqueue:processing:worker:%s",
 			CompletedList:         "jobqueue:completed",
 			DeadLetterList:        "jobqueue:dead_letter",
+			DeadLetterReasonField: "error",
 			BRPopLPushTimeout:     1 * time.Second,
 			BreakerPause:          100 * time.Millisecond,
+			PauseCacheTTL:         2 * time.Second,
+			SchedulerInterval:     1 * time.Second,
+			SchedulerBatch:        100,
+			CircuitBreaker:        WorkerBreaker{RequeueDelay: 1 * time.Second},
+			Stream:                WorkerStream{Group: "workers", ClaimIdle: 60 * time.Second, ClaimInterval: 5 * time.Second},
+			Dedup:                 WorkerDedup{TTL: 24 * time.Hour},
+			Coalesce:              WorkerCoalesce{TTL: 5 * time.Minute},
+			ReclaimBackoff:        ReclaimBackoff{Threshold: 1, Base: 30 * time.Second, Max: 10 * time.Minute},
+			Reaper:                ReaperConfig{Interval: 5 * time.Second, ScanCount: 100, GracePeriod: 10 * time.Second},
+			CompletedRetention:    CompletedRetention{Interval: time.Minute},
+			Autoscale:             WorkerAutoscale{MinConcurrency: 1, MaxConcurrency: 64, Interval: 5 * time.Second, BacklogPerWorker: 10, ScaleDownDelay: 30 * time.Second},
+			CompletionStream:      WorkerCompletion{MaxLen: 10000},
+			FairScheduling:        WorkerFairScheduling{Mode: SchedulingStrict},
+			SilenceAlert:          WorkerSilenceAlert{CheckInterval: 30 * time.Second, MinSilence: 5 * time.Minute, BaselineFactor: 10, BaselineWindow: 24 * time.Hour},
 		},
 		Producer: Producer{
 			ScanDir:          "./data",
@@ -132,6 +415,9 @@ func defaultConfig() *Config {
 			HighPriorityExts: []string{".pdf", ".docx", ".xlsx", ".zip"},
 			RateLimitPerSec:  100,
 			RateLimitKey:     "jobqueue:rate_limit:producer",
+			Compression:      Compression{Codec: "none", MinSize: 4096},
+			Backpressure:     Backpressure{Policy: BackpressureBlock, BlockTimeout: 30 * time.Second, PollInterval: 100 * time.Millisecond, LengthCacheTTL: time.Second},
+			MaxBatchSize:     1000,
 		},
 		CircuitBreaker: CircuitBreaker{
 			FailureThreshold: 0.5,
@@ -142,15 +428,41 @@ func defaultConfig() *Config {
 		Observability: Observability{
 			MetricsPort:         9090,
 			LogLevel:            "info",
-			Tracing:             Tracing{Enabled: false},
+			Tracing: Tracing{
+				Enabled:            false,
+				ServiceName:        "go-redis-work-queue",
+				SamplingStrategy:   "probabilistic",
+				SamplingRate:       1.0,
+				BatchTimeout:       5 * time.Second,
+				MaxExportBatchSize: 512,
+				MaxQueueSize:       2048,
+			},
 			QueueSampleInterval: 2 * time.Second,
+			HealthCheckTimeout:  2 * time.Second,
 		},
 		// ExactlyOnce: *exactlyonce.DefaultConfig(),
 	}
 }
 
-// Load reads configuration from YAML file and env overrides.
+// Load reads configuration from YAML file and env overrides. The profile
+// named by ProfileEnv, when set, is overlaid as for LoadProfile.
 func Load(path string) (*Config, error) {
+	return load(path, os.Getenv(ProfileEnv), false)
+}
+
+// LoadStrict is Load, but keys in the file that match no config field are
+// reported along with the Validate problems, so typos fail loudly instead
+// of leaving the default in place.
+func LoadStrict(path string) (*Config, error) {
+	return load(path, os.Getenv(ProfileEnv), true)
+}
+
+func load(path, profile string, strict bool) (*Config, error) {
+	overlay, err := overlayFile(path, profile)
+	if err != nil {
+		return nil, err
+	}
+
 	v := viper.New()
 	v.SetConfigFile(path)
 	v.SetConfigType("yaml")
@@ -170,6 +482,7 @@ func Load(path string) (*Config, error) {
 	v.SetDefault("worker.count", def.Worker.Count)
 	v.SetDefault("worker.heartbeat_ttl", def.Worker.HeartbeatTTL)
 	v.SetDefault("worker.max_retries", def.Worker.MaxRetries)
+	v.SetDefault("worker.unclassified_errors", def.Worker.UnclassifiedErrors)
 	v.SetDefault("worker.backoff.base", def.Worker.Backoff.Base)
 	v.SetDefault("worker.backoff.max", def.Worker.Backoff.Max)
 	v.SetDefault("worker.priorities", def.Worker.Priorities)
@@ -178,8 +491,54 @@ func Load(path string) (*Config, error) {
 	v.SetDefault("worker.heartbeat_key_pattern", def.Worker.HeartbeatKeyPattern)
 	v.SetDefault("worker.completed_list", def.Worker.CompletedList)
 	v.SetDefault("worker.dead_letter_list", def.Worker.DeadLetterList)
+	v.SetDefault("worker.dead_letter_reason_field", def.Worker.DeadLetterReasonField)
 	v.SetDefault("worker.brpoplpush_timeout", def.Worker.BRPopLPushTimeout)
 	v.SetDefault("worker.breaker_pause", def.Worker.BreakerPause)
+	v.SetDefault("worker.pause_cache_ttl", def.Worker.PauseCacheTTL)
+	v.SetDefault("worker.scheduler_interval", def.Worker.SchedulerInterval)
+	v.SetDefault("worker.scheduler_batch", def.Worker.SchedulerBatch)
+	v.SetDefault("worker.circuit_breaker.failure_threshold", def.Worker.CircuitBreaker.FailureThreshold)
+	v.SetDefault("worker.circuit_breaker.min_requests", def.Worker.CircuitBreaker.MinRequests)
+	v.SetDefault("worker.circuit_breaker.window", def.Worker.CircuitBreaker.Window)
+	v.SetDefault("worker.circuit_breaker.open_timeout", def.Worker.CircuitBreaker.OpenTimeout)
+	v.SetDefault("worker.circuit_breaker.requeue_delay", def.Worker.CircuitBreaker.RequeueDelay)
+	v.SetDefault("worker.mode", def.Worker.Mode)
+	v.SetDefault("worker.stream.group", def.Worker.Stream.Group)
+	v.SetDefault("worker.stream.claim_idle", def.Worker.Stream.ClaimIdle)
+	v.SetDefault("worker.stream.claim_interval", def.Worker.Stream.ClaimInterval)
+	v.SetDefault("worker.stream.max_len", def.Worker.Stream.MaxLen)
+	v.SetDefault("worker.dedup.queues", def.Worker.Dedup.Queues)
+	v.SetDefault("worker.dedup.ttl", def.Worker.Dedup.TTL)
+	v.SetDefault("worker.coalesce.queues", def.Worker.Coalesce.Queues)
+	v.SetDefault("worker.coalesce.ttl", def.Worker.Coalesce.TTL)
+	v.SetDefault("worker.reclaim_backoff.threshold", def.Worker.ReclaimBackoff.Threshold)
+	v.SetDefault("worker.reclaim_backoff.base", def.Worker.ReclaimBackoff.Base)
+	v.SetDefault("worker.reclaim_backoff.max", def.Worker.ReclaimBackoff.Max)
+	v.SetDefault("worker.reaper.interval", def.Worker.Reaper.Interval)
+	v.SetDefault("worker.reaper.scan_count", def.Worker.Reaper.ScanCount)
+	v.SetDefault("worker.reaper.max_ops_per_second", def.Worker.Reaper.MaxOpsPerSecond)
+	v.SetDefault("worker.reaper.grace_period", def.Worker.Reaper.GracePeriod)
+	v.SetDefault("worker.completed_retention.keep_last", def.Worker.CompletedRetention.KeepLast)
+	v.SetDefault("worker.completed_retention.max_age", def.Worker.CompletedRetention.MaxAge)
+	v.SetDefault("worker.completed_retention.interval", def.Worker.CompletedRetention.Interval)
+	v.SetDefault("worker.autoscale.enabled", def.Worker.Autoscale.Enabled)
+	v.SetDefault("worker.autoscale.min_concurrency", def.Worker.Autoscale.MinConcurrency)
+	v.SetDefault("worker.autoscale.max_concurrency", def.Worker.Autoscale.MaxConcurrency)
+	v.SetDefault("worker.autoscale.interval", def.Worker.Autoscale.Interval)
+	v.SetDefault("worker.autoscale.backlog_per_worker", def.Worker.Autoscale.BacklogPerWorker)
+	v.SetDefault("worker.autoscale.scale_down_delay", def.Worker.Autoscale.ScaleDownDelay)
+	v.SetDefault("worker.completion_stream.stream", def.Worker.CompletionStream.Stream)
+	v.SetDefault("worker.completion_stream.channel", def.Worker.CompletionStream.Channel)
+	v.SetDefault("worker.completion_stream.max_len", def.Worker.CompletionStream.MaxLen)
+	v.SetDefault("worker.completion_stream.transactional", def.Worker.CompletionStream.Transactional)
+	v.SetDefault("worker.fair_scheduling.mode", def.Worker.FairScheduling.Mode)
+	v.SetDefault("worker.silence_alert.enabled", def.Worker.SilenceAlert.Enabled)
+	v.SetDefault("worker.silence_alert.webhook_urls", def.Worker.SilenceAlert.WebhookURLs)
+	v.SetDefault("worker.silence_alert.webhook_secret", def.Worker.SilenceAlert.WebhookSecret)
+	v.SetDefault("worker.silence_alert.check_interval", def.Worker.SilenceAlert.CheckInterval)
+	v.SetDefault("worker.silence_alert.min_silence", def.Worker.SilenceAlert.MinSilence)
+	v.SetDefault("worker.silence_alert.baseline_factor", def.Worker.SilenceAlert.BaselineFactor)
+	v.SetDefault("worker.silence_alert.baseline_window", def.Worker.SilenceAlert.BaselineWindow)
 
 	v.SetDefault("producer.scan_dir", def.Producer.ScanDir)
 	v.SetDefault("producer.include_globs", def.Producer.IncludeGlobs)
@@ -188,6 +547,15 @@ func Load(path string) (*Config, error) {
 	v.SetDefault("producer.high_priority_exts", def.Producer.HighPriorityExts)
 	v.SetDefault("producer.rate_limit_per_sec", def.Producer.RateLimitPerSec)
 	v.SetDefault("producer.rate_limit_key", def.Producer.RateLimitKey)
+	v.SetDefault("producer.compression.codec", def.Producer.Compression.Codec)
+	v.SetDefault("producer.compression.min_size", def.Producer.Compression.MinSize)
+	v.SetDefault("producer.max_queue_length", def.Producer.MaxQueueLength)
+	v.SetDefault("producer.backpressure.policy", def.Producer.Backpressure.Policy)
+	v.SetDefault("producer.backpressure.overflow_queue", def.Producer.Backpressure.OverflowQueue)
+	v.SetDefault("producer.backpressure.block_timeout", def.Producer.Backpressure.BlockTimeout)
+	v.SetDefault("producer.backpressure.poll_interval", def.Producer.Backpressure.PollInterval)
+	v.SetDefault("producer.backpressure.length_cache_ttl", def.Producer.Backpressure.LengthCacheTTL)
+	v.SetDefault("producer.max_batch_size", def.Producer.MaxBatchSize)
 
 	v.SetDefault("circuit_breaker.failure_threshold", def.CircuitBreaker.FailureThreshold)
 	v.SetDefault("circuit_breaker.window", def.CircuitBreaker.Window)
@@ -198,7 +566,16 @@ func Load(path string) (*Config, error) {
 	v.SetDefault("observability.log_level", def.Observability.LogLevel)
 	v.SetDefault("observability.tracing.enabled", def.Observability.Tracing.Enabled)
 	v.SetDefault("observability.tracing.endpoint", def.Observability.Tracing.Endpoint)
+	v.SetDefault("observability.tracing.protocol", def.Observability.Tracing.Protocol)
+	v.SetDefault("observability.tracing.service_name", def.Observability.Tracing.ServiceName)
+	v.SetDefault("observability.tracing.service_version", def.Observability.Tracing.ServiceVersion)
+	v.SetDefault("observability.tracing.sampling_strategy", def.Observability.Tracing.SamplingStrategy)
+	v.SetDefault("observability.tracing.sampling_rate", def.Observability.Tracing.SamplingRate)
+	v.SetDefault("observability.tracing.batch_timeout", def.Observability.Tracing.BatchTimeout)
+	v.SetDefault("observability.tracing.max_export_batch_size", def.Observability.Tracing.MaxExportBatchSize)
+	v.SetDefault("observability.tracing.max_queue_size", def.Observability.Tracing.MaxQueueSize)
 	v.SetDefault("observability.queue_sample_interval", def.Observability.QueueSampleInterval)
+	v.SetDefault("observability.health_check_timeout", def.Observability.HealthCheckTimeout)
 
 	// Exactly-once patterns defaults (temporarily disabled)
 	// v.SetDefault("exactly_once.idempotency.enabled", def.ExactlyOnce.Idempotency.Enabled)
@@ -210,46 +587,45 @@ func Load(path string) (*Config, error) {
 
 
 	// Optional file read
+	var problems []FieldError
 	if _, err := os.Stat(path); err == nil {
 		if err := v.ReadInConfig(); err != nil {
 			return nil, fmt.Errorf("read config: %w", err)
 		}
+		if strict {
+			if problems, err = unknownKeys(path); err != nil {
+				return nil, fmt.Errorf("read config: %w", err)
+			}
+		}
+	}
+	if overlay != "" {
+		v.SetConfigFile(overlay)
+		if err := v.MergeInConfig(); err != nil {
+			return nil, fmt.Errorf("read profile %q: %w", profile, err)
+		}
+		if strict {
+			extra, err := unknownKeys(overlay)
+			if err != nil {
+				return nil, fmt.Errorf("read profile %q: %w", profile, err)
+			}
+			for _, p := range extra {
+				p.Message += " in " + filepath.Base(overlay)
+				problems = append(problems, p)
+			}
+		}
+	}
+	if err := applyEnvOverrides(v); err != nil {
+		return nil, fmt.Errorf("env overrides: %w", err)
 	}
 
 	var cfg Config
 	if err := v.Unmarshal(&cfg); err != nil {
 		return nil, fmt.Errorf("unmarshal config: %w", err)
 	}
-	if err := Validate(&cfg); err != nil {
-		return nil, err
+	cfg.ApplyNamespace()
+	problems = append(problems, validate(&cfg)...)
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
 	}
 	return &cfg, nil
 }
-
-// Validate checks config constraints and returns an error on invalid settings.
-func Validate(cfg *Config) error {
-	if cfg.Worker.Count < 1 {
-		return fmt.Errorf("worker.count must be >= 1")
-	}
-	if len(cfg.Worker.Priorities) == 0 {
-		return fmt.Errorf("worker.priorities must be non-empty")
-	}
-	for _, p := range cfg.Worker.Priorities {
-		if _, ok := cfg.Worker.Queues[p]; !ok {
-			return fmt.Errorf("worker.queues missing entry for priority %q", p)
-		}
-	}
-	if cfg.Worker.HeartbeatTTL < 5*time.Second {
-		return fmt.Errorf("worker.heartbeat_ttl must be >= 5s")
-	}
-	if cfg.Worker.BRPopLPushTimeout <= 0 || cfg.Worker.BRPopLPushTimeout > cfg.Worker.HeartbeatTTL/2 {
-		return fmt.Errorf("worker.brpoplpush_timeout must be >0 and <= heartbeat_ttl/2")
-	}
-	if cfg.Producer.RateLimitPerSec < 0 {
-		return fmt.Errorf("producer.rate_limit_per_sec must be >= 0")
-	}
-	if cfg.Observability.MetricsPort <= 0 || cfg.Observability.MetricsPort > 65535 {
-		return fmt.Errorf("observability.metrics_port must be 1..65535")
-	}
-	return nil
-}
@@ -3,7 +3,10 @@ package config
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestLoadDefaults(t *testing.T) {
@@ -36,4 +39,114 @@ func TestValidateFails(t *testing.T) {
 	if err := Validate(cfg); err == nil {
 		t.Fatalf("expected error for brpoplpush_timeout > heartbeat_ttl/2")
 	}
+	cfg = defaultConfig()
+	cfg.Worker.Mode = "pubsub"
+	if err := Validate(cfg); err == nil {
+		t.Fatalf("expected error for unknown worker.mode")
+	}
+	cfg = defaultConfig()
+	cfg.Worker.Mode = ModeStream
+	cfg.Worker.Stream.Group = ""
+	if err := Validate(cfg); err == nil {
+		t.Fatalf("expected error for stream mode without a group")
+	}
+	cfg = defaultConfig()
+	cfg.Worker.Dedup.Queues = []string{"urgent"}
+	if err := Validate(cfg); err == nil {
+		t.Fatalf("expected error for dedup on an unknown priority")
+	}
+	cfg.Worker.Dedup.Queues = []string{"high"}
+	cfg.Worker.Mode = ModeStream
+	if err := Validate(cfg); err == nil {
+		t.Fatalf("expected error for dedup in stream mode")
+	}
+	cfg = defaultConfig()
+	cfg.Worker.Coalesce.Queues = []string{"high"}
+	cfg.Worker.Coalesce.TTL = 0
+	if err := Validate(cfg); err == nil {
+		t.Fatalf("expected error for coalescing without a ttl")
+	}
+	cfg = defaultConfig()
+	cfg.Worker.ReclaimBackoff.Max = cfg.Worker.ReclaimBackoff.Base / 2
+	if err := Validate(cfg); err == nil {
+		t.Fatalf("expected error for reclaim_backoff.max below base")
+	}
+	cfg = defaultConfig()
+	cfg.Worker.Autoscale.Enabled = true
+	cfg.Worker.Autoscale.MaxConcurrency = 0
+	if err := Validate(cfg); err == nil {
+		t.Fatalf("expected error for autoscale.max_concurrency below min_concurrency")
+	}
+	cfg = defaultConfig()
+	cfg.Producer.Compression.Codec = "lz4"
+	if err := Validate(cfg); err == nil {
+		t.Fatalf("expected error for unknown compression codec")
+	}
+	cfg = defaultConfig()
+	cfg.Observability.Tracing.Protocol = "thrift"
+	if err := Validate(cfg); err == nil {
+		t.Fatalf("expected error for unknown tracing protocol")
+	}
+	cfg = defaultConfig()
+	cfg.Observability.Tracing.MaxExportBatchSize = cfg.Observability.Tracing.MaxQueueSize + 1
+	if err := Validate(cfg); err == nil {
+		t.Fatalf("expected error for export batch larger than the span queue")
+	}
+}
+
+func TestLoadEnvOverridesNestedKeys(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	yaml := "redis:\n  addr: file:6379\nworker:\n  count: 4\n  circuit_breaker:\n    open_timeout: 10s\n"
+	if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("WORKQUEUE_REDIS_ADDR", "env:6379")
+	t.Setenv("WORKQUEUE_REDIS_PASSWORD", "hunter2")
+	t.Setenv("WORKQUEUE_WORKER_CIRCUIT_BREAKER_OPEN_TIMEOUT", "1m30s")
+	t.Setenv("WORKQUEUE_WORKER_BACKOFF_MAX", "2s")
+	t.Setenv("WORKQUEUE_OBSERVABILITY_TRACING_ENABLED", "1")
+	t.Setenv("WORKQUEUE_CIRCUIT_BREAKER_FAILURE_THRESHOLD", "0.25")
+	t.Setenv("WORKQUEUE_WORKER_PRIORITIES", "high, low")
+	t.Setenv("WORKQUEUE_WORKER_QUEUES", "high=jobqueue:h,low=jobqueue:l")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Redis.Addr != "env:6379" || cfg.Redis.Password != "hunter2" {
+		t.Fatalf("env should beat file and fill keys without defaults: %+v", cfg.Redis)
+	}
+	if cfg.Worker.Count != 4 {
+		t.Fatalf("file value should beat default, got %d", cfg.Worker.Count)
+	}
+	if cfg.Worker.CircuitBreaker.OpenTimeout != 90*time.Second || cfg.Worker.Backoff.Max != 2*time.Second {
+		t.Fatalf("durations not coerced: %v %v", cfg.Worker.CircuitBreaker.OpenTimeout, cfg.Worker.Backoff.Max)
+	}
+	if !cfg.Observability.Tracing.Enabled || cfg.CircuitBreaker.FailureThreshold != 0.25 {
+		t.Fatalf("bool/float not coerced: %+v %+v", cfg.Observability.Tracing, cfg.CircuitBreaker)
+	}
+	if len(cfg.Worker.Priorities) != 2 || cfg.Worker.Priorities[1] != "low" || cfg.Worker.Queues["low"] != "jobqueue:l" {
+		t.Fatalf("list/map not coerced: %v %v", cfg.Worker.Priorities, cfg.Worker.Queues)
+	}
+}
+
+func TestLoadEnvOverridesRejectMalformedValues(t *testing.T) {
+	t.Setenv("WORKQUEUE_WORKER_HEARTBEAT_TTL", "30")
+	t.Setenv("WORKQUEUE_WORKER_COUNT", "many")
+	_, err := Load("nonexistent.yaml")
+	if err == nil {
+		t.Fatal("expected error for malformed env values")
+	}
+	for _, want := range []string{"WORKQUEUE_WORKER_HEARTBEAT_TTL", "invalid duration", "WORKQUEUE_WORKER_COUNT", "invalid integer"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("error %q should mention %q", err, want)
+		}
+	}
+}
+
+func TestEnvVarName(t *testing.T) {
+	if got := EnvVarName("worker.circuit_breaker.open_timeout"); got != "WORKQUEUE_WORKER_CIRCUIT_BREAKER_OPEN_TIMEOUT" {
+		t.Fatalf("got %s", got)
+	}
 }
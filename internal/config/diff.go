@@ -0,0 +1,35 @@
+// Copyright 2025 James Ross
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Diff returns the keys, dotted as in the config file (worker.count,
+// redis.addr), whose values differ between a and b, in field order.
+// Sections are compared key by key; maps and lists are compared whole.
+func Diff(a, b *Config) []string {
+	var keys []string
+	diffSection(reflect.ValueOf(*a), reflect.ValueOf(*b), "", &keys)
+	return keys
+}
+
+func diffSection(a, b reflect.Value, prefix string, keys *[]string) {
+	t := a.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := strings.Split(f.Tag.Get("mapstructure"), ",")[0]
+		if tag == "" || tag == "-" || !f.IsExported() {
+			continue
+		}
+		key := prefix + tag
+		if f.Type.Kind() == reflect.Struct && f.Type != durationType {
+			diffSection(a.Field(i), b.Field(i), key+".", keys)
+			continue
+		}
+		if !reflect.DeepEqual(a.Field(i).Interface(), b.Field(i).Interface()) {
+			*keys = append(*keys, key)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2025 James Ross
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// EnvPrefix starts every environment variable that overrides a config key.
+const EnvPrefix = "WORKQUEUE"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// EnvVarName returns the environment variable that overrides a config key:
+// the key upper-cased with dots replaced by underscores, behind EnvPrefix.
+// For example "worker.circuit_breaker.open_timeout" becomes
+// WORKQUEUE_WORKER_CIRCUIT_BREAKER_OPEN_TIMEOUT.
+func EnvVarName(key string) string {
+	return EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// envKey is one overridable leaf of Config.
+type envKey struct {
+	key string
+	typ reflect.Type
+}
+
+// configKeys lists the dotted mapstructure key of every leaf field in t.
+func configKeys(t reflect.Type, prefix string) []envKey {
+	var keys []envKey
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := strings.Split(f.Tag.Get("mapstructure"), ",")[0]
+		if tag == "" || tag == "-" || !f.IsExported() {
+			continue
+		}
+		key := prefix + tag
+		if f.Type.Kind() == reflect.Struct && f.Type != durationType {
+			keys = append(keys, configKeys(f.Type, key+".")...)
+			continue
+		}
+		keys = append(keys, envKey{key: key, typ: f.Type})
+	}
+	return keys
+}
+
+// applyEnvOverrides sets every key whose environment variable is present,
+// after coercing the value to the field's type. Values set this way take
+// precedence over the config file and defaults. All malformed values are
+// reported together.
+func applyEnvOverrides(v *viper.Viper) error {
+	var errs []error
+	for _, k := range configKeys(reflect.TypeOf(Config{}), "") {
+		name := EnvVarName(k.key)
+		raw, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		val, err := parseEnvValue(k.typ, raw)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s (%s): %w", name, k.key, err))
+			continue
+		}
+		v.Set(k.key, val)
+	}
+	return errors.Join(errs...)
+}
+
+// parseEnvValue converts raw to typ. Slices are comma-separated and maps
+// are comma-separated key=value pairs.
+func parseEnvValue(typ reflect.Type, raw string) (interface{}, error) {
+	raw = strings.TrimSpace(raw)
+	if typ == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration %q (use Go syntax such as 500ms, 30s, 1m)", raw)
+		}
+		return d, nil
+	}
+	switch typ.Kind() {
+	case reflect.String:
+		return raw, nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bool %q (use true/false/1/0)", raw)
+		}
+		return b, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, typ.Bits())
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer %q", raw)
+		}
+		return n, nil
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, typ.Bits())
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", raw)
+		}
+		return f, nil
+	case reflect.Slice:
+		if typ.Elem().Kind() != reflect.String {
+			break
+		}
+		out := []string{}
+		for _, s := range strings.Split(raw, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				out = append(out, s)
+			}
+		}
+		return out, nil
+	case reflect.Map:
+		if typ.Key().Kind() != reflect.String || typ.Elem().Kind() != reflect.String {
+			break
+		}
+		out := map[string]string{}
+		for _, pair := range strings.Split(raw, ",") {
+			if pair = strings.TrimSpace(pair); pair == "" {
+				continue
+			}
+			k, val, ok := strings.Cut(pair, "=")
+			if !ok || strings.TrimSpace(k) == "" {
+				return nil, fmt.Errorf("invalid map entry %q (want key=value)", pair)
+			}
+			out[strings.TrimSpace(k)] = strings.TrimSpace(val)
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", typ)
+}
@@ -0,0 +1,77 @@
+// Copyright 2025 James Ross
+package config
+
+import "strings"
+
+// defaultKeyPrefix is the first segment of every default key, such as
+// "jobqueue:high_priority".
+const defaultKeyPrefix = "jobqueue"
+
+// Key returns key inside the config's namespace: key behind "<namespace>:",
+// or key itself when no namespace is set or key is already inside it.
+func (c *Config) Key(key string) string {
+	if c.Namespace == "" || key == "" {
+		return key
+	}
+	prefix := c.Namespace + ":"
+	if strings.HasPrefix(key, prefix) {
+		return key
+	}
+	return prefix + key
+}
+
+// DerivedKey returns the key derive builds from queueKey, such as
+// queue.PausedKey or scheduler.ScheduledKey, with the namespace in front:
+// for namespace "billing", DerivedKey(queue.PausedKey,
+// "billing:jobqueue:high") is "billing:paused:jobqueue:high", so scans and
+// purges of "billing:*" find it. Without a namespace it is derive(queueKey).
+func (c *Config) DerivedKey(derive func(queueKey string) string, queueKey string) string {
+	if c.Namespace == "" {
+		return derive(queueKey)
+	}
+	return c.Key(derive(strings.TrimPrefix(queueKey, c.Namespace+":")))
+}
+
+// ApplyNamespace moves every Redis key the config names into its
+// namespace, so that queue systems with different namespaces can share
+// one Redis without seeing each other's jobs: the worker.queues keys,
+// completed and dead letter lists, processing list and heartbeat key
+// patterns, completion stream and channel, the producer's rate limit key
+// and overflow queue key, and the cluster_routes entries that are keys or
+// key prefixes rather than priorities. Keys derived from a queue key, such
+// as paused: or scheduled:, are built with DerivedKey. Load applies it;
+// calling it again is harmless.
+func (c *Config) ApplyNamespace() {
+	if c.Namespace == "" {
+		return
+	}
+	w := &c.Worker
+	for prio, key := range w.Queues {
+		w.Queues[prio] = c.Key(key)
+	}
+	w.CompletedList = c.Key(w.CompletedList)
+	w.DeadLetterList = c.Key(w.DeadLetterList)
+	w.ProcessingListPattern = c.Key(w.ProcessingListPattern)
+	w.HeartbeatKeyPattern = c.Key(w.HeartbeatKeyPattern)
+	w.CompletionStream.Stream = c.Key(w.CompletionStream.Stream)
+	w.CompletionStream.Channel = c.Key(w.CompletionStream.Channel)
+
+	p := &c.Producer
+	p.RateLimitKey = c.Key(p.RateLimitKey)
+	if oq := p.Backpressure.OverflowQueue; oq != "" {
+		if _, alias := w.Queues[oq]; !alias {
+			p.Backpressure.OverflowQueue = c.Key(oq)
+		}
+	}
+
+	if len(c.ClusterRoutes) > 0 {
+		routes := make(map[string]string, len(c.ClusterRoutes))
+		for route, name := range c.ClusterRoutes {
+			if _, alias := w.Queues[route]; !alias {
+				route = c.Key(route)
+			}
+			routes[route] = name
+		}
+		c.ClusterRoutes = routes
+	}
+}
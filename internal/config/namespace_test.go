@@ -0,0 +1,87 @@
+// Copyright 2025 James Ross
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadAppliesNamespace(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	yaml := "namespace: billing\n" +
+		"producer:\n  backpressure:\n    policy: overflow\n    overflow_queue: jobqueue:overflow\n" +
+		"cluster_routes:\n  high: default\n  jobqueue:bulk*: default\n"
+	if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, got := range map[string]string{
+		"queues.high":     cfg.Worker.Queues["high"],
+		"completed":       cfg.Worker.CompletedList,
+		"dead_letter":     cfg.Worker.DeadLetterList,
+		"processing":      cfg.Worker.ProcessingListPattern,
+		"heartbeat":       cfg.Worker.HeartbeatKeyPattern,
+		"rate_limit_key":  cfg.Producer.RateLimitKey,
+		"overflow_queue":  cfg.Producer.Backpressure.OverflowQueue,
+		"definitions key": cfg.Key("jobqueue:queue_defs"),
+	} {
+		if !strings.HasPrefix(got, "billing:") {
+			t.Fatalf("%s = %q, want it under billing:", name, got)
+		}
+	}
+	if _, ok := cfg.ClusterRoutes["high"]; !ok {
+		t.Fatalf("priority route should stay a priority: %v", cfg.ClusterRoutes)
+	}
+	if _, ok := cfg.ClusterRoutes["billing:jobqueue:bulk*"]; !ok {
+		t.Fatalf("prefix route should move into the namespace: %v", cfg.ClusterRoutes)
+	}
+
+	before := cfg.Worker.Queues["high"]
+	cfg.ApplyNamespace()
+	if cfg.Worker.Queues["high"] != before || cfg.Key(before) != before {
+		t.Fatalf("applying the namespace twice changed %q to %q", before, cfg.Worker.Queues["high"])
+	}
+}
+
+func TestKeyWithoutNamespace(t *testing.T) {
+	cfg := defaultConfig()
+	if got := cfg.Key("jobqueue:high_priority"); got != "jobqueue:high_priority" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestValidateNamespace(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Namespace = "app:*"
+	if p := problemAt(Validate(cfg), "namespace"); p == nil {
+		t.Fatal("expected a namespace problem for glob characters")
+	}
+	cfg.Namespace = "jobqueue"
+	if p := problemAt(Validate(cfg), "namespace"); p == nil {
+		t.Fatal("expected a namespace problem for the default key prefix")
+	}
+	cfg.Namespace = "billing-v2.eu_1"
+	if p := problemAt(Validate(cfg), "namespace"); p != nil {
+		t.Fatalf("unexpected problem: %v", p)
+	}
+}
+
+func TestDerivedKeyPutsTheNamespaceFirst(t *testing.T) {
+	cfg := defaultConfig()
+	paused := func(q string) string { return "paused:" + q }
+	if got := cfg.DerivedKey(paused, "jobqueue:high_priority"); got != "paused:jobqueue:high_priority" {
+		t.Fatalf("without a namespace got %q", got)
+	}
+	cfg.Namespace = "billing"
+	for _, q := range []string{"billing:jobqueue:high_priority", "jobqueue:high_priority"} {
+		if got := cfg.DerivedKey(paused, q); got != "billing:paused:jobqueue:high_priority" {
+			t.Fatalf("DerivedKey(%q) = %q", q, got)
+		}
+	}
+}
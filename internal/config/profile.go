@@ -0,0 +1,104 @@
+// Copyright 2025 James Ross
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// ProfileEnv names the environment variable selecting the profile Load
+// overlays on the base config file when no profile is passed explicitly.
+const ProfileEnv = EnvPrefix + "_PROFILE"
+
+// LoadProfile is Load with profile's overlay file (see OverlayPath)
+// deep-merged over the base file: nested sections merge key by key, and
+// wherever both set a value, including a whole list, the overlay wins. Env
+// overrides still apply last. The base file stays optional, but a named
+// profile whose overlay does not exist is an error, so a typo cannot
+// silently run with the base settings. An empty profile loads the base
+// file alone.
+func LoadProfile(path, profile string) (*Config, error) {
+	return load(path, profile, false)
+}
+
+// LoadStrictProfile is LoadStrict with profile's overlay merged as for
+// LoadProfile; unknown keys are reported from both files.
+func LoadStrictProfile(path, profile string) (*Config, error) {
+	return load(path, profile, true)
+}
+
+// OverlayPath returns the overlay file for profile next to the base config
+// at path: the profile name goes before the extension, so prod's overlay
+// for config/config.yaml is config/config.prod.yaml.
+func OverlayPath(path, profile string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + profile + ext
+}
+
+// overlayFile checks profile and returns its overlay path, or "" when no
+// profile is selected.
+func overlayFile(path, profile string) (string, error) {
+	profile = strings.TrimSpace(profile)
+	if profile == "" {
+		return "", nil
+	}
+	if strings.ContainsAny(profile, `/\.`) {
+		return "", fmt.Errorf("invalid profile %q: must not contain '/', '\\' or '.'", profile)
+	}
+	overlay := OverlayPath(path, profile)
+	if _, err := os.Stat(overlay); err != nil {
+		return "", fmt.Errorf("profile %q: %w", profile, err)
+	}
+	return overlay, nil
+}
+
+// maskedValue stands in for secrets in Effective.
+const maskedValue = "********"
+
+// Effective returns cfg as a document keyed like the config file, with
+// durations in Go syntax (30s, 5m0s) and passwords and exporter headers
+// masked, for printing the configuration a process actually runs with.
+func Effective(cfg *Config) map[string]interface{} {
+	return effectiveSection(reflect.ValueOf(*cfg))
+}
+
+func effectiveSection(v reflect.Value) map[string]interface{} {
+	out := make(map[string]interface{})
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := strings.Split(f.Tag.Get("mapstructure"), ",")[0]
+		if tag == "" || tag == "-" || !f.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+		switch {
+		case f.Type == durationType:
+			out[tag] = fv.Interface().(fmt.Stringer).String()
+		case f.Type.Kind() == reflect.Struct:
+			out[tag] = effectiveSection(fv)
+		case f.Type.Kind() == reflect.Map && f.Type.Elem().Kind() == reflect.Struct:
+			sections := make(map[string]interface{}, fv.Len())
+			iter := fv.MapRange()
+			for iter.Next() {
+				sections[fmt.Sprint(iter.Key().Interface())] = effectiveSection(iter.Value())
+			}
+			out[tag] = sections
+		case f.Type.Kind() == reflect.String && strings.Contains(tag, "password") && fv.String() != "":
+			out[tag] = maskedValue
+		case tag == "headers" && f.Type.Kind() == reflect.Map && fv.Len() > 0:
+			// exporter headers usually carry API keys
+			masked := make(map[string]interface{}, fv.Len())
+			for _, k := range fv.MapKeys() {
+				masked[fmt.Sprint(k.Interface())] = maskedValue
+			}
+			out[tag] = masked
+		default:
+			out[tag] = fv.Interface()
+		}
+	}
+	return out
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 James Ross
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeProfileFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return filepath.Join(dir, "config.yaml")
+}
+
+func TestLoadProfileDeepMergesOverlay(t *testing.T) {
+	path := writeProfileFiles(t, map[string]string{
+		"config.yaml": "redis:\n  addr: base:6379\n  db: 2\nworker:\n  count: 4\n  heartbeat_ttl: 20s\n" +
+			"  queues:\n    high: jobqueue:h\n    low: jobqueue:l\nproducer:\n  include_globs: ['*.csv', '*.json']\n",
+		"config.prod.yaml": "redis:\n  addr: prod:6379\nworker:\n  count: 32\nproducer:\n  include_globs: ['*.parquet']\n",
+	})
+	if got := OverlayPath(path, "prod"); got != filepath.Join(filepath.Dir(path), "config.prod.yaml") {
+		t.Fatalf("unexpected overlay path %s", got)
+	}
+
+	cfg, err := LoadProfile(path, "prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Redis.Addr != "prod:6379" || cfg.Redis.DB != 2 {
+		t.Fatalf("overlay should win and base keys survive: %+v", cfg.Redis)
+	}
+	if cfg.Worker.Count != 32 || cfg.Worker.HeartbeatTTL != 20*time.Second || cfg.Worker.Queues["low"] != "jobqueue:l" {
+		t.Fatalf("unexpected worker: %+v", cfg.Worker)
+	}
+	if len(cfg.Producer.IncludeGlobs) != 1 || cfg.Producer.IncludeGlobs[0] != "*.parquet" {
+		t.Fatalf("overlay lists should replace base lists, got %v", cfg.Producer.IncludeGlobs)
+	}
+
+	t.Setenv(ProfileEnv, "prod")
+	t.Setenv("WORKQUEUE_WORKER_COUNT", "8")
+	if cfg, err = Load(path); err != nil || cfg.Redis.Addr != "prod:6379" || cfg.Worker.Count != 8 {
+		t.Fatalf("expected the env profile with env overrides on top, got %+v, %v", cfg, err)
+	}
+	if cfg, err = LoadProfile(path, ""); err != nil || cfg.Redis.Addr != "base:6379" {
+		t.Fatalf("expected the base file alone, got %+v, %v", cfg, err)
+	}
+}
+
+func TestLoadProfileRejectsMissingOrInvalidProfiles(t *testing.T) {
+	path := writeProfileFiles(t, map[string]string{
+		"config.yaml":         "worker:\n  count: 4\n",
+		"config.staging.yaml": "worker:\n  cuont: 4\n",
+	})
+	if _, err := LoadProfile(path, "prdo"); err == nil || !strings.Contains(err.Error(), `profile "prdo"`) {
+		t.Fatalf("expected a missing overlay to fail, got %v", err)
+	}
+	if _, err := LoadProfile(path, "../prod"); err == nil {
+		t.Fatal("expected a profile with a path separator to be rejected")
+	}
+	if _, err := LoadProfile(path, "staging"); err != nil {
+		t.Fatalf("Load should not check overlays for unknown keys: %v", err)
+	}
+	_, err := LoadStrictProfile(path, "staging")
+	if p := problemAt(err, "worker.cuont"); p == nil || !strings.Contains(p.Message, "config.staging.yaml") {
+		t.Fatalf("expected the overlay typo to be reported, got %v", err)
+	}
+}
+
+func TestEffectiveMasksPasswordsAndFormatsDurations(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Redis.Password = "hunter2"
+	cfg.Clusters = map[string]Redis{"eu": {Addr: "eu:6379", Password: "x"}}
+	doc := Effective(cfg)
+
+	redis := doc["redis"].(map[string]interface{})
+	if redis["password"] != "********" || redis["dial_timeout"] != "5s" || redis["addr"] != "localhost:6379" {
+		t.Fatalf("unexpected redis section: %+v", redis)
+	}
+	eu := doc["clusters"].(map[string]interface{})["eu"].(map[string]interface{})
+	if eu["password"] != "********" || eu["addr"] != "eu:6379" {
+		t.Fatalf("unexpected cluster section: %+v", eu)
+	}
+	if doc["worker"].(map[string]interface{})["backoff"].(map[string]interface{})["base"] == nil {
+		t.Fatal("expected nested sections")
+	}
+}
+
+func TestDiffReportsChangedKeys(t *testing.T) {
+	a, b := defaultConfig(), defaultConfig()
+	if keys := Diff(a, b); len(keys) != 0 {
+		t.Fatalf("expected no differences, got %v", keys)
+	}
+	b.Redis.Addr = "other:6379"
+	b.Worker.Count = a.Worker.Count + 1
+	b.Worker.Backoff.Max = a.Worker.Backoff.Max * 2
+	b.Worker.QueueRateLimits = map[string]float64{"low": 5}
+	got := strings.Join(Diff(a, b), ",")
+	if got != "redis.addr,worker.count,worker.backoff.max,worker.queue_rate_limits" {
+		t.Fatalf("unexpected keys %s", got)
+	}
+}
@@ -0,0 +1,632 @@
+// Copyright 2025 James Ross
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// FieldError is one invalid setting, located by its YAML path.
+type FieldError struct {
+	Path       string // dotted YAML path, e.g. worker.heartbeat_ttl
+	Message    string
+	Suggestion string // how to fix it, when there is an obvious fix
+}
+
+func (e FieldError) Error() string {
+	if e.Suggestion == "" {
+		return e.Path + ": " + e.Message
+	}
+	return fmt.Sprintf("%s: %s (%s)", e.Path, e.Message, e.Suggestion)
+}
+
+// ValidationError lists every problem found in a config, so one run shows
+// everything that needs fixing.
+type ValidationError struct {
+	Problems []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Problems) == 1 {
+		return "invalid config: " + e.Problems[0].Error()
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid config: %d problems", len(e.Problems))
+	for _, p := range e.Problems {
+		b.WriteString("\n  - ")
+		b.WriteString(p.Error())
+	}
+	return b.String()
+}
+
+// Unwrap exposes each problem to errors.As.
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Problems))
+	for i, p := range e.Problems {
+		errs[i] = p
+	}
+	return errs
+}
+
+// Validate checks config constraints. It returns a *ValidationError listing
+// every invalid setting, or nil.
+func Validate(cfg *Config) error {
+	if problems := validate(cfg); len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// checker collects problems instead of stopping at the first one.
+type checker struct {
+	problems []FieldError
+}
+
+func (c *checker) add(path, message, suggestion string) {
+	c.problems = append(c.problems, FieldError{Path: path, Message: message, Suggestion: suggestion})
+}
+
+// oneOf reports value unless it is one of allowed, suggesting the closest.
+func (c *checker) oneOf(path, value string, allowed ...string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	c.add(path, fmt.Sprintf("must be one of %s, got %q", strings.Join(allowed, ", "), value), didYouMean(value, allowed))
+}
+
+func (c *checker) nonNegative(path string, d time.Duration) {
+	if d < 0 {
+		c.add(path, fmt.Sprintf("must be >= 0, got %s", d), "")
+	}
+}
+
+func (c *checker) positive(path string, d time.Duration) {
+	if d <= 0 {
+		c.add(path, fmt.Sprintf("must be > 0, got %s", d), "set a duration such as 1s or 500ms")
+	}
+}
+
+func validate(cfg *Config) []FieldError {
+	c := &checker{}
+	validateNamespace(c, cfg.Namespace)
+	validateRedis(c, &cfg.Redis)
+	validateWorker(c, &cfg.Worker)
+	validateProducer(c, &cfg.Producer, &cfg.Worker)
+	validateCircuitBreaker(c, &cfg.CircuitBreaker)
+	validateObservability(c, &cfg.Observability)
+	validateClusters(c, cfg)
+	return c.problems
+}
+
+// validateNamespace keeps the namespace to characters that are literal in
+// a SCAN match, so scans scoped to it cannot reach other namespaces, and
+// rejects the prefix the default keys already start with: Key leaves keys
+// already inside the namespace alone, so it would change nothing.
+func validateNamespace(c *checker, ns string) {
+	for _, r := range ns {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			c.add("namespace", fmt.Sprintf("%q contains %q", ns, r), `use letters, digits, "-", "_" or ".", e.g. "billing"`)
+			return
+		}
+	}
+	if ns == defaultKeyPrefix {
+		c.add("namespace", fmt.Sprintf("%q is the prefix of the default keys, so it would not separate this system from one without a namespace", ns), `pick a name of its own, e.g. "billing"`)
+	}
+}
+
+func validateRedis(c *checker, r *Redis) {
+	checkAddr(c, "redis.addr", r.Addr)
+	if r.DB < 0 {
+		c.add("redis.db", fmt.Sprintf("must be >= 0, got %d", r.DB), "")
+	}
+	if r.PoolSizeMultiplier < 1 {
+		c.add("redis.pool_size_multiplier", fmt.Sprintf("must be >= 1, got %d", r.PoolSizeMultiplier), "")
+	}
+	if r.MinIdleConns < 0 {
+		c.add("redis.min_idle_conns", fmt.Sprintf("must be >= 0, got %d", r.MinIdleConns), "")
+	}
+	c.nonNegative("redis.dial_timeout", r.DialTimeout)
+}
+
+// checkAddr reports addr at path unless it is a usable host:port.
+func checkAddr(c *checker, path, addr string) {
+	if addr == "" {
+		c.add(path, "is required", `use host:port, e.g. "localhost:6379"`)
+	} else if host, port, err := net.SplitHostPort(addr); err != nil {
+		hint := `use host:port, e.g. "localhost:6379"`
+		if i := strings.Index(addr, "://"); i >= 0 {
+			hint = fmt.Sprintf("drop the scheme: %q", addr[i+3:])
+		} else if !strings.Contains(addr, ":") {
+			hint = fmt.Sprintf("add the port: %q", net.JoinHostPort(addr, "6379"))
+		}
+		c.add(path, fmt.Sprintf("%q is not a host:port address", addr), hint)
+	} else {
+		if host == "" {
+			c.add(path, fmt.Sprintf("%q has no host", addr), fmt.Sprintf("e.g. %q", net.JoinHostPort("localhost", port)))
+		}
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			c.add(path, fmt.Sprintf("port %q must be 1..65535", port), "Redis listens on 6379 by default")
+		}
+	}
+}
+
+// validateClusters checks the named clusters and the routes to them.
+func validateClusters(c *checker, cfg *Config) {
+	names := make([]string, 0, len(cfg.Clusters))
+	for name := range cfg.Clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		r := cfg.Clusters[name]
+		path := "clusters." + name
+		if name == DefaultCluster {
+			c.add(path, fmt.Sprintf("%q names the top-level redis section", DefaultCluster), "pick another name")
+			continue
+		}
+		checkAddr(c, path+".addr", r.Addr)
+		if r.DB < 0 {
+			c.add(path+".db", fmt.Sprintf("must be >= 0, got %d", r.DB), "")
+		}
+	}
+
+	routes := make([]string, 0, len(cfg.ClusterRoutes))
+	for route := range cfg.ClusterRoutes {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+	for _, route := range routes {
+		name := cfg.ClusterRoutes[route]
+		if _, ok := cfg.Clusters[name]; !ok && name != DefaultCluster {
+			c.add("cluster_routes."+route, fmt.Sprintf("unknown cluster %q", name), didYouMean(name, append(names, DefaultCluster)))
+		}
+	}
+	if len(routes) > 0 && cfg.Worker.Mode == ModeStream {
+		c.add("cluster_routes", fmt.Sprintf("requires worker.mode %q", ModeList), "stream mode reads every queue in one XREADGROUP on one instance")
+	}
+}
+
+func validateWorker(c *checker, w *Worker) {
+	if w.Count < 1 {
+		c.add("worker.count", fmt.Sprintf("must be >= 1, got %d", w.Count), "")
+	}
+	if w.MaxRetries < 0 {
+		c.add("worker.max_retries", fmt.Sprintf("must be >= 0, got %d", w.MaxRetries), "")
+	}
+	c.positive("worker.backoff.base", w.Backoff.Base)
+	if w.Backoff.Max < w.Backoff.Base {
+		c.add("worker.backoff.max", fmt.Sprintf("must be >= backoff.base (%s), got %s", w.Backoff.Base, w.Backoff.Max), "")
+	}
+
+	if len(w.Priorities) == 0 {
+		c.add("worker.priorities", "must be non-empty", `e.g. ["high", "low"]`)
+	}
+	queueNames := make([]string, 0, len(w.Queues))
+	for name := range w.Queues {
+		queueNames = append(queueNames, name)
+	}
+	sort.Strings(queueNames)
+	seen := make(map[string]bool, len(w.Priorities))
+	for _, p := range w.Priorities {
+		if seen[p] {
+			c.add("worker.priorities", fmt.Sprintf("lists %q more than once", p), "")
+			continue
+		}
+		seen[p] = true
+		if _, ok := w.Queues[p]; !ok {
+			hint := didYouMean(p, queueNames)
+			if hint == "" {
+				hint = fmt.Sprintf("add worker.queues.%s", p)
+			}
+			c.add("worker.queues", fmt.Sprintf("missing entry for priority %q", p), hint)
+		}
+	}
+	for _, name := range queueNames {
+		if w.Queues[name] == "" {
+			c.add("worker.queues."+name, "must name a Redis key", "")
+		}
+	}
+
+	if !strings.Contains(w.ProcessingListPattern, "%s") {
+		c.add("worker.processing_list_pattern", "must contain %s for the worker ID", `e.g. "jobqueue:worker:%s:processing"`)
+	}
+	if !strings.Contains(w.HeartbeatKeyPattern, "%s") {
+		c.add("worker.heartbeat_key_pattern", "must contain %s for the worker ID", `e.g. "jobqueue:processing:worker:%s"`)
+	}
+	if w.CompletedList == "" {
+		c.add("worker.completed_list", "is required", "")
+	}
+	if w.DeadLetterList == "" {
+		c.add("worker.dead_letter_list", "is required", "")
+	}
+
+	if w.HeartbeatTTL < 5*time.Second {
+		c.add("worker.heartbeat_ttl", fmt.Sprintf("must be >= 5s, got %s", w.HeartbeatTTL), "")
+	}
+	if w.BRPopLPushTimeout <= 0 || w.BRPopLPushTimeout > w.HeartbeatTTL/2 {
+		c.add("worker.brpoplpush_timeout", fmt.Sprintf("must be >0 and <= heartbeat_ttl/2, got %s", w.BRPopLPushTimeout),
+			fmt.Sprintf("with heartbeat_ttl %s use at most %s", w.HeartbeatTTL, w.HeartbeatTTL/2))
+	}
+	c.nonNegative("worker.breaker_pause", w.BreakerPause)
+	c.nonNegative("worker.pause_cache_ttl", w.PauseCacheTTL)
+
+	wb := w.CircuitBreaker
+	if wb.FailureThreshold < 0 || wb.FailureThreshold > 1 {
+		c.add("worker.circuit_breaker.failure_threshold", fmt.Sprintf("must be within 0..1, got %g", wb.FailureThreshold), "0.5 opens at a 50% failure rate")
+	}
+	if wb.MinRequests < 0 {
+		c.add("worker.circuit_breaker.min_requests", fmt.Sprintf("must be >= 0, got %d", wb.MinRequests), "")
+	}
+	c.nonNegative("worker.circuit_breaker.window", wb.Window)
+	c.nonNegative("worker.circuit_breaker.open_timeout", wb.OpenTimeout)
+	if wb.RequeueDelay < 0 || wb.RequeueDelay >= w.HeartbeatTTL {
+		c.add("worker.circuit_breaker.requeue_delay", fmt.Sprintf("must be >= 0 and < heartbeat_ttl (%s), got %s", w.HeartbeatTTL, wb.RequeueDelay), "")
+	}
+
+	c.oneOf("worker.mode", w.Mode, ModeList, ModeStream)
+	c.oneOf("worker.unclassified_errors", w.UnclassifiedErrors, UnclassifiedRetry, UnclassifiedDeadLetter)
+	if w.Mode == ModeStream {
+		if w.Stream.Group == "" {
+			c.add("worker.stream.group", "is required in stream mode", `e.g. "workers"`)
+		}
+		c.positive("worker.stream.claim_idle", w.Stream.ClaimIdle)
+		c.positive("worker.stream.claim_interval", w.Stream.ClaimInterval)
+		if w.Stream.MaxLen < 0 {
+			c.add("worker.stream.max_len", fmt.Sprintf("must be >= 0, got %d", w.Stream.MaxLen), "0 keeps every entry")
+		}
+	}
+
+	if len(w.Dedup.Queues) > 0 {
+		if w.Mode != ModeList {
+			c.add("worker.dedup.queues", fmt.Sprintf("requires worker.mode %q", ModeList), "clear dedup.queues or switch to list mode")
+		}
+		if w.Dedup.TTL <= 0 {
+			c.add("worker.dedup.ttl", fmt.Sprintf("must be > 0, got %s", w.Dedup.TTL), "")
+		}
+		for _, p := range w.Dedup.Queues {
+			if _, ok := w.Queues[p]; !ok {
+				c.add("worker.dedup.queues", fmt.Sprintf("unknown priority %q", p), didYouMean(p, w.Priorities))
+			}
+		}
+	}
+
+	if len(w.Coalesce.Queues) > 0 {
+		if w.Mode != ModeList {
+			c.add("worker.coalesce.queues", fmt.Sprintf("requires worker.mode %q", ModeList), "clear coalesce.queues or switch to list mode")
+		}
+		if w.Coalesce.TTL <= 0 {
+			c.add("worker.coalesce.ttl", fmt.Sprintf("must be > 0, got %s", w.Coalesce.TTL), "")
+		}
+		for _, p := range w.Coalesce.Queues {
+			if _, ok := w.Queues[p]; !ok {
+				c.add("worker.coalesce.queues", fmt.Sprintf("unknown priority %q", p), didYouMean(p, w.Priorities))
+			}
+		}
+	}
+
+	rb := w.ReclaimBackoff
+	if rb.Threshold < 0 {
+		c.add("worker.reclaim_backoff.threshold", fmt.Sprintf("must be >= 0, got %d", rb.Threshold), "")
+	}
+	c.nonNegative("worker.reclaim_backoff.base", rb.Base)
+	if rb.Max < rb.Base {
+		c.add("worker.reclaim_backoff.max", fmt.Sprintf("must be >= reclaim_backoff.base (%s), got %s", rb.Base, rb.Max), "")
+	}
+
+	rp := w.Reaper
+	c.positive("worker.reaper.interval", rp.Interval)
+	if rp.ScanCount < 1 {
+		c.add("worker.reaper.scan_count", fmt.Sprintf("must be >= 1, got %d", rp.ScanCount), "")
+	}
+	if rp.MaxOpsPerSecond < 0 {
+		c.add("worker.reaper.max_ops_per_second", fmt.Sprintf("must be >= 0, got %g", rp.MaxOpsPerSecond), "0 means no limit")
+	}
+	c.nonNegative("worker.reaper.grace_period", rp.GracePeriod)
+
+	cr := w.CompletedRetention
+	if cr.KeepLast < 0 {
+		c.add("worker.completed_retention.keep_last", fmt.Sprintf("must be >= 0, got %d", cr.KeepLast), "0 keeps any number")
+	}
+	c.nonNegative("worker.completed_retention.max_age", cr.MaxAge)
+	if cr.KeepLast > 0 || cr.MaxAge > 0 {
+		c.positive("worker.completed_retention.interval", cr.Interval)
+	}
+
+	if as := w.Autoscale; as.Enabled {
+		if as.MinConcurrency < 1 {
+			c.add("worker.autoscale.min_concurrency", fmt.Sprintf("must be >= 1, got %d", as.MinConcurrency), "")
+		}
+		if as.MaxConcurrency < as.MinConcurrency {
+			c.add("worker.autoscale.max_concurrency", fmt.Sprintf("must be >= min_concurrency (%d), got %d", as.MinConcurrency, as.MaxConcurrency), "")
+		}
+		c.positive("worker.autoscale.interval", as.Interval)
+		if as.BacklogPerWorker < 1 {
+			c.add("worker.autoscale.backlog_per_worker", fmt.Sprintf("must be >= 1, got %d", as.BacklogPerWorker), "")
+		}
+		c.nonNegative("worker.autoscale.scale_down_delay", as.ScaleDownDelay)
+	}
+
+	cs := w.CompletionStream
+	if cs.MaxLen < 0 {
+		c.add("worker.completion_stream.max_len", fmt.Sprintf("must be >= 0, got %d", cs.MaxLen), "0 keeps every entry")
+	}
+	if cs.Transactional && cs.Stream == "" && cs.Channel == "" {
+		c.add("worker.completion_stream.transactional", "has no effect without a stream or channel", `set completion_stream.stream, e.g. "jobqueue:completions"`)
+	}
+	for _, list := range []string{w.CompletedList, w.DeadLetterList} {
+		if cs.Stream != "" && cs.Stream == list {
+			c.add("worker.completion_stream.stream", fmt.Sprintf("must not reuse the list key %q", list), `e.g. "jobqueue:completions"`)
+		}
+	}
+
+	fs := w.FairScheduling
+	c.oneOf("worker.fair_scheduling.mode", fs.Mode, SchedulingStrict, SchedulingWeighted)
+	if fs.Mode == SchedulingWeighted && w.Mode == ModeStream {
+		c.add("worker.fair_scheduling.mode", "weighted scheduling needs queue lengths and only works with worker.mode list", `use "strict" in stream mode`)
+	}
+	for p, weight := range fs.Weights {
+		if _, ok := w.Queues[p]; !ok {
+			c.add("worker.fair_scheduling.weights."+p, "is not a priority in worker.queues", "")
+		}
+		if weight < 1 {
+			c.add("worker.fair_scheduling.weights."+p, fmt.Sprintf("must be >= 1, got %d", weight), "")
+		}
+	}
+
+	if sa := w.SilenceAlert; sa.Enabled {
+		c.positive("worker.silence_alert.check_interval", sa.CheckInterval)
+		c.nonNegative("worker.silence_alert.min_silence", sa.MinSilence)
+		if sa.BaselineFactor <= 1 {
+			c.add("worker.silence_alert.baseline_factor", fmt.Sprintf("must be > 1, got %g", sa.BaselineFactor), "e.g. 10 alerts after ten usual gaps without a completion")
+		}
+		if sa.BaselineWindow < sa.CheckInterval {
+			c.add("worker.silence_alert.baseline_window", fmt.Sprintf("must be >= check_interval (%s), got %s", sa.CheckInterval, sa.BaselineWindow), "e.g. 24h")
+		}
+		for i, u := range sa.WebhookURLs {
+			if parsed, err := url.Parse(u); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+				c.add(fmt.Sprintf("worker.silence_alert.webhook_urls[%d]", i), fmt.Sprintf("must be an http(s) URL, got %q", u), "")
+			}
+		}
+	}
+
+	for p, rate := range w.QueueRateLimits {
+		if _, ok := w.Queues[p]; !ok {
+			c.add("worker.queue_rate_limits."+p, "is not a priority in worker.queues", didYouMean(p, w.Priorities))
+		}
+		if rate <= 0 {
+			c.add("worker.queue_rate_limits."+p, fmt.Sprintf("must be > 0 jobs per second, got %g", rate), "remove the entry for no limit")
+		}
+	}
+
+	for _, p := range w.PausedQueues {
+		if _, ok := w.Queues[p]; !ok {
+			c.add("worker.paused_queues", fmt.Sprintf("%q is not a priority in worker.queues", p), didYouMean(p, w.Priorities))
+		}
+	}
+
+	for p, qt := range w.QueueTimeouts {
+		path := "worker.queue_timeouts." + p
+		if _, ok := w.Queues[p]; !ok {
+			c.add(path, "is not a priority in worker.queues", didYouMean(p, w.Priorities))
+		}
+		c.nonNegative(path+".visibility_timeout", qt.VisibilityTimeout)
+		c.nonNegative(path+".job_timeout", qt.JobTimeout)
+		if qt.VisibilityTimeout > 0 && w.Mode == ModeStream {
+			c.add(path+".visibility_timeout", "only works with worker.mode list", "use worker.stream.claim_idle in stream mode")
+		}
+		if vis := w.Visibility(p); qt.JobTimeout > 0 && vis <= qt.JobTimeout {
+			hint := "raise visibility_timeout or lower job_timeout"
+			if qt.VisibilityTimeout <= 0 {
+				hint = "set visibility_timeout above job_timeout"
+			}
+			c.add(path+".visibility_timeout", fmt.Sprintf("must exceed job_timeout (%s), got %s", qt.JobTimeout, vis), hint)
+		}
+	}
+
+	c.positive("worker.scheduler_interval", w.SchedulerInterval)
+	if w.SchedulerBatch < 1 {
+		c.add("worker.scheduler_batch", fmt.Sprintf("must be >= 1, got %d", w.SchedulerBatch), "")
+	}
+}
+
+func validateProducer(c *checker, p *Producer, w *Worker) {
+	if p.DefaultPriority != "" && len(w.Priorities) > 0 {
+		c.oneOf("producer.default_priority", p.DefaultPriority, w.Priorities...)
+	}
+	if p.RateLimitPerSec < 0 {
+		c.add("producer.rate_limit_per_sec", fmt.Sprintf("must be >= 0, got %d", p.RateLimitPerSec), "0 disables the limit")
+	}
+	if p.RateLimitPerSec > 0 && p.RateLimitKey == "" {
+		c.add("producer.rate_limit_key", "is required when rate_limit_per_sec > 0", "")
+	}
+	if p.Compression.Codec != "" {
+		c.oneOf("producer.compression.codec", p.Compression.Codec, "none", "gzip", "zstd")
+	}
+	if p.Compression.MinSize < 0 {
+		c.add("producer.compression.min_size", fmt.Sprintf("must be >= 0, got %d", p.Compression.MinSize), "")
+	}
+
+	if p.MaxQueueLength < 0 {
+		c.add("producer.max_queue_length", fmt.Sprintf("must be >= 0, got %d", p.MaxQueueLength), "0 leaves queues unbounded")
+	}
+	if p.MaxQueueLength > 0 {
+		if w.Mode == ModeStream {
+			c.add("producer.max_queue_length", fmt.Sprintf("requires worker.mode %q", ModeList), "streams are capped with worker.stream.max_len")
+		}
+		bp := p.Backpressure
+		c.oneOf("producer.backpressure.policy", bp.Policy, BackpressureBlock, BackpressureReject, BackpressureOverflow)
+		if bp.Policy == BackpressureOverflow && bp.OverflowQueue == "" {
+			c.add("producer.backpressure.overflow_queue", "is required with policy overflow", `e.g. "jobqueue:overflow"`)
+		}
+		c.nonNegative("producer.backpressure.block_timeout", bp.BlockTimeout)
+		c.positive("producer.backpressure.poll_interval", bp.PollInterval)
+		c.nonNegative("producer.backpressure.length_cache_ttl", bp.LengthCacheTTL)
+	}
+	if p.MaxBatchSize < 1 {
+		c.add("producer.max_batch_size", fmt.Sprintf("must be >= 1, got %d", p.MaxBatchSize), "")
+	}
+
+	for prio, a := range p.PriorityAging {
+		path := "producer.priority_aging." + prio
+		if _, ok := w.Queues[prio]; !ok {
+			c.add(path, "is not a priority in worker.queues", didYouMean(prio, w.Priorities))
+		}
+		c.positive(path+".after", a.After)
+		c.positive(path+".check_interval", a.CheckInterval)
+		to := p.AgingTarget(prio, w)
+		if _, ok := w.Queues[to]; !ok {
+			c.add(path+".promote_to", fmt.Sprintf("%q is not a priority in worker.queues", to), didYouMean(to, w.Priorities))
+		} else if w.Queues[to] == w.Queues[prio] {
+			c.add(path+".promote_to", fmt.Sprintf("%q is the queue being aged", to), "name a higher priority")
+		}
+		if w.Mode == ModeStream {
+			c.add(path, fmt.Sprintf("requires worker.mode %q", ModeList), "")
+		}
+	}
+}
+
+func validateCircuitBreaker(c *checker, cb *CircuitBreaker) {
+	if cb.FailureThreshold < 0 || cb.FailureThreshold > 1 {
+		c.add("circuit_breaker.failure_threshold", fmt.Sprintf("must be within 0..1, got %g", cb.FailureThreshold), "0.5 opens at a 50% failure rate")
+	}
+	c.nonNegative("circuit_breaker.window", cb.Window)
+	c.nonNegative("circuit_breaker.cooldown_period", cb.CooldownPeriod)
+	if cb.MinSamples < 0 {
+		c.add("circuit_breaker.min_samples", fmt.Sprintf("must be >= 0, got %d", cb.MinSamples), "")
+	}
+}
+
+func validateObservability(c *checker, o *Observability) {
+	if o.MetricsPort <= 0 || o.MetricsPort > 65535 {
+		c.add("observability.metrics_port", fmt.Sprintf("must be 1..65535, got %d", o.MetricsPort), "")
+	}
+	if o.LogLevel != "" {
+		c.oneOf("observability.log_level", strings.ToLower(o.LogLevel), "debug", "info", "warn", "error")
+	}
+	c.nonNegative("observability.queue_sample_interval", o.QueueSampleInterval)
+	c.nonNegative("observability.health_check_timeout", o.HealthCheckTimeout)
+
+	tc := o.Tracing
+	if tc.Protocol != "" {
+		c.oneOf("observability.tracing.protocol", tc.Protocol, "grpc", "http", "http/protobuf")
+	}
+	if tc.SamplingStrategy != "" {
+		c.oneOf("observability.tracing.sampling_strategy", tc.SamplingStrategy, "always", "never", "probabilistic")
+	}
+	if tc.SamplingRate < 0 || tc.SamplingRate > 1 {
+		c.add("observability.tracing.sampling_rate", fmt.Sprintf("must be within 0..1, got %g", tc.SamplingRate), "")
+	}
+	c.nonNegative("observability.tracing.batch_timeout", tc.BatchTimeout)
+	if tc.MaxQueueSize < 0 {
+		c.add("observability.tracing.max_queue_size", fmt.Sprintf("must be >= 0, got %d", tc.MaxQueueSize), "")
+	}
+	if tc.MaxExportBatchSize < 0 {
+		c.add("observability.tracing.max_export_batch_size", fmt.Sprintf("must be >= 0, got %d", tc.MaxExportBatchSize), "")
+	}
+	if tc.MaxQueueSize > 0 && tc.MaxExportBatchSize > tc.MaxQueueSize {
+		c.add("observability.tracing.max_export_batch_size", fmt.Sprintf("must be <= max_queue_size (%d), got %d", tc.MaxQueueSize, tc.MaxExportBatchSize), "")
+	}
+}
+
+// reservedSections are top-level sections read outside Config; strict
+// loading leaves them alone.
+var reservedSections = []string{"exactly_once"}
+
+// unknownKeys reads the YAML file at path and reports every key that maps
+// to no Config field, suggesting the closest known key.
+func unknownKeys(path string) ([]FieldError, error) {
+	raw := viper.New()
+	raw.SetConfigFile(path)
+	raw.SetConfigType("yaml")
+	if err := raw.ReadInConfig(); err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool)
+	sections := make(map[string]bool)
+	var maps, names []string
+	for _, k := range configKeys(reflect.TypeOf(Config{}), "") {
+		known[k.key] = true
+		names = append(names, k.key)
+		if k.typ.Kind() == reflect.Map {
+			maps = append(maps, k.key+".")
+		}
+		parts := strings.Split(k.key, ".")
+		for i := 1; i < len(parts); i++ {
+			sections[strings.Join(parts[:i], ".")] = true
+		}
+	}
+
+	var problems []FieldError
+	keys := raw.AllKeys()
+	sort.Strings(keys)
+keys:
+	for _, key := range keys {
+		if known[key] {
+			continue
+		}
+		for _, prefix := range maps {
+			if strings.HasPrefix(key, prefix) {
+				continue keys
+			}
+		}
+		for _, section := range reservedSections {
+			if key == section || strings.HasPrefix(key, section+".") {
+				continue keys
+			}
+		}
+		if sections[key] {
+			problems = append(problems, FieldError{Path: key, Message: "must be a section, not a value"})
+			continue
+		}
+		problems = append(problems, FieldError{Path: key, Message: "unknown key", Suggestion: didYouMean(key, names)})
+	}
+	return problems, nil
+}
+
+// didYouMean returns a hint naming the candidate closest to s, or "" when
+// none is close enough to be a likely typo.
+func didYouMean(s string, candidates []string) string {
+	best, bestDist := "", -1
+	for _, cand := range candidates {
+		d := editDistance(strings.ToLower(s), strings.ToLower(cand))
+		if bestDist < 0 || d < bestDist {
+			best, bestDist = cand, d
+		}
+	}
+	if bestDist < 0 || bestDist > max(2, len(s)/3) {
+		return ""
+	}
+	return fmt.Sprintf("did you mean %q?", best)
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
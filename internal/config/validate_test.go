@@ -0,0 +1,322 @@
+// Copyright 2025 James Ross
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func problemAt(err error, path string) *FieldError {
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		return nil
+	}
+	for i := range verr.Problems {
+		if verr.Problems[i].Path == path {
+			return &verr.Problems[i]
+		}
+	}
+	return nil
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Worker.Count = 0
+	cfg.Worker.Mode = "strem"
+	cfg.Producer.DefaultPriority = "hihg"
+	cfg.Redis.Addr = "redis://cache:6379"
+
+	err := Validate(cfg)
+	var verr *ValidationError
+	if !errors.As(err, &verr) || len(verr.Problems) != 4 {
+		t.Fatalf("expected 4 problems, got %v", err)
+	}
+	if p := problemAt(err, "worker.mode"); p == nil || p.Suggestion != `did you mean "stream"?` {
+		t.Fatalf("worker.mode: %+v", p)
+	}
+	if p := problemAt(err, "producer.default_priority"); p == nil || p.Suggestion != `did you mean "high"?` {
+		t.Fatalf("producer.default_priority: %+v", p)
+	}
+	if p := problemAt(err, "redis.addr"); p == nil || !strings.Contains(p.Suggestion, `"cache:6379"`) {
+		t.Fatalf("redis.addr: %+v", p)
+	}
+	if !strings.Contains(err.Error(), "4 problems") || !strings.Contains(err.Error(), "worker.count: must be >= 1") {
+		t.Fatalf("unexpected message: %s", err)
+	}
+}
+
+func TestValidateRedisAddr(t *testing.T) {
+	for addr, ok := range map[string]bool{
+		"localhost:6379":  true,
+		"[::1]:6379":      true,
+		"redis:6379":      true,
+		"":                false,
+		"localhost":       false,
+		":6379":           false,
+		"localhost:0":     false,
+		"localhost:redis": false,
+	} {
+		cfg := defaultConfig()
+		cfg.Redis.Addr = addr
+		if got := problemAt(Validate(cfg), "redis.addr") == nil; got != ok {
+			t.Errorf("%q: valid = %v, want %v", addr, got, ok)
+		}
+	}
+}
+
+func TestLoadStrictReportsUnknownKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	yaml := "worker:\n  heartbeat_tll: 10s\n  queues:\n    high: jobqueue:h\n    low: jobqueue:l\n    bulk: jobqueue:b\n" +
+		"  count: 0\nobservability:\n  tracing:\n    headers:\n      x-api-key: secret\nexactly_once:\n  outbox:\n    enabled: false\n"
+	if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(path); problemAt(err, "worker.heartbeat_tll") != nil {
+		t.Fatal("Load should not check for unknown keys")
+	}
+
+	_, err := LoadStrict(path)
+	var verr *ValidationError
+	if !errors.As(err, &verr) || len(verr.Problems) != 2 {
+		t.Fatalf("expected the typo and worker.count, got %v", err)
+	}
+	if p := problemAt(err, "worker.heartbeat_tll"); p == nil || p.Suggestion != `did you mean "worker.heartbeat_ttl"?` {
+		t.Fatalf("typo: %+v", p)
+	}
+	if problemAt(err, "worker.count") == nil {
+		t.Fatal("value problems should be reported with unknown keys")
+	}
+}
+
+func TestLoadStrictAcceptsExampleConfig(t *testing.T) {
+	if _, err := LoadStrict(filepath.Join("..", "..", "config", "config.example.yaml")); err != nil {
+		t.Fatalf("example config should pass strict validation: %v", err)
+	}
+}
+
+func TestValidateCompletionStream(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Worker.CompletionStream.Transactional = true
+	if problemAt(Validate(cfg), "worker.completion_stream.transactional") == nil {
+		t.Fatal("transactional without a target should be reported")
+	}
+	cfg.Worker.CompletionStream.Stream = cfg.Worker.CompletedList
+	cfg.Worker.CompletionStream.MaxLen = -1
+	err := Validate(cfg)
+	if problemAt(err, "worker.completion_stream.stream") == nil || problemAt(err, "worker.completion_stream.max_len") == nil {
+		t.Fatalf("expected stream and max_len problems, got %v", err)
+	}
+	cfg.Worker.CompletionStream.Stream = "jobqueue:completions"
+	cfg.Worker.CompletionStream.MaxLen = 0
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("expected a valid config, got %v", err)
+	}
+}
+
+func TestValidateFairScheduling(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Worker.FairScheduling.Mode = "weigted"
+	cfg.Worker.FairScheduling.Weights = map[string]int{"high": 0, "urgent": 2}
+	err := Validate(cfg)
+	if p := problemAt(err, "worker.fair_scheduling.mode"); p == nil || p.Suggestion != `did you mean "weighted"?` {
+		t.Fatalf("mode: %+v", p)
+	}
+	if problemAt(err, "worker.fair_scheduling.weights.high") == nil || problemAt(err, "worker.fair_scheduling.weights.urgent") == nil {
+		t.Fatalf("expected weight problems, got %v", err)
+	}
+	cfg.Worker.FairScheduling = WorkerFairScheduling{Mode: SchedulingWeighted, Weights: map[string]int{"high": 4, "low": 1}}
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("expected a valid config, got %v", err)
+	}
+	cfg.Worker.Mode = ModeStream
+	if problemAt(Validate(cfg), "worker.fair_scheduling.mode") == nil {
+		t.Fatal("weighted scheduling in stream mode should be reported")
+	}
+}
+
+func TestValidateQueueRateLimits(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Worker.QueueRateLimits = map[string]float64{"high": 0, "hgh": 5}
+	err := Validate(cfg)
+	if problemAt(err, "worker.queue_rate_limits.high") == nil {
+		t.Fatalf("expected a zero rate to be reported, got %v", err)
+	}
+	if p := problemAt(err, "worker.queue_rate_limits.hgh"); p == nil || p.Suggestion != `did you mean "high"?` {
+		t.Fatalf("hgh: %+v", p)
+	}
+	cfg.Worker.QueueRateLimits = map[string]float64{"high": 0.5, "low": 200}
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("expected a valid config, got %v", err)
+	}
+}
+
+func TestValidatePausedQueues(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Worker.PausedQueues = []string{"low", "hihg"}
+	if p := problemAt(Validate(cfg), "worker.paused_queues"); p == nil || p.Suggestion != `did you mean "high"?` {
+		t.Fatalf("hihg: %+v", p)
+	}
+	cfg.Worker.PausedQueues = []string{"low"}
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("expected a valid config, got %v", err)
+	}
+}
+
+func TestValidateQueueTimeouts(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Worker.QueueTimeouts = map[string]QueueTimeouts{
+		"high": {VisibilityTimeout: 5 * time.Second, JobTimeout: 5 * time.Second},
+		"low":  {JobTimeout: time.Hour},
+		"lwo":  {VisibilityTimeout: time.Minute},
+	}
+	err := Validate(cfg)
+	if p := problemAt(err, "worker.queue_timeouts.high.visibility_timeout"); p == nil || p.Message != "must exceed job_timeout (5s), got 5s" {
+		t.Fatalf("high: %+v", p)
+	}
+	if p := problemAt(err, "worker.queue_timeouts.low.visibility_timeout"); p == nil || p.Suggestion != "set visibility_timeout above job_timeout" {
+		t.Fatalf("expected the default visibility checked against job_timeout, got %+v", p)
+	}
+	if p := problemAt(err, "worker.queue_timeouts.lwo"); p == nil || p.Suggestion != `did you mean "low"?` {
+		t.Fatalf("lwo: %+v", p)
+	}
+
+	cfg.Worker.QueueTimeouts = map[string]QueueTimeouts{
+		"high": {VisibilityTimeout: 5 * time.Second, JobTimeout: 4 * time.Second},
+		"low":  {VisibilityTimeout: 15 * time.Minute},
+	}
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("expected a valid config, got %v", err)
+	}
+	if got := cfg.Worker.Visibility("high"); got != 5*time.Second {
+		t.Fatalf("expected the queue's visibility timeout, got %s", got)
+	}
+	if got := cfg.Worker.Visibility("other"); got != cfg.Worker.HeartbeatTTL+cfg.Worker.Reaper.GracePeriod {
+		t.Fatalf("expected heartbeat_ttl plus the grace period, got %s", got)
+	}
+	cfg.Worker.Mode = ModeStream
+	if problemAt(Validate(cfg), "worker.queue_timeouts.low.visibility_timeout") == nil {
+		t.Fatal("expected visibility timeouts rejected in stream mode")
+	}
+}
+
+func TestValidateReaper(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Worker.Reaper = ReaperConfig{Interval: 0, ScanCount: 0, MaxOpsPerSecond: -1, GracePeriod: -time.Second}
+	err := Validate(cfg)
+	for _, path := range []string{"worker.reaper.interval", "worker.reaper.scan_count", "worker.reaper.max_ops_per_second", "worker.reaper.grace_period"} {
+		if problemAt(err, path) == nil {
+			t.Errorf("expected %s to be reported, got %v", path, err)
+		}
+	}
+	cfg.Worker.Reaper = ReaperConfig{Interval: time.Second, ScanCount: 1000, MaxOpsPerSecond: 200, GracePeriod: 10 * time.Second}
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("expected a valid config, got %v", err)
+	}
+}
+
+func TestValidateCompletedRetention(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Worker.CompletedRetention = CompletedRetention{KeepLast: -1, MaxAge: time.Hour}
+	if problemAt(Validate(cfg), "worker.completed_retention.keep_last") == nil {
+		t.Fatal("expected a negative keep_last to be reported")
+	}
+	cfg.Worker.CompletedRetention = CompletedRetention{KeepLast: 1000}
+	if problemAt(Validate(cfg), "worker.completed_retention.interval") == nil {
+		t.Fatal("expected an interval to be required once retention is on")
+	}
+	cfg.Worker.CompletedRetention.Interval = time.Minute
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("expected a valid config, got %v", err)
+	}
+}
+
+func TestValidateUnclassifiedErrors(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Worker.UnclassifiedErrors = "dead_leter"
+	if p := problemAt(Validate(cfg), "worker.unclassified_errors"); p == nil || p.Suggestion != `did you mean "dead_letter"?` {
+		t.Fatalf("unclassified_errors: %+v", p)
+	}
+}
+
+func TestValidateBackpressure(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Producer.MaxQueueLength = 1000
+	cfg.Producer.Backpressure.Policy = "overflw"
+	if p := problemAt(Validate(cfg), "producer.backpressure.policy"); p == nil || p.Suggestion != `did you mean "overflow"?` {
+		t.Fatalf("policy: %+v", p)
+	}
+	cfg.Producer.Backpressure.Policy = BackpressureOverflow
+	if problemAt(Validate(cfg), "producer.backpressure.overflow_queue") == nil {
+		t.Fatal("overflow without a queue should be reported")
+	}
+	cfg.Producer.Backpressure.OverflowQueue = "jobqueue:overflow"
+	cfg.Worker.Mode = ModeStream
+	if problemAt(Validate(cfg), "producer.max_queue_length") == nil {
+		t.Fatal("max_queue_length should require list mode")
+	}
+	cfg.Worker.Mode = ModeList
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("expected a valid config, got %v", err)
+	}
+}
+
+func TestValidatePriorityAging(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Producer.PriorityAging = map[string]PriorityAging{
+		"low":  {After: time.Minute},
+		"high": {After: time.Minute, CheckInterval: time.Second},
+	}
+	err := Validate(cfg)
+	if problemAt(err, "producer.priority_aging.low.check_interval") == nil {
+		t.Fatal("a zero check_interval should be reported")
+	}
+	if p := problemAt(err, "producer.priority_aging.high.promote_to"); p == nil || p.Message != `"high" is the queue being aged` {
+		t.Fatalf("high: %+v", p)
+	}
+
+	cfg.Producer.PriorityAging = map[string]PriorityAging{"low": {After: time.Minute, CheckInterval: time.Second, PromoteTo: "hihg"}}
+	if p := problemAt(Validate(cfg), "producer.priority_aging.low.promote_to"); p == nil || p.Suggestion != `did you mean "high"?` {
+		t.Fatalf("promote_to: %+v", p)
+	}
+	cfg.Producer.PriorityAging = map[string]PriorityAging{"low": {After: time.Minute, CheckInterval: time.Second}}
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("expected a valid config, got %v", err)
+	}
+	if got := cfg.Producer.AgingTarget("low", &cfg.Worker); got != "high" {
+		t.Fatalf("expected aging into the first priority, got %q", got)
+	}
+	cfg.Worker.Mode = ModeStream
+	if problemAt(Validate(cfg), "producer.priority_aging.low") == nil {
+		t.Fatal("priority aging should require list mode")
+	}
+}
+
+func TestValidateClusters(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Clusters = map[string]Redis{
+		"default": {Addr: "localhost:6380"},
+		"media":   {Addr: "redis-media", DB: -1},
+	}
+	cfg.ClusterRoutes = map[string]string{"high": "meda", "jobqueue:reports:*": "media"}
+	err := Validate(cfg)
+	if problemAt(err, "clusters.default") == nil || problemAt(err, "clusters.media.addr") == nil || problemAt(err, "clusters.media.db") == nil {
+		t.Fatalf("expected cluster problems, got %v", err)
+	}
+	if p := problemAt(err, "cluster_routes.high"); p == nil || p.Suggestion != `did you mean "media"?` {
+		t.Fatalf("route: %+v", p)
+	}
+	cfg.Clusters = map[string]Redis{"media": {Addr: "redis-media:6379"}}
+	cfg.ClusterRoutes["high"] = "media"
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("expected a valid config, got %v", err)
+	}
+	cfg.Worker.Mode = ModeStream
+	if problemAt(Validate(cfg), "cluster_routes") == nil {
+		t.Fatal("cluster routes in stream mode should be reported")
+	}
+}
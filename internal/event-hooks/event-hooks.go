@@ -378,6 +378,12 @@ func (eb *EventBus) processRetryAttempt(attempt *DeliveryAttempt) {
 
 // calculateRetryDelay calculates the next retry delay
 func (eb *EventBus) calculateRetryDelay(policy RetryPolicy, attempt int) time.Duration {
+	return RetryDelay(policy, attempt)
+}
+
+// RetryDelay returns how long to wait before retry number attempt (from 1)
+// under policy.
+func RetryDelay(policy RetryPolicy, attempt int) time.Duration {
 	var delay time.Duration
 
 	switch policy.Strategy {
@@ -549,30 +555,41 @@ func (eb *EventBus) dlhProcessor() {
 
 // storeDLH stores a dead letter hook in Redis
 func (eb *EventBus) storeDLH(dlh *DeadLetterHook) {
+	if err := StoreDeadLetterHook(eb.ctx, eb.redis, dlh); err != nil {
+		eb.logger.Error("failed to store DLH", "error", err)
+		return
+	}
+
+	eb.metrics.DLHSize++
+
+	eb.logger.Info("DLH stored",
+		"dlh_id", dlh.ID,
+		"subscription_id", dlh.SubscriptionID)
+}
+
+// StoreDeadLetterHook saves dlh for 30 days and adds it to its
+// subscription's index, where GetDLHEntries and the DLH endpoints find it.
+// Deliveries made outside the EventBus use it to dead-letter their
+// failures alongside webhook subscriptions'.
+func StoreDeadLetterHook(ctx context.Context, rdb *redis.Client, dlh *DeadLetterHook) error {
 	key := fmt.Sprintf("event_hooks:dlh:%s", dlh.ID)
 	data, err := json.Marshal(dlh)
 	if err != nil {
-		eb.logger.Error("failed to marshal DLH", "error", err)
-		return
+		return fmt.Errorf("failed to marshal DLH: %w", err)
 	}
 
 	// Store with 30-day expiration
-	err = eb.redis.Set(eb.ctx, key, data, 30*24*time.Hour).Err()
-	if err != nil {
-		eb.logger.Error("failed to store DLH", "error", err)
-		return
+	if err := rdb.Set(ctx, key, data, 30*24*time.Hour).Err(); err != nil {
+		return err
 	}
 
 	// Add to DLH index for subscription
 	indexKey := fmt.Sprintf("event_hooks:dlh_index:%s", dlh.SubscriptionID)
-	eb.redis.LPush(eb.ctx, indexKey, dlh.ID)
-	eb.redis.Expire(eb.ctx, indexKey, 30*24*time.Hour)
-
-	eb.metrics.DLHSize++
-
-	eb.logger.Info("DLH stored",
-		"dlh_id", dlh.ID,
-		"subscription_id", dlh.SubscriptionID)
+	pipe := rdb.Pipeline()
+	pipe.LPush(ctx, indexKey, dlh.ID)
+	pipe.Expire(ctx, indexKey, 30*24*time.Hour)
+	_, err = pipe.Exec(ctx)
+	return err
 }
 
 // metricsCollector periodically updates metrics
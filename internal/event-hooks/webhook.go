@@ -269,6 +269,12 @@ func (ws *WebhookSubscriber) setRequestHeaders(req *http.Request, payload []byte
 
 // generateSignature creates an HMAC signature for the payload
 func (ws *WebhookSubscriber) generateSignature(payload []byte, secret string) string {
+	return SignPayload(payload, secret)
+}
+
+// SignPayload returns the X-Webhook-Signature value for payload: a hex
+// HMAC-SHA256 keyed with secret, prefixed with "sha256=".
+func SignPayload(payload []byte, secret string) string {
 	h := hmac.New(sha256.New, []byte(secret))
 	h.Write(payload)
 	signature := h.Sum(nil)
@@ -0,0 +1,97 @@
+package jsonpayloadstudio
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Names of the limits a payload can break, as used in config files.
+const (
+	LimitMaxPayloadSize  = "max_payload_size"
+	LimitMaxNestingDepth = "max_nesting_depth"
+	LimitMaxFieldCount   = "max_field_count"
+)
+
+// ComplexityLimitDetails is the Details of the error returned when a payload
+// breaks a hard limit with EnforceComplexityLimits on.
+type ComplexityLimitDetails struct {
+	Limit  string    `json:"limit"` // one of the Limit* names
+	Max    int       `json:"max"`
+	Actual int       `json:"actual"`
+	Stats  LintStats `json:"stats"`
+}
+
+// checkComplexityLimits enforces MaxPayloadSize, MaxNestingDepth and
+// MaxFieldCount on a decoded payload whose encoding is content. The first
+// limit broken is reported, with the path where it was crossed: the first
+// value nested too deep, or the first field past the budget, walking object
+// keys in sorted order.
+func (jps *JSONPayloadStudio) checkComplexityLimits(payload interface{}, content string) *StudioError {
+	stats := calculateStats(payload, content)
+	cfg := jps.config
+
+	if len(content) > cfg.MaxPayloadSize {
+		err := NewSizeError(fmt.Sprintf("payload size %d bytes exceeds maximum of %d", len(content), cfg.MaxPayloadSize), nil)
+		return limitError(err, LimitMaxPayloadSize, cfg.MaxPayloadSize, len(content), "", stats)
+	}
+
+	cw := &complexityWalk{maxDepth: cfg.MaxNestingDepth, maxFields: cfg.MaxFieldCount}
+	cw.walk(payload, "", 0)
+	if cw.depthPath != nil {
+		return limitError(NewDepthError(cfg.MaxNestingDepth, stats.MaxDepth), LimitMaxNestingDepth, cfg.MaxNestingDepth, stats.MaxDepth, *cw.depthPath, stats)
+	}
+	if cw.fieldPath != nil {
+		return limitError(NewFieldCountError(cfg.MaxFieldCount, stats.Keys), LimitMaxFieldCount, cfg.MaxFieldCount, stats.Keys, *cw.fieldPath, stats)
+	}
+	return nil
+}
+
+// limitError names the broken limit in err and attaches the payload stats
+// and, unless the limit applies to the whole payload, the offending path.
+func limitError(err *StudioError, limit string, max, actual int, path string, stats LintStats) *StudioError {
+	err.Message = fmt.Sprintf("%s [%s]; payload has %d bytes, depth %d, %d fields",
+		err.Message, limit, stats.Characters, stats.MaxDepth, stats.Keys)
+	if path != "" {
+		err.Path = path
+	}
+	err.Details = ComplexityLimitDetails{Limit: limit, Max: max, Actual: actual, Stats: stats}
+	return err
+}
+
+// complexityWalk finds where a payload first crosses the depth and field
+// count limits; a nil path means the limit holds.
+type complexityWalk struct {
+	maxDepth, maxFields int
+	fields              int
+	depthPath           *string
+	fieldPath           *string
+}
+
+func (cw *complexityWalk) walk(value interface{}, path string, depth int) {
+	if depth > cw.maxDepth && cw.depthPath == nil {
+		p := path
+		cw.depthPath = &p
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			p := childPath(path, k)
+			cw.fields++
+			if cw.fields > cw.maxFields && cw.fieldPath == nil {
+				cw.fieldPath = &p
+			}
+			cw.walk(v[k], p, depth+1)
+		}
+
+	case []interface{}:
+		for i, item := range v {
+			cw.walk(item, fmt.Sprintf("%s[%d]", path, i), depth+1)
+		}
+	}
+}
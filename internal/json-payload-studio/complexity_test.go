@@ -0,0 +1,89 @@
+// Copyright 2025 James Ross
+package jsonpayloadstudio
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEnqueueRejectsPayloadsOverComplexityLimits(t *testing.T) {
+	studio, rdb, sessionID := newMatrixStudio(t, `{"a": {"b": {"c": {"d": 1}}}}`)
+	studio.config.MaxNestingDepth = 2
+	opts := &EnqueueOptions{Queue: "load", Count: 1}
+
+	// without enforcement only size is checked
+	if _, err := studio.EnqueuePayload(sessionID, opts); err != nil {
+		t.Fatalf("expected the deep payload to be accepted, got %v", err)
+	}
+
+	studio.config.EnforceComplexityLimits = true
+	_, err := studio.EnqueuePayload(sessionID, opts)
+	var studioErr *StudioError
+	if !errors.As(err, &studioErr) || studioErr.Type != ErrorTypeDepth {
+		t.Fatalf("expected a depth error, got %v", err)
+	}
+	if studioErr.Path != "a.b.c" || !strings.Contains(studioErr.Message, LimitMaxNestingDepth) {
+		t.Fatalf("error should name the limit and path: %v", studioErr)
+	}
+	details, ok := studioErr.Details.(ComplexityLimitDetails)
+	if !ok || details.Max != 2 || details.Actual != 4 || details.Stats.Keys != 4 {
+		t.Fatalf("unexpected details: %+v", studioErr.Details)
+	}
+
+	studio.config.MaxNestingDepth = 10
+	studio.config.MaxFieldCount = 3
+	_, err = studio.EnqueuePayload(sessionID, opts)
+	if !errors.As(err, &studioErr) || studioErr.Type != ErrorTypeFieldCount || studioErr.Path != "a.b.c.d" {
+		t.Fatalf("expected a field count error at a.b.c.d, got %v", err)
+	}
+
+	if n, _ := rdb.LLen(context.Background(), "queue:load").Result(); n != 1 {
+		t.Fatalf("rejected payloads must not be queued, queue has %d", n)
+	}
+}
+
+func TestCheckComplexityLimitsPaths(t *testing.T) {
+	studio, _, _ := newMatrixStudio(t, "{}")
+	studio.config.MaxNestingDepth = 2
+	studio.config.MaxFieldCount = 100
+
+	payload := map[string]interface{}{
+		"items":   []interface{}{1, []interface{}{2, []interface{}{3}}},
+		"x-trace": map[string]interface{}{"ok": true},
+	}
+	err := studio.checkComplexityLimits(payload, `{}`)
+	if err == nil || err.Path != "items[1][0]" {
+		t.Fatalf("expected the first too-deep value to be items[1][0], got %v", err)
+	}
+
+	studio.config.MaxNestingDepth = 10
+	studio.config.MaxFieldCount = 2
+	err = studio.checkComplexityLimits(payload, `{}`)
+	if err == nil || err.Path != `["x-trace"].ok` {
+		t.Fatalf("expected the third field to be reported, got %v", err)
+	}
+
+	studio.config.MaxPayloadSize = 1
+	if err := studio.checkComplexityLimits(payload, `{}`); err == nil || err.Type != ErrorTypeSize || err.Path != "" {
+		t.Fatalf("expected a size error without a path, got %v", err)
+	}
+}
+
+func TestValidateJSONReportsEnforcedLimitsAsErrors(t *testing.T) {
+	studio, _, _ := newMatrixStudio(t, "{}")
+	studio.config.MaxFieldCount = 1
+	content := `{"a": 1, "b": 2}`
+
+	res := studio.ValidateJSON(content, nil)
+	if !res.Valid || len(res.Warnings) != 1 {
+		t.Fatalf("expected a warning only, got %+v", res)
+	}
+
+	studio.config.EnforceComplexityLimits = true
+	res = studio.ValidateJSON(content, nil)
+	if res.Valid || len(res.Errors) != 1 || res.Errors[0].Path != "b" || len(res.Warnings) != 0 {
+		t.Fatalf("expected one field count error at b, got %+v", res)
+	}
+}
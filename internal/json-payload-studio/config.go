@@ -26,10 +26,15 @@ func DefaultConfig() *StudioConfig {
 		AutoLoadTemplates: true,
 		TemplateDirs:      []string{"config/templates", "templates"},
 
+		// Snippet settings
+		SnippetsPath: "config/snippets",
+
 		// Schema settings
-		SchemasPath:      "config/schemas",
-		DefaultSchema:    "",
-		StrictValidation: false,
+		SchemasPath:          "config/schemas",
+		DefaultSchema:        "",
+		StrictValidation:     false,
+		RemoteSchemaTimeout:  5 * time.Second,
+		RemoteSchemaCacheTTL: 10 * time.Minute,
 
 		// Safety settings
 		MaxPayloadSize:  10 * 1024 * 1024, // 10MB
@@ -53,11 +58,12 @@ func DefaultConfig() *StudioConfig {
 		RequireConfirm: true,
 
 		// UI settings
-		ShowPreview:      true,
-		PreviewLines:     20,
-		HistorySize:      100,
-		AutoSave:         true,
-		AutoSaveInterval: 30 * time.Second,
+		ShowPreview:        true,
+		PreviewLines:       20,
+		DiffMaxValueLength: 80,
+		HistorySize:        100,
+		AutoSave:           true,
+		AutoSaveInterval:   30 * time.Second,
 	}
 }
 
@@ -115,10 +121,22 @@ func (c *StudioConfig) Validate() error {
 		return fmt.Errorf("max_nesting_depth must be positive")
 	}
 
+	if c.RemoteSchemaTimeout < 0 {
+		return fmt.Errorf("remote_schema_timeout must not be negative")
+	}
+
+	if c.RemoteSchemaCacheTTL < 0 {
+		return fmt.Errorf("remote_schema_cache_ttl must not be negative")
+	}
+
 	if c.PreviewLines <= 0 {
 		c.PreviewLines = 20
 	}
 
+	if c.DiffMaxValueLength <= 0 {
+		c.DiffMaxValueLength = 80
+	}
+
 	if c.HistorySize < 0 {
 		c.HistorySize = 0
 	}
@@ -191,6 +209,12 @@ func (b *ConfigBuilder) WithTemplateDirs(dirs []string) *ConfigBuilder {
 	return b
 }
 
+// WithSnippetsPath sets the directory user snippets are saved to
+func (b *ConfigBuilder) WithSnippetsPath(path string) *ConfigBuilder {
+	b.config.SnippetsPath = path
+	return b
+}
+
 // WithSchemasPath sets the schemas directory path
 func (b *ConfigBuilder) WithSchemasPath(path string) *ConfigBuilder {
 	b.config.SchemasPath = path
@@ -209,6 +233,30 @@ func (b *ConfigBuilder) WithStrictValidation(enabled bool) *ConfigBuilder {
 	return b
 }
 
+// WithScaffoldOptionalFields controls whether schema scaffolds include optional properties
+func (b *ConfigBuilder) WithScaffoldOptionalFields(enabled bool) *ConfigBuilder {
+	b.config.ScaffoldOptionalFields = enabled
+	return b
+}
+
+// WithRemoteSchemaHosts sets the hosts remote $ref schemas may be fetched from
+func (b *ConfigBuilder) WithRemoteSchemaHosts(hosts []string) *ConfigBuilder {
+	b.config.RemoteSchemaHosts = hosts
+	return b
+}
+
+// WithRemoteSchemaTimeout sets the timeout for fetching a remote $ref schema
+func (b *ConfigBuilder) WithRemoteSchemaTimeout(timeout time.Duration) *ConfigBuilder {
+	b.config.RemoteSchemaTimeout = timeout
+	return b
+}
+
+// WithRemoteSchemaCacheTTL sets how long fetched $ref schemas are cached
+func (b *ConfigBuilder) WithRemoteSchemaCacheTTL(ttl time.Duration) *ConfigBuilder {
+	b.config.RemoteSchemaCacheTTL = ttl
+	return b
+}
+
 // WithMaxPayloadSize sets the maximum payload size in bytes
 func (b *ConfigBuilder) WithMaxPayloadSize(size int) *ConfigBuilder {
 	b.config.MaxPayloadSize = size
@@ -227,6 +275,19 @@ func (b *ConfigBuilder) WithMaxNestingDepth(depth int) *ConfigBuilder {
 	return b
 }
 
+// WithEnforceComplexityLimits makes the size, depth and field count limits
+// reject enqueues instead of only warning
+func (b *ConfigBuilder) WithEnforceComplexityLimits(enabled bool) *ConfigBuilder {
+	b.config.EnforceComplexityLimits = enabled
+	return b
+}
+
+// WithLenientInput accepts JSON5/JSONC input and normalizes it to strict JSON
+func (b *ConfigBuilder) WithLenientInput(enabled bool) *ConfigBuilder {
+	b.config.LenientInput = enabled
+	return b
+}
+
 // WithSecretStripping enables or disables secret stripping
 func (b *ConfigBuilder) WithSecretStripping(enabled bool) *ConfigBuilder {
 	b.config.StripSecrets = enabled
@@ -257,6 +318,12 @@ func (b *ConfigBuilder) WithPreviewLines(lines int) *ConfigBuilder {
 	return b
 }
 
+// WithDiffMaxValueLength sets how many characters of a value RenderDiff shows
+func (b *ConfigBuilder) WithDiffMaxValueLength(length int) *ConfigBuilder {
+	b.config.DiffMaxValueLength = length
+	return b
+}
+
 // WithHistorySize sets the history size
 func (b *ConfigBuilder) WithHistorySize(size int) *ConfigBuilder {
 	b.config.HistorySize = size
@@ -414,6 +481,14 @@ func (ec *EnvironmentConfig) Load() *StudioConfig {
 		}
 	}
 
+	if enforce := os.Getenv("JSON_STUDIO_ENFORCE_LIMITS"); enforce != "" {
+		config.EnforceComplexityLimits = enforce == "true" || enforce == "1"
+	}
+
+	if lenient := os.Getenv("JSON_STUDIO_LENIENT_INPUT"); lenient != "" {
+		config.LenientInput = lenient == "true" || lenient == "1"
+	}
+
 	if stripSecrets := os.Getenv("JSON_STUDIO_STRIP_SECRETS"); stripSecrets != "" {
 		config.StripSecrets = stripSecrets == "true" || stripSecrets == "1"
 	}
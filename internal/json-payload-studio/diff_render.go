@@ -0,0 +1,236 @@
+// Copyright 2025 James Ross
+package jsonpayloadstudio
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Diff render formats accepted by RenderDiff
+const (
+	DiffFormatUnified    = "unified"
+	DiffFormatSideBySide = "side-by-side"
+	DiffFormatSummary    = "summary"
+)
+
+// diffPalette holds the ANSI SGR codes for each kind of diff line
+type diffPalette struct {
+	added, removed, modified, header string
+}
+
+var diffPalettes = map[string]diffPalette{
+	"dark":  {added: "92", removed: "91", modified: "93", header: "1;96"},
+	"light": {added: "32", removed: "31", modified: "33", header: "1;34"},
+}
+
+// RenderDiff renders diff as text in the unified (git-style +/- lines),
+// side-by-side or summary format; any other format renders unified. Paths
+// use dotted/bracket notation with "$" for the whole payload, and values
+// are cut to DiffMaxValueLength characters. Output is colored with ANSI
+// escapes for the editor theme when syntax highlighting is on and
+// $NO_COLOR is unset.
+func (jps *JSONPayloadStudio) RenderDiff(diff *DiffResult, format string) string {
+	if diff == nil {
+		return ""
+	}
+	if !diff.HasChanges {
+		if diff.Summary != "" {
+			return diff.Summary + "\n"
+		}
+		return "No changes\n"
+	}
+
+	r := jps.newDiffRenderer()
+	switch format {
+	case DiffFormatSummary:
+		return r.summary(diff)
+	case DiffFormatSideBySide:
+		return r.sideBySide(diff)
+	default:
+		return r.unified(diff)
+	}
+}
+
+type diffRenderer struct {
+	maxLen  int
+	color   bool
+	palette diffPalette
+}
+
+func (jps *JSONPayloadStudio) newDiffRenderer() *diffRenderer {
+	r := &diffRenderer{maxLen: jps.config.DiffMaxValueLength}
+	if r.maxLen <= 0 {
+		r.maxLen = 80
+	}
+	if jps.config.SyntaxHighlight && os.Getenv("NO_COLOR") == "" {
+		palette, ok := diffPalettes[jps.config.EditorTheme]
+		if !ok {
+			palette = diffPalettes["dark"]
+		}
+		r.color = true
+		r.palette = palette
+	}
+	return r
+}
+
+// paint wraps s in the SGR code when coloring is on
+func (r *diffRenderer) paint(code, s string) string {
+	if !r.color || code == "" {
+		return s
+	}
+	return "\x1b[" + code + "m" + s + "\x1b[0m"
+}
+
+// sortedChanges returns every change ordered by path, so output is stable
+// even though objects are compared in map order.
+func sortedChanges(diff *DiffResult) []DiffChange {
+	changes := make([]DiffChange, 0, len(diff.Added)+len(diff.Removed)+len(diff.Modified))
+	changes = append(changes, diff.Removed...)
+	changes = append(changes, diff.Modified...)
+	changes = append(changes, diff.Added...)
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+func (r *diffRenderer) unified(diff *DiffResult) string {
+	var b strings.Builder
+	b.WriteString(r.paint(r.palette.header, "--- old") + "\n")
+	b.WriteString(r.paint(r.palette.header, "+++ new") + "\n")
+	b.WriteString(r.paint(r.palette.header, "@@ "+diff.Summary+" @@") + "\n")
+	for _, c := range sortedChanges(diff) {
+		path := displayPath(c.Path)
+		switch c.Type {
+		case "added":
+			b.WriteString(r.paint(r.palette.added, "+ "+path+": "+r.value(c.NewValue)) + "\n")
+		case "removed":
+			b.WriteString(r.paint(r.palette.removed, "- "+path+": "+r.value(c.OldValue)) + "\n")
+		default:
+			b.WriteString(r.paint(r.palette.removed, "- "+path+": "+r.value(c.OldValue)) + "\n")
+			b.WriteString(r.paint(r.palette.added, "+ "+path+": "+r.value(c.NewValue)) + "\n")
+		}
+	}
+	return b.String()
+}
+
+func (r *diffRenderer) sideBySide(diff *DiffResult) string {
+	type row struct{ mark, path, old, new, code string }
+	rows := []row{{path: "PATH", old: "OLD", new: "NEW", code: r.palette.header}}
+	for _, c := range sortedChanges(diff) {
+		switch c.Type {
+		case "added":
+			rows = append(rows, row{"+", displayPath(c.Path), "", r.value(c.NewValue), r.palette.added})
+		case "removed":
+			rows = append(rows, row{"-", displayPath(c.Path), r.value(c.OldValue), "", r.palette.removed})
+		default:
+			rows = append(rows, row{"~", displayPath(c.Path), r.value(c.OldValue), r.value(c.NewValue), r.palette.modified})
+		}
+	}
+
+	pathWidth, oldWidth := 0, 0
+	for _, rw := range rows {
+		pathWidth = max(pathWidth, utf8.RuneCountInString(rw.path))
+		oldWidth = max(oldWidth, utf8.RuneCountInString(rw.old))
+	}
+
+	var b strings.Builder
+	for _, rw := range rows {
+		mark := rw.mark
+		if mark == "" {
+			mark = " "
+		}
+		line := mark + " " + padRight(rw.path, pathWidth) + " | " + padRight(rw.old, oldWidth) + " | " + rw.new
+		b.WriteString(r.paint(rw.code, strings.TrimRight(line, " ")) + "\n")
+	}
+	return b.String()
+}
+
+func (r *diffRenderer) summary(diff *DiffResult) string {
+	var b strings.Builder
+	b.WriteString(r.paint(r.palette.header, diff.Summary) + "\n")
+	groups := []struct {
+		label   string
+		code    string
+		changes []DiffChange
+	}{
+		{"added", r.palette.added, diff.Added},
+		{"removed", r.palette.removed, diff.Removed},
+		{"modified", r.palette.modified, diff.Modified},
+	}
+	for _, g := range groups {
+		if len(g.changes) == 0 {
+			continue
+		}
+		paths := make([]string, len(g.changes))
+		for i, c := range g.changes {
+			paths[i] = displayPath(c.Path)
+		}
+		sort.Strings(paths)
+		b.WriteString(r.paint(g.code, fmt.Sprintf("  %-9s %s", g.label+":", strings.Join(paths, ", "))) + "\n")
+	}
+	return b.String()
+}
+
+// value renders v as compact JSON cut to maxLen characters
+func (r *diffRenderer) value(v interface{}) string {
+	var buf strings.Builder
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	s := fmt.Sprintf("%v", v)
+	if err := enc.Encode(v); err == nil {
+		s = strings.TrimSuffix(buf.String(), "\n")
+	}
+	if utf8.RuneCountInString(s) <= r.maxLen {
+		return s
+	}
+	if r.maxLen <= 1 {
+		return "…"
+	}
+	return string([]rune(s)[:r.maxLen-1]) + "…"
+}
+
+func padRight(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "$"
+	}
+	return path
+}
+
+// childPath appends an object key to a diff path: identifier-like keys use
+// dotted notation and anything else a quoted bracket, e.g. a.b["x-y"].
+func childPath(path, key string) string {
+	if !isPathIdentifier(key) {
+		return path + "[" + strconv.Quote(key) + "]"
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func isPathIdentifier(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, r := range key {
+		if r == '_' || r == '$' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r)) {
+			continue
+		}
+		return false
+	}
+	return true
+}
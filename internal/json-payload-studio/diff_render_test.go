@@ -0,0 +1,108 @@
+// Copyright 2025 James Ross
+package jsonpayloadstudio
+
+import (
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func newDiffStudio(t *testing.T, highlight bool) *JSONPayloadStudio {
+	t.Helper()
+	cfg := DefaultConfig()
+	cfg.TemplatesPath = ""
+	cfg.SchemasPath = t.TempDir()
+	cfg.AutoSave = false
+	cfg.SyntaxHighlight = highlight
+	cfg.DiffMaxValueLength = 12
+	studio, err := NewJSONPayloadStudio(cfg, nil, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return studio
+}
+
+func renderTestDiff(studio *JSONPayloadStudio) *DiffResult {
+	old := map[string]interface{}{
+		"user":  map[string]interface{}{"email": "a@example.com", "x-id": 1.0},
+		"tags":  []interface{}{"a", "b"},
+		"notes": "short",
+	}
+	new := map[string]interface{}{
+		"user": map[string]interface{}{"email": "b@example.com", "x-id": 1.0},
+		"tags": []interface{}{"a", "c", "d"},
+		"body": strings.Repeat("x", 40),
+	}
+	return studio.compareJSON(old, new)
+}
+
+func TestRenderDiffUnified(t *testing.T) {
+	studio := newDiffStudio(t, false)
+	got := studio.RenderDiff(renderTestDiff(studio), DiffFormatUnified)
+	want := `--- old
++++ new
+@@ 2 added, 1 removed, 2 modified @@
++ body: "xxxxxxxxxx…
+- notes: "short"
+- tags[1]: "b"
++ tags[1]: "c"
++ tags[2]: "d"
+- user.email: "a@example.…
++ user.email: "b@example.…
+`
+	if got != want {
+		t.Errorf("unexpected unified diff:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderDiffSummaryAndSideBySide(t *testing.T) {
+	studio := newDiffStudio(t, false)
+	diff := renderTestDiff(studio)
+
+	summary := studio.RenderDiff(diff, DiffFormatSummary)
+	for _, line := range []string{
+		"2 added, 1 removed, 2 modified",
+		"  added:    body, tags[2]",
+		"  removed:  notes",
+		"  modified: tags[1], user.email",
+	} {
+		if !strings.Contains(summary, line+"\n") {
+			t.Errorf("summary missing %q:\n%s", line, summary)
+		}
+	}
+
+	side := studio.RenderDiff(diff, DiffFormatSideBySide)
+	if !strings.Contains(side, `~ tags[1]    | "b"          | "c"`) {
+		t.Errorf("unexpected side-by-side diff:\n%s", side)
+	}
+
+	if got := studio.RenderDiff(studio.compareJSON(1.0, 1.0), DiffFormatUnified); got != "No changes\n" {
+		t.Errorf("expected no changes, got %q", got)
+	}
+}
+
+func TestRenderDiffColorsWithTheme(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	studio := newDiffStudio(t, true)
+	got := studio.RenderDiff(renderTestDiff(studio), DiffFormatUnified)
+	if !strings.Contains(got, "\x1b[92m+ tags[2]: \"d\"\x1b[0m") {
+		t.Errorf("expected dark theme colors, got %q", got)
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if got := studio.RenderDiff(renderTestDiff(studio), DiffFormatUnified); strings.Contains(got, "\x1b[") {
+		t.Errorf("expected NO_COLOR to disable colors, got %q", got)
+	}
+}
+
+func TestChildPathBracketsNonIdentifierKeys(t *testing.T) {
+	studio := newDiffStudio(t, false)
+	diff := studio.compareJSON(
+		map[string]interface{}{"user": map[string]interface{}{"x-id": 1.0}},
+		map[string]interface{}{"user": map[string]interface{}{"x-id": 2.0}},
+	)
+	if len(diff.Modified) != 1 || diff.Modified[0].Path != `user["x-id"]` {
+		t.Errorf("expected bracket notation, got %+v", diff.Modified)
+	}
+}
@@ -0,0 +1,337 @@
+// Copyright 2025 James Ross
+package jsonpayloadstudio
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// fieldSegmentKind is what one step of a field path selects.
+type fieldSegmentKind int
+
+const (
+	segmentKey      fieldSegmentKind = iota // an object field
+	segmentIndex                            // an array element
+	segmentWildcard                         // every array element or object value
+)
+
+type fieldSegment struct {
+	kind  fieldSegmentKind
+	key   string
+	index int
+}
+
+// parseFieldPath parses a field path in the notation diffs and redaction
+// previews report (user.tags[2], meta["x-id"]), optionally rooted at $
+// ($.user.name), with [*] or .* standing for every element of an array or
+// value of an object. "" and "$" are the whole payload.
+func parseFieldPath(path string) ([]fieldSegment, error) {
+	p := strings.TrimSpace(path)
+	p = strings.TrimPrefix(p, "$")
+	var segs []fieldSegment
+	for i := 0; i < len(p); {
+		switch {
+		case p[i] == '[':
+			end := strings.IndexByte(p[i:], ']')
+			if end < 0 {
+				return nil, fieldPathError("unclosed [ in path", path)
+			}
+			inner := p[i+1 : i+end]
+			switch {
+			case inner == "*":
+				segs = append(segs, fieldSegment{kind: segmentWildcard})
+			case strings.HasPrefix(inner, `"`):
+				quoted, err := strconv.QuotedPrefix(p[i+1:])
+				if err != nil || !strings.HasPrefix(p[i+1+len(quoted):], "]") {
+					return nil, fieldPathError("malformed quoted field in path", path)
+				}
+				key, _ := strconv.Unquote(quoted)
+				segs = append(segs, fieldSegment{kind: segmentKey, key: key})
+				end = 1 + len(quoted)
+			default:
+				n, err := strconv.Atoi(inner)
+				if err != nil || n < 0 {
+					return nil, fieldPathError(fmt.Sprintf("invalid array index %q in path", inner), path)
+				}
+				segs = append(segs, fieldSegment{kind: segmentIndex, index: n})
+			}
+			i += end + 1
+		default:
+			if p[i] == '.' {
+				i++
+			} else if len(segs) > 0 {
+				return nil, fieldPathError(fmt.Sprintf("unexpected %q in path", p[i]), path)
+			}
+			end := i
+			for end < len(p) && p[end] != '.' && p[end] != '[' {
+				end++
+			}
+			if end == i {
+				return nil, fieldPathError("empty field name in path", path)
+			}
+			if name := p[i:end]; name == "*" {
+				segs = append(segs, fieldSegment{kind: segmentWildcard})
+			} else {
+				segs = append(segs, fieldSegment{kind: segmentKey, key: name})
+			}
+			i = end
+		}
+	}
+	return segs, nil
+}
+
+func fieldPathError(message, path string) *StudioError {
+	return &StudioError{Type: ErrorTypeValidation, Message: message, Path: path}
+}
+
+// fieldEdit changes the container node at the path's last segment and
+// returns the node to put in its place and how many fields it changed.
+type fieldEdit func(node interface{}, seg fieldSegment, at string) (interface{}, int, error)
+
+// editFields walks node along segs and applies edit wherever the path
+// matches; wildcards fan out to every element. Fields missing on the way
+// match nothing, unless create is set, in which case they are added as
+// empty objects. It returns the edited node and the number of fields
+// changed.
+func editFields(node interface{}, segs []fieldSegment, at string, create bool, edit fieldEdit) (interface{}, int, error) {
+	if len(segs) == 1 {
+		return edit(node, segs[0], at)
+	}
+	seg, rest := segs[0], segs[1:]
+	switch seg.kind {
+	case segmentKey:
+		obj, ok := node.(map[string]interface{})
+		if !ok {
+			return node, 0, nil
+		}
+		child, exists := obj[seg.key]
+		if !exists {
+			if !create || rest[0].kind != segmentKey {
+				return node, 0, nil
+			}
+			child = map[string]interface{}{}
+		}
+		edited, n, err := editFields(child, rest, childPath(at, seg.key), create, edit)
+		if err != nil || n == 0 {
+			return node, n, err
+		}
+		obj[seg.key] = edited
+		return obj, n, nil
+
+	case segmentIndex:
+		arr, ok := node.([]interface{})
+		if !ok || seg.index >= len(arr) {
+			return node, 0, nil
+		}
+		edited, n, err := editFields(arr[seg.index], rest, fmt.Sprintf("%s[%d]", at, seg.index), create, edit)
+		if err != nil {
+			return node, 0, err
+		}
+		arr[seg.index] = edited
+		return arr, n, nil
+
+	default:
+		total := 0
+		switch v := node.(type) {
+		case []interface{}:
+			for i, item := range v {
+				edited, n, err := editFields(item, rest, fmt.Sprintf("%s[%d]", at, i), create, edit)
+				if err != nil {
+					return node, 0, err
+				}
+				v[i] = edited
+				total += n
+			}
+		case map[string]interface{}:
+			for _, key := range sortedKeys(v) {
+				edited, n, err := editFields(v[key], rest, childPath(at, key), create, edit)
+				if err != nil {
+					return node, 0, err
+				}
+				if n > 0 {
+					v[key] = edited
+				}
+				total += n
+			}
+		}
+		return node, total, nil
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// SetField sets the field at jsonPath in the session's payload to value,
+// adding it, and any missing parent objects, when absent. A wildcard sets
+// the field in every element it matches, e.g. items[*].status. The edit
+// goes through the undo history like any other; it returns the number of
+// fields set.
+func (jps *JSONPayloadStudio) SetField(sessionID, jsonPath string, value interface{}) (int, error) {
+	// round-trip value so the payload holds only JSON types
+	data, err := json.Marshal(value)
+	if err != nil {
+		return 0, NewValidationError(fmt.Sprintf("value is not JSON: %v", err), nil)
+	}
+	var v interface{}
+	_ = json.Unmarshal(data, &v)
+
+	return jps.editSessionFields(sessionID, jsonPath, true, func(node interface{}, seg fieldSegment, at string) (interface{}, int, error) {
+		switch seg.kind {
+		case segmentKey:
+			obj, ok := node.(map[string]interface{})
+			if !ok {
+				return node, 0, nil
+			}
+			obj[seg.key] = cloneValue(v)
+			return obj, 1, nil
+		case segmentIndex:
+			arr, ok := node.([]interface{})
+			if !ok || seg.index >= len(arr) {
+				return node, 0, nil
+			}
+			arr[seg.index] = cloneValue(v)
+			return arr, 1, nil
+		default:
+			switch c := node.(type) {
+			case []interface{}:
+				for i := range c {
+					c[i] = cloneValue(v)
+				}
+				return c, len(c), nil
+			case map[string]interface{}:
+				for k := range c {
+					c[k] = cloneValue(v)
+				}
+				return c, len(c), nil
+			}
+			return node, 0, nil
+		}
+	})
+}
+
+// DeleteField removes the field at jsonPath from the session's payload; an
+// array element is removed and the elements after it shift down. It
+// returns the number of fields removed.
+func (jps *JSONPayloadStudio) DeleteField(sessionID, jsonPath string) (int, error) {
+	return jps.editSessionFields(sessionID, jsonPath, false, func(node interface{}, seg fieldSegment, at string) (interface{}, int, error) {
+		switch seg.kind {
+		case segmentKey:
+			obj, ok := node.(map[string]interface{})
+			if !ok {
+				return node, 0, nil
+			}
+			if _, exists := obj[seg.key]; !exists {
+				return node, 0, nil
+			}
+			delete(obj, seg.key)
+			return obj, 1, nil
+		case segmentIndex:
+			arr, ok := node.([]interface{})
+			if !ok || seg.index >= len(arr) {
+				return node, 0, nil
+			}
+			return append(arr[:seg.index:seg.index], arr[seg.index+1:]...), 1, nil
+		default:
+			switch c := node.(type) {
+			case []interface{}:
+				return []interface{}{}, len(c), nil
+			case map[string]interface{}:
+				return map[string]interface{}{}, len(c), nil
+			}
+			return node, 0, nil
+		}
+	})
+}
+
+// RenameField renames the object field at jsonPath to newName, keeping its
+// value. The path must end in a field name; renaming onto a field that
+// already exists is an error rather than an overwrite. It returns the
+// number of fields renamed.
+func (jps *JSONPayloadStudio) RenameField(sessionID, jsonPath, newName string) (int, error) {
+	if newName == "" {
+		return 0, fieldPathError("new field name required", jsonPath)
+	}
+	return jps.editSessionFields(sessionID, jsonPath, false, func(node interface{}, seg fieldSegment, at string) (interface{}, int, error) {
+		if seg.kind != segmentKey {
+			return node, 0, fieldPathError("only object fields can be renamed", jsonPath)
+		}
+		obj, ok := node.(map[string]interface{})
+		if !ok {
+			return node, 0, nil
+		}
+		value, exists := obj[seg.key]
+		if !exists || seg.key == newName {
+			return node, 0, nil
+		}
+		if _, taken := obj[newName]; taken {
+			return node, 0, fieldPathError(fmt.Sprintf("field %s already exists", childPath(at, newName)), jsonPath)
+		}
+		delete(obj, seg.key)
+		obj[newName] = value
+		return obj, 1, nil
+	})
+}
+
+// editSessionFields applies edit to the session's payload at jsonPath and
+// stores the result, formatted, as a new undoable edit. A path matching no
+// field is an error and leaves the content alone, as does an edit whose
+// result does not serialize to valid JSON.
+func (jps *JSONPayloadStudio) editSessionFields(sessionID, jsonPath string, create bool, edit fieldEdit) (int, error) {
+	segs, err := parseFieldPath(jsonPath)
+	if err != nil {
+		return 0, err
+	}
+	if len(segs) == 0 {
+		return 0, fieldPathError("path must name a field", jsonPath)
+	}
+
+	jps.mu.Lock()
+	defer jps.mu.Unlock()
+
+	session, exists := jps.sessions[sessionID]
+	if !exists {
+		return 0, NewSessionError("session not found", sessionID)
+	}
+	if session.EditorState == nil {
+		session.EditorState = &EditorState{Content: "{}"}
+	}
+	state := session.EditorState
+
+	var payload interface{}
+	if err := jps.parseContent(state.Content, &payload); err != nil {
+		return 0, fmt.Errorf("invalid JSON: %w", err)
+	}
+	edited, n, err := editFields(payload, segs, "", create, edit)
+	if err != nil {
+		return 0, err
+	}
+	if n == 0 {
+		return 0, fieldPathError("no field matches path", jsonPath)
+	}
+
+	formatted, err := json.MarshalIndent(edited, "", "  ")
+	if err != nil {
+		return 0, NewInternalError("edit produced invalid JSON", err)
+	}
+	if !json.Valid(formatted) {
+		return 0, NewValidationError("edit produced invalid JSON", nil)
+	}
+	jps.pushContent(state, string(formatted))
+	if jps.config.ValidateOnType {
+		result := jps.ValidateJSON(state.Content, state.Schema)
+		state.Errors = result.Errors
+		state.Warnings = result.Warnings
+	}
+	session.LastActivity = time.Now()
+	return n, nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2025 James Ross
+package jsonpayloadstudio
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const fieldEditPayload = `{"order": {"id": 7}, "items": [{"sku": "a", "status": "new"}, {"sku": "b"}], "meta": {"x-id": "q"}}`
+
+func sessionPayload(t *testing.T, studio *JSONPayloadStudio, sessionID string) map[string]interface{} {
+	t.Helper()
+	var got map[string]interface{}
+	if err := json.Unmarshal([]byte(studio.sessions[sessionID].EditorState.Content), &got); err != nil {
+		t.Fatalf("content is not valid JSON: %v", err)
+	}
+	return got
+}
+
+func TestParseFieldPath(t *testing.T) {
+	segs, err := parseFieldPath(`$.items[*].tags[2].meta["x-id"]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []fieldSegment{
+		{kind: segmentKey, key: "items"},
+		{kind: segmentWildcard},
+		{kind: segmentKey, key: "tags"},
+		{kind: segmentIndex, index: 2},
+		{kind: segmentKey, key: "meta"},
+		{kind: segmentKey, key: "x-id"},
+	}
+	if !reflect.DeepEqual(segs, want) {
+		t.Fatalf("unexpected segments: %+v", segs)
+	}
+	if segs, err := parseFieldPath("user.*"); err != nil || len(segs) != 2 || segs[1].kind != segmentWildcard {
+		t.Fatalf("expected a trailing wildcard, got %+v %v", segs, err)
+	}
+	for _, bad := range []string{"items[", "items[-1]", "items[x]", "a..b", `m["x]`, "a[0]b"} {
+		if _, err := parseFieldPath(bad); err == nil {
+			t.Fatalf("expected an error for %q", bad)
+		}
+	}
+}
+
+func TestSetFieldWildcardAndUndo(t *testing.T) {
+	studio, _, sessionID := newMatrixStudio(t, fieldEditPayload)
+
+	n, err := studio.SetField(sessionID, "items[*].status", "shipped")
+	if err != nil || n != 2 {
+		t.Fatalf("expected 2 fields set, got %d %v", n, err)
+	}
+	if _, err := studio.SetField(sessionID, "order.customer.name", "ann"); err != nil {
+		t.Fatal(err)
+	}
+	got := sessionPayload(t, studio, sessionID)
+	for _, item := range got["items"].([]interface{}) {
+		if item.(map[string]interface{})["status"] != "shipped" {
+			t.Fatalf("expected every item shipped, got %+v", got["items"])
+		}
+	}
+	if got["order"].(map[string]interface{})["customer"].(map[string]interface{})["name"] != "ann" {
+		t.Fatalf("expected missing parents to be created, got %+v", got["order"])
+	}
+
+	if err := studio.Undo(sessionID); err != nil {
+		t.Fatal(err)
+	}
+	got = sessionPayload(t, studio, sessionID)
+	if _, ok := got["order"].(map[string]interface{})["customer"]; ok {
+		t.Fatalf("expected undo to drop the last edit, got %+v", got["order"])
+	}
+	if got["items"].([]interface{})[1].(map[string]interface{})["status"] != "shipped" {
+		t.Fatalf("expected the first edit to remain, got %+v", got["items"])
+	}
+}
+
+func TestDeleteAndRenameField(t *testing.T) {
+	studio, _, sessionID := newMatrixStudio(t, fieldEditPayload)
+
+	if n, err := studio.RenameField(sessionID, "items[*].sku", "code"); err != nil || n != 2 {
+		t.Fatalf("expected 2 fields renamed, got %d %v", n, err)
+	}
+	if n, err := studio.DeleteField(sessionID, `meta["x-id"]`); err != nil || n != 1 {
+		t.Fatalf("expected 1 field deleted, got %d %v", n, err)
+	}
+	if n, err := studio.DeleteField(sessionID, "items[0]"); err != nil || n != 1 {
+		t.Fatalf("expected 1 element deleted, got %d %v", n, err)
+	}
+	got := sessionPayload(t, studio, sessionID)
+	want := map[string]interface{}{
+		"order": map[string]interface{}{"id": 7.0},
+		"items": []interface{}{map[string]interface{}{"code": "b"}},
+		"meta":  map[string]interface{}{},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected payload: %+v", got)
+	}
+
+	before := studio.sessions[sessionID].EditorState.Content
+	if _, err := studio.RenameField(sessionID, "order.id", "id"); err == nil {
+		t.Fatal("expected renaming a field to itself to match nothing")
+	}
+	if _, err := studio.SetField(sessionID, "order.ref", "r"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := studio.RenameField(sessionID, "order.ref", "id"); err == nil {
+		t.Fatal("expected an error renaming onto an existing field")
+	}
+	if _, err := studio.DeleteField(sessionID, "missing.field"); err == nil {
+		t.Fatal("expected an error for a path matching nothing")
+	}
+	if _, err := studio.RenameField(sessionID, "items[0]", "x"); err == nil {
+		t.Fatal("expected an error renaming an array element")
+	}
+	if _, err := studio.SetField("nope", "a", 1); err == nil {
+		t.Fatal("expected an error for an unknown session")
+	}
+	if got := studio.sessions[sessionID].EditorState.Content; got == before {
+		t.Fatal("expected the successful set to change the content")
+	}
+}
@@ -2,8 +2,10 @@ package jsonpayloadstudio
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -77,9 +79,15 @@ func (h *Handler) HandleFormat(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.sendJSON(w, map[string]string{
+	resp := map[string]interface{}{
 		"formatted": formatted,
-	})
+	}
+	if h.studio.config.LenientInput {
+		// FormatJSON succeeded, so normalizing cannot fail
+		_, changes, _ := NormalizeJSON(req.Content)
+		resp["normalized"] = changes
+	}
+	h.sendJSON(w, resp)
 }
 
 // HandleTemplates handles template operations
@@ -188,6 +196,76 @@ func (h *Handler) HandleApplyTemplate(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// HandleTemplateVersions handles template history requests. GET with id and
+// version returns that version; GET with id, from and to returns the diff
+// between two versions; POST rolls a template back to a version.
+func (h *Handler) HandleTemplateVersions(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.handleGetTemplateVersion(w, r)
+	case http.MethodPost:
+		h.handleRollbackTemplate(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) handleGetTemplateVersion(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	templateID := query.Get("id")
+	if templateID == "" {
+		http.Error(w, "Template ID required", http.StatusBadRequest)
+		return
+	}
+
+	if query.Has("from") || query.Has("to") {
+		from, errFrom := strconv.Atoi(query.Get("from"))
+		to, errTo := strconv.Atoi(query.Get("to"))
+		if errFrom != nil || errTo != nil {
+			http.Error(w, "from and to must be version numbers", http.StatusBadRequest)
+			return
+		}
+		diff, err := h.studio.DiffTemplateVersions(templateID, from, to)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to diff template versions: %v", err), http.StatusNotFound)
+			return
+		}
+		h.sendJSON(w, diff)
+		return
+	}
+
+	version, err := strconv.Atoi(query.Get("version"))
+	if err != nil {
+		http.Error(w, "version must be a version number", http.StatusBadRequest)
+		return
+	}
+	template, err := h.studio.GetTemplateVersion(templateID, version)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get template version: %v", err), http.StatusNotFound)
+		return
+	}
+	h.sendJSON(w, template)
+}
+
+func (h *Handler) handleRollbackTemplate(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TemplateID string `json:"template_id"`
+		Version    int    `json:"version"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	template, err := h.studio.RollbackTemplate(req.TemplateID, req.Version)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to roll back template: %v", err), http.StatusBadRequest)
+		return
+	}
+	h.sendJSON(w, template)
+}
+
 // HandleEnqueue handles job enqueue requests
 func (h *Handler) HandleEnqueue(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -214,6 +292,33 @@ func (h *Handler) HandleEnqueue(w http.ResponseWriter, r *http.Request) {
 	h.sendJSON(w, result)
 }
 
+// HandleEnqueueMatrix handles bulk enqueue of one job per variable set
+func (h *Handler) HandleEnqueueMatrix(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		SessionID string                   `json:"session_id"`
+		Variables []map[string]interface{} `json:"variables"`
+		Options   *MatrixEnqueueOptions    `json:"options"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.studio.EnqueueMatrix(req.SessionID, req.Variables, req.Options)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to enqueue: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	h.sendJSON(w, result)
+}
+
 // HandleSessions handles session operations
 func (h *Handler) HandleSessions(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
@@ -358,16 +463,59 @@ func (h *Handler) HandleSnippets(w http.ResponseWriter, r *http.Request) {
 		h.handleListSnippets(w, r)
 	case http.MethodPost:
 		h.handleExpandSnippet(w, r)
+	case http.MethodPut:
+		h.handleSaveSnippet(w, r)
+	case http.MethodDelete:
+		h.handleDeleteSnippet(w, r)
 	default:
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 	}
 }
 
 func (h *Handler) handleListSnippets(w http.ResponseWriter, r *http.Request) {
+	if query := r.URL.Query().Get("q"); query != "" {
+		h.sendJSON(w, h.studio.SearchSnippets(query))
+		return
+	}
 	snippets := h.studio.ListSnippets()
 	h.sendJSON(w, snippets)
 }
 
+func (h *Handler) handleSaveSnippet(w http.ResponseWriter, r *http.Request) {
+	var snippet Snippet
+	if err := json.NewDecoder(r.Body).Decode(&snippet); err != nil {
+		http.Error(w, "Invalid snippet data", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.studio.SaveSnippet(&snippet); err != nil {
+		status := http.StatusInternalServerError
+		var studioErr *StudioError
+		if errors.As(err, &studioErr) {
+			status = http.StatusBadRequest
+		}
+		http.Error(w, fmt.Sprintf("Failed to save snippet: %v", err), status)
+		return
+	}
+
+	h.sendJSON(w, snippet)
+}
+
+func (h *Handler) handleDeleteSnippet(w http.ResponseWriter, r *http.Request) {
+	snippetID := r.URL.Query().Get("id")
+	if snippetID == "" {
+		http.Error(w, "Snippet ID required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.studio.DeleteSnippet(snippetID); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to delete snippet: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (h *Handler) handleExpandSnippet(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		Trigger   string                 `json:"trigger"`
@@ -449,19 +597,152 @@ func (h *Handler) HandlePreview(w http.ResponseWriter, r *http.Request) {
 	h.sendJSON(w, preview)
 }
 
+// HandleScaffold generates a skeleton payload from a schema
+func (h *Handler) HandleScaffold(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	schemaID := r.URL.Query().Get("schema_id")
+	if schemaID == "" {
+		http.Error(w, "schema_id required", http.StatusBadRequest)
+		return
+	}
+
+	content, err := h.studio.GenerateFromSchema(schemaID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	h.sendJSON(w, map[string]string{"content": content})
+}
+
+// HandleRedaction previews secret stripping for a session (GET) or replaces
+// its redaction allow list (PUT)
+func (h *Handler) HandleRedaction(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		sessionID := r.URL.Query().Get("session_id")
+		if sessionID == "" {
+			http.Error(w, "session_id required", http.StatusBadRequest)
+			return
+		}
+
+		preview, err := h.studio.PreviewRedaction(sessionID)
+		if err != nil {
+			status := http.StatusBadRequest
+			if se, ok := err.(*StudioError); ok && se.Type == ErrorTypeSession {
+				status = http.StatusNotFound
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+		h.sendJSON(w, preview)
+
+	case http.MethodPut:
+		var req struct {
+			SessionID string   `json:"session_id"`
+			Allow     []string `json:"allow"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.SessionID == "" {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		if err := h.studio.SetRedactionAllow(req.SessionID, req.Allow); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		preview, err := h.studio.PreviewRedaction(req.SessionID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.sendJSON(w, preview)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleFieldEdit sets, deletes or renames a field across a session's
+// payload
+func (h *Handler) HandleFieldEdit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		SessionID string          `json:"session_id"`
+		Op        string          `json:"op"` // "set", "delete" or "rename"
+		Path      string          `json:"path"`
+		Value     json.RawMessage `json:"value,omitempty"`
+		NewName   string          `json:"new_name,omitempty"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.SessionID == "" {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	var changed int
+	var err error
+	switch req.Op {
+	case "set":
+		var value interface{}
+		if len(req.Value) == 0 {
+			http.Error(w, "value required", http.StatusBadRequest)
+			return
+		}
+		if err := json.Unmarshal(req.Value, &value); err != nil {
+			http.Error(w, "Invalid value", http.StatusBadRequest)
+			return
+		}
+		changed, err = h.studio.SetField(req.SessionID, req.Path, value)
+	case "delete":
+		changed, err = h.studio.DeleteField(req.SessionID, req.Path)
+	case "rename":
+		changed, err = h.studio.RenameField(req.SessionID, req.Path, req.NewName)
+	default:
+		http.Error(w, "Invalid op. Use 'set', 'delete' or 'rename'", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		status := http.StatusBadRequest
+		if se, ok := err.(*StudioError); ok && se.Type == ErrorTypeSession {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	session, _ := h.studio.GetSession(req.SessionID)
+	h.sendJSON(w, map[string]interface{}{
+		"changed": changed,
+		"session": session,
+	})
+}
+
 // RegisterRoutes registers all HTTP routes for the JSON Payload Studio
 func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/api/json-studio/validate", h.HandleValidate)
 	mux.HandleFunc("/api/json-studio/format", h.HandleFormat)
 	mux.HandleFunc("/api/json-studio/templates", h.HandleTemplates)
 	mux.HandleFunc("/api/json-studio/templates/apply", h.HandleApplyTemplate)
+	mux.HandleFunc("/api/json-studio/templates/versions", h.HandleTemplateVersions)
 	mux.HandleFunc("/api/json-studio/enqueue", h.HandleEnqueue)
+	mux.HandleFunc("/api/json-studio/enqueue/matrix", h.HandleEnqueueMatrix)
 	mux.HandleFunc("/api/json-studio/sessions", h.HandleSessions)
 	mux.HandleFunc("/api/json-studio/completions", h.HandleCompletions)
 	mux.HandleFunc("/api/json-studio/diff", h.HandleDiff)
 	mux.HandleFunc("/api/json-studio/snippets", h.HandleSnippets)
 	mux.HandleFunc("/api/json-studio/history", h.HandleHistory)
 	mux.HandleFunc("/api/json-studio/preview", h.HandlePreview)
+	mux.HandleFunc("/api/json-studio/scaffold", h.HandleScaffold)
+	mux.HandleFunc("/api/json-studio/redaction", h.HandleRedaction)
+	mux.HandleFunc("/api/json-studio/fields", h.HandleFieldEdit)
 }
 
 // Helper function to send JSON responses
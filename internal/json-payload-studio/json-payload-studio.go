@@ -14,6 +14,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/flyingrobots/go-redis-work-queue/internal/scheduler"
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	"github.com/xeipuuv/gojsonschema"
@@ -24,9 +27,12 @@ import (
 type JSONPayloadStudio struct {
 	config       *StudioConfig
 	redis        *redis.Client
+	queues       *config.Config
 	logger       *zap.Logger
 	templates    map[string]*Template
+	versions     map[string][]*Template // template history, oldest first
 	schemas      map[string]*JSONSchema
+	refs         *schemaRefResolver
 	snippets     map[string]*Snippet
 	sessions     map[string]*SessionInfo
 	lastEnqueued *EnqueueResult
@@ -45,6 +51,7 @@ func NewJSONPayloadStudio(config *StudioConfig, redis *redis.Client, logger *zap
 			AutoComplete:     true,
 			ValidateOnType:   true,
 			TemplatesPath:    "./templates",
+			SnippetsPath:     "./snippets",
 			SchemasPath:      "./schemas",
 			MaxPayloadSize:   1024 * 1024, // 1MB
 			MaxFieldCount:    1000,
@@ -63,6 +70,7 @@ func NewJSONPayloadStudio(config *StudioConfig, redis *redis.Client, logger *zap
 		redis:     redis,
 		logger:    logger,
 		templates: make(map[string]*Template),
+		versions:  make(map[string][]*Template),
 		schemas:   make(map[string]*JSONSchema),
 		snippets:  make(map[string]*Snippet),
 		sessions:  make(map[string]*SessionInfo),
@@ -72,13 +80,20 @@ func NewJSONPayloadStudio(config *StudioConfig, redis *redis.Client, logger *zap
 	if err := studio.loadTemplates(); err != nil {
 		logger.Warn("Failed to load templates", zap.Error(err))
 	}
+	if err := studio.loadTemplateVersions(); err != nil {
+		logger.Warn("Failed to load template versions", zap.Error(err))
+	}
 
 	if err := studio.loadSchemas(); err != nil {
 		logger.Warn("Failed to load schemas", zap.Error(err))
 	}
+	studio.refs = newSchemaRefResolver(config, studio.schemas)
 
-	// Initialize default snippets
+	// Initialize default snippets, then user snippets from disk
 	studio.initializeSnippets()
+	if err := studio.loadSnippets(); err != nil {
+		logger.Warn("Failed to load snippets", zap.Error(err))
+	}
 
 	return studio, nil
 }
@@ -138,17 +153,7 @@ func (jps *JSONPayloadStudio) UpdateEditorState(sessionID string, newState *Edit
 	}
 
 	if newState.Content != "" && newState.Content != state.Content {
-		if state.HistoryIndex < len(state.History)-1 {
-			state.History = state.History[:state.HistoryIndex+1]
-		}
-		state.History = append(state.History, state.Content)
-		if jps.config.HistorySize > 0 && len(state.History) > jps.config.HistorySize {
-			state.History = state.History[1:]
-		} else if len(state.History) > 0 {
-			state.HistoryIndex++
-		}
-		state.Content = newState.Content
-		state.Modified = true
+		jps.pushContent(state, newState.Content)
 	}
 
 	state.CursorLine = newState.CursorLine
@@ -171,6 +176,22 @@ func (jps *JSONPayloadStudio) UpdateEditorState(sessionID string, newState *Edit
 	return nil
 }
 
+// pushContent replaces the editor content, keeping the previous content in
+// the undo history.
+func (jps *JSONPayloadStudio) pushContent(state *EditorState, content string) {
+	if state.HistoryIndex < len(state.History)-1 {
+		state.History = state.History[:state.HistoryIndex+1]
+	}
+	state.History = append(state.History, state.Content)
+	if jps.config.HistorySize > 0 && len(state.History) > jps.config.HistorySize {
+		state.History = state.History[1:]
+	} else if len(state.History) > 0 {
+		state.HistoryIndex++
+	}
+	state.Content = content
+	state.Modified = true
+}
+
 // GetSession returns a snapshot of the session by ID.
 func (jps *JSONPayloadStudio) GetSession(sessionID string) (*SessionInfo, error) {
 	jps.mu.RLock()
@@ -237,7 +258,9 @@ func (jps *JSONPayloadStudio) ListTemplates() []Template {
 	return result
 }
 
-// SaveTemplate stores or updates a template in memory.
+// SaveTemplate stores or updates a template. Each save is recorded as a
+// new version; template.CurrentVersion and template.Versions are set to
+// match.
 func (jps *JSONPayloadStudio) SaveTemplate(template *Template) error {
 	if template == nil {
 		return fmt.Errorf("template is nil")
@@ -246,7 +269,11 @@ func (jps *JSONPayloadStudio) SaveTemplate(template *Template) error {
 	jps.mu.Lock()
 	defer jps.mu.Unlock()
 
-	jps.templates[template.ID] = cloneTemplate(template)
+	saved := cloneTemplate(template)
+	if err := jps.saveTemplateLocked(saved); err != nil {
+		return err
+	}
+	template.CurrentVersion, template.Versions = saved.CurrentVersion, saved.Versions
 	return nil
 }
 
@@ -258,7 +285,11 @@ func (jps *JSONPayloadStudio) DeleteTemplate(id string) error {
 	if _, exists := jps.templates[id]; !exists {
 		return fmt.Errorf("template not found: %s", id)
 	}
+	if err := jps.deleteTemplateFromDisk(id); err != nil {
+		return fmt.Errorf("delete template %s: %w", id, err)
+	}
 	delete(jps.templates, id)
+	delete(jps.versions, id)
 	return nil
 }
 
@@ -485,6 +516,32 @@ func (jps *JSONPayloadStudio) ValidateJSON(content string, schema *JSONSchema) *
 		Info:     make([]ValidationError, 0),
 	}
 
+	// Normalize JSON5/JSONC input, reporting each change
+	if jps.config.LenientInput {
+		normalized, changes, err := NormalizeJSON(content)
+		if err != nil {
+			result.Valid = false
+			result.Errors = append(result.Errors, ValidationError{
+				Line:     1,
+				Column:   1,
+				Type:     "syntax",
+				Message:  err.Error(),
+				Severity: "error",
+			})
+			return result
+		}
+		for _, ch := range changes {
+			result.Info = append(result.Info, ValidationError{
+				Line:     ch.Line,
+				Column:   ch.Column,
+				Type:     "normalized",
+				Message:  ch.Message,
+				Severity: "info",
+			})
+		}
+		content = normalized
+	}
+
 	// Parse JSON
 	var parsed interface{}
 	decoder := json.NewDecoder(strings.NewReader(content))
@@ -527,31 +584,40 @@ func (jps *JSONPayloadStudio) ValidateJSON(content string, schema *JSONSchema) *
 		}
 	}
 
-	// Check size limits
-	if len(content) > jps.config.MaxPayloadSize {
-		result.Warnings = append(result.Warnings, ValidationError{
-			Type:     "size",
-			Message:  fmt.Sprintf("Payload size (%d bytes) exceeds maximum (%d bytes)", len(content), jps.config.MaxPayloadSize),
-			Severity: "warning",
-		})
-	}
-
-	// Check field count
-	if result.Stats.Keys > jps.config.MaxFieldCount {
-		result.Warnings = append(result.Warnings, ValidationError{
-			Type:     "complexity",
-			Message:  fmt.Sprintf("Field count (%d) exceeds maximum (%d)", result.Stats.Keys, jps.config.MaxFieldCount),
-			Severity: "warning",
-		})
-	}
-
-	// Check nesting depth
-	if result.Stats.MaxDepth > jps.config.MaxNestingDepth {
-		result.Warnings = append(result.Warnings, ValidationError{
-			Type:     "complexity",
-			Message:  fmt.Sprintf("Nesting depth (%d) exceeds maximum (%d)", result.Stats.MaxDepth, jps.config.MaxNestingDepth),
-			Severity: "warning",
-		})
+	// Check size, field count and nesting depth: errors when the limits
+	// are enforced, warnings otherwise
+	if jps.config.EnforceComplexityLimits {
+		if err := jps.checkComplexityLimits(parsed, content); err != nil {
+			result.Valid = false
+			result.Errors = append(result.Errors, ValidationError{
+				Type:     string(err.Type),
+				Message:  err.Message,
+				Path:     err.Path,
+				Severity: "error",
+			})
+		}
+	} else {
+		if len(content) > jps.config.MaxPayloadSize {
+			result.Warnings = append(result.Warnings, ValidationError{
+				Type:     "size",
+				Message:  fmt.Sprintf("Payload size (%d bytes) exceeds maximum (%d bytes)", len(content), jps.config.MaxPayloadSize),
+				Severity: "warning",
+			})
+		}
+		if result.Stats.Keys > jps.config.MaxFieldCount {
+			result.Warnings = append(result.Warnings, ValidationError{
+				Type:     "complexity",
+				Message:  fmt.Sprintf("Field count (%d) exceeds maximum (%d)", result.Stats.Keys, jps.config.MaxFieldCount),
+				Severity: "warning",
+			})
+		}
+		if result.Stats.MaxDepth > jps.config.MaxNestingDepth {
+			result.Warnings = append(result.Warnings, ValidationError{
+				Type:     "complexity",
+				Message:  fmt.Sprintf("Nesting depth (%d) exceeds maximum (%d)", result.Stats.MaxDepth, jps.config.MaxNestingDepth),
+				Severity: "warning",
+			})
+		}
 	}
 
 	// Check for potential secrets
@@ -572,10 +638,11 @@ func (jps *JSONPayloadStudio) ValidateJSON(content string, schema *JSONSchema) *
 	return result
 }
 
-// FormatJSON formats JSON content
+// FormatJSON formats JSON content, normalizing JSON5/JSONC first when
+// LenientInput is on
 func (jps *JSONPayloadStudio) FormatJSON(content string) (string, error) {
 	var parsed interface{}
-	if err := json.Unmarshal([]byte(content), &parsed); err != nil {
+	if err := jps.parseContent(content, &parsed); err != nil {
 		return "", fmt.Errorf("invalid JSON: %w", err)
 	}
 
@@ -753,17 +820,22 @@ func (jps *JSONPayloadStudio) EnqueuePayload(sessionID string, options *EnqueueO
 
 	// Parse payload
 	var payload interface{}
-	if err := json.Unmarshal([]byte(session.EditorState.Content), &payload); err != nil {
+	if err := jps.parseContent(session.EditorState.Content, &payload); err != nil {
 		return nil, fmt.Errorf("invalid JSON: %w", err)
 	}
 
-	// Strip secrets if configured
+	// Strip secrets if configured, keeping the session's allowed fields
 	if jps.config.StripSecrets {
-		payload = jps.stripSecrets(payload)
+		payload = jps.stripSessionSecrets(session, payload)
 	}
 
-	// Validate size
+	// Validate size, and depth and field count when enforced
 	payloadBytes, _ := json.Marshal(payload)
+	if jps.config.EnforceComplexityLimits {
+		if err := jps.checkComplexityLimits(payload, string(payloadBytes)); err != nil {
+			return nil, err
+		}
+	}
 	if len(payloadBytes) > jps.config.MaxPayloadSize {
 		return nil, fmt.Errorf("payload too large: %d bytes (max: %d)", len(payloadBytes), jps.config.MaxPayloadSize)
 	}
@@ -779,51 +851,7 @@ func (jps *JSONPayloadStudio) EnqueuePayload(sessionID string, options *EnqueueO
 	pipe := jps.redis.Pipeline()
 
 	for i := 0; i < options.Count; i++ {
-		job := map[string]interface{}{
-			"id":         jobIDs[i],
-			"payload":    payload,
-			"priority":   options.Priority,
-			"created_at": time.Now().Unix(),
-			"metadata":   options.Metadata,
-		}
-
-		if options.MaxRetries > 0 {
-			job["max_retries"] = options.MaxRetries
-		}
-
-		if options.TTL > 0 {
-			job["ttl"] = options.TTL.Seconds()
-		}
-
-		jobData, _ := json.Marshal(job)
-
-		// Handle scheduling
-		if options.RunAt != nil {
-			// Scheduled job
-			score := float64(options.RunAt.Unix())
-			pipe.ZAdd(ctx, fmt.Sprintf("scheduled:%s", options.Queue), redis.Z{
-				Score:  score,
-				Member: string(jobData),
-			})
-		} else if options.Delay > 0 {
-			// Delayed job
-			runAt := time.Now().Add(options.Delay)
-			score := float64(runAt.Unix())
-			pipe.ZAdd(ctx, fmt.Sprintf("delayed:%s", options.Queue), redis.Z{
-				Score:  score,
-				Member: string(jobData),
-			})
-		} else {
-			// Immediate job
-			if options.Priority > 0 {
-				pipe.ZAdd(ctx, fmt.Sprintf("priority:%s", options.Queue), redis.Z{
-					Score:  float64(options.Priority),
-					Member: string(jobData),
-				})
-			} else {
-				pipe.RPush(ctx, fmt.Sprintf("queue:%s", options.Queue), string(jobData))
-			}
-		}
+		jps.queueJob(ctx, pipe, jobIDs[i], payload, options)
 	}
 
 	// Handle cron scheduling
@@ -871,6 +899,108 @@ func (jps *JSONPayloadStudio) EnqueuePayload(sessionID string, options *EnqueueO
 	return result, nil
 }
 
+// SetQueueConfig makes scheduled (run_at) and delayed jobs join the queue
+// system's worker.queues: options.Queue is then a priority alias or queue
+// key, and the jobs wait in that queue's scheduled: and delayed: sets,
+// inside the namespace, until the scheduler promotes them. Without it they
+// wait under options.Queue as given.
+func (jps *JSONPayloadStudio) SetQueueConfig(cfg *config.Config) {
+	jps.mu.Lock()
+	defer jps.mu.Unlock()
+	jps.queues = cfg
+}
+
+// scheduledJob is a job waiting for the scheduler: a queue.Job, so workers
+// can decode it once promoted, carrying the studio payload alongside.
+type scheduledJob struct {
+	queue.Job
+	Payload    interface{}       `json:"payload"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+	MaxRetries int               `json:"max_retries,omitempty"`
+	TTL        float64           `json:"ttl,omitempty"`
+}
+
+// queueJob adds one job to pipe, routing it to the scheduled, delayed,
+// priority or plain queue according to options.
+func (jps *JSONPayloadStudio) queueJob(ctx context.Context, pipe redis.Pipeliner, jobID string, payload interface{}, options *EnqueueOptions) {
+	if options.RunAt != nil || options.Delay > 0 {
+		queueKey, priority := jps.scheduleTarget(options.Queue)
+		job := scheduledJob{
+			Job: queue.Job{
+				ID:           jobID,
+				Priority:     priority,
+				CreationTime: time.Now().UTC().Format(time.RFC3339Nano),
+			},
+			Payload:    payload,
+			Metadata:   options.Metadata,
+			MaxRetries: options.MaxRetries,
+			TTL:        options.TTL.Seconds(),
+		}
+		jobData, _ := json.Marshal(job)
+
+		derive, runAt := scheduler.DelayedKey, time.Now().Add(options.Delay)
+		if options.RunAt != nil {
+			derive, runAt = scheduler.ScheduledKey, *options.RunAt
+		}
+		zkey := derive(queueKey)
+		if jps.queues != nil {
+			zkey = jps.queues.DerivedKey(derive, queueKey)
+		}
+		pipe.ZAdd(ctx, zkey, redis.Z{
+			Score:  scheduler.Score(runAt),
+			Member: string(jobData),
+		})
+		return
+	}
+
+	job := map[string]interface{}{
+		"id":         jobID,
+		"payload":    payload,
+		"priority":   options.Priority,
+		"created_at": time.Now().Unix(),
+		"metadata":   options.Metadata,
+	}
+
+	if options.MaxRetries > 0 {
+		job["max_retries"] = options.MaxRetries
+	}
+
+	if options.TTL > 0 {
+		job["ttl"] = options.TTL.Seconds()
+	}
+
+	jobData, _ := json.Marshal(job)
+
+	// Immediate job
+	if options.Priority > 0 {
+		pipe.ZAdd(ctx, fmt.Sprintf("priority:%s", options.Queue), redis.Z{
+			Score:  float64(options.Priority),
+			Member: string(jobData),
+		})
+	} else {
+		pipe.RPush(ctx, fmt.Sprintf("queue:%s", options.Queue), string(jobData))
+	}
+}
+
+// scheduleTarget resolves name, a priority alias or queue key, to the
+// worker.queues key scheduled jobs are promoted onto and its priority.
+// Names the queue config does not know are taken as keys.
+func (jps *JSONPayloadStudio) scheduleTarget(name string) (string, string) {
+	if jps.queues == nil {
+		return name, ""
+	}
+	if key, ok := jps.queues.Worker.Queues[name]; ok && key != "" {
+		return key, name
+	}
+	key := jps.queues.Key(name)
+	for priority, k := range jps.queues.Worker.Queues {
+		if k == key {
+			return key, priority
+		}
+	}
+	return name, ""
+}
+
 // GetDiff compares current editor content with last enqueued payload
 func (jps *JSONPayloadStudio) GetDiff(sessionID string) (*DiffResult, error) {
 	jps.mu.RLock()
@@ -890,7 +1020,7 @@ func (jps *JSONPayloadStudio) GetDiff(sessionID string) (*DiffResult, error) {
 
 	// Parse current content
 	var current interface{}
-	if err := json.Unmarshal([]byte(session.EditorState.Content), &current); err != nil {
+	if err := jps.parseContent(session.EditorState.Content, &current); err != nil {
 		return nil, fmt.Errorf("invalid JSON: %w", err)
 	}
 
@@ -951,7 +1081,7 @@ func (jps *JSONPayloadStudio) SaveTemplateFromSession(sessionID string, name, de
 
 	// Parse content
 	var content map[string]interface{}
-	if err := json.Unmarshal([]byte(session.EditorState.Content), &content); err != nil {
+	if err := jps.parseContent(session.EditorState.Content, &content); err != nil {
 		return nil, fmt.Errorf("invalid JSON: %w", err)
 	}
 
@@ -972,13 +1102,10 @@ func (jps *JSONPayloadStudio) SaveTemplateFromSession(sessionID string, name, de
 	// Extract variables
 	template.Variables = jps.extractVariables(content)
 
-	// Save to disk
-	if err := jps.saveTemplateToDisk(template); err != nil {
+	if err := jps.saveTemplateLocked(template); err != nil {
 		return nil, err
 	}
 
-	jps.templates[template.ID] = template
-
 	// Track template usage
 	session.Templates = append(session.Templates, template.ID)
 
@@ -1007,6 +1134,10 @@ func (jps *JSONPayloadStudio) loadTemplates() error {
 			return err
 		}
 
+		// template history is loaded by loadTemplateVersions
+		if info.IsDir() && path == filepath.Join(jps.config.TemplatesPath, templateVersionsDir) {
+			return filepath.SkipDir
+		}
 		if !strings.HasSuffix(path, ".json") {
 			return nil
 		}
@@ -1066,8 +1197,15 @@ func (jps *JSONPayloadStudio) loadSchemas() error {
 }
 
 func (jps *JSONPayloadStudio) initializeSnippets() {
-	// Default snippets
-	jps.snippets["now"] = &Snippet{
+	for id, snippet := range defaultSnippets() {
+		jps.snippets[id] = snippet
+	}
+}
+
+// defaultSnippets returns the built-in snippets, keyed by ID.
+func defaultSnippets() map[string]*Snippet {
+	snippets := make(map[string]*Snippet)
+	snippets["now"] = &Snippet{
 		ID:          "now",
 		Name:        "Current Timestamp",
 		Trigger:     "now",
@@ -1076,7 +1214,7 @@ func (jps *JSONPayloadStudio) initializeSnippets() {
 		Expansion:   `"${TIMESTAMP}"`,
 	}
 
-	jps.snippets["uuid"] = &Snippet{
+	snippets["uuid"] = &Snippet{
 		ID:          "uuid",
 		Name:        "UUID",
 		Trigger:     "uuid",
@@ -1085,7 +1223,7 @@ func (jps *JSONPayloadStudio) initializeSnippets() {
 		Expansion:   `"${UUID}"`,
 	}
 
-	jps.snippets["date"] = &Snippet{
+	snippets["date"] = &Snippet{
 		ID:          "date",
 		Name:        "Current Date",
 		Trigger:     "date",
@@ -1094,7 +1232,7 @@ func (jps *JSONPayloadStudio) initializeSnippets() {
 		Expansion:   `"${DATE}"`,
 	}
 
-	jps.snippets["user"] = &Snippet{
+	snippets["user"] = &Snippet{
 		ID:          "user",
 		Name:        "User Info",
 		Trigger:     "user",
@@ -1106,6 +1244,7 @@ func (jps *JSONPayloadStudio) initializeSnippets() {
 			"name":    "${USER_NAME}",
 		},
 	}
+	return snippets
 }
 
 func (jps *JSONPayloadStudio) expandSnippet(snippet *Snippet) string {
@@ -1135,10 +1274,20 @@ func (jps *JSONPayloadStudio) validateAgainstSchema(data interface{}, schema *JS
 	dataJSON, _ := json.Marshal(data)
 	schemaJSON, _ := json.Marshal(schema)
 
-	schemaLoader := gojsonschema.NewBytesLoader(schemaJSON)
-	documentLoader := gojsonschema.NewBytesLoader(dataJSON)
-
-	result, err := gojsonschema.Validate(schemaLoader, documentLoader)
+	compiled, err := jps.refs.compile(schemaJSON)
+	if refErr, ok := asSchemaRefError(err); ok {
+		errors = append(errors, ValidationError{
+			Type:       "schema",
+			Message:    fmt.Sprintf("Schema reference error: %v", refErr),
+			SchemaPath: refErr.Ref,
+			Severity:   "error",
+		})
+		return errors
+	}
+	var result *gojsonschema.Result
+	if err == nil {
+		result, err = compiled.Validate(gojsonschema.NewBytesLoader(dataJSON))
+	}
 	if err != nil {
 		errors = append(errors, ValidationError{
 			Type:     "schema",
@@ -1206,33 +1355,7 @@ func (jps *JSONPayloadStudio) detectSecrets(content string) []ValidationError {
 }
 
 func (jps *JSONPayloadStudio) stripSecrets(data interface{}) interface{} {
-	switch v := data.(type) {
-	case map[string]interface{}:
-		result := make(map[string]interface{})
-		for key, value := range v {
-			lowerKey := strings.ToLower(key)
-			if strings.Contains(lowerKey, "password") ||
-				strings.Contains(lowerKey, "secret") ||
-				strings.Contains(lowerKey, "token") ||
-				strings.Contains(lowerKey, "key") ||
-				strings.Contains(lowerKey, "auth") {
-				result[key] = "***REDACTED***"
-			} else {
-				result[key] = jps.stripSecrets(value)
-			}
-		}
-		return result
-
-	case []interface{}:
-		result := make([]interface{}, len(v))
-		for i, item := range v {
-			result[i] = jps.stripSecrets(item)
-		}
-		return result
-
-	default:
-		return v
-	}
+	return redactSecrets(data, "", nil, nil)
 }
 
 func (jps *JSONPayloadStudio) matchesFilter(template *Template, filter *TemplateFilter) bool {
@@ -1412,12 +1535,7 @@ func (jps *JSONPayloadStudio) compareValues(old, new interface{}, path string, d
 		if newVal, ok := new.(map[string]interface{}); ok {
 			// Compare objects
 			for key, oldValue := range oldVal {
-				newPath := path
-				if newPath == "" {
-					newPath = key
-				} else {
-					newPath = path + "." + key
-				}
+				newPath := childPath(path, key)
 
 				if newValue, exists := newVal[key]; exists {
 					jps.compareValues(oldValue, newValue, newPath, diff)
@@ -1432,12 +1550,7 @@ func (jps *JSONPayloadStudio) compareValues(old, new interface{}, path string, d
 
 			for key, newValue := range newVal {
 				if _, exists := oldVal[key]; !exists {
-					newPath := path
-					if newPath == "" {
-						newPath = key
-					} else {
-						newPath = path + "." + key
-					}
+					newPath := childPath(path, key)
 					diff.Added = append(diff.Added, DiffChange{
 						Path:     newPath,
 						Type:     "added",
@@ -0,0 +1,192 @@
+// Copyright 2025 James Ross
+package jsonpayloadstudio
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Kinds of change NormalizeJSON makes.
+const (
+	NormalizedComment       = "comment"
+	NormalizedTrailingComma = "trailing_comma"
+	NormalizedUnquotedKey   = "unquoted_key"
+	NormalizedSingleQuotes  = "single_quoted_string"
+)
+
+// Normalization is one change NormalizeJSON made to lenient input. Line
+// and Column point into the input.
+type Normalization struct {
+	Kind    string `json:"kind"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+}
+
+// NormalizeJSON rewrites JSON5/JSONC style input as strict JSON: comments
+// (// and /* */) are dropped, trailing commas before } or ] removed,
+// identifier keys quoted and single-quoted strings double-quoted. Newlines
+// inside block comments are kept, so lines in the output match the input.
+// Anything else is copied as is and left for the JSON parser to reject.
+func NormalizeJSON(content string) (string, []Normalization, error) {
+	var changes []Normalization
+	note := func(kind string, offset int, msg string) {
+		line, col := getLineColumn(content, offset)
+		changes = append(changes, Normalization{Kind: kind, Line: line, Column: col, Message: msg})
+	}
+
+	out := make([]byte, 0, len(content))
+	var stack []byte   // open '{' and '['
+	var last byte      // last significant byte written
+	pendingComma := -1 // index in out of a comma that may be trailing
+	commaAt := 0       // its offset in content
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			out = append(out, c)
+			continue
+
+		case c == '/' && i+1 < len(content) && content[i+1] == '/':
+			note(NormalizedComment, i, "removed line comment")
+			for i+1 < len(content) && content[i+1] != '\n' {
+				i++
+			}
+			continue
+
+		case c == '/' && i+1 < len(content) && content[i+1] == '*':
+			note(NormalizedComment, i, "removed block comment")
+			start := i
+			for i += 2; ; i++ {
+				if i+1 >= len(content) {
+					line, col := getLineColumn(content, start)
+					return "", changes, fmt.Errorf("unterminated block comment at line %d, column %d", line, col)
+				}
+				if content[i] == '*' && content[i+1] == '/' {
+					i++
+					break
+				}
+				if content[i] == '\n' {
+					out = append(out, '\n')
+				}
+			}
+			continue
+		}
+
+		// c is significant: settle a pending comma first
+		if pendingComma >= 0 {
+			if c == '}' || c == ']' {
+				out = append(out[:pendingComma], out[pendingComma+1:]...)
+				note(NormalizedTrailingComma, commaAt, "removed trailing comma")
+			}
+			pendingComma = -1
+		}
+		inObject := len(stack) > 0 && stack[len(stack)-1] == '{'
+
+		switch {
+		case c == '"':
+			end, err := stringEnd(content, i, '"')
+			if err != nil {
+				return "", changes, err
+			}
+			out = append(out, content[i:end+1]...)
+			i = end
+
+		case c == '\'':
+			end, err := stringEnd(content, i, '\'')
+			if err != nil {
+				return "", changes, err
+			}
+			note(NormalizedSingleQuotes, i, "converted single-quoted string to double quotes")
+			out = append(out, requote(content[i+1:end])...)
+			i = end
+			c = '"'
+
+		case inObject && (last == '{' || last == ',') && isIdentStart(c):
+			end := i
+			for end < len(content) && isIdentPart(content[end]) {
+				end++
+			}
+			key := content[i:end]
+			note(NormalizedUnquotedKey, i, fmt.Sprintf("quoted key %s", key))
+			quoted, _ := json.Marshal(key)
+			out = append(out, quoted...)
+			i = end - 1
+			c = '"'
+
+		default:
+			switch c {
+			case '{', '[':
+				stack = append(stack, c)
+			case '}', ']':
+				if len(stack) > 0 {
+					stack = stack[:len(stack)-1]
+				}
+			case ',':
+				pendingComma = len(out)
+				commaAt = i
+			}
+			out = append(out, c)
+		}
+		last = c
+	}
+	return string(out), changes, nil
+}
+
+// stringEnd returns the index of the quote closing the string that opens
+// at content[start].
+func stringEnd(content string, start int, quote byte) (int, error) {
+	for i := start + 1; i < len(content); i++ {
+		switch content[i] {
+		case '\\':
+			i++
+		case quote:
+			return i, nil
+		case '\n':
+			i = len(content)
+		}
+	}
+	line, col := getLineColumn(content, start)
+	return 0, fmt.Errorf("unterminated string at line %d, column %d", line, col)
+}
+
+// requote turns the body of a single-quoted string into a double-quoted
+// JSON string: \' loses its backslash and bare " gains one.
+func requote(body string) []byte {
+	out := make([]byte, 0, len(body)+2)
+	out = append(out, '"')
+	for i := 0; i < len(body); i++ {
+		switch c := body[i]; {
+		case c == '\\' && i+1 < len(body) && body[i+1] == '\'':
+			out = append(out, '\'')
+			i++
+		case c == '\\' && i+1 < len(body):
+			out = append(out, c, body[i+1])
+			i++
+		case c == '"':
+			out = append(out, '\\', '"')
+		default:
+			out = append(out, c)
+		}
+	}
+	return append(out, '"')
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool { return isIdentStart(c) || (c >= '0' && c <= '9') }
+
+// parseContent unmarshals editor content into v, normalizing it first when
+// LenientInput is on.
+func (jps *JSONPayloadStudio) parseContent(content string, v interface{}) error {
+	if jps.config.LenientInput {
+		normalized, _, err := NormalizeJSON(content)
+		if err != nil {
+			return err
+		}
+		content = normalized
+	}
+	return json.Unmarshal([]byte(content), v)
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 James Ross
+package jsonpayloadstudio
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const lenientPayload = `{
+  // who to bill
+  user: 'o\'brien "ob"',
+  tags: ["a", "b",], /* kept lines
+  across */ n: 2,
+  "url": "http://x/y", // not a comment inside a string
+}`
+
+func TestNormalizeJSON(t *testing.T) {
+	out, changes, err := NormalizeJSON(lenientPayload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("expected strict JSON, got %v:\n%s", err, out)
+	}
+	if got["user"] != `o'brien "ob"` || got["n"] != 2.0 || got["url"] != "http://x/y" || len(got["tags"].([]interface{})) != 2 {
+		t.Fatalf("unexpected values: %+v", got)
+	}
+	if strings.Count(out, "\n") != strings.Count(lenientPayload, "\n") {
+		t.Fatalf("expected lines to be kept:\n%s", out)
+	}
+
+	kinds := map[string]int{}
+	for _, c := range changes {
+		kinds[c.Kind]++
+	}
+	want := map[string]int{NormalizedComment: 3, NormalizedUnquotedKey: 3, NormalizedSingleQuotes: 1, NormalizedTrailingComma: 2}
+	for k, n := range want {
+		if kinds[k] != n {
+			t.Fatalf("expected %d %s changes, got %+v", n, k, changes)
+		}
+	}
+	if c := changes[0]; c.Kind != NormalizedComment || c.Line != 2 || c.Column != 3 {
+		t.Fatalf("expected the first comment at 2:3, got %+v", c)
+	}
+
+	if out, changes, _ := NormalizeJSON(`{"a": [1, 2]}`); out != `{"a": [1, 2]}` || len(changes) != 0 {
+		t.Fatalf("strict JSON should pass through unchanged, got %q %+v", out, changes)
+	}
+	for _, bad := range []string{`{"a": 1 /* open`, `{'a: 1}`} {
+		if _, _, err := NormalizeJSON(bad); err == nil {
+			t.Fatalf("expected an error for %q", bad)
+		}
+	}
+}
+
+func TestLenientInputValidatesAndEnqueuesStrictJSON(t *testing.T) {
+	studio, rdb, sessionID := newMatrixStudio(t, `{job: 'resize', size: 3,} // thumbnail`)
+	studio.config.MaxPayloadSize = 1024
+
+	if res := studio.ValidateJSON(`{job: 1,}`, nil); res.Valid {
+		t.Fatalf("expected lenient input to be rejected by default, got %+v", res)
+	}
+	if _, err := studio.EnqueuePayload(sessionID, &EnqueueOptions{Queue: "load", Count: 1}); err == nil {
+		t.Fatal("expected the enqueue to fail without lenient input")
+	}
+
+	studio.config.LenientInput = true
+	res := studio.ValidateJSON(`{job: 1,}`, nil)
+	if !res.Valid || len(res.Info) != 2 || res.Info[0].Type != "normalized" {
+		t.Fatalf("expected a valid result reporting 2 changes, got %+v", res)
+	}
+	formatted, err := studio.FormatJSON(`{job: 1,}`)
+	if err != nil || formatted != "{\n  \"job\": 1\n}" {
+		t.Fatalf("unexpected formatted output %q (%v)", formatted, err)
+	}
+
+	if _, err := studio.EnqueuePayload(sessionID, &EnqueueOptions{Queue: "load", Count: 1}); err != nil {
+		t.Fatal(err)
+	}
+	item, _ := rdb.LIndex(context.Background(), "queue:load", 0).Result()
+	var job struct {
+		Payload json.RawMessage `json:"payload"`
+	}
+	if err := json.Unmarshal([]byte(item), &job); err != nil {
+		t.Fatal(err)
+	}
+	if string(job.Payload) != `{"job":"resize","size":3}` {
+		t.Fatalf("expected strict JSON on the queue, got %s", job.Payload)
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2025 James Ross
+package jsonpayloadstudio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// matrixPlaceholder matches {{name}} and ${name} in template text.
+var matrixPlaceholder = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}|\$\{([A-Za-z0-9_.-]+)\}`)
+
+// EnqueueMatrix renders the session content once per variable set and
+// enqueues one job per rendered item in a single pipeline. Placeholders are
+// substituted textually: string values are JSON-escaped, so "{{name}}" stays
+// a string, while other values are inserted as JSON, so a bare {{count}}
+// becomes a number. Every item is rendered, secret-stripped and size-checked
+// before anything is sent to Redis.
+func (jps *JSONPayloadStudio) EnqueueMatrix(sessionID string, variables []map[string]interface{}, options *MatrixEnqueueOptions) (*MatrixEnqueueResult, error) {
+	jps.mu.Lock()
+	defer jps.mu.Unlock()
+
+	session, exists := jps.sessions[sessionID]
+	if !exists {
+		return nil, fmt.Errorf("session not found")
+	}
+	if len(variables) == 0 {
+		return nil, fmt.Errorf("no variable sets provided")
+	}
+	if options == nil {
+		options = &MatrixEnqueueOptions{}
+	}
+
+	payloads := make([]interface{}, len(variables))
+	valid := make([]bool, len(variables))
+	var skipped []MatrixItemError
+	for i, vars := range variables {
+		payload, err := jps.renderMatrixItem(session, vars)
+		if err != nil {
+			skipped = append(skipped, MatrixItemError{Index: i, Error: err.Error()})
+			continue
+		}
+		payloads[i], valid[i] = payload, true
+	}
+	if len(skipped) > 0 && options.AllOrNothing {
+		return nil, fmt.Errorf("%d of %d items invalid, nothing enqueued; item %d: %s",
+			len(skipped), len(variables), skipped[0].Index, skipped[0].Error)
+	}
+	if len(skipped) == len(variables) {
+		return nil, fmt.Errorf("all %d items invalid; item %d: %s", len(variables), skipped[0].Index, skipped[0].Error)
+	}
+
+	ctx := context.Background()
+	pipe := jps.redis.Pipeline()
+	jobIDs := make([]string, len(variables))
+	count := 0
+	for i, payload := range payloads {
+		if !valid[i] {
+			continue
+		}
+		jobIDs[i] = uuid.New().String()
+		jps.queueJob(ctx, pipe, jobIDs[i], payload, &options.EnqueueOptions)
+		count++
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to enqueue: %w", err)
+	}
+
+	session.JobsEnqueued += count
+
+	jps.logger.Info("Payload matrix enqueued",
+		zap.String("session", sessionID),
+		zap.String("queue", options.Queue),
+		zap.Int("count", count),
+		zap.Int("skipped", len(skipped)))
+
+	return &MatrixEnqueueResult{
+		JobIDs:     jobIDs,
+		Queue:      options.Queue,
+		Count:      count,
+		Skipped:    skipped,
+		EnqueuedAt: time.Now(),
+	}, nil
+}
+
+// renderMatrixItem substitutes vars into the session content and returns
+// the decoded payload, secret-stripped per the session's allow list,
+// enforcing MaxPayloadSize (and, with EnforceComplexityLimits, depth and
+// field count) on the result.
+func (jps *JSONPayloadStudio) renderMatrixItem(session *SessionInfo, vars map[string]interface{}) (interface{}, error) {
+	content := session.EditorState.Content
+	var renderErr error
+	rendered := matrixPlaceholder.ReplaceAllStringFunc(content, func(match string) string {
+		sub := matrixPlaceholder.FindStringSubmatch(match)
+		name := sub[1]
+		if name == "" {
+			name = sub[2]
+		}
+		value, ok := vars[name]
+		if !ok {
+			return match
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			renderErr = fmt.Errorf("variable %q: %w", name, err)
+			return match
+		}
+		if _, isString := value.(string); isString {
+			// Drop the quotes; the template supplies them.
+			return strings.TrimSuffix(strings.TrimPrefix(string(encoded), `"`), `"`)
+		}
+		return string(encoded)
+	})
+	if renderErr != nil {
+		return nil, renderErr
+	}
+
+	var payload interface{}
+	if err := jps.parseContent(rendered, &payload); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if jps.config.StripSecrets {
+		payload = jps.stripSessionSecrets(session, payload)
+	}
+	payloadBytes, _ := json.Marshal(payload)
+	if jps.config.EnforceComplexityLimits {
+		if err := jps.checkComplexityLimits(payload, string(payloadBytes)); err != nil {
+			return nil, err
+		}
+	}
+	if len(payloadBytes) > jps.config.MaxPayloadSize {
+		return nil, fmt.Errorf("payload too large: %d bytes (max: %d)", len(payloadBytes), jps.config.MaxPayloadSize)
+	}
+	return payload, nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 James Ross
+package jsonpayloadstudio
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+func newMatrixStudio(t *testing.T, content string) (*JSONPayloadStudio, *redis.Client, string) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	cfg := DefaultConfig()
+	cfg.TemplatesPath = ""
+	cfg.SchemasPath = ""
+	cfg.AutoSave = false
+	cfg.MaxPayloadSize = 80
+	studio, err := NewJSONPayloadStudio(cfg, rdb, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionID := studio.CreateSession()
+	studio.sessions[sessionID].EditorState.Content = content
+	return studio, rdb, sessionID
+}
+
+const matrixTemplate = `{"user": "{{user}}", "n": {{n}}, "api_token": "${token}"}`
+
+func TestEnqueueMatrixRendersOneJobPerSet(t *testing.T) {
+	studio, rdb, sessionID := newMatrixStudio(t, matrixTemplate)
+
+	res, err := studio.EnqueueMatrix(sessionID, []map[string]interface{}{
+		{"user": `a"b`, "n": 1, "token": "s1"},
+		{"user": "c", "n": 2.5, "token": "s2"},
+	}, &MatrixEnqueueOptions{EnqueueOptions: EnqueueOptions{Queue: "load"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Count != 2 || len(res.JobIDs) != 2 || res.JobIDs[0] == "" || res.JobIDs[0] == res.JobIDs[1] {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+
+	items, _ := rdb.LRange(context.Background(), "queue:load", 0, -1).Result()
+	if len(items) != 2 {
+		t.Fatalf("expected 2 queued jobs, got %d", len(items))
+	}
+	var job struct {
+		ID      string                 `json:"id"`
+		Payload map[string]interface{} `json:"payload"`
+	}
+	if err := json.Unmarshal([]byte(items[0]), &job); err != nil {
+		t.Fatal(err)
+	}
+	if job.ID != res.JobIDs[0] || job.Payload["user"] != `a"b` || job.Payload["n"] != 1.0 {
+		t.Fatalf("unexpected first job: %+v", job)
+	}
+	if job.Payload["api_token"] != "***REDACTED***" {
+		t.Fatalf("secrets should be stripped per item: %+v", job.Payload)
+	}
+}
+
+func TestEnqueueMatrixAllOrNothing(t *testing.T) {
+	studio, rdb, sessionID := newMatrixStudio(t, matrixTemplate)
+	sets := []map[string]interface{}{
+		{"user": "ok", "n": 1},
+		{"user": "missing n"},                      // bare {{n}} left in place: invalid JSON
+		{"user": strings.Repeat("x", 100), "n": 3}, // exceeds MaxPayloadSize
+	}
+
+	_, err := studio.EnqueueMatrix(sessionID, sets, &MatrixEnqueueOptions{
+		EnqueueOptions: EnqueueOptions{Queue: "load"},
+		AllOrNothing:   true,
+	})
+	if err == nil {
+		t.Fatal("expected all-or-nothing failure")
+	}
+	if n, _ := rdb.LLen(context.Background(), "queue:load").Result(); n != 0 {
+		t.Fatalf("nothing should be enqueued, got %d", n)
+	}
+
+	res, err := studio.EnqueueMatrix(sessionID, sets, &MatrixEnqueueOptions{EnqueueOptions: EnqueueOptions{Queue: "load"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Count != 1 || len(res.Skipped) != 2 || res.Skipped[0].Index != 1 || res.Skipped[1].Index != 2 || res.JobIDs[1] != "" {
+		t.Fatalf("unexpected partial result: %+v", res)
+	}
+	if !strings.Contains(res.Skipped[1].Error, "too large") {
+		t.Fatalf("unexpected size error: %q", res.Skipped[1].Error)
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2025 James Ross
+package jsonpayloadstudio
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// redactedValue replaces the value of every field secret stripping removes.
+const redactedValue = "***REDACTED***"
+
+// secretKeyParts are the substrings that mark a field name as a secret.
+var secretKeyParts = []string{"password", "secret", "token", "key", "auth"}
+
+// isSecretKey reports whether stripping redacts a field named key.
+func isSecretKey(key string) bool {
+	lower := strings.ToLower(key)
+	for _, part := range secretKeyParts {
+		if strings.Contains(lower, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactSecrets returns a copy of data with the value of every secret-named
+// field replaced by redactedValue. path is data's own path. A field for
+// which allow returns true is kept and walked like any other; report, when
+// set, is called with each redacted field's path and original value.
+func redactSecrets(data interface{}, path string, allow func(path, key string) bool, report func(path string, value interface{})) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			field := childPath(path, key)
+			if isSecretKey(key) && (allow == nil || !allow(field, key)) {
+				result[key] = redactedValue
+				if report != nil {
+					report(field, value)
+				}
+				continue
+			}
+			result[key] = redactSecrets(value, field, allow, report)
+		}
+		return result
+
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = redactSecrets(item, fmt.Sprintf("%s[%d]", path, i), allow, report)
+		}
+		return result
+
+	default:
+		return v
+	}
+}
+
+// redactionAllower matches fields against a session's allow list: an entry
+// is either a field's full path or a bare field name.
+func redactionAllower(entries []string) func(path, key string) bool {
+	if len(entries) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		set[e] = true
+	}
+	return func(path, key string) bool {
+		return set[path] || set[key]
+	}
+}
+
+// stripSessionSecrets is stripSecrets honoring session's allow list.
+func (jps *JSONPayloadStudio) stripSessionSecrets(session *SessionInfo, payload interface{}) interface{} {
+	return redactSecrets(payload, "", redactionAllower(session.RedactionAllow), nil)
+}
+
+// PreviewRedaction shows what secret stripping would do to the session's
+// payload without enqueueing it: the fields that would be redacted, in path
+// order with their current values, the secret-named fields the session's
+// allow list keeps, and the payload before and after. With StripSecrets off
+// nothing is redacted and Enabled is false.
+func (jps *JSONPayloadStudio) PreviewRedaction(sessionID string) (*RedactionPreview, error) {
+	jps.mu.RLock()
+	defer jps.mu.RUnlock()
+
+	session, exists := jps.sessions[sessionID]
+	if !exists {
+		return nil, NewSessionError("session not found", sessionID)
+	}
+
+	var payload interface{}
+	if err := jps.parseContent(session.EditorState.Content, &payload); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	preview := &RedactionPreview{
+		SessionID:  sessionID,
+		Enabled:    jps.config.StripSecrets,
+		Redactions: []RedactedField{},
+		Allowed:    []string{},
+		Before:     payload,
+		After:      payload,
+	}
+	if !jps.config.StripSecrets {
+		return preview, nil
+	}
+
+	allow := redactionAllower(session.RedactionAllow)
+	tracked := func(path, key string) bool {
+		if allow != nil && allow(path, key) {
+			preview.Allowed = append(preview.Allowed, path)
+			return true
+		}
+		return false
+	}
+	preview.After = redactSecrets(payload, "", tracked, func(path string, value interface{}) {
+		preview.Redactions = append(preview.Redactions, RedactedField{Path: path, Value: value})
+	})
+	sort.Slice(preview.Redactions, func(i, j int) bool { return preview.Redactions[i].Path < preview.Redactions[j].Path })
+	sort.Strings(preview.Allowed)
+	return preview, nil
+}
+
+// SetRedactionAllow replaces the session's redaction allow list. Entries
+// are paths as PreviewRedaction reports them (user.api_key,
+// items[0].token) or bare field names, which allow that field wherever it
+// appears; empty entries are dropped.
+func (jps *JSONPayloadStudio) SetRedactionAllow(sessionID string, fields []string) error {
+	jps.mu.Lock()
+	defer jps.mu.Unlock()
+
+	session, exists := jps.sessions[sessionID]
+	if !exists {
+		return NewSessionError("session not found", sessionID)
+	}
+	allow := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if f = strings.TrimSpace(f); f != "" {
+			allow = append(allow, f)
+		}
+	}
+	session.RedactionAllow = allow
+	session.LastActivity = time.Now()
+	return nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 James Ross
+package jsonpayloadstudio
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+const redactionPayload = `{"user": {"name": "ada", "api_key": "k1"}, "items": [{"token": "t0"}, {"token": "t1"}], "password": "p"}`
+
+func TestPreviewRedactionHonorsAllowList(t *testing.T) {
+	studio, rdb, sessionID := newMatrixStudio(t, redactionPayload)
+	studio.config.MaxPayloadSize = 1024
+
+	preview, err := studio.PreviewRedaction(sessionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, r := range preview.Redactions {
+		paths = append(paths, r.Path)
+	}
+	if !preview.Enabled || len(paths) != 4 || paths[0] != "items[0].token" || paths[3] != "user.api_key" {
+		t.Fatalf("unexpected redactions: %v", paths)
+	}
+	if preview.Redactions[3].Value != "k1" {
+		t.Fatalf("expected the original value, got %v", preview.Redactions[3].Value)
+	}
+	if preview.After.(map[string]interface{})["password"] != redactedValue || preview.Before.(map[string]interface{})["password"] != "p" {
+		t.Fatalf("unexpected before/after: %+v / %+v", preview.Before, preview.After)
+	}
+
+	if err := studio.SetRedactionAllow(sessionID, []string{"user.api_key", " token ", ""}); err != nil {
+		t.Fatal(err)
+	}
+	preview, err = studio.PreviewRedaction(sessionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(preview.Redactions) != 1 || preview.Redactions[0].Path != "password" {
+		t.Fatalf("expected only password redacted, got %+v", preview.Redactions)
+	}
+	if len(preview.Allowed) != 3 || preview.Allowed[2] != "user.api_key" {
+		t.Fatalf("unexpected allowed: %v", preview.Allowed)
+	}
+
+	// enqueueing strips the same fields the preview reported
+	if _, err := studio.EnqueuePayload(sessionID, &EnqueueOptions{Queue: "load", Count: 1}); err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := rdb.LIndex(context.Background(), "queue:load", 0).Result()
+	var job struct {
+		Payload map[string]interface{} `json:"payload"`
+	}
+	if err := json.Unmarshal([]byte(raw), &job); err != nil {
+		t.Fatal(err)
+	}
+	if job.Payload["password"] != redactedValue || job.Payload["user"].(map[string]interface{})["api_key"] != "k1" {
+		t.Fatalf("unexpected enqueued payload: %+v", job.Payload)
+	}
+
+	studio.config.StripSecrets = false
+	if preview, _ = studio.PreviewRedaction(sessionID); preview.Enabled || len(preview.Redactions) != 0 {
+		t.Fatalf("expected nothing redacted with stripping off, got %+v", preview)
+	}
+	if _, err := studio.PreviewRedaction("missing"); err == nil {
+		t.Fatal("expected an error for an unknown session")
+	}
+}
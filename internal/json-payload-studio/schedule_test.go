@@ -0,0 +1,81 @@
+// Copyright 2025 James Ross
+package jsonpayloadstudio
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/flyingrobots/go-redis-work-queue/internal/scheduler"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+func TestScheduledJobsArePromotedOntoWorkerQueues(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	t.Setenv("WORKQUEUE_NAMESPACE", "billing")
+	qcfg, err := config.Load("nonexistent.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := DefaultConfig()
+	cfg.TemplatesPath = ""
+	cfg.SchemasPath = ""
+	cfg.AutoSave = false
+	studio, err := NewJSONPayloadStudio(cfg, rdb, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	studio.SetQueueConfig(qcfg)
+	sessionID := studio.CreateSession()
+	studio.sessions[sessionID].EditorState.Content = `{"invoice": 42}`
+
+	past := time.Now().Add(-time.Minute)
+	scheduled, err := studio.EnqueuePayload(sessionID, &EnqueueOptions{Queue: "high", Count: 1, RunAt: &past})
+	if err != nil {
+		t.Fatal(err)
+	}
+	delayed, err := studio.EnqueuePayload(sessionID, &EnqueueOptions{Queue: "jobqueue:low_priority", Count: 1, Delay: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range []string{"billing:scheduled:jobqueue:high_priority", "billing:delayed:jobqueue:low_priority"} {
+		if !mr.Exists(k) {
+			t.Fatalf("expected %s, have %v", k, mr.Keys())
+		}
+	}
+
+	n, err := scheduler.New(qcfg, rdb, zap.NewNop()).PromoteDue(ctx, time.Now().Add(time.Second))
+	if err != nil || n != 2 {
+		t.Fatalf("promoted %d jobs, err %v; want 2", n, err)
+	}
+
+	for priority, id := range map[string]string{"high": scheduled.JobIDs[0], "low": delayed.JobIDs[0]} {
+		items, err := rdb.LRange(ctx, qcfg.Worker.Queues[priority], 0, -1).Result()
+		if err != nil || len(items) != 1 {
+			t.Fatalf("%s queue: %v, %v", priority, items, err)
+		}
+		job, err := queue.UnmarshalJob(items[0])
+		if err != nil {
+			t.Fatalf("%s job does not decode as a queue.Job: %v", priority, err)
+		}
+		if job.ID != id || job.Priority != priority || job.CreationTime == "" {
+			t.Fatalf("%s job: %+v", priority, job)
+		}
+		var body struct {
+			Payload map[string]interface{} `json:"payload"`
+		}
+		if err := json.Unmarshal([]byte(items[0]), &body); err != nil || body.Payload["invoice"] != float64(42) {
+			t.Fatalf("%s job lost its payload: %s", priority, items[0])
+		}
+	}
+}
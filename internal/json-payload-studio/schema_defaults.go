@@ -0,0 +1,180 @@
+// Copyright 2025 James Ross
+package jsonpayloadstudio
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GenerateFromSchema scaffolds a formatted JSON payload from a loaded schema.
+// Each property is populated with its schema default or example when present,
+// otherwise with a zero value for its type. Only required properties are
+// emitted unless StudioConfig.ScaffoldOptionalFields is set.
+func (jps *JSONPayloadStudio) GenerateFromSchema(schemaID string) (string, error) {
+	schema, err := jps.GetSchema(schemaID)
+	if err != nil {
+		return "", NewNotFoundError("schema", schemaID)
+	}
+
+	gen := &schemaScaffolder{
+		definitions: schema.Definitions,
+		includeAll:  jps.config.ScaffoldOptionalFields,
+		maxDepth:    jps.config.MaxNestingDepth,
+	}
+
+	root := map[string]interface{}{
+		"properties": schema.Properties,
+		"required":   stringsToInterfaces(schema.Required),
+	}
+	if schema.Type != nil {
+		root["type"] = schema.Type
+	}
+
+	value := gen.value(root, 0)
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to format scaffold: %w", err)
+	}
+	return string(data), nil
+}
+
+// schemaScaffolder walks schema nodes and produces default values.
+type schemaScaffolder struct {
+	definitions map[string]interface{}
+	includeAll  bool
+	maxDepth    int
+}
+
+func (s *schemaScaffolder) value(node map[string]interface{}, depth int) interface{} {
+	if ref, ok := node["$ref"].(string); ok {
+		resolved, found := s.resolveRef(ref)
+		if !found {
+			return map[string]interface{}{}
+		}
+		node = resolved
+	}
+
+	if v, ok := node["default"]; ok {
+		return cloneValue(v)
+	}
+	if v, ok := node["example"]; ok {
+		return cloneValue(v)
+	}
+	if examples, ok := node["examples"].([]interface{}); ok && len(examples) > 0 {
+		return cloneValue(examples[0])
+	}
+	if v, ok := node["const"]; ok {
+		return cloneValue(v)
+	}
+	if enum, ok := node["enum"].([]interface{}); ok && len(enum) > 0 {
+		return cloneValue(enum[0])
+	}
+
+	switch schemaType(node) {
+	case "object":
+		if s.maxDepth > 0 && depth >= s.maxDepth {
+			// Stop expanding; this is what keeps $ref cycles finite.
+			return map[string]interface{}{}
+		}
+		return s.object(node, depth)
+	case "array":
+		return []interface{}{}
+	case "string":
+		return ""
+	case "integer", "number":
+		return 0
+	case "boolean":
+		return false
+	default:
+		return nil
+	}
+}
+
+func (s *schemaScaffolder) object(node map[string]interface{}, depth int) map[string]interface{} {
+	out := map[string]interface{}{}
+	props, _ := node["properties"].(map[string]interface{})
+	if len(props) == 0 {
+		return out
+	}
+
+	required := map[string]bool{}
+	if req, ok := node["required"].([]interface{}); ok {
+		for _, r := range req {
+			if name, ok := r.(string); ok {
+				required[name] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !s.includeAll && !required[name] {
+			continue
+		}
+		child, ok := props[name].(map[string]interface{})
+		if !ok {
+			out[name] = nil
+			continue
+		}
+		out[name] = s.value(child, depth+1)
+	}
+	return out
+}
+
+// resolveRef resolves local references of the form #/definitions/Name or #/$defs/Name.
+func (s *schemaScaffolder) resolveRef(ref string) (map[string]interface{}, bool) {
+	var name string
+	switch {
+	case strings.HasPrefix(ref, "#/definitions/"):
+		name = strings.TrimPrefix(ref, "#/definitions/")
+	case strings.HasPrefix(ref, "#/$defs/"):
+		name = strings.TrimPrefix(ref, "#/$defs/")
+	default:
+		return nil, false
+	}
+	def, ok := s.definitions[name].(map[string]interface{})
+	return def, ok
+}
+
+// schemaType returns the node's primary type, inferring object when only
+// properties are given and skipping "null" in type unions.
+func schemaType(node map[string]interface{}) string {
+	switch t := node["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, candidate := range t {
+			if name, ok := candidate.(string); ok && name != "null" {
+				return name
+			}
+		}
+		if len(t) > 0 {
+			return "null"
+		}
+	case []string:
+		for _, name := range t {
+			if name != "null" {
+				return name
+			}
+		}
+	}
+	if _, ok := node["properties"]; ok {
+		return "object"
+	}
+	return ""
+}
+
+func stringsToInterfaces(values []string) []interface{} {
+	out := make([]interface{}, len(values))
+	for i, v := range values {
+		out[i] = v
+	}
+	return out
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 James Ross
+package jsonpayloadstudio
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+const scaffoldSchema = `{
+  "id": "order",
+  "type": "object",
+  "required": ["id", "count", "express", "customer", "tags", "status"],
+  "properties": {
+    "id": {"type": "string"},
+    "count": {"type": "integer", "default": 1},
+    "express": {"type": "boolean"},
+    "status": {"enum": ["pending", "shipped"]},
+    "tags": {"type": "array", "items": {"type": "string"}},
+    "note": {"type": ["null", "string"], "example": "leave at door"},
+    "customer": {"$ref": "#/definitions/customer"}
+  },
+  "definitions": {
+    "customer": {
+      "type": "object",
+      "required": ["email", "referrer"],
+      "properties": {
+        "email": {"type": "string", "examples": ["a@example.com"]},
+        "age": {"type": "number"},
+        "referrer": {"$ref": "#/definitions/customer"}
+      }
+    }
+  }
+}`
+
+func newScaffoldStudio(t *testing.T, includeAll bool, maxDepth int) *JSONPayloadStudio {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "order.json"), []byte(scaffoldSchema), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := DefaultConfig()
+	cfg.TemplatesPath = ""
+	cfg.SchemasPath = dir
+	cfg.AutoSave = false
+	cfg.MaxNestingDepth = maxDepth
+	cfg.ScaffoldOptionalFields = includeAll
+	studio, err := NewJSONPayloadStudio(cfg, nil, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return studio
+}
+
+func TestGenerateFromSchemaRequiredOnly(t *testing.T) {
+	studio := newScaffoldStudio(t, false, 3)
+
+	out, err := studio.GenerateFromSchema("order")
+	if err != nil {
+		t.Fatalf("GenerateFromSchema: %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("scaffold is not valid JSON: %v\n%s", err, out)
+	}
+	want := map[string]interface{}{
+		"id":      "",
+		"count":   float64(1),
+		"express": false,
+		"status":  "pending",
+		"tags":    []interface{}{},
+		"customer": map[string]interface{}{
+			"email": "a@example.com",
+			"referrer": map[string]interface{}{
+				"email":    "a@example.com",
+				"referrer": map[string]interface{}{},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected scaffold:\n%s", out)
+	}
+}
+
+func TestGenerateFromSchemaAllFields(t *testing.T) {
+	studio := newScaffoldStudio(t, true, 2)
+
+	out, err := studio.GenerateFromSchema("order")
+	if err != nil {
+		t.Fatalf("GenerateFromSchema: %v", err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["note"] != "leave at door" {
+		t.Errorf("expected optional note example, got %v", got["note"])
+	}
+	customer := got["customer"].(map[string]interface{})
+	if customer["age"] != float64(0) {
+		t.Errorf("expected optional age default 0, got %v", customer["age"])
+	}
+	if ref := customer["referrer"]; !reflect.DeepEqual(ref, map[string]interface{}{}) {
+		t.Errorf("expected recursion to stop at max depth, got %v", ref)
+	}
+}
+
+func TestGenerateFromSchemaUnknownSchema(t *testing.T) {
+	studio := newScaffoldStudio(t, false, 3)
+	if _, err := studio.GenerateFromSchema("missing"); err == nil {
+		t.Fatal("expected error for unknown schema")
+	}
+}
@@ -0,0 +1,264 @@
+// Copyright 2025 James Ross
+package jsonpayloadstudio
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+const (
+	defaultRemoteSchemaTimeout  = 5 * time.Second
+	defaultRemoteSchemaCacheTTL = 10 * time.Minute
+	maxRemoteSchemaBytes        = 1 << 20 // 1MB
+)
+
+// schemaRefError is a $ref that could not be resolved.
+type schemaRefError struct {
+	Ref string
+	Err error
+}
+
+func (e *schemaRefError) Error() string {
+	return fmt.Sprintf("cannot resolve $ref %q: %v", e.Ref, e.Err)
+}
+
+func (e *schemaRefError) Unwrap() error { return e.Err }
+
+func asSchemaRefError(err error) (*schemaRefError, bool) {
+	var refErr *schemaRefError
+	ok := errors.As(err, &refErr)
+	return refErr, ok
+}
+
+// schemaRefResolver resolves $ref targets outside the schema being
+// validated. A reference is looked up first among the schemas registered in
+// the Studio, by ID, then fetched over HTTP(S) if its host is listed in
+// remote_schema_hosts. Nothing else is ever loaded: gojsonschema's default
+// loader would follow file:// and arbitrary http:// references, so every
+// lookup goes through this resolver instead.
+type schemaRefResolver struct {
+	registered map[string][]byte
+	hosts      map[string]struct{}
+	client     *http.Client
+	ttl        time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedRemoteSchema
+}
+
+type cachedRemoteSchema struct {
+	body    []byte
+	expires time.Time
+}
+
+// newSchemaRefResolver snapshots schemas, which must not change afterwards,
+// so lookups never need the Studio lock.
+func newSchemaRefResolver(config *StudioConfig, schemas map[string]*JSONSchema) *schemaRefResolver {
+	r := &schemaRefResolver{
+		registered: make(map[string][]byte, len(schemas)),
+		hosts:      make(map[string]struct{}, len(config.RemoteSchemaHosts)),
+		ttl:        config.RemoteSchemaCacheTTL,
+		cache:      make(map[string]cachedRemoteSchema),
+	}
+	for id, schema := range schemas {
+		if data, err := json.Marshal(schema); err == nil {
+			r.registered[id] = data
+		}
+	}
+	for _, host := range config.RemoteSchemaHosts {
+		r.hosts[strings.ToLower(host)] = struct{}{}
+	}
+	if r.ttl <= 0 {
+		r.ttl = defaultRemoteSchemaCacheTTL
+	}
+	timeout := config.RemoteSchemaTimeout
+	if timeout <= 0 {
+		timeout = defaultRemoteSchemaTimeout
+	}
+	r.client = &http.Client{
+		Timeout: timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if !r.hostAllowed(req.URL) {
+				return fmt.Errorf("redirect to host %q is not allowed", req.URL.Hostname())
+			}
+			if len(via) >= 5 {
+				return errors.New("too many redirects")
+			}
+			return nil
+		},
+	}
+	return r
+}
+
+// compile builds a schema whose references can only resolve through r.
+// Studio schemas referenced by a bare ID, directly or through another
+// Studio schema, are added to the loader up front because gojsonschema only
+// asks a loader factory for absolute URLs.
+func (r *schemaRefResolver) compile(schemaJSON []byte) (*gojsonschema.Schema, error) {
+	var root interface{}
+	if err := json.Unmarshal(schemaJSON, &root); err != nil {
+		return nil, err
+	}
+	rootID := schemaID(root)
+
+	sl := gojsonschema.NewSchemaLoader()
+	added := map[string]bool{rootID: true}
+	pending := schemaRefTargets(root)
+	for len(pending) > 0 {
+		id := pending[0]
+		pending = pending[1:]
+		data, ok := r.registered[id]
+		if !ok && !strings.Contains(id, "://") && !strings.Contains(rootID, "://") {
+			// With no absolute base to resolve against, gojsonschema
+			// would only report that the reference is not canonical.
+			return nil, &schemaRefError{Ref: id, Err: errors.New("not a registered schema ID")}
+		}
+		if !ok || added[id] {
+			continue
+		}
+		added[id] = true
+		if err := sl.AddSchema(id, gojsonschema.NewBytesLoader(data)); err != nil {
+			return nil, &schemaRefError{Ref: id, Err: err}
+		}
+		var doc interface{}
+		if err := json.Unmarshal(data, &doc); err == nil {
+			pending = append(pending, schemaRefTargets(doc)...)
+		}
+	}
+	return sl.Compile(sandboxedLoader{JSONLoader: gojsonschema.NewBytesLoader(schemaJSON), resolver: r})
+}
+
+// New implements gojsonschema.JSONLoaderFactory.
+func (r *schemaRefResolver) New(source string) gojsonschema.JSONLoader {
+	return &refLoader{
+		sandboxedLoader: sandboxedLoader{JSONLoader: gojsonschema.NewReferenceLoader(source), resolver: r},
+		source:          source,
+	}
+}
+
+// load returns the schema document at ref, without its fragment.
+func (r *schemaRefResolver) load(ref string) ([]byte, error) {
+	if data, ok := r.registered[ref]; ok {
+		return data, nil
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errors.New("not a registered schema ID or an http(s) URL")
+	}
+	if !r.hostAllowed(u) {
+		return nil, fmt.Errorf("host %q is not in remote_schema_hosts", u.Hostname())
+	}
+
+	r.mu.Lock()
+	cached, ok := r.cache[ref]
+	r.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.body, nil
+	}
+
+	resp, err := r.client.Get(ref)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch returned %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteSchemaBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxRemoteSchemaBytes {
+		return nil, fmt.Errorf("schema exceeds %d bytes", maxRemoteSchemaBytes)
+	}
+
+	r.mu.Lock()
+	r.cache[ref] = cachedRemoteSchema{body: body, expires: time.Now().Add(r.ttl)}
+	r.mu.Unlock()
+	return body, nil
+}
+
+func (r *schemaRefResolver) hostAllowed(u *url.URL) bool {
+	_, ok := r.hosts[strings.ToLower(u.Hostname())]
+	return ok
+}
+
+// sandboxedLoader makes every reference found while compiling resolve
+// through the resolver.
+type sandboxedLoader struct {
+	gojsonschema.JSONLoader
+	resolver *schemaRefResolver
+}
+
+func (l sandboxedLoader) LoaderFactory() gojsonschema.JSONLoaderFactory {
+	return l.resolver
+}
+
+// refLoader loads one referenced document through the resolver.
+type refLoader struct {
+	sandboxedLoader
+	source string
+}
+
+func (l *refLoader) LoadJSON() (interface{}, error) {
+	ref := l.source
+	if i := strings.Index(ref, "#"); i >= 0 {
+		ref = ref[:i]
+	}
+	data, err := l.resolver.load(ref)
+	if err != nil {
+		return nil, &schemaRefError{Ref: l.source, Err: err}
+	}
+	return gojsonschema.NewBytesLoader(data).LoadJSON()
+}
+
+// schemaRefTargets lists the documents referenced by $ref anywhere in doc,
+// ignoring references into doc itself.
+func schemaRefTargets(doc interface{}) []string {
+	var out []string
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch node := v.(type) {
+		case map[string]interface{}:
+			if ref, ok := node["$ref"].(string); ok {
+				if i := strings.Index(ref, "#"); i >= 0 {
+					ref = ref[:i]
+				}
+				if ref != "" {
+					out = append(out, ref)
+				}
+			}
+			for _, child := range node {
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range node {
+				walk(child)
+			}
+		}
+	}
+	walk(doc)
+	return out
+}
+
+func schemaID(doc interface{}) string {
+	m, _ := doc.(map[string]interface{})
+	for _, key := range []string{"$id", "id"} {
+		if id, ok := m[key].(string); ok && id != "" {
+			return id
+		}
+	}
+	return ""
+}
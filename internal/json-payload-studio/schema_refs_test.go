@@ -0,0 +1,119 @@
+// Copyright 2025 James Ross
+package jsonpayloadstudio
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+const customerSchema = `{
+  "id": "customer",
+  "type": "object",
+  "required": ["email"],
+  "properties": {"email": {"type": "string"}}
+}`
+
+func newRefStudio(t *testing.T, hosts []string, schemas map[string]string) *JSONPayloadStudio {
+	t.Helper()
+	dir := t.TempDir()
+	for name, body := range schemas {
+		if err := os.WriteFile(filepath.Join(dir, name+".json"), []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := DefaultConfig()
+	cfg.TemplatesPath = ""
+	cfg.SchemasPath = dir
+	cfg.AutoSave = false
+	cfg.RemoteSchemaHosts = hosts
+	studio, err := NewJSONPayloadStudio(cfg, nil, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return studio
+}
+
+func schemaErrors(result *LintResult) []ValidationError {
+	var out []ValidationError
+	for _, e := range result.Errors {
+		if e.Type == "schema" {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func TestValidateResolvesStudioSchemaRefs(t *testing.T) {
+	studio := newRefStudio(t, nil, map[string]string{"customer": customerSchema})
+	schema := &JSONSchema{
+		Type:       "object",
+		Properties: map[string]interface{}{"customer": map[string]interface{}{"$ref": "customer"}},
+	}
+
+	if result := studio.ValidateJSON(`{"customer": {"email": "a@example.com"}}`, schema); !result.Valid {
+		t.Fatalf("expected valid payload, got %+v", result.Errors)
+	}
+	result := studio.ValidateJSON(`{"customer": {}}`, schema)
+	errs := schemaErrors(result)
+	if result.Valid || len(errs) != 1 || !strings.Contains(errs[0].Message, "email") {
+		t.Fatalf("expected a missing email error from the referenced schema, got %+v", result.Errors)
+	}
+}
+
+func TestValidateFetchesAllowedRemoteRefsOnce(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		_, _ = w.Write([]byte(`{"definitions": {"sku": {"type": "string", "pattern": "^SKU-"}}}`))
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+	studio := newRefStudio(t, []string{u.Hostname()}, nil)
+	schema := &JSONSchema{
+		Type:       "object",
+		Properties: map[string]interface{}{"sku": map[string]interface{}{"$ref": srv.URL + "/common.json#/definitions/sku"}},
+	}
+
+	if result := studio.ValidateJSON(`{"sku": "SKU-1"}`, schema); !result.Valid {
+		t.Fatalf("expected valid payload, got %+v", result.Errors)
+	}
+	if result := studio.ValidateJSON(`{"sku": "nope"}`, schema); result.Valid {
+		t.Fatal("expected the remote pattern to reject the payload")
+	}
+	if n := hits.Load(); n != 1 {
+		t.Fatalf("expected the remote schema to be fetched once, got %d", n)
+	}
+}
+
+func TestValidateRejectsRefsOutsideAllowlist(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		_, _ = w.Write([]byte(`{"type": "string"}`))
+	}))
+	defer srv.Close()
+	studio := newRefStudio(t, nil, nil)
+
+	for _, ref := range []string{srv.URL + "/s.json", "file:///etc/passwd", "missing"} {
+		schema := &JSONSchema{
+			Type:       "object",
+			Properties: map[string]interface{}{"x": map[string]interface{}{"$ref": ref}},
+		}
+		result := studio.ValidateJSON(`{"x": "y"}`, schema)
+		errs := schemaErrors(result)
+		if result.Valid || len(errs) != 1 {
+			t.Fatalf("%s: expected one schema error, got %+v", ref, result.Errors)
+		}
+	}
+	if n := hits.Load(); n != 0 {
+		t.Fatalf("expected no requests to a host outside the allowlist, got %d", n)
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright 2025 James Ross
+package jsonpayloadstudio
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// SaveSnippet validates a snippet, writes it to SnippetsPath as <id>.json
+// and makes it available for expansion. A snippet without an ID gets a new
+// one; saving an existing ID, including a built-in one, replaces it.
+func (jps *JSONPayloadStudio) SaveSnippet(snippet *Snippet) error {
+	if snippet == nil {
+		return fmt.Errorf("snippet is nil")
+	}
+
+	clone := cloneSnippet(snippet)
+	clone.Trigger = strings.TrimSpace(clone.Trigger)
+	if clone.ID == "" {
+		clone.ID = uuid.New().String()
+	}
+
+	jps.mu.Lock()
+	defer jps.mu.Unlock()
+
+	if err := jps.validateSnippet(clone); err != nil {
+		return err
+	}
+	if err := jps.saveSnippetToDisk(clone); err != nil {
+		return fmt.Errorf("save snippet %s: %w", clone.ID, err)
+	}
+
+	jps.snippets[clone.ID] = clone
+	snippet.ID = clone.ID
+	return nil
+}
+
+// DeleteSnippet removes a snippet by ID, including its file in
+// SnippetsPath. Deleting a saved override of a built-in snippet restores the
+// built-in; the built-ins themselves cannot be deleted.
+func (jps *JSONPayloadStudio) DeleteSnippet(id string) error {
+	jps.mu.Lock()
+	defer jps.mu.Unlock()
+
+	snippet, exists := jps.snippets[id]
+	if !exists {
+		return fmt.Errorf("snippet not found: %s", id)
+	}
+
+	builtin, isBuiltin := defaultSnippets()[id]
+	if isBuiltin && reflect.DeepEqual(snippet, builtin) {
+		return NewSnippetError("built-in snippets cannot be deleted", snippet.Trigger)
+	}
+
+	if jps.config.SnippetsPath != "" && validSnippetID(id) {
+		err := os.Remove(filepath.Join(jps.config.SnippetsPath, id+".json"))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("delete snippet %s: %w", id, err)
+		}
+	}
+
+	if isBuiltin {
+		jps.snippets[id] = builtin
+		return nil
+	}
+	delete(jps.snippets, id)
+	return nil
+}
+
+// SearchSnippets returns the snippets whose trigger, name, description or
+// category fuzzily match query, best match first. An empty query returns
+// every snippet, as ListSnippets does.
+func (jps *JSONPayloadStudio) SearchSnippets(query string) []Snippet {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return jps.ListSnippets()
+	}
+
+	jps.mu.RLock()
+	defer jps.mu.RUnlock()
+
+	type match struct {
+		snippet Snippet
+		score   int
+	}
+	matches := make([]match, 0)
+	for _, snippet := range jps.snippets {
+		// the trigger is what users type, so it outranks the other fields
+		score := fuzzyScore(snippet.Trigger, query) * 2
+		for _, field := range []string{snippet.Name, snippet.Description, snippet.Category} {
+			score = max(score, fuzzyScore(field, query))
+		}
+		if score > 0 {
+			matches = append(matches, match{snippet: *cloneSnippet(snippet), score: score})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return strings.ToLower(matches[i].snippet.Trigger) < strings.ToLower(matches[j].snippet.Trigger)
+	})
+
+	result := make([]Snippet, len(matches))
+	for i, m := range matches {
+		result[i] = m.snippet
+	}
+	return result
+}
+
+// validateSnippet checks that a snippet can be saved: a usable ID, a
+// trigger no other snippet uses, and something to expand to. Callers hold
+// jps.mu.
+func (jps *JSONPayloadStudio) validateSnippet(snippet *Snippet) error {
+	if !validSnippetID(snippet.ID) {
+		return NewSnippetError(fmt.Sprintf("invalid snippet id %q", snippet.ID), snippet.Trigger)
+	}
+	if snippet.Trigger == "" {
+		return NewSnippetError("snippet trigger is required", snippet.Trigger)
+	}
+	if strings.TrimSpace(snippet.Expansion) == "" && isEmptySnippetContent(snippet.Content) {
+		return NewSnippetError("snippet needs an expansion or content", snippet.Trigger)
+	}
+	for id, existing := range jps.snippets {
+		if id != snippet.ID && existing.Trigger == snippet.Trigger {
+			return NewSnippetError(fmt.Sprintf("trigger %q is already used by snippet %s", snippet.Trigger, id), snippet.Trigger)
+		}
+	}
+	return nil
+}
+
+// validSnippetID reports whether id can be used as a file name inside
+// SnippetsPath.
+func validSnippetID(id string) bool {
+	return id != "" && id != "." && id != ".." && !strings.ContainsAny(id, `/\`)
+}
+
+func isEmptySnippetContent(content interface{}) bool {
+	switch c := content.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(c) == ""
+	case map[string]interface{}:
+		return len(c) == 0
+	case []interface{}:
+		return len(c) == 0
+	}
+	return false
+}
+
+func (jps *JSONPayloadStudio) saveSnippetToDisk(snippet *Snippet) error {
+	if jps.config.SnippetsPath == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(jps.config.SnippetsPath, 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(snippet, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write to a temp file first so a crash never leaves a truncated snippet
+	filename := filepath.Join(jps.config.SnippetsPath, snippet.ID+".json")
+	tmp := filename + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
+
+// loadSnippets reads user snippets saved in SnippetsPath. They replace
+// built-ins with the same ID; invalid files and files whose trigger is
+// already taken are skipped with a warning.
+func (jps *JSONPayloadStudio) loadSnippets() error {
+	if jps.config.SnippetsPath == "" {
+		return nil
+	}
+
+	entries, err := os.ReadDir(jps.config.SnippetsPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(jps.config.SnippetsPath, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		var snippet Snippet
+		if err := json.Unmarshal(data, &snippet); err != nil {
+			jps.logger.Warn("Failed to parse snippet", zap.String("path", path), zap.Error(err))
+			continue
+		}
+		// the file name is the ID, so DeleteSnippet can find it again
+		snippet.ID = strings.TrimSuffix(entry.Name(), ".json")
+		if err := jps.validateSnippet(&snippet); err != nil {
+			jps.logger.Warn("Skipping invalid snippet", zap.String("path", path), zap.Error(err))
+			continue
+		}
+		jps.snippets[snippet.ID] = &snippet
+	}
+	return nil
+}
+
+func cloneSnippet(s *Snippet) *Snippet {
+	clone := *s
+	clone.Content = cloneValue(s.Content)
+	clone.Variables = append([]string(nil), s.Variables...)
+	return &clone
+}
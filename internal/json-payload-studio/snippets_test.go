@@ -0,0 +1,137 @@
+// Copyright 2025 James Ross
+package jsonpayloadstudio
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func newSnippetStudio(t *testing.T, dir string) *JSONPayloadStudio {
+	t.Helper()
+	cfg := DefaultConfig()
+	cfg.TemplatesPath = ""
+	cfg.SchemasPath = ""
+	cfg.SnippetsPath = dir
+	cfg.AutoSave = false
+	studio, err := NewJSONPayloadStudio(cfg, nil, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return studio
+}
+
+func TestSaveSnippetPersistsAcrossRestarts(t *testing.T) {
+	dir := t.TempDir()
+	studio := newSnippetStudio(t, dir)
+
+	snippet := &Snippet{
+		Name:      "Order",
+		Trigger:   "order",
+		Category:  "commerce",
+		Content:   map[string]interface{}{"order_id": "${UUID}", "items": []interface{}{}},
+		Variables: []string{"UUID"},
+	}
+	if err := studio.SaveSnippet(snippet); err != nil {
+		t.Fatalf("SaveSnippet: %v", err)
+	}
+	if snippet.ID == "" {
+		t.Fatal("SaveSnippet should assign an ID")
+	}
+	if _, err := os.Stat(filepath.Join(dir, snippet.ID+".json")); err != nil {
+		t.Fatalf("snippet file not written: %v", err)
+	}
+
+	reloaded := newSnippetStudio(t, dir)
+	if len(reloaded.ListSnippets()) != len(defaultSnippets())+1 {
+		t.Fatalf("expected built-ins plus the saved snippet, got %d", len(reloaded.ListSnippets()))
+	}
+	expanded, err := reloaded.ExpandSnippet("order", nil)
+	if err != nil {
+		t.Fatalf("ExpandSnippet after reload: %v", err)
+	}
+	if expanded == "" {
+		t.Fatal("expected expanded content")
+	}
+
+	if err := reloaded.DeleteSnippet(snippet.ID); err != nil {
+		t.Fatalf("DeleteSnippet: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, snippet.ID+".json")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("snippet file should be removed, stat err = %v", err)
+	}
+	if len(newSnippetStudio(t, dir).ListSnippets()) != len(defaultSnippets()) {
+		t.Fatal("deleted snippet came back after restart")
+	}
+}
+
+func TestSaveSnippetValidation(t *testing.T) {
+	studio := newSnippetStudio(t, t.TempDir())
+
+	cases := map[string]*Snippet{
+		"missing trigger":   {Expansion: `"x"`},
+		"empty expansion":   {Trigger: "empty", Expansion: "  "},
+		"empty content":     {Trigger: "empty", Content: map[string]interface{}{}},
+		"duplicate trigger": {Trigger: "uuid", Expansion: `"${UUID}"`},
+		"path in id":        {ID: "../escape", Trigger: "escape", Expansion: `"x"`},
+	}
+	for name, snippet := range cases {
+		err := studio.SaveSnippet(snippet)
+		var studioErr *StudioError
+		if !errors.As(err, &studioErr) || studioErr.Type != ErrorTypeSnippet {
+			t.Errorf("%s: expected a snippet error, got %v", name, err)
+		}
+	}
+}
+
+func TestSnippetOverridesBuiltin(t *testing.T) {
+	dir := t.TempDir()
+	studio := newSnippetStudio(t, dir)
+
+	if err := studio.DeleteSnippet("uuid"); err == nil {
+		t.Fatal("deleting a built-in snippet should fail")
+	}
+	if err := studio.SaveSnippet(&Snippet{ID: "date", Trigger: "date", Expansion: `"2025-01-01"`}); err != nil {
+		t.Fatalf("override built-in: %v", err)
+	}
+	reloaded := newSnippetStudio(t, dir)
+	if got, _ := reloaded.ExpandSnippet("date", nil); got != `"2025-01-01"` {
+		t.Fatalf("override not loaded, got %s", got)
+	}
+	if err := reloaded.DeleteSnippet("date"); err != nil {
+		t.Fatalf("delete override: %v", err)
+	}
+	if got, _ := reloaded.ExpandSnippet("date", nil); got == `"2025-01-01"` {
+		t.Fatal("deleting the override should restore the built-in")
+	}
+}
+
+func TestSearchSnippets(t *testing.T) {
+	studio := newSnippetStudio(t, t.TempDir())
+	if err := studio.SaveSnippet(&Snippet{Trigger: "timestamp-ms", Name: "Millis", Expansion: `"${TIMESTAMP}000"`}); err != nil {
+		t.Fatal(err)
+	}
+
+	results := studio.SearchSnippets("tms")
+	if len(results) == 0 || results[0].Trigger != "timestamp-ms" {
+		t.Fatalf("expected timestamp-ms first, got %+v", results)
+	}
+
+	results = studio.SearchSnippets("time")
+	if len(results) < 2 {
+		t.Fatalf("expected trigger and category matches, got %d", len(results))
+	}
+	if results[0].Trigger != "timestamp-ms" {
+		t.Fatalf("trigger match should rank first, got %s", results[0].Trigger)
+	}
+
+	if len(studio.SearchSnippets("zzzz")) != 0 {
+		t.Fatal("expected no matches")
+	}
+	if len(studio.SearchSnippets("")) != len(studio.ListSnippets()) {
+		t.Fatal("empty query should list every snippet")
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright 2025 James Ross
+package jsonpayloadstudio
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// templateVersionsDir holds each template's history under TemplatesPath,
+// as versions/<id>/<n>.json.
+const templateVersionsDir = "versions"
+
+// GetTemplateVersion returns version (1-based) of a template's history.
+func (jps *JSONPayloadStudio) GetTemplateVersion(id string, version int) (*Template, error) {
+	jps.mu.RLock()
+	defer jps.mu.RUnlock()
+
+	tmpl, err := jps.templateVersionLocked(id, version)
+	if err != nil {
+		return nil, err
+	}
+	return cloneTemplate(tmpl), nil
+}
+
+// RollbackTemplate makes version the current template again. The rollback
+// is saved as a new version, so the versions it replaces stay available.
+func (jps *JSONPayloadStudio) RollbackTemplate(id string, version int) (*Template, error) {
+	jps.mu.Lock()
+	defer jps.mu.Unlock()
+
+	old, err := jps.templateVersionLocked(id, version)
+	if err != nil {
+		return nil, err
+	}
+	restored := cloneTemplate(old)
+	if current, ok := jps.templates[id]; ok {
+		restored.CreatedAt = current.CreatedAt
+	}
+	restored.UpdatedAt = time.Now()
+	if err := jps.saveTemplateLocked(restored); err != nil {
+		return nil, err
+	}
+	return cloneTemplate(restored), nil
+}
+
+// DiffTemplateVersions compares the content of two versions of a template.
+func (jps *JSONPayloadStudio) DiffTemplateVersions(id string, from, to int) (*DiffResult, error) {
+	jps.mu.RLock()
+	defer jps.mu.RUnlock()
+
+	oldVersion, err := jps.templateVersionLocked(id, from)
+	if err != nil {
+		return nil, err
+	}
+	newVersion, err := jps.templateVersionLocked(id, to)
+	if err != nil {
+		return nil, err
+	}
+	return jps.compareJSON(oldVersion.Content, newVersion.Content), nil
+}
+
+// templateVersionLocked returns the stored version without copying it.
+// Callers hold jps.mu.
+func (jps *JSONPayloadStudio) templateVersionLocked(id string, version int) (*Template, error) {
+	if _, exists := jps.templates[id]; !exists {
+		return nil, fmt.Errorf("template not found: %s", id)
+	}
+	history := jps.versions[id]
+	if version < 1 || version > len(history) {
+		return nil, fmt.Errorf("template %s has no version %d (versions: %d)", id, version, len(history))
+	}
+	return history[version-1], nil
+}
+
+// saveTemplateLocked appends tmpl to its template's history and makes it
+// current, writing both to TemplatesPath when one is configured. A
+// template that exists without history, such as one loaded from an older
+// templates directory, is first recorded as version 1 so its content is
+// kept. Callers hold jps.mu.
+func (jps *JSONPayloadStudio) saveTemplateLocked(tmpl *Template) error {
+	history := jps.versions[tmpl.ID]
+	if existing, ok := jps.templates[tmpl.ID]; ok && len(history) == 0 {
+		first := cloneTemplate(existing)
+		first.CurrentVersion, first.Versions = 1, 1
+		if err := jps.saveTemplateVersionToDisk(first); err != nil {
+			return fmt.Errorf("save template %s: %w", tmpl.ID, err)
+		}
+		history = append(history, first)
+	}
+
+	n := len(history) + 1
+	tmpl.CurrentVersion, tmpl.Versions = n, n
+	if err := jps.saveTemplateVersionToDisk(tmpl); err != nil {
+		return fmt.Errorf("save template %s: %w", tmpl.ID, err)
+	}
+	if err := jps.saveTemplateToDisk(tmpl); err != nil {
+		return fmt.Errorf("save template %s: %w", tmpl.ID, err)
+	}
+	jps.versions[tmpl.ID] = append(history, cloneTemplate(tmpl))
+	jps.templates[tmpl.ID] = tmpl
+	return nil
+}
+
+func (jps *JSONPayloadStudio) saveTemplateVersionToDisk(tmpl *Template) error {
+	if jps.config.TemplatesPath == "" {
+		return nil
+	}
+	// same file name rules as snippets
+	if !validSnippetID(tmpl.ID) {
+		return fmt.Errorf("invalid template id %q", tmpl.ID)
+	}
+
+	dir := filepath.Join(jps.config.TemplatesPath, templateVersionsDir, tmpl.ID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(tmpl, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	filename := filepath.Join(dir, fmt.Sprintf("%d.json", tmpl.CurrentVersion))
+	tmp := filename + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
+
+// deleteTemplateFromDisk removes a template's file and its history.
+func (jps *JSONPayloadStudio) deleteTemplateFromDisk(id string) error {
+	if jps.config.TemplatesPath == "" || !validSnippetID(id) {
+		return nil
+	}
+	err := os.Remove(filepath.Join(jps.config.TemplatesPath, id+".json"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return os.RemoveAll(filepath.Join(jps.config.TemplatesPath, templateVersionsDir, id))
+}
+
+// loadTemplateVersions reads the history of every loaded template. A
+// history stops at the first missing version; files after a gap are
+// skipped with a warning.
+func (jps *JSONPayloadStudio) loadTemplateVersions() error {
+	if jps.config.TemplatesPath == "" {
+		return nil
+	}
+
+	root := filepath.Join(jps.config.TemplatesPath, templateVersionsDir)
+	dirs, err := os.ReadDir(root)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, dir := range dirs {
+		id := dir.Name()
+		current, ok := jps.templates[id]
+		if !dir.IsDir() || !ok {
+			continue
+		}
+		entries, err := os.ReadDir(filepath.Join(root, id))
+		if err != nil {
+			return err
+		}
+
+		byNumber := make(map[int]*Template)
+		numbers := make([]int, 0, len(entries))
+		for _, entry := range entries {
+			n, err := strconv.Atoi(strings.TrimSuffix(entry.Name(), ".json"))
+			if err != nil || !strings.HasSuffix(entry.Name(), ".json") {
+				continue
+			}
+			path := filepath.Join(root, id, entry.Name())
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			var tmpl Template
+			if err := json.Unmarshal(data, &tmpl); err != nil {
+				jps.logger.Warn("Failed to parse template version", zap.String("path", path), zap.Error(err))
+				continue
+			}
+			byNumber[n] = &tmpl
+			numbers = append(numbers, n)
+		}
+		sort.Ints(numbers)
+
+		var history []*Template
+		for _, n := range numbers {
+			if n != len(history)+1 {
+				jps.logger.Warn("Template history has a gap; later versions skipped",
+					zap.String("template", id), zap.Int("missing", len(history)+1))
+				break
+			}
+			history = append(history, byNumber[n])
+		}
+		jps.versions[id] = history
+		current.Versions = len(history)
+	}
+	return nil
+}
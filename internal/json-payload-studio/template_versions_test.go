@@ -0,0 +1,105 @@
+// Copyright 2025 James Ross
+package jsonpayloadstudio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func newTemplateStudio(t *testing.T, dir string) *JSONPayloadStudio {
+	t.Helper()
+	cfg := DefaultConfig()
+	cfg.TemplatesPath = dir
+	cfg.SchemasPath = ""
+	cfg.SnippetsPath = ""
+	cfg.AutoSave = false
+	studio, err := NewJSONPayloadStudio(cfg, nil, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return studio
+}
+
+func TestTemplateVersionsRollbackAndReload(t *testing.T) {
+	dir := t.TempDir()
+	studio := newTemplateStudio(t, dir)
+
+	for _, status := range []string{"new", "paid", "shipped"} {
+		tmpl := &Template{ID: "order", Name: "Order", Content: map[string]interface{}{"status": status}}
+		if err := studio.SaveTemplate(tmpl); err != nil {
+			t.Fatalf("SaveTemplate: %v", err)
+		}
+	}
+
+	list := studio.ListTemplates()
+	if len(list) != 1 || list[0].CurrentVersion != 3 || list[0].Versions != 3 {
+		t.Fatalf("expected one template at version 3 of 3, got %+v", list)
+	}
+	v1, err := studio.GetTemplateVersion("order", 1)
+	if err != nil || v1.Content["status"] != "new" {
+		t.Fatalf("expected version 1 to keep its content, got %+v, %v", v1, err)
+	}
+	if _, err := studio.GetTemplateVersion("order", 4); err == nil {
+		t.Fatal("expected an error for a missing version")
+	}
+
+	diff, err := studio.DiffTemplateVersions("order", 1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !diff.HasChanges || len(diff.Modified) != 1 || diff.Modified[0].NewValue != "shipped" {
+		t.Fatalf("unexpected diff %+v", diff)
+	}
+
+	rolled, err := studio.RollbackTemplate("order", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rolled.CurrentVersion != 4 || rolled.Content["status"] != "new" {
+		t.Fatalf("expected the rollback saved as version 4, got %+v", rolled)
+	}
+	if v3, _ := studio.GetTemplateVersion("order", 3); v3 == nil || v3.Content["status"] != "shipped" {
+		t.Fatalf("expected version 3 kept after the rollback, got %+v", v3)
+	}
+
+	// history survives a restart
+	reloaded := newTemplateStudio(t, dir)
+	current, err := reloaded.GetTemplate("order")
+	if err != nil || current.CurrentVersion != 4 || current.Versions != 4 || current.Content["status"] != "new" {
+		t.Fatalf("unexpected template after reload: %+v, %v", current, err)
+	}
+	if v2, err := reloaded.GetTemplateVersion("order", 2); err != nil || v2.Content["status"] != "paid" {
+		t.Fatalf("expected version 2 after reload, got %+v, %v", v2, err)
+	}
+
+	if err := reloaded.DeleteTemplate("order"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, templateVersionsDir, "order")); !os.IsNotExist(err) {
+		t.Fatalf("expected history removed with the template, got %v", err)
+	}
+}
+
+func TestTemplateWithoutHistoryKeepsContentOnSave(t *testing.T) {
+	dir := t.TempDir()
+	legacy := `{"id": "legacy", "name": "Legacy", "content": {"v": "old"}}`
+	if err := os.WriteFile(filepath.Join(dir, "legacy.json"), []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	studio := newTemplateStudio(t, dir)
+
+	if err := studio.SaveTemplate(&Template{ID: "legacy", Name: "Legacy", Content: map[string]interface{}{"v": "new"}}); err != nil {
+		t.Fatal(err)
+	}
+	v1, err := studio.GetTemplateVersion("legacy", 1)
+	if err != nil || v1.Content["v"] != "old" {
+		t.Fatalf("expected the pre-history content as version 1, got %+v, %v", v1, err)
+	}
+	current, _ := studio.GetTemplate("legacy")
+	if current.CurrentVersion != 2 || current.Versions != 2 {
+		t.Fatalf("expected version 2 of 2, got %+v", current)
+	}
+}
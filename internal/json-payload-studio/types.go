@@ -31,6 +31,10 @@ type Template struct {
 	UpdatedAt   time.Time              `json:"updated_at"`
 	Author      string                 `json:"author"`
 	Version     string                 `json:"version"`
+	// CurrentVersion is the saved version this template is; Versions is
+	// how many versions its history holds.
+	CurrentVersion int `json:"current_version,omitempty"`
+	Versions       int `json:"versions,omitempty"`
 }
 
 // TemplateVariable represents a variable in a template
@@ -117,6 +121,31 @@ type EnqueueResult struct {
 	EnqueuedAt   time.Time         `json:"enqueued_at"`
 }
 
+// MatrixEnqueueOptions configures EnqueueMatrix. Count and CronSpec from the
+// embedded EnqueueOptions are ignored: one job is enqueued per variable set.
+type MatrixEnqueueOptions struct {
+	EnqueueOptions
+	// AllOrNothing aborts the whole batch when any rendered item is invalid
+	// JSON or too large; otherwise those items are skipped and reported.
+	AllOrNothing bool `json:"all_or_nothing"`
+}
+
+// MatrixItemError explains why one variable set was not enqueued
+type MatrixItemError struct {
+	Index int    `json:"index"`
+	Error string `json:"error"`
+}
+
+// MatrixEnqueueResult reports a matrix enqueue. JobIDs is indexed like the
+// variable sets; skipped items have an empty ID.
+type MatrixEnqueueResult struct {
+	JobIDs     []string          `json:"job_ids"`
+	Queue      string            `json:"queue"`
+	Count      int               `json:"count"`
+	Skipped    []MatrixItemError `json:"skipped,omitempty"`
+	EnqueuedAt time.Time         `json:"enqueued_at"`
+}
+
 // DiffResult represents the difference between two JSON payloads
 type DiffResult struct {
 	HasChanges   bool          `json:"has_changes"`
@@ -160,15 +189,32 @@ type StudioConfig struct {
 	AutoLoadTemplates bool    `json:"auto_load_templates"`
 	TemplateDirs     []string `json:"template_dirs"`
 
+	// Snippet settings
+	SnippetsPath     string   `json:"snippets_path"`
+
 	// Schema settings
 	SchemasPath      string   `json:"schemas_path"`
 	DefaultSchema    string   `json:"default_schema,omitempty"`
 	StrictValidation bool     `json:"strict_validation"`
+	ScaffoldOptionalFields bool `json:"scaffold_optional_fields"`
+	// $ref targets that are not Studio schema IDs are fetched only from
+	// these hosts; empty disables remote references.
+	RemoteSchemaHosts    []string      `json:"remote_schema_hosts,omitempty"`
+	RemoteSchemaTimeout  time.Duration `json:"remote_schema_timeout"`
+	RemoteSchemaCacheTTL time.Duration `json:"remote_schema_cache_ttl"`
 
 	// Safety settings
 	MaxPayloadSize   int      `json:"max_payload_size"`
 	MaxFieldCount    int      `json:"max_field_count"`
 	MaxNestingDepth  int      `json:"max_nesting_depth"`
+	// EnforceComplexityLimits rejects enqueues that break MaxPayloadSize,
+	// MaxNestingDepth or MaxFieldCount; otherwise only size is enforced
+	// and ValidateJSON just warns about depth and field count.
+	EnforceComplexityLimits bool `json:"enforce_complexity_limits"`
+	// LenientInput accepts JSON5/JSONC (comments, trailing commas, unquoted
+	// keys, single-quoted strings) and normalizes it to strict JSON before
+	// validating, formatting or enqueueing.
+	LenientInput bool `json:"lenient_input"`
 	StripSecrets     bool     `json:"strip_secrets"`
 	SecretPatterns   []string `json:"secret_patterns"`
 	RequireConfirm   bool     `json:"require_confirm"`
@@ -176,6 +222,7 @@ type StudioConfig struct {
 	// UI settings
 	ShowPreview      bool     `json:"show_preview"`
 	PreviewLines     int      `json:"preview_lines"`
+	DiffMaxValueLength int    `json:"diff_max_value_length"`
 	HistorySize      int      `json:"history_size"`
 	AutoSave         bool     `json:"auto_save"`
 	AutoSaveInterval time.Duration `json:"auto_save_interval"`
@@ -245,6 +292,10 @@ type SessionInfo struct {
 	JobsEnqueued int             `json:"jobs_enqueued"`
 	Templates    []string        `json:"templates_used"`
 	AutoSaved    bool            `json:"auto_saved"`
+	// RedactionAllow lists fields secret stripping leaves alone in this
+	// session: paths as RedactionPreview reports them, or bare field names
+	// matching that field at any depth
+	RedactionAllow []string `json:"redaction_allow,omitempty"`
 }
 
 // TemplateFilter represents filters for template search
@@ -290,4 +341,21 @@ type EditorEvent struct {
 	Action    EditorAction `json:"action,omitempty"`
 	Data      interface{} `json:"data,omitempty"`
 	Error     string      `json:"error,omitempty"`
-}
\ No newline at end of file
+}
+
+// RedactionPreview shows what secret stripping would change in a session's
+// payload before it is enqueued
+type RedactionPreview struct {
+	SessionID  string          `json:"session_id"`
+	Enabled    bool            `json:"enabled"` // StripSecrets is on
+	Redactions []RedactedField `json:"redactions"`
+	Allowed    []string        `json:"allowed"` // secret-named paths kept by the session's allow list
+	Before     interface{}     `json:"before"`
+	After      interface{}     `json:"after"`
+}
+
+// RedactedField is one field secret stripping would replace
+type RedactedField struct {
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"` // before redaction
+}
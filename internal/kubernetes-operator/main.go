@@ -1,10 +1,17 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
+	"encoding/json"
 	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
 	"os"
+	"strings"
 	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -45,6 +52,7 @@ func main() {
 	var enableHTTP2 bool
 	var webhookPort int
 	var adminAPIEndpoint string
+	var adminAPIToken string
 	var metricsEndpoint string
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
@@ -59,6 +67,8 @@ func main() {
 	flag.IntVar(&webhookPort, "webhook-port", 9443, "The port that the webhook server serves at.")
 	flag.StringVar(&adminAPIEndpoint, "admin-api-endpoint", "http://localhost:8080",
 		"The endpoint URL for the queue system Admin API")
+	flag.StringVar(&adminAPIToken, "admin-api-token", os.Getenv("ADMIN_API_TOKEN"),
+		"Bearer token for the Admin API (default $ADMIN_API_TOKEN)")
 	flag.StringVar(&metricsEndpoint, "metrics-endpoint", "http://localhost:9090",
 		"The endpoint URL for the Prometheus metrics server")
 
@@ -124,7 +134,7 @@ func main() {
 	}
 
 	// Create Admin API client
-	adminAPIClient, err := NewAdminAPIClient(adminAPIEndpoint)
+	adminAPIClient, err := NewAdminAPIClient(adminAPIEndpoint, adminAPIToken)
 	if err != nil {
 		setupLog.Error(err, "unable to create Admin API client")
 		os.Exit(1)
@@ -181,49 +191,143 @@ func main() {
 	}
 }
 
-// AdminAPIClient implementation
+// AdminAPIClient talks to the queue definition endpoints of the Admin API
+// (/api/v1/queues). The queue name is used as the Redis key. Retention and
+// per-queue Redis settings have no Admin API counterpart and are not sent.
 type AdminAPIClient struct {
 	baseURL string
-	// HTTP client would be here in real implementation
+	token   string
+	client  *http.Client
 }
 
-func NewAdminAPIClient(endpoint string) (*AdminAPIClient, error) {
+func NewAdminAPIClient(endpoint, token string) (*AdminAPIClient, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid Admin API endpoint %q", endpoint)
+	}
 	return &AdminAPIClient{
-		baseURL: endpoint,
+		baseURL: strings.TrimSuffix(endpoint, "/"),
+		token:   token,
+		client:  &http.Client{Timeout: 15 * time.Second},
 	}, nil
 }
 
+// adminAPIError is a non-2xx Admin API response.
+type adminAPIError struct {
+	Status  int
+	Code    string `json:"code"`
+	Message string `json:"error"`
+}
+
+func (e *adminAPIError) Error() string {
+	return fmt.Sprintf("admin API %d %s: %s", e.Status, e.Code, e.Message)
+}
+
+// queueDefinition is the Admin API's QueueDefinitionRequest and response.
+type queueDefinition struct {
+	Name       string  `json:"name,omitempty"`
+	Priority   string  `json:"priority,omitempty"`
+	RateLimit  float64 `json:"rate_limit,omitempty"`
+	Burst      int     `json:"burst,omitempty"`
+	DeadLetter struct {
+		MaxRetries *int `json:"max_retries,omitempty"`
+	} `json:"dead_letter"`
+	Length int64 `json:"length,omitempty"`
+}
+
+func toQueueDefinition(config controllers.QueueConfig) queueDefinition {
+	def := queueDefinition{Name: config.Name, Priority: config.Priority}
+	if rl := config.RateLimit; rl != nil && rl.Enabled {
+		def.RateLimit = rl.RequestsPerSecond
+		def.Burst = int(rl.BurstCapacity)
+	}
+	if dlq := config.DeadLetterQueue; dlq != nil && dlq.Enabled {
+		n := int(dlq.MaxRetries)
+		def.DeadLetter.MaxRetries = &n
+	}
+	return def
+}
+
+func (c *AdminAPIClient) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var rd io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		rd = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, rd)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		apiErr := &adminAPIError{Status: resp.StatusCode}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(apiErr)
+		return apiErr
+	}
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
+
+func queuePath(name string) string { return "/api/v1/queues/" + url.PathEscape(name) }
+
 func (c *AdminAPIClient) CreateQueue(ctx context.Context, config controllers.QueueConfig) error {
-	// Implementation would make HTTP calls to Admin API
 	setupLog.Info("Creating queue via Admin API", "queue", config.Name)
-	return nil
+	return c.do(ctx, http.MethodPost, "/api/v1/queues", toQueueDefinition(config), nil)
 }
 
 func (c *AdminAPIClient) UpdateQueue(ctx context.Context, name string, config controllers.QueueConfig) error {
-	// Implementation would make HTTP calls to Admin API
 	setupLog.Info("Updating queue via Admin API", "queue", name)
-	return nil
+	def := toQueueDefinition(config)
+	def.Name = ""
+	return c.do(ctx, http.MethodPut, queuePath(name), def, nil)
 }
 
+// DeleteQueue removes the definition. The Admin API refuses while the queue
+// holds jobs, so the controller retries until it has drained; a queue that
+// is already gone counts as deleted.
 func (c *AdminAPIClient) DeleteQueue(ctx context.Context, name string) error {
-	// Implementation would make HTTP calls to Admin API
 	setupLog.Info("Deleting queue via Admin API", "queue", name)
-	return nil
+	err := c.do(ctx, http.MethodDelete, queuePath(name), nil, nil)
+	if apiErr, ok := err.(*adminAPIError); ok && apiErr.Status == http.StatusNotFound {
+		return nil
+	}
+	return err
 }
 
+// GetQueueMetrics reports the backlog from the queue definition; the
+// rates come from Prometheus through the MetricsClient.
 func (c *AdminAPIClient) GetQueueMetrics(ctx context.Context, name string) (*controllers.QueueMetrics, error) {
-	// Implementation would make HTTP calls to Admin API
+	var def queueDefinition
+	if err := c.do(ctx, http.MethodGet, queuePath(name), nil, &def); err != nil {
+		return nil, err
+	}
 	return &controllers.QueueMetrics{
-		BacklogSize:    0,
-		ProcessingRate: 0,
-		ErrorRate:      0,
-		AverageLatency: 0,
-		LastUpdated:    time.Now(),
+		BacklogSize: def.Length,
+		LastUpdated: time.Now(),
 	}, nil
 }
 
+// GetQueueStatus fails for a queue that is not defined, which makes the
+// controller create it.
 func (c *AdminAPIClient) GetQueueStatus(ctx context.Context, name string) (*controllers.QueueStatus, error) {
-	// Implementation would make HTTP calls to Admin API
+	if err := c.do(ctx, http.MethodGet, queuePath(name), nil, nil); err != nil {
+		return nil, err
+	}
 	return &controllers.QueueStatus{
 		State:       "active",
 		LastUpdated: time.Now(),
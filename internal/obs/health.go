@@ -0,0 +1,225 @@
+// Copyright 2025 James Ross
+package obs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/redis/go-redis/v9"
+)
+
+// HealthStatus is the outcome of one health check, or of all of them.
+type HealthStatus string
+
+const (
+	HealthPass HealthStatus = "pass"
+	HealthWarn HealthStatus = "warn"
+	HealthFail HealthStatus = "fail"
+)
+
+// defaultHealthCheckTimeout applies when observability.health_check_timeout
+// is unset.
+const defaultHealthCheckTimeout = 2 * time.Second
+
+// HealthCheckFunc checks one dependency. It passes by returning nil, warns
+// by returning an error from HealthWarning and fails on any other error.
+type HealthCheckFunc func(ctx context.Context) error
+
+type healthWarning struct{ msg string }
+
+func (w *healthWarning) Error() string { return w.msg }
+
+// HealthWarning returns an error that makes a health check warn: the
+// dependency works but needs attention. Warnings do not fail /readyz.
+func HealthWarning(format string, args ...interface{}) error {
+	return &healthWarning{msg: fmt.Sprintf(format, args...)}
+}
+
+// HealthCheckResult is the outcome of one check.
+type HealthCheckResult struct {
+	Status     HealthStatus `json:"status"`
+	Message    string       `json:"message,omitempty"`
+	DurationMs float64      `json:"duration_ms"`
+}
+
+// HealthReport is the body of /healthz and /readyz. Status is the worst
+// status of any check.
+type HealthReport struct {
+	Status    HealthStatus                 `json:"status"`
+	CheckedAt time.Time                    `json:"checked_at"`
+	Checks    map[string]HealthCheckResult `json:"checks"`
+}
+
+var healthChecks = struct {
+	sync.Mutex
+	m map[string]HealthCheckFunc
+}{m: map[string]HealthCheckFunc{}}
+
+// RegisterHealthCheck adds fn to the checks StartHTTPServer reports, under
+// name. Registering a name again replaces its check; a nil fn removes it.
+func RegisterHealthCheck(name string, fn HealthCheckFunc) {
+	healthChecks.Lock()
+	defer healthChecks.Unlock()
+	if fn == nil {
+		delete(healthChecks.m, name)
+		return
+	}
+	healthChecks.m[name] = fn
+}
+
+// registeredHealthChecks returns a copy of the registered checks.
+func registeredHealthChecks() map[string]HealthCheckFunc {
+	healthChecks.Lock()
+	defer healthChecks.Unlock()
+	out := make(map[string]HealthCheckFunc, len(healthChecks.m))
+	for name, fn := range healthChecks.m {
+		out[name] = fn
+	}
+	return out
+}
+
+// CheckHealth runs the registered checks concurrently, each bounded by
+// timeout (2s when not positive).
+func CheckHealth(ctx context.Context, timeout time.Duration) HealthReport {
+	return runHealthChecks(ctx, timeout, registeredHealthChecks())
+}
+
+func runHealthChecks(ctx context.Context, timeout time.Duration, checks map[string]HealthCheckFunc) HealthReport {
+	if timeout <= 0 {
+		timeout = defaultHealthCheckTimeout
+	}
+	report := HealthReport{Status: HealthPass, CheckedAt: time.Now().UTC(), Checks: make(map[string]HealthCheckResult, len(checks))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, fn := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res := runHealthCheck(ctx, timeout, fn)
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = res
+			if healthRank(res.Status) > healthRank(report.Status) {
+				report.Status = res.Status
+			}
+		}()
+	}
+	wg.Wait()
+	return report
+}
+
+// runHealthCheck runs fn with a deadline. A check that ignores its context
+// is reported as timed out and left to finish in the background.
+func runHealthCheck(ctx context.Context, timeout time.Duration, fn HealthCheckFunc) HealthCheckResult {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- fmt.Errorf("check panicked: %v", p)
+			}
+		}()
+		done <- fn(ctx)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("timed out after %s", timeout)
+	}
+	res := HealthCheckResult{Status: HealthPass, DurationMs: float64(time.Since(start).Microseconds()) / 1000}
+	var warn *healthWarning
+	switch {
+	case err == nil:
+	case errors.As(err, &warn):
+		res.Status, res.Message = HealthWarn, err.Error()
+	default:
+		res.Status, res.Message = HealthFail, err.Error()
+	}
+	return res
+}
+
+func healthRank(s HealthStatus) int {
+	switch s {
+	case HealthFail:
+		return 2
+	case HealthWarn:
+		return 1
+	}
+	return 0
+}
+
+// RedisHealthCheck pings rdb. A ping slower than slow warns; 0 never warns.
+func RedisHealthCheck(rdb *redis.Client, slow time.Duration) HealthCheckFunc {
+	return func(ctx context.Context) error {
+		start := time.Now()
+		if err := rdb.Ping(ctx).Err(); err != nil {
+			return err
+		}
+		if took := time.Since(start); slow > 0 && took > slow {
+			return HealthWarning("ping took %s", took.Round(time.Millisecond))
+		}
+		return nil
+	}
+}
+
+// QueueHealthCheck reads the length of every worker queue: a key of the
+// wrong type for worker.mode fails, and a list queue at
+// producer.max_queue_length warns.
+func QueueHealthCheck(cfg *config.Config, rdb *redis.Client) HealthCheckFunc {
+	return func(ctx context.Context) error {
+		stream := cfg.Worker.Mode == config.ModeStream
+		keys := make([]string, 0, len(cfg.Worker.Queues))
+		for _, key := range cfg.Worker.Queues {
+			if stream {
+				key = queue.StreamKey(key)
+			}
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		pipe := rdb.Pipeline()
+		cmds := make([]*redis.IntCmd, len(keys))
+		for i, key := range keys {
+			if stream {
+				cmds[i] = pipe.XLen(ctx, key)
+			} else {
+				cmds[i] = pipe.LLen(ctx, key)
+			}
+		}
+		_, _ = pipe.Exec(ctx)
+		var full []string
+		for i, cmd := range cmds {
+			n, err := cmd.Result()
+			if err != nil {
+				return fmt.Errorf("%s: %w", keys[i], err)
+			}
+			if max := cfg.Producer.MaxQueueLength; !stream && max > 0 && n >= max {
+				full = append(full, fmt.Sprintf("%s (%d)", keys[i], n))
+			}
+		}
+		if len(full) > 0 {
+			return HealthWarning("at max_queue_length: %s", strings.Join(full, ", "))
+		}
+		return nil
+	}
+}
+
+// FreshnessHealthCheck fails when last, the time something last happened,
+// is more than maxAge ago, e.g. a background loop that stopped running.
+func FreshnessHealthCheck(what string, last func() time.Time, maxAge time.Duration) HealthCheckFunc {
+	return func(ctx context.Context) error {
+		if age := time.Since(last()); age > maxAge {
+			return fmt.Errorf("%s %s ago (max %s)", what, age.Round(time.Second), maxAge)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 James Ross
+package obs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestCheckHealthRunsChecksConcurrentlyWithTimeouts(t *testing.T) {
+	checks := map[string]HealthCheckFunc{
+		"ok":   func(context.Context) error { return nil },
+		"warn": func(context.Context) error { return HealthWarning("%d jobs stuck", 3) },
+		"slow": func(ctx context.Context) error { <-ctx.Done(); return ctx.Err() },
+		"deaf": func(context.Context) error { time.Sleep(time.Second); return nil },
+	}
+	for name, fn := range checks {
+		RegisterHealthCheck(name, fn)
+		t.Cleanup(func() { RegisterHealthCheck(name, nil) })
+	}
+
+	start := time.Now()
+	rep := CheckHealth(context.Background(), 50*time.Millisecond)
+	if took := time.Since(start); took > 500*time.Millisecond {
+		t.Fatalf("expected checks to run concurrently within their timeout, took %s", took)
+	}
+	if rep.Status != HealthFail || len(rep.Checks) != 4 {
+		t.Fatalf("expected a failing report of 4 checks, got %+v", rep)
+	}
+	if c := rep.Checks["ok"]; c.Status != HealthPass || c.Message != "" {
+		t.Fatalf("expected ok to pass, got %+v", c)
+	}
+	if c := rep.Checks["warn"]; c.Status != HealthWarn || c.Message != "3 jobs stuck" {
+		t.Fatalf("expected warn to warn, got %+v", c)
+	}
+	for _, name := range []string{"slow", "deaf"} {
+		if c := rep.Checks[name]; c.Status != HealthFail || !strings.Contains(c.Message, "timed out") {
+			t.Fatalf("expected %s to time out, got %+v", name, c)
+		}
+	}
+
+	RegisterHealthCheck("slow", nil)
+	RegisterHealthCheck("deaf", nil)
+	if rep := CheckHealth(context.Background(), time.Second); rep.Status != HealthWarn {
+		t.Fatalf("expected a warning overall, got %+v", rep)
+	}
+}
+
+func TestQueueHealthCheck(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	ctx := context.Background()
+	cfg := &config.Config{
+		Worker:   config.Worker{Mode: config.ModeList, Queues: map[string]string{"high": "q:high", "low": "q:low"}},
+		Producer: config.Producer{MaxQueueLength: 2},
+	}
+	check := QueueHealthCheck(cfg, rdb)
+	if err := check(ctx); err != nil {
+		t.Fatalf("expected empty queues to pass, got %v", err)
+	}
+
+	rdb.LPush(ctx, "q:high", "a", "b")
+	var warn *healthWarning
+	if err := check(ctx); !errors.As(err, &warn) || !strings.Contains(err.Error(), "q:high (2)") {
+		t.Fatalf("expected a full queue warning, got %v", err)
+	}
+
+	rdb.Set(ctx, "q:low", "x", 0)
+	if err := check(ctx); err == nil || errors.As(err, &warn) {
+		t.Fatalf("expected a wrong type failure, got %v", err)
+	}
+
+	stale := FreshnessHealthCheck("last scan", func() time.Time { return time.Now().Add(-time.Minute) }, 30*time.Second)
+	if err := stale(ctx); err == nil {
+		t.Fatal("expected a stale run to fail")
+	}
+}
@@ -3,6 +3,7 @@ package obs
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 
@@ -11,29 +12,39 @@ import (
 )
 
 // StartHTTPServer exposes /metrics, /healthz and /readyz.
-// readiness is a callback that should return nil when the app is ready.
+// readiness is an optional extra check, reported as "ready"; the checks
+// added with RegisterHealthCheck run alongside it. Both health endpoints
+// answer with a HealthReport. /healthz is liveness and always returns 200
+// while the process serves requests; /readyz returns 503 when any check
+// fails.
 func StartHTTPServer(cfg *config.Config, readiness func(context.Context) error) *http.Server {
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", promhttp.Handler())
+	report := func(r *http.Request) HealthReport {
+		checks := registeredHealthChecks()
+		if readiness != nil {
+			checks["ready"] = readiness
+		}
+		return runHealthChecks(r.Context(), cfg.Observability.HealthCheckTimeout, checks)
+	}
 	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
-		// Liveness: if the process is up, return 200
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte("ok"))
+		writeHealthReport(w, http.StatusOK, report(r))
 	})
 	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
-		if readiness == nil {
-			w.WriteHeader(http.StatusOK)
-			_, _ = w.Write([]byte("ready"))
-			return
-		}
-		if err := readiness(r.Context()); err != nil {
-			http.Error(w, fmt.Sprintf("not ready: %v", err), http.StatusServiceUnavailable)
-			return
+		rep := report(r)
+		code := http.StatusOK
+		if rep.Status == HealthFail {
+			code = http.StatusServiceUnavailable
 		}
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte("ready"))
+		writeHealthReport(w, code, rep)
 	})
 	srv := &http.Server{Addr: fmt.Sprintf(":%d", cfg.Observability.MetricsPort), Handler: mux}
 	go func() { _ = srv.ListenAndServe() }()
 	return srv
 }
+
+func writeHealthReport(w http.ResponseWriter, code int, rep HealthReport) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(rep)
+}
@@ -46,24 +46,102 @@ var (
 	}, []string{"queue"})
 	CircuitBreakerState = prometheus.NewGauge(prometheus.GaugeOpts{
 		Name: "circuit_breaker_state",
-		Help: "0 Closed, 1 HalfOpen, 2 Open",
+		Help: "Most severe per-queue breaker state: 0 Closed, 1 HalfOpen, 2 Open",
 	})
+	QueueCircuitBreakerState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "queue_circuit_breaker_state",
+		Help: "Per-queue circuit breaker state: 0 Closed, 1 HalfOpen, 2 Open",
+	}, []string{"queue"})
+	JobsBreakerRequeued = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "jobs_breaker_requeued_total",
+		Help: "Total number of jobs put back on their queue because its circuit breaker was open",
+	}, []string{"queue"})
 	CircuitBreakerTrips = prometheus.NewCounter(prometheus.CounterOpts{
 		Name: "circuit_breaker_trips_total",
 		Help: "Count of times the circuit breaker transitioned to Open",
 	})
+	JobsDeduplicated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "jobs_deduplicated_total",
+		Help: "Total number of jobs acked without running because their job ID was already done or in progress, by queue",
+	}, []string{"queue"})
+	JobsCoalesced = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "jobs_coalesced_total",
+		Help: "Total number of jobs completed without running because a job with the same dedup key was in flight or recently completed, by queue",
+	}, []string{"queue"})
+	JobsPromoted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "jobs_promoted_total",
+		Help: "Total number of scheduled or delayed jobs moved onto their live queue, by queue",
+	}, []string{"queue"})
+	JobsAged = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "jobs_aged_total",
+		Help: "Total number of jobs promoted to a higher priority queue after waiting past producer.priority_aging, by source and destination queue",
+	}, []string{"queue", "to"})
+	CompletionEventsFailed = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "completion_events_failed_total",
+		Help: "Total number of best-effort job completion events that could not be published",
+	})
+	PriorityServed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "worker_priority_served_total",
+		Help: "Jobs dequeued per priority, to check how worker.fair_scheduling splits work",
+	}, []string{"priority"})
+	QueueThrottled = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "queue_throttled_total",
+		Help: "Fetches skipped because the queue was at its worker.queue_rate_limits rate, by queue",
+	}, []string{"queue"})
+	CompletedTrimmed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "completed_trimmed_total",
+		Help: "Entries removed from the completed list by its retention policy, by reason (count, age)",
+	}, []string{"reason"})
+	QueueSilent = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "queue_silent",
+		Help: "1 while worker.silence_alert considers the queue silent, 0 otherwise, by queue",
+	}, []string{"queue"})
+	ProducerBackpressure = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "producer_backpressure_total",
+		Help: "Total number of enqueues that found their queue at producer.max_queue_length, by queue and action (blocked, rejected, overflowed, timed_out)",
+	}, []string{"queue", "action"})
+	PayloadCompressionRatio = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "producer_payload_compression_ratio",
+		Help:    "Stored size over original size of payloads the producer compressed, by codec",
+		Buckets: []float64{0.05, 0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9, 1},
+	}, []string{"codec"})
 	ReaperRecovered = prometheus.NewCounter(prometheus.CounterOpts{
 		Name: "reaper_recovered_total",
 		Help: "Total number of jobs recovered by the reaper from processing lists",
 	})
+	ReaperReclaimed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "reclaimed_total",
+		Help: "Total number of jobs reclaimed by the reaper, by destination queue",
+	}, []string{"queue"})
+	ReaperBackoffDelayed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "reaper_backoff_delayed_total",
+		Help: "Total number of reclaimed jobs parked in delayed:{queue} because they were reclaimed repeatedly, by queue",
+	}, []string{"queue"})
+	ReaperOrphanReclaimed = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "reaper_orphan_reclaimed_total",
+		Help: "Total number of jobs reclaimed from processing lists that had no heartbeat the reaper ever saw",
+	})
+	ReaperLastRun = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "reaper_last_run_timestamp",
+		Help: "Unix timestamp of the last completed reaper scan",
+	})
+	JobHandlerDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "job_handler_duration_seconds",
+		Help:    "Histogram of job handler latencies, by priority and outcome",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"priority", "outcome"})
 	WorkerActive = prometheus.NewGauge(prometheus.GaugeOpts{
 		Name: "worker_active",
 		Help: "Number of active worker goroutines",
 	})
+	WorkerConcurrency = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "worker_concurrency",
+		Help: "Target size of the worker goroutine pool, as set by the autoscaler when enabled",
+	})
 )
 
 func init() {
-	prometheus.MustRegister(JobsProduced, JobsConsumed, JobsCompleted, JobsFailed, JobsRetried, JobsDeadLetter, JobProcessingDuration, QueueLength, CircuitBreakerState, CircuitBreakerTrips, ReaperRecovered, WorkerActive)
+	prometheus.MustRegister(JobsProduced, JobsConsumed, JobsCompleted, JobsFailed, JobsRetried, JobsDeadLetter, JobProcessingDuration, QueueLength, CircuitBreakerState, QueueCircuitBreakerState, CircuitBreakerTrips, JobsBreakerRequeued, JobsDeduplicated, JobsCoalesced, JobsPromoted, JobsAged, CompletionEventsFailed, PriorityServed, QueueThrottled, CompletedTrimmed, QueueSilent, ProducerBackpressure, PayloadCompressionRatio, ReaperRecovered, ReaperReclaimed, ReaperBackoffDelayed, ReaperOrphanReclaimed, ReaperLastRun, JobHandlerDuration, WorkerActive, WorkerConcurrency)
 }
 
 // StartMetricsServer exposes /metrics and returns a server for controlled shutdown.
@@ -0,0 +1,61 @@
+// Copyright 2025 James Ross
+package obs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"go.uber.org/zap"
+)
+
+// RequestIDKey is the log field, job field and header-style name used for
+// request IDs.
+const RequestIDKey = "request_id"
+
+type requestIDCtxKey struct{}
+
+// WithRequestID returns ctx carrying a request ID and the ID itself. An ID
+// already on ctx is kept, so nested calls share one ID for the whole
+// request; otherwise a new random ID is generated.
+func WithRequestID(ctx context.Context) (context.Context, string) {
+	if id := RequestID(ctx); id != "" {
+		return ctx, id
+	}
+	id := newRequestID()
+	return ContextWithRequestID(ctx, id), id
+}
+
+// ContextWithRequestID stores a known request ID on ctx, for example one
+// read back from a job. An empty id leaves ctx unchanged.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, requestIDCtxKey{}, id)
+}
+
+// RequestID returns the request ID on ctx, or "" if there is none.
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDCtxKey{}).(string)
+	return id
+}
+
+// RequestIDField is a log field carrying the request ID on ctx. It is a
+// no-op field when ctx has none, so it can be passed unconditionally.
+func RequestIDField(ctx context.Context) zap.Field {
+	id := RequestID(ctx)
+	if id == "" {
+		return zap.Skip()
+	}
+	return zap.String(RequestIDKey, id)
+}
+
+func newRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,46 @@
+// Copyright 2025 James Ross
+package obs
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestWithRequestID(t *testing.T) {
+	ctx := context.Background()
+	if RequestID(ctx) != "" {
+		t.Fatal("expected no request ID on a bare context")
+	}
+	ctx1, id := WithRequestID(ctx)
+	if id == "" || RequestID(ctx1) != id {
+		t.Fatalf("expected generated ID on context, got %q / %q", id, RequestID(ctx1))
+	}
+	if _, id2 := WithRequestID(ctx1); id2 != id {
+		t.Fatalf("expected existing ID to be kept, got %q want %q", id2, id)
+	}
+	if _, other := WithRequestID(ctx); other == id {
+		t.Fatal("expected a fresh ID for a new request")
+	}
+	if got := RequestID(ContextWithRequestID(ctx, "abc")); got != "abc" {
+		t.Fatalf("expected restored ID, got %q", got)
+	}
+}
+
+func TestRequestIDField(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	log := zap.New(core)
+	log.Info("with", RequestIDField(ContextWithRequestID(context.Background(), "r1")))
+	log.Info("without", RequestIDField(context.Background()))
+
+	entries := logs.All()
+	if got := entries[0].ContextMap()[RequestIDKey]; got != "r1" {
+		t.Fatalf("expected request_id r1, got %v", got)
+	}
+	if _, ok := entries[1].ContextMap()[RequestIDKey]; ok {
+		t.Fatal("expected no request_id field without an ID")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/flyingrobots/go-redis-work-queue/internal/config"
 	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
@@ -12,6 +13,7 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
@@ -20,49 +22,58 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
-// MaybeInitTracing optionally initializes a global tracer provider with sampling and propagation.
+// MaybeInitTracing optionally initializes a global tracer provider that
+// exports spans over OTLP (gRPC or HTTP) through a batch span processor,
+// with sampling and W3C propagation. It returns nil, nil when tracing is
+// disabled or no collector endpoint is configured, either in config or via
+// OTEL_EXPORTER_OTLP_TRACES_ENDPOINT / OTEL_EXPORTER_OTLP_ENDPOINT. The
+// remaining standard OTEL_EXPORTER_OTLP_* variables (headers, timeout,
+// certificates, ...) apply to anything config leaves unset.
 func MaybeInitTracing(cfg *config.Config) (*sdktrace.TracerProvider, error) {
-	if !cfg.Observability.Tracing.Enabled || cfg.Observability.Tracing.Endpoint == "" {
+	tc := cfg.Observability.Tracing
+	if !tc.Enabled || !otlpEndpointConfigured(tc) {
 		return nil, nil
 	}
 
-	// Create OTLP exporter
-	exporter, err := otlptrace.New(context.Background(), otlptracehttp.NewClient(
-		otlptracehttp.WithEndpoint(cfg.Observability.Tracing.Endpoint),
-		otlptracehttp.WithInsecure(),
-	))
+	ctx := context.Background()
+	exporter, err := newOTLPExporter(ctx, tc)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get hostname for resource attributes
-	hostname, _ := os.Hostname()
-
-	// Create resource with service information
-	res := resource.NewWithAttributes(
-		semconv.SchemaURL,
-		semconv.ServiceNameKey.String("go-redis-work-queue"),
-		semconv.ServiceVersionKey.String("1.0.0"),
-		semconv.HostNameKey.String(hostname),
-		attribute.String("environment", cfg.Observability.Tracing.Environment),
-	)
+	res, err := tracingResource(ctx, tc)
+	if err != nil {
+		return nil, err
+	}
 
 	// Configure sampler based on config
 	var sampler sdktrace.Sampler
-	switch cfg.Observability.Tracing.SamplingStrategy {
+	switch tc.SamplingStrategy {
 	case "always":
 		sampler = sdktrace.AlwaysSample()
 	case "never":
 		sampler = sdktrace.NeverSample()
 	case "probabilistic":
-		sampler = sdktrace.TraceIDRatioBased(cfg.Observability.Tracing.SamplingRate)
+		sampler = sdktrace.TraceIDRatioBased(tc.SamplingRate)
 	default:
 		// Default to probabilistic with configured rate
-		sampler = sdktrace.TraceIDRatioBased(cfg.Observability.Tracing.SamplingRate)
+		sampler = sdktrace.TraceIDRatioBased(tc.SamplingRate)
+	}
+
+	// Zero values leave the SDK defaults, which honor OTEL_BSP_* variables.
+	var batch []sdktrace.BatchSpanProcessorOption
+	if tc.MaxQueueSize > 0 {
+		batch = append(batch, sdktrace.WithMaxQueueSize(tc.MaxQueueSize))
+	}
+	if tc.MaxExportBatchSize > 0 {
+		batch = append(batch, sdktrace.WithMaxExportBatchSize(tc.MaxExportBatchSize))
+	}
+	if tc.BatchTimeout > 0 {
+		batch = append(batch, sdktrace.WithBatchTimeout(tc.BatchTimeout))
 	}
 
 	tp := sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(exporter),
+		sdktrace.WithBatcher(exporter, batch...),
 		sdktrace.WithResource(res),
 		sdktrace.WithSampler(sampler),
 	)
@@ -79,6 +90,99 @@ func MaybeInitTracing(cfg *config.Config) (*sdktrace.TracerProvider, error) {
 	return tp, nil
 }
 
+// otlpEndpointConfigured reports whether config or the environment names a
+// collector to export to.
+func otlpEndpointConfigured(tc config.TracingConfig) bool {
+	return tc.Endpoint != "" ||
+		os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != "" ||
+		os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != ""
+}
+
+// otlpProtocol resolves the export protocol from config, then the standard
+// environment variables, defaulting to http/protobuf.
+func otlpProtocol(tc config.TracingConfig) string {
+	protocol := tc.Protocol
+	if protocol == "" {
+		protocol = os.Getenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL")
+	}
+	if protocol == "" {
+		protocol = os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL")
+	}
+	if protocol == "grpc" {
+		return "grpc"
+	}
+	return "http/protobuf"
+}
+
+// newOTLPExporter builds the span exporter. An endpoint with a scheme is
+// used as a URL (TLS follows the scheme); a bare host:port is plaintext, so
+// TLS takes an https:// endpoint. Insecure also
+// forces plaintext for an endpoint taken from the environment.
+func newOTLPExporter(ctx context.Context, tc config.TracingConfig) (*otlptrace.Exporter, error) {
+	withURL := strings.Contains(tc.Endpoint, "://")
+	plaintext := !withURL && (tc.Endpoint != "" || tc.Insecure)
+	if tc.Insecure && strings.HasPrefix(tc.Endpoint, "https://") {
+		return nil, fmt.Errorf("tracing endpoint %q uses TLS but insecure is set", tc.Endpoint)
+	}
+	if otlpProtocol(tc) == "grpc" {
+		var opts []otlptracegrpc.Option
+		switch {
+		case withURL:
+			opts = append(opts, otlptracegrpc.WithEndpointURL(tc.Endpoint))
+		case tc.Endpoint != "":
+			opts = append(opts, otlptracegrpc.WithEndpoint(tc.Endpoint))
+		}
+		if plaintext {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		if len(tc.Headers) > 0 {
+			opts = append(opts, otlptracegrpc.WithHeaders(tc.Headers))
+		}
+		return otlptracegrpc.New(ctx, opts...)
+	}
+
+	var opts []otlptracehttp.Option
+	switch {
+	case withURL:
+		opts = append(opts, otlptracehttp.WithEndpointURL(tc.Endpoint))
+	case tc.Endpoint != "":
+		opts = append(opts, otlptracehttp.WithEndpoint(tc.Endpoint))
+	}
+	if plaintext {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	if len(tc.Headers) > 0 {
+		opts = append(opts, otlptracehttp.WithHeaders(tc.Headers))
+	}
+	return otlptracehttp.New(ctx, opts...)
+}
+
+// tracingResource describes this process: service name and version from
+// config, a per-process instance ID, and anything in OTEL_SERVICE_NAME or
+// OTEL_RESOURCE_ATTRIBUTES, which take precedence.
+func tracingResource(ctx context.Context, tc config.TracingConfig) (*resource.Resource, error) {
+	hostname, _ := os.Hostname()
+	name := tc.ServiceName
+	if name == "" {
+		name = "go-redis-work-queue"
+	}
+	version := tc.ServiceVersion
+	if version == "" {
+		version = "dev"
+	}
+	return resource.New(ctx,
+		resource.WithSchemaURL(semconv.SchemaURL),
+		resource.WithAttributes(
+			semconv.ServiceNameKey.String(name),
+			semconv.ServiceVersionKey.String(version),
+			semconv.ServiceInstanceIDKey.String(fmt.Sprintf("%s-%d", hostname, os.Getpid())),
+			semconv.HostNameKey.String(hostname),
+			attribute.String("environment", tc.Environment),
+		),
+		resource.WithFromEnv(),
+	)
+}
+
 // ContextWithJobSpan creates a span for processing a job, attempting to honor
 // job-provided TraceID/SpanID as a remote parent when present and valid.
 func ContextWithJobSpan(ctx context.Context, job queue.Job) (context.Context, trace.Span) {
@@ -106,6 +210,8 @@ func ContextWithJobSpan(ctx context.Context, job queue.Job) (context.Context, tr
 			attribute.String("job.priority", job.Priority),
 			attribute.Int("job.retries", job.Retries),
 			attribute.String("job.creation_time", job.CreationTime),
+			attribute.String("job.request_id", job.RequestID),
+			attribute.String("job.parent_id", job.ParentID),
 			attribute.String("queue.type", "worker"),
 		),
 	)
@@ -195,7 +301,9 @@ func AddSpanAttributes(ctx context.Context, attrs ...attribute.KeyValue) {
 	}
 }
 
-// TracerShutdown gracefully shuts down the tracer provider.
+// TracerShutdown flushes spans still queued in the batch processor to the
+// exporter and shuts the tracer provider down; ctx bounds how long that may
+// take when the collector is unreachable.
 func TracerShutdown(ctx context.Context, tp *sdktrace.TracerProvider) error {
 	if tp == nil {
 		return nil
@@ -4,6 +4,7 @@ package obs
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/flyingrobots/go-redis-work-queue/internal/config"
 	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
@@ -551,4 +552,63 @@ func BenchmarkInjectExtract(b *testing.B) {
 		carrier := InjectTraceContext(ctx)
 		ExtractTraceContext(context.Background(), carrier)
 	}
-}
\ No newline at end of file
+}
+func TestMaybeInitTracingOTLPFromEnvAndGRPC(t *testing.T) {
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	// Endpoint from the standard environment variable, no endpoint in config.
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4318")
+	cfg := &config.Config{Observability: config.ObservabilityConfig{Tracing: config.TracingConfig{Enabled: true, SamplingRate: 1}}}
+	tp, err := MaybeInitTracing(cfg)
+	if err != nil || tp == nil {
+		t.Fatalf("expected tracer provider from env endpoint, got %v, %v", tp, err)
+	}
+	_ = TracerShutdown(context.Background(), tp)
+
+	// gRPC exporter with a bare host:port, plaintext without insecure.
+	cfg.Observability.Tracing.Protocol = "grpc"
+	cfg.Observability.Tracing.Endpoint = "localhost:4317"
+	cfg.Observability.Tracing.MaxQueueSize = 16
+	cfg.Observability.Tracing.MaxExportBatchSize = 8
+	tp, err = MaybeInitTracing(cfg)
+	if err != nil || tp == nil {
+		t.Fatalf("expected grpc tracer provider, got %v, %v", tp, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_ = TracerShutdown(ctx, tp)
+}
+
+func TestOTLPProtocol(t *testing.T) {
+	if got := otlpProtocol(config.TracingConfig{}); got != "http/protobuf" {
+		t.Errorf("expected http/protobuf by default, got %s", got)
+	}
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "grpc")
+	if got := otlpProtocol(config.TracingConfig{}); got != "grpc" {
+		t.Errorf("expected grpc from env, got %s", got)
+	}
+	if got := otlpProtocol(config.TracingConfig{Protocol: "http/protobuf"}); got != "http/protobuf" {
+		t.Errorf("expected config to win over env, got %s", got)
+	}
+}
+
+func TestTracingResource(t *testing.T) {
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "deployment.region=eu-west-1")
+	res, err := tracingResource(context.Background(), config.TracingConfig{ServiceName: "svc", ServiceVersion: "1.2.3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	attrs := map[string]string{}
+	for _, kv := range res.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	if attrs["service.name"] != "svc" || attrs["service.version"] != "1.2.3" {
+		t.Errorf("expected service name and version from config, got %v", attrs)
+	}
+	if attrs["service.instance.id"] == "" {
+		t.Error("expected a service.instance.id")
+	}
+	if attrs["deployment.region"] != "eu-west-1" {
+		t.Errorf("expected OTEL_RESOURCE_ATTRIBUTES to be applied, got %v", attrs)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2025 James Ross
+package producer
+
+import (
+	"context"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/obs"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/redis/go-redis/v9"
+)
+
+// trackAging records payloads as enqueued onto key now, when key's
+// priority is under producer.priority_aging, for the scheduler to promote
+// once they have waited too long. Workers drop the entries of jobs they
+// dequeue; any left behind are dropped when they come due. A failure is logged and leaves
+// the jobs enqueued, just not aged.
+func (p *Producer) trackAging(ctx context.Context, key string, payloads ...string) {
+	if !p.aging(key) || len(payloads) == 0 {
+		return
+	}
+	now := float64(time.Now().UnixMilli())
+	members := make([]redis.Z, len(payloads))
+	for i, payload := range payloads {
+		members[i] = redis.Z{Score: now, Member: payload}
+	}
+	if err := p.client(key).ZAddNX(ctx, p.cfg.DerivedKey(queue.AgingKey, key), members...).Err(); err != nil {
+		p.log.Warn("recording enqueue time for aging failed", obs.String("queue", key), obs.Err(err))
+	}
+}
+
+// aging reports whether key is the queue of a priority under
+// producer.priority_aging.
+func (p *Producer) aging(key string) bool {
+	for prio := range p.cfg.Producer.PriorityAging {
+		if p.cfg.Worker.Queues[prio] == key {
+			return true
+		}
+	}
+	return false
+}
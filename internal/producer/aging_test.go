@@ -0,0 +1,57 @@
+// Copyright 2025 James Ross
+package producer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+func TestEnqueueRecordsEnqueueTimeForAgedQueues(t *testing.T) {
+	mr, _ := miniredis.Run()
+	defer mr.Close()
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	cfg := &config.Config{
+		Worker: config.Worker{Mode: config.ModeList, Queues: map[string]string{"high": "jobqueue:high_priority", "low": "jobqueue:low_priority"}},
+		Producer: config.Producer{
+			DefaultPriority: "low",
+			MaxBatchSize:    10,
+			PriorityAging:   map[string]config.PriorityAging{"low": {After: time.Minute, CheckInterval: time.Second}},
+		},
+	}
+	p := New(cfg, rdb, zap.NewNop())
+	ctx := context.Background()
+
+	before := time.Now().UnixMilli()
+	if err := p.Enqueue(ctx, "low", `{"id":"a"}`); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Enqueue(ctx, "high", `{"id":"b"}`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.EnqueueBatch(ctx, []queue.Job{{ID: "c", Priority: "low"}, {ID: "d", Priority: "high"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	tracked, err := rdb.ZRangeWithScores(ctx, queue.AgingKey("jobqueue:low_priority"), 0, -1).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tracked) != 2 {
+		t.Fatalf("expected both low jobs tracked, got %v", tracked)
+	}
+	for _, z := range tracked {
+		if int64(z.Score) < before || int64(z.Score) > time.Now().UnixMilli() {
+			t.Fatalf("expected the enqueue time as score, got %v", z)
+		}
+	}
+	if n, _ := rdb.Exists(ctx, queue.AgingKey("jobqueue:high_priority")).Result(); n != 0 {
+		t.Fatal("expected no aging set for a queue without aging")
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2025 James Ross
+package producer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/obs"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrBatchTooLarge is returned by EnqueueBatch for more jobs than
+// producer.max_batch_size.
+var ErrBatchTooLarge = errors.New("batch is too large")
+
+// batchPushScript enqueues every job or none. It first checks that no key
+// holds the wrong type and, for capped lists, that every queue has room for
+// all of its jobs, so no command can fail once pushing starts. It returns
+// the number of jobs pushed, or the key of a full queue.
+// KEYS = one list or stream key per job
+// ARGV[1]=list or stream, ARGV[2]=stream field, then for each key its max
+// list length (0 = uncapped), then one payload per key
+var batchPushScript = redis.NewScript(`
+local kind, n = ARGV[1], #KEYS
+local counts = {}
+for _, key in ipairs(KEYS) do
+  local t = redis.call('TYPE', key).ok
+  if t ~= 'none' and t ~= kind then
+    return redis.error_reply('WRONGTYPE ' .. key .. ' holds a ' .. t)
+  end
+  counts[key] = (counts[key] or 0) + 1
+end
+if kind == 'list' then
+  for i, key in ipairs(KEYS) do
+    local max = tonumber(ARGV[i + 2])
+    if max > 0 and redis.call('LLEN', key) + counts[key] > max then
+      return key
+    end
+  end
+end
+for i, key in ipairs(KEYS) do
+  local payload = ARGV[n + i + 2]
+  if kind == 'list' then
+    redis.call('LPUSH', key, payload)
+  else
+    redis.call('XADD', key, '*', ARGV[2], payload)
+  end
+end
+return #KEYS
+`)
+
+// EnqueueBatch enqueues jobs atomically: either all of them or none. Each
+// job goes to the queue for its Priority, as the file scanner's do, and
+// jobs without an ID, creation time or request ID get one. It returns the
+// job IDs in order. A batch over producer.max_batch_size fails with
+// ErrBatchTooLarge rather than being split. A queue without room for its
+// jobs under its definition's max_length, or else
+// producer.max_queue_length, fails the whole batch with ErrQueueFull
+// whatever the backpressure policy, and so do queues routed to more than
+// one Redis cluster, which cannot be written in one step.
+func (p *Producer) EnqueueBatch(ctx context.Context, jobs []queue.Job) ([]string, error) {
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+	if limit := p.cfg.Producer.MaxBatchSize; len(jobs) > limit {
+		return nil, fmt.Errorf("%w: %d jobs, producer.max_batch_size is %d", ErrBatchTooLarge, len(jobs), limit)
+	}
+
+	ctx, reqID := obs.WithRequestID(ctx)
+	stream := p.cfg.Worker.Mode == config.ModeStream
+	ids := make([]string, len(jobs))
+	keys := make([]string, len(jobs))
+	caps := make([]interface{}, len(jobs))
+	payloads := make([]interface{}, len(jobs))
+	maxLen := map[string]int64{}
+	var rdb *redis.Client
+	for i, j := range jobs {
+		if j.ID == "" {
+			j.ID = randID()
+		}
+		if j.CreationTime == "" {
+			j.CreationTime = time.Now().UTC().Format(time.RFC3339Nano)
+		}
+		if j.RequestID == "" {
+			j.RequestID = reqID
+		}
+		if j.Priority == "" {
+			j.Priority = p.cfg.Producer.DefaultPriority
+		}
+		key := p.cfg.Worker.Queues[j.Priority]
+		if key == "" {
+			key = p.cfg.Worker.Queues[p.cfg.Producer.DefaultPriority]
+		}
+		if key == "" {
+			return nil, fmt.Errorf("job %s: no queue for priority %q", j.ID, j.Priority)
+		}
+		if c := p.client(key); rdb == nil {
+			rdb = c
+		} else if c != rdb {
+			return nil, fmt.Errorf("batch spans queues on different redis clusters (%s and %s)", keys[0], key)
+		}
+		payload, err := j.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = j.ID
+		keys[i] = key
+		caps[i] = int64(0)
+		if stream {
+			keys[i] = queue.StreamKey(key)
+		} else {
+			if _, ok := maxLen[key]; !ok {
+				maxLen[key] = p.maxQueueLength(ctx, key)
+			}
+			caps[i] = maxLen[key]
+		}
+		payloads[i] = p.compress(payload)
+	}
+
+	kind := "list"
+	if stream {
+		kind = "stream"
+	}
+	args := append([]interface{}{kind, queue.StreamField}, caps...)
+	res, err := batchPushScript.Run(ctx, rdb, keys, append(args, payloads...)...).Result()
+	if err != nil {
+		return nil, err
+	}
+	if full, ok := res.(string); ok {
+		obs.ProducerBackpressure.WithLabelValues(full, "rejected").Inc()
+		return nil, fmt.Errorf("%w: %s has no room for the batch", ErrQueueFull, full)
+	}
+	if stream {
+		trimmed := map[string]bool{}
+		for _, key := range keys {
+			if !trimmed[key] {
+				trimmed[key] = true
+				p.trimStream(ctx, rdb, key)
+			}
+		}
+	} else {
+		byKey := map[string][]string{}
+		for i, key := range keys {
+			byKey[key] = append(byKey[key], payloads[i].(string))
+		}
+		for key, payloads := range byKey {
+			p.trackAging(ctx, key, payloads...)
+		}
+	}
+	obs.JobsProduced.Add(float64(len(jobs)))
+	p.log.Info("enqueued job batch", obs.Int("jobs", len(jobs)), obs.RequestIDField(ctx))
+	return ids, nil
+}
@@ -0,0 +1,122 @@
+// Copyright 2025 James Ross
+package producer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+func TestEnqueueBatchIsAllOrNothing(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	cfg := &config.Config{
+		Worker: config.Worker{Mode: config.ModeList, Queues: map[string]string{
+			"high": "jobqueue:high_priority", "low": "jobqueue:low_priority"}},
+		Producer: config.Producer{DefaultPriority: "low", MaxQueueLength: 3, MaxBatchSize: 4},
+	}
+	p := New(cfg, rdb, zap.NewNop())
+	ctx := context.Background()
+
+	ids, err := p.EnqueueBatch(ctx, []queue.Job{{ID: "a", Priority: "high"}, {}, {Priority: "high"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 3 || ids[0] != "a" || ids[1] == "" || ids[2] == "" {
+		t.Fatalf("expected three job IDs starting with a, got %v", ids)
+	}
+	high, _ := rdb.LRange(ctx, "jobqueue:high_priority", 0, -1).Result()
+	if len(high) != 2 {
+		t.Fatalf("expected 2 high priority jobs, got %v", high)
+	}
+	j, err := queue.UnmarshalJob(high[1])
+	if err != nil || j.ID != "a" || j.CreationTime == "" || j.RequestID == "" {
+		t.Fatalf("expected job a with creation time and request ID, got %+v (%v)", j, err)
+	}
+	if n, _ := rdb.LLen(ctx, "jobqueue:low_priority").Result(); n != 1 {
+		t.Fatalf("expected 1 low priority job, got %d", n)
+	}
+
+	// two more high priority jobs would pass the cap: nothing is pushed
+	_, err = p.EnqueueBatch(ctx, []queue.Job{{Priority: "low"}, {Priority: "high"}, {Priority: "high"}})
+	if !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("expected ErrQueueFull, got %v", err)
+	}
+	if n, _ := rdb.LLen(ctx, "jobqueue:low_priority").Result(); n != 1 {
+		t.Fatalf("expected the low priority job to be rolled back, got %d jobs", n)
+	}
+
+	// a key of the wrong type fails the batch before anything is pushed
+	rdb.Set(ctx, "jobqueue:high_priority", "x", 0)
+	if _, err := p.EnqueueBatch(ctx, []queue.Job{{Priority: "low"}, {Priority: "high"}}); err == nil {
+		t.Fatal("expected a wrong type error")
+	}
+	if n, _ := rdb.LLen(ctx, "jobqueue:low_priority").Result(); n != 1 {
+		t.Fatalf("expected no low priority job pushed, got %d jobs", n)
+	}
+
+	_, err = p.EnqueueBatch(ctx, make([]queue.Job, 5))
+	if !errors.Is(err, ErrBatchTooLarge) {
+		t.Fatalf("expected ErrBatchTooLarge, got %v", err)
+	}
+}
+
+func TestEnqueueBatchAppliesDefinitionCaps(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	cfg := &config.Config{
+		Worker: config.Worker{Mode: config.ModeList, Queues: map[string]string{
+			"high": "jobqueue:high_priority", "low": "jobqueue:low_priority"}},
+		Producer: config.Producer{DefaultPriority: "low", MaxQueueLength: 10, MaxBatchSize: 10},
+	}
+	ctx := context.Background()
+	def, _ := json.Marshal(queue.Definition{Name: "jobqueue:high_priority", MaxLength: 1})
+	rdb.HSet(ctx, queue.DefinitionsKey, "jobqueue:high_priority", def)
+	p := New(cfg, rdb, zap.NewNop())
+
+	// the high priority definition caps that queue at 1; low keeps the global cap
+	_, err := p.EnqueueBatch(ctx, []queue.Job{{Priority: "low"}, {Priority: "high"}, {Priority: "high"}})
+	if !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("expected ErrQueueFull, got %v", err)
+	}
+	if n, _ := rdb.LLen(ctx, "jobqueue:low_priority").Result(); n != 0 {
+		t.Fatalf("expected nothing pushed, got %d low priority jobs", n)
+	}
+	if _, err := p.EnqueueBatch(ctx, []queue.Job{{Priority: "high"}, {}, {}, {}}); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := rdb.LLen(ctx, "jobqueue:low_priority").Result(); n != 3 {
+		t.Fatalf("expected 3 low priority jobs, got %d", n)
+	}
+}
+
+func TestEnqueueBatchStreamMode(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	cfg := &config.Config{
+		Worker:   config.Worker{Mode: config.ModeStream, Queues: map[string]string{"low": "jobqueue:low_priority"}},
+		Producer: config.Producer{DefaultPriority: "low", MaxBatchSize: 10},
+	}
+	p := New(cfg, rdb, zap.NewNop())
+	ctx := context.Background()
+
+	ids, err := p.EnqueueBatch(ctx, []queue.Job{{ID: "a"}, {ID: "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgs, err := rdb.XRange(ctx, queue.StreamKey("jobqueue:low_priority"), "-", "+").Result()
+	if err != nil || len(msgs) != 2 || len(ids) != 2 {
+		t.Fatalf("expected 2 stream entries and IDs, got %v %v (%v)", msgs, ids, err)
+	}
+	j, _ := queue.UnmarshalJob(msgs[1].Values[queue.StreamField].(string))
+	if j.ID != "b" {
+		t.Fatalf("expected job b last, got %+v", j)
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2025 James Ross
+package producer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/obs"
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrQueueFull is returned by an enqueue onto a queue at
+// producer.max_queue_length, under the reject policy or once a blocked
+// enqueue times out.
+var ErrQueueFull = errors.New("queue is full")
+
+// boundedPushScript LPUSHes the payload only if the list holds fewer than
+// ARGV[2] items. It returns the new length, or minus the current length
+// when the list is full.
+// KEYS[1]=queue, ARGV[1]=payload, ARGV[2]=max length
+var boundedPushScript = redis.NewScript(`
+local n = redis.call('LLEN', KEYS[1])
+if n >= tonumber(ARGV[2]) then
+  return -n
+end
+return redis.call('LPUSH', KEYS[1], ARGV[1])
+`)
+
+// queueLengths remembers, per queue, when it was last seen full, so
+// producers facing a full queue back off without asking Redis each time.
+type queueLengths struct {
+	mu     sync.Mutex
+	fullAt map[string]time.Time
+}
+
+func (q *queueLengths) knownFull(key string, ttl time.Duration) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	at, ok := q.fullAt[key]
+	return ok && time.Since(at) < ttl
+}
+
+func (q *queueLengths) set(key string, full bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !full {
+		delete(q.fullAt, key)
+		return
+	}
+	if q.fullAt == nil {
+		q.fullAt = make(map[string]time.Time)
+	}
+	q.fullAt[key] = time.Now()
+}
+
+// Enqueue adds payload to queue, a priority alias from worker.queues or a
+// queue key, compressing it per producer.compression. A queue at
+// producer.max_queue_length is handled by producer.backpressure.policy.
+func (p *Producer) Enqueue(ctx context.Context, queue, payload string) error {
+	key, err := p.queueKey(queue)
+	if err != nil {
+		return err
+	}
+	if err := p.push(ctx, key, p.compress(payload)); err != nil {
+		return err
+	}
+	obs.JobsProduced.Inc()
+	return nil
+}
+
+// pushBounded is push for a list queue capped at max jobs.
+func (p *Producer) pushBounded(ctx context.Context, key, payload string, max int64) error {
+	bp := p.cfg.Producer.Backpressure
+	var deadline <-chan time.Time
+	blocked := false
+	for {
+		pushed, err := p.tryPush(ctx, key, payload, max)
+		if err != nil {
+			return err
+		}
+		if pushed {
+			p.trackAging(ctx, key, payload)
+			return nil
+		}
+		switch bp.Policy {
+		case config.BackpressureReject:
+			obs.ProducerBackpressure.WithLabelValues(key, "rejected").Inc()
+			return fmt.Errorf("%w: %s has %d jobs", ErrQueueFull, key, max)
+		case config.BackpressureOverflow:
+			overflow, err := p.queueKey(bp.OverflowQueue)
+			if err != nil {
+				return err
+			}
+			obs.ProducerBackpressure.WithLabelValues(key, "overflowed").Inc()
+			p.log.Warn("queue full, enqueued to overflow", obs.String("queue", key), obs.String("overflow", overflow))
+			if err := p.client(overflow).LPush(ctx, overflow, payload).Err(); err != nil {
+				return err
+			}
+			p.trackAging(ctx, overflow, payload)
+			return nil
+		}
+
+		if !blocked {
+			blocked = true
+			obs.ProducerBackpressure.WithLabelValues(key, "blocked").Inc()
+			p.log.Warn("queue full, waiting for space", obs.String("queue", key), obs.String("block_timeout", bp.BlockTimeout.String()))
+			if bp.BlockTimeout > 0 {
+				timer := time.NewTimer(bp.BlockTimeout)
+				defer timer.Stop()
+				deadline = timer.C
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-deadline:
+			obs.ProducerBackpressure.WithLabelValues(key, "timed_out").Inc()
+			return fmt.Errorf("%w: %s still full after %s", ErrQueueFull, key, bp.BlockTimeout)
+		case <-time.After(bp.PollInterval):
+		}
+	}
+}
+
+// tryPush pushes payload unless key is at the cap. A queue seen full
+// within length_cache_ttl is reported full without a round trip.
+func (p *Producer) tryPush(ctx context.Context, key, payload string, max int64) (bool, error) {
+	if p.lengths.knownFull(key, p.cfg.Producer.Backpressure.LengthCacheTTL) {
+		return false, nil
+	}
+	n, err := boundedPushScript.Run(ctx, p.client(key), []string{key}, payload, max).Int64()
+	if err != nil {
+		return false, err
+	}
+	p.lengths.set(key, n < 0)
+	return n > 0, nil
+}
@@ -0,0 +1,143 @@
+// Copyright 2025 James Ross
+package producer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+func newBoundedProducer(t *testing.T, max int64, bp config.Backpressure) (*Producer, *redis.Client) {
+	t.Helper()
+	mr, _ := miniredis.Run()
+	t.Cleanup(mr.Close)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	cfg := &config.Config{
+		Worker:   config.Worker{Mode: config.ModeList, Queues: map[string]string{"high": "jobqueue:high_priority"}},
+		Producer: config.Producer{MaxQueueLength: max, Backpressure: bp},
+	}
+	return New(cfg, rdb, zap.NewNop()), rdb
+}
+
+func TestEnqueueRejectsAtMaxQueueLength(t *testing.T) {
+	p, rdb := newBoundedProducer(t, 5, config.Backpressure{Policy: config.BackpressureReject})
+	ctx := context.Background()
+
+	// Concurrent producers must not overshoot the cap.
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	rejected := 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := p.Enqueue(ctx, "high", `{"id":"x"}`)
+			if err != nil && !errors.Is(err, ErrQueueFull) {
+				t.Error(err)
+			}
+			if err != nil {
+				mu.Lock()
+				rejected++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if n, _ := rdb.LLen(ctx, "jobqueue:high_priority").Result(); n != 5 || rejected != 15 {
+		t.Fatalf("expected 5 queued and 15 rejected, got %d and %d", n, rejected)
+	}
+}
+
+func TestEnqueueOverflowsToOverflowQueue(t *testing.T) {
+	p, rdb := newBoundedProducer(t, 1, config.Backpressure{Policy: config.BackpressureOverflow, OverflowQueue: "jobqueue:overflow"})
+	ctx := context.Background()
+
+	for _, id := range []string{"a", "b"} {
+		if err := p.Enqueue(ctx, "high", `{"id":"`+id+`"}`); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, _ := rdb.LRange(ctx, "jobqueue:overflow", 0, -1).Result(); len(got) != 1 || got[0] != `{"id":"b"}` {
+		t.Fatalf("expected b in the overflow queue, got %v", got)
+	}
+	if n, _ := rdb.LLen(ctx, "jobqueue:high_priority").Result(); n != 1 {
+		t.Fatalf("expected the queue to stay at 1, got %d", n)
+	}
+}
+
+func TestEnqueueBlocksUntilSpaceFrees(t *testing.T) {
+	p, rdb := newBoundedProducer(t, 1, config.Backpressure{
+		Policy:         config.BackpressureBlock,
+		PollInterval:   5 * time.Millisecond,
+		LengthCacheTTL: 10 * time.Millisecond,
+	})
+	ctx := context.Background()
+	if err := p.Enqueue(ctx, "high", `{"id":"a"}`); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- p.Enqueue(ctx, "high", `{"id":"b"}`) }()
+	select {
+	case err := <-done:
+		t.Fatalf("expected the enqueue to block, got %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	rdb.RPop(ctx, "jobqueue:high_priority")
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("enqueue still blocked after space freed")
+	}
+	if got, _ := rdb.LRange(ctx, "jobqueue:high_priority", 0, -1).Result(); len(got) != 1 || got[0] != `{"id":"b"}` {
+		t.Fatalf("expected b queued, got %v", got)
+	}
+}
+
+func TestEnqueueBlockTimesOut(t *testing.T) {
+	p, _ := newBoundedProducer(t, 1, config.Backpressure{
+		Policy:       config.BackpressureBlock,
+		BlockTimeout: 30 * time.Millisecond,
+		PollInterval: 5 * time.Millisecond,
+	})
+	ctx := context.Background()
+	_ = p.Enqueue(ctx, "high", `{"id":"a"}`)
+
+	start := time.Now()
+	if err := p.Enqueue(ctx, "high", `{"id":"b"}`); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("expected ErrQueueFull, got %v", err)
+	}
+	if time.Since(start) < 30*time.Millisecond {
+		t.Fatal("gave up before block_timeout")
+	}
+}
+
+func TestEnqueueTrustsCachedFullLength(t *testing.T) {
+	p, rdb := newBoundedProducer(t, 1, config.Backpressure{Policy: config.BackpressureReject, LengthCacheTTL: time.Hour})
+	ctx := context.Background()
+	_ = p.Enqueue(ctx, "high", `{"id":"a"}`)
+	if err := p.Enqueue(ctx, "high", `{"id":"b"}`); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("expected ErrQueueFull, got %v", err)
+	}
+
+	// Space freed, but the queue was seen full within length_cache_ttl.
+	rdb.RPop(ctx, "jobqueue:high_priority")
+	if err := p.Enqueue(ctx, "high", `{"id":"c"}`); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("expected the cached length to reject, got %v", err)
+	}
+	p.lengths.set("jobqueue:high_priority", false)
+	if err := p.Enqueue(ctx, "high", `{"id":"c"}`); err != nil {
+		t.Fatal(err)
+	}
+}
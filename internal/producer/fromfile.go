@@ -0,0 +1,148 @@
+// Copyright 2025 James Ross
+package producer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// maxLineErrors is how many line errors an EnqueueFromResult keeps; the
+// counts cover the rest.
+const maxLineErrors = 20
+
+// EnqueueFromOptions controls EnqueueFrom.
+type EnqueueFromOptions struct {
+	// Queue is a priority alias from worker.queues or a queue key; empty
+	// means producer.default_priority.
+	Queue string
+	// Rate caps enqueues per second; 0 enqueues as fast as Redis allows.
+	Rate int
+	// Progress, if set, is called with the counts so far every
+	// ProgressEvery (default 5s) and once at the end.
+	Progress      func(EnqueueFromResult)
+	ProgressEvery time.Duration
+}
+
+// LineError is a line EnqueueFrom could not enqueue.
+type LineError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// EnqueueFromResult summarizes an EnqueueFrom run.
+type EnqueueFromResult struct {
+	Queue     string        `json:"queue"`
+	Lines     int           `json:"lines"` // non-blank lines read
+	Enqueued  int           `json:"enqueued"`
+	Malformed int           `json:"malformed"`
+	Rejected  int           `json:"rejected"`
+	Errors    []LineError   `json:"errors,omitempty"`
+	Duration  time.Duration `json:"duration"`
+}
+
+func (r *EnqueueFromResult) addError(line int, err error) {
+	if len(r.Errors) < maxLineErrors {
+		r.Errors = append(r.Errors, LineError{Line: line, Error: err.Error()})
+	}
+}
+
+// EnqueueFrom reads newline-delimited JSON from r and enqueues each line,
+// as it is, to one queue through Enqueue, so compression and backpressure
+// apply as usual. Lines should be job payloads as workers expect them, such
+// as the items admin peek prints. Blank lines are skipped; lines that are
+// not a JSON object are counted as malformed, and lines refused by a full
+// queue as rejected, and the run goes on. Any other enqueue error, a read
+// error or ctx ending stops it; the result then counts what was done.
+func (p *Producer) EnqueueFrom(ctx context.Context, r io.Reader, opts EnqueueFromOptions) (res EnqueueFromResult, err error) {
+	res.Queue = opts.Queue
+	if res.Queue == "" {
+		res.Queue = p.cfg.Producer.DefaultPriority
+	}
+	key, err := p.queueKey(res.Queue)
+	if err != nil {
+		return res, err
+	}
+	res.Queue = key
+
+	var tick <-chan time.Time
+	if opts.Rate > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(opts.Rate))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	every := opts.ProgressEvery
+	if every <= 0 {
+		every = 5 * time.Second
+	}
+	start := time.Now()
+	lastReport := start
+	report := func() {
+		res.Duration = time.Since(start)
+		if opts.Progress != nil {
+			opts.Progress(res)
+		}
+	}
+	defer report()
+
+	br := bufio.NewReader(r)
+	for lineNo := 1; ; lineNo++ {
+		line, readErr := br.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return res, readErr
+		}
+		if payload := bytes.TrimSpace(line); len(payload) > 0 {
+			res.Lines++
+			if err := p.enqueueLine(ctx, key, payload, tick); err != nil {
+				switch {
+				case ctx.Err() != nil:
+					return res, ctx.Err()
+				case errors.Is(err, errMalformedLine):
+					res.Malformed++
+					res.addError(lineNo, err)
+				case errors.Is(err, ErrQueueFull):
+					res.Rejected++
+					res.addError(lineNo, err)
+				default:
+					return res, err
+				}
+			} else {
+				res.Enqueued++
+			}
+			if time.Since(lastReport) >= every {
+				lastReport = time.Now()
+				report()
+			}
+		}
+		if readErr == io.EOF {
+			return res, nil
+		}
+	}
+}
+
+var errMalformedLine = errors.New("not a JSON object")
+
+// enqueueLine checks that payload is a JSON object, waits for tick when
+// rate limited, and enqueues it.
+func (p *Producer) enqueueLine(ctx context.Context, key string, payload []byte, tick <-chan time.Time) error {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &obj); err != nil {
+		return fmt.Errorf("%w: %v", errMalformedLine, err)
+	}
+	if obj == nil {
+		return errMalformedLine
+	}
+	if tick != nil {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-tick:
+		}
+	}
+	return p.Enqueue(ctx, key, string(payload))
+}
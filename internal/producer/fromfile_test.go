@@ -0,0 +1,78 @@
+// Copyright 2025 James Ross
+package producer
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+)
+
+func TestEnqueueFromCountsMalformedLines(t *testing.T) {
+	p, rdb := newBoundedProducer(t, 0, config.Backpressure{})
+	ctx := context.Background()
+	input := strings.Join([]string{
+		`{"id":"a","priority":"high"}`,
+		``,
+		`not json`,
+		`{"id":"b"}`,
+		`[1,2]`,
+		`null`,
+		`  {"id":"c"}  `,
+	}, "\n")
+
+	var reports int
+	res, err := p.EnqueueFrom(ctx, strings.NewReader(input), EnqueueFromOptions{
+		Queue:    "high",
+		Progress: func(EnqueueFromResult) { reports++ },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Queue != "jobqueue:high_priority" || res.Lines != 6 || res.Enqueued != 3 || res.Malformed != 3 || res.Rejected != 0 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if len(res.Errors) != 3 || res.Errors[0].Line != 3 || res.Errors[1].Line != 5 || res.Errors[2].Line != 6 {
+		t.Fatalf("expected errors on lines 3, 5 and 6, got %+v", res.Errors)
+	}
+	if reports != 1 || res.Duration <= 0 {
+		t.Fatalf("expected one final progress report and a duration, got %d and %s", reports, res.Duration)
+	}
+	items, _ := rdb.LRange(ctx, "jobqueue:high_priority", 0, -1).Result()
+	if len(items) != 3 || items[0] != `{"id":"c"}` || items[2] != `{"id":"a","priority":"high"}` {
+		t.Fatalf("expected the lines enqueued as they are, got %v", items)
+	}
+}
+
+func TestEnqueueFromRateAndRejections(t *testing.T) {
+	p, rdb := newBoundedProducer(t, 2, config.Backpressure{Policy: config.BackpressureReject})
+	ctx := context.Background()
+	input := strings.Repeat(`{"id":"x"}`+"\n", 5)
+
+	start := time.Now()
+	res, err := p.EnqueueFrom(ctx, strings.NewReader(input), EnqueueFromOptions{Queue: "jobqueue:high_priority", Rate: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if took := time.Since(start); took < 80*time.Millisecond {
+		t.Fatalf("expected 5 lines at 50/s to take about 100ms, took %s", took)
+	}
+	if res.Enqueued != 2 || res.Rejected != 3 || len(res.Errors) != 3 {
+		t.Fatalf("expected 2 enqueued and 3 rejected by the full queue, got %+v", res)
+	}
+	if n, _ := rdb.LLen(ctx, "jobqueue:high_priority").Result(); n != 2 {
+		t.Fatalf("expected 2 queued, got %d", n)
+	}
+}
+
+func TestEnqueueFromStopsWithContext(t *testing.T) {
+	p, _ := newBoundedProducer(t, 0, config.Backpressure{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	res, err := p.EnqueueFrom(ctx, strings.NewReader(`{"id":"a"}`+"\n"), EnqueueFromOptions{Queue: "high", Rate: 1})
+	if err != context.Canceled || res.Enqueued != 0 {
+		t.Fatalf("expected a canceled run with nothing enqueued, got %+v, %v", res, err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -14,20 +15,39 @@ import (
 	"github.com/flyingrobots/go-redis-work-queue/internal/config"
 	"github.com/flyingrobots/go-redis-work-queue/internal/obs"
 	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/flyingrobots/go-redis-work-queue/internal/redisclient"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
 type Producer struct {
-	cfg *config.Config
-	rdb *redis.Client
-	log *zap.Logger
+	cfg     *config.Config
+	rdb     *redis.Client
+	router  *redisclient.Router
+	log     *zap.Logger
+	lengths queueLengths
+	defs    queue.DefinitionCache
 }
 
 func New(cfg *config.Config, rdb *redis.Client, log *zap.Logger) *Producer {
 	return &Producer{cfg: cfg, rdb: rdb, log: log}
 }
 
+// SetRouter sends each queue's writes to the cluster cluster_routes assigns
+// it; the rate limiter stays on the producer's own client. It must be
+// called before the producer is used.
+func (p *Producer) SetRouter(r *redisclient.Router) {
+	p.router = r
+}
+
+// client returns the client for queue key.
+func (p *Producer) client(key string) *redis.Client {
+	if p.router != nil {
+		return p.router.For(key)
+	}
+	return p.rdb
+}
+
 func (p *Producer) Run(ctx context.Context) error {
 	root := p.cfg.Producer.ScanDir
 	absRoot, errAbs := filepath.Abs(root)
@@ -86,8 +106,12 @@ func (p *Producer) Run(ctx context.Context) error {
 		prio := p.priorityForExt(filepath.Ext(path))
 		id := randID()
 
+		// Each job carries a request ID; one already on ctx is shared by all
+		// jobs from this run.
+		reqCtx, reqID := obs.WithRequestID(ctx)
+
 		// Start enqueue span for tracing
-		enqCtx, enqSpan := obs.StartEnqueueSpan(ctx, p.cfg.Worker.Queues[prio], prio)
+		enqCtx, enqSpan := obs.StartEnqueueSpan(reqCtx, p.cfg.Worker.Queues[prio], prio)
 
 		// Get trace and span IDs from the current context
 		traceID, spanID := obs.GetTraceAndSpanID(enqCtx)
@@ -97,6 +121,7 @@ func (p *Producer) Run(ctx context.Context) error {
 		}
 
 		j := queue.NewJob(id, abs, fi.Size(), prio, traceID, spanID)
+		j.RequestID = reqID
 
 		// Add span attributes
 		obs.AddSpanAttributes(enqCtx,
@@ -104,9 +129,11 @@ func (p *Producer) Run(ctx context.Context) error {
 			obs.KeyValue("job.filepath", abs),
 			obs.KeyValue("job.filesize", fi.Size()),
 			obs.KeyValue("job.priority", prio),
+			obs.KeyValue("job.request_id", reqID),
 		)
 
 		payload, _ := j.Marshal()
+		payload = p.compress(payload)
 		key := p.cfg.Worker.Queues[prio]
 		if key == "" {
 			key = p.cfg.Worker.Queues[p.cfg.Producer.DefaultPriority]
@@ -118,9 +145,14 @@ func (p *Producer) Run(ctx context.Context) error {
 			obs.KeyValue("job_id", j.ID),
 		)
 
-		if err := p.rdb.LPush(enqCtx, key, payload).Err(); err != nil {
+		if err := p.push(enqCtx, key, payload); err != nil {
 			obs.RecordError(enqCtx, err)
 			enqSpan.End()
+			if errors.Is(err, ErrQueueFull) {
+				// skip the file rather than abandon the scan
+				p.log.Warn("job not enqueued", obs.String("id", j.ID), obs.String("path", abs), obs.Err(err), obs.RequestIDField(enqCtx))
+				return nil
+			}
 			return err
 		}
 
@@ -133,7 +165,7 @@ func (p *Producer) Run(ctx context.Context) error {
 		enqSpan.End()
 
 		obs.JobsProduced.Inc()
-		p.log.Info("enqueued job", obs.String("id", j.ID), obs.String("queue", key), obs.String("trace_id", j.TraceID), obs.String("span_id", j.SpanID))
+		p.log.Info("enqueued job", obs.String("id", j.ID), obs.String("queue", key), obs.String("trace_id", j.TraceID), obs.String("span_id", j.SpanID), obs.RequestIDField(enqCtx))
 		return nil
 	})
 	if err != nil {
@@ -142,6 +174,71 @@ func (p *Producer) Run(ctx context.Context) error {
 	return nil
 }
 
+// push adds payload to the queue key, or to its stream in stream mode.
+// List queues are capped at producer.max_queue_length when it is set, or
+// at the max_length of the queue's definition.
+func (p *Producer) push(ctx context.Context, key, payload string) error {
+	if p.cfg.Worker.Mode != config.ModeStream {
+		if max := p.maxQueueLength(ctx, key); max > 0 {
+			return p.pushBounded(ctx, key, payload, max)
+		}
+		if err := p.client(key).LPush(ctx, key, payload).Err(); err != nil {
+			return err
+		}
+		p.trackAging(ctx, key, payload)
+		return nil
+	}
+	stream := queue.StreamKey(key)
+	if err := p.client(key).XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		Values: []interface{}{queue.StreamField, payload},
+	}).Err(); err != nil {
+		return err
+	}
+	p.trimStream(ctx, p.client(key), stream)
+	return nil
+}
+
+// trimStream trims stream towards worker.stream.max_len entries without
+// dropping jobs still waiting or in flight. The jobs are already enqueued,
+// so a failure is only logged.
+func (p *Producer) trimStream(ctx context.Context, rdb *redis.Client, stream string) {
+	if err := queue.TrimStream(ctx, rdb, stream, p.cfg.Worker.Stream.Group, p.cfg.Worker.Stream.MaxLen); err != nil {
+		p.log.Warn("stream trim failed", obs.String("stream", stream), obs.Err(err))
+	}
+}
+
+// maxQueueLength is the cap on key: its definition's max_length, read from
+// the producer's own client every worker.pause_cache_ttl, or else
+// producer.max_queue_length.
+func (p *Producer) maxQueueLength(ctx context.Context, key string) int64 {
+	defs, err := p.defs.Get(ctx, p.rdb, p.cfg.Key(queue.DefinitionsKey), p.cfg.Worker.PauseCacheTTL)
+	if err != nil && ctx.Err() == nil {
+		p.log.Warn("queue definitions read failed", obs.Err(err))
+	}
+	if max := defs[key].MaxLength; max > 0 {
+		return max
+	}
+	return p.cfg.Producer.MaxQueueLength
+}
+
+// compress applies producer.compression to payload and records the ratio
+// achieved. A payload that fails to compress is enqueued as is.
+func (p *Producer) compress(payload string) string {
+	c := p.cfg.Producer.Compression
+	out, err := queue.CompressPayload(payload, c.Codec, c.MinSize)
+	if err != nil {
+		p.log.Warn("payload compression failed", obs.String("codec", c.Codec), obs.Err(err))
+		return payload
+	}
+	if out != payload {
+		ratio := float64(len(out)) / float64(len(payload))
+		obs.PayloadCompressionRatio.WithLabelValues(c.Codec).Observe(ratio)
+		p.log.Debug("compressed payload", obs.String("codec", c.Codec), obs.Int("bytes", len(payload)), obs.Int("compressed_bytes", len(out)), zap.Float64("ratio", ratio))
+	}
+	return out
+}
+
 func (p *Producer) priorityForExt(ext string) string {
 	ext = strings.ToLower(ext)
 	for _, e := range p.cfg.Producer.HighPriorityExts {
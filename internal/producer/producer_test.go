@@ -3,11 +3,16 @@ package producer
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/alicebob/miniredis/v2"
 	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/obs"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/flyingrobots/go-redis-work-queue/internal/redisclient"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
@@ -40,3 +45,146 @@ func TestRateLimit(t *testing.T) {
 		t.Fatalf("expected limiter to sleep when exceeded")
 	}
 }
+
+func TestEnqueueAtAndIn(t *testing.T) {
+	mr, _ := miniredis.Run()
+	defer mr.Close()
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	cfg := &config.Config{Worker: config.Worker{Queues: map[string]string{"high": "jobqueue:high_priority"}}}
+	p := New(cfg, rdb, zap.NewNop())
+	ctx := context.Background()
+
+	runAt := time.Now().Add(time.Hour)
+	if err := p.EnqueueAt(ctx, "high", `{"id":"a"}`, runAt); err != nil {
+		t.Fatal(err)
+	}
+	score, err := rdb.ZScore(ctx, "scheduled:jobqueue:high_priority", `{"id":"a"}`).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int64(score) != runAt.Unix() {
+		t.Fatalf("expected score %d, got %f", runAt.Unix(), score)
+	}
+
+	if err := p.EnqueueIn(ctx, "custom:queue", `{"id":"b"}`, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := rdb.ZCard(ctx, "delayed:custom:queue").Result(); n != 1 {
+		t.Fatalf("expected delayed job under the given key, got %d", n)
+	}
+	if err := p.EnqueueIn(ctx, "high", "x", -time.Second); err == nil {
+		t.Fatal("expected error for negative delay")
+	}
+}
+
+func TestRunStampsRequestID(t *testing.T) {
+	mr, _ := miniredis.Run()
+	defer mr.Close()
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{
+		Worker:   config.Worker{Queues: map[string]string{"low": "jobqueue:low_priority"}},
+		Producer: config.Producer{ScanDir: dir, DefaultPriority: "low"},
+	}
+	p := New(cfg, rdb, zap.NewNop())
+
+	ctx := obs.ContextWithRequestID(context.Background(), "req-1")
+	if err := p.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	payload, err := rdb.RPop(context.Background(), "jobqueue:low_priority").Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	job, err := queue.UnmarshalJob(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.RequestID != "req-1" {
+		t.Fatalf("expected request ID from context, got %q", job.RequestID)
+	}
+
+	if err := p.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	payload, _ = rdb.RPop(context.Background(), "jobqueue:low_priority").Result()
+	if job, _ = queue.UnmarshalJob(payload); job.RequestID == "" {
+		t.Fatal("expected a generated request ID")
+	}
+}
+
+func TestRunStreamModeAddsToStream(t *testing.T) {
+	mr, _ := miniredis.Run()
+	defer mr.Close()
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{
+		Worker: config.Worker{
+			Mode:   config.ModeStream,
+			Queues: map[string]string{"low": "jobqueue:low_priority"},
+			Stream: config.WorkerStream{MaxLen: 100},
+		},
+		Producer: config.Producer{ScanDir: dir, DefaultPriority: "low"},
+	}
+	if err := New(cfg, rdb, zap.NewNop()).Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := rdb.LLen(context.Background(), "jobqueue:low_priority").Result(); n != 0 {
+		t.Fatalf("expected nothing on the list in stream mode, got %d", n)
+	}
+	msgs, err := rdb.XRange(context.Background(), queue.StreamKey("jobqueue:low_priority"), "-", "+").Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 1 {
+		t.Fatalf("expected 1 stream entry, got %d", len(msgs))
+	}
+	payload, _ := msgs[0].Values[queue.StreamField].(string)
+	if job, err := queue.UnmarshalJob(payload); err != nil || job.Priority != "low" {
+		t.Fatalf("unexpected stream payload %q (%v)", payload, err)
+	}
+}
+
+func TestEnqueueFollowsClusterRoutes(t *testing.T) {
+	def, _ := miniredis.Run()
+	defer def.Close()
+	media, _ := miniredis.Run()
+	defer media.Close()
+	cfg := &config.Config{
+		Redis:         config.Redis{Addr: def.Addr()},
+		Clusters:      map[string]config.Redis{"media": {Addr: media.Addr()}},
+		ClusterRoutes: map[string]string{"high": "media"},
+		Worker:        config.Worker{Queues: map[string]string{"high": "jobqueue:high_priority", "low": "jobqueue:low_priority"}},
+	}
+	rdb := redis.NewClient(&redis.Options{Addr: def.Addr()})
+	router, err := redisclient.NewRouter(cfg, rdb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer router.Close()
+	p := New(cfg, rdb, zap.NewNop())
+	p.SetRouter(router)
+	ctx := context.Background()
+
+	if err := p.Enqueue(ctx, "high", `{"id":"a"}`); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Enqueue(ctx, "low", `{"id":"b"}`); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.EnqueueIn(ctx, "high", `{"id":"c"}`, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if !media.Exists("jobqueue:high_priority") || !media.Exists("delayed:jobqueue:high_priority") || def.Exists("jobqueue:high_priority") {
+		t.Fatalf("high should live on the media cluster, have %v and %v", media.Keys(), def.Keys())
+	}
+	if !def.Exists("jobqueue:low_priority") || media.Exists("jobqueue:low_priority") {
+		t.Fatal("low should stay on the default cluster")
+	}
+}
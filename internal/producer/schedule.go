@@ -0,0 +1,61 @@
+// Copyright 2025 James Ross
+package producer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/obs"
+	"github.com/flyingrobots/go-redis-work-queue/internal/scheduler"
+	"github.com/redis/go-redis/v9"
+)
+
+// EnqueueAt schedules payload to join queue at runAt. queue is a priority
+// alias from worker.queues or a queue key. The job waits in the queue's
+// scheduled: sorted set until the scheduler promotes it; a runAt in the past
+// is promoted on the scheduler's next pass. Payloads are sorted set members,
+// so scheduling an identical payload twice only moves its run time.
+func (p *Producer) EnqueueAt(ctx context.Context, queue, payload string, runAt time.Time) error {
+	key, err := p.queueKey(queue)
+	if err != nil {
+		return err
+	}
+	return p.schedule(ctx, p.cfg.DerivedKey(scheduler.ScheduledKey, key), key, payload, runAt)
+}
+
+// EnqueueIn schedules payload to join queue after delay, using the queue's
+// delayed: sorted set. See EnqueueAt.
+func (p *Producer) EnqueueIn(ctx context.Context, queue, payload string, delay time.Duration) error {
+	if delay < 0 {
+		return fmt.Errorf("delay must be >= 0, got %s", delay)
+	}
+	key, err := p.queueKey(queue)
+	if err != nil {
+		return err
+	}
+	return p.schedule(ctx, p.cfg.DerivedKey(scheduler.DelayedKey, key), key, payload, time.Now().Add(delay))
+}
+
+func (p *Producer) schedule(ctx context.Context, zkey, queue, payload string, runAt time.Time) error {
+	payload = p.compress(payload)
+	if err := p.client(queue).ZAdd(ctx, zkey, redis.Z{Score: scheduler.Score(runAt), Member: payload}).Err(); err != nil {
+		return err
+	}
+	obs.JobsProduced.Inc()
+	p.log.Info("scheduled job", obs.String("queue", queue), obs.String("key", zkey), obs.String("run_at", runAt.UTC().Format(time.RFC3339)))
+	return nil
+}
+
+// queueKey resolves a priority alias to its queue key; anything else is
+// taken as a key.
+func (p *Producer) queueKey(queue string) (string, error) {
+	if queue == "" {
+		return "", errors.New("queue is required")
+	}
+	if key, ok := p.cfg.Worker.Queues[queue]; ok && key != "" {
+		return key, nil
+	}
+	return queue, nil
+}
@@ -0,0 +1,134 @@
+// Copyright 2025 James Ross
+package queue
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Payload compression codecs.
+const (
+	CompressionNone = "none"
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+)
+
+// compressedPrefix starts every compressed payload; it is followed by the
+// codec name, a colon and the base64 of the compressed bytes. A JSON job
+// payload can never start with it. Base64 keeps payloads printable, so
+// snapshots, the admin API and logs handle them like any other item.
+const compressedPrefix = "jqz:"
+
+// maxInflatedSize bounds how large a compressed payload may inflate to.
+const maxInflatedSize = 64 << 20
+
+var (
+	zstdOnce    sync.Once
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+	zstdErr     error
+)
+
+func zstdCodec() (*zstd.Encoder, *zstd.Decoder, error) {
+	zstdOnce.Do(func() {
+		zstdEncoder, zstdErr = zstd.NewWriter(nil)
+		if zstdErr == nil {
+			zstdDecoder, zstdErr = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(maxInflatedSize))
+		}
+	})
+	return zstdEncoder, zstdDecoder, zstdErr
+}
+
+// IsCompressed reports whether payload was written by CompressPayload.
+func IsCompressed(payload string) bool {
+	return strings.HasPrefix(payload, compressedPrefix)
+}
+
+// CompressPayload compresses payload with codec when it is at least minSize
+// bytes and compressing makes it smaller; otherwise payload is returned
+// unchanged. An empty codec or "none" never compresses.
+func CompressPayload(payload, codec string, minSize int) (string, error) {
+	if codec == "" || codec == CompressionNone || len(payload) < minSize || IsCompressed(payload) {
+		return payload, nil
+	}
+
+	var raw []byte
+	switch codec {
+	case CompressionGzip:
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write([]byte(payload)); err != nil {
+			return "", err
+		}
+		if err := zw.Close(); err != nil {
+			return "", err
+		}
+		raw = buf.Bytes()
+	case CompressionZstd:
+		enc, _, err := zstdCodec()
+		if err != nil {
+			return "", err
+		}
+		raw = enc.EncodeAll([]byte(payload), nil)
+	default:
+		return "", fmt.Errorf("unknown compression codec %q", codec)
+	}
+
+	out := compressedPrefix + codec + ":" + base64.StdEncoding.EncodeToString(raw)
+	if len(out) >= len(payload) {
+		return payload, nil
+	}
+	return out, nil
+}
+
+// DecompressPayload returns the original payload of a compressed one and
+// any other payload unchanged.
+func DecompressPayload(payload string) (string, error) {
+	if !IsCompressed(payload) {
+		return payload, nil
+	}
+	codec, data, ok := strings.Cut(payload[len(compressedPrefix):], ":")
+	if !ok {
+		return "", fmt.Errorf("malformed compressed payload")
+	}
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return "", fmt.Errorf("compressed payload: %w", err)
+	}
+
+	switch codec {
+	case CompressionGzip:
+		zr, err := gzip.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return "", fmt.Errorf("gzip payload: %w", err)
+		}
+		defer zr.Close()
+		out, err := io.ReadAll(io.LimitReader(zr, maxInflatedSize+1))
+		if err != nil {
+			return "", fmt.Errorf("gzip payload: %w", err)
+		}
+		if len(out) > maxInflatedSize {
+			return "", fmt.Errorf("gzip payload inflates beyond %d bytes", maxInflatedSize)
+		}
+		return string(out), nil
+	case CompressionZstd:
+		_, dec, err := zstdCodec()
+		if err != nil {
+			return "", err
+		}
+		out, err := dec.DecodeAll(raw, nil)
+		if err != nil {
+			return "", fmt.Errorf("zstd payload: %w", err)
+		}
+		return string(out), nil
+	default:
+		return "", fmt.Errorf("unknown compression codec %q", codec)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2025 James Ross
+package queue
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompressPayloadRoundtrip(t *testing.T) {
+	payload := `{"id":"x","filepath":"` + strings.Repeat("/data/file", 200) + `"}`
+	for _, codec := range []string{CompressionGzip, CompressionZstd} {
+		out, err := CompressPayload(payload, codec, 64)
+		if err != nil {
+			t.Fatalf("%s: %v", codec, err)
+		}
+		if !IsCompressed(out) || len(out) >= len(payload) {
+			t.Fatalf("%s: expected a smaller compressed payload, got %d bytes", codec, len(out))
+		}
+		back, err := DecompressPayload(out)
+		if err != nil {
+			t.Fatalf("%s: %v", codec, err)
+		}
+		if back != payload {
+			t.Fatalf("%s: roundtrip mismatch", codec)
+		}
+	}
+}
+
+func TestCompressPayloadPassthrough(t *testing.T) {
+	payload := `{"id":"small"}`
+	for _, tc := range []struct {
+		codec   string
+		minSize int
+	}{
+		{CompressionNone, 0},
+		{"", 0},
+		{CompressionGzip, 4096},
+	} {
+		out, err := CompressPayload(payload, tc.codec, tc.minSize)
+		if err != nil || out != payload {
+			t.Fatalf("codec %q min %d: expected payload unchanged, got %q (%v)", tc.codec, tc.minSize, out, err)
+		}
+	}
+	if _, err := CompressPayload(payload, "lz4", 0); err == nil {
+		t.Fatal("expected error for unknown codec")
+	}
+}
+
+func TestUnmarshalJobCompressed(t *testing.T) {
+	j := NewJob("id", strings.Repeat("/tmp/x", 500), 42, "high", "t", "s")
+	s, _ := j.Marshal()
+	c, err := CompressPayload(s, CompressionZstd, 0)
+	if err != nil || !IsCompressed(c) {
+		t.Fatalf("expected compressed payload: %v", err)
+	}
+	j2, err := UnmarshalJob(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j2.ID != j.ID || j2.FilePath != j.FilePath {
+		t.Fatalf("roundtrip mismatch: %#v vs %#v", j, j2)
+	}
+	if _, err := UnmarshalJob(compressedPrefix + "gzip:not-base64!"); err == nil {
+		t.Fatal("expected error for corrupt compressed payload")
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 James Ross
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefinitionsKey is the hash of queue definitions, by queue name, before
+// any config namespace is applied.
+const DefinitionsKey = "jobqueue:queue_defs"
+
+// Definition declares a queue at runtime, through the admin API, instead of
+// in the config file. Workers poll it in list mode and apply its limits;
+// producers apply MaxLength. A definition for a configured queue key only
+// overrides that queue's settings.
+type Definition struct {
+	// Name is the Redis list key jobs are pushed to.
+	Name string `json:"name"`
+	// Priority is a worker.priorities entry; workers poll the queue right
+	// after that priority's configured queue.
+	Priority string `json:"priority"`
+	// RateLimit caps jobs per second fetched across all workers, 0 for
+	// none. Burst is how many tokens the bucket holds, 0 for one second's
+	// worth.
+	RateLimit float64 `json:"rate_limit,omitempty"`
+	Burst     int     `json:"burst,omitempty"`
+	// MaxLength caps the queue for producers, 0 for producer.max_queue_length.
+	MaxLength int64 `json:"max_length,omitempty"`
+	// DeadLetter overrides worker.max_retries and worker.dead_letter_list.
+	DeadLetter DeadLetterPolicy `json:"dead_letter"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DeadLetterPolicy says when a failing job from a defined queue is dead
+// lettered and where it goes.
+type DeadLetterPolicy struct {
+	MaxRetries *int   `json:"max_retries,omitempty"`
+	List       string `json:"list,omitempty"`
+}
+
+// LoadDefinitions reads every queue definition from the hash at key,
+// DefinitionsKey in the config's namespace. Entries that do not decode are
+// skipped.
+func LoadDefinitions(ctx context.Context, rdb redis.Cmdable, key string) (map[string]Definition, error) {
+	raw, err := rdb.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+	defs := make(map[string]Definition, len(raw))
+	for name, v := range raw {
+		var d Definition
+		if json.Unmarshal([]byte(v), &d) == nil {
+			d.Name = name
+			defs[name] = d
+		}
+	}
+	return defs, nil
+}
+
+// DefinitionCache keeps the result of LoadDefinitions for a while, so
+// looking a queue up does not cost a Redis round trip each time.
+type DefinitionCache struct {
+	mu     sync.Mutex
+	defs   map[string]Definition
+	loaded time.Time
+}
+
+// Get returns the definitions, reloading them when they are older than
+// ttl. If they cannot be reloaded, the last known ones are returned with
+// the error.
+func (c *DefinitionCache) Get(ctx context.Context, rdb redis.Cmdable, key string, ttl time.Duration) (map[string]Definition, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.defs != nil && time.Since(c.loaded) < ttl {
+		return c.defs, nil
+	}
+	c.loaded = time.Now()
+	defs, err := LoadDefinitions(ctx, rdb, key)
+	if err != nil {
+		return c.defs, err
+	}
+	c.defs = defs
+	return defs, nil
+}
@@ -15,6 +15,37 @@ type Job struct {
 	CreationTime string `json:"creation_time"`
 	TraceID      string `json:"trace_id"`
 	SpanID       string `json:"span_id"`
+	// RequestID ties the job to the request that produced it; the worker
+	// restores it into the handler context.
+	RequestID string `json:"request_id,omitempty"`
+	// Type selects the worker handler registered for it; see
+	// worker.Register.
+	Type string `json:"type,omitempty"`
+	// ReclaimCount is how many times the reaper has recovered the job from
+	// a dead worker.
+	ReclaimCount int `json:"reclaim_count,omitempty"`
+	// DedupKey identifies jobs that do the same work. On queues with
+	// coalescing enabled the worker runs one job per key at a time and
+	// completes the others with its result.
+	DedupKey string `json:"dedup_key,omitempty"`
+	// VisibilityDeadline (RFC3339) is stamped on the processing list entry
+	// of a job dequeued from a queue with a visibility timeout: once it has
+	// passed and the worker's heartbeat is gone, the reaper returns the job
+	// to its queue without waiting out the reaper's grace period.
+	VisibilityDeadline string `json:"visibility_deadline,omitempty"`
+	// ParentID is the ID of the job whose handler enqueued this one with
+	// worker.Context.Enqueue.
+	ParentID string `json:"parent_id,omitempty"`
+}
+
+// VisibleAfter reports whether j carries a visibility deadline that has
+// passed at now.
+func (j Job) VisibleAfter(now time.Time) bool {
+	if j.VisibilityDeadline == "" {
+		return false
+	}
+	t, err := time.Parse(time.RFC3339Nano, j.VisibilityDeadline)
+	return err == nil && !now.Before(t)
 }
 
 func NewJob(id, path string, size int64, priority string, traceID, spanID string) Job {
@@ -38,8 +69,14 @@ func (j Job) Marshal() (string, error) {
 	return string(b), nil
 }
 
+// UnmarshalJob decodes a job payload, inflating it first if the producer
+// compressed it.
 func UnmarshalJob(s string) (Job, error) {
 	var j Job
-	err := json.Unmarshal([]byte(s), &j)
+	s, err := DecompressPayload(s)
+	if err != nil {
+		return j, err
+	}
+	err = json.Unmarshal([]byte(s), &j)
 	return j, err
 }
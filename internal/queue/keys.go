@@ -0,0 +1,29 @@
+// Copyright 2025 James Ross
+package queue
+
+// PausedKey is the flag that stops workers from fetching from queueKey.
+// Producers ignore it, so jobs accumulate while the queue is paused.
+func PausedKey(queueKey string) string { return "paused:" + queueKey }
+
+// DoneKey marks a job ID as in progress or completed on queues with
+// completion dedup enabled. Unlike the per-queue keys it embeds no queue
+// key, so callers put it in their config namespace.
+func DoneKey(jobID string) string { return "done:" + jobID }
+
+// RateLimitKey holds the token bucket workers share to enforce
+// worker.queue_rate_limits on queueKey.
+func RateLimitKey(queueKey string) string { return "ratelimit:" + queueKey }
+
+// CoalesceKey records the job running or last completed for dedupKey on
+// queueKey, when the queue has coalescing enabled.
+func CoalesceKey(queueKey, dedupKey string) string {
+	return "coalesce:" + queueKey + ":" + dedupKey
+}
+
+// CadenceKey holds the completion history workers share to notice
+// queueKey going silent, when worker.silence_alert is enabled.
+func CadenceKey(queueKey string) string { return "cadence:" + queueKey }
+
+// AgingKey is the sorted set of jobs on queueKey by enqueue time (Unix
+// milliseconds), kept for queues under producer.priority_aging.
+func AgingKey(queueKey string) string { return "aging:" + queueKey }
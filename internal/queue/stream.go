@@ -0,0 +1,72 @@
+// Copyright 2025 James Ross
+package queue
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// StreamField is the stream entry field holding the job payload.
+const StreamField = "job"
+
+// StreamKey is the Redis Stream used for queueKey when workers run in stream
+// mode. It is a separate key so list and stream deployments of the same
+// queue never collide on key type.
+func StreamKey(queueKey string) string { return queueKey + ":stream" }
+
+// trimScript trims the stream KEYS[1] towards ARGV[2] entries, removing
+// only entries the consumer group ARGV[1] is done with: those older than
+// both its oldest pending entry and its first undelivered one. A plain
+// MAXLEN trim would delete jobs still waiting or in flight, which
+// XAUTOCLAIM then hands out without a payload. A stream whose group does
+// not exist yet is left alone. Returns the number of entries removed.
+var trimScript = redis.NewScript(`
+local function less(a, b)
+  local ams, aseq = string.match(a, '^(%d+)-(%d+)$')
+  local bms, bseq = string.match(b, '^(%d+)-(%d+)$')
+  ams, bms = tonumber(ams), tonumber(bms)
+  return ams < bms or (ams == bms and tonumber(aseq) < tonumber(bseq))
+end
+local function after(id)
+  local ms, seq = string.match(id, '^(%d+)-(%d+)$')
+  return ms .. '-' .. string.format('%d', tonumber(seq) + 1)
+end
+local excess = redis.call('XLEN', KEYS[1]) - tonumber(ARGV[2])
+if excess <= 0 then
+  return 0
+end
+local floor
+for _, group in ipairs(redis.call('XINFO', 'GROUPS', KEYS[1])) do
+  local info = {}
+  for i = 1, #group, 2 do
+    info[group[i]] = group[i + 1]
+  end
+  if info['name'] == ARGV[1] then
+    floor = after(info['last-delivered-id'])
+    local pending = redis.call('XPENDING', KEYS[1], ARGV[1])
+    if pending[1] > 0 and less(pending[2], floor) then
+      floor = pending[2]
+    end
+  end
+end
+if not floor then
+  return 0
+end
+local done = redis.call('XRANGE', KEYS[1], '-', '(' .. floor, 'COUNT', excess)
+if #done == 0 then
+  return 0
+end
+return redis.call('XTRIM', KEYS[1], 'MINID', after(done[#done][1]))
+`)
+
+// TrimStream trims stream towards maxLen entries without removing any that
+// group has yet to deliver or see acked, so a job stalled in the pending
+// entries list keeps the stream above maxLen until it is acked. It does
+// nothing when maxLen is not positive.
+func TrimStream(ctx context.Context, rdb redis.Scripter, stream, group string, maxLen int64) error {
+	if maxLen <= 0 {
+		return nil
+	}
+	return trimScript.Run(ctx, rdb, []string{stream}, group, maxLen).Err()
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 James Ross
+package queue
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestTrimStreamKeepsUnfinishedEntries(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	stream := StreamKey("jobqueue:low_priority")
+	add := func(n int) {
+		for i := 0; i < n; i++ {
+			if err := rdb.XAdd(ctx, &redis.XAddArgs{Stream: stream, Values: []interface{}{StreamField, fmt.Sprint(i)}}).Err(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	add(5)
+
+	// without the group nothing is known to be done
+	if err := TrimStream(ctx, rdb, stream, "workers", 2); err != nil {
+		t.Fatal(err)
+	}
+	if n := rdb.XLen(ctx, stream).Val(); n != 5 {
+		t.Fatalf("trimmed before the group existed: %d left", n)
+	}
+
+	// deliver three, ack only the second and third: the first stays pending
+	if err := rdb.XGroupCreate(ctx, stream, "workers", "0").Err(); err != nil {
+		t.Fatal(err)
+	}
+	got, err := rdb.XReadGroup(ctx, &redis.XReadGroupArgs{Group: "workers", Consumer: "w1", Streams: []string{stream, ">"}, Count: 3}).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgs := got[0].Messages
+	if err := rdb.XAck(ctx, stream, "workers", msgs[1].ID, msgs[2].ID).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := TrimStream(ctx, rdb, stream, "workers", 2); err != nil {
+		t.Fatal(err)
+	}
+	if n := rdb.XLen(ctx, stream).Val(); n != 5 {
+		t.Fatalf("trimmed past the pending entry: %d left", n)
+	}
+
+	// once the stalled entry is acked, done entries go, undelivered ones stay
+	if err := rdb.XAck(ctx, stream, "workers", msgs[0].ID).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := TrimStream(ctx, rdb, stream, "workers", 1); err != nil {
+		t.Fatal(err)
+	}
+	left, err := rdb.XRange(ctx, stream, "-", "+").Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) != 2 || left[0].Values[StreamField] != "3" {
+		t.Fatalf("want the two undelivered entries left, got %v", left)
+	}
+
+	if err := TrimStream(ctx, rdb, stream, "workers", 0); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -209,6 +209,12 @@ func GetEndpointPermissions() map[string][]Permission {
         "GET /api/v1/queues/*/peek": {PermQueueRead},
         "DELETE /api/v1/queues/dlq": {PermQueueDelete},
         "DELETE /api/v1/queues/all": {PermQueueDelete, PermAdminAll}, // Requires admin
+        // Queue definitions
+        "GET /api/v1/queues":        {PermQueueRead},
+        "POST /api/v1/queues":       {PermQueueWrite},
+        "GET /api/v1/queues/*":      {PermQueueRead},
+        "PUT /api/v1/queues/*":      {PermQueueWrite},
+        "DELETE /api/v1/queues/*":   {PermQueueDelete},
         "POST /api/v1/bench":        {PermBenchRun},
         // DLQ list/requeue/purge (selection)
         "GET /api/v1/dlq":          {PermQueueRead},
@@ -233,7 +233,8 @@ func isDestructiveOperation(method, path string) bool {
 		}
 	}
 
-	return false
+	// deleting a queue definition can drop the queue's jobs
+	return method == "DELETE" && strings.HasPrefix(path, "/api/v1/queues/")
 }
 
 func getClientIP(r *http.Request) string {
@@ -4,28 +4,99 @@ package reaper
 import (
 	"context"
 	"fmt"
+	"math"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/flyingrobots/go-redis-work-queue/internal/config"
 	"github.com/flyingrobots/go-redis-work-queue/internal/obs"
 	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/flyingrobots/go-redis-work-queue/internal/scheduler"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
+	"golang.org/x/time/rate"
 )
 
+// recentRetention bounds how far back Stats keeps individual reclaim times.
+const recentRetention = time.Hour
+
 type Reaper struct {
 	cfg *config.Config
 	rdb *redis.Client
 	log *zap.Logger
+
+	mu    sync.Mutex
+	stats Stats
+	// hbExpiry remembers when each live heartbeat was due to expire so a
+	// reclaim can report how long the worker had been dead. Workers without
+	// a processing list are dropped after each full sweep.
+	hbExpiry map[string]time.Time
+	// missingSince records when each processing list was first seen without
+	// a heartbeat; the grace period runs from then.
+	missingSince map[string]time.Time
+	// limiter paces Redis commands; nil without
+	// worker.reaper.max_ops_per_second.
+	limiter *rate.Limiter
+}
+
+// Stats is a snapshot of reaper activity since the reaper was created.
+type Stats struct {
+	StartedAt        time.Time        `json:"started_at"`
+	LastRun          time.Time        `json:"last_run,omitempty"`
+	Runs             int64            `json:"runs"`
+	Reclaimed        int64            `json:"reclaimed"`
+	OrphanReclaimed  int64            `json:"orphan_reclaimed"` // subset of Reclaimed with no heartbeat ever seen
+	BackoffDelayed   int64            `json:"backoff_delayed"`  // subset of Reclaimed parked in delayed:{queue}
+	ReclaimedByQueue map[string]int64 `json:"reclaimed_by_queue"`
+	// RecentReclaims holds reclaim times from the last hour, oldest first.
+	RecentReclaims []time.Time `json:"recent_reclaims,omitempty"`
+}
+
+// ReclaimedWithin counts reclaims in the window ending at now.
+func (s Stats) ReclaimedWithin(now time.Time, window time.Duration) int {
+	cutoff := now.Add(-window)
+	n := 0
+	for i := len(s.RecentReclaims) - 1; i >= 0 && s.RecentReclaims[i].After(cutoff); i-- {
+		n++
+	}
+	return n
 }
 
 func New(cfg *config.Config, rdb *redis.Client, log *zap.Logger) *Reaper {
-	return &Reaper{cfg: cfg, rdb: rdb, log: log}
+	r := &Reaper{
+		cfg:          cfg,
+		rdb:          rdb,
+		log:          log,
+		stats:        Stats{StartedAt: time.Now(), ReclaimedByQueue: map[string]int64{}},
+		hbExpiry:     map[string]time.Time{},
+		missingSince: map[string]time.Time{},
+	}
+	if ops := cfg.Worker.Reaper.MaxOpsPerSecond; ops > 0 {
+		r.limiter = rate.NewLimiter(rate.Limit(ops), int(math.Max(1, math.Ceil(ops))))
+	}
+	return r
+}
+
+// Stats returns a copy of the reaper's counters.
+func (r *Reaper) Stats() Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := r.stats
+	out.ReclaimedByQueue = make(map[string]int64, len(r.stats.ReclaimedByQueue))
+	for q, n := range r.stats.ReclaimedByQueue {
+		out.ReclaimedByQueue[q] = n
+	}
+	out.RecentReclaims = append([]time.Time(nil), r.stats.RecentReclaims...)
+	return out
 }
 
 func (r *Reaper) Run(ctx context.Context) {
-	ticker := time.NewTicker(5 * time.Second)
+	interval := r.cfg.Worker.Reaper.Interval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 	for {
 		select {
@@ -37,59 +108,377 @@ func (r *Reaper) Run(ctx context.Context) {
 	}
 }
 
+// sweep counts what one scanOnce did, for its summary log line.
+type sweep struct {
+	scanned   int // processing lists found
+	lists     int // processing lists reclaimed
+	reclaimed int // jobs moved out of them
+}
+
 func (r *Reaper) scanOnce(ctx context.Context) {
-	// Scan all processing lists
+	start := time.Now()
+	var sw sweep
+	defer func() {
+		r.finishRun()
+		r.logSweep(sw, time.Since(start))
+	}()
+	pattern, prefix, suffix := processingListMatch(r.cfg.Worker.ProcessingListPattern)
+	count := r.cfg.Worker.Reaper.ScanCount
+	if count <= 0 {
+		count = 100
+	}
+	grace := r.cfg.Worker.Reaper.GracePeriod
+	now := time.Now()
+	seen := map[string]struct{}{}
+	seenWorkers := map[string]struct{}{}
+	// Scan all processing lists a page at a time, checking the heartbeats
+	// of each page in one pipelined batch.
 	var cursor uint64
 	for {
-		keys, cur, err := r.rdb.Scan(ctx, cursor, "jobqueue:worker:*:processing", 100).Result()
+		if r.throttle(ctx, 1) != nil {
+			return
+		}
+		keys, cur, err := r.rdb.Scan(ctx, cursor, pattern, count).Result()
 		if err != nil {
 			r.log.Warn("reaper scan error", obs.Err(err))
 			return
 		}
 		cursor = cur
+		var plists, workerIDs []string
 		for _, plist := range keys {
-			// derive worker id
-			// jobqueue:worker:<ID>:processing
-			parts := strings.Split(plist, ":")
-			if len(parts) < 4 {
+			if !strings.HasPrefix(plist, prefix) || !strings.HasSuffix(plist, suffix) || len(plist) <= len(prefix)+len(suffix) {
 				continue
 			}
-			workerID := parts[2]
-			hbKey := fmt.Sprintf(r.cfg.Worker.HeartbeatKeyPattern, workerID)
-			exists, _ := r.rdb.Exists(ctx, hbKey).Result()
-			if exists == 1 {
-				continue
-			} // worker healthy
+			workerID := plist[len(prefix) : len(plist)-len(suffix)]
+			plists = append(plists, plist)
+			workerIDs = append(workerIDs, workerID)
+			seen[plist] = struct{}{}
+			seenWorkers[workerID] = struct{}{}
+		}
+		sw.scanned += len(plists)
+		ttls, err := r.heartbeatTTLs(ctx, workerIDs)
+		if err != nil {
+			return
+		}
 
-			// Requeue all jobs from processing list
-			for {
-				payload, err := r.rdb.RPop(ctx, plist).Result()
-				if err == redis.Nil {
-					break
-				}
-				if err != nil {
-					r.log.Warn("reaper rpop error", obs.Err(err))
-					break
-				}
-				job, err := queue.UnmarshalJob(payload)
-				if err != nil {
-					continue
-				}
-				prio := job.Priority
-				dest := r.cfg.Worker.Queues[prio]
-				if dest == "" {
-					dest = r.cfg.Worker.Queues[r.cfg.Producer.DefaultPriority]
+		for i, plist := range plists {
+			workerID, ttl := workerIDs[i], ttls[i]
+			if ttl == nil {
+				continue
+			}
+			if *ttl != -2 { // worker healthy
+				r.mu.Lock()
+				if *ttl > 0 {
+					r.hbExpiry[workerID] = now.Add(*ttl)
 				}
-				if err := r.rdb.LPush(ctx, dest, payload).Err(); err != nil {
-					r.log.Error("requeue failed", obs.Err(err))
-				} else {
-					obs.ReaperRecovered.Inc()
-					r.log.Warn("requeued abandoned job", obs.String("id", job.ID), obs.String("to", dest), obs.String("trace_id", job.TraceID), obs.String("span_id", job.SpanID))
+				delete(r.missingSince, plist)
+				r.mu.Unlock()
+				continue
+			}
+
+			r.mu.Lock()
+			expiredAt, expiryKnown := r.hbExpiry[workerID]
+			// orphan: no heartbeat was ever seen for this list, so the
+			// worker died before its first heartbeat or the keys drifted
+			// out of sync.
+			orphan := !expiryKnown
+			// A missing heartbeat is not proof the worker is dead: a live
+			// worker deletes it after each job and sets it again only
+			// after dequeuing the next, and an expired one may yet be
+			// refreshed. Whatever the last expiry said, reclaim only once
+			// the list has stayed without a heartbeat for the grace period.
+			first, ok := r.missingSince[plist]
+			if !ok {
+				first = now
+				r.missingSince[plist] = now
+			}
+			if now.Sub(first) < grace {
+				r.mu.Unlock()
+				// Jobs whose own visibility deadline has passed do not wait
+				// for the grace period.
+				if n := r.requeueVisible(ctx, plist, workerID, orphan, expiredAt, now); n > 0 {
+					sw.lists++
+					sw.reclaimed += n
 				}
+				continue
 			}
+			delete(r.missingSince, plist)
+			delete(r.hbExpiry, workerID)
+			r.mu.Unlock()
+
+			sw.lists++
+			sw.reclaimed += r.requeueList(ctx, plist, workerID, orphan, expiredAt)
 		}
 		if cursor == 0 {
 			break
 		}
 	}
+
+	// Forget lists that disappeared on their own, and the heartbeats of
+	// workers with nothing in flight: worker IDs are unique per process, so
+	// those of exited and restarted workers would otherwise pile up.
+	r.mu.Lock()
+	for plist := range r.missingSince {
+		if _, ok := seen[plist]; !ok {
+			delete(r.missingSince, plist)
+		}
+	}
+	for workerID := range r.hbExpiry {
+		if _, ok := seenWorkers[workerID]; !ok {
+			delete(r.hbExpiry, workerID)
+		}
+	}
+	r.mu.Unlock()
+}
+
+// heartbeatTTLs reads the heartbeat TTL of each worker in one pipeline. A
+// worker whose TTL could not be read gets nil and is skipped this sweep.
+func (r *Reaper) heartbeatTTLs(ctx context.Context, workerIDs []string) ([]*time.Duration, error) {
+	if len(workerIDs) == 0 {
+		return nil, nil
+	}
+	if err := r.throttle(ctx, len(workerIDs)); err != nil {
+		return nil, err
+	}
+	cmds := make([]*redis.DurationCmd, len(workerIDs))
+	_, _ = r.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, id := range workerIDs {
+			cmds[i] = pipe.PTTL(ctx, fmt.Sprintf(r.cfg.Worker.HeartbeatKeyPattern, id))
+		}
+		return nil
+	})
+	ttls := make([]*time.Duration, len(workerIDs))
+	for i, cmd := range cmds {
+		ttl, err := cmd.Result()
+		if err != nil {
+			r.log.Warn("reaper heartbeat check error", obs.Err(err), obs.String("worker_id", workerIDs[i]))
+			continue
+		}
+		ttls[i] = &ttl
+	}
+	return ttls, nil
+}
+
+// throttle waits until n more Redis commands fit in
+// worker.reaper.max_ops_per_second. It fails only when ctx is done.
+func (r *Reaper) throttle(ctx context.Context, n int) error {
+	if r.limiter == nil {
+		return ctx.Err()
+	}
+	for n > 0 {
+		k := min(n, r.limiter.Burst())
+		if err := r.limiter.WaitN(ctx, k); err != nil {
+			return err
+		}
+		n -= k
+	}
+	return nil
+}
+
+// logSweep writes the summary of a sweep: at info level when it reclaimed
+// anything, otherwise at debug.
+func (r *Reaper) logSweep(sw sweep, took time.Duration) {
+	level := zap.DebugLevel
+	if sw.lists > 0 {
+		level = zap.InfoLevel
+	}
+	if ce := r.log.Check(level, "reaper sweep"); ce != nil {
+		ce.Write(
+			obs.Int("keys_scanned", sw.scanned),
+			obs.Int("lists_reclaimed", sw.lists),
+			obs.Int("jobs_reclaimed", sw.reclaimed),
+			zap.Duration("duration", took),
+		)
+	}
+}
+
+// requeueList moves every job from a dead worker's processing list back to
+// its priority queue, bumping the job's reclaim count. A job reclaimed more
+// often than worker.reclaim_backoff.threshold goes to delayed:{queue}
+// instead, so a job that crashes its worker cannot take down the fleet one
+// worker at a time. Orphan reclaims are logged as "orphan_reclaimed" and
+// counted separately from heartbeat-expiry reclaims. It returns the number
+// of jobs moved.
+func (r *Reaper) requeueList(ctx context.Context, plist, workerID string, orphan bool, expiredAt time.Time) int {
+	moved := 0
+	for {
+		// one RPOP and one push per job
+		if r.throttle(ctx, 2) != nil {
+			return moved
+		}
+		payload, err := r.rdb.RPop(ctx, plist).Result()
+		if err == redis.Nil {
+			return moved
+		}
+		if err != nil {
+			r.log.Warn("reaper rpop error", obs.Err(err))
+			return moved
+		}
+		if r.requeueJob(ctx, plist, workerID, payload, orphan, expiredAt) {
+			moved++
+		}
+	}
+}
+
+// requeueVisible requeues, as requeueList does, only the jobs in plist
+// whose visibility deadline (see worker.queue_timeouts) has passed at now,
+// leaving the rest for the grace period to decide. It returns the number of
+// jobs moved.
+func (r *Reaper) requeueVisible(ctx context.Context, plist, workerID string, orphan bool, expiredAt, now time.Time) int {
+	if r.throttle(ctx, 1) != nil {
+		return 0
+	}
+	items, err := r.rdb.LRange(ctx, plist, 0, -1).Result()
+	if err != nil {
+		r.log.Warn("reaper lrange error", obs.Err(err))
+		return 0
+	}
+	moved := 0
+	for _, payload := range items {
+		job, err := queue.UnmarshalJob(payload)
+		if err != nil || !job.VisibleAfter(now) {
+			continue
+		}
+		// one LREM and one push per job
+		if r.throttle(ctx, 2) != nil {
+			return moved
+		}
+		// the worker may have finished with it since the LRANGE
+		if n, err := r.rdb.LRem(ctx, plist, 1, payload).Result(); err != nil || n == 0 {
+			continue
+		}
+		if r.requeueJob(ctx, plist, workerID, payload, orphan, expiredAt) {
+			moved++
+		}
+	}
+	return moved
+}
+
+// requeueJob returns payload, already taken off plist, to its queue or
+// delayed set and records the reclaim. It reports whether it did.
+func (r *Reaper) requeueJob(ctx context.Context, plist, workerID, payload string, orphan bool, expiredAt time.Time) bool {
+	job, err := queue.UnmarshalJob(payload)
+	if err != nil {
+		return false
+	}
+	prio := job.Priority
+	dest := r.cfg.Worker.Queues[prio]
+	if dest == "" {
+		dest = r.cfg.Worker.Queues[r.cfg.Producer.DefaultPriority]
+	}
+	job.ReclaimCount++
+	job.VisibilityDeadline = ""
+	if out, err := job.Marshal(); err == nil {
+		if c := r.cfg.Producer.Compression; queue.IsCompressed(payload) {
+			out, _ = queue.CompressPayload(out, c.Codec, c.MinSize)
+		}
+		payload = out
+	}
+	delay := reclaimDelay(r.cfg.Worker.ReclaimBackoff, job.ReclaimCount)
+	if delay > 0 {
+		err = r.rdb.ZAdd(ctx, r.cfg.DerivedKey(scheduler.DelayedKey, dest), redis.Z{Score: scheduler.Score(time.Now().Add(delay)), Member: payload}).Err()
+	} else {
+		err = r.rdb.LPush(ctx, dest, payload).Err()
+	}
+	if err != nil {
+		r.log.Error("requeue failed", obs.Err(err))
+		return false
+	}
+	fields := []zap.Field{
+		obs.String("id", job.ID),
+		obs.String("worker_id", workerID),
+		obs.String("processing_list", plist),
+		obs.String("to", dest),
+		obs.Int("reclaim_count", job.ReclaimCount),
+		obs.String("trace_id", job.TraceID),
+		obs.String("span_id", job.SpanID),
+	}
+	r.recordReclaim(dest)
+	if delay > 0 {
+		r.recordBackoff(dest)
+		fields = append(fields, zap.Duration("delay", delay))
+	}
+	if orphan {
+		r.recordOrphan()
+		r.log.Warn("orphan_reclaimed", append(fields, obs.String("event", "orphan_reclaimed"))...)
+		return true
+	}
+	fields = append(fields, zap.Duration("since_heartbeat_expiry", time.Since(expiredAt)))
+	r.log.Warn("requeued abandoned job", fields...)
+	return true
+}
+
+// reclaimDelay is how long a job on its reclaims-th reclaim waits before it
+// is runnable again: nothing up to the threshold, then base per reclaim,
+// capped at max.
+func reclaimDelay(rb config.ReclaimBackoff, reclaims int) time.Duration {
+	if rb.Base <= 0 || reclaims <= rb.Threshold {
+		return 0
+	}
+	if rb.Max > 0 && time.Duration(reclaims) > rb.Max/rb.Base {
+		return rb.Max
+	}
+	return rb.Base * time.Duration(reclaims)
+}
+
+// processingListMatch turns a processing list pattern such as
+// "jobqueue:worker:%s:processing" into a SCAN match plus the prefix and
+// suffix around the worker ID.
+func processingListMatch(pattern string) (match, prefix, suffix string) {
+	if pattern == "" || !strings.Contains(pattern, "%s") {
+		pattern = "jobqueue:worker:%s:processing"
+	}
+	prefix, suffix, _ = strings.Cut(pattern, "%s")
+	return prefix + "*" + suffix, prefix, suffix
+}
+
+func (r *Reaper) recordReclaim(queueKey string) {
+	obs.ReaperRecovered.Inc()
+	obs.ReaperReclaimed.WithLabelValues(queueKey).Inc()
+
+	now := time.Now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stats.Reclaimed++
+	r.stats.ReclaimedByQueue[queueKey]++
+	r.stats.RecentReclaims = append(r.stats.RecentReclaims, now)
+	r.pruneRecentLocked(now)
+}
+
+func (r *Reaper) recordBackoff(queueKey string) {
+	obs.ReaperBackoffDelayed.WithLabelValues(queueKey).Inc()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stats.BackoffDelayed++
+}
+
+func (r *Reaper) recordOrphan() {
+	obs.ReaperOrphanReclaimed.Inc()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stats.OrphanReclaimed++
+}
+
+func (r *Reaper) finishRun() {
+	now := time.Now()
+	obs.ReaperLastRun.Set(float64(now.Unix()))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stats.Runs++
+	r.stats.LastRun = now
+	r.pruneRecentLocked(now)
+}
+
+func (r *Reaper) pruneRecentLocked(now time.Time) {
+	cutoff := now.Add(-recentRetention)
+	i := 0
+	for i < len(r.stats.RecentReclaims) && !r.stats.RecentReclaims[i].After(cutoff) {
+		i++
+	}
+	if i > 0 {
+		r.stats.RecentReclaims = append(r.stats.RecentReclaims[:0], r.stats.RecentReclaims[i:]...)
+	}
 }
@@ -5,12 +5,15 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/alicebob/miniredis/v2"
 	"github.com/flyingrobots/go-redis-work-queue/internal/config"
 	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/flyingrobots/go-redis-work-queue/internal/scheduler"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestReaperRequeuesWithoutHeartbeat(t *testing.T) {
@@ -22,6 +25,7 @@ func TestReaperRequeuesWithoutHeartbeat(t *testing.T) {
 		t.Fatal(err)
 	}
 	cfg.Redis.Addr = mr.Addr()
+	cfg.Worker.Reaper.GracePeriod = 0 // reclaim heartbeat-less lists on first sight
 	log, _ := zap.NewDevelopment()
 	rep := New(cfg, rdb, log)
 
@@ -47,3 +51,368 @@ func TestReaperRequeuesWithoutHeartbeat(t *testing.T) {
 		t.Fatalf("heartbeat should not exist")
 	}
 }
+
+func TestReaperStatsTrackReclaims(t *testing.T) {
+	mr, _ := miniredis.Run()
+	defer mr.Close()
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	cfg, err := config.Load("nonexistent.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Worker.Reaper.GracePeriod = 0
+	rep := New(cfg, rdb, zap.NewNop())
+
+	ctx := context.Background()
+	plist := fmt.Sprintf(cfg.Worker.ProcessingListPattern, "w2")
+	hbKey := fmt.Sprintf(cfg.Worker.HeartbeatKeyPattern, "w2")
+	for i, prio := range []string{"high", "low", "low"} {
+		job := queue.NewJob(fmt.Sprintf("id%d", i), "/tmp/file.txt", 10, prio, "", "")
+		payload, _ := job.Marshal()
+		if err := rdb.LPush(ctx, plist, payload).Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Live heartbeat: nothing reclaimed, but the run is counted.
+	mr.Set(hbKey, "job")
+	mr.SetTTL(hbKey, 30*time.Second)
+	rep.scanOnce(ctx)
+	if st := rep.Stats(); st.Runs != 1 || st.Reclaimed != 0 {
+		t.Fatalf("expected 1 run and no reclaims, got %+v", st)
+	}
+
+	mr.Del(hbKey)
+	rep.scanOnce(ctx)
+
+	st := rep.Stats()
+	if st.Runs != 2 || st.LastRun.IsZero() {
+		t.Fatalf("expected 2 runs with last run set, got %+v", st)
+	}
+	if st.Reclaimed != 3 {
+		t.Fatalf("expected 3 reclaimed, got %d", st.Reclaimed)
+	}
+	if st.ReclaimedByQueue[cfg.Worker.Queues["low"]] != 2 || st.ReclaimedByQueue[cfg.Worker.Queues["high"]] != 1 {
+		t.Fatalf("unexpected per-queue counts: %v", st.ReclaimedByQueue)
+	}
+	if got := st.ReclaimedWithin(time.Now(), time.Minute); got != 3 {
+		t.Fatalf("expected 3 reclaims in last minute, got %d", got)
+	}
+	if got := st.ReclaimedWithin(time.Now().Add(2*time.Minute), time.Minute); got != 0 {
+		t.Fatalf("expected no reclaims in a later window, got %d", got)
+	}
+
+	// Snapshot must not alias internal state.
+	st.ReclaimedByQueue["bogus"] = 1
+	if _, ok := rep.Stats().ReclaimedByQueue["bogus"]; ok {
+		t.Fatal("Stats returned shared map")
+	}
+}
+
+func TestReaperReclaimsOrphanAfterGracePeriod(t *testing.T) {
+	mr, _ := miniredis.Run()
+	defer mr.Close()
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	cfg, err := config.Load("nonexistent.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A non-default layout must be scanned too.
+	cfg.Worker.ProcessingListPattern = "wq:processing:%s"
+	cfg.Worker.Reaper.GracePeriod = 50 * time.Millisecond
+	rep := New(cfg, rdb, zap.NewNop())
+
+	ctx := context.Background()
+	for _, id := range []string{"dead:1", "live"} {
+		job := queue.NewJob("job-"+id, "/tmp/file.txt", 10, "low", "", "")
+		payload, _ := job.Marshal()
+		if err := rdb.LPush(ctx, fmt.Sprintf(cfg.Worker.ProcessingListPattern, id), payload).Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	liveHB := fmt.Sprintf(cfg.Worker.HeartbeatKeyPattern, "live")
+	mr.Set(liveHB, "job")
+	mr.SetTTL(liveHB, time.Minute)
+
+	// Within the grace period the orphan is left alone.
+	rep.scanOnce(ctx)
+	if n, _ := rdb.LLen(ctx, cfg.Worker.Queues["low"]).Result(); n != 0 {
+		t.Fatalf("orphan reclaimed before grace period, low=%d", n)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	rep.scanOnce(ctx)
+	if n, _ := rdb.LLen(ctx, cfg.Worker.Queues["low"]).Result(); n != 1 {
+		t.Fatalf("expected the orphaned job requeued, low=%d", n)
+	}
+	if mr.Exists(fmt.Sprintf(cfg.Worker.ProcessingListPattern, "dead:1")) {
+		t.Fatal("orphaned processing list should be drained")
+	}
+	if !mr.Exists(fmt.Sprintf(cfg.Worker.ProcessingListPattern, "live")) {
+		t.Fatal("live worker's processing list must not be touched")
+	}
+	if st := rep.Stats(); st.Reclaimed != 1 || st.OrphanReclaimed != 1 {
+		t.Fatalf("expected one orphan reclaim, got %+v", st)
+	}
+}
+
+func TestReaperBacksOffRepeatedlyReclaimedJobs(t *testing.T) {
+	mr, _ := miniredis.Run()
+	defer mr.Close()
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	cfg, err := config.Load("nonexistent.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Worker.Reaper.GracePeriod = 0
+	cfg.Worker.ReclaimBackoff = config.ReclaimBackoff{Threshold: 1, Base: 30 * time.Second, Max: 75 * time.Second}
+	rep := New(cfg, rdb, zap.NewNop())
+
+	ctx := context.Background()
+	low := cfg.Worker.Queues["low"]
+	delayed := scheduler.DelayedKey(low)
+	job := queue.NewJob("poison", "/tmp/file.txt", 10, "low", "", "")
+	payload, _ := job.Marshal()
+
+	// Each round a worker takes the job and dies with it.
+	for round, want := range []time.Duration{0, 60 * time.Second, 75 * time.Second} {
+		plist := fmt.Sprintf(cfg.Worker.ProcessingListPattern, fmt.Sprintf("w%d", round))
+		if err := rdb.LPush(ctx, plist, payload).Err(); err != nil {
+			t.Fatal(err)
+		}
+		before := time.Now()
+		rep.scanOnce(ctx)
+
+		if want == 0 {
+			payload, err = rdb.RPop(ctx, low).Result()
+			if err != nil {
+				t.Fatalf("round %d: expected the job back on the live queue: %v", round, err)
+			}
+		} else {
+			if n, _ := rdb.LLen(ctx, low).Result(); n != 0 {
+				t.Fatalf("round %d: expected the live queue to stay empty, got %d", round, n)
+			}
+			zs, err := rdb.ZPopMin(ctx, delayed).Result()
+			if err != nil || len(zs) != 1 {
+				t.Fatalf("round %d: expected the job in %s: %v", round, delayed, err)
+			}
+			runAt := time.Unix(0, int64(zs[0].Score*1e9))
+			if d := runAt.Sub(before); d < want-time.Second || d > want+time.Second {
+				t.Fatalf("round %d: expected a %v delay, got %v", round, want, d)
+			}
+			payload = zs[0].Member.(string)
+		}
+
+		got, err := queue.UnmarshalJob(payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.ReclaimCount != round+1 {
+			t.Fatalf("round %d: expected reclaim_count %d, got %d", round, round+1, got.ReclaimCount)
+		}
+	}
+
+	if st := rep.Stats(); st.Reclaimed != 3 || st.BackoffDelayed != 2 {
+		t.Fatalf("expected 3 reclaims with 2 delayed, got %+v", st)
+	}
+}
+
+func TestReaperWaitsGracePeriodAfterHeartbeatExpiry(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	cfg, err := config.Load("nonexistent.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Worker.Reaper.GracePeriod = time.Hour
+	observed, logs := observer.New(zap.DebugLevel)
+	rep := New(cfg, rdb, zap.New(observed))
+
+	ctx := context.Background()
+	plist := fmt.Sprintf(cfg.Worker.ProcessingListPattern, "w3")
+	hbKey := fmt.Sprintf(cfg.Worker.HeartbeatKeyPattern, "w3")
+	payload, _ := queue.NewJob("id1", "/tmp/file.txt", 10, "low", "", "").Marshal()
+	rdb.LPush(ctx, plist, payload)
+	mr.Set(hbKey, "job")
+	mr.SetTTL(hbKey, 10*time.Millisecond)
+	rep.scanOnce(ctx)
+
+	time.Sleep(20 * time.Millisecond)
+	mr.Del(hbKey) // expired on schedule
+	rep.scanOnce(ctx)
+	if n, _ := rdb.LLen(ctx, plist).Result(); n != 1 {
+		t.Fatalf("expected the list left alone within the grace period, got length %d", n)
+	}
+
+	cfg.Worker.Reaper.GracePeriod = 10 * time.Millisecond
+	time.Sleep(20 * time.Millisecond)
+	rep.scanOnce(ctx)
+	if n, _ := rdb.LLen(ctx, cfg.Worker.Queues["low"]).Result(); n != 1 {
+		t.Fatalf("expected the job reclaimed after the grace period, low=%d", n)
+	}
+
+	sweeps := logs.FilterMessage("reaper sweep").All()
+	if len(sweeps) != 3 {
+		t.Fatalf("expected a summary per sweep, got %d", len(sweeps))
+	}
+	last := sweeps[2].ContextMap()
+	if sweeps[2].Level != zap.InfoLevel || last["keys_scanned"] != int64(1) || last["lists_reclaimed"] != int64(1) || last["jobs_reclaimed"] != int64(1) {
+		t.Fatalf("unexpected sweep summary %v at %s", last, sweeps[2].Level)
+	}
+}
+
+func TestReaperWaitsWhenHeartbeatIsDeletedBetweenJobs(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	cfg, err := config.Load("nonexistent.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Worker.Reaper.GracePeriod = 50 * time.Millisecond
+	rep := New(cfg, rdb, zap.NewNop())
+
+	ctx := context.Background()
+	plist := fmt.Sprintf(cfg.Worker.ProcessingListPattern, "w5")
+	hbKey := fmt.Sprintf(cfg.Worker.HeartbeatKeyPattern, "w5")
+	payload, _ := queue.NewJob("first", "/tmp/file.txt", 10, "low", "", "").Marshal()
+	rdb.LPush(ctx, plist, payload)
+	mr.Set(hbKey, "job")
+	mr.SetTTL(hbKey, time.Minute)
+	rep.scanOnce(ctx)
+
+	// The worker finishes its job and deletes the heartbeat long before
+	// its recorded expiry, then dequeues the next job and has not yet set
+	// the heartbeat again when the reaper looks.
+	rdb.Del(ctx, plist, hbKey)
+	payload, _ = queue.NewJob("second", "/tmp/file.txt", 10, "low", "", "").Marshal()
+	rdb.LPush(ctx, plist, payload)
+	rep.scanOnce(ctx)
+	if n, _ := rdb.LLen(ctx, plist).Result(); n != 1 {
+		t.Fatalf("a running job was reclaimed before the grace period, processing length %d", n)
+	}
+
+	// The heartbeat comes back, which resets the clock.
+	mr.Set(hbKey, "job")
+	mr.SetTTL(hbKey, time.Minute)
+	rep.scanOnce(ctx)
+	time.Sleep(60 * time.Millisecond)
+	mr.Del(hbKey)
+	rep.scanOnce(ctx)
+	if n, _ := rdb.LLen(ctx, plist).Result(); n != 1 {
+		t.Fatalf("the grace period should restart once the heartbeat is seen again, processing length %d", n)
+	}
+
+	// Gone for the whole grace period, the worker is taken for dead.
+	time.Sleep(60 * time.Millisecond)
+	rep.scanOnce(ctx)
+	if n, _ := rdb.LLen(ctx, cfg.Worker.Queues["low"]).Result(); n != 1 {
+		t.Fatalf("expected the job reclaimed after the grace period, low=%d", n)
+	}
+	if st := rep.Stats(); st.Reclaimed != 1 || st.OrphanReclaimed != 0 {
+		t.Fatalf("unexpected stats %+v", st)
+	}
+}
+
+func TestReaperForgetsWorkersWithNothingInFlight(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	cfg, err := config.Load("nonexistent.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep := New(cfg, rdb, zap.NewNop())
+
+	ctx := context.Background()
+	for _, id := range []string{"exited", "busy"} {
+		payload, _ := queue.NewJob("job-"+id, "/tmp/file.txt", 10, "low", "", "").Marshal()
+		rdb.LPush(ctx, fmt.Sprintf(cfg.Worker.ProcessingListPattern, id), payload)
+		hbKey := fmt.Sprintf(cfg.Worker.HeartbeatKeyPattern, id)
+		mr.Set(hbKey, "job")
+		mr.SetTTL(hbKey, time.Minute)
+	}
+	rep.scanOnce(ctx)
+	if len(rep.hbExpiry) != 2 {
+		t.Fatalf("expected both heartbeats remembered, got %v", rep.hbExpiry)
+	}
+
+	// one worker finishes its job and exits cleanly
+	rdb.Del(ctx, fmt.Sprintf(cfg.Worker.ProcessingListPattern, "exited"), fmt.Sprintf(cfg.Worker.HeartbeatKeyPattern, "exited"))
+	rep.scanOnce(ctx)
+	if _, ok := rep.hbExpiry["exited"]; ok || len(rep.hbExpiry) != 1 {
+		t.Fatalf("expected only the busy worker remembered, got %v", rep.hbExpiry)
+	}
+}
+
+func TestReaperStopsWhenOutOfOpsBudget(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	cfg, err := config.Load("nonexistent.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Worker.Reaper.GracePeriod = 0
+	cfg.Worker.Reaper.MaxOpsPerSecond = 2 // the SCAN and the heartbeat check
+	rep := New(cfg, rdb, zap.NewNop())
+
+	ctx := context.Background()
+	plist := fmt.Sprintf(cfg.Worker.ProcessingListPattern, "w4")
+	for i := 0; i < 3; i++ {
+		payload, _ := queue.NewJob(fmt.Sprintf("id%d", i), "/tmp/file.txt", 10, "low", "", "").Marshal()
+		rdb.LPush(ctx, plist, payload)
+	}
+
+	// the first job needs two more commands, a second away: past the deadline
+	short, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	rep.scanOnce(short)
+	if time.Since(start) > 500*time.Millisecond {
+		t.Fatal("expected the sweep to give up rather than wait past its deadline")
+	}
+	if n, _ := rdb.LLen(ctx, plist).Result(); n != 3 {
+		t.Fatalf("expected no jobs moved over budget, got length %d", n)
+	}
+}
+
+func TestReaperHonorsPerJobVisibilityDeadline(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	cfg, err := config.Load("nonexistent.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Worker.Reaper.GracePeriod = time.Hour
+	rep := New(cfg, rdb, zap.NewNop())
+
+	ctx := context.Background()
+	plist := fmt.Sprintf(cfg.Worker.ProcessingListPattern, "w4")
+	stamp := func(id string, deadline time.Time) {
+		job := queue.NewJob(id, "/tmp/file.txt", 10, "low", "", "")
+		if !deadline.IsZero() {
+			job.VisibilityDeadline = deadline.UTC().Format(time.RFC3339Nano)
+		}
+		payload, _ := job.Marshal()
+		rdb.LPush(ctx, plist, payload)
+	}
+	stamp("due", time.Now().Add(-time.Second))
+	stamp("later", time.Now().Add(time.Hour))
+	stamp("plain", time.Time{})
+
+	// the grace period applies to everything but the job already past its
+	// deadline
+	rep.scanOnce(ctx)
+	items, _ := rdb.LRange(ctx, cfg.Worker.Queues["low"], 0, -1).Result()
+	if len(items) != 1 {
+		t.Fatalf("expected only the due job reclaimed, got %d", len(items))
+	}
+	job, _ := queue.UnmarshalJob(items[0])
+	if job.ID != "due" || job.VisibilityDeadline != "" || job.ReclaimCount != 1 {
+		t.Fatalf("unexpected reclaimed job %+v", job)
+	}
+	if n, _ := rdb.LLen(ctx, plist).Result(); n != 2 {
+		t.Fatalf("expected the other jobs left in processing, got %d", n)
+	}
+	if st := rep.Stats(); st.Reclaimed != 1 || st.OrphanReclaimed != 1 {
+		t.Fatalf("unexpected stats %+v", st)
+	}
+}
@@ -2,7 +2,9 @@
 package redisclient
 
 import (
+	"fmt"
 	"runtime"
+	"sort"
 	"time"
 
 	"github.com/flyingrobots/go-redis-work-queue/internal/config"
@@ -11,21 +13,123 @@ import (
 
 // New returns a configured go-redis v8 client with pooling and retries.
 func New(cfg *config.Config) *redis.Client {
-	poolSize := cfg.Redis.PoolSizeMultiplier * runtime.NumCPU()
+	return newClient(cfg.Redis)
+}
+
+// NewForCluster returns a client for the named cluster in cfg.Clusters, or
+// for the top-level redis section when name is empty or
+// config.DefaultCluster. Pool, timeout and retry settings a cluster leaves
+// unset are taken from the redis section; its address, credentials and DB
+// are its own.
+func NewForCluster(cfg *config.Config, name string) (*redis.Client, error) {
+	if name == "" || name == config.DefaultCluster {
+		return New(cfg), nil
+	}
+	r, ok := cfg.Clusters[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown cluster %q", name)
+	}
+	def := cfg.Redis
+	if r.PoolSizeMultiplier == 0 {
+		r.PoolSizeMultiplier = def.PoolSizeMultiplier
+	}
+	if r.MinIdleConns == 0 {
+		r.MinIdleConns = def.MinIdleConns
+	}
+	if r.DialTimeout == 0 {
+		r.DialTimeout = def.DialTimeout
+	}
+	if r.ReadTimeout == 0 {
+		r.ReadTimeout = def.ReadTimeout
+	}
+	if r.WriteTimeout == 0 {
+		r.WriteTimeout = def.WriteTimeout
+	}
+	if r.MaxRetries == 0 {
+		r.MaxRetries = def.MaxRetries
+	}
+	return newClient(r), nil
+}
+
+func newClient(r config.Redis) *redis.Client {
+	poolSize := r.PoolSizeMultiplier * runtime.NumCPU()
 	if poolSize <= 0 {
 		poolSize = 10 * runtime.NumCPU()
 	}
 	return redis.NewClient(&redis.Options{
-		Addr:            cfg.Redis.Addr,
-		Username:        cfg.Redis.Username,
-		Password:        cfg.Redis.Password,
-		DB:              cfg.Redis.DB,
+		Addr:            r.Addr,
+		Username:        r.Username,
+		Password:        r.Password,
+		DB:              r.DB,
 		PoolSize:        poolSize,
-		MinIdleConns:    cfg.Redis.MinIdleConns,
-		DialTimeout:     cfg.Redis.DialTimeout,
-		ReadTimeout:     cfg.Redis.ReadTimeout,
-		WriteTimeout:    cfg.Redis.WriteTimeout,
-		MaxRetries:      cfg.Redis.MaxRetries,
+		MinIdleConns:    r.MinIdleConns,
+		DialTimeout:     r.DialTimeout,
+		ReadTimeout:     r.ReadTimeout,
+		WriteTimeout:    r.WriteTimeout,
+		MaxRetries:      r.MaxRetries,
 		ConnMaxIdleTime: 5 * time.Minute,
 	})
 }
+
+// Router hands out the client of the cluster each key is routed to by
+// cfg.ClusterRoutes.
+type Router struct {
+	cfg     *config.Config
+	def     *redis.Client
+	clients map[string]*redis.Client // by cluster name, without the default
+}
+
+// NewRouter opens a client for every cluster in cfg.Clusters; def serves
+// the default cluster and stays owned by the caller. With no clusters
+// configured every key maps to def.
+func NewRouter(cfg *config.Config, def *redis.Client) (*Router, error) {
+	r := &Router{cfg: cfg, def: def, clients: make(map[string]*redis.Client, len(cfg.Clusters))}
+	for name := range cfg.Clusters {
+		c, err := NewForCluster(cfg, name)
+		if err != nil {
+			_ = r.Close()
+			return nil, err
+		}
+		r.clients[name] = c
+	}
+	return r, nil
+}
+
+// For returns the client for key.
+func (r *Router) For(key string) *redis.Client {
+	if c, ok := r.clients[r.cfg.ClusterFor(key)]; ok {
+		return c
+	}
+	return r.def
+}
+
+// Default returns the client for the default cluster.
+func (r *Router) Default() *redis.Client {
+	return r.def
+}
+
+// Clients returns the default client followed by one per cluster, in name
+// order, for work that must visit every instance.
+func (r *Router) Clients() []*redis.Client {
+	names := make([]string, 0, len(r.clients))
+	for name := range r.clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	out := []*redis.Client{r.def}
+	for _, name := range names {
+		out = append(out, r.clients[name])
+	}
+	return out
+}
+
+// Close closes the cluster clients the router opened.
+func (r *Router) Close() error {
+	var first error
+	for _, c := range r.clients {
+		if err := c.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
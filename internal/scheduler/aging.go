@@ -0,0 +1,100 @@
+// Copyright 2025 James Ross
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/obs"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/redis/go-redis/v9"
+)
+
+// ageScript promotes up to ARGV[2] jobs enqueued at or before ARGV[1] (Unix
+// ms) according to the aging set KEYS[1]: each is taken off the list
+// KEYS[2] and pushed onto the consuming end of KEYS[3], so it runs next
+// there. Jobs are taken youngest first, so across batches the oldest ends
+// up nearest the consuming end. Workers drop the entries of jobs they
+// dequeue, so the due jobs still queued are the last entries of KEYS[2]:
+// each is looked for only that far from the consuming end, plus ARGV[2]
+// entries for jobs pushed there since, and is dropped when not found. It
+// returns the entries read and the jobs moved.
+var ageScript = redis.NewScript(`
+local aged = redis.call('ZREVRANGEBYSCORE', KEYS[1], ARGV[1], '-inf', 'LIMIT', 0, tonumber(ARGV[2]))
+local window = redis.call('ZCOUNT', KEYS[1], '-inf', ARGV[1]) + tonumber(ARGV[2])
+local moved = 0
+for _, member in ipairs(aged) do
+  redis.call('ZREM', KEYS[1], member)
+  if redis.call('LPOS', KEYS[2], member, 'RANK', -1, 'MAXLEN', window) then
+    redis.call('LREM', KEYS[2], -1, member)
+    redis.call('RPUSH', KEYS[3], member)
+    moved = moved + 1
+  end
+end
+return {#aged, moved}
+`)
+
+// RunAging ages each queue under producer.priority_aging on its own
+// check_interval until ctx is done. It returns at once when none is.
+func (s *Scheduler) RunAging(ctx context.Context) {
+	for prio, a := range s.cfg.Producer.PriorityAging {
+		go s.runAging(ctx, prio, a.CheckInterval)
+	}
+}
+
+func (s *Scheduler) runAging(ctx context.Context, priority string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.PromoteAged(ctx, priority, time.Now()); err != nil && ctx.Err() == nil {
+				s.log.Warn("priority aging error", obs.String("priority", priority), obs.Err(err))
+			}
+		}
+	}
+}
+
+// PromoteAged moves the jobs of priority's queue that were enqueued longer
+// than its producer.priority_aging after ago at now to its promote_to
+// queue, in batches of worker.scheduler_batch, and returns how many moved.
+func (s *Scheduler) PromoteAged(ctx context.Context, priority string, now time.Time) (int, error) {
+	a, ok := s.cfg.Producer.PriorityAging[priority]
+	if !ok {
+		return 0, fmt.Errorf("priority %q is not under producer.priority_aging", priority)
+	}
+	from := s.cfg.Worker.Queues[priority]
+	to := s.cfg.Worker.Queues[s.cfg.Producer.AgingTarget(priority, &s.cfg.Worker)]
+	if from == "" || to == "" {
+		return 0, fmt.Errorf("no queue to age %q from or to", priority)
+	}
+	rdb := s.rdb
+	if s.router != nil {
+		if rdb = s.router.For(from); rdb != s.router.For(to) {
+			return 0, fmt.Errorf("cannot age %s into %s on another redis cluster", from, to)
+		}
+	}
+	batch := s.cfg.Worker.SchedulerBatch
+	if batch <= 0 {
+		batch = 100
+	}
+	cutoff := now.Add(-a.After).UnixMilli()
+	total := 0
+	for {
+		res, err := ageScript.Run(ctx, rdb, []string{s.cfg.DerivedKey(queue.AgingKey, from), from, to}, cutoff, batch).Int64Slice()
+		if err != nil {
+			return total, err
+		}
+		if n := int(res[1]); n > 0 {
+			total += n
+			obs.JobsAged.WithLabelValues(from, to).Add(float64(n))
+			s.log.Debug("promoted aged jobs", obs.String("from", from), obs.String("to", to), obs.Int("count", n))
+		}
+		if res[0] < int64(batch) {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 James Ross
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+func TestPromoteAgedMovesOldJobsToConsumingEnd(t *testing.T) {
+	mr, _ := miniredis.Run()
+	defer mr.Close()
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	cfg, err := config.Load("nonexistent.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Worker.SchedulerBatch = 2 // force more than one batch
+	cfg.Producer.PriorityAging = map[string]config.PriorityAging{"low": {After: time.Minute, CheckInterval: time.Second}}
+	ctx := context.Background()
+	now := time.Now()
+	high := cfg.Worker.Queues["high"]
+	low := cfg.Worker.Queues["low"]
+
+	// low holds, oldest first: old1, old2, fresh; "gone" was consumed
+	// before it aged.
+	rdb.LPush(ctx, low, "old1", "old2", "fresh")
+	rdb.LPush(ctx, high, "h1", "h2")
+	age := func(member string, at time.Time) {
+		rdb.ZAdd(ctx, queue.AgingKey(low), redis.Z{Score: float64(at.UnixMilli()), Member: member})
+	}
+	age("old1", now.Add(-3*time.Minute))
+	age("gone", now.Add(-2*time.Minute))
+	age("old2", now.Add(-90*time.Second))
+	age("fresh", now.Add(-time.Second))
+
+	n, err := New(cfg, rdb, zap.NewNop()).PromoteAged(ctx, "low", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("expected 2 aged jobs promoted, got %d", n)
+	}
+	// Workers pop from the right: aged jobs run next, oldest first.
+	if got, _ := rdb.LRange(ctx, high, 0, -1).Result(); len(got) != 4 || got[2] != "old2" || got[3] != "old1" {
+		t.Fatalf("expected aged jobs at the consuming end of %s, got %v", high, got)
+	}
+	if got, _ := rdb.LRange(ctx, low, 0, -1).Result(); len(got) != 1 || got[0] != "fresh" {
+		t.Fatalf("expected only the fresh job on %s, got %v", low, got)
+	}
+	if left, _ := rdb.ZRange(ctx, queue.AgingKey(low), 0, -1).Result(); len(left) != 1 || left[0] != "fresh" {
+		t.Fatalf("expected only the fresh job still tracked, got %v", left)
+	}
+
+	if _, err := New(cfg, rdb, zap.NewNop()).PromoteAged(ctx, "high", now); err == nil {
+		t.Fatal("expected an error for a priority without aging")
+	}
+}
+
+func TestPromoteAgedLooksOnlyNearTheConsumingEnd(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	cfg, err := config.Load("nonexistent.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Worker.SchedulerBatch = 2
+	cfg.Producer.PriorityAging = map[string]config.PriorityAging{"low": {After: time.Minute, CheckInterval: time.Second}}
+	ctx := context.Background()
+	now := time.Now()
+	low := cfg.Worker.Queues["low"]
+
+	// "old" is due but five untracked jobs were pushed onto the consuming
+	// end after it, more than the one due entry plus a batch the script
+	// looks through.
+	rdb.LPush(ctx, low, "old")
+	rdb.RPush(ctx, low, "u1", "u2", "u3", "u4", "u5")
+	rdb.ZAdd(ctx, queue.AgingKey(low), redis.Z{Score: float64(now.Add(-2 * time.Minute).UnixMilli()), Member: "old"})
+
+	n, err := New(cfg, rdb, zap.NewNop()).PromoteAged(ctx, "low", now)
+	if err != nil || n != 0 {
+		t.Fatalf("expected nothing found near the consuming end, got %d, %v", n, err)
+	}
+	if left, _ := rdb.ZCard(ctx, queue.AgingKey(low)).Result(); left != 0 {
+		t.Fatalf("expected the entry dropped, %d left", left)
+	}
+}
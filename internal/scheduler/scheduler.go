@@ -0,0 +1,164 @@
+// Copyright 2025 James Ross
+package scheduler
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/obs"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/flyingrobots/go-redis-work-queue/internal/redisclient"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// ScheduledKey is the sorted set holding jobs due at an absolute time for
+// queue. Scores are Unix times in seconds; members are job payloads. The
+// JSON Payload Studio writes the same keys.
+func ScheduledKey(queue string) string { return "scheduled:" + queue }
+
+// DelayedKey is the sorted set holding jobs enqueued with a relative delay.
+// It uses the same scoring as ScheduledKey.
+func DelayedKey(queue string) string { return "delayed:" + queue }
+
+// Score converts a run time into a sorted set score.
+func Score(t time.Time) float64 {
+	return float64(t.UnixNano()) / 1e9
+}
+
+// promoteScript moves up to ARGV[2] members scored at or below ARGV[1] from
+// the sorted set KEYS[1] onto the list KEYS[2]. Running it as one script
+// means two schedulers can never promote the same job twice.
+var promoteScript = redis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, tonumber(ARGV[2]))
+for _, member in ipairs(due) do
+  redis.call('ZREM', KEYS[1], member)
+  redis.call('LPUSH', KEYS[2], member)
+end
+return #due
+`)
+
+// promoteStreamScript is promoteScript for stream mode: due members are
+// added to the stream KEYS[2] under field ARGV[3].
+var promoteStreamScript = redis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, tonumber(ARGV[2]))
+for _, member in ipairs(due) do
+  redis.call('ZREM', KEYS[1], member)
+  redis.call('XADD', KEYS[2], '*', ARGV[3], member)
+end
+return #due
+`)
+
+// Scheduler promotes due jobs from the scheduled: and delayed: sorted sets
+// of every configured queue onto the queue itself (its stream in stream
+// mode), where workers pick them up like any other job.
+type Scheduler struct {
+	cfg    *config.Config
+	rdb    *redis.Client
+	router *redisclient.Router
+	log    *zap.Logger
+}
+
+func New(cfg *config.Config, rdb *redis.Client, log *zap.Logger) *Scheduler {
+	return &Scheduler{cfg: cfg, rdb: rdb, log: log}
+}
+
+// SetRouter makes the scheduler promote each queue's jobs on the cluster
+// cluster_routes assigns it, where its producer schedules them. It must be
+// called before Run.
+func (s *Scheduler) SetRouter(r *redisclient.Router) {
+	s.router = r
+}
+
+// Run promotes due jobs every worker.scheduler_interval until ctx is done.
+func (s *Scheduler) Run(ctx context.Context) {
+	interval := s.cfg.Worker.SchedulerInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.PromoteDue(ctx, time.Now()); err != nil && ctx.Err() == nil {
+				s.log.Warn("scheduler promote error", obs.Err(err))
+			}
+		}
+	}
+}
+
+// PromoteDue moves every job due at or before now onto its live queue and
+// returns how many were moved. Each sorted set is drained in batches of
+// worker.scheduler_batch so a large backlog does not block Redis.
+func (s *Scheduler) PromoteDue(ctx context.Context, now time.Time) (int, error) {
+	batch := s.cfg.Worker.SchedulerBatch
+	if batch <= 0 {
+		batch = 100
+	}
+	max := strconv.FormatFloat(Score(now), 'f', -1, 64)
+	total := 0
+	for _, queue := range s.queues() {
+		for _, zkey := range []string{s.cfg.DerivedKey(ScheduledKey, queue), s.cfg.DerivedKey(DelayedKey, queue)} {
+			for {
+				n, err := s.promote(ctx, zkey, queue, max, batch)
+				if err != nil {
+					return total, err
+				}
+				if n > 0 {
+					total += n
+					obs.JobsPromoted.WithLabelValues(queue).Add(float64(n))
+					s.log.Debug("promoted due jobs", obs.String("from", zkey), obs.String("queue", queue), obs.Int("count", n))
+				}
+				if n < batch {
+					break
+				}
+			}
+		}
+	}
+	return total, nil
+}
+
+func (s *Scheduler) promote(ctx context.Context, zkey, queueKey, max string, batch int) (int, error) {
+	if s.cfg.Worker.Mode == config.ModeStream {
+		stream := queue.StreamKey(queueKey)
+		n, err := promoteStreamScript.Run(ctx, s.rdb, []string{zkey, stream}, max, batch, queue.StreamField).Int()
+		if err == nil && n > 0 {
+			// trimming never drops jobs still waiting or in flight
+			if terr := queue.TrimStream(ctx, s.rdb, stream, s.cfg.Worker.Stream.Group, s.cfg.Worker.Stream.MaxLen); terr != nil {
+				s.log.Warn("stream trim failed", obs.String("stream", stream), obs.Err(terr))
+			}
+		}
+		return n, err
+	}
+	rdb := s.rdb
+	if s.router != nil {
+		rdb = s.router.For(queueKey)
+	}
+	return promoteScript.Run(ctx, rdb, []string{zkey, queueKey}, max, batch).Int()
+}
+
+// queues lists each configured queue key once.
+func (s *Scheduler) queues() []string {
+	seen := map[string]struct{}{}
+	var out []string
+	for _, p := range s.cfg.Worker.Priorities {
+		if q := s.cfg.Worker.Queues[p]; q != "" {
+			if _, ok := seen[q]; !ok {
+				seen[q] = struct{}{}
+				out = append(out, q)
+			}
+		}
+	}
+	for _, q := range s.cfg.Worker.Queues {
+		if _, ok := seen[q]; !ok && q != "" {
+			seen[q] = struct{}{}
+			out = append(out, q)
+		}
+	}
+	return out
+}
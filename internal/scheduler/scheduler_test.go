@@ -0,0 +1,87 @@
+// Copyright 2025 James Ross
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+func TestPromoteDueMovesOnlyDueJobs(t *testing.T) {
+	mr, _ := miniredis.Run()
+	defer mr.Close()
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	cfg, err := config.Load("nonexistent.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Worker.SchedulerBatch = 2 // force more than one batch
+	ctx := context.Background()
+	now := time.Now()
+	high := cfg.Worker.Queues["high"]
+	low := cfg.Worker.Queues["low"]
+
+	for _, m := range []string{"a", "b", "c"} {
+		rdb.ZAdd(ctx, ScheduledKey(high), redis.Z{Score: Score(now.Add(-time.Second)), Member: m})
+	}
+	rdb.ZAdd(ctx, ScheduledKey(high), redis.Z{Score: Score(now.Add(time.Hour)), Member: "later"})
+	// The JSON Payload Studio scores in whole seconds.
+	rdb.ZAdd(ctx, DelayedKey(low), redis.Z{Score: float64(now.Add(-time.Minute).Unix()), Member: "studio"})
+
+	n, err := New(cfg, rdb, zap.NewNop()).PromoteDue(ctx, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 4 {
+		t.Fatalf("expected 4 promoted, got %d", n)
+	}
+	if got, _ := rdb.LLen(ctx, high).Result(); got != 3 {
+		t.Fatalf("expected 3 jobs on %s, got %d", high, got)
+	}
+	if got, _ := rdb.LRange(ctx, low, 0, -1).Result(); len(got) != 1 || got[0] != "studio" {
+		t.Fatalf("expected studio job on %s, got %v", low, got)
+	}
+	if left, _ := rdb.ZRange(ctx, ScheduledKey(high), 0, -1).Result(); len(left) != 1 || left[0] != "later" {
+		t.Fatalf("expected only the future job to remain, got %v", left)
+	}
+
+	n, err = New(cfg, rdb, zap.NewNop()).PromoteDue(ctx, now)
+	if err != nil || n != 0 {
+		t.Fatalf("second pass should promote nothing, got %d, %v", n, err)
+	}
+}
+
+func TestPromoteDueStreamMode(t *testing.T) {
+	mr, _ := miniredis.Run()
+	defer mr.Close()
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	cfg, err := config.Load("nonexistent.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Worker.Mode = config.ModeStream
+	ctx := context.Background()
+	now := time.Now()
+	high := cfg.Worker.Queues["high"]
+	rdb.ZAdd(ctx, DelayedKey(high), redis.Z{Score: Score(now.Add(-time.Second)), Member: "due"})
+
+	if n, err := New(cfg, rdb, zap.NewNop()).PromoteDue(ctx, now); err != nil || n != 1 {
+		t.Fatalf("expected 1 promoted, got %d (%v)", n, err)
+	}
+	if n, _ := rdb.LLen(ctx, high).Result(); n != 0 {
+		t.Fatalf("expected nothing on the list in stream mode, got %d", n)
+	}
+	msgs, err := rdb.XRange(ctx, queue.StreamKey(high), "-", "+").Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 1 || msgs[0].Values[queue.StreamField] != "due" {
+		t.Fatalf("expected the due job on the stream, got %v", msgs)
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2025 James Ross
+package themeplayground
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	// minContrastAA is the WCAG AA contrast ratio for normal text.
+	minContrastAA = 4.5
+	// fixLightnessStep is how far SuggestFixes moves HSL lightness per try.
+	fixLightnessStep = 0.01
+	// SuggestFixes keeps lightness within these bounds; beyond them a color
+	// is indistinguishable from black or white and its hue is lost.
+	minFixLightness = 0.05
+	maxFixLightness = 0.95
+)
+
+// contrastPair is a foreground drawn on a background that must stay legible.
+type contrastPair struct {
+	fg, bg *Color
+	name   string
+}
+
+// criticalPairs returns the color pairs CheckAccessibility grades a theme
+// on, pointing into theme so they can be corrected in place.
+func criticalPairs(theme *Theme) []contrastPair {
+	return []contrastPair{
+		{&theme.Palette.TextPrimary, &theme.Palette.Background, "primary_text_background"},
+		{&theme.Palette.TextSecondary, &theme.Palette.Background, "secondary_text_background"},
+		{&theme.Components.Button.Primary.Text, &theme.Components.Button.Primary.Background, "primary_button"},
+		{&theme.Components.Table.HeaderText, &theme.Components.Table.HeaderBackground, "table_header"},
+		{&theme.Components.Input.Text, &theme.Components.Input.Background, "input_field"},
+	}
+}
+
+// SuggestFixes returns a copy of theme in which the foreground of every
+// critical pair below WCAG AA (4.5:1) has had its HSL lightness moved, in 1%
+// steps and in whichever direction needs the smaller change, until the pair
+// passes. Hue and saturation are kept, and lightness stays between 5% and
+// 95%. The messages list each change, and each pair left alone because no
+// lightness of its hue passes. The copy's Accessibility is recomputed;
+// theme itself is not modified.
+func (ac *AccessibilityChecker) SuggestFixes(theme *Theme) (*Theme, []string) {
+	if theme == nil {
+		return nil, nil
+	}
+	fixed := *theme
+	fixed.Components.Chart.DataColors = append([]Color(nil), theme.Components.Chart.DataColors...)
+
+	var changes []string
+	for _, pair := range criticalPairs(&fixed) {
+		ratio, err := ac.colorUtils.ContrastRatio(*pair.fg, *pair.bg)
+		if err != nil {
+			changes = append(changes, fmt.Sprintf("%s: skipped, %v", pair.name, err))
+			continue
+		}
+		if ratio >= minContrastAA {
+			continue
+		}
+		fg, _ := ac.colorUtils.HexToRGB(pair.fg.Hex)
+		bg, _ := ac.colorUtils.HexToRGB(pair.bg.Hex)
+		h, s := hueSat(*fg)
+		rgb, newRatio, ok := ac.fixLightness(h, s, lightness(*fg), *bg)
+		if !ok {
+			changes = append(changes, fmt.Sprintf("%s: no lightness of hue %.0f reaches %.1f:1 on %s (now %.2f:1); left unchanged",
+				pair.name, h, minContrastAA, pair.bg.Hex, ratio))
+			continue
+		}
+		old := pair.fg.Hex
+		hsl, _ := ac.colorUtils.RGBToHSL(rgb)
+		*pair.fg = Color{Hex: rgbHex(rgb), RGB: rgb, HSL: *hsl, Name: pair.fg.Name, Description: pair.fg.Description}
+		changes = append(changes, fmt.Sprintf("%s: %s -> %s (%.2f:1 -> %.2f:1)", pair.name, old, pair.fg.Hex, ratio, newRatio))
+	}
+
+	if info, err := ac.CheckAccessibility(&fixed); err == nil {
+		fixed.Accessibility = *info
+	}
+	return &fixed, changes
+}
+
+// fixLightness walks lightness up and down from l, within the fix bounds,
+// and returns the nearest color of hue h and saturation s that reaches AA
+// against bg.
+func (ac *AccessibilityChecker) fixLightness(h, s, l float64, bg RGB) (RGB, float64, bool) {
+	bgLum := ac.colorUtils.relativeLuminance(bg)
+	var best RGB
+	bestRatio, bestDist := 0.0, math.Inf(1)
+	for _, dir := range []float64{1, -1} {
+		for step := 1; ; step++ {
+			nl := l + dir*float64(step)*fixLightnessStep
+			if nl < minFixLightness-1e-9 || nl > maxFixLightness+1e-9 {
+				break
+			}
+			dist := math.Abs(nl - l)
+			if dist >= bestDist {
+				break
+			}
+			rgb := hslToRGB(h, s, nl)
+			lum := ac.colorUtils.relativeLuminance(rgb)
+			ratio := (math.Max(lum, bgLum) + 0.05) / (math.Min(lum, bgLum) + 0.05)
+			if ratio >= minContrastAA {
+				best, bestRatio, bestDist = rgb, ratio, dist
+				break
+			}
+		}
+	}
+	return best, bestRatio, !math.IsInf(bestDist, 1)
+}
+
+// lightness returns the HSL lightness of c in 0..1.
+func lightness(c RGB) float64 {
+	max := math.Max(float64(c.R), math.Max(float64(c.G), float64(c.B)))
+	min := math.Min(float64(c.R), math.Min(float64(c.G), float64(c.B)))
+	return (max + min) / 2 / 255
+}
+
+// hslToRGB converts hue in degrees and saturation and lightness in 0..1.
+func hslToRGB(h, s, l float64) RGB {
+	c := (1 - math.Abs(2*l-1)) * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := l - c/2
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = c, x, 0
+	case h < 120:
+		r, g, b = x, c, 0
+	case h < 180:
+		r, g, b = 0, c, x
+	case h < 240:
+		r, g, b = 0, x, c
+	case h < 300:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+	to := func(v float64) uint8 { return uint8(math.Round((v + m) * 255)) }
+	return RGB{R: to(r), G: to(g), B: to(b)}
+}
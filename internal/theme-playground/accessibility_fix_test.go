@@ -0,0 +1,81 @@
+// Copyright 2025 James Ross
+package themeplayground
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSuggestFixesRaisesFailingPairsToAA(t *testing.T) {
+	ac := NewAccessibilityChecker()
+	cu := NewColorUtilities()
+	theme := &Theme{
+		Name: "washed-out",
+		Palette: ColorPalette{
+			Background:    Color{Hex: "#ffffff"},
+			TextPrimary:   Color{Hex: "#1a1a1a", Name: "Ink"},
+			TextSecondary: Color{Hex: "#7fa7d9", Name: "Soft Blue"},
+		},
+		Components: ComponentStyles{
+			Button: ButtonStyles{Primary: ButtonVariant{Text: Color{Hex: "#ffe08a"}, Background: Color{Hex: "#1e3a8a"}}},
+			Table:  TableStyles{HeaderText: Color{Hex: "#334155"}, HeaderBackground: Color{Hex: "#475569"}},
+			Input:  InputStyles{Text: Color{Hex: "#000000"}, Background: Color{Hex: "#ffffff"}},
+		},
+	}
+
+	fixed, changes := ac.SuggestFixes(theme)
+	if len(changes) != 2 {
+		t.Fatalf("expected two fixes, got %v", changes)
+	}
+	if theme.Palette.TextSecondary.Hex != "#7fa7d9" {
+		t.Fatal("the original theme must not change")
+	}
+	if fixed.Palette.TextPrimary.Hex != "#1a1a1a" || fixed.Components.Button.Primary.Text.Hex != "#ffe08a" {
+		t.Fatal("passing pairs must be left alone")
+	}
+
+	for _, c := range []struct{ fg, bg Color }{
+		{fixed.Palette.TextSecondary, fixed.Palette.Background},
+		{fixed.Components.Table.HeaderText, fixed.Components.Table.HeaderBackground},
+	} {
+		ratio, _ := cu.ContrastRatio(c.fg, c.bg)
+		if ratio < 4.5 {
+			t.Fatalf("%s on %s is still %.2f:1", c.fg.Hex, c.bg.Hex, ratio)
+		}
+	}
+	// the soft blue is darkened but stays blue and keeps its name
+	fg := fixed.Palette.TextSecondary
+	if fg.Name != "Soft Blue" || fg.HSL.H < 205 || fg.HSL.H > 220 || fg.RGB.B <= fg.RGB.R {
+		t.Fatalf("hue should be kept: %+v", fg)
+	}
+	if !strings.HasPrefix(changes[0], "secondary_text_background: #7fa7d9 -> ") {
+		t.Fatalf("unexpected message: %q", changes[0])
+	}
+	if fixed.Accessibility.WCAGLevel != "AA" && fixed.Accessibility.WCAGLevel != "AAA" {
+		t.Fatalf("fixed theme should pass AA, got %q", fixed.Accessibility.WCAGLevel)
+	}
+}
+
+func TestSuggestFixesReportsUnreachablePairs(t *testing.T) {
+	ac := NewAccessibilityChecker()
+	// mid gray: neither 5% nor 95% lightness gray reaches 4.5:1 on it
+	theme := &Theme{Palette: ColorPalette{
+		Background:    Color{Hex: "#777777"},
+		TextPrimary:   Color{Hex: "#888888"},
+		TextSecondary: Color{Hex: "#000000"},
+	}}
+
+	fixed, changes := ac.SuggestFixes(theme)
+	if fixed.Palette.TextPrimary.Hex != "#888888" {
+		t.Fatalf("unreachable pair should be left unchanged, got %s", fixed.Palette.TextPrimary.Hex)
+	}
+	found := false
+	for _, c := range changes {
+		if strings.HasPrefix(c, "primary_text_background: no lightness") {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected a note for the unreachable pair, got %v", changes)
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2025 James Ross
+package themeplayground
+
+// Default candidates for BestTextColor.
+var (
+	textBlack = Color{Hex: "#000000", Name: "Black"}
+	textWhite = Color{Hex: "#ffffff", Name: "White"}
+)
+
+// BestTextColor returns whichever candidate has the highest contrast ratio
+// against bg, choosing between black and white when none are given. Ties go
+// to the earlier candidate, and candidates that are not valid hex colors
+// are skipped; if none is valid, or bg is not, the first is returned.
+func (cu *ColorUtilities) BestTextColor(bg Color, candidates ...Color) Color {
+	if len(candidates) == 0 {
+		candidates = []Color{textBlack, textWhite}
+	}
+	best, bestRatio := candidates[0], 0.0
+	for _, c := range candidates {
+		if ratio, err := cu.ContrastRatio(c, bg); err == nil && ratio > bestRatio {
+			best, bestRatio = c, ratio
+		}
+	}
+	return best
+}
+
+// legibleText returns the color to draw text on bg with: text itself when
+// it meets WCAG AA against bg, else the most legible of text and the
+// palette's text colors if that does, else black or white. Generated,
+// blended and imported palettes can leave a component's text unreadable on
+// its own background, as a fixed inverse color can be.
+func (tm *ThemeManager) legibleText(theme *Theme, text, bg Color) Color {
+	if ratio, err := tm.colorUtils.ContrastRatio(text, bg); err != nil || ratio >= minContrastAA {
+		return text
+	}
+	p := theme.Palette
+	best := tm.colorUtils.BestTextColor(bg, text, p.TextPrimary, p.TextInverse, p.TextSecondary)
+	if ratio, err := tm.colorUtils.ContrastRatio(best, bg); err == nil && ratio >= minContrastAA {
+		return best
+	}
+	return tm.colorUtils.BestTextColor(bg)
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 James Ross
+package themeplayground
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestBestTextColorAcrossBackgrounds(t *testing.T) {
+	cu := NewColorUtilities()
+	navy := Color{Hex: "#1e3a8a", Name: "Navy"}
+	cream := Color{Hex: "#fff8e7", Name: "Cream"}
+
+	tests := []struct {
+		name       string
+		bg         Color
+		candidates []Color
+		want       string
+	}{
+		{"white background", Color{Hex: "#ffffff"}, nil, "#000000"},
+		{"light yellow background", Color{Hex: "#fde68a"}, nil, "#000000"},
+		{"dark background", Color{Hex: "#1a1b26"}, nil, "#ffffff"},
+		{"saturated blue background", Color{Hex: "#0000ff"}, nil, "#ffffff"},
+		{"mid grey leans black", Color{Hex: "#808080"}, nil, "#000000"},
+		{"light background, palette candidates", Color{Hex: "#f5f5f5"}, []Color{cream, navy}, "#1e3a8a"},
+		{"dark background, palette candidates", Color{Hex: "#0f172a"}, []Color{navy, cream}, "#fff8e7"},
+		{"invalid candidate skipped", Color{Hex: "#0f172a"}, []Color{{Hex: "cream"}, navy}, "#1e3a8a"},
+		{"invalid background keeps first", Color{Hex: "blue"}, []Color{navy, cream}, "#1e3a8a"},
+	}
+	for _, tt := range tests {
+		if got := cu.BestTextColor(tt.bg, tt.candidates...); got.Hex != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.want, got.Hex)
+		}
+	}
+}
+
+func TestGetStyleForReplacesIllegibleText(t *testing.T) {
+	setTerminalEnv(t, "truecolor", "xterm", "")
+	tm := NewThemeManager(t.TempDir())
+	base, err := tm.GetTheme(ThemeDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A blended light palette whose fixed inverse is as pale as the button.
+	theme := *base
+	theme.Name = "blended-light"
+	theme.Palette.Background = Color{Hex: "#fafafa"}
+	theme.Palette.TextPrimary = Color{Hex: "#27272a"}
+	theme.Palette.TextSecondary = Color{Hex: "#71717a"}
+	theme.Palette.TextInverse = Color{Hex: "#f4f4f5"}
+	theme.Components.Button.Primary.Background = Color{Hex: "#fcd34d"}
+	theme.Components.Button.Primary.Text = theme.Palette.TextInverse
+	theme.Components.Input.Background = Color{Hex: "#ffffff"}
+	theme.Components.Input.Text = Color{Hex: "#18181b"}
+	// Nothing in the palette reads on this grey header.
+	theme.Components.Table.HeaderBackground = Color{Hex: "#8b8b8b"}
+	theme.Components.Table.HeaderText = Color{Hex: "#9ca3af"}
+	if err := tm.RegisterTheme(&theme); err != nil {
+		t.Fatal(err)
+	}
+	if err := tm.SetActiveTheme("blended-light"); err != nil {
+		t.Fatal(err)
+	}
+	tm.SetTerminalCapabilities(TerminalCaps{Profile: ProfileTrueColor})
+
+	if got := tm.GetStyleFor("button", "primary").GetForeground(); got != lipgloss.Color("#27272a") {
+		t.Errorf("button: expected the palette's primary text, got %v", got)
+	}
+	if got := tm.GetStyleFor("input", "").GetForeground(); got != lipgloss.Color("#18181b") {
+		t.Errorf("input: expected its legible text color kept, got %v", got)
+	}
+	if got := tm.GetStyleFor("table", "header").GetForeground(); got != lipgloss.Color("#000000") {
+		t.Errorf("table header: expected black, got %v", got)
+	}
+
+	// The same components on a dark palette.
+	theme.Name = "blended-dark"
+	theme.Palette.TextPrimary = Color{Hex: "#3f3f46"}
+	theme.Palette.TextSecondary = Color{Hex: "#52525b"}
+	theme.Palette.TextInverse = Color{Hex: "#e4e4e7"}
+	theme.Components.Button.Primary.Background = Color{Hex: "#1e1b4b"}
+	theme.Components.Button.Primary.Text = Color{Hex: "#312e81"}
+	theme.Components.Table.HeaderBackground = Color{Hex: "#6b7280"}
+	theme.Components.Table.HeaderText = Color{Hex: "#1f2937"}
+	if err := tm.RegisterTheme(&theme); err != nil {
+		t.Fatal(err)
+	}
+	if err := tm.SetActiveTheme("blended-dark"); err != nil {
+		t.Fatal(err)
+	}
+	if got := tm.GetStyleFor("button", "primary").GetForeground(); got != lipgloss.Color("#e4e4e7") {
+		t.Errorf("dark button: expected the palette's inverse text, got %v", got)
+	}
+	if got := tm.GetStyleFor("table", "header").GetForeground(); got != lipgloss.Color("#ffffff") {
+		t.Errorf("dark table header: expected white, got %v", got)
+	}
+}
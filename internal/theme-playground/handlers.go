@@ -227,6 +227,30 @@ func (h *PlaygroundHandler) UpdatePreferences(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	// Style overrides are validated, so apply them first; an empty value
+	// clears an override
+	var warnings []string
+	if raw, ok := updates["overrides"]; ok {
+		overrides, ok := raw.(map[string]interface{})
+		if !ok {
+			http.Error(w, "overrides must be an object of key to hex color", http.StatusBadRequest)
+			return
+		}
+		for key, value := range overrides {
+			hex, _ := value.(string)
+			if hex == "" {
+				h.themeManager.ClearOverride(key)
+				continue
+			}
+			ws, err := h.themeManager.SetOverride(key, hex)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			warnings = append(warnings, ws...)
+		}
+	}
+
 	// Update preferences based on provided fields
 	for key, value := range updates {
 		switch key {
@@ -259,6 +283,9 @@ func (h *PlaygroundHandler) UpdatePreferences(w http.ResponseWriter, r *http.Req
 		"success":     true,
 		"preferences": prefs,
 	}
+	if len(warnings) > 0 {
+		response["warnings"] = warnings
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
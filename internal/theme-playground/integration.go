@@ -112,16 +112,13 @@ func (ti *ThemeIntegration) detectAndAdjustTheme() {
 		return
 	}
 
-	// Check for NO_COLOR environment variable
-	if prefs.RespectNoColor && os.Getenv("NO_COLOR") != "" {
+	// Check terminal color support, including NO_COLOR
+	caps := ti.themeManager.DetectTerminalCapabilities()
+	if caps.NoColor {
 		ti.themeManager.SetActiveTheme(ThemeMonochrome)
 		return
 	}
 
-	// Check terminal color support
-	colorTerm := os.Getenv("COLORTERM")
-	term := os.Getenv("TERM")
-
 	// Prefer high contrast for accessibility if requested
 	if prefs.AccessibilityMode {
 		ti.themeManager.SetActiveTheme(ThemeHighContrast)
@@ -131,14 +128,14 @@ func (ti *ThemeIntegration) detectAndAdjustTheme() {
 	// Detect dark/light mode from terminal or system
 	if ti.isDarkMode() {
 		// Use a dark theme as default
-		if ti.supportsFullColors(colorTerm, term) {
+		if caps.Profile >= ProfileANSI256 {
 			ti.themeManager.SetActiveTheme(ThemeTokyoNight)
 		} else {
 			ti.themeManager.SetActiveTheme(ThemeOneDark)
 		}
 	} else {
 		// Use a light theme as default
-		if ti.supportsFullColors(colorTerm, term) {
+		if caps.Profile >= ProfileANSI256 {
 			ti.themeManager.SetActiveTheme(ThemeGitHub)
 		} else {
 			ti.themeManager.SetActiveTheme(ThemeDefault)
@@ -171,21 +168,6 @@ func (ti *ThemeIntegration) isDarkMode() bool {
 	return colorTerm == "truecolor" || colorTerm == "24bit"
 }
 
-// supportsFullColors checks if the terminal supports full color palette
-func (ti *ThemeIntegration) supportsFullColors(colorTerm, term string) bool {
-	// Check for truecolor support
-	if colorTerm == "truecolor" || colorTerm == "24bit" {
-		return true
-	}
-
-	// Check TERM for 256 color support
-	if strings.Contains(term, "256") || strings.Contains(term, "xterm") {
-		return true
-	}
-
-	return false
-}
-
 // StyleHelper provides convenient methods for common styling operations
 type StyleHelper struct {
 	integration *ThemeIntegration
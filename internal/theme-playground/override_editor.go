@@ -0,0 +1,135 @@
+// Copyright 2025 James Ross
+package themeplayground
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// overrideEditor is the playground view for ThemePreferences.Overrides: a
+// table of every override key with the active theme's color and the
+// override, if any. Enter edits the selected key's hex value, x clears its
+// override, and esc leaves the editor.
+type overrideEditor struct {
+	themeManager *ThemeManager
+	table        table.Model
+	input        textinput.Model
+	editing      bool
+	warnings     []string
+	err          error
+}
+
+func newOverrideEditor(themeManager *ThemeManager, width, height int) *overrideEditor {
+	t := table.New(
+		table.WithColumns([]table.Column{
+			{Title: "Key", Width: 36},
+			{Title: "Theme", Width: 9},
+			{Title: "Override", Width: 9},
+		}),
+		table.WithFocused(true),
+		table.WithHeight(max(5, height-12)),
+		table.WithWidth(max(60, width-4)),
+	)
+	input := textinput.New()
+	input.Placeholder = "#rrggbb"
+	input.CharLimit = 7
+
+	e := &overrideEditor{themeManager: themeManager, table: t, input: input}
+	e.refresh()
+	e.warnings = themeManager.OverrideWarnings()
+	return e
+}
+
+// refresh reloads the rows, keeping the cursor
+func (e *overrideEditor) refresh() {
+	overrides := e.themeManager.Overrides()
+	theme := e.themeManager.GetActiveTheme()
+	keys := OverrideKeys()
+	rows := make([]table.Row, len(keys))
+	for i, key := range keys {
+		base := ""
+		if theme != nil {
+			if c, ok := overrideColor(theme, key); ok {
+				base = c.Hex
+			}
+		}
+		rows[i] = table.Row{key, base, overrides[key]}
+	}
+	e.table.SetRows(rows)
+}
+
+// Update handles a key and reports whether the editor should close
+func (e *overrideEditor) Update(msg tea.KeyMsg) (bool, tea.Cmd) {
+	if e.editing {
+		switch msg.String() {
+		case "esc":
+			e.editing = false
+			e.input.Blur()
+			return false, nil
+		case "enter":
+			e.editing = false
+			e.input.Blur()
+			row := e.table.SelectedRow()
+			if len(row) == 0 {
+				return false, nil
+			}
+			warnings, err := e.themeManager.SetOverride(row[0], strings.TrimSpace(e.input.Value()))
+			e.warnings, e.err = warnings, err
+			e.refresh()
+			return false, nil
+		}
+		var cmd tea.Cmd
+		e.input, cmd = e.input.Update(msg)
+		return false, cmd
+	}
+
+	switch msg.String() {
+	case "esc", "o":
+		return true, nil
+	case "enter":
+		row := e.table.SelectedRow()
+		if len(row) == 0 {
+			return false, nil
+		}
+		value := row[2]
+		if value == "" {
+			value = row[1]
+		}
+		e.input.SetValue(value)
+		e.input.CursorEnd()
+		e.editing = true
+		e.err = nil
+		return false, e.input.Focus()
+	case "x", "delete":
+		if row := e.table.SelectedRow(); len(row) > 0 {
+			e.themeManager.ClearOverride(row[0])
+			e.warnings, e.err = e.themeManager.OverrideWarnings(), nil
+			e.refresh()
+		}
+		return false, nil
+	}
+	var cmd tea.Cmd
+	e.table, cmd = e.table.Update(msg)
+	return false, cmd
+}
+
+// View renders the table, the input while editing, and any warnings
+func (e *overrideEditor) View() string {
+	sections := []string{e.table.View()}
+	if e.editing {
+		sections = append(sections, "Set "+e.table.SelectedRow()[0]+": "+e.input.View()+"  (enter to save, esc to cancel)")
+	} else {
+		sections = append(sections, "enter: edit  x: clear override  esc: back")
+	}
+	if e.err != nil {
+		sections = append(sections, e.themeManager.GetStyleFor("status", "error").Bold(true).Render("Error: "+e.err.Error()))
+	}
+	for _, w := range e.warnings {
+		sections = append(sections, e.themeManager.GetStyleFor("status", "warning").Render("Warning: "+w))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
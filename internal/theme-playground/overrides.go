@@ -0,0 +1,228 @@
+// Copyright 2025 James Ross
+package themeplayground
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// colorType is the type every override key must end at
+var colorType = reflect.TypeOf(Color{})
+
+// OverrideKeys returns every key ThemePreferences.Overrides accepts, sorted.
+// A key is a component and a color property named as in theme JSON, such as
+// "table.selected_row"; components with variants take the variant in
+// between ("button.primary.background"), and "palette.<color>" overrides a
+// palette color.
+func OverrideKeys() []string {
+	var keys []string
+	var walk func(prefix string, t reflect.Type)
+	walk = func(prefix string, t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			key := prefix + jsonName(f)
+			switch {
+			case f.Type == colorType:
+				keys = append(keys, key)
+			case f.Type.Kind() == reflect.Struct:
+				walk(key+".", f.Type)
+			}
+		}
+	}
+	walk("palette.", reflect.TypeOf(ColorPalette{}))
+	walk("", reflect.TypeOf(ComponentStyles{}))
+	sort.Strings(keys)
+	return keys
+}
+
+// overrideColor returns the color of theme that key names
+func overrideColor(theme *Theme, key string) (*Color, bool) {
+	parts := strings.Split(key, ".")
+	v := reflect.ValueOf(&theme.Components).Elem()
+	if parts[0] == "palette" {
+		v, parts = reflect.ValueOf(&theme.Palette).Elem(), parts[1:]
+	}
+	for _, part := range parts {
+		if v.Kind() != reflect.Struct || v.Type() == colorType {
+			return nil, false
+		}
+		next := reflect.Value{}
+		for i := 0; i < v.NumField(); i++ {
+			if jsonName(v.Type().Field(i)) == part {
+				next = v.Field(i)
+				break
+			}
+		}
+		if !next.IsValid() {
+			return nil, false
+		}
+		v = next
+	}
+	if v.Type() != colorType {
+		return nil, false
+	}
+	return v.Addr().Interface().(*Color), true
+}
+
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	return name
+}
+
+// applyOverrides returns a copy of theme with each override's color
+// replaced. Entries with an unknown key or a bad hex value, such as ones
+// edited into the preferences file by hand, are skipped. theme itself is
+// not modified.
+func (tm *ThemeManager) applyOverrides(theme *Theme, overrides map[string]string) *Theme {
+	if theme == nil || len(overrides) == 0 {
+		return theme
+	}
+	out := *theme
+	out.Components.Chart.DataColors = append([]Color(nil), theme.Components.Chart.DataColors...)
+	for key, hex := range overrides {
+		c, ok := overrideColor(&out, key)
+		if !ok {
+			continue
+		}
+		rgb, err := tm.colorUtils.HexToRGB(hex)
+		if err != nil {
+			continue
+		}
+		hsl, _ := tm.colorUtils.RGBToHSL(*rgb)
+		*c = Color{Hex: strings.ToLower(hex), RGB: *rgb, HSL: *hsl, Name: c.Name, Description: c.Description}
+	}
+	return &out
+}
+
+// Overrides returns a copy of the style overrides in the preferences
+func (tm *ThemeManager) Overrides() map[string]string {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	out := make(map[string]string)
+	if tm.preferences != nil {
+		for k, v := range tm.preferences.Overrides {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// SetOverride sets the color that key (see OverrideKeys) is drawn in,
+// whatever the active theme, saves the preferences and notifies theme
+// change callbacks. hex must be #rrggbb. The returned warnings list the
+// contrast pairs drawn with the color that fall below WCAG AA (4.5:1) in
+// the active theme once overridden; the override is kept regardless.
+func (tm *ThemeManager) SetOverride(key, hex string) ([]string, error) {
+	if _, ok := overrideColor(&Theme{}, key); !ok {
+		return nil, ErrOverrideInvalid.WithDetails(key)
+	}
+	if !isValidHexColor(hex) {
+		return nil, ErrColorInvalid.WithDetails(fmt.Sprintf("invalid hex format: %s", hex))
+	}
+
+	tm.mu.Lock()
+	if tm.preferences.Overrides == nil {
+		tm.preferences.Overrides = make(map[string]string)
+	}
+	tm.preferences.Overrides[key] = strings.ToLower(hex)
+	tm.preferences.UpdatedAt = time.Now()
+	tm.savePreferences()
+	warnings := tm.overrideWarnings(key)
+	theme, callbacks := tm.activeTheme, tm.callbacks
+	tm.mu.Unlock()
+
+	for _, callback := range callbacks {
+		callback(theme)
+	}
+	return warnings, nil
+}
+
+// ClearOverride removes the override of key, if any, saves the preferences
+// and notifies theme change callbacks.
+func (tm *ThemeManager) ClearOverride(key string) {
+	tm.mu.Lock()
+	if _, ok := tm.preferences.Overrides[key]; !ok {
+		tm.mu.Unlock()
+		return
+	}
+	delete(tm.preferences.Overrides, key)
+	tm.preferences.UpdatedAt = time.Now()
+	tm.savePreferences()
+	theme, callbacks := tm.activeTheme, tm.callbacks
+	tm.mu.Unlock()
+
+	for _, callback := range callbacks {
+		callback(theme)
+	}
+}
+
+// OverrideWarnings re-checks the contrast of every pair drawn with an
+// overridden color in the active theme, for example after switching
+// themes, and describes those below WCAG AA.
+func (tm *ThemeManager) OverrideWarnings() []string {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	var warnings []string
+	keys := make([]string, 0, len(tm.preferences.Overrides))
+	for key := range tm.preferences.Overrides {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		warnings = append(warnings, tm.overrideWarnings(key)...)
+	}
+	return warnings
+}
+
+// overrideWarnings checks the pairs drawn with key's color in the active
+// theme with overrides applied. Callers hold tm.mu.
+func (tm *ThemeManager) overrideWarnings(key string) []string {
+	theme := tm.applyOverrides(tm.activeTheme, tm.preferences.Overrides)
+	if theme == nil {
+		return nil
+	}
+	c, ok := overrideColor(theme, key)
+	if !ok {
+		return nil
+	}
+	var warnings []string
+	for _, pair := range stylePairs(theme) {
+		if pair.fg != c && pair.bg != c {
+			continue
+		}
+		ratio, err := tm.colorUtils.ContrastRatio(*pair.fg, *pair.bg)
+		if err != nil || ratio >= minContrastAA {
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("%s: low contrast in %s with %s on %s: %.2f:1 (AA needs %.1f:1)",
+			key, pair.name, pair.fg.Hex, pair.bg.Hex, ratio, minContrastAA))
+	}
+	return warnings
+}
+
+// stylePairs returns criticalPairs plus every other text and background
+// pair GetStyleFor draws, so overriding any of them is re-checked.
+func stylePairs(theme *Theme) []contrastPair {
+	c := &theme.Components
+	pairs := criticalPairs(theme)
+	pairs = append(pairs,
+		contrastPair{&c.Button.Secondary.Text, &c.Button.Secondary.Background, "secondary_button"},
+		contrastPair{&c.Button.Danger.Text, &c.Button.Danger.Background, "danger_button"},
+		contrastPair{&c.Button.Success.Text, &c.Button.Success.Background, "success_button"},
+		contrastPair{&c.Button.Ghost.Text, &c.Button.Ghost.Background, "ghost_button"},
+	)
+	return append(pairs,
+		contrastPair{&c.Table.RowText, &c.Table.RowBackground, "table_row"},
+		contrastPair{&c.Table.RowText, &c.Table.RowBackgroundAlt, "table_row_alt"},
+		contrastPair{&c.Table.RowText, &c.Table.SelectedRow, "table_selected_row"},
+		contrastPair{&c.Navigation.Text, &c.Navigation.Background, "navigation"},
+		contrastPair{&c.Navigation.TextActive, &c.Navigation.Background, "navigation_active"},
+		contrastPair{&c.Navigation.TextHover, &c.Navigation.Background, "navigation_hover"},
+		contrastPair{&c.Notification.Text, &c.Notification.Background, "notification"},
+	)
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 James Ross
+package themeplayground
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestOverrideKeys(t *testing.T) {
+	keys := OverrideKeys()
+	want := map[string]bool{"table.selected_row": false, "button.primary.background": false, "palette.background": false}
+	theme := NewThemeManager(t.TempDir()).GetActiveTheme()
+	for _, key := range keys {
+		if _, ok := want[key]; ok {
+			want[key] = true
+		}
+		if _, ok := overrideColor(theme, key); !ok {
+			t.Errorf("key %s does not resolve to a color", key)
+		}
+	}
+	for key, found := range want {
+		if !found {
+			t.Errorf("expected %s among the override keys", key)
+		}
+	}
+	for _, key := range []string{"table", "table.cell_padding", "button.primary", "palette", "table.selected_row.hex", "nope.background"} {
+		if _, ok := overrideColor(theme, key); ok {
+			t.Errorf("expected %q to be rejected", key)
+		}
+	}
+}
+
+func TestSetOverrideAppliesAndPersists(t *testing.T) {
+	setTerminalEnv(t, "truecolor", "xterm-256color", "")
+	dir := t.TempDir()
+	tm := NewThemeManager(dir)
+	base := tm.GetActiveTheme().Components.Table.SelectedRow.Hex
+
+	notified := 0
+	tm.OnThemeChange(func(*Theme) { notified++ })
+	if _, err := tm.SetOverride("table.selected_row", "#1E3A5F"); err != nil {
+		t.Fatalf("SetOverride: %v", err)
+	}
+	if notified != 1 {
+		t.Errorf("expected theme change callbacks to run once, ran %d times", notified)
+	}
+	if bg := tm.GetStyleFor("table", "selected").GetBackground(); bg != lipgloss.Color("#1e3a5f") {
+		t.Errorf("expected the overridden background, got %v", bg)
+	}
+	if bg := tm.GetStyleFor("table", "row").GetBackground(); bg == lipgloss.Color("#1e3a5f") {
+		t.Error("override leaked into other table variants")
+	}
+	if got := tm.GetActiveTheme().Components.Table.SelectedRow.Hex; got != base {
+		t.Errorf("the registered theme was modified: %s -> %s", base, got)
+	}
+
+	// overrides follow the user across themes and restarts
+	if err := tm.SetActiveTheme(ThemeTokyoNight); err != nil {
+		t.Fatal(err)
+	}
+	reloaded := NewThemeManager(dir)
+	if got := reloaded.Overrides()["table.selected_row"]; got != "#1e3a5f" {
+		t.Fatalf("expected the override to be persisted, got %q", got)
+	}
+	if bg := reloaded.GetStyleFor("table", "selected").GetBackground(); bg != lipgloss.Color("#1e3a5f") {
+		t.Errorf("expected the persisted override to apply, got %v", bg)
+	}
+
+	reloaded.ClearOverride("table.selected_row")
+	want := reloaded.GetActiveTheme().Components.Table.SelectedRow.Hex
+	if bg := reloaded.GetStyleFor("table", "selected").GetBackground(); bg != lipgloss.Color(want) {
+		t.Errorf("expected the theme's color back after clearing, got %v", bg)
+	}
+}
+
+func TestSetOverrideValidates(t *testing.T) {
+	tm := NewThemeManager(t.TempDir())
+	if _, err := tm.SetOverride("table.nope", "#ffffff"); err == nil || err.(*ThemeError).Code != ErrOverrideInvalid.Code {
+		t.Errorf("expected an unknown key to be rejected, got %v", err)
+	}
+	for _, hex := range []string{"red", "#fff", "#gggggg", "ffffff"} {
+		if _, err := tm.SetOverride("table.selected_row", hex); err == nil || err.(*ThemeError).Code != ErrColorInvalid.Code {
+			t.Errorf("expected %q to be rejected, got %v", hex, err)
+		}
+	}
+	if len(tm.Overrides()) != 0 {
+		t.Errorf("rejected overrides were stored: %v", tm.Overrides())
+	}
+}
+
+func TestSetOverrideWarnsOnLowContrast(t *testing.T) {
+	tm := NewThemeManager(t.TempDir())
+	rowText := tm.GetActiveTheme().Components.Table.RowText.Hex
+
+	warnings, err := tm.SetOverride("table.selected_row", rowText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "table_selected_row") {
+		t.Fatalf("expected a contrast warning for the selected row, got %v", warnings)
+	}
+	if got := tm.Overrides()["table.selected_row"]; got != strings.ToLower(rowText) {
+		t.Errorf("expected the override to be kept despite the warning, got %q", got)
+	}
+	if ws := tm.OverrideWarnings(); len(ws) != 1 {
+		t.Errorf("expected OverrideWarnings to repeat the warning, got %v", ws)
+	}
+}
@@ -19,6 +19,7 @@ type PlaygroundModel struct {
 	selectedTheme   string
 	previewMode     bool
 	showHelp        bool
+	overrides       *overrideEditor
 	table          table.Model
 	help           help.Model
 	width          int
@@ -35,6 +36,7 @@ type PlaygroundKeyMap struct {
 	Preview     key.Binding
 	Apply       key.Binding
 	Reset       key.Binding
+	Overrides   key.Binding
 	ToggleHelp  key.Binding
 	Quit        key.Binding
 }
@@ -66,6 +68,10 @@ func DefaultKeyMap() PlaygroundKeyMap {
 			key.WithKeys("r"),
 			key.WithHelp("r", "reset to default"),
 		),
+		Overrides: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "edit style overrides"),
+		),
 		ToggleHelp: key.NewBinding(
 			key.WithKeys("?"),
 			key.WithHelp("?", "toggle help"),
@@ -86,7 +92,7 @@ func (k PlaygroundKeyMap) ShortHelp() []key.Binding {
 func (k PlaygroundKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down, k.Enter},
-		{k.Preview, k.Apply, k.Reset},
+		{k.Preview, k.Apply, k.Reset, k.Overrides},
 		{k.ToggleHelp, k.Quit},
 	}
 }
@@ -174,6 +180,13 @@ func (m *PlaygroundModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.help.Width = msg.Width
 
 	case tea.KeyMsg:
+		if m.overrides != nil {
+			done, cmd := m.overrides.Update(msg)
+			if done {
+				m.overrides = nil
+			}
+			return m, cmd
+		}
 		if m.table.Focused() {
 			switch {
 			case key.Matches(msg, DefaultKeyMap().Quit):
@@ -182,6 +195,10 @@ func (m *PlaygroundModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case key.Matches(msg, DefaultKeyMap().ToggleHelp):
 				m.showHelp = !m.showHelp
 
+			case key.Matches(msg, DefaultKeyMap().Overrides):
+				m.overrides = newOverrideEditor(m.themeManager, m.width, m.height)
+				return m, nil
+
 			case key.Matches(msg, DefaultKeyMap().Preview):
 				selectedRow := m.table.SelectedRow()
 				if len(selectedRow) > 0 {
@@ -283,6 +300,12 @@ func (m *PlaygroundModel) View() string {
 		sections = append(sections, preview)
 	}
 
+	// Style override editor, in place of the theme list
+	if m.overrides != nil {
+		sections = append(sections, lipgloss.NewStyle().Padding(1, 2).Render(m.overrides.View()))
+		return lipgloss.JoinVertical(lipgloss.Left, sections...)
+	}
+
 	// Theme table
 	tableStyle := m.themeManager.GetStyleFor("table", "default").
 		Padding(1, 2)
@@ -393,6 +416,12 @@ func (m *PlaygroundModel) renderThemePreview(themeName string) string {
 
 	preview.WriteString(accessibilityStyle.Render(accessInfo))
 
+	// Sample dashboard in the theme's component styles
+	if sample, err := m.themeManager.RenderPreview(themeName, m.width-4); err == nil {
+		preview.WriteString("\n")
+		preview.WriteString(lipgloss.NewStyle().Padding(1, 2).Render(sample))
+	}
+
 	return preview.String()
 }
 
@@ -446,4 +446,42 @@ func BenchmarkQuickThemeToggle_HandleKey(b *testing.B) {
 		key := string(rune('1' + (i % 9)))
 		_ = toggle.HandleKey(key)
 	}
-}
\ No newline at end of file
+}
+func TestPlaygroundModel_OverrideEditor(t *testing.T) {
+	tm := NewThemeManager(t.TempDir())
+	var model tea.Model = NewPlaygroundModel(tm)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 100, Height: 50})
+	press := func(k tea.KeyMsg) *PlaygroundModel {
+		model, _ = model.Update(k)
+		return model.(*PlaygroundModel)
+	}
+
+	m := press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+	if m.overrides == nil {
+		t.Fatal("expected 'o' to open the override editor")
+	}
+	key := m.overrides.table.SelectedRow()[0]
+	if !strings.Contains(m.View(), key) {
+		t.Errorf("expected the editor to list %s", key)
+	}
+
+	m = press(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.overrides.editing {
+		t.Fatal("expected enter to start editing")
+	}
+	m.overrides.input.SetValue("#123456")
+	m = press(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := tm.Overrides()[key]; got != "#123456" || m.overrides.err != nil {
+		t.Fatalf("expected %s overridden, got %q (err %v)", key, got, m.overrides.err)
+	}
+
+	m = press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if _, ok := tm.Overrides()[key]; ok {
+		t.Error("expected x to clear the override")
+	}
+
+	m = press(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.overrides != nil {
+		t.Error("expected esc to close the editor")
+	}
+}
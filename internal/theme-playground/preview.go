@@ -0,0 +1,130 @@
+// Copyright 2025 James Ross
+package themeplayground
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// previewMinWidth is the narrowest sample dashboard RenderPreview draws
+const previewMinWidth = 48
+
+// previewQueues is the fake data shown in the preview's queue table
+var previewQueues = []struct {
+	name                     string
+	pending, running, failed int
+}{
+	{"jobqueue:high_priority", 128, 12, 0},
+	{"jobqueue:low_priority", 1156, 30, 3},
+	{"jobqueue:bulk", 42, 0, 17},
+	{"jobqueue:dead_letter", 20, 0, 0},
+}
+
+// RenderPreview renders a sample dashboard in the named theme: navigation
+// tabs, status cards, a queue table, a progress bar, a notification and
+// buttons, each drawn with the theme's component styles. The theme does
+// not need to be active; the preferences' style overrides are applied, as
+// they would be once it is. Colors are degraded to the detected terminal
+// profile, so contrast problems show up as they would in the TUI. width is
+// the number of columns to fill and is raised to 48 when smaller.
+func (tm *ThemeManager) RenderPreview(themeName string, width int) (string, error) {
+	theme, err := tm.GetTheme(themeName)
+	if err != nil {
+		return "", err
+	}
+	if width < previewMinWidth {
+		width = previewMinWidth
+	}
+	tm.mu.RLock()
+	profile := tm.caps.Profile
+	theme = tm.applyOverrides(theme, tm.preferences.Overrides)
+	tm.mu.RUnlock()
+
+	style := func(component, variant string) lipgloss.Style {
+		return tm.styleFor(theme, profile, component, variant)
+	}
+	color := func(c Color) lipgloss.TerminalColor {
+		return tm.terminalColor(c.Hex, profile)
+	}
+
+	sections := []string{
+		renderPreviewNav(style),
+		renderPreviewCards(theme, style, color, width),
+		renderPreviewTable(style, width),
+		renderPreviewProgress(style, width, 0.68),
+		style("notification", "").Width(width - 2).Render("✓ Job import-batch-42 completed in 1.8s"),
+		lipgloss.JoinHorizontal(lipgloss.Top,
+			style("button", "primary").Render("Retry"), " ",
+			style("button", "secondary").Render("Peek"), " ",
+			style("button", "danger").Render("Purge DLQ")),
+		lipgloss.JoinHorizontal(lipgloss.Top,
+			style("input", "focus").Render("queue: high"), " ",
+			style("input", "error").Render("count: -1")),
+	}
+
+	title := style("base", "").Bold(true).Render(fmt.Sprintf("%s — %s", theme.Name, theme.Description))
+	body := lipgloss.JoinVertical(lipgloss.Left, append([]string{title}, sections...)...)
+	return style("base", "").Width(width).MaxWidth(width).Render(body), nil
+}
+
+func renderPreviewNav(style func(component, variant string) lipgloss.Style) string {
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		style("navigation", "active").Render("Queues"),
+		style("navigation", "hover").Render("Workers"),
+		style("navigation", "").Render("DLQ"),
+		style("navigation", "").Render("Scheduled"))
+}
+
+func renderPreviewCards(theme *Theme, style func(component, variant string) lipgloss.Style, color func(Color) lipgloss.TerminalColor, width int) string {
+	cards := []struct {
+		title, value string
+		status       Color
+	}{
+		{"Pending", "1,346", theme.Palette.StatusPending},
+		{"Running", "42", theme.Palette.StatusRunning},
+		{"Failed", "20", theme.Palette.StatusFailed},
+	}
+	// each card has a 1-column border on both sides and a 1-column gap
+	cardW := (width-2)/len(cards) - 2
+	out := make([]string, 0, 2*len(cards))
+	for i, c := range cards {
+		title := lipgloss.NewStyle().Foreground(color(theme.Components.StatusCard.Title)).Render(c.title)
+		value := lipgloss.NewStyle().Foreground(color(c.status)).Bold(true).Render(c.value)
+		if i > 0 {
+			out = append(out, " ")
+		}
+		out = append(out, style("status_card", "").Width(cardW).Render(title+"\n"+value))
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, out...)
+}
+
+func renderPreviewTable(style func(component, variant string) lipgloss.Style, width int) string {
+	inner := width - 2       // table border
+	nameW := inner - 2 - 3*9 // cell padding plus three numeric columns
+	row := func(variant, name string, counts ...interface{}) string {
+		line := fmt.Sprintf("%-*.*s", nameW, nameW, name) + fmt.Sprintf("%9v%9v%9v", counts...)
+		return style("table", variant).Width(inner).Render(line)
+	}
+	lines := []string{row("header", "Queue", "Pending", "Running", "Failed")}
+	for i, q := range previewQueues {
+		variant := "row"
+		switch {
+		case i == 1:
+			variant = "selected"
+		case i%2 == 1:
+			variant = "row_alt"
+		}
+		lines = append(lines, row(variant, q.name, q.pending, q.running, q.failed))
+	}
+	return style("table", "").Render(strings.Join(lines, "\n"))
+}
+
+func renderPreviewProgress(style func(component, variant string) lipgloss.Style, width int, done float64) string {
+	label := fmt.Sprintf(" Draining bulk %3.0f%%", done*100)
+	barW := width - lipgloss.Width(label)
+	filled := int(float64(barW) * done)
+	bar := style("progress_bar", "")
+	return bar.Render(strings.Repeat("█", filled)+strings.Repeat("░", barW-filled)) + label
+}
@@ -0,0 +1,54 @@
+// Copyright 2025 James Ross
+package themeplayground
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestThemeManager_RenderPreview(t *testing.T) {
+	tm := NewThemeManager(t.TempDir())
+
+	for _, theme := range tm.ListThemes() {
+		for _, width := range []int{20, 80, 120} {
+			out, err := tm.RenderPreview(theme.Name, width)
+			if err != nil {
+				t.Fatalf("%s: %v", theme.Name, err)
+			}
+			want := width
+			if want < previewMinWidth {
+				want = previewMinWidth
+			}
+			for i, line := range strings.Split(out, "\n") {
+				if w := lipgloss.Width(line); w != want {
+					t.Fatalf("%s at %d: line %d is %d columns wide", theme.Name, width, i, w)
+				}
+			}
+			for _, part := range []string{"Queues", "Pending", "jobqueue:bulk", "68%", "completed", "Purge DLQ"} {
+				if !strings.Contains(out, part) {
+					t.Errorf("%s: preview is missing %q", theme.Name, part)
+				}
+			}
+		}
+	}
+}
+
+func TestThemeManager_RenderPreviewLeavesActiveTheme(t *testing.T) {
+	tm := NewThemeManager(t.TempDir())
+	if err := tm.SetActiveTheme(ThemeDefault); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := tm.RenderPreview(ThemeHighContrast, 80); err != nil {
+		t.Fatal(err)
+	}
+	if got := tm.GetActiveTheme().Name; got != ThemeDefault {
+		t.Errorf("active theme changed to %s", got)
+	}
+
+	if _, err := tm.RenderPreview("no-such-theme", 80); err == nil {
+		t.Error("expected an error for an unknown theme")
+	}
+}
@@ -0,0 +1,561 @@
+// Copyright 2025 James Ross
+package themeplayground
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Supported external color scheme formats
+const (
+	SchemeFormatBase16 = "base16" // Base16 / tinted-theming YAML
+	SchemeFormatITerm2 = "iterm2" // iTerm2 .itermcolors property list
+)
+
+// terminalScheme is the common shape every external format is reduced to:
+// the 16 ANSI colors plus the terminal's special colors. Surface and Muted
+// are optional hints that only richer formats (Base16) provide.
+type terminalScheme struct {
+	Name       string
+	Author     string
+	Background string
+	Foreground string
+	Selection  string
+	Cursor     string
+	Surface    string
+	Muted      string
+	ANSI       [16]string
+}
+
+// ImportScheme converts an external color scheme into a Theme. The 16 ANSI
+// colors are mapped onto palette roles by nearest hue, and roles the scheme
+// does not define are derived by blending its background and foreground.
+// The returned theme has passed validateTheme but is not registered.
+func (tm *ThemeManager) ImportScheme(format string, data []byte) (*Theme, error) {
+	var (
+		scheme *terminalScheme
+		err    error
+	)
+	switch strings.ToLower(format) {
+	case SchemeFormatBase16:
+		scheme, err = parseBase16(data)
+	case SchemeFormatITerm2:
+		scheme, err = parseITerm2(data)
+	default:
+		return nil, ErrUnsupportedThemeFormat.WithDetails(format)
+	}
+	if err != nil {
+		return nil, ErrThemeImportFailed.WithDetails(err.Error())
+	}
+	if scheme.Name == "" {
+		// iTerm2 files carry no name; derive a stable one from the content.
+		sum := sha1.Sum(data)
+		scheme.Name = fmt.Sprintf("%s-%x", strings.ToLower(format), sum[:4])
+	}
+
+	theme, err := themeFromScheme(scheme, strings.ToLower(format))
+	if err != nil {
+		return nil, ErrThemeImportFailed.WithDetails(err.Error())
+	}
+	if err := tm.validateTheme(theme); err != nil {
+		return nil, err
+	}
+	return theme, nil
+}
+
+// ExportScheme renders a registered theme in an external scheme format.
+func (tm *ThemeManager) ExportScheme(name, format string) ([]byte, error) {
+	theme, err := tm.GetTheme(name)
+	if err != nil {
+		return nil, err
+	}
+	scheme, err := schemeFromTheme(theme)
+	if err != nil {
+		return nil, ErrThemeExportFailed.WithDetails(err.Error())
+	}
+	switch strings.ToLower(format) {
+	case SchemeFormatBase16:
+		return renderBase16(theme, scheme), nil
+	case SchemeFormatITerm2:
+		return renderITerm2(scheme), nil
+	default:
+		return nil, ErrUnsupportedThemeFormat.WithDetails(format)
+	}
+}
+
+// statusHues are the hues (degrees) status roles are matched against. Status
+// colors are not part of terminal schemes, so each takes whichever ANSI color
+// is closest in hue.
+var statusHues = map[string]float64{
+	"failed":    0,
+	"retrying":  30,
+	"completed": 120,
+	"running":   210,
+}
+
+func themeFromScheme(s *terminalScheme, format string) (*Theme, error) {
+	bg, err := parseSchemeHex(s.Background)
+	if err != nil {
+		return nil, fmt.Errorf("background: %w", err)
+	}
+	fg, err := parseSchemeHex(s.Foreground)
+	if err != nil {
+		return nil, fmt.Errorf("foreground: %w", err)
+	}
+
+	var ansi [16]*RGB
+	var chromatic []RGB
+	for i, hex := range s.ANSI {
+		if hex == "" {
+			continue
+		}
+		c, err := parseSchemeHex(hex)
+		if err != nil {
+			return nil, fmt.Errorf("ansi %d: %w", i, err)
+		}
+		ansi[i] = &c
+		if i%8 != 0 && i%8 != 7 { // black/white slots carry no hue
+			chromatic = append(chromatic, c)
+		}
+	}
+	nearest := func(hue float64) RGB {
+		best, bestDist := mixRGB(fg, bg, 0.3), math.MaxFloat64
+		for _, c := range chromatic {
+			h, sat := hueSat(c)
+			if sat < 0.15 {
+				continue
+			}
+			d := math.Abs(h - hue)
+			if d > 180 {
+				d = 360 - d
+			}
+			if d < bestDist {
+				best, bestDist = c, d
+			}
+		}
+		return best
+	}
+	// slot returns the ANSI color at i, falling back to the nearest hue.
+	slot := func(i int, hue float64) RGB {
+		if ansi[i] != nil {
+			return *ansi[i]
+		}
+		return nearest(hue)
+	}
+	optional := func(hex string, fallback RGB) RGB {
+		if c, err := parseSchemeHex(hex); err == nil {
+			return c
+		}
+		return fallback
+	}
+
+	red, green, yellow := slot(1, 0), slot(2, 120), slot(3, 60)
+	blue, magenta, cyan := slot(4, 240), slot(5, 300), slot(6, 180)
+	muted := optional(s.Muted, mixRGB(fg, bg, 0.3))
+	disabled := optional(s.ANSI[8], mixRGB(fg, bg, 0.55))
+
+	color := func(c RGB, name string) Color {
+		return Color{Hex: rgbHex(c), Name: name}
+	}
+
+	return &Theme{
+		Name:        s.Name,
+		Description: fmt.Sprintf("Imported from %s color scheme %q", format, s.Name),
+		Category:    CategoryCustom,
+		Version:     "1.0.0",
+		Author:      s.Author,
+		Palette: ColorPalette{
+			Background:      color(bg, "Background"),
+			Surface:         color(optional(s.Surface, mixRGB(bg, fg, 0.06)), "Surface"),
+			Primary:         color(blue, "Blue"),
+			Secondary:       color(muted, "Muted"),
+			Accent:          color(magenta, "Magenta"),
+			Success:         color(green, "Green"),
+			Warning:         color(yellow, "Yellow"),
+			Error:           color(red, "Red"),
+			Info:            color(cyan, "Cyan"),
+			TextPrimary:     color(fg, "Foreground"),
+			TextSecondary:   color(muted, "Muted"),
+			TextDisabled:    color(disabled, "Bright Black"),
+			TextInverse:     color(bg, "Background"),
+			Border:          color(mixRGB(bg, fg, 0.2), "Border"),
+			Divider:         color(mixRGB(bg, fg, 0.12), "Divider"),
+			Focus:           color(optional(s.Cursor, blue), "Cursor"),
+			Selected:        color(optional(s.Selection, mixRGB(bg, fg, 0.2)), "Selection"),
+			Hover:           color(mixRGB(bg, fg, 0.1), "Hover"),
+			StatusPending:   color(disabled, "Pending"),
+			StatusRunning:   color(nearest(statusHues["running"]), "Running"),
+			StatusCompleted: color(nearest(statusHues["completed"]), "Completed"),
+			StatusFailed:    color(nearest(statusHues["failed"]), "Failed"),
+			StatusRetrying:  color(nearest(statusHues["retrying"]), "Retrying"),
+		},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+func schemeFromTheme(theme *Theme) (*terminalScheme, error) {
+	p := theme.Palette
+	get := func(c Color) (RGB, error) { return parseSchemeHex(c.Hex) }
+
+	roles := []Color{p.Background, p.TextPrimary, p.Surface, p.TextSecondary, p.TextDisabled,
+		p.Error, p.Success, p.Warning, p.Primary, p.Accent, p.Info, p.Selected, p.Focus}
+	rgbs := make([]RGB, len(roles))
+	for i, c := range roles {
+		v, err := get(c)
+		if err != nil {
+			return nil, err
+		}
+		rgbs[i] = v
+	}
+	bg, fg, surface, secondary, disabled := rgbs[0], rgbs[1], rgbs[2], rgbs[3], rgbs[4]
+	hues := rgbs[5:11] // red, green, yellow, blue, magenta, cyan
+
+	s := &terminalScheme{
+		Name:       theme.Name,
+		Author:     theme.Author,
+		Background: rgbHex(bg),
+		Foreground: rgbHex(fg),
+		Surface:    rgbHex(surface),
+		Muted:      rgbHex(secondary),
+		Selection:  rgbHex(rgbs[11]),
+		Cursor:     rgbHex(rgbs[12]),
+	}
+	s.ANSI[0] = rgbHex(surface)
+	s.ANSI[7] = rgbHex(secondary)
+	s.ANSI[8] = rgbHex(disabled)
+	s.ANSI[15] = rgbHex(fg)
+	for i, c := range hues {
+		s.ANSI[1+i] = rgbHex(c)
+		s.ANSI[9+i] = rgbHex(mixRGB(c, RGB{0xff, 0xff, 0xff}, 0.15))
+	}
+	return s, nil
+}
+
+// Base16
+
+func parseBase16(data []byte) (*terminalScheme, error) {
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse base16 yaml: %w", err)
+	}
+	// tinted-theming schemes nest the colors under "palette" and use "name".
+	colors := doc
+	if nested, ok := doc["palette"].(map[string]interface{}); ok {
+		colors = nested
+	}
+	base := make(map[string]string, 16)
+	for i := 0; i < 16; i++ {
+		key := fmt.Sprintf("base%02X", i)
+		raw, ok := colors[key]
+		if !ok {
+			raw, ok = colors[strings.ToLower(key)]
+		}
+		if !ok {
+			return nil, fmt.Errorf("missing %s", key)
+		}
+		switch v := raw.(type) {
+		case int:
+			// Unquoted all-digit values such as 002b36 decode as integers.
+			base[key] = fmt.Sprintf("%06d", v)
+		default:
+			base[key] = fmt.Sprint(v)
+		}
+	}
+
+	s := &terminalScheme{
+		Name:       firstString(doc, "scheme", "name"),
+		Author:     firstString(doc, "author"),
+		Background: base["base00"],
+		Foreground: base["base05"],
+		Selection:  base["base02"],
+		Cursor:     base["base05"],
+		Surface:    base["base01"],
+		Muted:      base["base04"],
+	}
+	// Standard base16-shell ANSI assignment.
+	order := []string{"base00", "base08", "base0B", "base0A", "base0D", "base0E", "base0C", "base05",
+		"base03", "base08", "base0B", "base0A", "base0D", "base0E", "base0C", "base07"}
+	for i, key := range order {
+		s.ANSI[i] = base[key]
+	}
+	return s, nil
+}
+
+func renderBase16(theme *Theme, s *terminalScheme) []byte {
+	bg, _ := parseSchemeHex(s.Background)
+	fg, _ := parseSchemeHex(s.Foreground)
+	red, _ := parseSchemeHex(s.ANSI[1])
+	yellow, _ := parseSchemeHex(s.ANSI[3])
+	// base06/07 continue the ramp away from the background.
+	extreme := RGB{0xff, 0xff, 0xff}
+	if luminance(bg) > 0.5 {
+		extreme = RGB{}
+	}
+	base := []string{
+		s.Background, s.Surface, s.Selection, s.ANSI[8], s.Muted, s.Foreground,
+		rgbHex(mixRGB(fg, extreme, 0.3)), rgbHex(mixRGB(fg, extreme, 0.6)),
+		s.ANSI[1], rgbHex(mixRGB(red, yellow, 0.5)), s.ANSI[3], s.ANSI[2],
+		s.ANSI[6], s.ANSI[4], s.ANSI[5], rgbHex(mixRGB(red, bg, 0.4)),
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "scheme: %q\n", theme.Name)
+	fmt.Fprintf(&buf, "author: %q\n", theme.Author)
+	for i, hex := range base {
+		fmt.Fprintf(&buf, "base%02X: %q\n", i, strings.TrimPrefix(hex, "#"))
+	}
+	return buf.Bytes()
+}
+
+// iTerm2
+
+// plistValue is a minimal XML property-list node: dictionaries keep their
+// keys in order, reals are kept as text.
+type plistValue struct {
+	keys   []string
+	values []*plistValue
+	text   string
+}
+
+func (v *plistValue) get(key string) *plistValue {
+	for i, k := range v.keys {
+		if k == key {
+			return v.values[i]
+		}
+	}
+	return nil
+}
+
+func parseITerm2(data []byte) (*terminalScheme, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	root, err := decodePlist(dec)
+	if err != nil {
+		return nil, fmt.Errorf("parse iterm2 plist: %w", err)
+	}
+	if root == nil || root.keys == nil {
+		return nil, fmt.Errorf("parse iterm2 plist: top-level dict not found")
+	}
+
+	color := func(key string) (string, error) {
+		d := root.get(key)
+		if d == nil {
+			return "", nil
+		}
+		var rgb [3]uint8
+		for i, comp := range []string{"Red Component", "Green Component", "Blue Component"} {
+			c := d.get(comp)
+			if c == nil {
+				return "", fmt.Errorf("%s: missing %s", key, comp)
+			}
+			f, err := strconv.ParseFloat(strings.TrimSpace(c.text), 64)
+			if err != nil {
+				return "", fmt.Errorf("%s: %s: %w", key, comp, err)
+			}
+			rgb[i] = uint8(math.Round(math.Max(0, math.Min(1, f)) * 255))
+		}
+		return rgbHex(RGB{rgb[0], rgb[1], rgb[2]}), nil
+	}
+
+	s := &terminalScheme{}
+	fields := map[string]*string{
+		"Background Color": &s.Background,
+		"Foreground Color": &s.Foreground,
+		"Selection Color":  &s.Selection,
+		"Cursor Color":     &s.Cursor,
+	}
+	for i := 0; i < 16; i++ {
+		fields[fmt.Sprintf("Ansi %d Color", i)] = &s.ANSI[i]
+	}
+	for key, dst := range fields {
+		if *dst, err = color(key); err != nil {
+			return nil, err
+		}
+	}
+	if s.Background == "" {
+		s.Background = s.ANSI[0]
+	}
+	if s.Foreground == "" {
+		s.Foreground = s.ANSI[7]
+	}
+	return s, nil
+}
+
+// decodePlist returns the first value in the stream, skipping the plist
+// envelope. Arrays and scalar types other than real/integer/string are
+// decoded as text and otherwise ignored.
+func decodePlist(dec *xml.Decoder) (*plistValue, error) {
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local == "plist" {
+			continue
+		}
+		return decodePlistElement(dec, start)
+	}
+}
+
+func decodePlistElement(dec *xml.Decoder, start xml.StartElement) (*plistValue, error) {
+	if start.Name.Local != "dict" {
+		var text string
+		if err := dec.DecodeElement(&text, &start); err != nil {
+			// Nested structures such as arrays are not needed; skip them.
+			return &plistValue{}, nil
+		}
+		return &plistValue{text: text}, nil
+	}
+
+	v := &plistValue{keys: []string{}}
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.EndElement:
+			return v, nil
+		case xml.StartElement:
+			if t.Name.Local != "key" {
+				if err := dec.Skip(); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			var key string
+			if err := dec.DecodeElement(&key, &t); err != nil {
+				return nil, err
+			}
+			valStart, err := nextStart(dec)
+			if err != nil {
+				return nil, err
+			}
+			val, err := decodePlistElement(dec, valStart)
+			if err != nil {
+				return nil, err
+			}
+			v.keys = append(v.keys, key)
+			v.values = append(v.values, val)
+		}
+	}
+}
+
+func nextStart(dec *xml.Decoder) (xml.StartElement, error) {
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return xml.StartElement{}, err
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			return start, nil
+		}
+	}
+}
+
+func renderITerm2(s *terminalScheme) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	buf.WriteString(`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">` + "\n")
+	buf.WriteString("<plist version=\"1.0\">\n<dict>\n")
+	writeColor := func(key, hex string) {
+		c, err := parseSchemeHex(hex)
+		if err != nil {
+			return
+		}
+		fmt.Fprintf(&buf, "\t<key>%s</key>\n\t<dict>\n", key)
+		fmt.Fprintf(&buf, "\t\t<key>Alpha Component</key>\n\t\t<real>1</real>\n")
+		fmt.Fprintf(&buf, "\t\t<key>Blue Component</key>\n\t\t<real>%s</real>\n", componentString(c.B))
+		fmt.Fprintf(&buf, "\t\t<key>Color Space</key>\n\t\t<string>sRGB</string>\n")
+		fmt.Fprintf(&buf, "\t\t<key>Green Component</key>\n\t\t<real>%s</real>\n", componentString(c.G))
+		fmt.Fprintf(&buf, "\t\t<key>Red Component</key>\n\t\t<real>%s</real>\n", componentString(c.R))
+		buf.WriteString("\t</dict>\n")
+	}
+	for i, hex := range s.ANSI {
+		writeColor(fmt.Sprintf("Ansi %d Color", i), hex)
+	}
+	writeColor("Background Color", s.Background)
+	writeColor("Cursor Color", s.Cursor)
+	writeColor("Foreground Color", s.Foreground)
+	writeColor("Selection Color", s.Selection)
+	buf.WriteString("</dict>\n</plist>\n")
+	return buf.Bytes()
+}
+
+// Color helpers
+
+func parseSchemeHex(s string) (RGB, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(s) != 6 {
+		return RGB{}, fmt.Errorf("invalid color %q", s)
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return RGB{}, fmt.Errorf("invalid color %q", s)
+	}
+	return RGB{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v)}, nil
+}
+
+func rgbHex(c RGB) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// mixRGB moves a toward b by t (0..1).
+func mixRGB(a, b RGB, t float64) RGB {
+	lerp := func(x, y uint8) uint8 {
+		return uint8(math.Round(float64(x) + (float64(y)-float64(x))*t))
+	}
+	return RGB{R: lerp(a.R, b.R), G: lerp(a.G, b.G), B: lerp(a.B, b.B)}
+}
+
+// hueSat returns the HSL hue in degrees and saturation in 0..1.
+func hueSat(c RGB) (float64, float64) {
+	r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	max, min := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	delta := max - min
+	if delta == 0 {
+		return 0, 0
+	}
+	l := (max + min) / 2
+	sat := delta / (1 - math.Abs(2*l-1))
+	var h float64
+	switch max {
+	case r:
+		h = math.Mod((g-b)/delta, 6)
+	case g:
+		h = (b-r)/delta + 2
+	default:
+		h = (r-g)/delta + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h, sat
+}
+
+func luminance(c RGB) float64 {
+	return (0.2126*float64(c.R) + 0.7152*float64(c.G) + 0.0722*float64(c.B)) / 255
+}
+
+func componentString(v uint8) string {
+	return strconv.FormatFloat(float64(v)/255, 'f', -1, 64)
+}
+
+func firstString(doc map[string]interface{}, keys ...string) string {
+	for _, k := range keys {
+		if s, ok := doc[k].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
@@ -0,0 +1,138 @@
+// Copyright 2025 James Ross
+package themeplayground
+
+import (
+	"strings"
+	"testing"
+)
+
+const solarizedBase16 = `scheme: "Solarized Dark"
+author: "Ethan Schoonover"
+base00: 002b36
+base01: "073642"
+base02: "586e75"
+base03: "657b83"
+base04: "839496"
+base05: "93a1a1"
+base06: "eee8d5"
+base07: "fdf6e3"
+base08: "dc322f"
+base09: "cb4b16"
+base0A: "b58900"
+base0B: "859900"
+base0C: "2aa198"
+base0D: "268bd2"
+base0E: "6c71c4"
+base0F: "d33682"
+`
+
+func TestImportSchemeBase16(t *testing.T) {
+	tm := NewThemeManager(t.TempDir())
+
+	theme, err := tm.ImportScheme(SchemeFormatBase16, []byte(solarizedBase16))
+	if err != nil {
+		t.Fatalf("ImportScheme failed: %v", err)
+	}
+	if theme.Name != "Solarized Dark" || theme.Author != "Ethan Schoonover" {
+		t.Errorf("unexpected metadata: %q by %q", theme.Name, theme.Author)
+	}
+	if theme.Category != CategoryCustom {
+		t.Errorf("expected custom category, got %s", theme.Category)
+	}
+
+	p := theme.Palette
+	checks := map[string]string{
+		"background": p.Background.Hex,
+		"surface":    p.Surface.Hex,
+		"text":       p.TextPrimary.Hex,
+		"error":      p.Error.Hex,
+		"success":    p.Success.Hex,
+		"primary":    p.Primary.Hex,
+		"failed":     p.StatusFailed.Hex,
+		"completed":  p.StatusCompleted.Hex,
+	}
+	want := map[string]string{
+		"background": "#002b36",
+		"surface":    "#073642",
+		"text":       "#93a1a1",
+		"error":      "#dc322f",
+		"success":    "#859900",
+		"primary":    "#268bd2",
+		"failed":     "#dc322f",
+		"completed":  "#859900",
+	}
+	for role, hex := range want {
+		if checks[role] != hex {
+			t.Errorf("%s: got %s, want %s", role, checks[role], hex)
+		}
+	}
+
+	if err := tm.RegisterTheme(theme); err != nil {
+		t.Fatalf("imported theme should register: %v", err)
+	}
+}
+
+func TestSchemeITerm2RoundTrip(t *testing.T) {
+	tm := NewThemeManager(t.TempDir())
+
+	data, err := tm.ExportScheme(ThemeTokyoNight, SchemeFormatITerm2)
+	if err != nil {
+		t.Fatalf("ExportScheme failed: %v", err)
+	}
+	if !strings.Contains(string(data), "<key>Ansi 15 Color</key>") {
+		t.Fatalf("export missing ANSI colors:\n%s", data)
+	}
+
+	theme, err := tm.ImportScheme(SchemeFormatITerm2, data)
+	if err != nil {
+		t.Fatalf("ImportScheme failed: %v", err)
+	}
+	if !strings.HasPrefix(theme.Name, "iterm2-") {
+		t.Errorf("expected generated name, got %q", theme.Name)
+	}
+
+	orig, _ := tm.GetTheme(ThemeTokyoNight)
+	same := map[string][2]string{
+		"background": {orig.Palette.Background.Hex, theme.Palette.Background.Hex},
+		"foreground": {orig.Palette.TextPrimary.Hex, theme.Palette.TextPrimary.Hex},
+		"error":      {orig.Palette.Error.Hex, theme.Palette.Error.Hex},
+		"success":    {orig.Palette.Success.Hex, theme.Palette.Success.Hex},
+		"warning":    {orig.Palette.Warning.Hex, theme.Palette.Warning.Hex},
+		"primary":    {orig.Palette.Primary.Hex, theme.Palette.Primary.Hex},
+	}
+	for role, pair := range same {
+		if !strings.EqualFold(pair[0], pair[1]) {
+			t.Errorf("%s did not round-trip: %s -> %s", role, pair[0], pair[1])
+		}
+	}
+}
+
+func TestSchemeBase16ExportReimports(t *testing.T) {
+	tm := NewThemeManager(t.TempDir())
+
+	data, err := tm.ExportScheme(ThemeOneDark, SchemeFormatBase16)
+	if err != nil {
+		t.Fatalf("ExportScheme failed: %v", err)
+	}
+	theme, err := tm.ImportScheme(SchemeFormatBase16, data)
+	if err != nil {
+		t.Fatalf("re-import failed: %v\n%s", err, data)
+	}
+	if theme.Name != ThemeOneDark || theme.Palette.Background.Hex != "#282c34" {
+		t.Errorf("unexpected re-import: %s %s", theme.Name, theme.Palette.Background.Hex)
+	}
+}
+
+func TestImportSchemeErrors(t *testing.T) {
+	tm := NewThemeManager(t.TempDir())
+
+	if _, err := tm.ImportScheme("vscode", []byte("{}")); err == nil {
+		t.Error("expected unsupported format error")
+	}
+	if _, err := tm.ImportScheme(SchemeFormatBase16, []byte("scheme: x\nbase00: \"000000\"\n")); err == nil {
+		t.Error("expected error for incomplete base16 scheme")
+	}
+	if _, err := tm.ExportScheme("missing", SchemeFormatBase16); err == nil {
+		t.Error("expected error exporting unknown theme")
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2025 James Ross
+package themeplayground
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ColorProfile is the range of colors a terminal can display
+type ColorProfile int
+
+const (
+	ProfileMonochrome ColorProfile = iota
+	ProfileANSI16
+	ProfileANSI256
+	ProfileTrueColor
+)
+
+// String returns the profile name
+func (p ColorProfile) String() string {
+	switch p {
+	case ProfileMonochrome:
+		return "monochrome"
+	case ProfileANSI16:
+		return "16"
+	case ProfileANSI256:
+		return "256"
+	case ProfileTrueColor:
+		return "truecolor"
+	default:
+		return "unknown"
+	}
+}
+
+// TerminalCaps describes what the current terminal can render
+type TerminalCaps struct {
+	Profile   ColorProfile `json:"profile"`
+	NoColor   bool         `json:"no_color"`
+	Term      string       `json:"term"`
+	ColorTerm string       `json:"color_term"`
+}
+
+// DetectTerminalCapabilities inspects $COLORTERM, $TERM and $NO_COLOR,
+// records the result for GetStyleFor, and returns it. NO_COLOR only forces
+// monochrome when the RespectNoColor preference is set.
+func (tm *ThemeManager) DetectTerminalCapabilities() TerminalCaps {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	caps := detectTerminalCaps(os.Getenv("COLORTERM"), os.Getenv("TERM"))
+	if os.Getenv("NO_COLOR") != "" && (tm.preferences == nil || tm.preferences.RespectNoColor) {
+		caps.NoColor = true
+		caps.Profile = ProfileMonochrome
+	}
+	tm.caps = caps
+	return caps
+}
+
+// TerminalCapabilities returns the capabilities GetStyleFor renders for
+func (tm *ThemeManager) TerminalCapabilities() TerminalCaps {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	return tm.caps
+}
+
+// SetTerminalCapabilities overrides the detected capabilities
+func (tm *ThemeManager) SetTerminalCapabilities(caps TerminalCaps) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.caps = caps
+}
+
+// detectTerminalCaps maps the terminal environment to a color profile
+func detectTerminalCaps(colorTerm, term string) TerminalCaps {
+	caps := TerminalCaps{Term: term, ColorTerm: colorTerm}
+	colorTerm = strings.ToLower(colorTerm)
+	term = strings.ToLower(term)
+
+	switch {
+	case colorTerm == "truecolor" || colorTerm == "24bit":
+		caps.Profile = ProfileTrueColor
+	case term == "" || term == "dumb":
+		caps.Profile = ProfileMonochrome
+	case strings.HasSuffix(term, "-direct") || strings.Contains(term, "truecolor") ||
+		strings.HasPrefix(term, "xterm-kitty") || strings.HasPrefix(term, "alacritty") ||
+		strings.HasPrefix(term, "wezterm"):
+		caps.Profile = ProfileTrueColor
+	case strings.Contains(term, "256color"):
+		caps.Profile = ProfileANSI256
+	default:
+		caps.Profile = ProfileANSI16
+	}
+	return caps
+}
+
+// styleTheme returns the theme GetStyleFor renders with: the active theme
+// with the preferences' style overrides applied. A monochrome terminal
+// always gets the monochrome theme, without changing the saved active
+// theme.
+func (tm *ThemeManager) styleTheme() (*Theme, ColorProfile) {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	if tm.caps.Profile == ProfileMonochrome {
+		if mono, ok := tm.registry[ThemeMonochrome]; ok {
+			return mono, ProfileMonochrome
+		}
+	}
+	return tm.applyOverrides(tm.activeTheme, tm.preferences.Overrides), tm.caps.Profile
+}
+
+// terminalColor converts a theme color to one the profile can display,
+// quantizing to the nearest palette entry below truecolor.
+func (tm *ThemeManager) terminalColor(hex string, profile ColorProfile) lipgloss.TerminalColor {
+	if profile == ProfileMonochrome {
+		return lipgloss.NoColor{}
+	}
+	if profile == ProfileTrueColor {
+		return lipgloss.Color(hex)
+	}
+
+	rgb, err := tm.colorUtils.HexToRGB(hex)
+	if err != nil {
+		return lipgloss.Color(hex)
+	}
+	if profile == ProfileANSI256 {
+		return lipgloss.Color(strconv.Itoa(nearestANSI256(*rgb)))
+	}
+	return lipgloss.Color(strconv.Itoa(nearestANSI16(*rgb)))
+}
+
+// ansi16Palette holds the xterm default values of the 16 basic colors
+var ansi16Palette = [16]RGB{
+	{0, 0, 0}, {128, 0, 0}, {0, 128, 0}, {128, 128, 0},
+	{0, 0, 128}, {128, 0, 128}, {0, 128, 128}, {192, 192, 192},
+	{128, 128, 128}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{0, 0, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// cubeLevels are the channel values of the xterm 6x6x6 color cube
+var cubeLevels = [6]int{0, 95, 135, 175, 215, 255}
+
+// nearestANSI16 returns the index of the closest basic color
+func nearestANSI16(c RGB) int {
+	best, bestDist := 0, -1
+	for i, p := range ansi16Palette {
+		if d := rgbDistance(c, p); bestDist < 0 || d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
+
+// nearestANSI256 returns the closest entry of the 6x6x6 cube or the
+// grayscale ramp. The 16 basic colors are skipped because terminals
+// commonly remap them.
+func nearestANSI256(c RGB) int {
+	ri, gi, bi := nearestCubeLevel(c.R), nearestCubeLevel(c.G), nearestCubeLevel(c.B)
+	cube := RGB{R: uint8(cubeLevels[ri]), G: uint8(cubeLevels[gi]), B: uint8(cubeLevels[bi])}
+	cubeIndex := 16 + 36*ri + 6*gi + bi
+
+	// Grayscale ramp 232-255 runs from 8 to 238 in steps of 10.
+	avg := (int(c.R) + int(c.G) + int(c.B)) / 3
+	grayStep := (avg - 8 + 5) / 10
+	if grayStep < 0 {
+		grayStep = 0
+	} else if grayStep > 23 {
+		grayStep = 23
+	}
+	level := uint8(8 + 10*grayStep)
+	gray := RGB{R: level, G: level, B: level}
+
+	if rgbDistance(c, gray) < rgbDistance(c, cube) {
+		return 232 + grayStep
+	}
+	return cubeIndex
+}
+
+func nearestCubeLevel(v uint8) int {
+	best := 0
+	for i, level := range cubeLevels {
+		if absInt(int(v)-level) < absInt(int(v)-cubeLevels[best]) {
+			best = i
+		}
+	}
+	return best
+}
+
+func rgbDistance(a, b RGB) int {
+	dr, dg, db := int(a.R)-int(b.R), int(a.G)-int(b.G), int(a.B)-int(b.B)
+	return dr*dr + dg*dg + db*db
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,117 @@
+// Copyright 2025 James Ross
+package themeplayground
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func setTerminalEnv(t *testing.T, colorTerm, term, noColor string) {
+	t.Helper()
+	t.Setenv("COLORTERM", colorTerm)
+	t.Setenv("TERM", term)
+	t.Setenv("NO_COLOR", noColor)
+}
+
+func TestDetectTerminalCapabilities(t *testing.T) {
+	tests := []struct {
+		name      string
+		colorTerm string
+		term      string
+		noColor   string
+		want      ColorProfile
+	}{
+		{"truecolor", "truecolor", "xterm", "", ProfileTrueColor},
+		{"24bit", "24bit", "screen", "", ProfileTrueColor},
+		{"256color", "", "xterm-256color", "", ProfileANSI256},
+		{"basic", "", "xterm", "", ProfileANSI16},
+		{"dumb", "", "dumb", "", ProfileMonochrome},
+		{"no_color", "truecolor", "xterm-256color", "1", ProfileMonochrome},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setTerminalEnv(t, tt.colorTerm, tt.term, tt.noColor)
+			tm := NewThemeManager(t.TempDir())
+			if got := tm.DetectTerminalCapabilities().Profile; got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDetectTerminalCapabilitiesIgnoresNoColorWhenNotRespected(t *testing.T) {
+	setTerminalEnv(t, "truecolor", "xterm", "1")
+	tm := NewThemeManager(t.TempDir())
+	tm.preferences.RespectNoColor = false
+
+	caps := tm.DetectTerminalCapabilities()
+	if caps.NoColor || caps.Profile != ProfileTrueColor {
+		t.Errorf("expected truecolor with NO_COLOR ignored, got %+v", caps)
+	}
+}
+
+func TestGetStyleForQuantizesColors(t *testing.T) {
+	setTerminalEnv(t, "truecolor", "xterm", "")
+	tm := NewThemeManager(t.TempDir())
+	base, err := tm.GetTheme(ThemeDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	theme := *base
+	theme.Name = "quantize"
+	// a legible pair, so GetStyleFor keeps the text color
+	theme.Palette.Background = Color{Hex: "#0000ff"}
+	theme.Palette.TextPrimary = Color{Hex: "#ffff00"}
+	if err := tm.RegisterTheme(&theme); err != nil {
+		t.Fatal(err)
+	}
+	if err := tm.SetActiveTheme("quantize"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		profile ColorProfile
+		bg, fg  lipgloss.TerminalColor
+	}{
+		{ProfileTrueColor, lipgloss.Color("#0000ff"), lipgloss.Color("#ffff00")},
+		{ProfileANSI256, lipgloss.Color("21"), lipgloss.Color("226")},
+		{ProfileANSI16, lipgloss.Color("12"), lipgloss.Color("11")},
+	}
+	for _, tt := range tests {
+		tm.SetTerminalCapabilities(TerminalCaps{Profile: tt.profile})
+		style := tm.GetStyleFor("base", "")
+		if got := style.GetBackground(); got != tt.bg {
+			t.Errorf("%s: expected background %v, got %v", tt.profile, tt.bg, got)
+		}
+		if got := style.GetForeground(); got != tt.fg {
+			t.Errorf("%s: expected foreground %v, got %v", tt.profile, tt.fg, got)
+		}
+	}
+}
+
+func TestGetStyleForUsesMonochromeWithNoColor(t *testing.T) {
+	setTerminalEnv(t, "truecolor", "xterm", "1")
+	tm := NewThemeManager(t.TempDir())
+	if err := tm.SetActiveTheme(ThemeTokyoNight); err != nil {
+		t.Fatal(err)
+	}
+
+	style := tm.GetStyleFor("button", "primary")
+	if _, ok := style.GetBackground().(lipgloss.NoColor); !ok {
+		t.Errorf("expected no background color, got %v", style.GetBackground())
+	}
+	if got := tm.GetActiveTheme().Name; got != ThemeTokyoNight {
+		t.Errorf("expected the saved active theme to be kept, got %s", got)
+	}
+}
+
+func TestNearestANSI256Grayscale(t *testing.T) {
+	if got := nearestANSI256(RGB{R: 128, G: 128, B: 128}); got != 244 {
+		t.Errorf("expected gray to map to 244, got %d", got)
+	}
+	if got := nearestANSI256(RGB{R: 255, G: 255, B: 255}); got != 231 {
+		t.Errorf("expected white to map to 231, got %d", got)
+	}
+}
@@ -27,6 +27,7 @@ type ThemeManager struct {
 	colorUtils    *ColorUtilities
 	accessibility *AccessibilityChecker
 	callbacks     []func(*Theme)
+	caps          TerminalCaps
 }
 
 // NewThemeManager creates a new theme manager instance
@@ -37,6 +38,7 @@ func NewThemeManager(configDir string) *ThemeManager {
 		colorUtils:    NewColorUtilities(),
 		accessibility: NewAccessibilityChecker(),
 		callbacks:     make([]func(*Theme), 0),
+		caps:          TerminalCaps{Profile: ProfileTrueColor},
 	}
 
 	// Load built-in themes
@@ -45,6 +47,11 @@ func NewThemeManager(configDir string) *ThemeManager {
 	// Load user preferences
 	tm.loadPreferences()
 
+	// Degrade colors to what the terminal can show
+	if tm.preferences.AutoDetectTerminal {
+		tm.DetectTerminalCapabilities()
+	}
+
 	// Load custom themes
 	tm.loadCustomThemes()
 
@@ -255,35 +262,40 @@ func (tm *ThemeManager) validateColor(color *Color) error {
 
 // GetStyleFor returns a Lip Gloss style for a component and variant
 func (tm *ThemeManager) GetStyleFor(component, variant string) lipgloss.Style {
-	theme := tm.GetActiveTheme()
+	theme, profile := tm.styleTheme()
+	return tm.styleFor(theme, profile, component, variant)
+}
+
+// styleFor is GetStyleFor for a given theme and color profile
+func (tm *ThemeManager) styleFor(theme *Theme, profile ColorProfile, component, variant string) lipgloss.Style {
 	if theme == nil {
 		return lipgloss.NewStyle()
 	}
 
 	switch component {
 	case "button":
-		return tm.getButtonStyle(theme, variant)
+		return tm.getButtonStyle(theme, variant, profile)
 	case "table":
-		return tm.getTableStyle(theme, variant)
+		return tm.getTableStyle(theme, variant, profile)
 	case "modal":
-		return tm.getModalStyle(theme)
+		return tm.getModalStyle(theme, profile)
 	case "input":
-		return tm.getInputStyle(theme, variant)
+		return tm.getInputStyle(theme, variant, profile)
 	case "navigation":
-		return tm.getNavigationStyle(theme, variant)
+		return tm.getNavigationStyle(theme, variant, profile)
 	case "status_card":
-		return tm.getStatusCardStyle(theme)
+		return tm.getStatusCardStyle(theme, profile)
 	case "progress_bar":
-		return tm.getProgressBarStyle(theme)
+		return tm.getProgressBarStyle(theme, profile)
 	case "notification":
-		return tm.getNotificationStyle(theme)
+		return tm.getNotificationStyle(theme, profile)
 	default:
-		return tm.getBaseStyle(theme)
+		return tm.getBaseStyle(theme, profile)
 	}
 }
 
 // getButtonStyle returns button styling
-func (tm *ThemeManager) getButtonStyle(theme *Theme, variant string) lipgloss.Style {
+func (tm *ThemeManager) getButtonStyle(theme *Theme, variant string, profile ColorProfile) lipgloss.Style {
 	var btnVariant ButtonVariant
 
 	switch variant {
@@ -302,140 +314,140 @@ func (tm *ThemeManager) getButtonStyle(theme *Theme, variant string) lipgloss.St
 	}
 
 	return lipgloss.NewStyle().
-		Background(lipgloss.Color(btnVariant.Background.Hex)).
-		Foreground(lipgloss.Color(btnVariant.Text.Hex)).
+		Background(tm.terminalColor(btnVariant.Background.Hex, profile)).
+		Foreground(tm.terminalColor(tm.legibleText(theme, btnVariant.Text, btnVariant.Background).Hex, profile)).
 		Border(lipgloss.NormalBorder()).
-		BorderForeground(lipgloss.Color(btnVariant.Border.Hex)).
+		BorderForeground(tm.terminalColor(btnVariant.Border.Hex, profile)).
 		Padding(0, 2)
 }
 
 // getTableStyle returns table styling
-func (tm *ThemeManager) getTableStyle(theme *Theme, variant string) lipgloss.Style {
+func (tm *ThemeManager) getTableStyle(theme *Theme, variant string, profile ColorProfile) lipgloss.Style {
 	table := theme.Components.Table
 
 	switch variant {
 	case "header":
 		return lipgloss.NewStyle().
-			Background(lipgloss.Color(table.HeaderBackground.Hex)).
-			Foreground(lipgloss.Color(table.HeaderText.Hex)).
+			Background(tm.terminalColor(table.HeaderBackground.Hex, profile)).
+			Foreground(tm.terminalColor(tm.legibleText(theme, table.HeaderText, table.HeaderBackground).Hex, profile)).
 			Bold(true).
 			Padding(0, 1)
 	case "row":
 		return lipgloss.NewStyle().
-			Background(lipgloss.Color(table.RowBackground.Hex)).
-			Foreground(lipgloss.Color(table.RowText.Hex)).
+			Background(tm.terminalColor(table.RowBackground.Hex, profile)).
+			Foreground(tm.terminalColor(tm.legibleText(theme, table.RowText, table.RowBackground).Hex, profile)).
 			Padding(0, 1)
 	case "row_alt":
 		return lipgloss.NewStyle().
-			Background(lipgloss.Color(table.RowBackgroundAlt.Hex)).
-			Foreground(lipgloss.Color(table.RowText.Hex)).
+			Background(tm.terminalColor(table.RowBackgroundAlt.Hex, profile)).
+			Foreground(tm.terminalColor(tm.legibleText(theme, table.RowText, table.RowBackgroundAlt).Hex, profile)).
 			Padding(0, 1)
 	case "selected":
 		return lipgloss.NewStyle().
-			Background(lipgloss.Color(table.SelectedRow.Hex)).
-			Foreground(lipgloss.Color(table.RowText.Hex)).
+			Background(tm.terminalColor(table.SelectedRow.Hex, profile)).
+			Foreground(tm.terminalColor(tm.legibleText(theme, table.RowText, table.SelectedRow).Hex, profile)).
 			Padding(0, 1)
 	default:
 		return lipgloss.NewStyle().
 			Border(lipgloss.NormalBorder()).
-			BorderForeground(lipgloss.Color(table.Border.Hex))
+			BorderForeground(tm.terminalColor(table.Border.Hex, profile))
 	}
 }
 
 // getModalStyle returns modal styling
-func (tm *ThemeManager) getModalStyle(theme *Theme) lipgloss.Style {
+func (tm *ThemeManager) getModalStyle(theme *Theme, profile ColorProfile) lipgloss.Style {
 	modal := theme.Components.Modal
 
 	return lipgloss.NewStyle().
-		Background(lipgloss.Color(modal.Background.Hex)).
+		Background(tm.terminalColor(modal.Background.Hex, profile)).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color(modal.Border.Hex)).
+		BorderForeground(tm.terminalColor(modal.Border.Hex, profile)).
 		Padding(2, 4)
 }
 
 // getInputStyle returns input styling
-func (tm *ThemeManager) getInputStyle(theme *Theme, variant string) lipgloss.Style {
+func (tm *ThemeManager) getInputStyle(theme *Theme, variant string, profile ColorProfile) lipgloss.Style {
 	input := theme.Components.Input
 
 	style := lipgloss.NewStyle().
-		Background(lipgloss.Color(input.Background.Hex)).
-		Foreground(lipgloss.Color(input.Text.Hex)).
+		Background(tm.terminalColor(input.Background.Hex, profile)).
+		Foreground(tm.terminalColor(tm.legibleText(theme, input.Text, input.Background).Hex, profile)).
 		Border(lipgloss.NormalBorder()).
 		Padding(0, 1)
 
 	switch variant {
 	case "focus":
-		style = style.BorderForeground(lipgloss.Color(input.BorderFocus.Hex))
+		style = style.BorderForeground(tm.terminalColor(input.BorderFocus.Hex, profile))
 	case "error":
-		style = style.BorderForeground(lipgloss.Color(input.BorderError.Hex))
+		style = style.BorderForeground(tm.terminalColor(input.BorderError.Hex, profile))
 	default:
-		style = style.BorderForeground(lipgloss.Color(input.Border.Hex))
+		style = style.BorderForeground(tm.terminalColor(input.Border.Hex, profile))
 	}
 
 	return style
 }
 
 // getNavigationStyle returns navigation styling
-func (tm *ThemeManager) getNavigationStyle(theme *Theme, variant string) lipgloss.Style {
+func (tm *ThemeManager) getNavigationStyle(theme *Theme, variant string, profile ColorProfile) lipgloss.Style {
 	nav := theme.Components.Navigation
 
 	switch variant {
 	case "active":
 		return lipgloss.NewStyle().
-			Background(lipgloss.Color(nav.Background.Hex)).
-			Foreground(lipgloss.Color(nav.TextActive.Hex)).
+			Background(tm.terminalColor(nav.Background.Hex, profile)).
+			Foreground(tm.terminalColor(tm.legibleText(theme, nav.TextActive, nav.Background).Hex, profile)).
 			Bold(true).
 			Padding(0, 2)
 	case "hover":
 		return lipgloss.NewStyle().
-			Background(lipgloss.Color(nav.Background.Hex)).
-			Foreground(lipgloss.Color(nav.TextHover.Hex)).
+			Background(tm.terminalColor(nav.Background.Hex, profile)).
+			Foreground(tm.terminalColor(tm.legibleText(theme, nav.TextHover, nav.Background).Hex, profile)).
 			Padding(0, 2)
 	default:
 		return lipgloss.NewStyle().
-			Background(lipgloss.Color(nav.Background.Hex)).
-			Foreground(lipgloss.Color(nav.Text.Hex)).
+			Background(tm.terminalColor(nav.Background.Hex, profile)).
+			Foreground(tm.terminalColor(tm.legibleText(theme, nav.Text, nav.Background).Hex, profile)).
 			Padding(0, 2)
 	}
 }
 
 // getStatusCardStyle returns status card styling
-func (tm *ThemeManager) getStatusCardStyle(theme *Theme) lipgloss.Style {
+func (tm *ThemeManager) getStatusCardStyle(theme *Theme, profile ColorProfile) lipgloss.Style {
 	card := theme.Components.StatusCard
 
 	return lipgloss.NewStyle().
-		Background(lipgloss.Color(card.Background.Hex)).
+		Background(tm.terminalColor(card.Background.Hex, profile)).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color(card.Border.Hex)).
+		BorderForeground(tm.terminalColor(card.Border.Hex, profile)).
 		Padding(1, 2)
 }
 
 // getProgressBarStyle returns progress bar styling
-func (tm *ThemeManager) getProgressBarStyle(theme *Theme) lipgloss.Style {
+func (tm *ThemeManager) getProgressBarStyle(theme *Theme, profile ColorProfile) lipgloss.Style {
 	bar := theme.Components.ProgressBar
 
 	return lipgloss.NewStyle().
-		Background(lipgloss.Color(bar.Background.Hex)).
-		Foreground(lipgloss.Color(bar.Fill.Hex))
+		Background(tm.terminalColor(bar.Background.Hex, profile)).
+		Foreground(tm.terminalColor(bar.Fill.Hex, profile))
 }
 
 // getNotificationStyle returns notification styling
-func (tm *ThemeManager) getNotificationStyle(theme *Theme) lipgloss.Style {
+func (tm *ThemeManager) getNotificationStyle(theme *Theme, profile ColorProfile) lipgloss.Style {
 	notif := theme.Components.Notification
 
 	return lipgloss.NewStyle().
-		Background(lipgloss.Color(notif.Background.Hex)).
-		Foreground(lipgloss.Color(notif.Text.Hex)).
+		Background(tm.terminalColor(notif.Background.Hex, profile)).
+		Foreground(tm.terminalColor(tm.legibleText(theme, notif.Text, notif.Background).Hex, profile)).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color(notif.Border.Hex)).
+		BorderForeground(tm.terminalColor(notif.Border.Hex, profile)).
 		Padding(1, 2)
 }
 
 // getBaseStyle returns base styling
-func (tm *ThemeManager) getBaseStyle(theme *Theme) lipgloss.Style {
+func (tm *ThemeManager) getBaseStyle(theme *Theme, profile ColorProfile) lipgloss.Style {
 	return lipgloss.NewStyle().
-		Background(lipgloss.Color(theme.Palette.Background.Hex)).
-		Foreground(lipgloss.Color(theme.Palette.TextPrimary.Hex))
+		Background(tm.terminalColor(theme.Palette.Background.Hex, profile)).
+		Foreground(tm.terminalColor(tm.legibleText(theme, theme.Palette.TextPrimary, theme.Palette.Background).Hex, profile))
 }
 
 // OnThemeChange registers a callback for theme changes
@@ -720,21 +732,9 @@ func (ac *AccessibilityChecker) CheckAccessibility(theme *Theme) (*Accessibility
 	}
 
 	// Check contrast ratios for critical color combinations
-	criticalChecks := []struct {
-		fg   Color
-		bg   Color
-		name string
-	}{
-		{theme.Palette.TextPrimary, theme.Palette.Background, "primary_text_background"},
-		{theme.Palette.TextSecondary, theme.Palette.Background, "secondary_text_background"},
-		{theme.Components.Button.Primary.Text, theme.Components.Button.Primary.Background, "primary_button"},
-		{theme.Components.Table.HeaderText, theme.Components.Table.HeaderBackground, "table_header"},
-		{theme.Components.Input.Text, theme.Components.Input.Background, "input_field"},
-	}
-
 	minRatio := 21.0 // Track minimum ratio
-	for _, check := range criticalChecks {
-		ratio, err := ac.colorUtils.ContrastRatio(check.fg, check.bg)
+	for _, check := range criticalPairs(theme) {
+		ratio, err := ac.colorUtils.ContrastRatio(*check.fg, *check.bg)
 		if err != nil {
 			continue
 		}
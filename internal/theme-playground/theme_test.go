@@ -170,6 +170,7 @@ func TestThemeManager_ListThemes(t *testing.T) {
 }
 
 func TestThemeManager_GetStyleFor(t *testing.T) {
+	setTerminalEnv(t, "truecolor", "xterm-256color", "")
 	tm := NewThemeManager(t.TempDir())
 
 	style := tm.GetStyleFor("text", "primary")
@@ -308,6 +308,7 @@ var (
 	ErrThemeInvalid       = NewThemeError("THEME_INVALID", "theme validation failed")
 	ErrThemeExists        = NewThemeError("THEME_EXISTS", "theme already exists")
 	ErrColorInvalid       = NewThemeError("COLOR_INVALID", "invalid color format")
+	ErrOverrideInvalid    = NewThemeError("OVERRIDE_INVALID", "unknown style override key")
 	ErrAccessibilityFail  = NewThemeError("ACCESSIBILITY_FAIL", "accessibility check failed")
 	ErrPersistenceFail    = NewThemeError("PERSISTENCE_FAIL", "failed to save/load theme")
 	ErrPlaygroundInactive = NewThemeError("PLAYGROUND_INACTIVE", "playground not running")
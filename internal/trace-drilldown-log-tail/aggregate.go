@@ -0,0 +1,213 @@
+// Copyright 2025 James Ross
+package tracedrilldownlogtail
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Dimensions Aggregate can group log counts by.
+const (
+	AggregateByLevel  = "level"
+	AggregateByQueue  = "queue"
+	AggregateByWorker = "worker"
+	AggregateBySource = "source"
+)
+
+// aggregateDimensions reads each group-by dimension from an entry.
+var aggregateDimensions = map[string]func(*LogEntry) string{
+	AggregateByLevel:  func(e *LogEntry) string { return strings.ToLower(e.Level) },
+	AggregateByQueue:  func(e *LogEntry) string { return e.QueueName },
+	AggregateByWorker: func(e *LogEntry) string { return e.WorkerID },
+	AggregateBySource: func(e *LogEntry) string { return e.Source },
+}
+
+// DefaultAggregateBucket is the bucket width Aggregate uses when none is
+// given.
+const DefaultAggregateBucket = time.Minute
+
+// maxAggregateEntries caps how many stored entries one Aggregate call reads,
+// so a wide range cannot scan the whole store. A result that hit it is
+// marked Truncated and only counts the oldest entries.
+var maxAggregateEntries = 200000
+
+// maxAggregateBuckets caps how many buckets a range may be split into,
+// bounding the size of every series.
+const maxAggregateBuckets = 10000
+
+// Aggregate counts the entries matching filter in fixed time buckets,
+// grouped by the groupBy dimensions (level, queue, worker, source). It
+// returns one series per combination of values seen, each with a point for
+// every bucket in the range, plus the totals across all groups. With no
+// groupBy there is a single series. The range defaults to the last 24 hours
+// as in SearchLogs and may span any number of day buckets; buckets are
+// aligned to multiples of bucket, so the first may start before
+// filter.StartTime. filter.MaxResults is ignored.
+func (lt *LogTailer) Aggregate(ctx context.Context, filter *LogFilter, bucket time.Duration, groupBy []string) (*AggregationResult, error) {
+	if filter == nil {
+		filter = &LogFilter{}
+	}
+	if bucket <= 0 {
+		bucket = DefaultAggregateBucket
+	}
+	dims, err := normalizeGroupBy(groupBy)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end, numBuckets, err := aggregateBounds(filter, bucket)
+	if err != nil {
+		return nil, err
+	}
+	first := start.Truncate(bucket)
+
+	result := &AggregationResult{
+		Start:   first,
+		End:     end,
+		Bucket:  bucket,
+		GroupBy: dims,
+		Totals:  make([]AggregationPoint, numBuckets),
+	}
+	for i := range result.Totals {
+		result.Totals[i].Time = first.Add(time.Duration(i) * bucket)
+	}
+	series := make(map[string]*AggregationSeries)
+
+	count := func(entry *LogEntry) {
+		i := int(entry.Timestamp.Sub(first) / bucket)
+		if i < 0 || i >= numBuckets {
+			return
+		}
+		values := make([]string, len(dims))
+		for d, dim := range dims {
+			values[d] = aggregateDimensions[dim](entry)
+		}
+		key := strings.Join(values, "/")
+		s, ok := series[key]
+		if !ok {
+			s = &AggregationSeries{Key: key, Group: make(map[string]string, len(dims))}
+			for d, dim := range dims {
+				s.Group[dim] = values[d]
+			}
+			s.Points = make([]AggregationPoint, numBuckets)
+			for p := range s.Points {
+				s.Points[p].Time = result.Totals[p].Time
+			}
+			series[key] = s
+		}
+		s.Points[i].Count++
+		s.Total++
+		result.Totals[i].Count++
+		result.Total++
+	}
+
+	if err := lt.aggregateRange(ctx, filter, start, end, result, count); err != nil {
+		return nil, err
+	}
+
+	result.Series = make([]AggregationSeries, 0, len(series))
+	for _, s := range series {
+		result.Series = append(result.Series, *s)
+	}
+	sort.Slice(result.Series, func(i, j int) bool {
+		if result.Series[i].Total != result.Series[j].Total {
+			return result.Series[i].Total > result.Series[j].Total
+		}
+		return result.Series[i].Key < result.Series[j].Key
+	})
+	return result, nil
+}
+
+// aggregateRange passes every entry stamped between start and end that
+// matches filter to count, reading the day buckets a page at a time. It
+// stops early, marking result Truncated, once maxAggregateEntries have been
+// read.
+func (lt *LogTailer) aggregateRange(ctx context.Context, filter *LogFilter, start, end time.Time, result *AggregationResult, count func(*LogEntry)) error {
+	from := start.Local()
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.Local)
+	min := strconv.FormatInt(start.UnixNano(), 10)
+	max := strconv.FormatInt(end.UnixNano(), 10)
+
+	for ; !day.After(end); day = day.AddDate(0, 0, 1) {
+		key := logDayKey(day)
+		for offset := int64(0); ; offset += exportPageSize {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			page, err := lt.redis.ZRangeByScore(ctx, key, &redis.ZRangeBy{
+				Min:    min,
+				Max:    max,
+				Offset: offset,
+				Count:  exportPageSize,
+			}).Result()
+			if err != nil {
+				return err
+			}
+			for _, logData := range page {
+				if result.Scanned >= maxAggregateEntries {
+					result.Truncated = true
+					return nil
+				}
+				result.Scanned++
+				var entry LogEntry
+				if err := json.Unmarshal([]byte(logData), &entry); err != nil {
+					continue
+				}
+				if lt.matchesLogFilter(&entry, filter) {
+					count(&entry)
+				}
+			}
+			if len(page) < exportPageSize {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// aggregateBounds resolves the range Aggregate covers and how many buckets
+// of the given width it spans, rejecting inverted or oversized ranges.
+func aggregateBounds(filter *LogFilter, bucket time.Duration) (start, end time.Time, numBuckets int, err error) {
+	end = filter.EndTime
+	if end.IsZero() {
+		end = time.Now()
+	}
+	start = filter.StartTime
+	if start.IsZero() {
+		start = end.Add(-24 * time.Hour)
+	}
+	if end.Before(start) {
+		return start, end, 0, fmt.Errorf("aggregation range ends before it starts")
+	}
+	numBuckets = int(end.Sub(start.Truncate(bucket))/bucket) + 1
+	if numBuckets > maxAggregateBuckets {
+		return start, end, 0, fmt.Errorf("range of %s in %s buckets exceeds %d buckets", end.Sub(start), bucket, maxAggregateBuckets)
+	}
+	return start, end, numBuckets, nil
+}
+
+// normalizeGroupBy lowercases and validates the group-by dimensions,
+// dropping duplicates.
+func normalizeGroupBy(groupBy []string) ([]string, error) {
+	dims := make([]string, 0, len(groupBy))
+	seen := make(map[string]bool, len(groupBy))
+	for _, dim := range groupBy {
+		dim = strings.ToLower(strings.TrimSpace(dim))
+		if dim == "" || seen[dim] {
+			continue
+		}
+		if _, ok := aggregateDimensions[dim]; !ok {
+			return nil, fmt.Errorf("unsupported group by %q (want %s, %s, %s or %s)", dim, AggregateByLevel, AggregateByQueue, AggregateByWorker, AggregateBySource)
+		}
+		seen[dim] = true
+		dims = append(dims, dim)
+	}
+	return dims, nil
+}
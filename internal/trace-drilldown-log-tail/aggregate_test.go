@@ -0,0 +1,87 @@
+// Copyright 2025 James Ross
+package tracedrilldownlogtail
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAggregateGroupsAcrossDays(t *testing.T) {
+	lt := newTestLogTailer(t)
+	now := time.Now()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	entries := []LogEntry{
+		{Timestamp: midnight.Add(-90 * time.Second), Level: "ERROR", QueueName: "emails"},
+		{Timestamp: midnight.Add(-80 * time.Second), Level: "error", QueueName: "emails"},
+		{Timestamp: midnight.Add(-70 * time.Second), Level: "info", QueueName: "emails"},
+		{Timestamp: midnight.Add(10 * time.Second), Level: "error", QueueName: "emails"},
+		{Timestamp: midnight.Add(20 * time.Second), Level: "error", QueueName: "reports"},
+		{Timestamp: midnight.Add(5 * time.Minute), Level: "error", QueueName: "reports"},
+	}
+	for i := range entries {
+		if err := lt.WriteLog(&entries[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	filter := &LogFilter{
+		StartTime: midnight.Add(-2 * time.Minute),
+		EndTime:   midnight.Add(2 * time.Minute),
+		Levels:    []string{"error"},
+	}
+	result, err := lt.Aggregate(context.Background(), filter, time.Minute, []string{"Queue", "level", "queue"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.GroupBy) != 2 || result.GroupBy[0] != AggregateByQueue {
+		t.Fatalf("expected group by [queue level], got %v", result.GroupBy)
+	}
+	if result.Total != 4 || len(result.Totals) != 5 {
+		t.Fatalf("expected 4 entries in 5 buckets, got %d in %d", result.Total, len(result.Totals))
+	}
+	if len(result.Series) != 2 {
+		t.Fatalf("expected 2 series, got %+v", result.Series)
+	}
+	emails := result.Series[0]
+	if emails.Key != "emails/error" || emails.Group["level"] != "error" || emails.Total != 3 {
+		t.Fatalf("unexpected first series %+v", emails)
+	}
+	// midnight-2m, -1m, 0, +1m, +2m
+	want := []int64{2, 0, 1, 0, 0}
+	for i, p := range emails.Points {
+		if p.Count != want[i] {
+			t.Fatalf("bucket %d at %s: expected %d, got %d", i, p.Time, want[i], p.Count)
+		}
+	}
+	if !emails.Points[2].Time.Equal(midnight) || result.Totals[2].Count != 2 {
+		t.Errorf("expected both queues counted in the midnight bucket, got %+v", result.Totals[2])
+	}
+
+	if _, err := lt.Aggregate(context.Background(), filter, time.Minute, []string{"trace"}); err == nil {
+		t.Error("expected an unknown group by to be rejected")
+	}
+	if _, err := lt.Aggregate(context.Background(), filter, time.Millisecond, nil); err == nil {
+		t.Error("expected too many buckets to be rejected")
+	}
+}
+
+func TestAggregateCapsScannedEntries(t *testing.T) {
+	lt := newTestLogTailer(t)
+	writeExportLogs(t, lt, time.Now().Add(-time.Minute), 10)
+
+	old := maxAggregateEntries
+	maxAggregateEntries = 4
+	defer func() { maxAggregateEntries = old }()
+
+	result, err := lt.Aggregate(context.Background(), nil, time.Hour, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Truncated || result.Scanned != 4 || result.Total != 4 {
+		t.Fatalf("expected 4 of 10 entries counted and truncation flagged, got %+v", result)
+	}
+	if len(result.Series) != 1 || result.Series[0].Key != "" || len(result.Series[0].Group) != 0 {
+		t.Fatalf("expected a single ungrouped series, got %+v", result.Series)
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2025 James Ross
+package tracedrilldownlogtail
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gorilla/mux"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+func newCorrelationFixture(t *testing.T, cfg *TracingConfig) (*TraceManager, *LogTailer) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	lt := NewLogTailer(&LoggingConfig{Enabled: true, RetentionPeriod: 30 * 24 * time.Hour}, rdb, zap.NewNop())
+	t.Cleanup(func() {
+		lt.Shutdown()
+		_ = rdb.Close()
+	})
+	return NewTraceManager(cfg, rdb, zap.NewNop()), lt
+}
+
+func TestGetCorrelatedLogs(t *testing.T) {
+	tm, lt := newCorrelationFixture(t, &TracingConfig{Enabled: true, SamplingRate: 1.0})
+	base := time.Now().Add(-time.Minute)
+	entries := []LogEntry{
+		{Timestamp: base.Add(2 * time.Second), Level: "info", Message: "second", TraceID: "t1"},
+		{Timestamp: base, Level: "info", Message: "first", TraceID: "t1"},
+		{Timestamp: base.Add(time.Second), Level: "info", Message: "other trace", TraceID: "t2"},
+		{Timestamp: base.Add(3 * time.Second), Level: "info", Message: "untraced"},
+	}
+	for i := range entries {
+		if err := lt.WriteLog(&entries[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	logs, err := tm.GetCorrelatedLogs(context.Background(), "t1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 2 || logs[0].Message != "first" || logs[1].Message != "second" {
+		t.Fatalf("expected the two t1 lines in order, got %+v", logs)
+	}
+	for _, entry := range logs {
+		if entry.TraceLink == nil || entry.TraceLink.URL != "/api/v1/trace-drilldown/traces/t1" {
+			t.Fatalf("expected a drilldown link to t1, got %+v", entry.TraceLink)
+		}
+	}
+
+	if logs, err := tm.GetCorrelatedLogs(context.Background(), "missing"); err != nil || len(logs) != 0 {
+		t.Fatalf("expected no logs for an unknown trace, got %d, %v", len(logs), err)
+	}
+}
+
+func TestLinkLogsUsesURLTemplate(t *testing.T) {
+	tm, _ := newCorrelationFixture(t, &TracingConfig{
+		Enabled:     true,
+		Provider:    "jaeger",
+		URLTemplate: "https://jaeger.example/trace/{trace_id}",
+	})
+	logs := []LogEntry{{TraceID: "abc"}, {Message: "no trace"}}
+	tm.LinkLogs(logs)
+
+	if logs[0].TraceLink == nil || logs[0].TraceLink.URL != "https://jaeger.example/trace/abc" {
+		t.Fatalf("expected the jaeger link, got %+v", logs[0].TraceLink)
+	}
+	if logs[1].TraceLink != nil {
+		t.Fatal("entries without a trace ID should not be linked")
+	}
+}
+
+func TestSpanSummaryCountsLogsPerSpan(t *testing.T) {
+	tm, lt := newCorrelationFixture(t, &TracingConfig{Enabled: true, SamplingRate: 1.0})
+	traceCtx, ctx := tm.StartTrace(context.Background(), "job")
+	processCtx, endProcess := tm.StartSpan(ctx, "process")
+	processSpan := tm.getTraceContext(processCtx).SpanID
+	endProcess("ok")
+	tm.EndTrace(ctx, "ok")
+
+	base := time.Now()
+	for i, spanID := range []string{processSpan, processSpan, "", "unknown"} {
+		entry := LogEntry{Timestamp: base.Add(time.Duration(i) * time.Millisecond), Level: "info", TraceID: traceCtx.TraceID, SpanID: spanID}
+		if err := lt.WriteLog(&entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	summary, err := tm.GetSpanSummary(ctx, traceCtx.TraceID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.LogCount != 4 {
+		t.Fatalf("expected 4 correlated lines, got %d", summary.LogCount)
+	}
+	if summary.SpanLogCounts[processSpan] != 2 || summary.SpanLogCounts[traceCtx.SpanID] != 2 {
+		t.Fatalf("unexpected per-span counts: %v", summary.SpanLogCounts)
+	}
+	if summary.Tree.LogCount != 2 || summary.Tree.Children[0].LogCount != 2 {
+		t.Fatalf("expected counts on tree nodes, got root=%d process=%d", summary.Tree.LogCount, summary.Tree.Children[0].LogCount)
+	}
+}
+
+func TestHandleGetTraceLogs(t *testing.T) {
+	tm, lt := newCorrelationFixture(t, &TracingConfig{Enabled: true, SamplingRate: 1.0})
+	if err := lt.WriteLog(&LogEntry{Level: "info", Message: "hello", TraceID: "t1"}); err != nil {
+		t.Fatal(err)
+	}
+	router := mux.NewRouter()
+	NewHTTPHandlers(tm, lt, zap.NewNop()).RegisterRoutes(router)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/trace-drilldown/traces/t1/logs", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+	}
+	var body struct {
+		Count int        `json:"count"`
+		Logs  []LogEntry `json:"logs"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Count != 1 || body.Logs[0].TraceLink == nil {
+		t.Fatalf("expected one linked line, got %+v", body)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/trace-drilldown/logs/search", strings.NewReader(`{"trace_ids":["t1"]}`)))
+	if !strings.Contains(w.Body.String(), `"trace_link"`) {
+		t.Fatalf("search results should link to their trace: %s", w.Body.String())
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/flyingrobots/go-redis-work-queue/internal/admin"
 	"github.com/flyingrobots/go-redis-work-queue/internal/config"
@@ -218,10 +219,18 @@ func (ea *EnhancedAdmin) SearchJobsByTrace(ctx context.Context, cfg *config.Conf
 	}
 	queues = append(queues, cfg.Worker.CompletedList, cfg.Worker.DeadLetterList)
 
-	// Search processing lists
-	processingKeys, err := rdb.Keys(ctx, "jobqueue:worker:*:processing").Result()
-	if err == nil {
-		queues = append(queues, processingKeys...)
+	// Search processing lists, scanning only this config's namespace
+	match := strings.Replace(cfg.Worker.ProcessingListPattern, "%s", "*", 1)
+	var cursor uint64
+	for {
+		keys, next, err := rdb.Scan(ctx, cursor, match, 500).Result()
+		if err != nil {
+			break
+		}
+		queues = append(queues, keys...)
+		if cursor = next; cursor == 0 {
+			break
+		}
 	}
 
 	for _, queue := range queues {
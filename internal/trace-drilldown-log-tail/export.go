@@ -0,0 +1,267 @@
+// Copyright 2025 James Ross
+package tracedrilldownlogtail
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Log export formats accepted by ExportLogs and ExportLogsFollow.
+const (
+	ExportFormatNDJSON = "ndjson"
+	ExportFormatCSV    = "csv"
+)
+
+// exportContentTypes maps each export format to its HTTP content type.
+var exportContentTypes = map[string]string{
+	ExportFormatNDJSON: "application/x-ndjson",
+	ExportFormatCSV:    "text/csv",
+}
+
+// exportPageSize is how many entries an export reads from a day bucket per
+// round trip, bounding memory regardless of the time range.
+const exportPageSize = 500
+
+// exportPollInterval is how often ExportLogsFollow checks for new entries.
+var exportPollInterval = 500 * time.Millisecond
+
+// csvExportHeader is the first row of a CSV export. Fields is the entry's
+// fields as a JSON object.
+var csvExportHeader = []string{
+	"timestamp", "level", "source", "message", "job_id", "worker_id",
+	"queue_name", "trace_id", "span_id", "fields", "stack_trace",
+}
+
+// normalizeExportFormat validates format, defaulting an empty one to NDJSON.
+func normalizeExportFormat(format string) (string, error) {
+	format = strings.ToLower(format)
+	if format == "" || format == "jsonl" {
+		format = ExportFormatNDJSON
+	}
+	if _, ok := exportContentTypes[format]; !ok {
+		return "", fmt.Errorf("unsupported export format %q (want %s or %s)", format, ExportFormatNDJSON, ExportFormatCSV)
+	}
+	return format, nil
+}
+
+// logEncoder writes exported entries. flush pushes buffered output to the
+// underlying writer; exports flush after every page so a reader sees
+// progress.
+type logEncoder interface {
+	encode(entry *LogEntry) error
+	flush() error
+}
+
+type ndjsonEncoder struct {
+	buf *bufio.Writer
+	enc *json.Encoder
+}
+
+func (e *ndjsonEncoder) encode(entry *LogEntry) error { return e.enc.Encode(entry) }
+func (e *ndjsonEncoder) flush() error                 { return e.buf.Flush() }
+
+type csvEncoder struct {
+	w *csv.Writer
+}
+
+func (e *csvEncoder) encode(entry *LogEntry) error {
+	fields := ""
+	if len(entry.Fields) > 0 {
+		data, err := json.Marshal(entry.Fields)
+		if err != nil {
+			return err
+		}
+		fields = string(data)
+	}
+	return e.w.Write([]string{
+		entry.Timestamp.Format(time.RFC3339Nano), entry.Level, entry.Source, entry.Message,
+		entry.JobID, entry.WorkerID, entry.QueueName, entry.TraceID, entry.SpanID,
+		fields, entry.StackTrace,
+	})
+}
+
+func (e *csvEncoder) flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// newLogEncoder returns an encoder for format, writing the CSV header
+// straight away so an empty export is still a valid file.
+func newLogEncoder(w io.Writer, format string) (logEncoder, error) {
+	format, err := normalizeExportFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	if format == ExportFormatCSV {
+		enc := &csvEncoder{w: csv.NewWriter(w)}
+		if err := enc.w.Write(csvExportHeader); err != nil {
+			return nil, err
+		}
+		return enc, nil
+	}
+	buf := bufio.NewWriter(w)
+	return &ndjsonEncoder{buf: buf, enc: json.NewEncoder(buf)}, nil
+}
+
+// ExportLogs writes every entry matching filter to w as NDJSON (one JSON
+// LogEntry per line) or CSV, in timestamp order, and returns how many it
+// wrote. Entries are read a page at a time, so large ranges are streamed
+// rather than held in memory. As with SearchLogs, the range defaults to the
+// last 24 hours and filter.MaxResults caps the export when set.
+func (lt *LogTailer) ExportLogs(ctx context.Context, filter *LogFilter, w io.Writer, format string) (int, error) {
+	enc, err := newLogEncoder(w, format)
+	if err != nil {
+		return 0, err
+	}
+	if filter == nil {
+		filter = &LogFilter{}
+	}
+
+	end := filter.EndTime
+	if end.IsZero() {
+		end = time.Now()
+	}
+	start := filter.StartTime
+	if start.IsZero() {
+		start = end.Add(-24 * time.Hour)
+	}
+
+	n, err := lt.exportRange(ctx, filter, enc, start, end, 0)
+	if ferr := enc.flush(); err == nil {
+		err = ferr
+	}
+	return n, err
+}
+
+// ExportLogsFollow is ExportLogs that keeps going: after writing the entries
+// from filter.StartTime until now it polls for new ones and writes them as
+// they arrive, until ctx is cancelled or the tailer shuts down, which end
+// the export without an error. filter.EndTime is ignored; without a
+// StartTime only new entries are written.
+func (lt *LogTailer) ExportLogsFollow(ctx context.Context, filter *LogFilter, w io.Writer, format string) (int, error) {
+	enc, err := newLogEncoder(w, format)
+	if err != nil {
+		return 0, err
+	}
+	if filter == nil {
+		filter = &LogFilter{}
+	}
+
+	now := time.Now()
+	n := 0
+	if !filter.StartTime.IsZero() {
+		if n, err = lt.exportRange(ctx, filter, enc, filter.StartTime, now, 0); err != nil {
+			return n, ignoreCancel(ctx, err)
+		}
+	}
+	if err := enc.flush(); err != nil {
+		return n, err
+	}
+
+	ticker := time.NewTicker(exportPollInterval)
+	defer ticker.Stop()
+	cursor := now.UnixNano()
+	for filter.MaxResults <= 0 || n < filter.MaxResults {
+		select {
+		case <-ctx.Done():
+			return n, nil
+		case <-lt.stopCh:
+			return n, nil
+		case <-ticker.C:
+		}
+
+		entries, err := lt.fetchDayLogs(ctx, cursor, time.Now())
+		if err != nil {
+			return n, ignoreCancel(ctx, err)
+		}
+		for i := range entries {
+			if ts := entries[i].Timestamp.UnixNano(); ts > cursor {
+				cursor = ts
+			}
+			if !lt.matchesLogFilter(&entries[i], filter) {
+				continue
+			}
+			if err := enc.encode(&entries[i]); err != nil {
+				return n, err
+			}
+			n++
+			if filter.MaxResults > 0 && n >= filter.MaxResults {
+				break
+			}
+		}
+		if err := enc.flush(); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// exportRange encodes the entries stamped between start and end, inclusive,
+// that match filter, one day bucket and page at a time. n is the number
+// already written, counted against filter.MaxResults; the new total is
+// returned.
+func (lt *LogTailer) exportRange(ctx context.Context, filter *LogFilter, enc logEncoder, start, end time.Time, n int) (int, error) {
+	from := start.Local()
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.Local)
+	min := strconv.FormatInt(start.UnixNano(), 10)
+	max := strconv.FormatInt(end.UnixNano(), 10)
+
+	for ; !day.After(end); day = day.AddDate(0, 0, 1) {
+		key := logDayKey(day)
+		for offset := int64(0); ; offset += exportPageSize {
+			if err := ctx.Err(); err != nil {
+				return n, err
+			}
+			page, err := lt.redis.ZRangeByScore(ctx, key, &redis.ZRangeBy{
+				Min:    min,
+				Max:    max,
+				Offset: offset,
+				Count:  exportPageSize,
+			}).Result()
+			if err != nil {
+				return n, err
+			}
+			for _, logData := range page {
+				var entry LogEntry
+				if err := json.Unmarshal([]byte(logData), &entry); err != nil {
+					continue
+				}
+				if !lt.matchesLogFilter(&entry, filter) {
+					continue
+				}
+				if err := enc.encode(&entry); err != nil {
+					return n, err
+				}
+				n++
+				if filter.MaxResults > 0 && n >= filter.MaxResults {
+					return n, nil
+				}
+			}
+			if err := enc.flush(); err != nil {
+				return n, err
+			}
+			if len(page) < exportPageSize {
+				break
+			}
+		}
+	}
+	return n, nil
+}
+
+// ignoreCancel drops the error a follow export gets when its context ends
+// mid-request, which is how callers stop it.
+func ignoreCancel(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,186 @@
+// Copyright 2025 James Ross
+package tracedrilldownlogtail
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+func writeExportLogs(t *testing.T, lt *LogTailer, base time.Time, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		level := "info"
+		if i%2 == 1 {
+			level = "error"
+		}
+		entry := &LogEntry{
+			Timestamp: base.Add(time.Duration(i) * time.Millisecond),
+			Level:     level,
+			Message:   "line, \"quoted\"",
+			JobID:     "job-1",
+			Fields:    map[string]interface{}{"n": i},
+		}
+		if err := lt.WriteLog(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestExportLogsNDJSONStreamsPages(t *testing.T) {
+	lt := newTestLogTailer(t)
+	base := time.Now().Add(-time.Minute)
+	writeExportLogs(t, lt, base, 2*exportPageSize+10)
+
+	var buf bytes.Buffer
+	n, err := lt.ExportLogs(context.Background(), &LogFilter{Levels: []string{"error"}}, &buf, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != exportPageSize+5 {
+		t.Fatalf("expected %d error entries, got %d", exportPageSize+5, n)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != n {
+		t.Fatalf("expected %d lines, got %d", n, len(lines))
+	}
+	var prev time.Time
+	for _, line := range lines {
+		var entry LogEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("bad line %q: %v", line, err)
+		}
+		if entry.Level != "error" || entry.Timestamp.Before(prev) {
+			t.Fatalf("unexpected or out-of-order entry: %+v", entry)
+		}
+		prev = entry.Timestamp
+	}
+
+	buf.Reset()
+	if n, err := lt.ExportLogs(context.Background(), &LogFilter{MaxResults: 3}, &buf, ExportFormatNDJSON); err != nil || n != 3 {
+		t.Fatalf("expected MaxResults to cap the export at 3, got %d (err=%v)", n, err)
+	}
+}
+
+func TestExportLogsCSV(t *testing.T) {
+	lt := newTestLogTailer(t)
+	writeExportLogs(t, lt, time.Now().Add(-time.Minute), 2)
+
+	var buf bytes.Buffer
+	n, err := lt.ExportLogs(context.Background(), nil, &buf, ExportFormatCSV)
+	if err != nil || n != 2 {
+		t.Fatalf("expected 2 rows, got %d (err=%v)", n, err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || strings.Join(rows[0], ",") != strings.Join(csvExportHeader, ",") {
+		t.Fatalf("expected a header and 2 rows, got %v", rows)
+	}
+	if rows[2][1] != "error" || rows[2][3] != `line, "quoted"` || rows[2][4] != "job-1" || rows[2][9] != `{"n":1}` {
+		t.Fatalf("unexpected row: %v", rows[2])
+	}
+
+	if _, err := lt.ExportLogs(context.Background(), nil, &buf, "xml"); err == nil {
+		t.Fatal("expected an unsupported format error")
+	}
+}
+
+// lockedBuffer lets a test read what a follow export has written so far.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestExportLogsFollowWritesNewEntriesUntilCancel(t *testing.T) {
+	old := exportPollInterval
+	exportPollInterval = 10 * time.Millisecond
+	defer func() { exportPollInterval = old }()
+
+	lt := newTestLogTailer(t)
+	if err := lt.WriteLog(&LogEntry{Timestamp: time.Now().Add(-time.Minute), Level: "info", Message: "history"}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var out lockedBuffer
+	type result struct {
+		n   int
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		n, err := lt.ExportLogsFollow(ctx, &LogFilter{StartTime: time.Now().Add(-time.Hour), Levels: []string{"info"}}, &out, ExportFormatNDJSON)
+		done <- result{n, err}
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(out.String(), "history") && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	for _, entry := range []*LogEntry{{Level: "debug", Message: "skipped"}, {Level: "info", Message: "live"}} {
+		if err := lt.WriteLog(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for !strings.Contains(out.String(), "live") && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+
+	res := <-done
+	if res.err != nil || res.n != 2 {
+		t.Fatalf("expected 2 entries and no error, got %d (err=%v)\n%s", res.n, res.err, out.String())
+	}
+	if strings.Contains(out.String(), "skipped") {
+		t.Fatalf("filtered entry was exported: %s", out.String())
+	}
+}
+
+func TestHandleExportLogs(t *testing.T) {
+	lt := newTestLogTailer(t)
+	writeExportLogs(t, lt, time.Now().Add(-time.Minute), 4)
+	router := mux.NewRouter()
+	NewHTTPHandlers(NewTraceManager(nil, lt.redis, zap.NewNop()), lt, zap.NewNop()).RegisterRoutes(router)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/trace-drilldown/logs/export?format=csv", strings.NewReader(`{"levels":["error"]}`)))
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "text/csv" {
+		t.Fatalf("status = %d, content type %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(w.Header().Get("Content-Disposition"), ".csv") {
+		t.Fatalf("expected a csv attachment, got %q", w.Header().Get("Content-Disposition"))
+	}
+	if rows := strings.Count(w.Body.String(), "\n"); rows != 3 {
+		t.Fatalf("expected a header and 2 rows, got:\n%s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/trace-drilldown/logs/export?format=xml", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an unknown format, got %d", w.Code)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 James Ross
+package tracedrilldownlogtail
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+func newTestLogTailer(t *testing.T) *LogTailer {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	lt := NewLogTailer(&LoggingConfig{Enabled: true, RetentionPeriod: 30 * 24 * time.Hour}, rdb, zap.NewNop())
+	t.Cleanup(func() {
+		lt.Shutdown()
+		_ = rdb.Close()
+	})
+	return lt
+}
+
+// straddleMidnight writes job logs on both sides of the most recent local
+// midnight and returns that midnight.
+func straddleMidnight(t *testing.T, lt *LogTailer) time.Time {
+	t.Helper()
+	now := time.Now()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	entries := []LogEntry{
+		{Timestamp: midnight.Add(-2 * time.Second), Level: "info", Message: "started", JobID: "job-1"},
+		{Timestamp: midnight.Add(-time.Second), Level: "info", Message: "other job", JobID: "job-2"},
+		{Timestamp: midnight.Add(time.Second), Level: "info", Message: "finished", JobID: "job-1"},
+	}
+	for i := range entries {
+		if err := lt.WriteLog(&entries[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return midnight
+}
+
+func TestFetchNewLogsFollowJobAcrossDays(t *testing.T) {
+	lt := newTestLogTailer(t)
+	midnight := straddleMidnight(t, lt)
+
+	ctx := context.Background()
+	if n, _ := lt.redis.Exists(ctx, logDayKey(midnight.Add(-time.Second)), logDayKey(midnight)).Result(); n != 2 {
+		t.Fatalf("expected entries in two day buckets, got %d", n)
+	}
+
+	session := &TailSession{Config: TailConfig{FollowJobID: "job-1"}}
+	var cursor int64
+	logs, err := lt.fetchNewLogs(ctx, session, &cursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 2 || logs[0].Message != "started" || logs[1].Message != "finished" {
+		t.Fatalf("expected both job-1 entries in order, got %+v", logs)
+	}
+	if cursor != midnight.Add(time.Second).UnixNano() {
+		t.Fatalf("cursor not advanced to newest entry: %d", cursor)
+	}
+
+	// Nothing new since the cursor.
+	logs, err = lt.fetchNewLogs(ctx, session, &cursor)
+	if err != nil || len(logs) != 0 {
+		t.Fatalf("expected no new entries, got %d (err=%v)", len(logs), err)
+	}
+}
+
+func TestFetchNewLogsRollsOverMidnight(t *testing.T) {
+	lt := newTestLogTailer(t)
+	midnight := straddleMidnight(t, lt)
+
+	// A tail positioned just before midnight must pick up both buckets.
+	session := &TailSession{}
+	cursor := midnight.Add(-3 * time.Second).UnixNano()
+	logs, err := lt.fetchNewLogs(context.Background(), session, &cursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 3 || logs[2].Message != "finished" {
+		t.Fatalf("expected entries from both days, got %+v", logs)
+	}
+}
@@ -4,8 +4,10 @@ package tracedrilldownlogtail
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -35,13 +37,18 @@ func (h *HTTPHandlers) RegisterRoutes(router *mux.Router) {
 	// Trace operations
 	api.HandleFunc("/traces/{traceId}", h.handleGetTrace).Methods("GET")
 	api.HandleFunc("/traces/{traceId}/summary", h.handleGetTraceSummary).Methods("GET")
+	api.HandleFunc("/traces/{traceId}/breakdown", h.handleGetTraceBreakdown).Methods("GET")
 	api.HandleFunc("/traces/{traceId}/links", h.handleGetTraceLinks).Methods("GET")
+	api.HandleFunc("/traces/{traceId}/logs", h.handleGetTraceLogs).Methods("GET")
 	api.HandleFunc("/traces/{traceId}/open", h.handleOpenTrace).Methods("POST")
 	api.HandleFunc("/traces/search", h.handleSearchTraces).Methods("POST")
+	api.HandleFunc("/traces/import", h.handleImportTraces).Methods("POST")
 
 	// Log operations
 	api.HandleFunc("/logs/search", h.handleSearchLogs).Methods("POST")
 	api.HandleFunc("/logs/stats", h.handleGetLogStats).Methods("GET")
+	api.HandleFunc("/logs/export", h.handleExportLogs).Methods("POST")
+	api.HandleFunc("/logs/aggregate", h.handleAggregateLogs).Methods("POST")
 	api.HandleFunc("/logs/tail", h.handleStartTail).Methods("POST")
 	api.HandleFunc("/logs/tail/{sessionId}", h.handleStopTail).Methods("DELETE")
 	api.HandleFunc("/logs/tail/sessions", h.handleGetTailSessions).Methods("GET")
@@ -79,6 +86,18 @@ func (h *HTTPHandlers) handleGetTraceSummary(w http.ResponseWriter, r *http.Requ
 	h.writeJSON(w, http.StatusOK, summary)
 }
 
+func (h *HTTPHandlers) handleGetTraceBreakdown(w http.ResponseWriter, r *http.Request) {
+	traceID := mux.Vars(r)["traceId"]
+
+	breakdown, err := h.traceManager.GetSpanBreakdown(traceID)
+	if err != nil {
+		h.writeError(w, http.StatusNotFound, "Trace not found", err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, breakdown)
+}
+
 func (h *HTTPHandlers) handleGetTraceLinks(w http.ResponseWriter, r *http.Request) {
 	traceID := mux.Vars(r)["traceId"]
 
@@ -94,6 +113,22 @@ func (h *HTTPHandlers) handleGetTraceLinks(w http.ResponseWriter, r *http.Reques
 	})
 }
 
+func (h *HTTPHandlers) handleGetTraceLogs(w http.ResponseWriter, r *http.Request) {
+	traceID := mux.Vars(r)["traceId"]
+
+	logs, err := h.traceManager.GetCorrelatedLogs(r.Context(), traceID)
+	if err != nil {
+		h.writeError(w, http.StatusInternalServerError, "Failed to get trace logs", err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"trace_id": traceID,
+		"logs":     logs,
+		"count":    len(logs),
+	})
+}
+
 func (h *HTTPHandlers) handleOpenTrace(w http.ResponseWriter, r *http.Request) {
 	traceID := mux.Vars(r)["traceId"]
 
@@ -127,6 +162,33 @@ func (h *HTTPHandlers) handleSearchTraces(w http.ResponseWriter, r *http.Request
 	h.writeJSON(w, http.StatusOK, result)
 }
 
+// handleImportTraces pulls traces from the tracing backend. ?since= is an
+// RFC 3339 time or a duration back from now (1h by default).
+func (h *HTTPHandlers) handleImportTraces(w http.ResponseWriter, r *http.Request) {
+	since := time.Now().Add(-time.Hour)
+	if value := r.URL.Query().Get("since"); value != "" {
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			since = t
+		} else if d, err := time.ParseDuration(value); err == nil && d > 0 {
+			since = time.Now().Add(-d)
+		} else {
+			h.writeError(w, http.StatusBadRequest, "Invalid since", fmt.Errorf("want an RFC 3339 time or a duration, got %q", value))
+			return
+		}
+	}
+
+	imported, err := h.traceManager.ImportTraces(r.Context(), since)
+	if err != nil {
+		h.writeError(w, http.StatusBadGateway, "Import failed", err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"imported": imported,
+		"since":    since,
+	})
+}
+
 // Log handlers
 
 func (h *HTTPHandlers) handleSearchLogs(w http.ResponseWriter, r *http.Request) {
@@ -141,6 +203,87 @@ func (h *HTTPHandlers) handleSearchLogs(w http.ResponseWriter, r *http.Request)
 		h.writeError(w, http.StatusInternalServerError, "Search failed", err)
 		return
 	}
+	h.traceManager.LinkLogs(result.Logs)
+
+	h.writeJSON(w, http.StatusOK, result)
+}
+
+// handleExportLogs streams the logs matching the filter in the body as a
+// download. ?format= picks ndjson (default) or csv; ?follow=true keeps the
+// response open and writes new entries until the client disconnects.
+func (h *HTTPHandlers) handleExportLogs(w http.ResponseWriter, r *http.Request) {
+	var filter LogFilter
+	if err := json.NewDecoder(r.Body).Decode(&filter); err != nil && err != io.EOF {
+		h.writeError(w, http.StatusBadRequest, "Invalid filter", err)
+		return
+	}
+	format, err := normalizeExportFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid format", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", exportContentTypes[format])
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="logs-%s.%s"`, time.Now().UTC().Format("20060102T150405Z"), format))
+	out := io.Writer(w)
+	if f, ok := w.(http.Flusher); ok {
+		out = flushWriter{w: w, f: f}
+	}
+
+	export := h.logTailer.ExportLogs
+	if parseBoolQuery(r, "follow", false) {
+		export = h.logTailer.ExportLogsFollow
+	}
+	// The status is already sent, so a failure part way can only be logged.
+	if n, err := export(r.Context(), &filter, out, format); err != nil {
+		h.logger.Error("Log export failed", zap.Int("written", n), zap.Error(err))
+	}
+}
+
+// flushWriter flushes every write through to the client, so followed
+// exports arrive as they are encoded.
+type flushWriter struct {
+	w io.Writer
+	f http.Flusher
+}
+
+func (fw flushWriter) Write(p []byte) (int, error) {
+	n, err := fw.w.Write(p)
+	fw.f.Flush()
+	return n, err
+}
+
+// handleAggregateLogs counts the logs matching the filter in the body per
+// ?bucket= (a duration, 1m by default), grouped by the comma separated
+// ?group_by= dimensions.
+func (h *HTTPHandlers) handleAggregateLogs(w http.ResponseWriter, r *http.Request) {
+	var filter LogFilter
+	if err := json.NewDecoder(r.Body).Decode(&filter); err != nil && err != io.EOF {
+		h.writeError(w, http.StatusBadRequest, "Invalid filter", err)
+		return
+	}
+	var groupBy []string
+	if value := r.URL.Query().Get("group_by"); value != "" {
+		groupBy = strings.Split(value, ",")
+	}
+	if _, err := normalizeGroupBy(groupBy); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid group_by", err)
+		return
+	}
+	bucket := parseDurationQuery(r, "bucket", DefaultAggregateBucket)
+	if bucket <= 0 {
+		bucket = DefaultAggregateBucket
+	}
+	if _, _, _, err := aggregateBounds(&filter, bucket); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid range", err)
+		return
+	}
+
+	result, err := h.logTailer.Aggregate(r.Context(), &filter, bucket, groupBy)
+	if err != nil {
+		h.writeError(w, http.StatusInternalServerError, "Aggregation failed", err)
+		return
+	}
 
 	h.writeJSON(w, http.StatusOK, result)
 }
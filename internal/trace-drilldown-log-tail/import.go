@@ -0,0 +1,366 @@
+// Copyright 2025 James Ross
+package tracedrilldownlogtail
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// defaultImportLimit is how many traces one ImportTraces call asks the
+// backend for when import_limit is unset.
+const defaultImportLimit = 1000
+
+// TraceSource reads completed traces from an external tracing backend.
+// Implementations translate the backend's own format into TraceInfo, with
+// the trace's root span as the TraceInfo and every other span in Spans.
+type TraceSource interface {
+	FetchTraces(ctx context.Context, start, end time.Time, limit int) ([]TraceInfo, error)
+}
+
+// SetTraceSource replaces the source ImportTraces reads from, which
+// otherwise follows the configured provider.
+func (tm *TraceManager) SetTraceSource(source TraceSource) {
+	tm.mu.Lock()
+	tm.source = source
+	tm.mu.Unlock()
+}
+
+// traceSource returns the source set with SetTraceSource, or one for the
+// configured provider.
+func (tm *TraceManager) traceSource() (TraceSource, error) {
+	tm.mu.RLock()
+	source := tm.source
+	tm.mu.RUnlock()
+	if source != nil {
+		return source, nil
+	}
+	if tm.config.Endpoint == "" {
+		return nil, fmt.Errorf("no tracing endpoint configured")
+	}
+	switch strings.ToLower(tm.config.Provider) {
+	case "", "jaeger":
+		return &jaegerSource{
+			endpoint:  tm.config.Endpoint,
+			service:   tm.config.ServiceName,
+			authToken: tm.config.AuthToken,
+			client:    tm.httpClient,
+		}, nil
+	default:
+		return nil, fmt.Errorf("trace import not supported for provider %q", tm.config.Provider)
+	}
+}
+
+// ImportTraces pulls the traces completed in the backend since the given
+// time and caches them alongside locally recorded ones, so the drilldown
+// views can show traces that started in other services but touched our
+// jobs. Imported traces are stored and evicted like local ones; since is
+// clamped to trace_ttl ago, as older traces would expire on arrival.
+// Traces this process is still recording are left alone. It returns how
+// many traces were stored.
+func (tm *TraceManager) ImportTraces(ctx context.Context, since time.Time) (int, error) {
+	source, err := tm.traceSource()
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	if oldest := now.Add(-tm.traceTTL()); since.Before(oldest) {
+		since = oldest
+	}
+	limit := tm.config.ImportLimit
+	if limit <= 0 {
+		limit = defaultImportLimit
+	}
+
+	traces, err := source.FetchTraces(ctx, since, now, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch traces: %w", err)
+	}
+
+	imported := 0
+	for i := range traces {
+		trace := &traces[i]
+		if trace.TraceID == "" {
+			continue
+		}
+		tm.mu.RLock()
+		_, live := tm.traces[trace.TraceID]
+		tm.mu.RUnlock()
+		if live {
+			continue
+		}
+
+		data, err := json.Marshal(trace)
+		if err != nil {
+			continue
+		}
+		key := fmt.Sprintf("trace:%s", trace.TraceID)
+		if err := tm.redis.Set(ctx, key, string(data), tm.traceTTL()).Err(); err != nil {
+			return imported, fmt.Errorf("failed to store trace %s: %w", trace.TraceID, err)
+		}
+		tm.indexTrace(ctx, trace)
+		imported++
+	}
+
+	tm.logger.Info("Imported traces",
+		zap.Int("fetched", len(traces)),
+		zap.Int("imported", imported),
+		zap.Time("since", since))
+	return imported, nil
+}
+
+// jaegerSource reads traces through the Jaeger query service's HTTP API.
+// Jaeger searches by service, so it returns the whole of every trace with a
+// span from ours, whichever service started it.
+type jaegerSource struct {
+	endpoint  string
+	service   string
+	authToken string
+	client    *http.Client
+}
+
+// jaegerResponse is the envelope of GET /api/traces.
+type jaegerResponse struct {
+	Data   []jaegerTrace `json:"data"`
+	Errors []struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	} `json:"errors"`
+}
+
+type jaegerTrace struct {
+	TraceID   string                   `json:"traceID"`
+	Spans     []jaegerSpan             `json:"spans"`
+	Processes map[string]jaegerProcess `json:"processes"`
+}
+
+type jaegerSpan struct {
+	TraceID       string            `json:"traceID"`
+	SpanID        string            `json:"spanID"`
+	OperationName string            `json:"operationName"`
+	References    []jaegerReference `json:"references"`
+	StartTime     int64             `json:"startTime"` // microseconds since the epoch
+	Duration      int64             `json:"duration"`  // microseconds
+	Tags          []jaegerKeyValue  `json:"tags"`
+	Logs          []jaegerLog       `json:"logs"`
+	ProcessID     string            `json:"processID"`
+}
+
+type jaegerReference struct {
+	RefType string `json:"refType"`
+	TraceID string `json:"traceID"`
+	SpanID  string `json:"spanID"`
+}
+
+type jaegerProcess struct {
+	ServiceName string `json:"serviceName"`
+}
+
+type jaegerKeyValue struct {
+	Key   string      `json:"key"`
+	Value interface{} `json:"value"`
+}
+
+type jaegerLog struct {
+	Timestamp int64            `json:"timestamp"`
+	Fields    []jaegerKeyValue `json:"fields"`
+}
+
+func (js *jaegerSource) FetchTraces(ctx context.Context, start, end time.Time, limit int) ([]TraceInfo, error) {
+	endpoint, err := url.Parse(js.endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tracing endpoint: %w", err)
+	}
+	endpoint.Path = strings.TrimSuffix(endpoint.Path, "/") + "/api/traces"
+	query := url.Values{}
+	query.Set("service", js.service)
+	query.Set("start", strconv.FormatInt(start.UnixMicro(), 10))
+	query.Set("end", strconv.FormatInt(end.UnixMicro(), 10))
+	query.Set("limit", strconv.Itoa(limit))
+	endpoint.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if js.authToken != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", js.authToken))
+	}
+
+	resp, err := js.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jaeger query failed: %s", resp.Status)
+	}
+
+	var body jaegerResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("invalid jaeger response: %w", err)
+	}
+	if len(body.Errors) > 0 && len(body.Data) == 0 {
+		return nil, fmt.Errorf("jaeger query failed: %s", body.Errors[0].Msg)
+	}
+
+	traces := make([]TraceInfo, 0, len(body.Data))
+	for _, jt := range body.Data {
+		if trace, ok := jt.toTraceInfo(); ok {
+			traces = append(traces, trace)
+		}
+	}
+	return traces, nil
+}
+
+// toTraceInfo converts a Jaeger trace. The root is the earliest span with
+// no parent in the trace; its tags become the trace's tags, and the logs of
+// every span become the trace's logs.
+func (jt jaegerTrace) toTraceInfo() (TraceInfo, bool) {
+	if len(jt.Spans) == 0 {
+		return TraceInfo{}, false
+	}
+	spanIDs := make(map[string]bool, len(jt.Spans))
+	for _, s := range jt.Spans {
+		spanIDs[s.SpanID] = true
+	}
+	spans := make([]SpanInfo, 0, len(jt.Spans))
+	var root *SpanInfo
+	for _, s := range jt.Spans {
+		span := SpanInfo{
+			SpanID:        s.SpanID,
+			ParentSpanID:  s.parentSpanID(),
+			ServiceName:   jt.Processes[s.ProcessID].ServiceName,
+			OperationName: s.OperationName,
+			StartTime:     time.UnixMicro(s.StartTime).UTC(),
+			Duration:      time.Duration(s.Duration) * time.Microsecond,
+			Status:        s.status(),
+		}
+		span.EndTime = span.StartTime.Add(span.Duration)
+		spans = append(spans, span)
+	}
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].StartTime.Before(spans[j].StartTime) })
+	for i := range spans {
+		if p := spans[i].ParentSpanID; p == "" || !spanIDs[p] {
+			root = &spans[i]
+			break
+		}
+	}
+	if root == nil {
+		root = &spans[0] // a cycle of references; start at the earliest
+	}
+
+	trace := TraceInfo{
+		TraceID:       jt.TraceID,
+		SpanID:        root.SpanID,
+		ParentSpanID:  root.ParentSpanID,
+		ServiceName:   root.ServiceName,
+		OperationName: root.OperationName,
+		StartTime:     root.StartTime,
+		EndTime:       root.EndTime,
+		Duration:      root.Duration,
+		Status:        root.Status,
+		Sampled:       true,
+		SampleRate:    1.0,
+		Tags:          jt.spanTags(root.SpanID),
+		ImportedFrom:  "jaeger",
+	}
+	for _, span := range spans {
+		if span.SpanID != root.SpanID {
+			trace.Spans = append(trace.Spans, span)
+		}
+	}
+	for _, s := range jt.Spans {
+		for _, l := range s.Logs {
+			trace.Logs = append(trace.Logs, l.toTraceLog())
+		}
+	}
+	sort.SliceStable(trace.Logs, func(i, j int) bool { return trace.Logs[i].Timestamp.Before(trace.Logs[j].Timestamp) })
+	if trace.TraceID == "" {
+		trace.TraceID = jt.Spans[0].TraceID
+	}
+	return trace, true
+}
+
+// spanTags returns the tags of the span with the given ID as strings.
+func (jt jaegerTrace) spanTags(spanID string) map[string]string {
+	tags := make(map[string]string)
+	for _, s := range jt.Spans {
+		if s.SpanID != spanID {
+			continue
+		}
+		for _, kv := range s.Tags {
+			tags[kv.Key] = fmt.Sprint(kv.Value)
+		}
+		break
+	}
+	return tags
+}
+
+// parentSpanID returns the span this one is a child of, falling back to
+// the span it follows from.
+func (s jaegerSpan) parentSpanID() string {
+	parent := ""
+	for _, ref := range s.References {
+		switch ref.RefType {
+		case "CHILD_OF":
+			return ref.SpanID
+		case "FOLLOWS_FROM":
+			if parent == "" {
+				parent = ref.SpanID
+			}
+		}
+	}
+	return parent
+}
+
+// status maps Jaeger's error tags onto the statuses the drilldown uses.
+func (s jaegerSpan) status() string {
+	for _, kv := range s.Tags {
+		switch kv.Key {
+		case "error":
+			if fmt.Sprint(kv.Value) == "true" {
+				return "error"
+			}
+		case "otel.status_code":
+			if fmt.Sprint(kv.Value) == "ERROR" {
+				return "error"
+			}
+		}
+	}
+	return "ok"
+}
+
+// toTraceLog takes the level and message from the fields OpenTracing and
+// OpenTelemetry use for them and keeps the rest as fields.
+func (l jaegerLog) toTraceLog() TraceLog {
+	log := TraceLog{
+		Timestamp: time.UnixMicro(l.Timestamp).UTC(),
+		Level:     "info",
+		Fields:    make(map[string]interface{}),
+	}
+	for _, kv := range l.Fields {
+		switch kv.Key {
+		case "level":
+			log.Level = fmt.Sprint(kv.Value)
+		case "message":
+			log.Message = fmt.Sprint(kv.Value)
+		case "event":
+			if log.Message == "" {
+				log.Message = fmt.Sprint(kv.Value)
+			}
+		default:
+			log.Fields[kv.Key] = kv.Value
+		}
+	}
+	return log
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 James Ross
+package tracedrilldownlogtail
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const jaegerTraceJSON = `{"data":[{"traceID":"abc123","spans":[
+ {"traceID":"abc123","spanID":"s2","operationName":"process_job","processID":"p2",
+  "references":[{"refType":"CHILD_OF","traceID":"abc123","spanID":"s1"}],
+  "startTime":%d,"duration":2000,"tags":[{"key":"error","type":"bool","value":true}],
+  "logs":[{"timestamp":%d,"fields":[{"key":"event","value":"retry"},{"key":"attempt","value":2}]}]},
+ {"traceID":"abc123","spanID":"s1","operationName":"POST /orders","processID":"p1",
+  "references":[],"startTime":%d,"duration":5000,"tags":[{"key":"http.status_code","type":"int64","value":201}]}
+],"processes":{"p1":{"serviceName":"orders"},"p2":{"serviceName":"go-redis-work-queue"}}}]}`
+
+func TestImportTracesFromJaeger(t *testing.T) {
+	start := time.Now().Add(-time.Minute).UnixMicro()
+	var query map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/traces" || r.Header.Get("Authorization") != "Bearer tok" {
+			http.NotFound(w, r)
+			return
+		}
+		query = map[string]string{}
+		for k := range r.URL.Query() {
+			query[k] = r.URL.Query().Get(k)
+		}
+		w.Write([]byte(fmt.Sprintf(jaegerTraceJSON, start+1000, start+1500, start)))
+	}))
+	defer srv.Close()
+
+	tm := newSamplingManager(t, &TracingConfig{
+		Enabled: true, Provider: "jaeger", Endpoint: srv.URL, AuthToken: "tok",
+		ServiceName: "go-redis-work-queue", TraceTTL: time.Hour, ImportLimit: 50,
+	})
+	n, err := tm.ImportTraces(context.Background(), time.Now().Add(-48*time.Hour))
+	if err != nil || n != 1 {
+		t.Fatalf("expected one imported trace, got %d, %v", n, err)
+	}
+	if query["service"] != "go-redis-work-queue" || query["limit"] != "50" {
+		t.Fatalf("unexpected query %v", query)
+	}
+	since, _ := strconv.ParseInt(query["start"], 10, 64)
+	if oldest := time.Now().Add(-time.Hour - time.Minute).UnixMicro(); since < oldest {
+		t.Fatalf("since should be clamped to the trace TTL, got %d", since)
+	}
+
+	trace, err := tm.GetTrace("abc123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trace.SpanID != "s1" || trace.ServiceName != "orders" || trace.Duration != 5*time.Millisecond ||
+		trace.ImportedFrom != "jaeger" || trace.Tags["http.status_code"] != "201" {
+		t.Fatalf("unexpected root: %+v", trace)
+	}
+	if len(trace.Spans) != 1 || trace.Spans[0].ParentSpanID != "s1" || trace.Spans[0].Status != "error" {
+		t.Fatalf("unexpected spans: %+v", trace.Spans)
+	}
+	if len(trace.Logs) != 1 || trace.Logs[0].Message != "retry" || trace.Logs[0].Fields["attempt"] == nil {
+		t.Fatalf("unexpected logs: %+v", trace.Logs)
+	}
+	if tm.redis.ZScore(context.Background(), traceIndexKey, "abc123").Err() != nil {
+		t.Fatal("imported trace should be indexed for retention")
+	}
+
+	// served from the imported copy, not refetched from the endpoint
+	summary, err := tm.GetSpanSummary(context.Background(), "abc123")
+	if err != nil || summary.TotalSpans != 2 || summary.ErrorCount != 1 || len(summary.Services) != 2 {
+		t.Fatalf("unexpected summary: %+v, %v", summary, err)
+	}
+}
+
+func TestImportTracesUnsupportedProvider(t *testing.T) {
+	tm := newSamplingManager(t, &TracingConfig{Enabled: true, Provider: "zipkin", Endpoint: "http://zipkin"})
+	if _, err := tm.ImportTraces(context.Background(), time.Now()); err == nil {
+		t.Fatal("expected an error for a provider without an importer")
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2025 James Ross
+package tracedrilldownlogtail
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// traceIndexKey is a sorted set of stored trace IDs scored by start time in
+// Unix nanoseconds. It sits outside the trace:* pattern SearchTraces scans.
+const traceIndexKey = "traces:index"
+
+const (
+	defaultTraceTTL             = 24 * time.Hour
+	defaultSamplingBudgetWindow = time.Minute
+)
+
+var (
+	tracesStored = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "trace_drilldown_traces_stored",
+		Help: "Traces currently held in Redis, as of the last store",
+	})
+	tracesDropped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "trace_drilldown_traces_dropped_total",
+		Help: "Traces not kept because of retention limits, by reason (evicted for max_stored_traces, budget for sampling_budget)",
+	}, []string{"reason"})
+)
+
+// samplingBudget counts the sampled traces started in the current window.
+type samplingBudget struct {
+	mu    sync.Mutex
+	start time.Time
+	used  int
+}
+
+// traceTTL is how long a stored trace lives in Redis.
+func (tm *TraceManager) traceTTL() time.Duration {
+	if tm.config.TraceTTL > 0 {
+		return tm.config.TraceTTL
+	}
+	return defaultTraceTTL
+}
+
+// takeSamplingBudget reports whether another sampled trace may be started
+// in this window. Without a SamplingBudget every trace may.
+func (tm *TraceManager) takeSamplingBudget() bool {
+	limit := tm.config.SamplingBudget
+	if limit <= 0 {
+		return true
+	}
+	window := tm.config.SamplingBudgetWindow
+	if window <= 0 {
+		window = defaultSamplingBudgetWindow
+	}
+
+	b := &tm.budget
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if now := time.Now(); now.Sub(b.start) >= window {
+		b.start, b.used = now, 0
+	}
+	if b.used >= limit {
+		return false
+	}
+	b.used++
+	return true
+}
+
+// indexTrace records a newly stored trace in the index, forgets index
+// entries older than the TTL and, past MaxStoredTraces, evicts the oldest
+// traces. It updates the stored-trace gauge.
+func (tm *TraceManager) indexTrace(ctx context.Context, trace *TraceInfo) {
+	cutoff := time.Now().Add(-tm.traceTTL()).UnixNano()
+	pipe := tm.redis.TxPipeline()
+	pipe.ZAdd(ctx, traceIndexKey, redis.Z{Score: float64(trace.StartTime.UnixNano()), Member: trace.TraceID})
+	pipe.ZRemRangeByScore(ctx, traceIndexKey, "-inf", "("+strconv.FormatInt(cutoff, 10))
+	card := pipe.ZCard(ctx, traceIndexKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		tm.logger.Warn("Failed to index trace", zap.String("trace_id", trace.TraceID), zap.Error(err))
+		return
+	}
+
+	count := card.Val()
+	max := int64(tm.config.MaxStoredTraces)
+	if max > 0 && count > max {
+		evicted, err := tm.redis.ZPopMin(ctx, traceIndexKey, count-max).Result()
+		if err != nil {
+			tm.logger.Warn("Failed to evict old traces", zap.Error(err))
+		} else {
+			tm.evictTraces(ctx, evicted)
+			count -= int64(len(evicted))
+		}
+	}
+	tracesStored.Set(float64(count))
+}
+
+// evictTraces deletes the traces popped from the index, here and in Redis.
+func (tm *TraceManager) evictTraces(ctx context.Context, evicted []redis.Z) {
+	if len(evicted) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(evicted))
+	tm.mu.Lock()
+	for _, z := range evicted {
+		id := fmt.Sprint(z.Member)
+		keys = append(keys, fmt.Sprintf("trace:%s", id))
+		delete(tm.traces, id)
+	}
+	tm.mu.Unlock()
+	if err := tm.redis.Del(ctx, keys...).Err(); err != nil {
+		tm.logger.Warn("Failed to delete evicted traces", zap.Error(err))
+	}
+	tracesDropped.WithLabelValues("evicted").Add(float64(len(evicted)))
+}
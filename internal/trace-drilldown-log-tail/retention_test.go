@@ -0,0 +1,66 @@
+// Copyright 2025 James Ross
+package tracedrilldownlogtail
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMaxStoredTracesEvictsOldest(t *testing.T) {
+	tm := newSamplingManager(t, &TracingConfig{Enabled: true, SamplingRate: 1.0, MaxStoredTraces: 3, TraceTTL: time.Hour})
+	ctx := context.Background()
+
+	var ids []string
+	var ctxs []context.Context
+	for i := 0; i < 5; i++ {
+		traceCtx, tctx := tm.StartTrace(ctx, "job")
+		ids = append(ids, traceCtx.TraceID)
+		ctxs = append(ctxs, tctx)
+		time.Sleep(time.Millisecond)
+	}
+
+	if n := tm.redis.ZCard(ctx, traceIndexKey).Val(); n != 3 {
+		t.Fatalf("expected 3 indexed traces, got %d", n)
+	}
+	for i, id := range ids {
+		_, err := tm.GetTrace(id)
+		if evicted := i < 2; evicted != (err != nil) {
+			t.Fatalf("trace %d: evicted=%t but GetTrace err=%v", i, evicted, err)
+		}
+	}
+	if ttl := tm.redis.TTL(ctx, "trace:"+ids[4]).Val(); ttl <= 59*time.Minute || ttl > time.Hour {
+		t.Fatalf("expected the configured 1h TTL, got %v", ttl)
+	}
+
+	// ending an evicted trace or one of its spans must not bring it back
+	_, end := tm.StartSpan(ctxs[0], "late")
+	end("ok")
+	tm.EndTrace(ctxs[0], "ok")
+	if tm.redis.Exists(ctx, "trace:"+ids[0]).Val() != 0 {
+		t.Fatal("evicted trace was written back")
+	}
+}
+
+func TestSamplingBudgetStopsStoringTraces(t *testing.T) {
+	tm := newSamplingManager(t, &TracingConfig{
+		Enabled:              true,
+		SamplingRate:         1.0,
+		SamplingBudget:       2,
+		SamplingBudgetWindow: 50 * time.Millisecond,
+	})
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		traceCtx, _ := tm.StartTrace(ctx, "job")
+		stored := tm.redis.Exists(ctx, "trace:"+traceCtx.TraceID).Val() == 1
+		if within := i < 2; traceCtx.Sampled != within || stored != within {
+			t.Fatalf("trace %d: sampled=%t stored=%t, want %t", i, traceCtx.Sampled, stored, within)
+		}
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if traceCtx, _ := tm.StartTrace(ctx, "job"); !traceCtx.Sampled {
+		t.Fatal("budget should refill in the next window")
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2025 James Ross
+package tracedrilldownlogtail
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+func newSamplingManager(t *testing.T, cfg *TracingConfig) *TraceManager {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	return NewTraceManager(cfg, rdb, zap.NewNop())
+}
+
+func TestStartTraceUsesOperationSampleRates(t *testing.T) {
+	tm := newSamplingManager(t, &TracingConfig{
+		Enabled:      true,
+		SamplingRate: 0.0,
+		OperationSampleRates: map[string]float64{
+			"enqueue": 1.0,
+		},
+	})
+
+	for i := 0; i < 50; i++ {
+		traceCtx, _ := tm.StartTrace(context.Background(), "enqueue")
+		if !traceCtx.Sampled {
+			t.Fatal("rate 1.0 operation should always be sampled")
+		}
+		info, err := tm.GetTrace(traceCtx.TraceID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.SampleRate != 1.0 || !info.Sampled {
+			t.Fatalf("expected effective rate 1.0 recorded, got %v (sampled=%t)", info.SampleRate, info.Sampled)
+		}
+
+		traceCtx, _ = tm.StartTrace(context.Background(), "dequeue")
+		if traceCtx.Sampled {
+			t.Fatal("operation falling back to global rate 0.0 should never be sampled")
+		}
+	}
+}
+
+func TestStartTraceSamplingIsProbabilistic(t *testing.T) {
+	tm := newSamplingManager(t, &TracingConfig{Enabled: true, SamplingRate: 0.5})
+
+	const n = 2000
+	sampled := 0
+	for i := 0; i < n; i++ {
+		if tm.shouldSample(tm.sampleRate("op")) {
+			sampled++
+		}
+	}
+	if sampled < n*35/100 || sampled > n*65/100 {
+		t.Fatalf("expected roughly half sampled, got %d/%d", sampled, n)
+	}
+}
+
+func TestStartTraceRespectsSampledParent(t *testing.T) {
+	tm := newSamplingManager(t, &TracingConfig{Enabled: true, SamplingRate: 0.0})
+
+	parent := &TraceContext{TraceID: "parent", SpanID: "span", Sampled: true}
+	ctx := WithTraceContext(context.Background(), parent)
+	traceCtx, _ := tm.StartTrace(ctx, "child")
+	if !traceCtx.Sampled {
+		t.Fatal("sampled parent must not be downsampled")
+	}
+
+	unsampled := WithTraceContext(context.Background(), &TraceContext{TraceID: "p2", Sampled: false})
+	traceCtx, _ = tm.StartTrace(unsampled, "child")
+	if traceCtx.Sampled {
+		t.Fatal("unsampled parent should defer to the configured rate")
+	}
+}
@@ -0,0 +1,276 @@
+// Copyright 2025 James Ross
+package tracedrilldownlogtail
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// StartSpan starts a child of the current span in ctx and returns a context
+// carrying it, so spans started from that context nest below it. Call the
+// returned function with the final status to end the span. Without an
+// active, sampled trace in ctx the span is not recorded, but the returned
+// context still propagates the new span ID.
+func (tm *TraceManager) StartSpan(ctx context.Context, operationName string) (context.Context, func(status string)) {
+	parent := tm.getTraceContext(ctx)
+	if parent == nil {
+		return ctx, func(string) {}
+	}
+
+	child := &TraceContext{
+		TraceID: parent.TraceID,
+		SpanID:  generateSpanID(),
+		Sampled: parent.Sampled,
+		Baggage: make(map[string]string, len(parent.Baggage)),
+	}
+	for k, v := range parent.Baggage {
+		child.Baggage[k] = v
+	}
+	ctx = context.WithValue(ctx, "trace", child)
+
+	if !parent.Sampled {
+		return ctx, func(string) {}
+	}
+
+	tm.mu.Lock()
+	trace, exists := tm.traces[parent.TraceID]
+	if !exists {
+		tm.mu.Unlock()
+		return ctx, func(string) {}
+	}
+	trace.Spans = append(trace.Spans, SpanInfo{
+		SpanID:        child.SpanID,
+		ParentSpanID:  parent.SpanID,
+		ServiceName:   tm.config.ServiceName,
+		OperationName: operationName,
+		StartTime:     time.Now(),
+		Status:        "active",
+	})
+	tm.mu.Unlock()
+
+	var once sync.Once
+	return ctx, func(status string) {
+		once.Do(func() { tm.endSpan(child.TraceID, child.SpanID, status) })
+	}
+}
+
+// endSpan closes a span and saves the trace so other processes see it.
+func (tm *TraceManager) endSpan(traceID, spanID, status string) {
+	tm.mu.Lock()
+	trace, exists := tm.traces[traceID]
+	if !exists {
+		tm.mu.Unlock()
+		return
+	}
+	for i := range trace.Spans {
+		if trace.Spans[i].SpanID == spanID {
+			span := &trace.Spans[i]
+			span.EndTime = time.Now()
+			span.Duration = span.EndTime.Sub(span.StartTime)
+			span.Status = status
+			break
+		}
+	}
+	data, err := json.Marshal(trace)
+	tm.mu.Unlock()
+	if err != nil {
+		return
+	}
+
+	key := fmt.Sprintf("trace:%s", traceID)
+	// XX: a trace evicted for max_stored_traces stays evicted
+	tm.redis.SetXX(context.Background(), key, string(data), tm.traceTTL())
+}
+
+// buildSpanSummary assembles the timeline, per-operation totals and span
+// tree of a trace. The trace itself is the root; a span whose parent is
+// unknown hangs off the root so it is never lost from the tree.
+func (tm *TraceManager) buildSpanSummary(trace *TraceInfo) *SpanSummary {
+	spans := tm.traceSpans(trace)
+	summary := &SpanSummary{
+		TraceID:    trace.TraceID,
+		TotalSpans: len(spans),
+		Duration:   trace.Duration,
+		Services:   make([]string, 0, 1),
+		Operations: make([]Operation, 0),
+		Timeline:   make([]TimelineEvent, 0, 2*len(spans)),
+	}
+
+	services := make(map[string]bool)
+	operations := make(map[string]*Operation)
+	opErrors := make(map[string]int)
+	var opOrder []string
+	for _, span := range spans {
+		if !services[span.ServiceName] {
+			services[span.ServiceName] = true
+			summary.Services = append(summary.Services, span.ServiceName)
+		}
+
+		op, ok := operations[span.OperationName]
+		if !ok {
+			op = &Operation{Name: span.OperationName, Service: span.ServiceName}
+			operations[span.OperationName] = op
+			opOrder = append(opOrder, span.OperationName)
+		}
+		op.Count++
+		op.Duration += span.Duration
+		if isErrorStatus(span.Status) {
+			summary.ErrorCount++
+			opErrors[span.OperationName]++
+		}
+
+		summary.Timeline = append(summary.Timeline, TimelineEvent{
+			Timestamp: span.StartTime,
+			SpanID:    span.SpanID,
+			Operation: span.OperationName,
+			Service:   span.ServiceName,
+			EventType: "start",
+		})
+		if !span.EndTime.IsZero() {
+			eventType := "end"
+			if isErrorStatus(span.Status) {
+				eventType = "error"
+			}
+			summary.Timeline = append(summary.Timeline, TimelineEvent{
+				Timestamp: span.EndTime,
+				SpanID:    span.SpanID,
+				Operation: span.OperationName,
+				Service:   span.ServiceName,
+				Duration:  span.Duration,
+				EventType: eventType,
+			})
+		}
+	}
+	for _, name := range opOrder {
+		op := operations[name]
+		op.ErrorRate = float64(opErrors[name]) / float64(op.Count)
+		summary.Operations = append(summary.Operations, *op)
+	}
+	sort.SliceStable(summary.Timeline, func(i, j int) bool {
+		return summary.Timeline[i].Timestamp.Before(summary.Timeline[j].Timestamp)
+	})
+
+	summary.Tree = buildSpanTree(spans)
+	return summary
+}
+
+// traceSpans returns the spans of a trace with the trace itself first, as
+// its root span.
+func (tm *TraceManager) traceSpans(trace *TraceInfo) []SpanInfo {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	spans := make([]SpanInfo, 0, len(trace.Spans)+1)
+	spans = append(spans, SpanInfo{
+		SpanID:        trace.SpanID,
+		ParentSpanID:  trace.ParentSpanID,
+		ServiceName:   trace.ServiceName,
+		OperationName: trace.OperationName,
+		StartTime:     trace.StartTime,
+		EndTime:       trace.EndTime,
+		Duration:      trace.Duration,
+		Status:        trace.Status,
+	})
+	return append(spans, trace.Spans...)
+}
+
+// GetSpanBreakdown splits the time of each span of a trace into self time
+// and time spent in its children, and lays the spans out as bars with an
+// offset, duration and depth for a flamegraph or waterfall view.
+func (tm *TraceManager) GetSpanBreakdown(traceID string) (*SpanBreakdown, error) {
+	trace, err := tm.GetTrace(traceID)
+	if err != nil {
+		return nil, err
+	}
+	root := buildSpanTree(tm.traceSpans(trace))
+	now := time.Now()
+
+	breakdown := &SpanBreakdown{TraceID: traceID, StartTime: root.StartTime}
+	var walk func(node *SpanNode, depth int) time.Time
+	// walk appends node and its subtree and returns the end of node.
+	walk = func(node *SpanNode, depth int) time.Time {
+		end, active := node.EndTime, node.EndTime.IsZero()
+		if active {
+			end = now
+		}
+		if end.Before(node.StartTime) {
+			end = node.StartTime
+		}
+		i := len(breakdown.Spans)
+		breakdown.Spans = append(breakdown.Spans, SpanBar{
+			SpanID:        node.SpanID,
+			ParentSpanID:  node.ParentSpanID,
+			ServiceName:   node.ServiceName,
+			OperationName: node.OperationName,
+			Status:        node.Status,
+			Depth:         depth,
+			Offset:        node.StartTime.Sub(root.StartTime),
+			Duration:      end.Sub(node.StartTime),
+			Active:        active,
+		})
+		if depth > breakdown.MaxDepth {
+			breakdown.MaxDepth = depth
+		}
+
+		children := append([]*SpanNode(nil), node.Children...)
+		sort.SliceStable(children, func(a, b int) bool {
+			return children[a].StartTime.Before(children[b].StartTime)
+		})
+		// children in start order: extend the covered interval, or close
+		// it and start a new one at a gap
+		var childTime time.Duration
+		var from, to time.Time
+		for _, child := range children {
+			start := child.StartTime
+			childEnd := walk(child, depth+1)
+			if start.Before(node.StartTime) {
+				start = node.StartTime
+			}
+			if childEnd.After(end) {
+				childEnd = end
+			}
+			if !childEnd.After(start) {
+				continue
+			}
+			if to.IsZero() || start.After(to) {
+				childTime += to.Sub(from)
+				from, to = start, childEnd
+			} else if childEnd.After(to) {
+				to = childEnd
+			}
+		}
+		childTime += to.Sub(from)
+
+		bar := &breakdown.Spans[i]
+		bar.ChildTime = childTime
+		bar.SelfTime = bar.Duration - childTime
+		return end
+	}
+	walk(root, 0)
+	breakdown.Duration = breakdown.Spans[0].Duration
+	return breakdown, nil
+}
+
+// buildSpanTree links spans to their parents, with spans[0] as the root.
+func buildSpanTree(spans []SpanInfo) *SpanNode {
+	nodes := make(map[string]*SpanNode, len(spans))
+	for _, span := range spans {
+		nodes[span.SpanID] = &SpanNode{SpanInfo: span}
+	}
+	root := nodes[spans[0].SpanID]
+	for _, span := range spans[1:] {
+		parent, ok := nodes[span.ParentSpanID]
+		if !ok || span.ParentSpanID == span.SpanID {
+			parent = root
+		}
+		parent.Children = append(parent.Children, nodes[span.SpanID])
+	}
+	return root
+}
+
+func isErrorStatus(status string) bool {
+	return status == "error" || status == "failed"
+}
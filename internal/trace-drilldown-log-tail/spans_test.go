@@ -0,0 +1,150 @@
+// Copyright 2025 James Ross
+package tracedrilldownlogtail
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStartSpanBuildsTree(t *testing.T) {
+	tm := newSamplingManager(t, &TracingConfig{Enabled: true, ServiceName: "svc", SamplingRate: 1.0})
+	ctx := context.Background()
+
+	traceCtx, ctx := tm.StartTrace(ctx, "job")
+	dequeueCtx, endDequeue := tm.StartSpan(ctx, "dequeue")
+	_, endDecode := tm.StartSpan(dequeueCtx, "decode")
+	endDecode("error")
+	endDequeue("ok")
+	_, endProcess := tm.StartSpan(ctx, "process")
+	endProcess("ok")
+	tm.EndTrace(ctx, "ok")
+
+	info, err := tm.GetTrace(traceCtx.TraceID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.Spans) != 3 {
+		t.Fatalf("expected 3 child spans, got %d", len(info.Spans))
+	}
+
+	summary, err := tm.GetSpanSummary(ctx, traceCtx.TraceID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.TotalSpans != 4 || summary.ErrorCount != 1 || len(summary.Timeline) != 8 {
+		t.Fatalf("unexpected summary: spans=%d errors=%d timeline=%d", summary.TotalSpans, summary.ErrorCount, len(summary.Timeline))
+	}
+	root := summary.Tree
+	if root.SpanID != traceCtx.SpanID || len(root.Children) != 2 {
+		t.Fatalf("expected root with 2 children, got %+v", root)
+	}
+	dequeue := root.Children[0]
+	if dequeue.OperationName != "dequeue" || len(dequeue.Children) != 1 || dequeue.Children[0].OperationName != "decode" {
+		t.Fatalf("expected decode nested under dequeue, got %+v", dequeue)
+	}
+	if dequeue.Children[0].ParentSpanID != dequeue.SpanID {
+		t.Fatal("expected decode to link to its parent span")
+	}
+	for i := 1; i < len(summary.Timeline); i++ {
+		if summary.Timeline[i].Timestamp.Before(summary.Timeline[i-1].Timestamp) {
+			t.Fatal("expected the timeline in time order")
+		}
+	}
+}
+
+func TestStartSpanPersistsToRedis(t *testing.T) {
+	tm := newSamplingManager(t, &TracingConfig{Enabled: true, SamplingRate: 1.0})
+	traceCtx, ctx := tm.StartTrace(context.Background(), "job")
+	_, end := tm.StartSpan(ctx, "process")
+	end("ok")
+
+	stored, err := tm.loadTrace(traceCtx.TraceID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored.Spans) != 1 || stored.Spans[0].Status != "ok" {
+		t.Fatalf("expected the ended span in Redis, got %+v", stored.Spans)
+	}
+}
+
+func TestStartSpanSkipsUnsampledTraces(t *testing.T) {
+	tm := newSamplingManager(t, &TracingConfig{Enabled: true, SamplingRate: 0.0})
+	traceCtx, ctx := tm.StartTrace(context.Background(), "job")
+	spanCtx, end := tm.StartSpan(ctx, "process")
+	end("ok")
+
+	if got := tm.getTraceContext(spanCtx); got.SpanID == traceCtx.SpanID || got.TraceID != traceCtx.TraceID {
+		t.Fatalf("expected a new span in the same trace, got %+v", got)
+	}
+	info, _ := tm.GetTrace(traceCtx.TraceID)
+	if len(info.Spans) != 0 {
+		t.Fatalf("expected no recorded spans for an unsampled trace, got %d", len(info.Spans))
+	}
+}
+
+func TestGetSpanBreakdown(t *testing.T) {
+	tm := newSamplingManager(t, &TracingConfig{Enabled: true, SamplingRate: 1.0})
+	t0 := time.Unix(1700000000, 0)
+	at := func(ms int) time.Time { return t0.Add(time.Duration(ms) * time.Millisecond) }
+	span := func(id, parent string, from, to int) SpanInfo {
+		s := SpanInfo{SpanID: id, ParentSpanID: parent, OperationName: id, StartTime: at(from), Status: "ok"}
+		if to >= 0 {
+			s.EndTime = at(to)
+			s.Duration = s.EndTime.Sub(s.StartTime)
+		}
+		return s
+	}
+	tm.traces["t1"] = &TraceInfo{
+		TraceID: "t1", SpanID: "root", OperationName: "job",
+		StartTime: at(0), EndTime: at(100), Duration: 100 * time.Millisecond,
+		Spans: []SpanInfo{
+			span("b", "root", 70, 90),
+			span("a", "root", 10, 60),
+			span("a2", "a", 20, 50),
+			span("a1", "a", 10, 30),
+			span("orphan", "gone", 95, 120),
+			span("open", "b", 80, -1),
+		},
+	}
+
+	bd, err := tm.GetSpanBreakdown("t1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bd.Duration != 100*time.Millisecond || bd.MaxDepth != 2 || len(bd.Spans) != 7 {
+		t.Fatalf("unexpected breakdown: duration=%s depth=%d spans=%d", bd.Duration, bd.MaxDepth, len(bd.Spans))
+	}
+	var order []string
+	bars := map[string]SpanBar{}
+	for _, bar := range bd.Spans {
+		order = append(order, bar.SpanID)
+		bars[bar.SpanID] = bar
+	}
+	if got := strings.Join(order, ","); got != "root,a,a1,a2,b,open,orphan" {
+		t.Fatalf("expected depth first in start order, got %s", got)
+	}
+
+	ms := func(n int) time.Duration { return time.Duration(n) * time.Millisecond }
+	for id, want := range map[string][4]time.Duration{
+		// offset, duration, self, child
+		"root":   {0, ms(100), ms(25), ms(75)},
+		"a":      {ms(10), ms(50), ms(10), ms(40)},
+		"a1":     {ms(10), ms(20), ms(20), 0},
+		"b":      {ms(70), ms(20), ms(10), ms(10)},
+		"orphan": {ms(95), ms(25), ms(25), 0},
+	} {
+		bar := bars[id]
+		if got := [4]time.Duration{bar.Offset, bar.Duration, bar.SelfTime, bar.ChildTime}; got != want {
+			t.Errorf("%s: expected offset/duration/self/child %v, got %v", id, want, got)
+		}
+	}
+	if open := bars["open"]; !open.Active || open.Depth != 2 || open.Duration < ms(20) {
+		t.Fatalf("expected the open span measured up to now, got %+v", open)
+	}
+
+	if _, err := tm.GetSpanBreakdown("missing"); err == nil {
+		t.Fatal("expected an error for an unknown trace")
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math/rand"
 	"net/http"
 	"net/url"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -25,6 +28,8 @@ type TraceManager struct {
 	httpClient *http.Client
 	traces     map[string]*TraceInfo
 	mu         sync.RWMutex
+	budget     samplingBudget
+	source     TraceSource // set by SetTraceSource; nil follows Provider
 }
 
 // NewTraceManager creates a new trace manager
@@ -56,15 +61,31 @@ func (tm *TraceManager) StartTrace(ctx context.Context, operationName string) (*
 	traceID := generateTraceID()
 	spanID := generateSpanID()
 
+	rate := tm.sampleRate(operationName)
+	sampled := tm.shouldSample(rate)
+	// Never downsample a parent that was already sampled upstream.
+	if parent := tm.getTraceContext(ctx); parent != nil && parent.Sampled {
+		sampled = true
+	}
+	// The budget wins over both, so a burst cannot fill Redis.
+	overBudget := sampled && !tm.takeSamplingBudget()
+	if overBudget {
+		sampled = false
+		tracesDropped.WithLabelValues("budget").Inc()
+	}
+
 	traceCtx := &TraceContext{
 		TraceID: traceID,
 		SpanID:  spanID,
-		Sampled: tm.shouldSample(),
+		Sampled: sampled,
 		Baggage: make(map[string]string),
 	}
 
 	// Store in context
 	ctx = context.WithValue(ctx, "trace", traceCtx)
+	if overBudget {
+		return traceCtx, ctx
+	}
 
 	// Create trace info
 	traceInfo := &TraceInfo{
@@ -74,6 +95,8 @@ func (tm *TraceManager) StartTrace(ctx context.Context, operationName string) (*
 		OperationName: operationName,
 		StartTime:     time.Now(),
 		Status:        "active",
+		Sampled:       sampled,
+		SampleRate:    rate,
 		Tags:          make(map[string]string),
 		Logs:          make([]TraceLog, 0),
 	}
@@ -163,9 +186,20 @@ func (tm *TraceManager) GetTraceLink(traceID string) (*TraceLink, error) {
 
 // GetSpanSummary retrieves a summary of spans for a trace
 func (tm *TraceManager) GetSpanSummary(ctx context.Context, traceID string) (*SpanSummary, error) {
-	// Fetch from external tracing system if configured
+	// Fetch from external tracing system if configured, unless the trace
+	// was already imported from it
+	if trace, err := tm.GetTrace(traceID); err == nil && trace.ImportedFrom != "" {
+		summary := tm.buildSpanSummary(trace)
+		tm.attachLogCounts(ctx, summary)
+		return summary, nil
+	}
 	if tm.config.Endpoint != "" {
-		return tm.fetchSpanSummary(ctx, traceID)
+		summary, err := tm.fetchSpanSummary(ctx, traceID)
+		if err != nil {
+			return nil, err
+		}
+		tm.attachLogCounts(ctx, summary)
+		return summary, nil
 	}
 
 	// Otherwise use local data
@@ -174,35 +208,85 @@ func (tm *TraceManager) GetSpanSummary(ctx context.Context, traceID string) (*Sp
 		return nil, err
 	}
 
-	summary := &SpanSummary{
-		TraceID:    traceID,
-		TotalSpans: 1, // Basic implementation
-		Duration:   trace.Duration,
-		Services:   []string{trace.ServiceName},
-		Timeline:   make([]TimelineEvent, 0),
-	}
+	summary := tm.buildSpanSummary(trace)
+	tm.attachLogCounts(ctx, summary)
+	return summary, nil
+}
 
-	// Add timeline events
-	summary.Timeline = append(summary.Timeline, TimelineEvent{
-		Timestamp: trace.StartTime,
-		SpanID:    trace.SpanID,
-		Operation: trace.OperationName,
-		Service:   trace.ServiceName,
-		EventType: "start",
+// GetCorrelatedLogs returns every log line written for a trace, in time
+// order, using the log:trace:{id} index the LogTailer maintains. Each entry
+// carries a link back to the trace.
+func (tm *TraceManager) GetCorrelatedLogs(ctx context.Context, traceID string) ([]LogEntry, error) {
+	entries, err := readIndexedLogs(ctx, tm.redis, fmt.Sprintf("log:trace:%s", traceID), 0, func(entry *LogEntry) bool {
+		return entry.TraceID == traceID
 	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load logs for trace %s: %w", traceID, err)
+	}
+	tm.LinkLogs(entries)
+	return entries, nil
+}
 
-	if !trace.EndTime.IsZero() {
-		summary.Timeline = append(summary.Timeline, TimelineEvent{
-			Timestamp: trace.EndTime,
-			SpanID:    trace.SpanID,
-			Operation: trace.OperationName,
-			Service:   trace.ServiceName,
-			Duration:  trace.Duration,
-			EventType: "end",
-		})
+// LinkLogs sets TraceLink on every entry that carries a trace ID. The link
+// points at the configured tracing UI, or at this drilldown's trace view
+// when no URL template is set.
+func (tm *TraceManager) LinkLogs(entries []LogEntry) {
+	for i := range entries {
+		if entries[i].TraceID == "" {
+			continue
+		}
+		link, err := tm.GetTraceLink(entries[i].TraceID)
+		if err != nil {
+			link = &TraceLink{
+				Type:        "drilldown",
+				URL:         fmt.Sprintf("/api/v1/trace-drilldown/traces/%s", url.PathEscape(entries[i].TraceID)),
+				DisplayName: "View trace",
+			}
+		}
+		entries[i].TraceLink = link
 	}
+}
 
-	return summary, nil
+// attachLogCounts adds the number of correlated log lines per span to a
+// summary. Lines without a span ID, or with one the trace does not know,
+// count against the root span. Failing to read the logs leaves the summary
+// without counts rather than failing it.
+func (tm *TraceManager) attachLogCounts(ctx context.Context, summary *SpanSummary) {
+	entries, err := tm.GetCorrelatedLogs(ctx, summary.TraceID)
+	if err != nil {
+		tm.logger.Warn("Failed to count trace logs", zap.String("trace_id", summary.TraceID), zap.Error(err))
+		return
+	}
+
+	known := make(map[string]*SpanNode)
+	var walk func(node *SpanNode)
+	walk = func(node *SpanNode) {
+		known[node.SpanID] = node
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	var rootID string
+	if summary.Tree != nil {
+		rootID = summary.Tree.SpanID
+		walk(summary.Tree)
+	}
+
+	summary.LogCount = len(entries)
+	summary.SpanLogCounts = make(map[string]int)
+	for _, entry := range entries {
+		spanID := entry.SpanID
+		if _, ok := known[spanID]; !ok && rootID != "" {
+			spanID = rootID
+		}
+		if spanID == "" {
+			continue
+		}
+		summary.SpanLogCounts[spanID]++
+	}
+	for spanID, node := range known {
+		node.LogCount = summary.SpanLogCounts[spanID]
+	}
 }
 
 // SearchTraces searches for traces
@@ -322,6 +406,15 @@ func (tm *TraceManager) ExtractTrace(headers http.Header) *TraceContext {
 	}
 }
 
+// WithTraceContext attaches an incoming trace context (e.g. from ExtractTrace)
+// so StartTrace can honor its sampling decision.
+func WithTraceContext(ctx context.Context, traceCtx *TraceContext) context.Context {
+	if traceCtx == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, "trace", traceCtx)
+}
+
 // Helper methods
 
 func (tm *TraceManager) getTraceContext(ctx context.Context) *TraceContext {
@@ -331,16 +424,32 @@ func (tm *TraceManager) getTraceContext(ctx context.Context) *TraceContext {
 	return nil
 }
 
-func (tm *TraceManager) shouldSample() bool {
-	// Simple sampling based on rate
-	return time.Now().UnixNano()%100 < int64(tm.config.SamplingRate*100)
+// sampleRate returns the per-operation rate when configured, else the global rate.
+func (tm *TraceManager) sampleRate(operationName string) float64 {
+	if rate, ok := tm.config.OperationSampleRates[operationName]; ok {
+		return rate
+	}
+	return tm.config.SamplingRate
+}
+
+func (tm *TraceManager) shouldSample(rate float64) bool {
+	switch {
+	case rate >= 1.0:
+		return true
+	case rate <= 0.0:
+		return false
+	}
+	return rand.Float64() < rate
 }
 
 func (tm *TraceManager) storeTrace(trace *TraceInfo) {
 	ctx := context.Background()
 	data, _ := json.Marshal(trace)
 	key := fmt.Sprintf("trace:%s", trace.TraceID)
-	tm.redis.Set(ctx, key, string(data), 24*time.Hour)
+	if err := tm.redis.Set(ctx, key, string(data), tm.traceTTL()).Err(); err != nil {
+		return
+	}
+	tm.indexTrace(ctx, trace)
 }
 
 func (tm *TraceManager) updateTrace(traceID, status string) {
@@ -361,8 +470,9 @@ func (tm *TraceManager) updateTrace(traceID, status string) {
 	trace.EndTime = time.Now()
 	trace.Duration = trace.EndTime.Sub(trace.StartTime)
 
+	// XX: a trace evicted meanwhile stays evicted
 	updatedData, _ := json.Marshal(trace)
-	tm.redis.Set(ctx, key, string(updatedData), 24*time.Hour)
+	tm.redis.SetXX(ctx, key, string(updatedData), tm.traceTTL())
 }
 
 func (tm *TraceManager) loadTrace(traceID string) (*TraceInfo, error) {
@@ -565,13 +675,16 @@ func (lt *LogTailer) WriteLog(entry *LogEntry) error {
 
 	// Store in Redis
 	ctx := context.Background()
-	data, err := json.Marshal(entry)
+	stored := *entry
+	stored.TraceLink = nil
+	data, err := json.Marshal(&stored)
 	if err != nil {
 		return err
 	}
 
-	// Use sorted set for time-based queries
-	key := fmt.Sprintf("logs:%s", time.Now().Format("2006-01-02"))
+	// Use sorted set for time-based queries, bucketed by the entry's own day
+	// so readers can locate it from its timestamp alone.
+	key := logDayKey(entry.Timestamp)
 	score := float64(entry.Timestamp.UnixNano())
 
 	if err := lt.redis.ZAdd(ctx, key, redis.Z{
@@ -736,6 +849,13 @@ func (lt *LogTailer) tailLoop(session *TailSession, eventCh chan LogStreamEvent)
 	rateLimiter := NewRateLimiter(float64(session.Config.MaxLinesPerSecond))
 	buffer := make([]LogEntry, 0, session.Config.BufferSize)
 
+	// cursor is the newest timestamp (ns) already delivered. Following a job
+	// replays its history, so it starts from the beginning.
+	cursor := session.StartedAt.UnixNano()
+	if session.Config.FollowJobID != "" {
+		cursor = 0
+	}
+
 	var linesProcessed int64
 	var droppedLines int64
 
@@ -747,7 +867,7 @@ func (lt *LogTailer) tailLoop(session *TailSession, eventCh chan LogStreamEvent)
 			return
 		case <-ticker.C:
 			// Fetch new logs
-			logs, err := lt.fetchNewLogs(ctx, session, &buffer)
+			logs, err := lt.fetchNewLogs(ctx, session, &cursor)
 			if err != nil {
 				eventCh <- LogStreamEvent{
 					Type:      "error",
@@ -823,36 +943,123 @@ func (lt *LogTailer) tailLoop(session *TailSession, eventCh chan LogStreamEvent)
 	}
 }
 
-func (lt *LogTailer) fetchNewLogs(ctx context.Context, session *TailSession, buffer *[]LogEntry) ([]LogEntry, error) {
-	// Get current date key
-	key := fmt.Sprintf("logs:%s", time.Now().Format("2006-01-02"))
+// fetchNewLogs returns entries newer than *cursor in timestamp order and
+// advances the cursor. Plain tails read every day bucket from the cursor's day
+// through today, so a tail running across midnight moves on to the new key.
+func (lt *LogTailer) fetchNewLogs(ctx context.Context, session *TailSession, cursor *int64) ([]LogEntry, error) {
+	var entries []LogEntry
+	var err error
+	if session.Config.FollowJobID != "" {
+		entries, err = lt.fetchJobLogs(ctx, session.Config.FollowJobID, *cursor)
+	} else {
+		entries, err = lt.fetchDayLogs(ctx, *cursor, time.Now())
+	}
+	if err != nil {
+		return nil, err
+	}
 
-	// Get logs since last fetch
-	min := fmt.Sprintf("%d", session.LastActivity.UnixNano())
-	max := "+inf"
+	result := make([]LogEntry, 0, len(entries))
+	for i := range entries {
+		if ts := entries[i].Timestamp.UnixNano(); ts > *cursor {
+			*cursor = ts
+		}
+		if lt.matchesLogFilter(&entries[i], session.Config.Filter) {
+			result = append(result, entries[i])
+		}
+	}
+	return result, nil
+}
 
-	logs, err := lt.redis.ZRangeByScore(ctx, key, &redis.ZRangeBy{
-		Min: min,
-		Max: max,
-	}).Result()
+// fetchDayLogs reads entries strictly after the given timestamp from each day
+// bucket between that timestamp and now.
+func (lt *LogTailer) fetchDayLogs(ctx context.Context, after int64, now time.Time) ([]LogEntry, error) {
+	from := time.Unix(0, after).Local()
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.Local)
+
+	var result []LogEntry
+	for ; !day.After(now); day = day.AddDate(0, 0, 1) {
+		logs, err := lt.redis.ZRangeByScore(ctx, logDayKey(day), &redis.ZRangeBy{
+			Min: fmt.Sprintf("(%d", after),
+			Max: "+inf",
+		}).Result()
+		if err != nil {
+			return nil, err
+		}
+		for _, logData := range logs {
+			var entry LogEntry
+			if err := json.Unmarshal([]byte(logData), &entry); err != nil {
+				continue
+			}
+			result = append(result, entry)
+		}
+	}
+	return result, nil
+}
+
+// fetchJobLogs pulls a job's entries newer than after using the log:job:{id}
+// index and returns them in timestamp order.
+func (lt *LogTailer) fetchJobLogs(ctx context.Context, jobID string, after int64) ([]LogEntry, error) {
+	return readIndexedLogs(ctx, lt.redis, fmt.Sprintf("log:job:%s", jobID), after, func(entry *LogEntry) bool {
+		return entry.JobID == jobID
+	})
+}
 
+// readIndexedLogs resolves the timestamps in one of the log:{kind}:{id}
+// index sets that are newer than after, querying only the day buckets they
+// fall in. Entries sharing a timestamp with an indexed one are dropped unless
+// match accepts them. The result is in timestamp order.
+func readIndexedLogs(ctx context.Context, rdb *redis.Client, indexKey string, after int64, match func(*LogEntry) bool) ([]LogEntry, error) {
+	members, err := rdb.SMembers(ctx, indexKey).Result()
 	if err != nil {
 		return nil, err
 	}
 
-	result := make([]LogEntry, 0, len(logs))
-	for _, logData := range logs {
-		var entry LogEntry
-		if err := json.Unmarshal([]byte(logData), &entry); err != nil {
+	// Group wanted timestamps by day bucket, tracking each bucket's range.
+	type bucket struct{ min, max int64 }
+	buckets := make(map[string]*bucket)
+	wanted := make(map[int64]bool, len(members))
+	for _, m := range members {
+		ts, err := strconv.ParseInt(m, 10, 64)
+		if err != nil || ts <= after {
 			continue
 		}
+		wanted[ts] = true
+		key := logDayKey(time.Unix(0, ts))
+		if b, ok := buckets[key]; ok {
+			if ts < b.min {
+				b.min = ts
+			}
+			if ts > b.max {
+				b.max = ts
+			}
+		} else {
+			buckets[key] = &bucket{min: ts, max: ts}
+		}
+	}
 
-		// Apply filter
-		if lt.matchesLogFilter(&entry, session.Config.Filter) {
-			result = append(result, entry)
+	var result []LogEntry
+	for key, b := range buckets {
+		logs, err := rdb.ZRangeByScore(ctx, key, &redis.ZRangeBy{
+			Min: strconv.FormatInt(b.min, 10),
+			Max: strconv.FormatInt(b.max, 10),
+		}).Result()
+		if err != nil {
+			return nil, err
+		}
+		for _, logData := range logs {
+			var entry LogEntry
+			if err := json.Unmarshal([]byte(logData), &entry); err != nil {
+				continue
+			}
+			if match(&entry) && wanted[entry.Timestamp.UnixNano()] {
+				result = append(result, entry)
+			}
 		}
 	}
 
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Timestamp.Before(result[j].Timestamp)
+	})
 	return result, nil
 }
 
@@ -1004,6 +1211,11 @@ func (lt *LogTailer) Shutdown() {
 
 // Helper functions
 
+// logDayKey returns the logs:YYYY-MM-DD bucket holding entries stamped at t.
+func logDayKey(t time.Time) string {
+	return fmt.Sprintf("logs:%s", t.Local().Format("2006-01-02"))
+}
+
 func generateTraceID() string {
 	return uuid.New().String()
 }
@@ -16,9 +16,37 @@ type TraceInfo struct {
 	EndTime      time.Time         `json:"end_time,omitempty"`
 	Duration     time.Duration     `json:"duration,omitempty"`
 	Status       string            `json:"status"`
+	Sampled      bool              `json:"sampled"`
+	SampleRate   float64           `json:"sample_rate"`
 	Tags         map[string]string `json:"tags,omitempty"`
 	Logs         []TraceLog        `json:"logs,omitempty"`
 	Links        []TraceLink       `json:"links,omitempty"`
+	// Spans holds the child spans started with StartSpan; the trace itself
+	// is the root span.
+	Spans        []SpanInfo        `json:"spans,omitempty"`
+	// ImportedFrom names the provider an imported trace was pulled from;
+	// empty for traces recorded here.
+	ImportedFrom string            `json:"imported_from,omitempty"`
+}
+
+// SpanInfo represents one span below the root of a trace
+type SpanInfo struct {
+	SpanID        string        `json:"span_id"`
+	ParentSpanID  string        `json:"parent_span_id"`
+	ServiceName   string        `json:"service_name"`
+	OperationName string        `json:"operation_name"`
+	StartTime     time.Time     `json:"start_time"`
+	EndTime       time.Time     `json:"end_time,omitempty"`
+	Duration      time.Duration `json:"duration,omitempty"`
+	Status        string        `json:"status"`
+}
+
+// SpanNode is a span and its children in a trace's span tree
+type SpanNode struct {
+	SpanInfo
+	// LogCount is the number of correlated log lines written in this span.
+	LogCount int         `json:"log_count"`
+	Children []*SpanNode `json:"children,omitempty"`
 }
 
 // TraceLog represents a log entry within a trace
@@ -49,6 +77,8 @@ type LogEntry struct {
 	SpanID      string                 `json:"span_id,omitempty"`
 	Fields      map[string]interface{} `json:"fields,omitempty"`
 	StackTrace  string                 `json:"stack_trace,omitempty"`
+	// TraceLink is filled in when the entry is served, never stored.
+	TraceLink   *TraceLink             `json:"trace_link,omitempty"`
 }
 
 // LogFilter defines filtering criteria for logs
@@ -74,6 +104,9 @@ type TailConfig struct {
 	BackpressureLimit int           `json:"backpressure_limit"`
 	FlushInterval     time.Duration `json:"flush_interval"`
 	Filter            *LogFilter    `json:"filter,omitempty"`
+	// FollowJobID streams every entry for one job, from its first log line
+	// onward, using the log:job:{id} index instead of the day buckets.
+	FollowJobID       string        `json:"follow_job_id,omitempty"`
 }
 
 // TracingConfig defines configuration for tracing integration
@@ -83,6 +116,21 @@ type TracingConfig struct {
 	Endpoint      string            `json:"endpoint"`
 	ServiceName   string            `json:"service_name"`
 	SamplingRate  float64           `json:"sampling_rate"`
+	// OperationSampleRates overrides SamplingRate for specific operation names.
+	OperationSampleRates map[string]float64 `json:"operation_sample_rates,omitempty"`
+	// TraceTTL is how long stored traces live in Redis (default 24h).
+	// MaxStoredTraces caps how many are kept, evicting the oldest first;
+	// 0 leaves the count unbounded.
+	TraceTTL        time.Duration `json:"trace_ttl,omitempty"`
+	MaxStoredTraces int           `json:"max_stored_traces,omitempty"`
+	// SamplingBudget caps the sampled traces started per
+	// SamplingBudgetWindow (default 1m), whatever the sampling rate.
+	// Traces over budget are unsampled and not stored. 0 disables it.
+	SamplingBudget       int           `json:"sampling_budget,omitempty"`
+	SamplingBudgetWindow time.Duration `json:"sampling_budget_window,omitempty"`
+	// ImportLimit caps the traces one ImportTraces call fetches (default
+	// 1000).
+	ImportLimit      int            `json:"import_limit,omitempty"`
 	PropagateHeaders []string       `json:"propagate_headers"`
 	URLTemplate   string            `json:"url_template"` // Template for external trace URLs
 	AuthToken     string            `json:"auth_token,omitempty"`
@@ -117,6 +165,41 @@ type SpanSummary struct {
 	WarningCount int           `json:"warning_count"`
 	Operations   []Operation   `json:"operations"`
 	Timeline     []TimelineEvent `json:"timeline"`
+	Tree         *SpanNode     `json:"tree,omitempty"`
+	// LogCount and SpanLogCounts cover the log lines correlated with the
+	// trace; SpanLogCounts is keyed by span ID.
+	LogCount      int            `json:"log_count"`
+	SpanLogCounts map[string]int `json:"span_log_counts,omitempty"`
+}
+
+// SpanBreakdown lays a trace's spans out for a flamegraph or waterfall:
+// one bar per span, depth first with siblings in start order, so a
+// renderer can draw them top to bottom.
+type SpanBreakdown struct {
+	TraceID   string        `json:"trace_id"`
+	StartTime time.Time     `json:"start_time"`
+	Duration  time.Duration `json:"duration"`
+	MaxDepth  int           `json:"max_depth"`
+	Spans     []SpanBar     `json:"spans"`
+}
+
+// SpanBar is one span of a SpanBreakdown. Offset is from the start of the
+// trace and Depth is 0 for the root. ChildTime is the part of the span
+// covered by at least one child, so overlapping children are not counted
+// twice; SelfTime is the rest. A span still running is measured up to now
+// and marked Active.
+type SpanBar struct {
+	SpanID        string        `json:"span_id"`
+	ParentSpanID  string        `json:"parent_span_id,omitempty"`
+	ServiceName   string        `json:"service_name"`
+	OperationName string        `json:"operation_name"`
+	Status        string        `json:"status"`
+	Depth         int           `json:"depth"`
+	Offset        time.Duration `json:"offset"`
+	Duration      time.Duration `json:"duration"`
+	SelfTime      time.Duration `json:"self_time"`
+	ChildTime     time.Duration `json:"child_time"`
+	Active        bool          `json:"active,omitempty"`
 }
 
 // Operation represents an operation within a trace
@@ -205,4 +288,31 @@ type LogSearchResult struct {
 	HasMore    bool       `json:"has_more"`
 	NextCursor string     `json:"next_cursor,omitempty"`
 	Stats      *LogStats  `json:"stats,omitempty"`
-}
\ No newline at end of file
+}
+// AggregationResult holds time-bucketed log counts from LogTailer.Aggregate
+type AggregationResult struct {
+	Start     time.Time           `json:"start"`
+	End       time.Time           `json:"end"`
+	Bucket    time.Duration       `json:"bucket"`
+	GroupBy   []string            `json:"group_by"`
+	Series    []AggregationSeries `json:"series"`
+	Totals    []AggregationPoint  `json:"totals"`
+	Total     int64               `json:"total"`
+	Scanned   int                 `json:"scanned"`
+	Truncated bool                `json:"truncated"`
+}
+
+// AggregationSeries is the count per bucket for one combination of the
+// group-by values
+type AggregationSeries struct {
+	Key    string             `json:"key"`
+	Group  map[string]string  `json:"group"`
+	Points []AggregationPoint `json:"points"`
+	Total  int64              `json:"total"`
+}
+
+// AggregationPoint is the count for the bucket starting at Time
+type AggregationPoint struct {
+	Time  time.Time `json:"time"`
+	Count int64     `json:"count"`
+}
@@ -19,6 +19,9 @@ type Options struct {
 	MetricsAddr string
 	Theme       string
 	FPS         int
+	// StatePath is the file that keeps the layout, theme and queue filter
+	// between runs; empty disables it.
+	StatePath string
 }
 
 // New constructs the TUI model.
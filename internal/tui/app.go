@@ -8,6 +8,7 @@ import (
 	bubprog "github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/flyingrobots/go-redis-work-queue/internal/admin"
@@ -22,8 +23,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		if m.confirmOpen {
-			if m.opts.ReadOnly && (m.confirmAction == "purge-dlq" || m.confirmAction == "purge-all") {
-				m.errText = "read-only mode: purge disabled"
+			if m.opts.ReadOnly && (m.confirmAction == "purge-dlq" || m.confirmAction == "purge-all" || m.confirmAction == "move" || m.confirmAction == "cancel-scheduled") {
+				m.errText = "read-only mode: destructive actions disabled"
 				m.confirmOpen = false
 				return m, nil
 			}
@@ -31,6 +32,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "y", "enter":
 				if m.confirmAction == "quit" {
 					m.confirmOpen = false
+					m.saveState()
 					m.cancel()
 					return m, tea.Quit
 				}
@@ -56,16 +58,42 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						}
 						return statsMsg{}
 					}, spinner.Tick, m.refreshCmd(), m.fetchKeysCmd())
+				case "move":
+					m.loading = true
+					m.errText = ""
+					m.confirmOpen = false
+					cmds = append(cmds, m.doMoveCmd(m.moveFrom, m.moveTo), spinner.Tick)
+				case "cancel-scheduled":
+					m.errText = ""
+					m.confirmOpen = false
+					cmds = append(cmds, m.doCancelScheduledCmd(m.cancelJob))
 				}
 			case "n", "esc":
 				m.confirmOpen = false
 			case "q", "ctrl+c":
+				m.saveState()
 				m.cancel()
 				return m, tea.Quit
 			}
 			return m, tea.Batch(cmds...)
 		}
+		if m.editOpen {
+			return m.updateEditor(msg)
+		}
+		if m.paletteOpen {
+			return m.updatePalette(msg)
+		}
+		if m.activeTab == tabScheduled {
+			if cmd, ok := m.handleScheduledKey(msg.String()); ok {
+				return m, cmd
+			}
+		}
 		switch msg.String() {
+		case ":":
+			if !m.filterActive && !m.benchCount.Focused() && !m.benchRate.Focused() && !m.benchPriority.Focused() && !m.benchTimeout.Focused() {
+				m.openPalette()
+				return m, textinput.Blink
+			}
 		case "ctrl+c", "q":
 			m.confirmOpen = true
 			m.confirmAction = "quit"
@@ -75,13 +103,16 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		case "2":
 			m.activeTab = tabWorkers
-			return m, nil
+			return m, m.fetchWorkersCmd()
 		case "3":
 			m.activeTab = tabDLQ
 			return m, nil
 		case "4":
 			m.activeTab = tabSettings
 			return m, nil
+		case "5":
+			m.activeTab = tabScheduled
+			return m, m.fetchScheduledCmd()
 		case "tab":
 			m.focus = (m.focus + 1) % 3
 			return m, nil
@@ -93,7 +124,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 		case "r":
-			return m, tea.Batch(m.refreshCmd(), m.fetchKeysCmd())
+			return m, tea.Batch(m.refreshCmd(), m.fetchKeysCmd(), m.fetchDLQCmd())
+		case "L":
+			if !m.filterActive && !m.benchCount.Focused() && !m.benchRate.Focused() && !m.benchPriority.Focused() && !m.benchTimeout.Focused() {
+				m.setLayout((m.layoutIdx + 1) % len(layoutStrategies))
+				return m, nil
+			}
 		case "h", "?":
 			m.help2.SetIsActive(!m.help2.Active)
 			if m.help2.Active {
@@ -161,6 +197,39 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.help2.GotoTop()
 				}
 			}
+		case "m":
+			if m.opts.ReadOnly {
+				m.errText = "read-only mode: move disabled"
+				return m, nil
+			}
+			i := m.tbl.Cursor()
+			if i < 0 || i >= len(m.peekTargets) {
+				return m, nil
+			}
+			from := m.peekTargets[i]
+			to := m.moveDestination(from)
+			if to == "" {
+				m.errText = "move: select a priority queue with another queue to move into"
+				return m, nil
+			}
+			m.moveFrom, m.moveTo = from, to
+			m.confirmOpen = true
+			m.confirmAction = "move"
+		case "P":
+			if m.opts.ReadOnly {
+				m.errText = "read-only mode: pause disabled"
+				return m, nil
+			}
+			i := m.tbl.Cursor()
+			if i < 0 || i >= len(m.peekTargets) {
+				return m, nil
+			}
+			key := m.peekTargets[i]
+			if !m.isWorkerQueue(key) {
+				m.errText = "pause: select a priority queue"
+				return m, nil
+			}
+			cmds = append(cmds, m.doPauseCmd(key, !m.lastStats.Paused[key]))
 		case "D":
 			if m.opts.ReadOnly {
 				m.errText = "read-only mode: purge disabled"
@@ -201,31 +270,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		headerLines := 4 // tabs + header + sub + blank
-		if m.filterActive || strings.TrimSpace(m.filter.Value()) != "" {
-			headerLines++
-		}
-		footerLines := 2
-		availH := m.height - headerLines - footerLines
-		if availH < 6 {
-			availH = 6
-		}
-		bottomH := availH / 3
-		topH := availH - bottomH
-		leftW := m.width / 2
-		if leftW < 30 {
-			leftW = 30
-		}
-		rightW := m.width - leftW - 3
-		if rightW < 20 {
-			rightW = 20
-		}
-		m.tbl.SetWidth(leftW - 4)
-		m.tbl.SetHeight(topH - 3)
-		m.vpCharts.Width = rightW - 2
-		m.vpCharts.Height = topH - 2
-		m.vpInfo.Width = m.width - 4
-		m.vpInfo.Height = bottomH - 2
+		m.resizePanels()
 		m.sb.SetSize(m.width)
 		hw := m.width - 10
 		if hw < 40 {
@@ -241,13 +286,19 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case tea.MouseMsg:
-		if !m.confirmOpen {
+		if !m.confirmOpen && !m.paletteOpen {
 			// Tab bar click handling (first row)
 			if msg.Button == tea.MouseButtonLeft && msg.Action == tea.MouseActionPress && msg.Y == 0 {
 				_, zones := m.buildTabBar()
 				for _, z := range zones {
 					if msg.X >= z.start && msg.X < z.end {
 						m.activeTab = z.id
+						switch z.id {
+						case tabScheduled:
+							return m, m.fetchScheduledCmd()
+						case tabWorkers:
+							return m, m.fetchWorkersCmd()
+						}
 						return m, nil
 					}
 				}
@@ -302,7 +353,17 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 	case tick:
-		cmds = append(cmds, m.refreshCmd(), m.fetchKeysCmd(), tea.Every(m.refreshEvery, func(time.Time) tea.Msg { return tick{} }))
+		cmds = append(cmds, m.refreshCmd(), m.fetchKeysCmd(), m.fetchDLQCmd(), tea.Every(m.refreshEvery, func(time.Time) tea.Msg { return tick{} }))
+		switch m.activeTab {
+		case tabScheduled:
+			cmds = append(cmds, m.fetchScheduledCmd())
+		case tabWorkers:
+			cmds = append(cmds, m.fetchWorkersCmd())
+		}
+		if key := m.focusedWorkerQueue(); key != "" && m.activeTab == tabJobs && !m.drainBusy {
+			m.drainBusy = true
+			cmds = append(cmds, m.fetchDrainCmd(key))
+		}
 	case statsMsg:
 		if msg.err != nil {
 			m.errText = msg.err.Error()
@@ -324,13 +385,16 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			ordered = append(ordered, fmt.Sprintf("completed (%s)", m.cfg.Worker.CompletedList))
 			ordered = append(ordered, fmt.Sprintf("dead_letter (%s)", m.cfg.Worker.DeadLetterList))
 			for _, display := range ordered {
-				cnt := msg.s.Queues[display]
-				rows = append(rows, table.Row{display, fmt.Sprintf("%d", cnt)})
+				target := display
 				if idx := strings.LastIndex(display, "("); idx != -1 && strings.HasSuffix(display, ")") {
-					m.peekTargets = append(m.peekTargets, display[idx+1:len(display)-1])
-				} else {
-					m.peekTargets = append(m.peekTargets, display)
+					target = display[idx+1 : len(display)-1]
 				}
+				count := fmt.Sprintf("%d", msg.s.Queues[display])
+				if msg.s.Paused[target] {
+					count += " paused"
+				}
+				rows = append(rows, table.Row{display, count})
+				m.peekTargets = append(m.peekTargets, target)
 			}
 			m.allRows = rows
 			m.allTargets = append([]string(nil), m.peekTargets...)
@@ -347,6 +411,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.lastKeys = msg.k
 			m.errText = ""
 		}
+	case dlqMsg:
+		if msg.err != nil {
+			m.errText = msg.err.Error()
+		} else {
+			m.lastDLQ = msg.r
+		}
 	case peekMsg:
 		m.loading = false
 		if msg.err != nil {
@@ -354,6 +424,67 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		} else {
 			m.lastPeek = msg.p
 		}
+	case moveMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.errText = msg.err.Error()
+		} else {
+			m.errText = ""
+			cmds = append(cmds, m.refreshCmd(), m.fetchKeysCmd())
+		}
+	case scheduledMsg:
+		if msg.err != nil {
+			m.errText = msg.err.Error()
+		} else {
+			m.lastScheduled = msg.jobs
+			if m.schedCursor >= len(m.lastScheduled) {
+				m.schedCursor = max(len(m.lastScheduled)-1, 0)
+			}
+		}
+	case workersMsg:
+		if msg.err != nil {
+			m.errText = msg.err.Error()
+		} else {
+			m.lastWorkers = msg.workers
+		}
+	case drainMsg:
+		m.drainBusy = false
+		if msg.err != nil {
+			m.errText = msg.err.Error()
+		} else {
+			m.lastDrain = msg.est
+		}
+	case cancelScheduledMsg:
+		if msg.err != nil {
+			m.errText = msg.err.Error()
+		} else {
+			m.errText = ""
+		}
+		cmds = append(cmds, m.fetchScheduledCmd())
+	case editJobMsg:
+		if msg.err != nil {
+			m.editIssues = []string{"error: " + msg.err.Error()}
+		} else {
+			m.editOpen = false
+			m.editor.Blur()
+			m.errText = ""
+			cmds = append(cmds, m.doPeekCmd(msg.source, 10), m.refreshCmd())
+		}
+	case reorderMsg:
+		if msg.err != nil {
+			m.errText = msg.err.Error()
+		} else {
+			// re-peek so the new order shows
+			m.errText = ""
+			cmds = append(cmds, m.doPeekCmd(msg.key, 10))
+		}
+	case pauseMsg:
+		if msg.err != nil {
+			m.errText = msg.err.Error()
+		} else {
+			m.errText = ""
+			cmds = append(cmds, m.refreshCmd())
+		}
 	case benchMsg:
 		m.loading = false
 		if msg.err != nil {
@@ -396,6 +527,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	if m.editOpen {
+		var c tea.Cmd
+		m.editor, c = m.editor.Update(msg)
+		cmds = append(cmds, c)
+	}
 	if m.loading {
 		var c tea.Cmd
 		m.spinner, c = m.spinner.Update(msg)
@@ -25,6 +25,16 @@ func (m model) fetchKeysCmd() tea.Cmd {
 	}
 }
 
+// dlqSampleSize bounds how many DLQ items the Info panel analyzes per refresh.
+const dlqSampleSize = 200
+
+func (m model) fetchDLQCmd() tea.Cmd {
+	return func() tea.Msg {
+		r, err := admin.DLQAnalytics(m.ctx, m.cfg, m.rdb, dlqSampleSize)
+		return dlqMsg{r: r, err: err}
+	}
+}
+
 func (m model) doPeekCmd(target string, n int) tea.Cmd {
 	return func() tea.Msg {
 		p, err := admin.Peek(m.ctx, m.cfg, m.rdb, target, int64(n))
@@ -0,0 +1,44 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/admin"
+)
+
+// focusedWorkerQueue returns the key of the priority queue selected in the
+// queues table, or "" when the selection is another list.
+func (m model) focusedWorkerQueue() string {
+	i := m.tbl.Cursor()
+	if i < 0 || i >= len(m.peekTargets) || !m.isWorkerQueue(m.peekTargets[i]) {
+		return ""
+	}
+	return m.peekTargets[i]
+}
+
+// fetchDrainCmd estimates when key will empty. It samples for a few
+// seconds, so at most one runs at a time.
+func (m model) fetchDrainCmd(key string) tea.Cmd {
+	return func() tea.Msg {
+		est, err := admin.TimeToDrain(m.ctx, m.cfg, m.rdb, key)
+		return drainMsg{key: key, est: est, err: err}
+	}
+}
+
+// drainStatus is the status bar's time-to-drain for the focused queue, or
+// "" while none has been estimated for it.
+func drainStatus(m model) string {
+	key := m.focusedWorkerQueue()
+	est := m.lastDrain
+	if key == "" || est == nil || est.Queue != key {
+		return ""
+	}
+	name := est.Priority
+	if name == "" {
+		name = est.Queue
+	}
+	if est.Drains && est.Length > 0 {
+		return "⏳ " + name + " drains " + est.Summary
+	}
+	return "⏳ " + name + ": " + est.Summary
+}
@@ -0,0 +1,220 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"go.uber.org/zap"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/admin"
+	jsonpayloadstudio "github.com/flyingrobots/go-redis-work-queue/internal/json-payload-studio"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+)
+
+// editMaxIssues bounds how many validation issues the job editor lists.
+const editMaxIssues = 6
+
+// editCommands offers to edit each job of the last peek and enqueue the
+// corrected payload.
+func (m model) editCommands() []paletteCommand {
+	key := m.lastPeek.Queue
+	if key == "" {
+		return nil
+	}
+	var cmds []paletteCommand
+	for i := len(m.lastPeek.Items) - 1; i >= 0; i-- {
+		item := m.lastPeek.Items[i]
+		job, err := queue.UnmarshalJob(item)
+		if err != nil || job.ID == "" {
+			continue
+		}
+		cmds = append(cmds, paletteCommand{
+			title:       fmt.Sprintf("Edit and re-enqueue job %s from %s", job.ID, key),
+			destructive: true,
+			run:         func(m *model) tea.Cmd { return m.openEditor(key, item) },
+		})
+	}
+	return cmds
+}
+
+// openEditor opens item, peeked from source, in the job editor. The
+// original is removed on save unless toggled off, except from the
+// completed list, whose entries are history rather than pending work.
+func (m *model) openEditor(source, item string) tea.Cmd {
+	if m.opts.ReadOnly {
+		m.errText = "read-only mode: edit disabled"
+		return nil
+	}
+	if m.studio == nil {
+		logger := m.logger
+		if logger == nil {
+			logger = zap.NewNop()
+		}
+		studio, err := jsonpayloadstudio.NewJSONPayloadStudio(jsonpayloadstudio.DefaultConfig(), m.rdb, logger)
+		if err != nil {
+			m.errText = err.Error()
+			return nil
+		}
+		studio.SetQueueConfig(m.cfg)
+		m.studio = studio
+	}
+	content := item
+	var pretty bytes.Buffer
+	if json.Indent(&pretty, []byte(item), "", "  ") == nil {
+		content = pretty.String()
+	}
+
+	ed := textarea.New()
+	ed.ShowLineNumbers = true
+	ed.CharLimit = 0
+	ed.MaxHeight = 0
+	m.sizeEditor(&ed)
+	ed.SetValue(content)
+	m.editor = ed
+	m.editOpen, m.editConfirm = true, false
+	m.editSource, m.editOriginal = source, item
+	m.editRemove = source != m.cfg.Worker.CompletedList
+	m.editIssues = nil
+	return m.editor.Focus()
+}
+
+func (m model) sizeEditor(ed *textarea.Model) {
+	w, h := m.width, m.height
+	if w <= 0 {
+		w = 80
+	}
+	if h <= 0 {
+		h = 24
+	}
+	ed.SetWidth(min(w-8, 120))
+	ed.SetHeight(max(h-16, 5))
+}
+
+// validateEdit checks the editor content with the JSON Payload Studio's
+// validator and as a job payload. It returns the compacted payload, or
+// the problems found.
+func (m *model) validateEdit() (string, bool) {
+	content := m.editor.Value()
+	res := m.studio.ValidateJSON(content, nil)
+	var issues []string
+	for _, e := range res.Errors {
+		issues = append(issues, fmt.Sprintf("error %d:%d: %s", e.Line, e.Column, e.Message))
+	}
+	if res.Valid {
+		if _, err := queue.UnmarshalJob(content); err != nil {
+			res.Valid = false
+			issues = append(issues, "error: not a job payload: "+err.Error())
+		}
+	}
+	for _, w := range res.Warnings {
+		issues = append(issues, "warning: "+w.Message)
+	}
+	m.editIssues = issues
+	if !res.Valid {
+		return "", false
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, []byte(content)); err != nil {
+		m.editIssues = append([]string{"error: " + err.Error()}, issues...)
+		return "", false
+	}
+	return compact.String(), true
+}
+
+// doEditJobCmd enqueues the corrected job, removing the original first
+// when asked.
+func (m model) doEditJobCmd(edited string) tea.Cmd {
+	source, original, remove := m.editSource, m.editOriginal, m.editRemove
+	return func() tea.Msg {
+		dest, err := admin.ReenqueueEdited(m.ctx, m.cfg, m.rdb, source, original, edited, admin.EditOptions{RemoveOriginal: remove})
+		return editJobMsg{source: source, dest: dest, err: err}
+	}
+}
+
+// updateEditor handles keys while the job editor is open. ctrl+s
+// validates and asks to confirm the write; ctrl+o formats; ctrl+r
+// toggles removing the original.
+func (m model) updateEditor(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.editConfirm {
+		switch msg.String() {
+		case "y", "enter":
+			m.editConfirm = false
+			if m.opts.ReadOnly {
+				m.errText = "read-only mode: edit disabled"
+				m.editOpen = false
+				return m, nil
+			}
+			edited, ok := m.validateEdit()
+			if !ok {
+				return m, nil
+			}
+			return m, m.doEditJobCmd(edited)
+		case "n", "esc":
+			m.editConfirm = false
+		}
+		return m, nil
+	}
+	switch msg.String() {
+	case "esc":
+		m.editOpen = false
+		m.editor.Blur()
+		return m, nil
+	case "ctrl+s":
+		if _, ok := m.validateEdit(); ok {
+			m.editConfirm = true
+		}
+		return m, nil
+	case "ctrl+o":
+		if formatted, err := m.studio.FormatJSON(m.editor.Value()); err != nil {
+			m.editIssues = []string{"error: " + err.Error()}
+		} else {
+			m.editor.SetValue(formatted)
+		}
+		return m, nil
+	case "ctrl+r":
+		m.editRemove = !m.editRemove
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.editor, cmd = m.editor.Update(msg)
+	return m, cmd
+}
+
+// renderEditorOverlay dims the screen and shows the job editor.
+func renderEditorOverlay(m model) string {
+	title := "Edit job from " + m.editSource
+	if job, err := queue.UnmarshalJob(m.editOriginal); err == nil && job.ID != "" {
+		title = fmt.Sprintf("Edit job %s from %s", job.ID, m.editSource)
+	}
+	remove := "off"
+	if m.editRemove {
+		remove = "on"
+	}
+	lines := []string{lipgloss.NewStyle().Bold(true).Render(title), m.editor.View()}
+	if n := len(m.editIssues); n > 0 {
+		issues := m.editIssues
+		if n > editMaxIssues {
+			issues = append(issues[:editMaxIssues:editMaxIssues], fmt.Sprintf("... %d more", n-editMaxIssues))
+		}
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("203")).Render(strings.Join(issues, "\n")))
+	}
+	if m.editConfirm {
+		prompt := "Enqueue the edited job to the queue of its priority"
+		if m.editRemove {
+			prompt += " and remove the original from " + m.editSource
+		}
+		lines = append(lines, lipgloss.NewStyle().Bold(true).Render(prompt+"?"), "[y] Yes   [n] No")
+	} else {
+		lines = append(lines, fmt.Sprintf("[ctrl+s] validate and save   [ctrl+o] format   [ctrl+r] remove original: %s   [esc] cancel", remove))
+	}
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("212")).
+		Padding(0, 1)
+	return renderScrim(m, box.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+}
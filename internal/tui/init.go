@@ -51,6 +51,11 @@ func initialModel(cfg *config.Config, rdb *redis.Client, logger *zap.Logger, ref
 	fi.Placeholder = "filter"
 	fi.CharLimit = 64
 
+	pi := textinput.New()
+	pi.Prompt = ": "
+	pi.Placeholder = "type a command"
+	pi.CharLimit = 64
+
 	boxTitle := lipgloss.NewStyle().Bold(true)
 	boxBody := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
 
@@ -65,10 +70,15 @@ func initialModel(cfg *config.Config, rdb *redis.Client, logger *zap.Logger, ref
 		{Key: "q", Description: "Quit"},
 		{Key: "tab/shift+tab", Description: "Focus next/prev panel"},
 		{Key: "j/k, wheel", Description: "Scroll selected panel"},
+		{Key: ":", Description: "Command palette (fuzzy)"},
 		{Key: "f or /", Description: "Filter queues (fuzzy)"},
 		{Key: "p", Description: "Peek selected queue"},
 		{Key: "b", Description: "Bench form (enter to run)"},
+		{Key: "m", Description: "Move jobs to next priority (y/n)"},
+		{Key: "P", Description: "Pause/resume selected queue"},
 		{Key: "D / A", Description: "Purge DLQ / ALL (y/n)"},
+		{Key: "5", Description: "Scheduled jobs (w: due within, x: cancel)"},
+		{Key: "L", Description: "Next layout (split, queues, charts, logs)"},
 		{Key: "h/?", Description: "Toggle help"},
 	}
 	help2 := tchelp.New(false, false, "Help",
@@ -80,7 +90,7 @@ func initialModel(cfg *config.Config, rdb *redis.Client, logger *zap.Logger, ref
 		fps = 60
 	}
 
-	return model{
+	m := model{
 		ctx:           ctx,
 		cancel:        cancel,
 		cfg:           cfg,
@@ -100,6 +110,8 @@ func initialModel(cfg *config.Config, rdb *redis.Client, logger *zap.Logger, ref
 		series:        map[string][]float64{"high": {}, "low": {}, "completed": {}, "dead_letter": {}},
 		seriesMax:     180,
 		filter:        fi,
+		palette:       pi,
+		darkTheme:     lipgloss.HasDarkBackground(),
 		vpCharts:      viewport.New(0, 10),
 		vpInfo:        viewport.New(0, 10),
 		boxTitle:      boxTitle,
@@ -114,4 +126,12 @@ func initialModel(cfg *config.Config, rdb *redis.Client, logger *zap.Logger, ref
 		expTarget:     0.0,
 		expActive:     false,
 	}
+	if opts.StatePath != "" {
+		if st, err := loadUIState(opts.StatePath); err != nil {
+			m.errText = "load TUI state: " + err.Error()
+		} else {
+			m.applyUIState(st)
+		}
+	}
+	return m
 }
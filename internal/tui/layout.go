@@ -1,7 +1,186 @@
 package tui
 
+import (
+	flexbox "github.com/76creates/stickers/flexbox"
+	"github.com/charmbracelet/lipgloss"
+)
+
 // layout helpers for view composition
 
+// panelID names a panel of the Job Queue tab.
+type panelID int
+
+const (
+	panelQueues panelID = iota
+	panelCharts
+	panelInfo
+)
+
+// layoutCell is one panel and its share of its row's width.
+type layoutCell struct {
+	panel panelID
+	ratio int
+}
+
+// layoutRow is a row of panels and its share of the body height.
+type layoutRow struct {
+	ratio int
+	cells []layoutCell
+}
+
+// layoutStrategy arranges the Job Queue tab panels for a body size; expPos
+// is the Charts expansion animation (0 balanced, 1 expanded). Panels an
+// arrangement leaves out are not shown.
+type layoutStrategy struct {
+	name    string
+	title   string
+	arrange func(w, h int, expPos float64) []layoutRow
+}
+
+// layoutNarrowWidth is the body width below which layouts stack panels.
+const layoutNarrowWidth = 120
+
+// layoutStrategies are the presets L cycles through, default first.
+var layoutStrategies = []layoutStrategy{
+	{name: "split", title: "Split", arrange: arrangeSplit},
+	{name: "queues-focus", title: "Queues focus", arrange: arrangeFocus(panelQueues, panelCharts, panelInfo)},
+	{name: "charts-focus", title: "Charts focus", arrange: arrangeFocus(panelCharts, panelQueues, panelInfo)},
+	{name: "logs", title: "Logs", arrange: arrangeLogs},
+}
+
+// layoutIndex returns the position of the named preset in layoutStrategies.
+func layoutIndex(name string) (int, bool) {
+	for i, l := range layoutStrategies {
+		if l.name == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func (m model) layout() layoutStrategy {
+	return layoutStrategies[m.layoutIdx]
+}
+
+// arrangeSplit is the original dashboard: Queues | Charts over Info, with
+// Charts widening as expPos grows; narrow terminals stack all three.
+func arrangeSplit(w, h int, expPos float64) []layoutRow {
+	if w < layoutNarrowWidth {
+		return []layoutRow{
+			{ratio: 2, cells: []layoutCell{{panelQueues, 1}}},
+			{ratio: 2, cells: []layoutCell{{panelCharts, 1}}},
+			{ratio: 1, cells: []layoutCell{{panelInfo, 1}}},
+		}
+	}
+	// 0.0 => 1:1, 1.0 => 1:2 (Charts wider)
+	base := 100
+	right := base + int(float64(base)*expPos)
+	if right < 1 {
+		right = 1
+	}
+	return []layoutRow{
+		{ratio: 2, cells: []layoutCell{{panelQueues, base}, {panelCharts, right}}},
+		{ratio: 1, cells: []layoutCell{{panelInfo, 1}}},
+	}
+}
+
+// arrangeFocus gives main the top three quarters and puts the other two
+// panels side by side below it; narrow terminals drop the second one.
+func arrangeFocus(main, second, third panelID) func(w, h int, expPos float64) []layoutRow {
+	return func(w, h int, expPos float64) []layoutRow {
+		if w < layoutNarrowWidth {
+			return []layoutRow{
+				{ratio: 3, cells: []layoutCell{{main, 1}}},
+				{ratio: 1, cells: []layoutCell{{third, 1}}},
+			}
+		}
+		return []layoutRow{
+			{ratio: 3, cells: []layoutCell{{main, 1}}},
+			{ratio: 1, cells: []layoutCell{{second, 1}, {third, 1}}},
+		}
+	}
+}
+
+// arrangeLogs gives Info, where peeks, DLQ reports and bench output land,
+// three quarters of the height.
+func arrangeLogs(w, h int, expPos float64) []layoutRow {
+	if w < layoutNarrowWidth {
+		return []layoutRow{
+			{ratio: 1, cells: []layoutCell{{panelQueues, 1}}},
+			{ratio: 3, cells: []layoutCell{{panelInfo, 1}}},
+		}
+	}
+	return []layoutRow{
+		{ratio: 1, cells: []layoutCell{{panelQueues, 1}, {panelCharts, 1}}},
+		{ratio: 3, cells: []layoutCell{{panelInfo, 1}}},
+	}
+}
+
+// panelBox locates a panel's cell in the flexbox and holds its inner size.
+type panelBox struct {
+	row, col int
+	w, h     int
+}
+
+// arrangeJobs lays out the Job Queue tab with the current strategy, sized
+// and ready for content, and returns where each shown panel went. Panels
+// sharing a row are separated by a two-column gutter.
+func (m model) arrangeJobs(panel lipgloss.Style) (*flexbox.FlexBox, map[panelID]panelBox) {
+	bodyW, bodyH := m.bodyDims()
+	fb := flexbox.New(bodyW, bodyH)
+	boxes := make(map[panelID]panelBox, 3)
+	var rows []*flexbox.Row
+	for ri, r := range m.layout().arrange(bodyW, bodyH, m.expPos) {
+		row := fb.NewRow()
+		for ci, c := range r.cells {
+			if ci > 0 {
+				row.AddCells(flexbox.NewCell(0, r.ratio).SetMinWidth(2).SetContent(""))
+			}
+			boxes[c.panel] = panelBox{row: ri, col: row.CellsLen()}
+			row.AddCells(flexbox.NewCell(c.ratio, r.ratio).SetStyle(panel))
+		}
+		rows = append(rows, row)
+	}
+	fb.SetRows(rows)
+
+	// Size pass: compute inner dimensions for contents
+	fb.ForceRecalculate()
+	for p, b := range boxes {
+		cell := fb.GetRowCellCopy(b.row, b.col)
+		// Panel overhead: 2 border + 2 padding (h), 2 border (v)
+		b.w, b.h = max(cell.GetWidth()-4, 1), max(cell.GetHeight()-2, 1)
+		boxes[p] = b
+	}
+	return fb, boxes
+}
+
+// setLayout switches the Job Queue tab to layoutStrategies[i] and
+// remembers the choice.
+func (m *model) setLayout(i int) {
+	m.layoutIdx = i
+	m.resizePanels()
+	m.saveState()
+}
+
+// resizePanels sizes the Queues table and the panel viewports to the
+// current layout.
+func (m *model) resizePanels() {
+	_, boxes := m.arrangeJobs(m.boxBody)
+	if b, ok := boxes[panelQueues]; ok {
+		m.tbl.SetWidth(b.w)
+		// minus the title and filter bar lines
+		m.tbl.SetHeight(max(b.h-2, 3))
+	}
+	if b, ok := boxes[panelCharts]; ok {
+		m.vpCharts.Width = b.w
+		m.vpCharts.Height = b.h - 1
+	}
+	if b, ok := boxes[panelInfo]; ok {
+		m.vpInfo.Width = b.w
+		m.vpInfo.Height = b.h - 1 // minus title line
+	}
+}
+
 // bodyDims computes the body width and height available for panels,
 // accounting for header/sub lines, optional filter line, and footer/status bar.
 func (m model) bodyDims() (w, h int) {
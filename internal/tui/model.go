@@ -8,6 +8,7 @@ import (
 	bubprog "github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	"github.com/charmbracelet/harmonica"
@@ -19,6 +20,7 @@ import (
 
 	"github.com/flyingrobots/go-redis-work-queue/internal/admin"
 	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	jsonpayloadstudio "github.com/flyingrobots/go-redis-work-queue/internal/json-payload-studio"
 )
 
 // focusable panels on the dashboard
@@ -40,6 +42,10 @@ type (
 		k   admin.KeysStats
 		err error
 	}
+	dlqMsg struct {
+		r   *admin.DLQReport
+		err error
+	}
 	peekMsg struct {
 		p   admin.PeekResult
 		err error
@@ -48,6 +54,43 @@ type (
 		b   admin.BenchResult
 		err error
 	}
+	moveMsg struct {
+		n        int
+		from, to string
+		err      error
+	}
+	pauseMsg struct {
+		key    string
+		paused bool
+		err    error
+	}
+	scheduledMsg struct {
+		jobs []admin.ScheduledJob
+		err  error
+	}
+	cancelScheduledMsg struct {
+		err error
+	}
+	reorderMsg struct {
+		key     string
+		jobID   string
+		promote bool
+		err     error
+	}
+	workersMsg struct {
+		workers []admin.WorkerInfo
+		err     error
+	}
+	drainMsg struct {
+		key string
+		est *admin.DrainEstimate
+		err error
+	}
+	editJobMsg struct {
+		source string
+		dest   string
+		err    error
+	}
 	enqueueMsg struct {
 		n   int
 		key string
@@ -86,8 +129,34 @@ type model struct {
 	lastStats admin.StatsResult
 	lastKeys  admin.KeysStats
 	lastPeek  admin.PeekResult
+	lastDLQ   *admin.DLQReport
 	lastBench admin.BenchResult
 
+	// Scheduled tab: upcoming jobs, selection and due-within filter index
+	lastScheduled []admin.ScheduledJob
+	schedCursor   int
+	schedWithin   int
+
+	// Workers tab: per-worker heartbeat and processing list detail
+	lastWorkers []admin.WorkerInfo
+
+	// Time-to-drain of the focused queue, for the status bar; drainBusy
+	// while an estimate is sampling
+	lastDrain *admin.DrainEstimate
+	drainBusy bool
+
+	// Job editor: a peeked job opened for correction and re-enqueue.
+	// editRemove takes the original out of editSource on save; studio
+	// validates the edit and is created on first use.
+	editOpen     bool
+	editConfirm  bool
+	editor       textarea.Model
+	editSource   string
+	editOriginal string
+	editRemove   bool
+	editIssues   []string
+	studio       *jsonpayloadstudio.JSONPayloadStudio
+
 	// Bench prompt inputs
 	benchCount    textinput.Model
 	benchRate     textinput.Model
@@ -106,6 +175,15 @@ type model struct {
 	// confirmation modal state
 	confirmOpen   bool
 	confirmAction string
+	moveFrom      string
+	moveTo        string
+	cancelJob     admin.ScheduledJob
+
+	// Command palette state
+	palette       textinput.Model
+	paletteOpen   bool
+	paletteCursor int
+	darkTheme     bool
 
 	// Filter state for queues view
 	filter       textinput.Model
@@ -133,6 +211,9 @@ type model struct {
 	// Tabs
 	activeTab tabID
 
+	// Job Queue tab layout, an index into layoutStrategies
+	layoutIdx int
+
 	// Expansion animation (Jobs: Queues | Charts)
 	spring    harmonica.Spring
 	expPos    float64 // 0.0 = 50/50, 1.0 = Charts expanded (1:2)
@@ -148,6 +229,7 @@ const (
 	tabJobs tabID = iota
 	tabWorkers
 	tabDLQ
+	tabScheduled
 	tabTimeTravel
 	tabEventHooks
 	tabSettings
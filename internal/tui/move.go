@@ -0,0 +1,35 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/admin"
+)
+
+// moveBatchSize is how many jobs a single TUI move action transfers.
+const moveBatchSize = 100
+
+// doMoveCmd moves up to moveBatchSize jobs between two queue keys.
+func (m model) doMoveCmd(from, to string) tea.Cmd {
+	return func() tea.Msg {
+		n, err := admin.MoveJobs(m.ctx, m.cfg, m.rdb, from, to, moveBatchSize)
+		return moveMsg{n: n, from: from, to: to, err: err}
+	}
+}
+
+// moveDestination picks the next priority queue after from, wrapping around.
+// It returns "" when from is not a priority queue or there is nowhere to go.
+func (m model) moveDestination(from string) string {
+	prios := m.cfg.Worker.Priorities
+	for i, p := range prios {
+		if m.cfg.Worker.Queues[p] != from {
+			continue
+		}
+		for j := 1; j < len(prios); j++ {
+			if key := m.cfg.Worker.Queues[prios[(i+j)%len(prios)]]; key != "" && key != from {
+				return key
+			}
+		}
+	}
+	return ""
+}
@@ -1,7 +1,9 @@
 package tui
 
 import (
+	"fmt"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -22,6 +24,10 @@ func renderConfirmModal(m model) string {
 		msg = "Purge dead letter queue?"
 	case "purge-all":
 		msg = "Purge ALL managed keys?"
+	case "move":
+		msg = fmt.Sprintf("Move up to %d jobs from %s to %s?", moveBatchSize, m.moveFrom, m.moveTo)
+	case "cancel-scheduled":
+		msg = fmt.Sprintf("Cancel job on %s due %s?\n%s", m.cancelJob.Queue, formatDue(m.cancelJob.DueAt, time.Now()), m.cancelJob.Preview)
 	default:
 		msg = m.confirmAction
 	}
@@ -50,58 +56,16 @@ func renderConfirmModal(m model) string {
 
 // renderOverlayScreen builds a full-screen dimmed scrim and centers the confirm modal.
 func renderOverlayScreen(m model) string {
-	width := m.width
-	height := m.height
-	if width <= 0 {
-		width = 80
-	}
-	if height <= 0 {
-		height = 24
-	}
-
-	scrimCell := lipgloss.NewStyle().Background(lipgloss.Color("236")).Faint(true).Render(" ")
-	line := strings.Repeat(scrimCell, width)
-	lines := make([]string, height)
-	for i := 0; i < height; i++ {
-		lines[i] = line
-	}
-
-	modal := renderConfirmModal(m)
-	modalLines := strings.Split(modal, "\n")
-	modalH := len(modalLines)
-	modalW := 0
-	for _, ml := range modalLines {
-		if w := lipgloss.Width(ml); w > modalW {
-			modalW = w
-		}
-	}
-	top := (height - modalH) / 2
-	left := (width - modalW) / 2
-	if top < 0 {
-		top = 0
-	}
-	if left < 0 {
-		left = 0
-	}
-	for i := 0; i < modalH && (top+i) < height; i++ {
-		ml := modalLines[i]
-		lp := left
-		rp := width - (left + lipgloss.Width(ml))
-		if lp < 0 {
-			lp = 0
-		}
-		if rp < 0 {
-			rp = 0
-		}
-		leftPad := strings.Repeat(scrimCell, lp)
-		rightPad := strings.Repeat(scrimCell, rp)
-		lines[top+i] = leftPad + ml + rightPad
-	}
-	return strings.Join(lines, "\n")
+	return renderScrim(m, renderConfirmModal(m))
 }
 
 // renderHelpOverlay dims the background and centers the help view.
 func renderHelpOverlay(m model, _ string) string {
+	return renderScrim(m, m.help2.View())
+}
+
+// renderScrim fills the screen with a dimmed scrim and centers content on it.
+func renderScrim(m model, content string) string {
 	width := m.width
 	height := m.height
 	if width <= 0 {
@@ -118,25 +82,24 @@ func renderHelpOverlay(m model, _ string) string {
 		lines[i] = line
 	}
 
-	hv := m.help2.View()
-	hvLines := strings.Split(hv, "\n")
-	hH := len(hvLines)
-	hW := 0
-	for _, l := range hvLines {
-		if w := lipgloss.Width(l); w > hW {
-			hW = w
+	contentLines := strings.Split(content, "\n")
+	cH := len(contentLines)
+	cW := 0
+	for _, l := range contentLines {
+		if w := lipgloss.Width(l); w > cW {
+			cW = w
 		}
 	}
-	top := (height - hH) / 2
-	left := (width - hW) / 2
+	top := (height - cH) / 2
+	left := (width - cW) / 2
 	if top < 0 {
 		top = 0
 	}
 	if left < 0 {
 		left = 0
 	}
-	for i := 0; i < hH && (top+i) < height; i++ {
-		ml := hvLines[i]
+	for i := 0; i < cH && (top+i) < height; i++ {
+		ml := contentLines[i]
 		lp := left
 		rp := width - (left + lipgloss.Width(ml))
 		if lp < 0 {
@@ -0,0 +1,256 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lithammer/fuzzysearch/fuzzy"
+)
+
+// paletteMaxRows bounds how many matching commands the palette lists.
+const paletteMaxRows = 10
+
+// paletteCommand is one action offered by the ":" command palette.
+type paletteCommand struct {
+	title string
+	key   string // equivalent hotkey, if any
+	// destructive commands change queue state and are hidden in read-only mode
+	destructive bool
+	run         func(m *model) tea.Cmd
+}
+
+// paletteCommands lists the actions available for the current state.
+func (m model) paletteCommands() []paletteCommand {
+	cmds := []paletteCommand{
+		{title: "Refresh", key: "r", run: func(m *model) tea.Cmd {
+			return tea.Batch(m.refreshCmd(), m.fetchKeysCmd(), m.fetchDLQCmd())
+		}},
+		{title: "Peek selected queue", key: "p", run: func(m *model) tea.Cmd {
+			i := m.tbl.Cursor()
+			if i < 0 || i >= len(m.peekTargets) {
+				return nil
+			}
+			return m.startPeek(m.peekTargets[i])
+		}},
+	}
+
+	for _, p := range m.cfg.Worker.Priorities {
+		key := m.cfg.Worker.Queues[p]
+		if key == "" {
+			continue
+		}
+		cmds = append(cmds, paletteCommand{
+			title: fmt.Sprintf("Peek queue %s", p),
+			run:   func(m *model) tea.Cmd { return m.startPeek(key) },
+		})
+		verb := "Pause"
+		if m.lastStats.Paused[key] {
+			verb = "Resume"
+		}
+		cmds = append(cmds, paletteCommand{
+			title:       fmt.Sprintf("%s queue %s", verb, p),
+			destructive: true,
+			run:         func(m *model) tea.Cmd { return m.doPauseCmd(key, !m.lastStats.Paused[key]) },
+		})
+	}
+	if key := m.cfg.Worker.CompletedList; key != "" {
+		cmds = append(cmds, paletteCommand{title: "Peek completed", run: func(m *model) tea.Cmd { return m.startPeek(key) }})
+	}
+	if key := m.cfg.Worker.DeadLetterList; key != "" {
+		cmds = append(cmds, paletteCommand{title: "Peek dead letter queue", run: func(m *model) tea.Cmd { return m.startPeek(key) }})
+	}
+
+	cmds = append(cmds, m.reorderCommands()...)
+	cmds = append(cmds, m.editCommands()...)
+
+	cmds = append(cmds,
+		paletteCommand{title: "Move jobs from selected queue", key: "m", destructive: true, run: func(m *model) tea.Cmd {
+			i := m.tbl.Cursor()
+			if i < 0 || i >= len(m.peekTargets) {
+				return nil
+			}
+			from := m.peekTargets[i]
+			to := m.moveDestination(from)
+			if to == "" {
+				m.errText = "move: select a priority queue with another queue to move into"
+				return nil
+			}
+			m.moveFrom, m.moveTo = from, to
+			m.confirmOpen = true
+			m.confirmAction = "move"
+			return nil
+		}},
+		paletteCommand{title: "Purge DLQ", key: "D", destructive: true, run: func(m *model) tea.Cmd {
+			m.confirmOpen = true
+			m.confirmAction = "purge-dlq"
+			return nil
+		}},
+		paletteCommand{title: "Purge ALL managed keys", key: "A", destructive: true, run: func(m *model) tea.Cmd {
+			m.confirmOpen = true
+			m.confirmAction = "purge-all"
+			return nil
+		}},
+		paletteCommand{title: "Run bench", key: "b", destructive: true, run: func(m *model) tea.Cmd {
+			m.activeTab = tabJobs
+			m.benchCount.Focus()
+			return nil
+		}},
+		paletteCommand{title: "Switch theme", run: func(m *model) tea.Cmd {
+			m.darkTheme = !m.darkTheme
+			lipgloss.SetHasDarkBackground(m.darkTheme)
+			m.saveState()
+			return nil
+		}},
+		paletteCommand{title: "Next layout", key: "L", run: func(m *model) tea.Cmd {
+			m.setLayout((m.layoutIdx + 1) % len(layoutStrategies))
+			return nil
+		}},
+		paletteCommand{title: "Toggle help", key: "h", run: func(m *model) tea.Cmd {
+			m.help2.SetIsActive(!m.help2.Active)
+			if m.help2.Active {
+				m.help2.GotoTop()
+			}
+			return nil
+		}},
+		paletteCommand{title: "Go to Job Queue tab", key: "1", run: func(m *model) tea.Cmd { m.activeTab = tabJobs; return nil }},
+		paletteCommand{title: "Go to Workers tab", key: "2", run: func(m *model) tea.Cmd { m.activeTab = tabWorkers; return m.fetchWorkersCmd() }},
+		paletteCommand{title: "Go to Dead Letter tab", key: "3", run: func(m *model) tea.Cmd { m.activeTab = tabDLQ; return nil }},
+		paletteCommand{title: "Go to Settings tab", key: "4", run: func(m *model) tea.Cmd { m.activeTab = tabSettings; return nil }},
+		paletteCommand{title: "Go to Scheduled tab", key: "5", run: func(m *model) tea.Cmd { m.activeTab = tabScheduled; return m.fetchScheduledCmd() }},
+		paletteCommand{title: "Quit", key: "q", run: func(m *model) tea.Cmd {
+			m.confirmOpen = true
+			m.confirmAction = "quit"
+			return nil
+		}},
+	)
+
+	for i, l := range layoutStrategies {
+		cmds = append(cmds, paletteCommand{
+			title: fmt.Sprintf("Layout: %s", l.title),
+			run:   func(m *model) tea.Cmd { m.setLayout(i); return nil },
+		})
+	}
+
+	if !m.opts.ReadOnly {
+		return cmds
+	}
+	visible := cmds[:0]
+	for _, c := range cmds {
+		if !c.destructive {
+			visible = append(visible, c)
+		}
+	}
+	return visible
+}
+
+// paletteMatches returns the commands matching the palette query, best
+// match first. An empty query lists every command in its default order.
+func (m model) paletteMatches() []paletteCommand {
+	cmds := m.paletteCommands()
+	q := strings.TrimSpace(m.palette.Value())
+	if q == "" {
+		return cmds
+	}
+	titles := make([]string, len(cmds))
+	for i, c := range cmds {
+		titles[i] = c.title
+	}
+	ranks := fuzzy.RankFindNormalizedFold(q, titles)
+	sort.Stable(ranks)
+	out := make([]paletteCommand, 0, len(ranks))
+	for _, rk := range ranks {
+		out = append(out, cmds[rk.OriginalIndex])
+	}
+	return out
+}
+
+// startPeek peeks target and shows the spinner until the result arrives.
+func (m *model) startPeek(target string) tea.Cmd {
+	m.loading = true
+	m.errText = ""
+	return tea.Batch(m.doPeekCmd(target, 10), spinner.Tick)
+}
+
+func (m *model) openPalette() {
+	m.paletteOpen = true
+	m.paletteCursor = 0
+	m.palette.SetValue("")
+	m.palette.Focus()
+}
+
+func (m *model) closePalette() {
+	m.paletteOpen = false
+	m.palette.Blur()
+}
+
+// updatePalette handles keys while the palette is open.
+func (m model) updatePalette(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.closePalette()
+		return m, nil
+	case "enter":
+		matches := m.paletteMatches()
+		m.closePalette()
+		if m.paletteCursor < 0 || m.paletteCursor >= len(matches) {
+			return m, nil
+		}
+		cmd := matches[m.paletteCursor].run(&m)
+		return m, cmd
+	case "up", "ctrl+p", "ctrl+k":
+		if m.paletteCursor > 0 {
+			m.paletteCursor--
+		}
+		return m, nil
+	case "down", "ctrl+n", "ctrl+j":
+		if m.paletteCursor < min(len(m.paletteMatches()), paletteMaxRows)-1 {
+			m.paletteCursor++
+		}
+		return m, nil
+	}
+	var c tea.Cmd
+	m.palette, c = m.palette.Update(msg)
+	m.paletteCursor = 0
+	return m, c
+}
+
+// renderPaletteOverlay dims the background and centers the command palette.
+func renderPaletteOverlay(m model) string {
+	matches := m.paletteMatches()
+	lines := []string{m.palette.View(), ""}
+	if len(matches) == 0 {
+		lines = append(lines, lipgloss.NewStyle().Faint(true).Render("no matching commands"))
+	}
+	selected := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212"))
+	hint := lipgloss.NewStyle().Faint(true)
+	for i, c := range matches {
+		if i == paletteMaxRows {
+			lines = append(lines, hint.Render(fmt.Sprintf("… %d more", len(matches)-paletteMaxRows)))
+			break
+		}
+		row := "  " + c.title
+		if i == m.paletteCursor {
+			row = selected.Render("> " + c.title)
+		}
+		if c.key != "" {
+			row += "  " + hint.Render("["+c.key+"]")
+		}
+		lines = append(lines, row)
+	}
+	lines = append(lines, "", hint.Render("↑/↓ select   enter run   esc close"))
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("212")).
+		Padding(1, 2).
+		Width(52)
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.NewStyle().Bold(true).Render("Command Palette"),
+		strings.Join(lines, "\n"),
+	)
+	return renderScrim(m, box.Render(content))
+}
@@ -0,0 +1,28 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/admin"
+)
+
+// doPauseCmd pauses or resumes fetching from a worker queue.
+func (m model) doPauseCmd(key string, pause bool) tea.Cmd {
+	return func() tea.Msg {
+		op := admin.ResumeQueue
+		if pause {
+			op = admin.PauseQueue
+		}
+		return pauseMsg{key: key, paused: pause, err: op(m.ctx, m.cfg, m.rdb, key)}
+	}
+}
+
+// isWorkerQueue reports whether key is one of the priority queues.
+func (m model) isWorkerQueue(key string) bool {
+	for _, q := range m.cfg.Worker.Queues {
+		if q == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// growthAlertRate is the backlog growth, in jobs per second, that counts
+	// toward a status bar alert.
+	growthAlertRate = 1.0
+	// growthAlertSamples is how many consecutive refreshes must exceed
+	// growthAlertRate before a queue is flagged.
+	growthAlertSamples = 5
+)
+
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// rateSeries lists the charted series in display order. Growth in
+// "completed" is throughput, so it is never flagged.
+var rateSeries = []struct {
+	alias, label string
+	backlog      bool
+}{
+	{"high", "High", true},
+	{"low", "Low", true},
+	{"completed", "Completed", false},
+	{"dead_letter", "Dead Letter", true},
+}
+
+// seriesRates turns queue-length samples taken every interval into rates of
+// change in items per second.
+func seriesRates(samples []float64, interval time.Duration) []float64 {
+	if len(samples) < 2 || interval <= 0 {
+		return nil
+	}
+	secs := interval.Seconds()
+	out := make([]float64, len(samples)-1)
+	for i := 1; i < len(samples); i++ {
+		out[i-1] = (samples[i] - samples[i-1]) / secs
+	}
+	return out
+}
+
+// sparkline renders the last width values, scaled between their min and max.
+func sparkline(vals []float64, width int) string {
+	if width <= 0 || len(vals) == 0 {
+		return ""
+	}
+	if len(vals) > width {
+		vals = vals[len(vals)-width:]
+	}
+	lo, hi := vals[0], vals[0]
+	for _, v := range vals {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	var b strings.Builder
+	for _, v := range vals {
+		i := 0
+		if hi > lo {
+			i = int((v - lo) / (hi - lo) * float64(len(sparkLevels)-1))
+		}
+		b.WriteRune(sparkLevels[i])
+	}
+	return b.String()
+}
+
+// sustainedGrowth reports whether the last n rates all exceed threshold.
+func sustainedGrowth(rates []float64, threshold float64, n int) bool {
+	if n <= 0 || len(rates) < n {
+		return false
+	}
+	for _, r := range rates[len(rates)-n:] {
+		if r <= threshold {
+			return false
+		}
+	}
+	return true
+}
+
+// formatRate renders a rate as a signed "±N/s" annotation.
+func formatRate(r float64) string {
+	if math.Abs(r) < 0.05 {
+		return "±0/s"
+	}
+	return fmt.Sprintf("%+.1f/s", r)
+}
+
+// rateStyle picks a status color for the latest rate. For backlogs growth
+// is bad and draining is good; for completed any movement is good.
+func rateStyle(styles StyleSet, r float64, backlog bool) lipgloss.Style {
+	switch {
+	case math.Abs(r) < 0.05:
+		return styles.StatusMuted
+	case !backlog:
+		return styles.StatusSuccess
+	case r > growthAlertRate:
+		return styles.StatusError
+	case r > 0:
+		return styles.StatusWarning
+	default:
+		return styles.StatusSuccess
+	}
+}
+
+// renderRates draws one sparkline of the rate of change per charted series.
+func renderRates(m model, width int) string {
+	styles := GetStyleSet(m.width, m.height)
+	const labelW, rateW = 12, 9
+	sparkW := width - labelW - rateW - 2
+	if sparkW < 5 {
+		sparkW = 5
+	}
+	lines := []string{"Rate of change"}
+	for _, s := range rateSeries {
+		rates := seriesRates(m.series[s.alias], m.refreshEvery)
+		if len(rates) == 0 {
+			lines = append(lines, fmt.Sprintf("%-*s (collecting)", labelW, s.label))
+			continue
+		}
+		last := rates[len(rates)-1]
+		st := rateStyle(styles, last, s.backlog)
+		lines = append(lines, fmt.Sprintf("%-*s %s %s", labelW, s.label,
+			st.Render(fmt.Sprintf("%-*s", sparkW, sparkline(rates, sparkW))),
+			st.Render(fmt.Sprintf("%*s", rateW, formatRate(last)))))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// growingQueues names the backlog series that have grown faster than
+// growthAlertRate for growthAlertSamples refreshes in a row.
+func growingQueues(m model) []string {
+	var out []string
+	for _, s := range rateSeries {
+		if !s.backlog {
+			continue
+		}
+		if sustainedGrowth(seriesRates(m.series[s.alias], m.refreshEvery), growthAlertRate, growthAlertSamples) {
+			out = append(out, s.alias)
+		}
+	}
+	return out
+}
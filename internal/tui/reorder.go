@@ -0,0 +1,59 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/admin"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+)
+
+// doReorderCmd promotes a job to the front of queueKey, or demotes it to
+// the back.
+func (m model) doReorderCmd(queueKey, jobID string, promote bool) tea.Cmd {
+	return func() tea.Msg {
+		op := admin.Demote
+		if promote {
+			op = admin.Promote
+		}
+		return reorderMsg{key: queueKey, jobID: jobID, promote: promote, err: op(m.ctx, m.cfg, m.rdb, queueKey, jobID)}
+	}
+}
+
+// peekedJobIDs returns the job IDs of the last peek, next to run first.
+func (m model) peekedJobIDs() []string {
+	items := m.lastPeek.Items
+	ids := make([]string, 0, len(items))
+	for i := len(items) - 1; i >= 0; i-- {
+		if job, err := queue.UnmarshalJob(items[i]); err == nil && job.ID != "" {
+			ids = append(ids, job.ID)
+		}
+	}
+	return ids
+}
+
+// reorderCommands offers promote and demote for every job in the last
+// peek, so a job seen in a queue or the dead letter list can be moved.
+func (m model) reorderCommands() []paletteCommand {
+	key := m.lastPeek.Queue
+	if key == "" || key == m.cfg.Worker.CompletedList {
+		return nil
+	}
+	var cmds []paletteCommand
+	for _, id := range m.peekedJobIDs() {
+		cmds = append(cmds,
+			paletteCommand{
+				title:       fmt.Sprintf("Promote job %s to front of %s", id, key),
+				destructive: true,
+				run:         func(m *model) tea.Cmd { return m.doReorderCmd(key, id, true) },
+			},
+			paletteCommand{
+				title:       fmt.Sprintf("Demote job %s to back of %s", id, key),
+				destructive: true,
+				run:         func(m *model) tea.Cmd { return m.doReorderCmd(key, id, false) },
+			},
+		)
+	}
+	return cmds
+}
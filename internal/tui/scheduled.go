@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/admin"
+	"github.com/flyingrobots/go-redis-work-queue/internal/scheduler"
+)
+
+// scheduledLimit bounds how many upcoming jobs the Scheduled tab lists.
+const scheduledLimit = 50
+
+// scheduledWithins are the due-within filters the w key cycles through;
+// zero lists everything.
+var scheduledWithins = []time.Duration{0, time.Minute, 5 * time.Minute, time.Hour, 24 * time.Hour}
+
+// fetchScheduledCmd loads the soonest scheduled and delayed jobs across
+// every worker queue, honoring the current due-within filter.
+func (m model) fetchScheduledCmd() tea.Cmd {
+	opts := admin.ScheduledOptions{DueWithin: scheduledWithins[m.schedWithin]}
+	return func() tea.Msg {
+		jobs, err := admin.ListScheduledWithOptions(m.ctx, m.cfg, m.rdb, "", scheduledLimit, opts)
+		return scheduledMsg{jobs: jobs, err: err}
+	}
+}
+
+// doCancelScheduledCmd removes one job before the scheduler promotes it.
+func (m model) doCancelScheduledCmd(job admin.ScheduledJob) tea.Cmd {
+	return func() tea.Msg {
+		return cancelScheduledMsg{err: admin.CancelScheduled(m.ctx, m.cfg, m.rdb, job.Queue, job.Member)}
+	}
+}
+
+// handleScheduledKey handles the Scheduled tab's own keys: j/k to select,
+// w to cycle the due-within filter and x to cancel the selected job.
+func (m *model) handleScheduledKey(key string) (tea.Cmd, bool) {
+	switch key {
+	case "j", "down":
+		if m.schedCursor < len(m.lastScheduled)-1 {
+			m.schedCursor++
+		}
+	case "k", "up":
+		if m.schedCursor > 0 {
+			m.schedCursor--
+		}
+	case "w":
+		m.schedWithin = (m.schedWithin + 1) % len(scheduledWithins)
+		return m.fetchScheduledCmd(), true
+	case "x":
+		if m.opts.ReadOnly {
+			m.errText = "read-only mode: cancel disabled"
+			return nil, true
+		}
+		if m.schedCursor < 0 || m.schedCursor >= len(m.lastScheduled) {
+			return nil, true
+		}
+		m.cancelJob = m.lastScheduled[m.schedCursor]
+		m.confirmOpen = true
+		m.confirmAction = "cancel-scheduled"
+	default:
+		return nil, false
+	}
+	return nil, true
+}
+
+func renderScheduled(m model, width int) string {
+	now := time.Now()
+	b := &strings.Builder{}
+	filter := "all"
+	if w := scheduledWithins[m.schedWithin]; w > 0 {
+		filter = "due within " + w.String()
+	}
+	fmt.Fprintf(b, "Showing: %s  (w: change, j/k: select, x: cancel)\n\n", filter)
+	if len(m.lastScheduled) == 0 {
+		b.WriteString("(no scheduled jobs)")
+		return b.String()
+	}
+	previewW := width - 46
+	if previewW < 10 {
+		previewW = 10
+	}
+	fmt.Fprintf(b, "  %-14s %-28s %s\n", "Due", "Queue", "Payload")
+	for i, job := range m.lastScheduled {
+		marker := " "
+		if i == m.schedCursor {
+			marker = ">"
+		}
+		kind := ""
+		if job.Set == m.cfg.DerivedKey(scheduler.DelayedKey, job.Queue) {
+			kind = " (delayed)"
+		}
+		preview := job.Preview
+		if r := []rune(preview); len(r) > previewW {
+			preview = string(r[:previewW-1]) + "…"
+		}
+		fmt.Fprintf(b, "%s %-14s %-28.28s %s\n", marker, formatDue(job.DueAt, now), job.Queue+kind, preview)
+	}
+	return b.String()
+}
+
+// formatDue describes a due time relative to now, e.g. "in 4m30s" or
+// "overdue 3s" when the scheduler has not promoted the job yet.
+func formatDue(due, now time.Time) string {
+	d := due.Sub(now).Round(time.Second)
+	if d < 0 {
+		return "overdue " + (-d).String()
+	}
+	return "in " + d.String()
+}
@@ -0,0 +1,82 @@
+package tui
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// uiState is what the TUI remembers between runs in Options.StatePath.
+type uiState struct {
+	Layout string `json:"layout,omitempty"`
+	Theme  string `json:"theme,omitempty"` // dark or light
+	Filter string `json:"filter,omitempty"`
+}
+
+// loadUIState reads path; a missing file is an empty state.
+func loadUIState(path string) (uiState, error) {
+	var st uiState
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return st, nil
+	}
+	if err != nil {
+		return st, err
+	}
+	err = json.Unmarshal(data, &st)
+	return st, err
+}
+
+// saveUIState writes st to path through a temporary file, so a crash
+// cannot leave it half written.
+func saveUIState(path string, st uiState) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// applyUIState restores a saved layout, theme and filter. An explicit
+// dark or light Options.Theme wins over the saved theme.
+func (m *model) applyUIState(st uiState) {
+	if i, ok := layoutIndex(st.Layout); ok {
+		m.layoutIdx = i
+	}
+	theme := st.Theme
+	if m.opts.Theme == "dark" || m.opts.Theme == "light" {
+		theme = m.opts.Theme
+	}
+	if theme == "dark" || theme == "light" {
+		m.darkTheme = theme == "dark"
+		lipgloss.SetHasDarkBackground(m.darkTheme)
+	}
+	m.filter.SetValue(st.Filter)
+}
+
+// saveState records the current layout, theme and filter when
+// Options.StatePath is set. A failure shows in the header and is
+// otherwise ignored.
+func (m *model) saveState() {
+	if m.opts.StatePath == "" {
+		return
+	}
+	theme := "light"
+	if m.darkTheme {
+		theme = "dark"
+	}
+	st := uiState{Layout: m.layout().name, Theme: theme, Filter: trim(m.filter.Value())}
+	if err := saveUIState(m.opts.StatePath, st); err != nil {
+		m.errText = "save TUI state: " + err.Error()
+	}
+}
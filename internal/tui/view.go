@@ -26,7 +26,7 @@ func (m model) View() string {
 		headerText += fmt.Sprintf(" | Namespace: %s", m.opts.Namespace)
 	}
 	header := lipgloss.NewStyle().Bold(true).Render(headerText)
-	sub := fmt.Sprintf("Focus: %s  |  Heartbeats: %d  |  Processing lists: %d", focusName(m.focus), m.lastStats.Heartbeats, len(m.lastStats.ProcessingLists))
+	sub := fmt.Sprintf("Focus: %s  |  Layout: %s  |  Heartbeats: %d  |  Processing lists: %d", focusName(m.focus), m.layout().name, m.lastStats.Heartbeats, len(m.lastStats.ProcessingLists))
 	if m.opts.ReadOnly {
 		sub += "  |  Mode: READ-ONLY"
 	}
@@ -44,6 +44,8 @@ func (m model) View() string {
 		panelColor = "#9ece6a"
 	case tabDLQ:
 		panelColor = "#f7768e"
+	case tabScheduled:
+		panelColor = "#7dcfff"
 	case tabEventHooks:
 		panelColor = "#e0af68"
 	case tabSettings:
@@ -55,140 +57,53 @@ func (m model) View() string {
 	switch m.activeTab {
 	case tabJobs:
 		// Flex layout with borders at the cell level to avoid double borders/overflow.
-		bodyW, bodyH := m.bodyDims()
-		fbBox := flexbox.New(bodyW, bodyH)
-		stack := bodyW < 120 // stack panels vertically on narrow terminals
-		gutter := flexbox.NewCell(0, 2).SetMinWidth(2).SetContent("")
-		// Animated ratios for wide mode: 0.0 => 1:1, 1.0 => 1:2 (Charts wider)
-		base := 100
-		lrx := base
-		rrx := base + int(float64(base)*m.expPos)
-		if rrx < 1 {
-			rrx = 1
-		}
-		if lrx < 1 {
-			lrx = 1
-		}
-
-		var rowTop, rowMid, rowBottom *flexbox.Row
-		if !stack {
-			// Wide: Queues | gutter | Charts on top, Info on bottom
-			cLeft := flexbox.NewCell(lrx, 2).SetStyle(panel)
-			cRight := flexbox.NewCell(rrx, 2).SetStyle(panel)
-			cBottom := flexbox.NewCell(1, 1).SetStyle(panel)
-			rowTop = fbBox.NewRow().AddCells(cLeft, gutter, cRight)
-			rowBottom = fbBox.NewRow().AddCells(cBottom)
-			fbBox.SetRows([]*flexbox.Row{rowTop, rowBottom})
-		} else {
-			// Narrow: stack Queues, Charts, then Info (2,2,1 ratios)
-			cQueues := flexbox.NewCell(1, 2).SetStyle(panel)
-			cCharts := flexbox.NewCell(1, 2).SetStyle(panel)
-			cInfo := flexbox.NewCell(1, 1).SetStyle(panel)
-			rowTop = fbBox.NewRow().AddCells(cQueues)
-			rowMid = fbBox.NewRow().AddCells(cCharts)
-			rowBottom = fbBox.NewRow().AddCells(cInfo)
-			fbBox.SetRows([]*flexbox.Row{rowTop, rowMid, rowBottom})
-		}
-
-		// Size pass: compute inner dimensions for contents
-		fbBox.ForceRecalculate()
-		var lc, rc, bc *flexbox.Cell
-		if !stack {
-			lc = fbBox.GetRowCellCopy(0, 0)
-			rc = fbBox.GetRowCellCopy(0, 2)
-			bc = fbBox.GetRowCellCopy(1, 0)
-		} else {
-			lc = fbBox.GetRowCellCopy(0, 0)
-			rc = fbBox.GetRowCellCopy(1, 0)
-			bc = fbBox.GetRowCellCopy(2, 0)
-		}
-		// Panel overhead: 2 border + 2 padding (h), 2 border (v)
-		innerLeftW, innerLeftH := lc.GetWidth()-4, lc.GetHeight()-2
-		innerRightW, innerRightH := rc.GetWidth()-4, rc.GetHeight()-2
-		innerBottomW, innerBottomH := bc.GetWidth()-4, bc.GetHeight()-2
-		if innerLeftW < 1 {
-			innerLeftW = 1
-		}
-		if innerRightW < 1 {
-			innerRightW = 1
-		}
-		if innerBottomW < 1 {
-			innerBottomW = 1
-		}
-		if innerLeftH < 1 {
-			innerLeftH = 1
-		}
-		if innerRightH < 1 {
-			innerRightH = 1
-		}
-		if innerBottomH < 1 {
-			innerBottomH = 1
-		}
-
-		// Left (Queues)
-		m.tbl.SetWidth(innerLeftW)
-		// Height = inner cell height minus title and optional filter line
-		filterLines := 0
-		if strings.TrimSpace(m.filter.Value()) != "" || m.filterActive {
-			filterLines = 1
-		}
-		tblH := innerLeftH - 1 - filterLines
-		if tblH < 3 {
-			tblH = 3
-		}
-		m.tbl.SetHeight(tblH)
-		leftBody := m.tbl.View()
-		if fb := renderFilterBar(m); fb != "" {
-			leftBody = fb + "\n" + leftBody
+		fbBox, boxes := m.arrangeJobs(panel)
+		m.resizePanels()
+		setContent := func(p panelID, content string) {
+			if b, ok := boxes[p]; ok {
+				fbBox.GetRow(b.row).GetCell(b.col).SetContent(content)
+			}
 		}
-		leftContent := m.boxTitle.Render("Queues") + "\n" + leftBody
-
-		// Right (Charts): render with cell-based width
-		chartsStr := renderChartsWidth(m, innerRightW)
-		rightContent := m.boxTitle.Render("Charts") + "\n" + chartsStr
 
-		// Bottom (Info): viewport sized to inner dimensions
-		m.vpInfo.Width = innerBottomW
-		m.vpInfo.Height = innerBottomH - 1 // minus title line
-		info := summarizeKeys(m.lastKeys)
-		if len(m.lastPeek.Items) > 0 {
-			info += "\n\n" + renderPeek(m.lastPeek)
-		}
-		if m.benchCount.Focused() || m.benchRate.Focused() || m.benchPriority.Focused() || m.benchTimeout.Focused() || m.lastBench.Count > 0 {
-			info += "\n\n" + renderBenchForm(m)
-			if m.lastBench.Count > 0 {
-				info += "\n" + renderBenchResult(m.lastBench)
+		if _, ok := boxes[panelQueues]; ok {
+			leftBody := m.tbl.View()
+			if fb := renderFilterBar(m); fb != "" {
+				leftBody = fb + "\n" + leftBody
 			}
+			setContent(panelQueues, m.boxTitle.Render("Queues")+"\n"+leftBody)
 		}
-		if m.pbActive && m.pbTotal > 0 {
-			info += "\n\nBench Progress:\n" + m.pb.View()
+		if b, ok := boxes[panelCharts]; ok {
+			// render with cell-based width
+			setContent(panelCharts, m.boxTitle.Render("Charts")+"\n"+renderChartsWidth(m, b.w))
 		}
-		m.vpInfo.SetContent(info)
-		bottomContent := m.boxTitle.Render("Info") + "\n" + m.vpInfo.View()
-
-		// Set contents after sizing so borders line up with cell widths
-		rowTop.GetCell(0).SetContent(leftContent)
-		if !stack {
-			rowTop.GetCell(2).SetContent(rightContent)
-		} else {
-			rowMid.GetCell(0).SetContent(rightContent)
+		if _, ok := boxes[panelInfo]; ok {
+			info := summarizeKeys(m.lastKeys)
+			if m.lastDLQ != nil && m.lastDLQ.Sampled > 0 {
+				info += "\n\n" + renderDLQReport(m.lastDLQ)
+			}
+			if len(m.lastPeek.Items) > 0 {
+				info += "\n\n" + renderPeek(m.lastPeek)
+			}
+			if m.benchCount.Focused() || m.benchRate.Focused() || m.benchPriority.Focused() || m.benchTimeout.Focused() || m.lastBench.Count > 0 {
+				info += "\n\n" + renderBenchForm(m)
+				if m.lastBench.Count > 0 {
+					info += "\n" + renderBenchResult(m.lastBench)
+				}
+			}
+			if m.pbActive && m.pbTotal > 0 {
+				info += "\n\nBench Progress:\n" + m.pb.View()
+			}
+			m.vpInfo.SetContent(info)
+			setContent(panelInfo, m.boxTitle.Render("Info")+"\n"+m.vpInfo.View())
 		}
-		rowBottom.GetCell(0).SetContent(bottomContent)
 
 		body = fbBox.Render()
 
 	case tabWorkers:
-		// Simple summary placeholder
-		workersInfo := []string{
-			fmt.Sprintf("Workers: heartbeats=%d", m.lastStats.Heartbeats),
-			fmt.Sprintf("Processing lists: %d", len(m.lastStats.ProcessingLists)),
-			"(Placeholder) Future: live workers view with heartbeats and active jobs",
-		}
-		content := strings.Join(workersInfo, "\n")
 		bodyW, bodyH := m.bodyDims()
 		fbBox := flexbox.New(bodyW, bodyH)
 		single := fbBox.NewRow().AddCells(
-			flexbox.NewCell(1, 1).SetStyle(panel).SetContent(m.boxTitle.Render("Workers") + "\n" + content),
+			flexbox.NewCell(1, 1).SetStyle(panel).SetContent(m.boxTitle.Render("Workers") + "\n" + renderWorkers(m, bodyW-4)),
 		)
 		fbBox.SetRows([]*flexbox.Row{single})
 		body = fbBox.Render()
@@ -210,6 +125,15 @@ func (m model) View() string {
 		fbBox.SetRows([]*flexbox.Row{single})
 		body = fbBox.Render()
 
+	case tabScheduled:
+		bodyW, bodyH := m.bodyDims()
+		fbBox := flexbox.New(bodyW, bodyH)
+		single := fbBox.NewRow().AddCells(
+			flexbox.NewCell(1, 1).SetStyle(panel).SetContent(m.boxTitle.Render("Scheduled Jobs") + "\n" + renderScheduled(m, bodyW-4)),
+		)
+		fbBox.SetRows([]*flexbox.Row{single})
+		body = fbBox.Render()
+
 	case tabEventHooks:
 		// Event Hooks management view
 		lines := []string{
@@ -258,8 +182,21 @@ func (m model) View() string {
 		// Use a full-screen scrim overlay that centers the modal and preserves header/body
 		return renderOverlayScreen(m)
 	}
+	if m.editOpen {
+		return renderEditorOverlay(m)
+	}
+	if m.paletteOpen {
+		return renderPaletteOverlay(m)
+	}
 	now := time.Now().Format("15:04:05")
-	m.sb.SetContent("Redis "+m.cfg.Redis.Addr, "focus:"+focusName(m.focus), m.spinner.View(), now)
+	status := "focus:" + focusName(m.focus)
+	if drain := drainStatus(m); drain != "" {
+		status += "  " + drain
+	}
+	if growing := growingQueues(m); len(growing) > 0 {
+		status += "  ▲ growing: " + strings.Join(growing, ", ")
+	}
+	m.sb.SetContent("Redis "+m.cfg.Redis.Addr, status, m.spinner.View(), now)
 	out := base + "\n" + m.sb.View()
 	if m.help2.Active {
 		// Dim with scrim and center the help content
@@ -298,6 +235,29 @@ func renderKeys(k admin.KeysStats) string {
 	return b.String()
 }
 
+// dlqTopRows caps each breakdown in the Info panel's DLQ table.
+const dlqTopRows = 3
+
+func renderDLQReport(r *admin.DLQReport) string {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "DLQ: %d items, %d sampled by %s\n", r.Total, r.Sampled, r.ReasonField)
+	fmt.Fprintf(b, "  %-32s %6s %6s  %s\n", "Reason", "Count", "%", "Top queue")
+	for i, rb := range r.Reasons {
+		if i == dlqTopRows {
+			break
+		}
+		fmt.Fprintf(b, "  %-32.32s %6d %5.0f%%  %s\n", rb.Name, rb.Count, rb.Percent, rb.TopQueue)
+	}
+	ages := make([]string, 0, len(r.Ages))
+	for _, ab := range r.Ages {
+		if ab.Count > 0 {
+			ages = append(ages, fmt.Sprintf("%s=%d", ab.Name, ab.Count))
+		}
+	}
+	fmt.Fprintf(b, "  Ages: %s", strings.Join(ages, "  "))
+	return b.String()
+}
+
 func renderPeek(p admin.PeekResult) string {
 	b := &strings.Builder{}
 	fmt.Fprintf(b, "Peek: %s\n", p.Queue)
@@ -336,7 +296,7 @@ func renderBenchResult(b admin.BenchResult) string {
 }
 
 func helpBar() string {
-	return strings.Join([]string{"q:quit", "tab/shift+tab:focus panel", "r:refresh", "j/k:down/up", "wheel/mouse: scroll/select", "enter/p:peek", "b:bench form", "f:filter (queues)", "D:purge DLQ (y/n)", "A:purge ALL (y/n)"}, "  ")
+	return strings.Join([]string{"q:quit", "tab/shift+tab:focus panel", "r:refresh", "j/k:down/up", "wheel/mouse: scroll/select", "enter/p:peek", "b:bench form", "f:filter (queues)", "m:move jobs (y/n)", "P:pause/resume queue", "D:purge DLQ (y/n)", "A:purge ALL (y/n)", "5:scheduled jobs (w:due within, x:cancel)"}, "  ")
 }
 
 func focusName(f focusArea) string {
@@ -363,7 +323,7 @@ func renderChartsWidth(m model, plotW int) string {
 		g := asciigraph.Plot(data, asciigraph.Height(h), asciigraph.Width(plotW), asciigraph.Caption(title))
 		return g
 	}
-	parts := []string{}
+	parts := []string{renderRates(m, plotW)}
 	parts = append(parts, makePlot("High Priority", m.series["high"]))
 	parts = append(parts, makePlot("Low Priority", m.series["low"]))
 	parts = append(parts, makePlot("Completed", m.series["completed"]))
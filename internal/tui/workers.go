@@ -0,0 +1,52 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/admin"
+)
+
+// fetchWorkersCmd loads per-worker heartbeat and processing list detail.
+func (m model) fetchWorkersCmd() tea.Cmd {
+	return func() tea.Msg {
+		workers, err := admin.Workers(m.ctx, m.cfg, m.rdb)
+		return workersMsg{workers: workers, err: err}
+	}
+}
+
+func renderWorkers(m model, width int) string {
+	now := time.Now()
+	b := &strings.Builder{}
+	b.WriteString(admin.WorkerSummary(m.lastWorkers) + "\n\n")
+	if len(m.lastWorkers) == 0 {
+		b.WriteString("(no busy workers; idle workers hold no heartbeat)")
+		return b.String()
+	}
+	idW := width - 62
+	if idW < 12 {
+		idW = 12
+	}
+	fmt.Fprintf(b, "%-*s %-6s %-10s %-20s %-10s %s\n", idW, "Worker", "State", "Last seen", "Job", "Processing", "Queue")
+	for _, wi := range m.lastWorkers {
+		state, seen := "stale", "-"
+		switch {
+		case wi.Alive:
+			state = "alive"
+		case wi.ReapCandidate:
+			state = "REAP"
+		}
+		if !wi.LastHeartbeat.IsZero() {
+			seen = now.Sub(wi.LastHeartbeat).Round(time.Second).String() + " ago"
+		}
+		id := wi.ID
+		if r := []rune(id); len(r) > idW {
+			id = string(r[:idW-1]) + "…"
+		}
+		fmt.Fprintf(b, "%-*s %-6s %-10s %-20.20s %-10d %s\n", idW, id, state, seen, wi.JobID, wi.Processing, wi.Queue)
+	}
+	return b.String()
+}
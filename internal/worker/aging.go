@@ -0,0 +1,34 @@
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/obs"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+)
+
+// agingQueues returns the queue keys of the priorities under
+// producer.priority_aging.
+func agingQueues(cfg *config.Config) map[string]bool {
+	out := make(map[string]bool, len(cfg.Producer.PriorityAging))
+	for p := range cfg.Producer.PriorityAging {
+		if key := cfg.Worker.Queues[p]; key != "" {
+			out[key] = true
+		}
+	}
+	return out
+}
+
+// untrackAging drops payload, just dequeued from srcQueue, from the queue's
+// aging set, so the scheduler never goes looking for a job that has left
+// the queue. A failure only leaves the entry for the scheduler to drop.
+func (w *Worker) untrackAging(ctx context.Context, srcQueue, payload string) {
+	if !w.aging[srcQueue] {
+		return
+	}
+	if err := w.client(srcQueue).ZRem(ctx, w.cfg.DerivedKey(queue.AgingKey, srcQueue), payload).Err(); err != nil {
+		w.log.Warn("dropping aging entry failed", obs.String("queue", srcQueue), obs.Err(err))
+	}
+}
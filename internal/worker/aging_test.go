@@ -0,0 +1,47 @@
+//go:build worker_tests
+// +build worker_tests
+
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestWorkerDropsAgingEntriesOnDequeue(t *testing.T) {
+	w, cfg, rdb, cleanup := setupWorkerTest(t)
+	defer cleanup()
+	cfg.Worker.Count = 1
+	cfg.Worker.BRPopLPushTimeout = 10 * time.Millisecond
+	cfg.Producer.PriorityAging = map[string]config.PriorityAging{"low": {After: time.Minute, CheckInterval: time.Second}}
+	w.aging = agingQueues(cfg)
+	ctx := context.Background()
+
+	low := cfg.Worker.Queues["low"]
+	payload, _ := queue.NewJob("aging", "/tmp/ok.txt", 1, "low", "", "").Marshal()
+	rdb.LPush(ctx, low, payload)
+	rdb.ZAdd(ctx, queue.AgingKey(low), redis.Z{Score: float64(time.Now().UnixMilli()), Member: payload})
+
+	runCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- w.Run(runCtx) }()
+	for runCtx.Err() == nil && rdb.LLen(ctx, cfg.Worker.CompletedList).Val() == 0 {
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if n := rdb.LLen(ctx, cfg.Worker.CompletedList).Val(); n != 1 {
+		t.Fatalf("expected the job completed, got %d", n)
+	}
+	if n := rdb.ZCard(ctx, queue.AgingKey(low)).Val(); n != 0 {
+		t.Fatalf("expected the aging entry dropped on dequeue, %d left", n)
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/obs"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+)
+
+// latencyWeight is the weight of the newest sample in the job latency EWMA.
+const latencyWeight = 0.2
+
+// scaleController decides the worker pool size from the observed backlog
+// and job latency. It holds only the state needed between samples, so it
+// can be driven directly in tests.
+type scaleController struct {
+	min, max         int
+	backlogPerWorker int64
+	scaleDownDelay   time.Duration
+
+	lastLatency time.Duration
+	idleSince   time.Time
+}
+
+func newScaleController(as config.WorkerAutoscale) *scaleController {
+	return &scaleController{
+		min:              as.MinConcurrency,
+		max:              as.MaxConcurrency,
+		backlogPerWorker: as.BacklogPerWorker,
+		scaleDownDelay:   as.ScaleDownDelay,
+	}
+}
+
+// clamp bounds n to the configured concurrency range.
+func (c *scaleController) clamp(n int) int {
+	return max(c.min, min(c.max, n))
+}
+
+// Target returns the pool size for the next interval given the current size,
+// the number of queued jobs and the average job latency. The pool grows,
+// at most doubling per step, while the backlog is deeper than current
+// goroutines can absorb and latency is not falling; when latency falls the
+// current pool is catching up and is left alone. The pool halves once the
+// backlog has been empty for scaleDownDelay, and again after each further
+// delay.
+func (c *scaleController) Target(now time.Time, current int, backlog int64, latency time.Duration) int {
+	rising := latency >= c.lastLatency
+	c.lastLatency = latency
+
+	if backlog > 0 {
+		c.idleSince = time.Time{}
+	}
+	switch {
+	case backlog > int64(current)*c.backlogPerWorker && rising:
+		need := int((backlog + c.backlogPerWorker - 1) / c.backlogPerWorker)
+		return c.clamp(min(need, 2*max(current, 1)))
+	case backlog == 0:
+		if c.idleSince.IsZero() {
+			c.idleSince = now
+		}
+		if now.Sub(c.idleSince) >= c.scaleDownDelay {
+			c.idleSince = now
+			return c.clamp(current / 2)
+		}
+	}
+	return c.clamp(current)
+}
+
+// workerSlot is one goroutine of the pool. stop asks it to exit after its
+// current job; the slot's ID stays taken until it has.
+type workerSlot struct {
+	stop     chan struct{}
+	retiring bool
+}
+
+// workerPool runs a resizable set of worker goroutines. Slot i always uses
+// worker ID baseID-i, so processing lists and heartbeat keys are reused
+// rather than accumulating as the pool grows and shrinks.
+type workerPool struct {
+	ctx  context.Context
+	wg   *sync.WaitGroup
+	run  func(ctx context.Context, workerID string, stop <-chan struct{})
+	base string
+
+	mu    sync.Mutex
+	slots map[int]*workerSlot
+	size  int
+}
+
+// resize starts or retires goroutines until n are running. Retired
+// goroutines finish the job in hand before exiting.
+func (p *workerPool) resize(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.ctx.Err() != nil {
+		return
+	}
+	for i := 0; p.size < n; i++ {
+		if p.slots[i] != nil {
+			continue
+		}
+		s := &workerSlot{stop: make(chan struct{})}
+		p.slots[i] = s
+		p.size++
+		p.wg.Add(1)
+		go func(i int) {
+			defer p.wg.Done()
+			obs.WorkerActive.Inc()
+			defer obs.WorkerActive.Dec()
+			p.run(p.ctx, fmt.Sprintf("%s-%d", p.base, i), s.stop)
+			p.mu.Lock()
+			delete(p.slots, i)
+			p.mu.Unlock()
+		}(i)
+	}
+	top := -1
+	for i := range p.slots {
+		top = max(top, i)
+	}
+	for i := top; p.size > n && i >= 0; i-- {
+		if s := p.slots[i]; s != nil && !s.retiring {
+			s.retiring = true
+			close(s.stop)
+			p.size--
+		}
+	}
+}
+
+func (p *workerPool) current() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.size
+}
+
+// stopped reports whether a pool slot has been asked to exit.
+func stopped(stop <-chan struct{}) bool {
+	select {
+	case <-stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// observeLatency folds one job's processing time into the latency average.
+func (w *Worker) observeLatency(d time.Duration) {
+	w.latencyMu.Lock()
+	defer w.latencyMu.Unlock()
+	if w.latency == 0 {
+		w.latency = d
+		return
+	}
+	w.latency = time.Duration(latencyWeight*float64(d) + (1-latencyWeight)*float64(w.latency))
+}
+
+func (w *Worker) avgLatency() time.Duration {
+	w.latencyMu.Lock()
+	defer w.latencyMu.Unlock()
+	return w.latency
+}
+
+// backlog counts jobs waiting in queues the worker would poll right now;
+// paused queues and queues behind an open breaker are left out, since more
+// goroutines would not drain them. In stream mode it is the consumer group's
+// lag, i.e. entries not yet delivered to any consumer.
+func (w *Worker) backlog(ctx context.Context) (int64, error) {
+	var keys []string
+	for _, p := range w.cfg.Worker.Priorities {
+		key := w.cfg.Worker.Queues[p]
+		if key == "" {
+			continue
+		}
+		if qb := w.breakers[key]; qb != nil && !qb.cb.Ready() {
+			continue
+		}
+		if w.queuePaused(ctx, key) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+
+	var total int64
+	if w.cfg.Worker.Mode == config.ModeStream {
+		for _, key := range keys {
+			groups, err := w.rdb.XInfoGroups(ctx, queue.StreamKey(key)).Result()
+			if err != nil {
+				return 0, err
+			}
+			for _, g := range groups {
+				if g.Name == w.cfg.Worker.Stream.Group && g.Lag > 0 {
+					total += g.Lag
+				}
+			}
+		}
+		return total, nil
+	}
+
+	lengths, err := w.queueLengths(ctx, keys)
+	if err != nil {
+		return 0, err
+	}
+	for _, n := range lengths {
+		total += n
+	}
+	return total, nil
+}
+
+// autoscale resizes pool every interval until ctx is done.
+func (w *Worker) autoscale(ctx context.Context, pool *workerPool) {
+	as := w.cfg.Worker.Autoscale
+	ctrl := newScaleController(as)
+	ticker := time.NewTicker(as.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		backlog, err := w.backlog(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				w.log.Warn("autoscale backlog check failed", obs.Err(err))
+			}
+			continue
+		}
+		current := pool.current()
+		target := ctrl.Target(time.Now(), current, backlog, w.avgLatency())
+		if target == current {
+			continue
+		}
+		pool.resize(target)
+		w.concurrency.Store(int64(target))
+		obs.WorkerConcurrency.Set(float64(target))
+		w.log.Info("worker concurrency changed",
+			obs.Int("from", current), obs.Int("to", target),
+			obs.Int("backlog", int(backlog)), obs.String("latency", w.avgLatency().String()))
+	}
+}
@@ -0,0 +1,189 @@
+//go:build worker_tests
+// +build worker_tests
+
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"go.uber.org/zap"
+)
+
+func testScaleController() *scaleController {
+	return newScaleController(config.WorkerAutoscale{
+		MinConcurrency:   2,
+		MaxConcurrency:   16,
+		BacklogPerWorker: 10,
+		ScaleDownDelay:   30 * time.Second,
+	})
+}
+
+func TestScaleControllerGrowsWithDeepBacklog(t *testing.T) {
+	c := testScaleController()
+	now := time.Now()
+
+	// 200 queued jobs want 20 goroutines; growth is capped at doubling.
+	if got := c.Target(now, 4, 200, 100*time.Millisecond); got != 8 {
+		t.Fatalf("first step: want 8, got %d", got)
+	}
+	if got := c.Target(now, 8, 200, 150*time.Millisecond); got != 16 {
+		t.Fatalf("second step: want 16, got %d", got)
+	}
+	// capped at max_concurrency
+	if got := c.Target(now, 16, 500, 200*time.Millisecond); got != 16 {
+		t.Fatalf("at max: want 16, got %d", got)
+	}
+}
+
+func TestScaleControllerHoldsWhileLatencyFalls(t *testing.T) {
+	c := testScaleController()
+	now := time.Now()
+	c.Target(now, 4, 200, 200*time.Millisecond)
+
+	if got := c.Target(now, 4, 200, 120*time.Millisecond); got != 4 {
+		t.Fatalf("latency falling: want 4, got %d", got)
+	}
+}
+
+func TestScaleControllerHoldsWhenBacklogFits(t *testing.T) {
+	c := testScaleController()
+	if got := c.Target(time.Now(), 4, 40, time.Second); got != 4 {
+		t.Fatalf("backlog within capacity: want 4, got %d", got)
+	}
+}
+
+func TestScaleControllerShrinksWhenIdle(t *testing.T) {
+	c := testScaleController()
+	now := time.Now()
+
+	if got := c.Target(now, 16, 0, 0); got != 16 {
+		t.Fatalf("idle before delay: want 16, got %d", got)
+	}
+	now = now.Add(30 * time.Second)
+	if got := c.Target(now, 16, 0, 0); got != 8 {
+		t.Fatalf("idle after delay: want 8, got %d", got)
+	}
+	// each further step waits a full delay again
+	if got := c.Target(now.Add(time.Second), 8, 0, 0); got != 8 {
+		t.Fatalf("idle right after a step: want 8, got %d", got)
+	}
+	now = now.Add(30 * time.Second)
+	if got := c.Target(now, 8, 0, 0); got != 4 {
+		t.Fatalf("second idle step: want 4, got %d", got)
+	}
+	now = now.Add(time.Minute)
+	if got := c.Target(now, 3, 0, 0); got != 2 {
+		t.Fatalf("at min: want 2, got %d", got)
+	}
+}
+
+func TestScaleControllerWorkResetsIdleTimer(t *testing.T) {
+	c := testScaleController()
+	now := time.Now()
+	c.Target(now, 8, 0, 0)
+	c.Target(now.Add(20*time.Second), 8, 5, 0)
+	if got := c.Target(now.Add(40*time.Second), 8, 0, 0); got != 8 {
+		t.Fatalf("idle timer should restart after work: want 8, got %d", got)
+	}
+}
+
+func TestWorkerPoolResize(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	running := map[string]bool{}
+	var wg sync.WaitGroup
+	pool := &workerPool{ctx: ctx, wg: &wg, base: "w", slots: map[int]*workerSlot{}}
+	pool.run = func(ctx context.Context, id string, stop <-chan struct{}) {
+		mu.Lock()
+		running[id] = true
+		mu.Unlock()
+		select {
+		case <-ctx.Done():
+		case <-stop:
+		}
+		mu.Lock()
+		delete(running, id)
+		mu.Unlock()
+	}
+	waitRunning := func(want ...string) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			mu.Lock()
+			ok := len(running) == len(want)
+			for _, id := range want {
+				ok = ok && running[id]
+			}
+			mu.Unlock()
+			if ok {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("running slots never became %v", want)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	pool.resize(3)
+	waitRunning("w-0", "w-1", "w-2")
+	pool.resize(1)
+	if got := pool.current(); got != 1 {
+		t.Fatalf("current after shrink: want 1, got %d", got)
+	}
+	waitRunning("w-0")
+	// freed IDs are reused
+	pool.resize(2)
+	waitRunning("w-0", "w-1")
+
+	cancel()
+	wg.Wait()
+	pool.resize(4)
+	if len(pool.slots) != 0 {
+		t.Fatalf("pool started %d goroutines after cancel", len(pool.slots))
+	}
+}
+
+func TestRunAutoscalesWithBacklog(t *testing.T) {
+	cfg, rdb := newBreakerFixture(t)
+	cfg.Worker.Autoscale = config.WorkerAutoscale{
+		Enabled:          true,
+		MinConcurrency:   1,
+		MaxConcurrency:   4,
+		Interval:         10 * time.Millisecond,
+		BacklogPerWorker: 1,
+		ScaleDownDelay:   time.Hour,
+	}
+	pushJobs(t, rdb, cfg.Worker.Queues["low"], "/tmp/ok.txt", 50)
+
+	w := New(cfg, rdb, zap.NewNop())
+	block := make(chan struct{})
+	w.handler = func(ctx context.Context, _ queue.Job) error {
+		select {
+		case <-block:
+		case <-ctx.Done():
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() { defer close(done); _ = w.Run(ctx) }()
+	defer func() { close(block); cancel(); <-done }()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for w.Stats().Concurrency != 4 {
+		if time.Now().After(deadline) {
+			t.Fatalf("concurrency never reached max, got %d", w.Stats().Concurrency)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
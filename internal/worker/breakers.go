@@ -0,0 +1,149 @@
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/breaker"
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/obs"
+)
+
+// BreakerStats is a point-in-time view of one queue's circuit breaker.
+type BreakerStats struct {
+	State    string `json:"state"`
+	Trips    int64  `json:"trips"`
+	Requeued int64  `json:"requeued"`
+}
+
+// Stats is a snapshot of the worker's runtime state.
+type Stats struct {
+	// Breakers is keyed by queue key.
+	Breakers map[string]BreakerStats `json:"breakers"`
+	// Concurrency is the current size of the worker goroutine pool.
+	Concurrency int `json:"concurrency"`
+}
+
+// queueBreaker guards a single source queue, so a failing downstream only
+// pauses the queue that feeds it.
+type queueBreaker struct {
+	cb       *breaker.CircuitBreaker
+	trips    atomic.Int64
+	requeued atomic.Int64
+}
+
+// breakerSettings resolves worker.circuit_breaker, filling unset fields from
+// the top-level circuit_breaker section.
+func breakerSettings(cfg *config.Config) config.WorkerBreaker {
+	s := cfg.Worker.CircuitBreaker
+	if s.FailureThreshold == 0 {
+		s.FailureThreshold = cfg.CircuitBreaker.FailureThreshold
+	}
+	if s.MinRequests == 0 {
+		s.MinRequests = cfg.CircuitBreaker.MinSamples
+	}
+	if s.Window == 0 {
+		s.Window = cfg.CircuitBreaker.Window
+	}
+	if s.OpenTimeout == 0 {
+		s.OpenTimeout = cfg.CircuitBreaker.CooldownPeriod
+	}
+	return s
+}
+
+func newQueueBreakers(cfg *config.Config) map[string]*queueBreaker {
+	s := breakerSettings(cfg)
+	out := make(map[string]*queueBreaker, len(cfg.Worker.Queues))
+	for _, key := range cfg.Worker.Queues {
+		if key == "" {
+			continue
+		}
+		out[key] = &queueBreaker{cb: breaker.New(s.Window, s.OpenTimeout, s.FailureThreshold, s.MinRequests)}
+	}
+	return out
+}
+
+// Stats returns the current state of every per-queue circuit breaker and
+// the size of the worker pool.
+func (w *Worker) Stats() Stats {
+	st := Stats{
+		Breakers:    make(map[string]BreakerStats, len(w.breakers)),
+		Concurrency: int(w.concurrency.Load()),
+	}
+	for key, qb := range w.breakers {
+		st.Breakers[key] = BreakerStats{
+			State:    qb.cb.State().String(),
+			Trips:    qb.trips.Load(),
+			Requeued: qb.requeued.Load(),
+		}
+	}
+	return st
+}
+
+// record feeds a job outcome into the queue's breaker and counts trips.
+func (w *Worker) record(qb *queueBreaker, queueKey string, ok bool) {
+	prev := qb.cb.State()
+	qb.cb.Record(ok)
+	curr := qb.cb.State()
+	if prev != curr {
+		obs.QueueCircuitBreakerState.WithLabelValues(queueKey).Set(breakerStateValue(curr))
+		if curr == breaker.Open {
+			qb.trips.Add(1)
+			obs.CircuitBreakerTrips.Inc()
+			w.log.Warn("circuit breaker opened", obs.String("queue", queueKey))
+		}
+	}
+}
+
+// requeueRejected holds a job rejected by an open breaker for the configured
+// delay, then puts it back at the consuming end of its queue so it keeps its
+// place in line. It is not counted as a failure and never reaches the DLQ.
+func (w *Worker) requeueRejected(ctx context.Context, qb *queueBreaker, srcQueue, procList, hbKey, payload string) {
+	if d := breakerSettings(w.cfg).RequeueDelay; d > 0 {
+		select {
+		case <-ctx.Done():
+		case <-time.After(d):
+		}
+	}
+	rc := w.client(srcQueue)
+	if err := rc.RPush(ctx, srcQueue, payload).Err(); err != nil {
+		// Leave it in the processing list for the reaper.
+		w.log.Error("RPUSH breaker requeue failed", obs.Err(err))
+		return
+	}
+	if err := rc.LRem(ctx, procList, 1, payload).Err(); err != nil {
+		w.log.Error("LREM processing failed", obs.Err(err))
+	}
+	if err := rc.Del(ctx, hbKey).Err(); err != nil {
+		w.log.Error("DEL heartbeat failed", obs.Err(err))
+	}
+	qb.requeued.Add(1)
+	obs.JobsBreakerRequeued.WithLabelValues(srcQueue).Inc()
+}
+
+// publishBreakerStates refreshes the breaker gauges. The unlabeled gauge
+// reports the most severe state across queues.
+func (w *Worker) publishBreakerStates() {
+	worst := 0.0
+	for key, qb := range w.breakers {
+		v := breakerStateValue(qb.cb.State())
+		obs.QueueCircuitBreakerState.WithLabelValues(key).Set(v)
+		if v > worst {
+			worst = v
+		}
+	}
+	obs.CircuitBreakerState.Set(worst)
+}
+
+func breakerStateValue(s breaker.State) float64 {
+	switch s {
+	case breaker.HalfOpen:
+		return 1
+	case breaker.Open:
+		return 2
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,116 @@
+//go:build worker_tests
+// +build worker_tests
+
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+func newBreakerFixture(t *testing.T) (*config.Config, *redis.Client) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	cfg, _ := config.Load("nonexistent.yaml")
+	cfg.Redis.Addr = mr.Addr()
+	cfg.Worker.Count = 1
+	cfg.Worker.MaxRetries = 100
+	cfg.Worker.Backoff.Base = time.Millisecond
+	cfg.Worker.Backoff.Max = 2 * time.Millisecond
+	cfg.Worker.BRPopLPushTimeout = 5 * time.Millisecond
+	cfg.Worker.BreakerPause = 5 * time.Millisecond
+	cfg.Worker.CircuitBreaker = config.WorkerBreaker{
+		FailureThreshold: 0.5,
+		MinRequests:      2,
+		Window:           time.Second,
+		OpenTimeout:      time.Minute,
+		RequeueDelay:     time.Millisecond,
+	}
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	return cfg, rdb
+}
+
+func pushJobs(t *testing.T, rdb *redis.Client, key, path string, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		payload, _ := queue.NewJob("id", path, 1, "", "", "").Marshal()
+		if err := rdb.LPush(context.Background(), key, payload).Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// A failing queue opens only its own breaker; the other queue keeps draining
+// and the failing jobs stay queued instead of being dead-lettered.
+func TestBreakerIsPerQueue(t *testing.T) {
+	cfg, rdb := newBreakerFixture(t)
+	ctx := context.Background()
+	high, low := cfg.Worker.Queues["high"], cfg.Worker.Queues["low"]
+	pushJobs(t, rdb, high, "/tmp/fail.txt", 5)
+	pushJobs(t, rdb, low, "/tmp/ok.txt", 10)
+
+	w := New(cfg, rdb, zap.NewNop())
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() { defer close(done); _ = w.Run(runCtx) }()
+	defer func() { cancel(); <-done }()
+
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		n, _ := rdb.LLen(ctx, cfg.Worker.CompletedList).Result()
+		if n == 10 && w.Stats().Breakers[high].State == "open" {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	st := w.Stats()
+	if st.Breakers[high].State != "open" || st.Breakers[high].Trips != 1 {
+		t.Fatalf("high breaker should have opened once: %+v", st.Breakers[high])
+	}
+	if st.Breakers[low].State != "closed" {
+		t.Fatalf("low breaker should stay closed: %+v", st.Breakers[low])
+	}
+	if n, _ := rdb.LLen(ctx, cfg.Worker.CompletedList).Result(); n != 10 {
+		t.Fatalf("low queue should drain while high is open, completed=%d", n)
+	}
+	if n, _ := rdb.LLen(ctx, cfg.Worker.DeadLetterList).Result(); n != 0 {
+		t.Fatalf("no job should be dead-lettered, got %d", n)
+	}
+	if n, _ := rdb.LLen(ctx, high).Result(); n != 5 {
+		t.Fatalf("high jobs should remain queued, got %d", n)
+	}
+}
+
+func TestRequeueRejectedKeepsPlaceInLine(t *testing.T) {
+	cfg, rdb := newBreakerFixture(t)
+	ctx := context.Background()
+	w := New(cfg, rdb, zap.NewNop())
+	low := cfg.Worker.Queues["low"]
+	rdb.LPush(ctx, low, "next", "later")
+	rdb.LPush(ctx, "proc", "job")
+	rdb.Set(ctx, "hb", "job", time.Minute)
+
+	w.requeueRejected(ctx, w.breakers[low], low, "proc", "hb", "job")
+
+	if got, _ := rdb.LRange(ctx, low, -1, -1).Result(); len(got) != 1 || got[0] != "job" {
+		t.Fatalf("rejected job should be consumed next, tail=%v", got)
+	}
+	if n, _ := rdb.LLen(ctx, "proc").Result(); n != 0 {
+		t.Fatalf("processing list not cleared: %d", n)
+	}
+	if n, _ := rdb.Exists(ctx, "hb").Result(); n != 0 {
+		t.Fatal("heartbeat not cleared")
+	}
+	if got := w.Stats().Breakers[low].Requeued; got != 1 {
+		t.Fatalf("requeued = %d", got)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/obs"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+)
+
+// Context is a handler's handle on the job it is running, got with
+// JobContext. It is the handler's context.Context, so it can be passed on,
+// and enqueues follow-up jobs that stay in the job's trace.
+type Context struct {
+	context.Context
+	w   *Worker
+	job queue.Job
+}
+
+type jobContextKey struct{}
+
+// withJobContext makes JobContext work in handlers run with the returned
+// context.
+func (w *Worker) withJobContext(ctx context.Context, job queue.Job) context.Context {
+	return context.WithValue(ctx, jobContextKey{}, &Context{w: w, job: job})
+}
+
+// JobContext returns the Context of the job being processed by the
+// handler ctx was given to, bound to ctx so that its cancellation and
+// current span apply. It returns nil outside a handler.
+func JobContext(ctx context.Context) *Context {
+	jc, ok := ctx.Value(jobContextKey{}).(*Context)
+	if !ok {
+		return nil
+	}
+	return &Context{Context: ctx, w: jc.w, job: jc.job}
+}
+
+// Job returns the job being processed.
+func (c *Context) Job() queue.Job {
+	return c.job
+}
+
+// Enqueue adds child to target, a priority alias from worker.queues or a
+// queue key, as a follow-up of the job being processed, the way the
+// producer's Enqueue would. The child gets the job's request ID and ID as
+// ParentID, and continues the job's trace: its span ID is that of an
+// enqueue span started under the span current in c, so the worker that
+// runs it records its processing below this job's. An ID, creation time
+// and, for an alias, priority are filled in when empty; trace and request
+// IDs already set on child are kept. It returns the child's ID.
+func (c *Context) Enqueue(target string, child queue.Job) (string, error) {
+	if target == "" {
+		return "", errors.New("queue is required")
+	}
+	key := target
+	if k, ok := c.w.cfg.Worker.Queues[target]; ok && k != "" {
+		key = k
+		if child.Priority == "" {
+			child.Priority = target
+		}
+	}
+	if child.ID == "" {
+		var b [16]byte
+		_, _ = rand.Read(b[:])
+		child.ID = hex.EncodeToString(b[:])
+	}
+	if child.CreationTime == "" {
+		child.CreationTime = time.Now().UTC().Format(time.RFC3339Nano)
+	}
+	child.ParentID = c.job.ID
+	if child.RequestID == "" {
+		child.RequestID = obs.RequestID(c)
+		if child.RequestID == "" {
+			child.RequestID = c.job.RequestID
+		}
+	}
+
+	ctx, span := obs.StartEnqueueSpan(c, key, child.Priority)
+	defer span.End()
+	if child.TraceID == "" {
+		child.TraceID, child.SpanID = obs.GetTraceAndSpanID(ctx)
+		if child.TraceID == "" {
+			// tracing is off; keep the job's IDs so logs still link up
+			child.TraceID, child.SpanID = c.job.TraceID, c.job.SpanID
+		}
+	}
+	obs.AddSpanAttributes(ctx,
+		obs.KeyValue("job.id", child.ID),
+		obs.KeyValue("job.parent_id", child.ParentID),
+		obs.KeyValue("job.request_id", child.RequestID),
+	)
+
+	payload, err := child.Marshal()
+	if err != nil {
+		return "", err
+	}
+	if err := c.w.children.Enqueue(ctx, key, payload); err != nil {
+		obs.RecordError(ctx, err)
+		return "", err
+	}
+	obs.SetSpanSuccess(ctx)
+	c.w.log.Info("enqueued child job", obs.String("id", child.ID), obs.String("parent_id", child.ParentID), obs.String("queue", key), obs.String("trace_id", child.TraceID), obs.RequestIDField(ctx))
+	return child.ID, nil
+}
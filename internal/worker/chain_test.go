@@ -0,0 +1,98 @@
+//go:build worker_tests
+// +build worker_tests
+
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/obs"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestContextEnqueueContinuesTraceAndRequest(t *testing.T) {
+	w, cfg, rdb, cleanup := setupWorkerTest(t)
+	defer cleanup()
+	spans := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)))
+	defer otel.SetTracerProvider(prev)
+	ctx := context.Background()
+
+	if JobContext(ctx) != nil {
+		t.Fatal("expected no job context outside a handler")
+	}
+
+	var childID string
+	w.Register("parent", func(ctx context.Context, job queue.Job) error {
+		var err error
+		childID, err = JobContext(ctx).Enqueue("high", queue.Job{Type: "child", FilePath: "/tmp/child.txt"})
+		return err
+	})
+	var got queue.Job
+	var gotRequestID string
+	w.Register("child", func(ctx context.Context, job queue.Job) error {
+		got = JobContext(ctx).Job()
+		gotRequestID = obs.RequestID(ctx)
+		return nil
+	})
+
+	parent := queue.NewJob("p1", "/tmp/parent.txt", 10, "low", "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7")
+	parent.Type = "parent"
+	parent.RequestID = "req-1"
+	payload, _ := parent.Marshal()
+	procList := fmt.Sprintf(cfg.Worker.ProcessingListPattern, "w1")
+	hbKey := fmt.Sprintf(cfg.Worker.HeartbeatKeyPattern, "w1")
+	if !w.processJob(ctx, "w1", cfg.Worker.Queues["low"], procList, hbKey, payload) {
+		t.Fatal("expected the parent to complete")
+	}
+
+	childPayload, err := rdb.RPop(ctx, cfg.Worker.Queues["high"]).Result()
+	if err != nil {
+		t.Fatalf("expected the child on the high queue: %v", err)
+	}
+	if !w.processJob(ctx, "w1", cfg.Worker.Queues["high"], procList, hbKey, childPayload) {
+		t.Fatal("expected the child to complete")
+	}
+
+	if got.ID != childID || got.ParentID != "p1" || got.Priority != "high" || got.CreationTime == "" {
+		t.Fatalf("unexpected child job %+v (id %s)", got, childID)
+	}
+	if got.RequestID != "req-1" || gotRequestID != "req-1" {
+		t.Fatalf("expected the parent's request ID, got %q on the job and %q in the handler", got.RequestID, gotRequestID)
+	}
+	if got.TraceID != parent.TraceID {
+		t.Fatalf("expected the parent's trace, got %s", got.TraceID)
+	}
+
+	// parent job.process -> queue.enqueue (the child's span ID) -> child job.process
+	var parentProcess, enqueue, childProcess sdktrace.ReadOnlySpan
+	for _, s := range spans.Ended() {
+		switch {
+		case s.Name() == "queue.enqueue":
+			enqueue = s
+		case s.Name() == "job.process" && s.Parent().SpanID().String() == parent.SpanID:
+			parentProcess = s
+		case s.Name() == "job.process":
+			childProcess = s
+		}
+	}
+	if parentProcess == nil || enqueue == nil || childProcess == nil {
+		t.Fatalf("missing spans: %v", spans.Ended())
+	}
+	if enqueue.Parent().SpanID() != parentProcess.SpanContext().SpanID() {
+		t.Fatal("expected the enqueue span under the parent's processing span")
+	}
+	if got.SpanID != enqueue.SpanContext().SpanID().String() || childProcess.Parent().SpanID() != enqueue.SpanContext().SpanID() {
+		t.Fatal("expected the child's processing span under the enqueue span")
+	}
+	if childProcess.SpanContext().TraceID().String() != parent.TraceID {
+		t.Fatal("expected the child processed in the parent's trace")
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/obs"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/redis/go-redis/v9"
+)
+
+// Coalesce marker states.
+const (
+	coalesceRunning   = "running"
+	coalesceCompleted = "completed"
+)
+
+// coalesceMarker is stored at coalesce:{queue}:{dedupKey} while the job
+// doing the work runs, and replaced with its result once it completes.
+type coalesceMarker struct {
+	State  string `json:"state"`
+	JobID  string `json:"job_id"`
+	Result string `json:"result,omitempty"`
+}
+
+// coalesceScript sets the marker if it is absent and returns nil, or
+// returns the marker already there.
+// KEYS[1]=coalesce marker
+// ARGV[1]=marker value, ARGV[2]=ttl ms
+var coalesceScript = redis.NewScript(`
+if redis.call('SET', KEYS[1], ARGV[1], 'NX', 'PX', ARGV[2]) then
+  return false
+end
+return redis.call('GET', KEYS[1])
+`)
+
+// coalesceQueues maps the keys of the queues listed in
+// worker.coalesce.queues.
+func coalesceQueues(cfg *config.Config) map[string]bool {
+	out := make(map[string]bool, len(cfg.Worker.Coalesce.Queues))
+	for _, p := range cfg.Worker.Coalesce.Queues {
+		if key := cfg.Worker.Queues[p]; key != "" {
+			out[key] = true
+		}
+	}
+	return out
+}
+
+// coalesceKey is queue.CoalesceKey of srcQueue and dedupKey in the
+// config's namespace.
+func (w *Worker) coalesceKey(srcQueue, dedupKey string) string {
+	return w.cfg.DerivedKey(func(q string) string { return queue.CoalesceKey(q, dedupKey) }, srcQueue)
+}
+
+// coalesceJob marks job as doing the work for its dedup key. It returns
+// the marker of another job with the same key that is running or
+// completed, in which case job should not run. A marker left by job itself,
+// as when the reaper requeues it after its worker died, lets it run again.
+func (w *Worker) coalesceJob(ctx context.Context, srcQueue string, job queue.Job) (*coalesceMarker, error) {
+	own, err := json.Marshal(coalesceMarker{State: coalesceRunning, JobID: job.ID})
+	if err != nil {
+		return nil, err
+	}
+	data, err := coalesceScript.Run(ctx, w.client(srcQueue),
+		[]string{w.coalesceKey(srcQueue, job.DedupKey)},
+		own, w.cfg.Worker.Coalesce.TTL.Milliseconds()).Text()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var m coalesceMarker
+	if err := json.Unmarshal([]byte(data), &m); err != nil {
+		return nil, err
+	}
+	if m.JobID == job.ID {
+		return nil, nil
+	}
+	return &m, nil
+}
+
+// markCoalesced replaces job's running marker with its result, so later
+// duplicates complete with it for worker.coalesce.ttl.
+func (w *Worker) markCoalesced(ctx context.Context, srcQueue string, job queue.Job, result string) {
+	data, err := json.Marshal(coalesceMarker{State: coalesceCompleted, JobID: job.ID, Result: result})
+	if err == nil {
+		err = w.client(srcQueue).Set(ctx, w.coalesceKey(srcQueue, job.DedupKey), data, w.cfg.Worker.Coalesce.TTL).Err()
+	}
+	if err != nil {
+		w.log.Error("SET coalesce marker failed", obs.String("id", job.ID), obs.String("dedup_key", job.DedupKey), obs.Err(err))
+	}
+}
+
+// releaseCoalesce drops the marker of a failed job so the next job with
+// its dedup key runs. Duplicates already completed against it stay
+// completed.
+func (w *Worker) releaseCoalesce(ctx context.Context, srcQueue string, job queue.Job) {
+	if err := w.client(srcQueue).Del(ctx, w.coalesceKey(srcQueue, job.DedupKey)).Err(); err != nil {
+		w.log.Error("DEL coalesce marker failed", obs.String("id", job.ID), obs.String("dedup_key", job.DedupKey), obs.Err(err))
+	}
+}
+
+// completeCoalesced acks a duplicate of the job in m without running it:
+// it goes to the completed list with a completion event carrying m's
+// result and coalesced_with set to m's job ID.
+func (w *Worker) completeCoalesced(ctx context.Context, workerID, srcQueue, procList, hbKey, payload string, job queue.Job, m *coalesceMarker) {
+	rc := w.client(srcQueue)
+	ev := w.completionEvent(ctx, job, StatusCompleted, srcQueue, workerID, 0, m.Result)
+	if ev != nil {
+		ev.CoalescedWith = m.JobID
+	}
+	if err := w.pushOutcome(ctx, w.cfg.Worker.CompletedList, payload, ev); err != nil {
+		w.log.Error("LPUSH completed failed", obs.Err(err))
+	}
+	if err := rc.LRem(ctx, procList, 1, payload).Err(); err != nil {
+		w.log.Error("LREM processing failed", obs.Err(err))
+	}
+	if err := rc.Del(ctx, hbKey).Err(); err != nil {
+		w.log.Error("DEL heartbeat failed", obs.Err(err))
+	}
+	obs.JobsCoalesced.WithLabelValues(srcQueue).Inc()
+	obs.JobsCompleted.Inc()
+	w.silence.record(srcQueue)
+	w.log.Info("job coalesced", obs.String("id", job.ID), obs.String("dedup_key", job.DedupKey), obs.String("coalesced_with", m.JobID), obs.String("state", m.State), obs.String("worker_id", workerID), obs.RequestIDField(ctx))
+}
@@ -0,0 +1,124 @@
+//go:build worker_tests
+// +build worker_tests
+
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+)
+
+func coalesceJobPayload(id, key string) string {
+	job := queue.NewJob(id, "/tmp/ok.txt", 1, "low", "", "")
+	job.DedupKey = key
+	payload, _ := job.Marshal()
+	return payload
+}
+
+func TestCoalesceCompletesDuplicatesWithFirstResult(t *testing.T) {
+	w, cfg, rdb, cleanup := setupWorkerTest(t)
+	defer cleanup()
+	cfg.Worker.Coalesce.Queues = []string{"low"}
+	cfg.Worker.CompletionStream.Stream = "jobqueue:completions"
+	w.coalesce = coalesceQueues(cfg)
+	ctx := context.Background()
+	src := cfg.Worker.Queues["low"]
+	procList := fmt.Sprintf(cfg.Worker.ProcessingListPattern, "w1")
+	hbKey := fmt.Sprintf(cfg.Worker.HeartbeatKeyPattern, "w1")
+
+	var ran []string
+	w.handler = func(ctx context.Context, job queue.Job) error {
+		ran = append(ran, job.ID)
+		if job.ID == "a" {
+			// a duplicate arrives while the first job is still running
+			dup := coalesceJobPayload("b", "thumb:1")
+			_ = rdb.LPush(ctx, procList, dup).Err()
+			if !w.processJob(ctx, "w1", src, procList, hbKey, dup) {
+				t.Error("expected the in-flight duplicate to complete")
+			}
+		}
+		SetResult(ctx, "s3://thumbs/1.png")
+		return nil
+	}
+
+	for _, id := range []string{"a", "c"} {
+		payload := coalesceJobPayload(id, "thumb:1")
+		_ = rdb.LPush(ctx, procList, payload).Err()
+		if !w.processJob(ctx, "w1", src, procList, hbKey, payload) {
+			t.Fatalf("job %s: expected success", id)
+		}
+	}
+	// jobs without a key never coalesce
+	for _, id := range []string{"d", "e"} {
+		payload, _ := queue.NewJob(id, "/tmp/ok.txt", 1, "low", "", "").Marshal()
+		w.processJob(ctx, "w1", src, procList, hbKey, payload)
+	}
+
+	if fmt.Sprint(ran) != "[a d e]" {
+		t.Fatalf("expected only a, d and e to run, ran %v", ran)
+	}
+	if n, _ := rdb.LLen(ctx, cfg.Worker.CompletedList).Result(); n != 5 {
+		t.Fatalf("expected 5 completed, got %d", n)
+	}
+	if n, _ := rdb.LLen(ctx, procList).Result(); n != 0 {
+		t.Fatalf("expected the duplicates to be acked, got %d in processing", n)
+	}
+	coalesced := map[string]CompletionEvent{}
+	for _, ev := range completionEvents(t, w) {
+		if ev.CoalescedWith != "" {
+			coalesced[ev.JobID] = ev
+		}
+	}
+	if len(coalesced) != 2 || coalesced["b"].CoalescedWith != "a" || coalesced["c"].CoalescedWith != "a" {
+		t.Fatalf("expected b and c coalesced with a, got %+v", coalesced)
+	}
+	if coalesced["b"].Result != "" || coalesced["c"].Result != "s3://thumbs/1.png" {
+		t.Fatalf("expected only the duplicate after completion to share the result, got %+v", coalesced)
+	}
+	if ttl := rdb.PTTL(ctx, queue.CoalesceKey(src, "thumb:1")).Val(); ttl <= 0 || ttl > cfg.Worker.Coalesce.TTL {
+		t.Fatalf("expected the marker to expire within coalesce.ttl, got %v", ttl)
+	}
+}
+
+func TestCoalesceReleasesMarkerOnFailure(t *testing.T) {
+	w, cfg, rdb, cleanup := setupWorkerTest(t)
+	defer cleanup()
+	cfg.Worker.Coalesce.Queues = []string{"low"}
+	w.coalesce = coalesceQueues(cfg)
+	ctx := context.Background()
+	src := cfg.Worker.Queues["low"]
+	procList := fmt.Sprintf(cfg.Worker.ProcessingListPattern, "w1")
+	runs := 0
+	w.handler = func(ctx context.Context, job queue.Job) error {
+		runs++
+		if runs == 1 {
+			return errJobFailed
+		}
+		return nil
+	}
+
+	first := coalesceJobPayload("a", "report:7")
+	_ = rdb.LPush(ctx, procList, first).Err()
+	if w.processJob(ctx, "w1", src, procList, "hb", first) {
+		t.Fatal("expected the first job to fail")
+	}
+	if rdb.Exists(ctx, queue.CoalesceKey(src, "report:7")).Val() != 0 {
+		t.Fatal("expected the failed job's marker to be released")
+	}
+	second := coalesceJobPayload("b", "report:7")
+	_ = rdb.LPush(ctx, procList, second).Err()
+	if !w.processJob(ctx, "w1", src, procList, "hb", second) || runs != 2 {
+		t.Fatalf("expected the next job with the key to run, runs=%d", runs)
+	}
+
+	// a job the reaper requeued after its worker died finds its own marker
+	_ = rdb.Set(ctx, queue.CoalesceKey(src, "report:8"), `{"state":"running","job_id":"c"}`, 0).Err()
+	requeued := coalesceJobPayload("c", "report:8")
+	if !w.processJob(ctx, "w1", src, procList, "hb", requeued) || runs != 3 {
+		t.Fatalf("expected the requeued job to run again, runs=%d", runs)
+	}
+}
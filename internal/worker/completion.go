@@ -0,0 +1,181 @@
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/obs"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/redis/go-redis/v9"
+)
+
+// Completion event statuses.
+const (
+	StatusCompleted  = "completed"
+	StatusDeadLetter = "dead_letter"
+)
+
+// CompletionEventField is the stream entry field holding the JSON event.
+const CompletionEventField = "event"
+
+// maxResultSummary bounds CompletionEvent.Result.
+const maxResultSummary = 256
+
+// CompletionEvent is published to worker.completion_stream when a job
+// reaches a final outcome.
+type CompletionEvent struct {
+	JobID      string    `json:"job_id"`
+	Status     string    `json:"status"` // completed or dead_letter
+	Queue      string    `json:"queue"`
+	Priority   string    `json:"priority,omitempty"`
+	WorkerID   string    `json:"worker_id,omitempty"`
+	Retries    int       `json:"retries"`
+	DurationMs int64     `json:"duration_ms"`
+	LatencyMs  int64     `json:"latency_ms,omitempty"`  // creation_time to FinishedAt, if the job has one
+	Result     string    `json:"result,omitempty"`      // SetResult summary, or the failure reason
+	ErrorClass string    `json:"error_class,omitempty"` // dead letters: retryable, permanent or unclassified
+	RequestID  string    `json:"request_id,omitempty"`
+	TraceID    string    `json:"trace_id,omitempty"`
+	SpanID     string    `json:"span_id,omitempty"`
+	FinishedAt time.Time `json:"finished_at"`
+
+	// CoalescedWith is set on a job that did not run because another job
+	// with its dedup key did; Result is that job's.
+	CoalescedWith string `json:"coalesced_with,omitempty"`
+}
+
+type resultKey struct{}
+
+// SetResult records a short summary of what the handler produced, e.g. an
+// output location. It is sent as the result of the job's completion event
+// and is ignored when the worker has no completion stream configured.
+func SetResult(ctx context.Context, summary string) {
+	if slot, ok := ctx.Value(resultKey{}).(*string); ok {
+		*slot = summary
+	}
+}
+
+// withResultSlot gives handlers run with the returned context somewhere to
+// put their SetResult summary.
+func withResultSlot(ctx context.Context) (context.Context, *string) {
+	slot := new(string)
+	return context.WithValue(ctx, resultKey{}, slot), slot
+}
+
+func (w *Worker) completionEnabled() bool {
+	cs := w.cfg.Worker.CompletionStream
+	return cs.Stream != "" || cs.Channel != ""
+}
+
+// completionEvent builds the event for job, or returns nil when no
+// completion stream is configured.
+func (w *Worker) completionEvent(ctx context.Context, job queue.Job, status, srcQueue, workerID string, took time.Duration, result string) *CompletionEvent {
+	if !w.completionEnabled() {
+		return nil
+	}
+	if r := []rune(result); len(r) > maxResultSummary {
+		result = string(r[:maxResultSummary])
+	}
+	reqID := job.RequestID
+	if reqID == "" {
+		reqID = obs.RequestID(ctx)
+	}
+	finished := time.Now().UTC()
+	var latency int64
+	if created, err := time.Parse(time.RFC3339Nano, job.CreationTime); err == nil {
+		latency = max(finished.Sub(created).Milliseconds(), 0)
+	}
+	return &CompletionEvent{
+		JobID:      job.ID,
+		Status:     status,
+		Queue:      srcQueue,
+		Priority:   job.Priority,
+		WorkerID:   workerID,
+		Retries:    job.Retries,
+		DurationMs: took.Milliseconds(),
+		LatencyMs:  latency,
+		Result:     result,
+		RequestID:  reqID,
+		TraceID:    job.TraceID,
+		SpanID:     job.SpanID,
+		FinishedAt: finished,
+	}
+}
+
+// outcomeScript publishes a completion event and then LPUSHes the job, so
+// a failed XADD or PUBLISH aborts the script before the list is touched.
+// A MULTI would still run the LPUSH after the event failed.
+// KEYS[1]=completed or dead letter list, KEYS[2]=event stream or ""
+// ARGV[1]=payload, ARGV[2]=event, ARGV[3]=channel or "", ARGV[4]=max len
+var outcomeScript = redis.NewScript(`
+if KEYS[2] ~= '' then
+  if tonumber(ARGV[4]) > 0 then
+    redis.call('XADD', KEYS[2], 'MAXLEN', '~', ARGV[4], '*', 'event', ARGV[2])
+  else
+    redis.call('XADD', KEYS[2], '*', 'event', ARGV[2])
+  end
+end
+if ARGV[3] ~= '' then
+  redis.call('PUBLISH', ARGV[3], ARGV[2])
+end
+return redis.call('LPUSH', KEYS[1], ARGV[1])
+`)
+
+// pushOutcome LPUSHes payload onto list, the completed or dead letter list,
+// and publishes ev when it is not nil. With completion_stream.transactional
+// both happen in one script, so there is no list entry without its event.
+// Otherwise the event follows a successful push and a failure to publish it
+// is only logged. The script runs on the list's cluster, so the stream must
+// be routed with it.
+func (w *Worker) pushOutcome(ctx context.Context, list, payload string, ev *CompletionEvent) error {
+	cs := w.cfg.Worker.CompletionStream
+	if ev == nil || !cs.Transactional {
+		if err := w.client(list).LPush(ctx, list, payload).Err(); err != nil {
+			return err
+		}
+		w.publishCompletion(ctx, ev)
+		return nil
+	}
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	return outcomeScript.Run(ctx, w.client(list), []string{list, cs.Stream}, payload, data, cs.Channel, cs.MaxLen).Err()
+}
+
+// publishCompletion sends ev outside of any transaction. Failures are
+// logged and counted; the job's outcome stands either way.
+func (w *Worker) publishCompletion(ctx context.Context, ev *CompletionEvent) {
+	if ev == nil {
+		return
+	}
+	data, err := json.Marshal(ev)
+	if err == nil {
+		_, err = w.client(w.cfg.Worker.CompletionStream.Stream).Pipelined(ctx, func(p redis.Pipeliner) error {
+			w.queueCompletion(ctx, p, string(data))
+			return nil
+		})
+	}
+	if err != nil {
+		obs.CompletionEventsFailed.Inc()
+		w.log.Warn("completion event not published", obs.String("id", ev.JobID), obs.String("status", ev.Status), obs.Err(err))
+	}
+}
+
+// queueCompletion adds the XADD and PUBLISH for an encoded event to p.
+func (w *Worker) queueCompletion(ctx context.Context, p redis.Pipeliner, data string) {
+	cs := w.cfg.Worker.CompletionStream
+	if cs.Stream != "" {
+		p.XAdd(ctx, &redis.XAddArgs{
+			Stream: cs.Stream,
+			MaxLen: cs.MaxLen,
+			Approx: cs.MaxLen > 0,
+			Values: []interface{}{CompletionEventField, data},
+		})
+	}
+	if cs.Channel != "" {
+		p.Publish(ctx, cs.Channel, data)
+	}
+}
@@ -0,0 +1,141 @@
+//go:build worker_tests
+// +build worker_tests
+
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+)
+
+func completionEvents(t *testing.T, w *Worker) []CompletionEvent {
+	t.Helper()
+	msgs, err := w.rdb.XRange(context.Background(), w.cfg.Worker.CompletionStream.Stream, "-", "+").Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := make([]CompletionEvent, 0, len(msgs))
+	for _, m := range msgs {
+		var ev CompletionEvent
+		if err := json.Unmarshal([]byte(m.Values[CompletionEventField].(string)), &ev); err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, ev)
+	}
+	return out
+}
+
+func TestCompletionEventsCarryOutcomeAndCorrelation(t *testing.T) {
+	w, cfg, _, cleanup := setupWorkerTest(t)
+	defer cleanup()
+	cfg.Worker.MaxRetries = 0
+	cfg.Worker.CompletionStream.Stream = "jobqueue:completions"
+	ctx := context.Background()
+	src := cfg.Worker.Queues["low"]
+	procList := fmt.Sprintf(cfg.Worker.ProcessingListPattern, "w1")
+	hbKey := fmt.Sprintf(cfg.Worker.HeartbeatKeyPattern, "w1")
+	w.handler = func(ctx context.Context, job queue.Job) error {
+		if job.ID == "bad" {
+			return errors.New("boom")
+		}
+		SetResult(ctx, "wrote s3://out/"+job.ID)
+		return nil
+	}
+
+	good := queue.NewJob("good", "/tmp/ok.txt", 1, "low", "trace-1", "span-1")
+	good.RequestID = "req-1"
+	good.CreationTime = time.Now().Add(-2 * time.Second).UTC().Format(time.RFC3339Nano)
+	payload, _ := good.Marshal()
+	if !w.processJob(ctx, "w1", src, procList, hbKey, payload) {
+		t.Fatal("expected success")
+	}
+	payload, _ = queue.NewJob("bad", "/tmp/bad.txt", 1, "low", "trace-2", "span-2").Marshal()
+	if w.processJob(ctx, "w1", src, procList, hbKey, payload) {
+		t.Fatal("expected failure")
+	}
+
+	evs := completionEvents(t, w)
+	if len(evs) != 2 {
+		t.Fatalf("expected 2 events, got %+v", evs)
+	}
+	ok := evs[0]
+	if ok.JobID != "good" || ok.Status != StatusCompleted || ok.Queue != src || ok.WorkerID != "w1" ||
+		ok.Result != "wrote s3://out/good" || ok.RequestID != "req-1" || ok.TraceID != "trace-1" || ok.SpanID != "span-1" {
+		t.Fatalf("unexpected completed event: %+v", ok)
+	}
+	if ok.LatencyMs < 2000 {
+		t.Fatalf("expected latency from creation time, got %dms", ok.LatencyMs)
+	}
+	if dead := evs[1]; dead.JobID != "bad" || dead.Status != StatusDeadLetter || dead.Result != "boom" || dead.Retries != 1 || dead.TraceID != "trace-2" {
+		t.Fatalf("unexpected dead letter event: %+v", dead)
+	}
+}
+
+func TestCompletionEventFailureIsNotFatal(t *testing.T) {
+	w, cfg, rdb, cleanup := setupWorkerTest(t)
+	defer cleanup()
+	ctx := context.Background()
+	// A string under the stream key makes XADD fail with WRONGTYPE.
+	cfg.Worker.CompletionStream.Stream = "jobqueue:completions"
+	rdb.Set(ctx, cfg.Worker.CompletionStream.Stream, "x", 0)
+	procList := fmt.Sprintf(cfg.Worker.ProcessingListPattern, "w1")
+	hbKey := fmt.Sprintf(cfg.Worker.HeartbeatKeyPattern, "w1")
+	payload, _ := queue.NewJob("id1", "/tmp/ok.txt", 1, "low", "", "").Marshal()
+
+	if !w.processJob(ctx, "w1", cfg.Worker.Queues["low"], procList, hbKey, payload) {
+		t.Fatal("expected success despite the publish failure")
+	}
+	if n, _ := rdb.LLen(ctx, cfg.Worker.CompletedList).Result(); n != 1 {
+		t.Fatalf("expected the job on the completed list, got %d", n)
+	}
+
+	// Transactional: the completed list entry goes down with the event.
+	cfg.Worker.CompletionStream.Transactional = true
+	payload, _ = queue.NewJob("id2", "/tmp/ok.txt", 1, "low", "", "").Marshal()
+	w.processJob(ctx, "w1", cfg.Worker.Queues["low"], procList, hbKey, payload)
+	if n, _ := rdb.LLen(ctx, cfg.Worker.CompletedList).Result(); n != 1 {
+		t.Fatalf("expected no completed entry without its event, got %d", n)
+	}
+}
+
+func TestCompletionEventsPublishToChannel(t *testing.T) {
+	w, cfg, rdb, cleanup := setupWorkerTest(t)
+	defer cleanup()
+	ctx := context.Background()
+	cfg.Worker.CompletionStream.Channel = "jobqueue:completions"
+	cfg.Worker.CompletionStream.Transactional = true
+	sub := rdb.Subscribe(ctx, cfg.Worker.CompletionStream.Channel)
+	defer sub.Close()
+	if _, err := sub.Receive(ctx); err != nil {
+		t.Fatal(err)
+	}
+	procList := fmt.Sprintf(cfg.Worker.ProcessingListPattern, "w1")
+	hbKey := fmt.Sprintf(cfg.Worker.HeartbeatKeyPattern, "w1")
+	payload, _ := queue.NewJob("id1", "/tmp/ok.txt", 1, "low", "t1", "s1").Marshal()
+	if !w.processJob(ctx, "w1", cfg.Worker.Queues["low"], procList, hbKey, payload) {
+		t.Fatal("expected success")
+	}
+
+	select {
+	case msg := <-sub.Channel():
+		var ev CompletionEvent
+		if err := json.Unmarshal([]byte(msg.Payload), &ev); err != nil {
+			t.Fatal(err)
+		}
+		if ev.JobID != "id1" || ev.Status != StatusCompleted || ev.TraceID != "t1" {
+			t.Fatalf("unexpected event: %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no completion event published")
+	}
+	if n, _ := rdb.LLen(ctx, cfg.Worker.CompletedList).Result(); n != 1 {
+		t.Fatalf("expected completed 1, got %d", n)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/obs"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/redis/go-redis/v9"
+)
+
+const doneMarker = "done"
+
+// claimScript claims a job ID for a worker and writes the worker's
+// heartbeat in the same step, so the in-progress marker lives exactly as
+// long as the heartbeat guarding the job. A marker held by a worker whose
+// heartbeat is gone is stale, left by a crash, and is taken over.
+// Otherwise the ID is done or running elsewhere: the duplicate is removed
+// from the processing list before any handler can see it, and the marker
+// is returned.
+// KEYS[1]=done marker, KEYS[2]=processing list, KEYS[3]=heartbeat key
+// ARGV[1]=payload, ARGV[2]=worker ID, ARGV[3]=heartbeat ttl ms,
+// ARGV[4]/ARGV[5]=heartbeat key prefix/suffix around a worker ID
+var claimScript = redis.NewScript(`
+local held = redis.call('GET', KEYS[1])
+if held then
+  local holder = string.match(held, '^processing:(.+)$')
+  if not holder or redis.call('EXISTS', ARGV[4] .. holder .. ARGV[5]) == 1 then
+    redis.call('LREM', KEYS[2], 1, ARGV[1])
+    return held
+  end
+end
+redis.call('SET', KEYS[1], 'processing:' .. ARGV[2], 'PX', ARGV[3])
+redis.call('SET', KEYS[3], ARGV[1], 'PX', ARGV[3])
+return false
+`)
+
+// dedupQueues maps the keys of the queues listed in worker.dedup.queues.
+func dedupQueues(cfg *config.Config) map[string]bool {
+	out := make(map[string]bool, len(cfg.Worker.Dedup.Queues))
+	for _, p := range cfg.Worker.Dedup.Queues {
+		if key := cfg.Worker.Queues[p]; key != "" {
+			out[key] = true
+		}
+	}
+	return out
+}
+
+// claimJob marks jobID as in progress by workerID for hbTTL and sets
+// hbKey for as long. It returns false when the ID was already done or in
+// progress on a live worker; the duplicate has then been removed from
+// procList. Markers live on srcQueue's cluster.
+func (w *Worker) claimJob(ctx context.Context, srcQueue, workerID, procList, hbKey, payload, jobID string, hbTTL time.Duration) (bool, error) {
+	hbPrefix, hbSuffix, _ := strings.Cut(w.cfg.Worker.HeartbeatKeyPattern, "%s")
+	marker, err := claimScript.Run(ctx, w.client(srcQueue),
+		[]string{w.cfg.Key(queue.DoneKey(jobID)), procList, hbKey},
+		payload, workerID, hbTTL.Milliseconds(), hbPrefix, hbSuffix).Text()
+	if err == redis.Nil {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	w.log.Warn("duplicate job skipped", obs.String("id", jobID), obs.String("marker", marker), obs.String("worker_id", workerID))
+	return false, nil
+}
+
+// markDone records that jobID completed, so redeliveries are skipped for
+// worker.dedup.ttl.
+func (w *Worker) markDone(ctx context.Context, srcQueue, jobID string) {
+	if err := w.client(srcQueue).Set(ctx, w.cfg.Key(queue.DoneKey(jobID)), doneMarker, w.cfg.Worker.Dedup.TTL).Err(); err != nil {
+		w.log.Error("SET done marker failed", obs.String("id", jobID), obs.Err(err))
+	}
+}
+
+// releaseClaim drops the in-progress marker of a failed job so its retry,
+// or a later DLQ requeue, can run.
+func (w *Worker) releaseClaim(ctx context.Context, srcQueue, jobID string) {
+	if err := w.client(srcQueue).Del(ctx, w.cfg.Key(queue.DoneKey(jobID))).Err(); err != nil {
+		w.log.Error("DEL done marker failed", obs.String("id", jobID), obs.Err(err))
+	}
+}
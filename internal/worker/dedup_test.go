@@ -0,0 +1,170 @@
+//go:build worker_tests
+// +build worker_tests
+
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/flyingrobots/go-redis-work-queue/internal/reaper"
+)
+
+func TestDedupSkipsCompletedJobIDs(t *testing.T) {
+	w, cfg, rdb, cleanup := setupWorkerTest(t)
+	defer cleanup()
+	cfg.Worker.Dedup.Queues = []string{"low"}
+	w.dedup = dedupQueues(cfg)
+	ctx := context.Background()
+	src := cfg.Worker.Queues["low"]
+	procList := fmt.Sprintf(cfg.Worker.ProcessingListPattern, "w1")
+	hbKey := fmt.Sprintf(cfg.Worker.HeartbeatKeyPattern, "w1")
+	runs := 0
+	w.handler = func(ctx context.Context, job queue.Job) error {
+		runs++
+		return nil
+	}
+
+	payload, _ := queue.NewJob("dup", "/tmp/ok.txt", 1, "low", "", "").Marshal()
+	for i := 0; i < 2; i++ {
+		// simulate BRPOPLPUSH of a redelivered copy
+		_ = rdb.LPush(ctx, procList, payload).Err()
+		if w.startJob(ctx, "w1", src, procList, hbKey, payload, cfg.Worker.HeartbeatTTL) &&
+			!w.processJob(ctx, "w1", src, procList, hbKey, payload) {
+			t.Fatalf("delivery %d: expected success", i)
+		}
+	}
+	if runs != 1 {
+		t.Fatalf("expected the handler to run once, got %d", runs)
+	}
+	if n, _ := rdb.LLen(ctx, procList).Result(); n != 0 {
+		t.Fatalf("expected the duplicate to be acked, got %d in processing", n)
+	}
+	if v, _ := rdb.Get(ctx, queue.DoneKey("dup")).Result(); v != doneMarker {
+		t.Fatalf("expected done marker, got %q", v)
+	}
+	if ttl := rdb.PTTL(ctx, queue.DoneKey("dup")).Val(); ttl <= 0 || ttl > cfg.Worker.Dedup.TTL {
+		t.Fatalf("expected the marker to expire within dedup.ttl, got %v", ttl)
+	}
+}
+
+func TestDedupReleasesClaimForRetry(t *testing.T) {
+	w, cfg, rdb, cleanup := setupWorkerTest(t)
+	defer cleanup()
+	cfg.Worker.Dedup.Queues = []string{"low"}
+	w.dedup = dedupQueues(cfg)
+	ctx := context.Background()
+	src := cfg.Worker.Queues["low"]
+	procList := fmt.Sprintf(cfg.Worker.ProcessingListPattern, "w1")
+	hbKey := fmt.Sprintf(cfg.Worker.HeartbeatKeyPattern, "w1")
+	runs := 0
+	w.handler = func(ctx context.Context, job queue.Job) error {
+		runs++
+		if runs == 1 {
+			return errJobFailed
+		}
+		return nil
+	}
+
+	payload, _ := queue.NewJob("retry", "/tmp/ok.txt", 1, "low", "", "").Marshal()
+	_ = rdb.LPush(ctx, procList, payload).Err()
+	if !w.startJob(ctx, "w1", src, procList, hbKey, payload, cfg.Worker.HeartbeatTTL) ||
+		w.processJob(ctx, "w1", src, procList, hbKey, payload) {
+		t.Fatal("expected the first attempt to run and fail")
+	}
+	retried, err := rdb.RPopLPush(ctx, src, procList).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !w.startJob(ctx, "w1", src, procList, hbKey, retried, cfg.Worker.HeartbeatTTL) ||
+		!w.processJob(ctx, "w1", src, procList, hbKey, retried) || runs != 2 {
+		t.Fatalf("expected the retry to run, runs=%d", runs)
+	}
+}
+
+func TestDedupSkipsJobInProgressElsewhere(t *testing.T) {
+	w, cfg, rdb, cleanup := setupWorkerTest(t)
+	defer cleanup()
+	cfg.Worker.Dedup.Queues = []string{"low"}
+	w.dedup = dedupQueues(cfg)
+	ctx := context.Background()
+	procList := fmt.Sprintf(cfg.Worker.ProcessingListPattern, "w2")
+	hbKey := fmt.Sprintf(cfg.Worker.HeartbeatKeyPattern, "w2")
+	// A slow worker still holds the claim, and its heartbeat, when the
+	// reaper requeues its job.
+	_ = rdb.Set(ctx, queue.DoneKey("slow"), "processing:w1", 0).Err()
+	_ = rdb.Set(ctx, fmt.Sprintf(cfg.Worker.HeartbeatKeyPattern, "w1"), "job", 0).Err()
+
+	payload, _ := queue.NewJob("slow", "/tmp/ok.txt", 1, "low", "", "").Marshal()
+	_ = rdb.LPush(ctx, procList, payload).Err()
+	if w.startJob(ctx, "w2", cfg.Worker.Queues["low"], procList, hbKey, payload, cfg.Worker.HeartbeatTTL) {
+		t.Fatal("a job claimed by a live worker must not start")
+	}
+	if n, _ := rdb.LLen(ctx, procList).Result(); n != 0 {
+		t.Fatalf("expected the duplicate to be acked, got %d", n)
+	}
+	if n, _ := rdb.Exists(ctx, hbKey).Result(); n != 0 {
+		t.Fatal("no heartbeat should be left for a skipped duplicate")
+	}
+}
+
+func TestDedupRedeliversAfterCrash(t *testing.T) {
+	w, cfg, rdb, cleanup := setupWorkerTest(t)
+	defer cleanup()
+	cfg.Worker.Dedup.Queues = []string{"low"}
+	cfg.Worker.Reaper.Interval = 5 * time.Millisecond
+	cfg.Worker.Reaper.GracePeriod = 0
+	w.dedup = dedupQueues(cfg)
+	ctx := context.Background()
+	src := cfg.Worker.Queues["low"]
+	runs := 0
+	w.handler = func(ctx context.Context, job queue.Job) error {
+		runs++
+		return nil
+	}
+
+	payload, _ := queue.NewJob("crash", "/tmp/ok.txt", 1, "low", "", "").Marshal()
+	_ = rdb.LPush(ctx, src, payload).Err()
+
+	// w1 takes the job, claims it and dies before finishing.
+	proc1 := fmt.Sprintf(cfg.Worker.ProcessingListPattern, "w1")
+	hb1 := fmt.Sprintf(cfg.Worker.HeartbeatKeyPattern, "w1")
+	got, _ := rdb.RPopLPush(ctx, src, proc1).Result()
+	if !w.startJob(ctx, "w1", src, proc1, hb1, got, cfg.Worker.HeartbeatTTL) {
+		t.Fatal("expected w1 to claim the job")
+	}
+	if ttl := rdb.PTTL(ctx, queue.DoneKey("crash")).Val(); ttl <= 0 || ttl > cfg.Worker.HeartbeatTTL {
+		t.Fatalf("expected the in-progress marker to live as long as the heartbeat, got %v", ttl)
+	}
+	_ = rdb.Del(ctx, hb1).Err() // the heartbeat expires
+
+	rctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	go reaper.New(cfg, rdb, w.log).Run(rctx)
+	for rdb.LLen(ctx, src).Val() == 0 {
+		if rctx.Err() != nil {
+			t.Fatal("the reaper did not requeue the crashed job")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+
+	// w2 gets the redelivered copy and runs it, despite w1's marker.
+	proc2 := fmt.Sprintf(cfg.Worker.ProcessingListPattern, "w2")
+	hb2 := fmt.Sprintf(cfg.Worker.HeartbeatKeyPattern, "w2")
+	redelivered, _ := rdb.RPopLPush(ctx, src, proc2).Result()
+	if !w.startJob(ctx, "w2", src, proc2, hb2, redelivered, cfg.Worker.HeartbeatTTL) ||
+		!w.processJob(ctx, "w2", src, proc2, hb2, redelivered) {
+		t.Fatal("expected the redelivered job to run")
+	}
+	if runs != 1 {
+		t.Fatalf("expected the handler to run once, got %d", runs)
+	}
+	if v, _ := rdb.Get(ctx, queue.DoneKey("crash")).Result(); v != doneMarker {
+		t.Fatalf("expected done marker, got %q", v)
+	}
+}
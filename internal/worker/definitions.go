@@ -0,0 +1,111 @@
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+	"sort"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/obs"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+)
+
+// definitions returns the queues defined through the admin API, re-read
+// every worker.pause_cache_ttl. If they cannot be read, the last known
+// ones are kept.
+func (w *Worker) definitions(ctx context.Context) map[string]queue.Definition {
+	defs, err := w.defs.Get(ctx, w.rdb, w.cfg.Key(queue.DefinitionsKey), w.cfg.Worker.PauseCacheTTL)
+	if err != nil && ctx.Err() == nil {
+		w.log.Warn("queue definitions read failed", obs.Err(err))
+	}
+	return defs
+}
+
+// pollTarget is one queue a fetch may try.
+type pollTarget struct {
+	priority string
+	key      string
+}
+
+// pollTargets turns the priority order into the queues to try. In list
+// mode each defined queue follows its priority's configured queue, in name
+// order; defined queues whose priority this worker does not know go last.
+// Definitions of configured queue keys add nothing here.
+func (w *Worker) pollTargets(ctx context.Context, order []string) []pollTarget {
+	targets := make([]pollTarget, 0, len(order))
+	for _, p := range order {
+		targets = append(targets, pollTarget{priority: p, key: w.cfg.Worker.Queues[p]})
+	}
+	if w.cfg.Worker.Mode == config.ModeStream {
+		return targets
+	}
+	defs := w.definitions(ctx)
+	if len(defs) == 0 {
+		return targets
+	}
+
+	configured := make(map[string]bool, len(w.cfg.Worker.Queues))
+	for _, key := range w.cfg.Worker.Queues {
+		configured[key] = true
+	}
+	names := make([]string, 0, len(defs))
+	for name := range defs {
+		if !configured[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	out := make([]pollTarget, 0, len(targets)+len(names))
+	placed := make(map[string]bool, len(names))
+	for _, t := range targets {
+		out = append(out, t)
+		for _, name := range names {
+			if defs[name].Priority == t.priority {
+				out = append(out, pollTarget{priority: t.priority, key: name})
+				placed[name] = true
+			}
+		}
+	}
+	for _, name := range names {
+		if !placed[name] {
+			out = append(out, pollTarget{priority: defs[name].Priority, key: name})
+		}
+	}
+	return out
+}
+
+// queueRate returns the jobs per second and bucket size that limit
+// fetches from key: its definition's rate limit if it has one, otherwise
+// worker.queue_rate_limits for the priority whose configured queue it is.
+// A zero rate means no limit.
+func (w *Worker) queueRate(ctx context.Context, priority, key string) (rate, burst float64) {
+	if def, ok := w.definitions(ctx)[key]; ok && def.RateLimit > 0 {
+		if def.Burst > 0 {
+			return def.RateLimit, float64(def.Burst)
+		}
+		return def.RateLimit, rateBurst(def.RateLimit)
+	}
+	if w.cfg.Worker.Queues[priority] != key {
+		return 0, 0
+	}
+	rate = w.settings().rateLimits[priority]
+	return rate, rateBurst(rate)
+}
+
+// maxRetries is worker.max_retries unless key's definition overrides it.
+func (w *Worker) maxRetries(ctx context.Context, key string) int {
+	if n := w.definitions(ctx)[key].DeadLetter.MaxRetries; n != nil {
+		return *n
+	}
+	return w.cfg.Worker.MaxRetries
+}
+
+// deadLetterList is worker.dead_letter_list unless key's definition
+// overrides it.
+func (w *Worker) deadLetterList(ctx context.Context, key string) string {
+	if l := w.definitions(ctx)[key].DeadLetter.List; l != "" {
+		return l
+	}
+	return w.cfg.Worker.DeadLetterList
+}
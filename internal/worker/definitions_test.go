@@ -0,0 +1,69 @@
+//go:build worker_tests
+// +build worker_tests
+
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/redis/go-redis/v9"
+)
+
+func defineQueue(t *testing.T, rdb *redis.Client, def queue.Definition) {
+	t.Helper()
+	b, _ := json.Marshal(def)
+	if err := rdb.HSet(context.Background(), queue.DefinitionsKey, def.Name, b).Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPollTargetsIncludeDefinedQueues(t *testing.T) {
+	w, cfg, rdb, cleanup := setupWorkerTest(t)
+	defer cleanup()
+	defineQueue(t, rdb, queue.Definition{Name: "reports:b", Priority: "high"})
+	defineQueue(t, rdb, queue.Definition{Name: "reports:a", Priority: "high"})
+	defineQueue(t, rdb, queue.Definition{Name: "other", Priority: "urgent"})
+	defineQueue(t, rdb, queue.Definition{Name: cfg.Worker.Queues["low"], Priority: "low", MaxLength: 5})
+
+	var got []string
+	for _, target := range w.pollTargets(context.Background(), []string{"high", "low"}) {
+		got = append(got, target.priority+"="+target.key)
+	}
+	want := []string{
+		"high=" + cfg.Worker.Queues["high"], "high=reports:a", "high=reports:b",
+		"low=" + cfg.Worker.Queues["low"], "urgent=other",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestDefinedQueueDeadLetterPolicy(t *testing.T) {
+	w, cfg, rdb, cleanup := setupWorkerTest(t)
+	defer cleanup()
+	zero := 0
+	defineQueue(t, rdb, queue.Definition{Name: "reports", Priority: "low", DeadLetter: queue.DeadLetterPolicy{MaxRetries: &zero, List: "reports:dead"}})
+
+	ctx := context.Background()
+	procList := fmt.Sprintf(cfg.Worker.ProcessingListPattern, "w1")
+	hbKey := fmt.Sprintf(cfg.Worker.HeartbeatKeyPattern, "w1")
+	payload, _ := queue.NewJob("id1", "/tmp/fail.txt", 10, "low", "", "").Marshal()
+	_ = rdb.LPush(ctx, procList, payload).Err()
+	if w.processJob(ctx, "w1", "reports", procList, hbKey, payload) {
+		t.Fatal("expected failure")
+	}
+	if n, _ := rdb.LLen(ctx, "reports:dead").Result(); n != 1 {
+		t.Fatalf("expected the job in the queue's own dead letter list without a retry, got %d", n)
+	}
+	if n, _ := rdb.LLen(ctx, cfg.Worker.DeadLetterList).Result(); n != 0 {
+		t.Fatalf("expected nothing in the default dead letter list, got %d", n)
+	}
+	if got := w.maxRetries(ctx, cfg.Worker.Queues["low"]); got != cfg.Worker.MaxRetries {
+		t.Fatalf("expected undefined queues to keep worker.max_retries, got %d", got)
+	}
+}
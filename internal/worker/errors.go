@@ -0,0 +1,147 @@
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+)
+
+// ErrorClass says how the worker treats a handler error.
+type ErrorClass string
+
+const (
+	// ErrorRetryable errors are retried with backoff up to max_retries.
+	ErrorRetryable ErrorClass = "retryable"
+	// ErrorPermanent errors dead-letter the job at once.
+	ErrorPermanent ErrorClass = "permanent"
+	// ErrorUnclassified errors follow worker.unclassified_errors.
+	ErrorUnclassified ErrorClass = "unclassified"
+)
+
+// RetryableError marks a handler error as transient, e.g. a timeout talking
+// to a dependency. Wrap errors with Retryable.
+type RetryableError struct {
+	Err error
+}
+
+func (e *RetryableError) Error() string { return e.Err.Error() }
+func (e *RetryableError) Unwrap() error { return e.Err }
+
+// PermanentError marks a handler error that retrying cannot fix, e.g. a
+// payload that fails validation. Wrap errors with Permanent.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string { return e.Err.Error() }
+func (e *PermanentError) Unwrap() error { return e.Err }
+
+// Retryable wraps err so the worker retries the job. It returns nil for a
+// nil err.
+func Retryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &RetryableError{Err: err}
+}
+
+// Permanent wraps err so the worker dead-letters the job without retrying.
+// It returns nil for a nil err.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+// ClassifyError finds a RetryableError or PermanentError anywhere in err's
+// chain. If both are present, permanent wins. Panics and unknown job types
+// are permanent.
+func ClassifyError(err error) ErrorClass {
+	var (
+		perr *PermanentError
+		rerr *RetryableError
+		pnc  *PanicError
+	)
+	switch {
+	case errors.As(err, &perr), errors.As(err, &pnc), errors.Is(err, errUnknownJobType):
+		return ErrorPermanent
+	case errors.As(err, &rerr):
+		return ErrorRetryable
+	}
+	return ErrorUnclassified
+}
+
+// classifyFailure returns the dead letter reason for a handler error, its
+// class, and whether the job may still be retried. Reasons of classified
+// errors are prefixed with the class ("permanent: ..."); panics and
+// unknown job types keep their short reasons.
+func (w *Worker) classifyFailure(err error) (reason string, class ErrorClass, retryable bool) {
+	class = ClassifyError(err)
+	var perr *PanicError
+	switch {
+	case errors.As(err, &perr):
+		return "panic", class, false
+	case errors.Is(err, errUnknownJobType):
+		return errUnknownJobType.Error(), class, false
+	}
+	switch class {
+	case ErrorPermanent:
+		return string(class) + ": " + err.Error(), class, false
+	case ErrorRetryable:
+		return string(class) + ": " + err.Error(), class, true
+	}
+	return err.Error(), class, w.cfg.Worker.UnclassifiedErrors != config.UnclassifiedDeadLetter
+}
+
+// deadLetterEntry is payload as it goes to the dead letter list: the job
+// with reason set at worker.dead_letter_reason_field, the dotted path
+// dlq-analytics groups by, and class, when known, in error_class. A payload
+// that is not a JSON object goes as is.
+func (w *Worker) deadLetterEntry(payload, reason string, class ErrorClass) string {
+	raw, err := queue.DecompressPayload(payload)
+	if err != nil {
+		return payload
+	}
+	var doc map[string]interface{}
+	dec := json.NewDecoder(strings.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil || doc == nil {
+		return payload
+	}
+	field := w.cfg.Worker.DeadLetterReasonField
+	if field == "" {
+		field = "error"
+	}
+	setField(doc, strings.Split(field, "."), reason)
+	if class != "" {
+		doc["error_class"] = string(class)
+	}
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return payload
+	}
+	entry := string(out)
+	if c := w.cfg.Producer.Compression; queue.IsCompressed(payload) {
+		entry, _ = queue.CompressPayload(entry, c.Codec, c.MinSize)
+	}
+	return entry
+}
+
+// setField sets the dotted path in doc to v, replacing whatever is on the
+// way that is not an object.
+func setField(doc map[string]interface{}, path []string, v interface{}) {
+	for _, k := range path[:len(path)-1] {
+		next, ok := doc[k].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			doc[k] = next
+		}
+		doc = next
+	}
+	doc[path[len(path)-1]] = v
+}
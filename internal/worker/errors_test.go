@@ -0,0 +1,96 @@
+//go:build worker_tests
+// +build worker_tests
+
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/admin"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+)
+
+func TestPermanentErrorsSkipRetries(t *testing.T) {
+	w, cfg, rdb, cleanup := setupWorkerTest(t)
+	defer cleanup()
+	cfg.Worker.MaxRetries = 5
+	cfg.Worker.CompletionStream.Stream = "jobqueue:completions"
+	ctx := context.Background()
+	src := cfg.Worker.Queues["low"]
+	procList := fmt.Sprintf(cfg.Worker.ProcessingListPattern, "w1")
+	hbKey := fmt.Sprintf(cfg.Worker.HeartbeatKeyPattern, "w1")
+	w.handler = func(ctx context.Context, job queue.Job) error {
+		if job.ID == "bad" {
+			return Permanent(errors.New("schema mismatch"))
+		}
+		return Retryable(errors.New("upstream timeout"))
+	}
+
+	payload, _ := queue.NewJob("bad", "/tmp/bad.txt", 1, "low", "", "").Marshal()
+	if w.processJob(ctx, "w1", src, procList, hbKey, payload) {
+		t.Fatal("expected failure")
+	}
+	if n := rdb.LLen(ctx, cfg.Worker.DeadLetterList).Val(); n != 1 {
+		t.Fatalf("expected the permanent failure in the DLQ at once, got %d", n)
+	}
+
+	payload, _ = queue.NewJob("flaky", "/tmp/flaky.txt", 1, "low", "", "").Marshal()
+	if w.processJob(ctx, "w1", src, procList, hbKey, payload) {
+		t.Fatal("expected failure")
+	}
+	if n := rdb.LLen(ctx, src).Val(); n != 1 {
+		t.Fatalf("expected the retryable failure requeued, low has %d", n)
+	}
+
+	evs := completionEvents(t, w)
+	if len(evs) != 1 {
+		t.Fatalf("expected one dead letter event, got %+v", evs)
+	}
+	if ev := evs[0]; ev.JobID != "bad" || ev.ErrorClass != string(ErrorPermanent) || ev.Result != "permanent: schema mismatch" || ev.Retries != 1 {
+		t.Fatalf("unexpected dead letter event: %+v", ev)
+	}
+}
+
+func TestDeadLetterEntriesCarryTheirReason(t *testing.T) {
+	w, cfg, rdb, cleanup := setupWorkerTest(t)
+	defer cleanup()
+	cfg.Worker.DeadLetterReasonField = "failure.reason"
+	ctx := context.Background()
+	src := cfg.Worker.Queues["low"]
+	procList := fmt.Sprintf(cfg.Worker.ProcessingListPattern, "w1")
+	hbKey := fmt.Sprintf(cfg.Worker.HeartbeatKeyPattern, "w1")
+	w.handler = func(ctx context.Context, job queue.Job) error {
+		return Permanent(errors.New("schema mismatch"))
+	}
+
+	for _, id := range []string{"a", "b"} {
+		payload, _ := queue.NewJob(id, "/tmp/bad.txt", 4096, "low", "", "").Marshal()
+		_ = rdb.LPush(ctx, procList, payload).Err()
+		if w.processJob(ctx, "w1", src, procList, hbKey, payload) {
+			t.Fatal("expected failure")
+		}
+	}
+
+	rep, err := admin.DLQAnalytics(ctx, cfg, rdb, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rep.Reasons) != 1 || rep.Reasons[0].Name != "permanent: schema mismatch" || rep.Reasons[0].Count != 2 {
+		t.Fatalf("expected the dead letters grouped by their reason, got %+v", rep.Reasons)
+	}
+	dlq, _ := rdb.LRange(ctx, cfg.Worker.DeadLetterList, 0, 0).Result()
+	if job, err := queue.UnmarshalJob(dlq[0]); err != nil || job.ID != "b" || job.FileSize != 4096 {
+		t.Fatalf("the dead letter entry should still be the job: %+v, %v", job, err)
+	}
+	var entry struct {
+		ErrorClass string `json:"error_class"`
+	}
+	if err := json.Unmarshal([]byte(dlq[0]), &entry); err != nil || entry.ErrorClass != string(ErrorPermanent) {
+		t.Fatalf("expected error_class %q on the dead letter entry, got %q (%v)", ErrorPermanent, entry.ErrorClass, err)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/obs"
+)
+
+// priorityWeights returns the worker.fair_scheduling weight of each entry in
+// worker.priorities; priorities without one weigh 1.
+func priorityWeights(cfg *config.Config) []int {
+	weights := make([]int, len(cfg.Worker.Priorities))
+	for i, p := range cfg.Worker.Priorities {
+		weights[i] = 1
+		if wt, ok := cfg.Worker.FairScheduling.Weights[p]; ok && wt > 0 {
+			weights[i] = wt
+		}
+	}
+	return weights
+}
+
+// fairOrder picks which priority a fetch should try first using smooth
+// weighted round robin over the non-empty queues. lengths and weights are
+// indexed like worker.priorities; credits is the state a goroutine carries
+// from one fetch to the next (all zero to start) and next replaces it.
+//
+// The order starts with the pick, then the other non-empty queues and then
+// the empty ones, each in priority order, so a fetch falls through to the
+// next queue if the first turns out to be empty. With every queue empty the
+// order is plain priority order, as in strict scheduling.
+func fairOrder(lengths []int64, weights, credits []int) (order, next []int) {
+	next = make([]int, len(credits))
+	copy(next, credits)
+
+	pick, total := -1, 0
+	for i, n := range lengths {
+		if n <= 0 {
+			continue
+		}
+		next[i] += weights[i]
+		total += weights[i]
+		// ties go to the higher priority
+		if pick < 0 || next[i] > next[pick] {
+			pick = i
+		}
+	}
+
+	order = make([]int, 0, len(lengths))
+	if pick >= 0 {
+		next[pick] -= total
+		order = append(order, pick)
+	}
+	for i, n := range lengths {
+		if n > 0 && i != pick {
+			order = append(order, i)
+		}
+	}
+	for i, n := range lengths {
+		if n <= 0 {
+			order = append(order, i)
+		}
+	}
+	return order, next
+}
+
+// pollOrder returns the priorities in the order runOne should try them. In
+// weighted mode it reads every queue's length, one pipeline per cluster, and
+// updates credits in place; on a Redis error it falls back to strict order.
+func (w *Worker) pollOrder(ctx context.Context, credits []int) []string {
+	prios := w.cfg.Worker.Priorities
+	if w.cfg.Worker.FairScheduling.Mode != config.SchedulingWeighted || len(prios) < 2 {
+		return prios
+	}
+
+	keys := make([]string, len(prios))
+	for i, p := range prios {
+		keys[i] = w.cfg.Worker.Queues[p]
+	}
+	lengths, err := w.queueLengths(ctx, keys)
+	if err != nil {
+		if ctx.Err() == nil {
+			w.log.Warn("LLEN for fair scheduling failed; using strict order", obs.Err(err))
+		}
+		return prios
+	}
+
+	order, next := fairOrder(lengths, w.weights, credits)
+	copy(credits, next)
+
+	out := make([]string, len(order))
+	for i, idx := range order {
+		out[i] = prios[idx]
+	}
+	return out
+}
@@ -0,0 +1,80 @@
+//go:build worker_tests
+// +build worker_tests
+
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+)
+
+func TestFairOrderInterleavesByWeight(t *testing.T) {
+	weights := []int{3, 1}
+	credits := make([]int, 2)
+	var firsts []int
+	for i := 0; i < 8; i++ {
+		var order []int
+		order, credits = fairOrder([]int64{100, 100}, weights, credits)
+		firsts = append(firsts, order[0])
+	}
+	if want := []int{0, 0, 1, 0, 0, 0, 1, 0}; !reflect.DeepEqual(firsts, want) {
+		t.Fatalf("first picks = %v, want %v", firsts, want)
+	}
+}
+
+func TestFairOrderDoesNotStarveLowPriority(t *testing.T) {
+	weights := []int{10, 1}
+	credits := make([]int, 2)
+	served := make([]int, 2)
+	for i := 0; i < 110; i++ {
+		var order []int
+		order, credits = fairOrder([]int64{1000, 1000}, weights, credits)
+		served[order[0]]++
+	}
+	if served[0] != 100 || served[1] != 10 {
+		t.Fatalf("served = %v, want [100 10]", served)
+	}
+}
+
+func TestFairOrderEmptyQueues(t *testing.T) {
+	order, next := fairOrder([]int64{0, 5, 0}, []int{4, 1, 2}, []int{0, 0, 0})
+	if want := []int{1, 0, 2}; !reflect.DeepEqual(order, want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+	if !reflect.DeepEqual(next, []int{0, 0, 0}) {
+		t.Fatalf("a lone non-empty queue should keep zero credit, got %v", next)
+	}
+
+	order, _ = fairOrder([]int64{0, 0, 0}, []int{4, 1, 2}, []int{0, 0, 0})
+	if want := []int{0, 1, 2}; !reflect.DeepEqual(order, want) {
+		t.Fatalf("all empty: order = %v, want strict %v", order, want)
+	}
+}
+
+func TestPollOrderWeighted(t *testing.T) {
+	w, cfg, rdb, cleanup := setupWorkerTest(t)
+	defer cleanup()
+	ctx := context.Background()
+	credits := make([]int, len(cfg.Worker.Priorities))
+
+	if got := w.pollOrder(ctx, credits); !reflect.DeepEqual(got, cfg.Worker.Priorities) {
+		t.Fatalf("strict order = %v", got)
+	}
+
+	cfg.Worker.FairScheduling = config.WorkerFairScheduling{Mode: config.SchedulingWeighted, Weights: map[string]int{"high": 2}}
+	w.weights = priorityWeights(cfg)
+	for _, p := range cfg.Worker.Priorities {
+		rdb.LPush(ctx, cfg.Worker.Queues[p], "a", "b", "c")
+	}
+	var firsts []string
+	for i := 0; i < 3; i++ {
+		firsts = append(firsts, w.pollOrder(ctx, credits)[0])
+	}
+	if want := []string{"high", "low", "high"}; !reflect.DeepEqual(firsts, want) {
+		t.Fatalf("weighted first picks = %v, want %v", firsts, want)
+	}
+}
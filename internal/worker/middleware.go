@@ -0,0 +1,115 @@
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/obs"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	tracedrilldownlogtail "github.com/flyingrobots/go-redis-work-queue/internal/trace-drilldown-log-tail"
+	"go.uber.org/zap"
+)
+
+// Handler executes a single job. A non-nil error marks the job as failed and
+// sends it through the retry/dead-letter path.
+type Handler func(ctx context.Context, job queue.Job) error
+
+// HandlerMiddleware wraps a Handler to add cross-cutting behaviour.
+type HandlerMiddleware func(next Handler) Handler
+
+// PanicError is returned by RecoverMiddleware when a handler panics. Jobs
+// failing with a PanicError skip retries and go straight to the dead letter
+// queue, since replaying them would most likely panic again.
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("handler panic: %v", e.Value)
+}
+
+// Use appends middleware to the chain wrapped around every job execution.
+// Middleware composes in registration order: the first registered is the
+// outermost, so it sees the job first and the result last. Use must be
+// called before Run.
+func (w *Worker) Use(mw ...HandlerMiddleware) {
+	w.middleware = append(w.middleware, mw...)
+}
+
+// chain returns the type dispatcher wrapped in all registered middleware.
+func (w *Worker) chain() Handler {
+	h := Handler(w.dispatch)
+	for i := len(w.middleware) - 1; i >= 0; i-- {
+		h = w.middleware[i](h)
+	}
+	return h
+}
+
+// RecoverMiddleware converts handler panics into a *PanicError so the worker
+// dead-letters the job instead of crashing.
+func RecoverMiddleware(log *zap.Logger) HandlerMiddleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, job queue.Job) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					perr := &PanicError{Value: r, Stack: debug.Stack()}
+					if log != nil {
+						log.Error("job handler panic", obs.String("id", job.ID), obs.String("panic", fmt.Sprint(r)), zap.ByteString("stack", perr.Stack), obs.RequestIDField(ctx))
+					}
+					err = perr
+				}
+			}()
+			return next(ctx, job)
+		}
+	}
+}
+
+// TracingMiddleware records each job execution as a trace in tm. The trace
+// status is "ok" or "error" depending on the handler result.
+func TracingMiddleware(tm *tracedrilldownlogtail.TraceManager) HandlerMiddleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, job queue.Job) error {
+			traceCtx, tctx := tm.StartTrace(ctx, "job.process")
+			if traceCtx == nil {
+				return next(ctx, job)
+			}
+			tm.AddTraceLog(tctx, "info", "job started", map[string]interface{}{
+				"job.id":       job.ID,
+				"job.priority": job.Priority,
+				"job.retries":  job.Retries,
+			})
+			err := next(tctx, job)
+			status := "ok"
+			if err != nil {
+				status = "error"
+				tm.AddTraceLog(tctx, "error", "job failed", map[string]interface{}{
+					"job.id": job.ID,
+					"error":  err.Error(),
+				})
+			}
+			tm.EndTrace(tctx, status)
+			return err
+		}
+	}
+}
+
+// LatencyMiddleware observes handler latency in obs.JobHandlerDuration,
+// labelled by job priority and outcome.
+func LatencyMiddleware() HandlerMiddleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, job queue.Job) error {
+			start := time.Now()
+			err := next(ctx, job)
+			outcome := "success"
+			if err != nil {
+				outcome = "failure"
+			}
+			obs.JobHandlerDuration.WithLabelValues(job.Priority, outcome).Observe(time.Since(start).Seconds())
+			return err
+		}
+	}
+}
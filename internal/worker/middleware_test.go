@@ -0,0 +1,81 @@
+//go:build worker_tests
+// +build worker_tests
+
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+)
+
+func TestUseComposesFirstRegisteredOutermost(t *testing.T) {
+	w, _, _, cleanup := setupWorkerTest(t)
+	defer cleanup()
+
+	var calls []string
+	trace := func(name string) HandlerMiddleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, job queue.Job) error {
+				calls = append(calls, name+">")
+				err := next(ctx, job)
+				calls = append(calls, "<"+name)
+				return err
+			}
+		}
+	}
+	w.handler = func(ctx context.Context, job queue.Job) error {
+		calls = append(calls, "handler")
+		return nil
+	}
+	w.Use(trace("a"), trace("b"))
+
+	if err := w.chain()(context.Background(), queue.NewJob("id1", "/tmp/ok.txt", 0, "low", "", "")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"a>", "b>", "handler", "<b", "<a"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Fatalf("call order = %v, want %v", calls, want)
+	}
+}
+
+func TestRecoverMiddlewareDeadLettersWithoutRetry(t *testing.T) {
+	w, cfg, rdb, cleanup := setupWorkerTest(t)
+	defer cleanup()
+	cfg.Worker.MaxRetries = 5
+	w.handler = func(ctx context.Context, job queue.Job) error {
+		panic("boom")
+	}
+	w.Use(RecoverMiddleware(nil), LatencyMiddleware())
+
+	workerID := "w1"
+	procList := fmt.Sprintf(cfg.Worker.ProcessingListPattern, workerID)
+	hbKey := fmt.Sprintf(cfg.Worker.HeartbeatKeyPattern, workerID)
+	payload, _ := queue.NewJob("id1", "/tmp/ok.txt", 0, "low", "", "").Marshal()
+	ctx := context.Background()
+	_ = rdb.LPush(ctx, procList, payload).Err()
+
+	if w.processJob(ctx, workerID, cfg.Worker.Queues["low"], procList, hbKey, payload) {
+		t.Fatal("expected panic to fail the job")
+	}
+	if n, _ := rdb.LLen(ctx, cfg.Worker.Queues["low"]).Result(); n != 0 {
+		t.Fatalf("expected no retry, got %d requeued", n)
+	}
+	if n, _ := rdb.LLen(ctx, cfg.Worker.DeadLetterList).Result(); n != 1 {
+		t.Fatalf("expected DLQ 1, got %d", n)
+	}
+	if n, _ := rdb.LLen(ctx, procList).Result(); n != 0 {
+		t.Fatalf("expected processing list drained, got %d", n)
+	}
+
+	err := RecoverMiddleware(nil)(w.handler)(ctx, queue.Job{})
+	var perr *PanicError
+	if !errors.As(err, &perr) || perr.Value != "boom" || len(perr.Stack) == 0 {
+		t.Fatalf("expected PanicError carrying value and stack, got %#v", err)
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/obs"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+)
+
+// pauseCache holds the paused flags of every queue for PauseCacheTTL, so
+// polling a queue does not cost an extra Redis round trip each time.
+type pauseCache struct {
+	mu      sync.Mutex
+	paused  map[string]bool
+	checked time.Time
+}
+
+// queuePaused reports whether key is paused in worker.paused_queues or an
+// operator paused it with admin.PauseQueue. If the flags cannot be read,
+// the last known state is kept.
+func (w *Worker) queuePaused(ctx context.Context, key string) bool {
+	if w.settings().paused[key] {
+		return true
+	}
+	pc := &w.paused
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	if pc.paused != nil && time.Since(pc.checked) < w.cfg.Worker.PauseCacheTTL {
+		return pc.paused[key]
+	}
+	pc.checked = time.Now()
+
+	keys := make([]string, 0, len(w.cfg.Worker.Queues))
+	flags := make([]string, 0, len(w.cfg.Worker.Queues))
+	for _, q := range w.cfg.Worker.Queues {
+		keys = append(keys, q)
+		flags = append(flags, w.cfg.DerivedKey(queue.PausedKey, q))
+	}
+	vals, err := w.rdb.MGet(ctx, flags...).Result()
+	if err != nil {
+		if ctx.Err() == nil {
+			w.log.Warn("paused queue check failed", obs.Err(err))
+		}
+		return pc.paused[key]
+	}
+	paused := make(map[string]bool, len(keys))
+	for i, v := range vals {
+		if v != nil {
+			paused[keys[i]] = true
+		}
+	}
+	pc.paused = paused
+	return paused[key]
+}
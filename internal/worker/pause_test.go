@@ -0,0 +1,67 @@
+//go:build worker_tests
+// +build worker_tests
+
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+)
+
+func TestWorkerSkipsPausedQueues(t *testing.T) {
+	w, cfg, rdb, cleanup := setupWorkerTest(t)
+	defer cleanup()
+	cfg.Worker.Count = 1
+	cfg.Worker.BRPopLPushTimeout = 10 * time.Millisecond
+	cfg.Worker.PauseCacheTTL = 0
+	ctx := context.Background()
+
+	high, low := cfg.Worker.Queues["high"], cfg.Worker.Queues["low"]
+	if err := rdb.Set(ctx, queue.PausedKey(high), "1", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{high, low} {
+		payload, _ := queue.NewJob("id-"+key, "/tmp/ok.txt", 1, "", "", "").Marshal()
+		if err := rdb.LPush(ctx, key, payload).Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run := func(until func() bool) {
+		runCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+		defer cancel()
+		done := make(chan error, 1)
+		go func() { done <- w.Run(runCtx) }()
+		for runCtx.Err() == nil && !until() {
+			time.Sleep(5 * time.Millisecond)
+		}
+		cancel()
+		<-done
+	}
+
+	run(func() bool {
+		n, _ := rdb.LLen(ctx, cfg.Worker.CompletedList).Result()
+		return n == 1
+	})
+	if n, _ := rdb.LLen(ctx, high).Result(); n != 1 {
+		t.Fatalf("expected the paused queue to keep its job, got %d", n)
+	}
+	if n, _ := rdb.LLen(ctx, low).Result(); n != 0 {
+		t.Fatalf("expected the unpaused queue to be drained, got %d", n)
+	}
+
+	if err := rdb.Del(ctx, queue.PausedKey(high)).Err(); err != nil {
+		t.Fatal(err)
+	}
+	run(func() bool {
+		n, _ := rdb.LLen(ctx, cfg.Worker.CompletedList).Result()
+		return n == 2
+	})
+	if n, _ := rdb.LLen(ctx, cfg.Worker.CompletedList).Result(); n != 2 {
+		t.Fatalf("expected both jobs completed after resume, got %d", n)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+	"math"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/obs"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/redis/go-redis/v9"
+)
+
+// tokenBucketScript takes (ARGV[3] = 1) or returns (ARGV[3] = -1) a token
+// from the bucket hash KEYS[1], refilled at ARGV[1] tokens per second up to
+// ARGV[2]. It runs on the Redis clock so workers on different hosts share
+// one view of time, and returns 1 if the token was taken (returns always
+// succeed) or 0 if the bucket is empty. An idle bucket expires once it
+// would be full again, which is the same as it not existing.
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local delta = tonumber(ARGV[3])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+  tokens, ts = burst, now
+end
+if now > ts then
+  tokens = math.min(burst, tokens + (now - ts) * rate / 1000)
+  ts = now
+end
+
+local ok = 1
+if delta > 0 and tokens < delta then
+  ok = 0
+else
+  tokens = math.min(burst, tokens - delta)
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(ts))
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate * 1000) + 1000)
+return ok
+`)
+
+// rateBurst is how many tokens a bucket holds: one second's worth, and at
+// least one so rates below 1/s still let a job through now and then.
+func rateBurst(rate float64) float64 { return math.Max(1, rate) }
+
+// takeToken reports whether a fetch from key, polled for priority p, may
+// go ahead under its rate limit (see queueRate). Queues without one always
+// may. A throttled fetch is counted in queue_throttled_total; if the bucket
+// cannot be read the fetch is allowed, since the dequeue would fail too.
+func (w *Worker) takeToken(ctx context.Context, p, key string) bool {
+	rate, burst := w.queueRate(ctx, p, key)
+	if rate <= 0 {
+		return true
+	}
+	ok, err := tokenBucketScript.Run(ctx, w.client(key), []string{w.cfg.DerivedKey(queue.RateLimitKey, key)}, rate, burst, 1).Int()
+	if err != nil {
+		if ctx.Err() == nil {
+			w.log.Warn("queue rate limit check failed", obs.String("queue", key), obs.Err(err))
+		}
+		return true
+	}
+	if ok == 0 {
+		obs.QueueThrottled.WithLabelValues(key).Inc()
+		return false
+	}
+	return true
+}
+
+// returnToken gives back the token of a fetch that found the queue empty,
+// so polling an idle queue does not eat into its rate.
+func (w *Worker) returnToken(ctx context.Context, p, key string) {
+	rate, burst := w.queueRate(ctx, p, key)
+	if rate <= 0 || ctx.Err() != nil {
+		return
+	}
+	_ = tokenBucketScript.Run(ctx, w.client(key), []string{w.cfg.DerivedKey(queue.RateLimitKey, key)}, rate, burst, -1).Err()
+}
@@ -0,0 +1,128 @@
+//go:build worker_tests
+// +build worker_tests
+
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+func setupRateLimitTest(t *testing.T) (*Worker, *config.Config, *redis.Client, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	mr.SetTime(time.Unix(1700000000, 0))
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	cfg, _ := config.Load("nonexistent.yaml")
+	cfg.Redis.Addr = mr.Addr()
+	cfg.Worker.QueueRateLimits = map[string]float64{"high": 10}
+	return New(cfg, rdb, zap.NewNop()), cfg, rdb, mr
+}
+
+func TestTokenBucketUnderConcurrency(t *testing.T) {
+	w, cfg, rdb, mr := setupRateLimitTest(t)
+	ctx := context.Background()
+	key := cfg.Worker.Queues["high"]
+
+	take := func(workers, each int) int64 {
+		var allowed atomic.Int64
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < each; j++ {
+					if w.takeToken(ctx, "high", key) {
+						allowed.Add(1)
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		return allowed.Load()
+	}
+
+	// the clock is frozen, so only the initial burst of 10 gets through
+	if n := take(20, 5); n != 10 {
+		t.Fatalf("expected the burst of 10 fetches, got %d", n)
+	}
+	// 250ms refills 2.5 tokens; the half token stays in the bucket
+	mr.SetTime(time.Unix(1700000000, 250*int64(time.Millisecond)))
+	if n := take(20, 5); n != 2 {
+		t.Fatalf("expected 2 fetches after 250ms, got %d", n)
+	}
+	mr.SetTime(time.Unix(1700000000, 300*int64(time.Millisecond)))
+	if n := take(20, 5); n != 1 {
+		t.Fatalf("expected the half token to complete after 50ms more, got %d", n)
+	}
+	// a long idle period refills to the burst, not beyond
+	mr.SetTime(time.Unix(1700000060, 0))
+	if n := take(20, 5); n != 10 {
+		t.Fatalf("expected a full bucket of 10 after idling, got %d", n)
+	}
+
+	// returned tokens can be taken again, up to the burst
+	for i := 0; i < 3; i++ {
+		w.returnToken(ctx, "high", key)
+	}
+	if n := take(4, 5); n != 3 {
+		t.Fatalf("expected 3 returned tokens, got %d", n)
+	}
+	if ttl := mr.TTL(queue.RateLimitKey(key)); ttl <= 0 || ttl > 3*time.Second {
+		t.Fatalf("expected the bucket to expire once it would be full, got ttl %s", ttl)
+	}
+
+	// unlimited queues never touch Redis
+	if !w.takeToken(ctx, "low", cfg.Worker.Queues["low"]) {
+		t.Fatal("expected a queue without a limit to be allowed")
+	}
+	if n, _ := rdb.Exists(ctx, queue.RateLimitKey(cfg.Worker.Queues["low"])).Result(); n != 0 {
+		t.Fatal("expected no bucket for a queue without a limit")
+	}
+}
+
+func TestWorkerSkipsThrottledQueues(t *testing.T) {
+	w, cfg, rdb, _ := setupRateLimitTest(t)
+	cfg.Worker.Count = 2
+	cfg.Worker.BRPopLPushTimeout = 10 * time.Millisecond
+	cfg.Worker.QueueRateLimits["high"] = 0.1 // one job, then nothing while the clock is frozen
+	ctx := context.Background()
+
+	high, low := cfg.Worker.Queues["high"], cfg.Worker.Queues["low"]
+	for i, key := range []string{high, high, high, low, low} {
+		payload, _ := queue.NewJob(string(rune('a'+i)), "/tmp/ok.txt", 1, "", "", "").Marshal()
+		if err := rdb.LPush(ctx, key, payload).Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- w.Run(runCtx) }()
+	for runCtx.Err() == nil {
+		if n, _ := rdb.LLen(ctx, cfg.Worker.CompletedList).Result(); n == 3 {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if n, _ := rdb.LLen(ctx, low).Result(); n != 0 {
+		t.Fatalf("expected workers to drain the unthrottled queue, %d left", n)
+	}
+	if n, _ := rdb.LLen(ctx, high).Result(); n != 2 {
+		t.Fatalf("expected the throttled queue to keep 2 jobs, got %d", n)
+	}
+}
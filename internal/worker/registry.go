@@ -0,0 +1,47 @@
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+)
+
+// errUnknownJobType fails a job whose type has no registered handler when
+// there is no fallback. Like a panic, it skips retries.
+var errUnknownJobType = errors.New("unknown_job_type")
+
+// Register routes jobs whose type field is jobType to h. Once any type is
+// registered, jobs of other types go to the fallback handler, or to the dead
+// letter queue with reason unknown_job_type when there is none. Registering
+// a type again replaces its handler. Register must be called before Run.
+func (w *Worker) Register(jobType string, h Handler) {
+	if w.handlers == nil {
+		w.handlers = map[string]Handler{}
+	}
+	w.handlers[jobType] = h
+}
+
+// SetFallback sets the handler for jobs whose type is not registered,
+// including jobs without a type. It must be called before Run.
+func (w *Worker) SetFallback(h Handler) {
+	w.fallback = h
+}
+
+// dispatch is the base of the middleware chain: it picks the handler for
+// job by type. With nothing registered every job goes to the default
+// handler.
+func (w *Worker) dispatch(ctx context.Context, job queue.Job) error {
+	if h, ok := w.handlers[job.Type]; ok {
+		return h(ctx, job)
+	}
+	if w.fallback != nil {
+		return w.fallback(ctx, job)
+	}
+	if len(w.handlers) == 0 {
+		return w.handler(ctx, job)
+	}
+	return fmt.Errorf("%w %q", errUnknownJobType, job.Type)
+}
@@ -0,0 +1,113 @@
+//go:build worker_tests
+// +build worker_tests
+
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+)
+
+func TestProcessJobDispatchesByType(t *testing.T) {
+	w, cfg, rdb, cleanup := setupWorkerTest(t)
+	defer cleanup()
+	ctx := context.Background()
+	procList := fmt.Sprintf(cfg.Worker.ProcessingListPattern, "w1")
+	hbKey := fmt.Sprintf(cfg.Worker.HeartbeatKeyPattern, "w1")
+
+	var ran []string
+	w.Register("resize", func(ctx context.Context, job queue.Job) error {
+		ran = append(ran, "resize:"+job.ID)
+		return nil
+	})
+	w.Register("email", func(ctx context.Context, job queue.Job) error {
+		ran = append(ran, "email:"+job.ID)
+		return nil
+	})
+	run := func(id, typ string) bool {
+		job := queue.NewJob(id, "/tmp/ok.txt", 10, "low", "", "")
+		job.Type = typ
+		payload, _ := job.Marshal()
+		return w.processJob(ctx, "w1", cfg.Worker.Queues["low"], procList, hbKey, payload)
+	}
+
+	if !run("a", "resize") || !run("b", "email") {
+		t.Fatal("registered types should succeed")
+	}
+	if len(ran) != 2 || ran[0] != "resize:a" || ran[1] != "email:b" {
+		t.Fatalf("unexpected dispatch: %v", ran)
+	}
+
+	// no fallback: unknown types are dead-lettered without a retry
+	if run("c", "fax") {
+		t.Fatal("unknown type should fail")
+	}
+	if n := rdb.LLen(ctx, cfg.Worker.DeadLetterList).Val(); n != 1 {
+		t.Fatalf("expected the unknown job in the DLQ, got %d", n)
+	}
+	if n := rdb.LLen(ctx, cfg.Worker.Queues["low"]).Val(); n != 0 {
+		t.Fatalf("unknown types must not be retried, low has %d", n)
+	}
+
+	w.SetFallback(func(ctx context.Context, job queue.Job) error {
+		ran = append(ran, "fallback:"+job.ID)
+		return nil
+	})
+	if !run("d", "fax") || !run("e", "") || ran[len(ran)-2] != "fallback:d" || ran[len(ran)-1] != "fallback:e" {
+		t.Fatalf("fallback should take unregistered types: %v", ran)
+	}
+}
+
+func TestDispatchWithoutRegistrationsUsesDefaultHandler(t *testing.T) {
+	w, _, _, cleanup := setupWorkerTest(t)
+	defer cleanup()
+	called := false
+	w.handler = func(ctx context.Context, job queue.Job) error {
+		called = true
+		return nil
+	}
+	if err := w.dispatch(context.Background(), queue.Job{Type: "anything"}); err != nil || !called {
+		t.Fatalf("expected the default handler, err=%v called=%t", err, called)
+	}
+}
+
+func TestClassifyFailure(t *testing.T) {
+	w, cfg, _, cleanup := setupWorkerTest(t)
+	defer cleanup()
+	cases := []struct {
+		err       error
+		reason    string
+		class     ErrorClass
+		retryable bool
+	}{
+		{errors.New("boom"), "boom", ErrorUnclassified, true},
+		{&PanicError{Value: "x"}, "panic", ErrorPermanent, false},
+		{fmt.Errorf("%w %q", errUnknownJobType, "fax"), "unknown_job_type", ErrorPermanent, false},
+		{fmt.Errorf("fetch: %w", Retryable(errors.New("timeout"))), "retryable: fetch: timeout", ErrorRetryable, true},
+		{Permanent(errors.New("bad input")), "permanent: bad input", ErrorPermanent, false},
+		{Retryable(Permanent(errors.New("both"))), "permanent: both", ErrorPermanent, false},
+	}
+	for _, c := range cases {
+		reason, class, retryable := w.classifyFailure(c.err)
+		if reason != c.reason || class != c.class || retryable != c.retryable {
+			t.Fatalf("%v: got %q/%s/%t, want %q/%s/%t", c.err, reason, class, retryable, c.reason, c.class, c.retryable)
+		}
+	}
+
+	cfg.Worker.UnclassifiedErrors = config.UnclassifiedDeadLetter
+	if _, _, retryable := w.classifyFailure(errors.New("boom")); retryable {
+		t.Fatal("expected unclassified errors to be dead-lettered with unclassified_errors: dead_letter")
+	}
+	if _, _, retryable := w.classifyFailure(Retryable(errors.New("boom"))); !retryable {
+		t.Fatal("expected retryable errors to be retried regardless")
+	}
+	if Retryable(nil) != nil || Permanent(nil) != nil {
+		t.Fatal("expected nil errors to stay nil")
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/obs"
+)
+
+// reloadableKeys are the settings Reload applies to a running worker.
+// Changing any other key takes a restart.
+var reloadableKeys = map[string]bool{
+	"worker.count":             true,
+	"worker.queue_rate_limits": true,
+	"worker.paused_queues":     true,
+}
+
+// liveSettings are the settings Reload may replace while jobs run. They are
+// swapped whole, so a poll sees either the old or the new set.
+type liveSettings struct {
+	count        int
+	rateLimits   map[string]float64 // by priority
+	pausedQueues []string           // priorities, as configured
+	paused       map[string]bool    // by queue key
+}
+
+func newLiveSettings(cfg *config.Config) *liveSettings {
+	ls := &liveSettings{
+		count:        cfg.Worker.Count,
+		rateLimits:   cfg.Worker.QueueRateLimits,
+		pausedQueues: cfg.Worker.PausedQueues,
+		paused:       make(map[string]bool, len(cfg.Worker.PausedQueues)),
+	}
+	for _, p := range cfg.Worker.PausedQueues {
+		if key := cfg.Worker.Queues[p]; key != "" {
+			ls.paused[key] = true
+		}
+	}
+	return ls
+}
+
+// settings returns the live settings: worker.* as of Run, or as last
+// reloaded.
+func (w *Worker) settings() *liveSettings {
+	if ls := w.live.Load(); ls != nil {
+		return ls
+	}
+	return newLiveSettings(w.cfg)
+}
+
+// Reload applies the reloadable settings of cfg, a freshly loaded and
+// validated config: worker.count resizes the pool (goroutines retired by a
+// shrink finish their job first), and worker.queue_rate_limits and
+// worker.paused_queues take effect on the next poll. Changes to any other
+// key, and to worker.count while autoscale sizes the pool, are logged and
+// ignored. It returns the keys applied and the keys refused.
+func (w *Worker) Reload(cfg *config.Config) (applied, rejected []string) {
+	old := w.settings()
+	running := *w.cfg
+	running.Worker.Count = old.count
+	running.Worker.QueueRateLimits = old.rateLimits
+	running.Worker.PausedQueues = old.pausedQueues
+
+	for _, key := range config.Diff(&running, cfg) {
+		if !reloadableKeys[key] || key == "worker.count" && w.cfg.Worker.Autoscale.Enabled {
+			rejected = append(rejected, key)
+			continue
+		}
+		applied = append(applied, key)
+	}
+	if len(rejected) > 0 {
+		w.log.Warn("config reload: changes need a restart and were not applied",
+			obs.String("keys", strings.Join(rejected, ", ")))
+	}
+	if len(applied) == 0 {
+		w.log.Info("config reload: no reloadable changes")
+		return applied, rejected
+	}
+
+	// the new settings, resolved against the running queues
+	next := *old
+	for _, key := range applied {
+		switch key {
+		case "worker.count":
+			next.count = cfg.Worker.Count
+			w.log.Info("config reload: worker.count changed",
+				obs.Int("from", old.count), obs.Int("to", next.count))
+		case "worker.queue_rate_limits":
+			next.rateLimits = cfg.Worker.QueueRateLimits
+			w.log.Info("config reload: worker.queue_rate_limits changed",
+				obs.String("from", fmt.Sprint(old.rateLimits)), obs.String("to", fmt.Sprint(next.rateLimits)))
+		case "worker.paused_queues":
+			reloaded := *w.cfg
+			reloaded.Worker.PausedQueues = cfg.Worker.PausedQueues
+			fresh := newLiveSettings(&reloaded)
+			next.pausedQueues, next.paused = fresh.pausedQueues, fresh.paused
+			w.log.Info("config reload: worker.paused_queues changed",
+				obs.String("from", strings.Join(old.pausedQueues, ",")), obs.String("to", strings.Join(next.pausedQueues, ",")))
+		}
+	}
+	w.live.Store(&next)
+
+	if pool := w.pool.Load(); pool != nil && next.count != old.count {
+		pool.resize(next.count)
+		w.concurrency.Store(int64(next.count))
+		obs.WorkerConcurrency.Set(float64(next.count))
+	}
+	return applied, rejected
+}
+
+// WatchReload calls load and Reload for every signal received on signals
+// until ctx is done; the worker command feeds it SIGHUP. When load fails,
+// validation included, the running settings stay as they are.
+func (w *Worker) WatchReload(ctx context.Context, signals <-chan os.Signal, load func() (*config.Config, error)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case sig := <-signals:
+			w.log.Info("reloading config", obs.String("signal", sig.String()))
+			cfg, err := load()
+			if err != nil {
+				w.log.Warn("config reload failed; keeping current settings", obs.Err(err))
+				continue
+			}
+			w.Reload(cfg)
+		}
+	}
+}
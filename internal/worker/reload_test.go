@@ -0,0 +1,95 @@
+//go:build worker_tests
+// +build worker_tests
+
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+)
+
+func TestWatchReloadAppliesReloadableSettings(t *testing.T) {
+	w, cfg, _, cleanup := setupWorkerTest(t)
+	defer cleanup()
+	cfg.Worker.Count = 1
+	cfg.Worker.BRPopLPushTimeout = 10 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx) }()
+	defer func() { cancel(); <-done }()
+	waitFor(t, func() bool { return w.pool.Load() != nil })
+
+	reloaded := *cfg
+	reloaded.Worker.Count = 3
+	reloaded.Worker.PausedQueues = []string{"high"}
+	reloaded.Worker.QueueRateLimits = map[string]float64{"low": 5}
+	reloaded.Redis.Addr = "elsewhere:6379"
+	loads := []func() (*config.Config, error){
+		func() (*config.Config, error) { return nil, errors.New("bad yaml") },
+		func() (*config.Config, error) { return &reloaded, nil },
+	}
+	signals := make(chan os.Signal)
+	go w.WatchReload(ctx, signals, func() (*config.Config, error) {
+		load := loads[0]
+		loads = loads[1:]
+		return load()
+	})
+
+	signals <- syscall.SIGHUP
+	if w.settings().count != 1 {
+		t.Fatal("a failed load must keep the running settings")
+	}
+	signals <- syscall.SIGHUP
+	waitFor(t, func() bool { return w.pool.Load().current() == 3 && w.concurrency.Load() == 3 })
+
+	if !w.queuePaused(ctx, cfg.Worker.Queues["high"]) || w.queuePaused(ctx, cfg.Worker.Queues["low"]) {
+		t.Fatal("expected only the high queue paused")
+	}
+	if rate, _ := w.queueRate(ctx, "low", cfg.Worker.Queues["low"]); rate != 5 {
+		t.Fatalf("expected the reloaded rate limit, got %g", rate)
+	}
+
+	// the running worker kept its Redis address; reloading the same file
+	// again only reports it
+	applied, rejected := w.Reload(&reloaded)
+	if len(applied) != 0 || !reflect.DeepEqual(rejected, []string{"redis.addr"}) {
+		t.Fatalf("expected only redis.addr refused, got applied=%v rejected=%v", applied, rejected)
+	}
+	if w.cfg.Redis.Addr == "elsewhere:6379" {
+		t.Fatal("non-reloadable settings must not change")
+	}
+}
+
+func TestReloadLeavesCountToAutoscale(t *testing.T) {
+	w, cfg, _, cleanup := setupWorkerTest(t)
+	defer cleanup()
+	cfg.Worker.Autoscale.Enabled = true
+
+	reloaded := *cfg
+	reloaded.Worker.Count = cfg.Worker.Count + 4
+	applied, rejected := w.Reload(&reloaded)
+	if len(applied) != 0 || !reflect.DeepEqual(rejected, []string{"worker.count"}) {
+		t.Fatalf("expected worker.count refused under autoscale, got applied=%v rejected=%v", applied, rejected)
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for condition")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
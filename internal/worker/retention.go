@@ -0,0 +1,38 @@
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/admin"
+	"github.com/flyingrobots/go-redis-work-queue/internal/obs"
+	"go.uber.org/zap"
+)
+
+// trimCompleted applies worker.completed_retention to the completed list
+// every interval until ctx is done. Every worker process runs it; trims
+// from several at once only remove entries once.
+func (w *Worker) trimCompleted(ctx context.Context) {
+	cr := w.cfg.Worker.CompletedRetention
+	ticker := time.NewTicker(cr.Interval)
+	defer ticker.Stop()
+	list := w.cfg.Worker.CompletedList
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			res, err := admin.TrimCompleted(ctx, w.cfg, w.client(list), cr.KeepLast, cr.MaxAge)
+			if err != nil {
+				if ctx.Err() == nil {
+					w.log.Warn("completed list trim failed", obs.String("list", list), obs.Err(err))
+				}
+				continue
+			}
+			if res.ByCount+res.ByAge > 0 {
+				w.log.Debug("completed list trimmed", obs.String("list", list), zap.Int64("by_count", res.ByCount), zap.Int64("by_age", res.ByAge))
+			}
+		}
+	}
+}
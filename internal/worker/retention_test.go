@@ -0,0 +1,33 @@
+//go:build worker_tests
+// +build worker_tests
+
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+)
+
+func TestTrimCompletedKeepsNewest(t *testing.T) {
+	w, cfg, rdb, cleanup := setupWorkerTest(t)
+	defer cleanup()
+	cfg.Worker.CompletedRetention = config.CompletedRetention{KeepLast: 2, Interval: 10 * time.Millisecond}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	rdb.LPush(ctx, cfg.Worker.CompletedList, "a", "b", "c", "d", "e")
+
+	go w.trimCompleted(ctx)
+	for ctx.Err() == nil {
+		if n, _ := rdb.LLen(ctx, cfg.Worker.CompletedList).Result(); n == 2 {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if items, _ := rdb.LRange(context.Background(), cfg.Worker.CompletedList, 0, -1).Result(); len(items) != 2 || items[0] != "e" || items[1] != "d" {
+		t.Fatalf("expected the two newest entries kept, got %v", items)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/redisclient"
+	"github.com/redis/go-redis/v9"
+)
+
+// SetRouter makes the worker consume each queue from the cluster
+// cluster_routes assigns it. A job's processing list, heartbeat, retries
+// and dedup markers stay on its queue's cluster; completed and dead letter
+// lists are routed by their own keys. Pause flags and stream mode stay on
+// the worker's own client. It must be called before Run.
+func (w *Worker) SetRouter(r *redisclient.Router) {
+	w.router = r
+	w.children.SetRouter(r)
+}
+
+// client returns the client for key.
+func (w *Worker) client(key string) *redis.Client {
+	if w.router != nil {
+		return w.router.For(key)
+	}
+	return w.rdb
+}
+
+// queueLengths returns the LLEN of each key, with one pipeline per cluster
+// involved. Empty keys have length 0.
+func (w *Worker) queueLengths(ctx context.Context, keys []string) ([]int64, error) {
+	cmds := make([]*redis.IntCmd, len(keys))
+	pipes := map[*redis.Client]redis.Pipeliner{}
+	var order []redis.Pipeliner
+	for i, key := range keys {
+		if key == "" {
+			continue
+		}
+		rc := w.client(key)
+		pipe, ok := pipes[rc]
+		if !ok {
+			pipe = rc.Pipeline()
+			pipes[rc] = pipe
+			order = append(order, pipe)
+		}
+		cmds[i] = pipe.LLen(ctx, key)
+	}
+	for _, pipe := range order {
+		if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+			return nil, err
+		}
+	}
+	lengths := make([]int64, len(keys))
+	for i, cmd := range cmds {
+		if cmd != nil {
+			lengths[i] = cmd.Val()
+		}
+	}
+	return lengths, nil
+}
@@ -0,0 +1,247 @@
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	eventhooks "github.com/flyingrobots/go-redis-work-queue/internal/event-hooks"
+	"github.com/flyingrobots/go-redis-work-queue/internal/obs"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Silence alert kinds, as sent in SilenceAlert.Alert.
+const (
+	AlertQueueSilent    = "queue_silent"
+	AlertQueueRecovered = "queue_recovered"
+)
+
+// SilenceAlert is the body POSTed to worker.silence_alert.webhook_urls when
+// a queue goes silent and when it completes a job again.
+type SilenceAlert struct {
+	Alert          string    `json:"alert"`
+	Queue          string    `json:"queue"`
+	Priority       string    `json:"priority"`
+	LastCompletion time.Time `json:"last_completion"`
+	// SilentSeconds is how long the queue had gone without a completion
+	// and ThresholdSeconds the silence its baseline allowed; both are only
+	// set on queue_silent.
+	SilentSeconds    float64   `json:"silent_seconds,omitempty"`
+	ThresholdSeconds float64   `json:"threshold_seconds,omitempty"`
+	Worker           string    `json:"worker"`
+	Timestamp        time.Time `json:"timestamp"`
+}
+
+var silenceHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// silenceTracker counts this process's completions per queue between
+// silence checks.
+type silenceTracker struct {
+	mu    sync.Mutex
+	count map[string]int64
+	last  map[string]time.Time
+}
+
+func newSilenceTracker() *silenceTracker {
+	return &silenceTracker{count: map[string]int64{}, last: map[string]time.Time{}}
+}
+
+// record notes a job completed from key. It is a no-op when silence
+// alerts are off.
+func (t *silenceTracker) record(key string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	t.count[key]++
+	t.last[key] = time.Now()
+	t.mu.Unlock()
+}
+
+// take returns and resets the completions recorded for key.
+func (t *silenceTracker) take(key string) (int64, time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	n, last := t.count[key], t.last[key]
+	delete(t.count, key)
+	delete(t.last, key)
+	return n, last
+}
+
+// putBack returns completions take handed out that could not be stored.
+func (t *silenceTracker) putBack(key string, n int64, last time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.count[key] += n
+	if last.After(t.last[key]) {
+		t.last[key] = last
+	}
+}
+
+// cadenceScript folds ARGV[2] new completions, the latest at ARGV[3] (ms,
+// 0 for none), into the queue's cadence hash KEYS[1] and decides whether
+// the queue has gone silent. The hash keeps an exponentially decayed
+// completion count (score) with time constant ARGV[4] ms, so score divided
+// by the time it covers is the queue's recent completion rate. A queue is
+// silent once it has gone ARGV[5] times its usual gap between completions,
+// and at least ARGV[6] ms, without one; queues with fewer than ARGV[5]
+// completions to go on, or whose threshold would exceed the window, are
+// not expected to be active. ARGV[7] is "1" while the queue is paused,
+// which holds off new alerts.
+//
+// It returns {action, last completion ms, silence ms, threshold ms}, with
+// action 1 for a new silence and 2 when a silent queue completes a job
+// again. The alerted field, holding the last completion at the time of the
+// alert, makes each silence alert once however many workers check.
+var cadenceScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local tau = tonumber(ARGV[4])
+local factor = tonumber(ARGV[5])
+local v = redis.call('HMGET', KEYS[1], 'score', 'updated', 'since', 'last', 'alerted')
+local score = tonumber(v[1]) or 0
+local updated = tonumber(v[2]) or now
+local since = tonumber(v[3]) or now
+local last = tonumber(v[4]) or 0
+if now > updated then
+  score = score * math.exp(-(now - updated) / tau)
+  updated = now
+end
+score = score + tonumber(ARGV[2])
+last = math.max(last, tonumber(ARGV[3]))
+redis.call('HSET', KEYS[1], 'score', tostring(score), 'updated', tostring(updated), 'since', tostring(since), 'last', tostring(last))
+redis.call('PEXPIRE', KEYS[1], math.floor(4 * tau))
+if last == 0 then
+  return {0, 0, 0, 0}
+end
+local alerted = tonumber(v[5])
+if alerted then
+  if last > alerted then
+    redis.call('HDEL', KEYS[1], 'alerted')
+    return {2, last, 0, 0}
+  end
+  return {0, last, 0, 0}
+end
+if ARGV[7] == '1' or score < factor then
+  return {0, last, 0, 0}
+end
+local rate = score / (tau * (1 - math.exp(-math.max(now - since, 1) / tau)))
+local gap = factor / rate
+if gap > tau then
+  return {0, last, 0, 0}
+end
+local threshold = math.max(gap, tonumber(ARGV[6]))
+local silent = now - last
+if silent > threshold then
+  redis.call('HSET', KEYS[1], 'alerted', tostring(last))
+  return {1, last, math.floor(silent), math.floor(threshold)}
+end
+return {0, last, 0, 0}
+`)
+
+// watchSilence runs checkSilence every worker.silence_alert.check_interval
+// until ctx is done. Every worker process runs it; the cadence hash is
+// shared, so a silent queue alerts once.
+func (w *Worker) watchSilence(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.Worker.SilenceAlert.CheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.checkSilence(ctx, time.Now())
+		}
+	}
+}
+
+// checkSilence stores the completions since the last check in each
+// queue's cadence hash and alerts on queues that went silent or recovered.
+func (w *Worker) checkSilence(ctx context.Context, now time.Time) {
+	sa := w.cfg.Worker.SilenceAlert
+	seen := map[string]bool{}
+	for _, p := range w.cfg.Worker.Priorities {
+		key := w.cfg.Worker.Queues[p]
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		n, latest := w.silence.take(key)
+		var latestMs int64
+		if n > 0 {
+			latestMs = latest.UnixMilli()
+		}
+		paused := "0"
+		if w.queuePaused(ctx, key) {
+			paused = "1"
+		}
+		res, err := cadenceScript.Run(ctx, w.client(key), []string{w.cfg.DerivedKey(queue.CadenceKey, key)},
+			now.UnixMilli(), n, latestMs, sa.BaselineWindow.Milliseconds(),
+			strconv.FormatFloat(sa.BaselineFactor, 'f', -1, 64), sa.MinSilence.Milliseconds(), paused).Int64Slice()
+		if err != nil {
+			w.silence.putBack(key, n, latest)
+			if ctx.Err() == nil {
+				w.log.Warn("queue silence check failed", obs.String("queue", key), obs.Err(err))
+			}
+			continue
+		}
+
+		alert := SilenceAlert{Queue: key, Priority: p, LastCompletion: time.UnixMilli(res[1]).UTC(), Worker: w.baseID, Timestamp: now.UTC()}
+		switch res[0] {
+		case 1:
+			alert.Alert = AlertQueueSilent
+			alert.SilentSeconds = (time.Duration(res[2]) * time.Millisecond).Seconds()
+			alert.ThresholdSeconds = (time.Duration(res[3]) * time.Millisecond).Seconds()
+			obs.QueueSilent.WithLabelValues(key).Set(1)
+			w.log.Warn("queue went silent", obs.String("queue", key), obs.String("last_completion", alert.LastCompletion.Format(time.RFC3339)),
+				zap.Float64("silent_seconds", alert.SilentSeconds), zap.Float64("threshold_seconds", alert.ThresholdSeconds))
+		case 2:
+			alert.Alert = AlertQueueRecovered
+			obs.QueueSilent.WithLabelValues(key).Set(0)
+			w.log.Info("queue completing jobs again", obs.String("queue", key))
+		default:
+			continue
+		}
+		w.sendSilenceAlert(ctx, alert)
+	}
+}
+
+// sendSilenceAlert POSTs alert to every configured webhook, signing it
+// when a secret is set. Failures are logged; alerts are not retried.
+func (w *Worker) sendSilenceAlert(ctx context.Context, alert SilenceAlert) {
+	sa := w.cfg.Worker.SilenceAlert
+	body, err := json.Marshal(alert)
+	if err != nil {
+		w.log.Error("encode silence alert failed", obs.Err(err))
+		return
+	}
+	for _, url := range sa.WebhookURLs {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			w.log.Warn("silence alert webhook failed", obs.String("url", url), obs.Err(err))
+			continue
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", "go-redis-work-queue/silence-alert")
+		if sa.WebhookSecret != "" {
+			req.Header.Set("X-Webhook-Signature", eventhooks.SignPayload(body, sa.WebhookSecret))
+		}
+		resp, err := silenceHTTPClient.Do(req)
+		if err != nil {
+			w.log.Warn("silence alert webhook failed", obs.String("url", url), obs.Err(err))
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			w.log.Warn("silence alert webhook failed", obs.String("url", url), obs.Err(fmt.Errorf("HTTP %d", resp.StatusCode)))
+		}
+	}
+}
@@ -0,0 +1,74 @@
+//go:build worker_tests
+// +build worker_tests
+
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCheckSilenceAlertsOnceAndRecovers(t *testing.T) {
+	w, cfg, _, cleanup := setupWorkerTest(t)
+	defer cleanup()
+
+	var mu sync.Mutex
+	var alerts []SilenceAlert
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mac := hmac.New(sha256.New, []byte("s3cret"))
+		mac.Write(body)
+		if r.Header.Get("X-Webhook-Signature") != "sha256="+hex.EncodeToString(mac.Sum(nil)) {
+			t.Errorf("bad signature %q", r.Header.Get("X-Webhook-Signature"))
+		}
+		var a SilenceAlert
+		if err := json.Unmarshal(body, &a); err != nil {
+			t.Errorf("bad alert body: %v", err)
+		}
+		mu.Lock()
+		alerts = append(alerts, a)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	cfg.Worker.SilenceAlert.Enabled = true
+	cfg.Worker.SilenceAlert.WebhookURLs = []string{srv.URL}
+	cfg.Worker.SilenceAlert.WebhookSecret = "s3cret"
+	cfg.Worker.SilenceAlert.MinSilence = time.Minute
+	cfg.Worker.SilenceAlert.BaselineFactor = 10
+	cfg.Worker.SilenceAlert.BaselineWindow = time.Hour
+	w.silence = newSilenceTracker()
+	busy, quiet := cfg.Worker.Queues["low"], cfg.Worker.Queues["high"]
+	ctx := context.Background()
+
+	t0 := time.Now()
+	w.silence.putBack(busy, 30, t0)
+	w.silence.putBack(quiet, 2, t0)
+	w.checkSilence(ctx, t0)
+	// ~25 decayed completions over ~9 minutes put the busy queue's
+	// threshold near 4 minutes; the quiet queue has too little history
+	w.checkSilence(ctx, t0.Add(10*time.Minute))
+	w.checkSilence(ctx, t0.Add(11*time.Minute))
+	if len(alerts) != 1 || alerts[0].Alert != AlertQueueSilent || alerts[0].Queue != busy {
+		t.Fatalf("expected one silence alert for %s, got %+v", busy, alerts)
+	}
+	if a := alerts[0]; a.SilentSeconds != 600 || a.ThresholdSeconds < 60 || a.ThresholdSeconds > 600 || !a.LastCompletion.Equal(time.UnixMilli(t0.UnixMilli())) {
+		t.Fatalf("unexpected silence alert %+v", a)
+	}
+
+	w.silence.putBack(busy, 1, t0.Add(12*time.Minute))
+	w.checkSilence(ctx, t0.Add(12*time.Minute))
+	if len(alerts) != 2 || alerts[1].Alert != AlertQueueRecovered || alerts[1].Queue != busy {
+		t.Fatalf("expected a recovery alert for %s, got %+v", busy, alerts)
+	}
+}
@@ -0,0 +1,412 @@
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/obs"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/redis/go-redis/v9"
+)
+
+// streamMessage is one stream entry delivered to this worker.
+type streamMessage struct {
+	stream  string
+	queue   string
+	id      string
+	payload string
+	// deliveries counts how often the group has handed the entry out,
+	// including this time.
+	deliveries int64
+}
+
+// ensureGroups creates the consumer group on every queue's stream, creating
+// empty streams as needed. A group that already exists is left alone so
+// restarts never rewind delivery.
+func (w *Worker) ensureGroups(ctx context.Context) error {
+	for _, key := range w.cfg.Worker.Queues {
+		if key == "" {
+			continue
+		}
+		err := w.rdb.XGroupCreateMkStream(ctx, queue.StreamKey(key), w.cfg.Worker.Stream.Group, "0").Err()
+		if err != nil && !strings.Contains(err.Error(), "BUSYGROUP") {
+			return fmt.Errorf("create group on %s: %w", queue.StreamKey(key), err)
+		}
+	}
+	return nil
+}
+
+// runStreamOne is runOne for stream mode. New entries are read with
+// XREADGROUP in priority order; every claim_interval the worker first looks
+// for entries left pending longer than claim_idle by a crashed or stuck
+// consumer and takes one over with XAUTOCLAIM. That replaces the reaper.
+func (w *Worker) runStreamOne(ctx context.Context, workerID string, stop <-chan struct{}) {
+	hbKey := fmt.Sprintf(w.cfg.Worker.HeartbeatKeyPattern, workerID)
+	cursors := map[string]string{}
+	var lastClaim time.Time
+
+	for ctx.Err() == nil && !stopped(stop) {
+		var msg *streamMessage
+		polled := 0
+		if time.Since(lastClaim) >= w.cfg.Worker.Stream.ClaimInterval {
+			lastClaim = time.Now()
+			msg = w.claimStalled(ctx, workerID, cursors)
+		}
+		for _, p := range w.cfg.Worker.Priorities {
+			if msg != nil {
+				break
+			}
+			key := w.cfg.Worker.Queues[p]
+			if key == "" {
+				continue
+			}
+			if qb := w.breakers[key]; qb != nil && !qb.cb.Ready() {
+				continue
+			}
+			if w.queuePaused(ctx, key) {
+				continue
+			}
+			if !w.takeToken(ctx, p, key) {
+				continue
+			}
+			polled++
+
+			stream := queue.StreamKey(key)
+			deqCtx, deqSpan := obs.StartDequeueSpan(ctx, stream)
+			res, err := w.rdb.XReadGroup(deqCtx, &redis.XReadGroupArgs{
+				Group:    w.cfg.Worker.Stream.Group,
+				Consumer: workerID,
+				Streams:  []string{stream, ">"},
+				Count:    1,
+				Block:    w.cfg.Worker.BRPopLPushTimeout,
+			}).Result()
+			if err == redis.Nil {
+				deqSpan.End()
+				w.returnToken(ctx, p, key)
+				continue
+			}
+			if err != nil {
+				obs.RecordError(deqCtx, err)
+				deqSpan.End()
+				if ctx.Err() != nil {
+					return
+				}
+				w.returnToken(ctx, p, key)
+				w.log.Warn("XREADGROUP error", obs.Err(err))
+				time.Sleep(50 * time.Millisecond)
+				continue
+			}
+			obs.SetSpanSuccess(deqCtx)
+			obs.AddEvent(deqCtx, "job_dequeued", obs.KeyValue("queue", key))
+			deqSpan.End()
+			obs.PriorityServed.WithLabelValues(p).Inc()
+			for _, s := range res {
+				for _, m := range s.Messages {
+					msg = &streamMessage{stream: stream, queue: key, id: m.ID, payload: streamPayload(m), deliveries: 1}
+				}
+			}
+		}
+		if msg == nil {
+			if polled == 0 {
+				// every queue is paused, throttled or has its breaker open; back off
+				time.Sleep(w.cfg.Worker.BreakerPause)
+			}
+			continue
+		}
+
+		_ = w.rdb.Set(ctx, hbKey, msg.payload, w.cfg.Worker.HeartbeatTTL).Err()
+
+		qb := w.breakers[msg.queue]
+		if qb != nil && !qb.cb.Allow() {
+			w.requeueRejectedStream(ctx, qb, msg, hbKey)
+			continue
+		}
+		obs.JobsConsumed.Inc()
+
+		start := time.Now()
+		ok := w.processStreamJob(ctx, workerID, hbKey, msg)
+		elapsed := time.Since(start)
+		obs.JobProcessingDuration.Observe(elapsed.Seconds())
+		w.observeLatency(elapsed)
+		if qb != nil {
+			w.record(qb, msg.queue, ok)
+		}
+	}
+}
+
+// claimStalled takes over one entry that has been pending longer than
+// claim_idle, scanning streams in priority order. Each stream keeps its own
+// XAUTOCLAIM cursor so repeated calls walk the whole pending entries list.
+// Entries already delivered more than max_retries+1 times are dead-lettered
+// without running them again, so a job that keeps crashing its worker
+// cannot loop forever.
+func (w *Worker) claimStalled(ctx context.Context, workerID string, cursors map[string]string) *streamMessage {
+	for _, p := range w.cfg.Worker.Priorities {
+		key := w.cfg.Worker.Queues[p]
+		if key == "" {
+			continue
+		}
+		if qb := w.breakers[key]; qb != nil && !qb.cb.Ready() {
+			continue
+		}
+		if w.queuePaused(ctx, key) {
+			continue
+		}
+		stream := queue.StreamKey(key)
+		start := cursors[stream]
+		if start == "" {
+			start = "0-0"
+		}
+		msgs, next, err := w.rdb.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   stream,
+			Group:    w.cfg.Worker.Stream.Group,
+			Consumer: workerID,
+			MinIdle:  w.cfg.Worker.Stream.ClaimIdle,
+			Start:    start,
+			Count:    1,
+		}).Result()
+		if err != nil {
+			if ctx.Err() == nil {
+				w.log.Warn("XAUTOCLAIM error", obs.String("stream", stream), obs.Err(err))
+			}
+			continue
+		}
+		cursors[stream] = next
+		if len(msgs) == 0 {
+			continue
+		}
+		m := msgs[0]
+		msg := &streamMessage{stream: stream, queue: key, id: m.ID, payload: streamPayload(m), deliveries: 1}
+		if msg.payload == "" {
+			// The entry was deleted from the stream while pending, as by an
+			// XTRIM outside queue.TrimStream; there is no job left to run.
+			w.log.Error("stalled job lost: entry no longer in stream", obs.String("stream", stream), obs.String("entry", m.ID), obs.String("worker_id", workerID))
+			w.ackStream(ctx, msg)
+			continue
+		}
+		pending, err := w.rdb.XPendingExt(ctx, &redis.XPendingExtArgs{
+			Stream: stream, Group: w.cfg.Worker.Stream.Group, Start: m.ID, End: m.ID, Count: 1,
+		}).Result()
+		if err == nil && len(pending) == 1 {
+			msg.deliveries = pending[0].RetryCount
+		}
+		w.log.Warn("claimed stalled job", obs.String("stream", stream), obs.String("entry", m.ID), obs.Int("deliveries", int(msg.deliveries)), obs.String("worker_id", workerID))
+
+		if msg.deliveries > int64(w.maxRetries(ctx, key))+1 {
+			job, err := queue.UnmarshalJob(msg.payload)
+			if err == nil {
+				job.Retries = int(msg.deliveries) - 1
+				w.deadLetterStream(ctx, msg, job, "delivery limit exceeded", "", w.completionEvent(ctx, job, StatusDeadLetter, key, workerID, 0, "delivery limit exceeded"))
+			} else {
+				w.ackStream(ctx, msg)
+			}
+			continue
+		}
+		return msg
+	}
+	return nil
+}
+
+// processStreamJob runs the handler for msg. Success acks the entry.
+// A failure with retries left leaves it pending: it is redelivered through
+// XAUTOCLAIM once claim_idle has passed, which stands in for the list-mode
+// backoff. Panics and failures on the last attempt go to the dead letter
+// list and are acked.
+func (w *Worker) processStreamJob(ctx context.Context, workerID, hbKey string, msg *streamMessage) bool {
+	defer func() {
+		if err := w.rdb.Del(ctx, hbKey).Err(); err != nil {
+			w.log.Error("DEL heartbeat failed", obs.Err(err))
+		}
+	}()
+	job, err := queue.UnmarshalJob(msg.payload)
+	if err != nil {
+		w.log.Error("invalid job payload", obs.String("entry", msg.id), obs.Err(err))
+		// ack it to avoid a poison pill loop
+		w.ackStream(ctx, msg)
+		return false
+	}
+	// The stream entry is never rewritten, so the attempt number comes from
+	// the group's delivery count.
+	if r := int(msg.deliveries) - 1; r > job.Retries {
+		job.Retries = r
+	}
+	ctx = obs.ContextWithRequestID(ctx, job.RequestID)
+
+	ctx, span := obs.ContextWithJobSpan(ctx, job)
+	defer span.End()
+	obs.AddSpanAttributes(ctx,
+		obs.KeyValue("worker.id", workerID),
+		obs.KeyValue("queue.source", msg.queue),
+		obs.KeyValue("stream.entry", msg.id),
+	)
+	obs.AddEvent(ctx, "job.processing.started",
+		obs.KeyValue("job.id", job.ID),
+		obs.KeyValue("worker.id", workerID),
+	)
+
+	stopKeepAlive := w.keepClaimed(ctx, workerID, msg)
+	hctx, result := withResultSlot(ctx)
+	hctx = w.withJobContext(hctx, job)
+	hctx, cancel := w.jobContext(hctx, msg.queue)
+	processingStart := time.Now()
+	herr := w.chain()(hctx, job)
+	processingDuration := time.Since(processingStart)
+	cancel()
+	stopKeepAlive()
+	obs.AddSpanAttributes(ctx, obs.KeyValue("processing.duration_ms", processingDuration.Milliseconds()))
+
+	if herr == nil {
+		obs.SetSpanSuccess(ctx)
+		obs.AddEvent(ctx, "job.processing.completed",
+			obs.KeyValue("job.id", job.ID),
+			obs.KeyValue("duration_ms", processingDuration.Milliseconds()),
+		)
+		ev := w.completionEvent(ctx, job, StatusCompleted, msg.queue, workerID, processingDuration, *result)
+		if err := w.pushOutcome(ctx, w.cfg.Worker.CompletedList, msg.payload, ev); err != nil {
+			w.log.Error("LPUSH completed failed", obs.Err(err))
+			obs.RecordError(ctx, err)
+		}
+		w.ackStream(ctx, msg)
+		obs.JobsCompleted.Inc()
+		w.silence.record(msg.queue)
+		w.log.Info("job completed", obs.String("id", job.ID), obs.String("trace_id", job.TraceID), obs.String("span_id", job.SpanID), obs.String("worker_id", workerID), obs.RequestIDField(ctx))
+		return true
+	}
+
+	obs.JobsFailed.Inc()
+	failureReason, class, retryable := w.classifyFailure(herr)
+	obs.RecordError(ctx, herr)
+	obs.AddEvent(ctx, "job.processing.failed",
+		obs.KeyValue("job.id", job.ID),
+		obs.KeyValue("reason", failureReason),
+		obs.KeyValue("error_class", string(class)),
+		obs.KeyValue("retries", job.Retries),
+	)
+
+	job.Retries++
+	if retryable && job.Retries <= w.maxRetries(ctx, msg.queue) {
+		obs.JobsRetried.Inc()
+		obs.AddEvent(ctx, "job.retrying",
+			obs.KeyValue("job.id", job.ID),
+			obs.KeyValue("retry_count", job.Retries),
+			obs.KeyValue("backoff_ms", w.cfg.Worker.Stream.ClaimIdle.Milliseconds()),
+		)
+		w.log.Warn("job left pending for retry", obs.String("id", job.ID), obs.Int("retries", job.Retries), obs.String("error_class", string(class)), obs.String("entry", msg.id), obs.String("worker_id", workerID), obs.RequestIDField(ctx))
+		return false
+	}
+
+	obs.AddEvent(ctx, "job.dead_lettered",
+		obs.KeyValue("job.id", job.ID),
+		obs.KeyValue("max_retries_exceeded", retryable),
+	)
+	ev := w.completionEvent(ctx, job, StatusDeadLetter, msg.queue, workerID, processingDuration, failureReason)
+	if ev != nil {
+		ev.ErrorClass = string(class)
+	}
+	w.deadLetterStream(ctx, msg, job, failureReason, class, ev)
+	return false
+}
+
+// keepClaimed re-claims msg for this consumer every claim_idle/3 while the
+// handler runs, resetting its idle time so a long job is not taken over by
+// another worker. XCLAIM with JUSTID leaves the delivery count alone.
+func (w *Worker) keepClaimed(ctx context.Context, workerID string, msg *streamMessage) (stop func()) {
+	interval := w.cfg.Worker.Stream.ClaimIdle / 3
+	if interval <= 0 {
+		return func() {}
+	}
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-done:
+				return
+			case <-ticker.C:
+				err := w.rdb.XClaimJustID(ctx, &redis.XClaimArgs{
+					Stream:   msg.stream,
+					Group:    w.cfg.Worker.Stream.Group,
+					Consumer: workerID,
+					Messages: []string{msg.id},
+				}).Err()
+				if err != nil && ctx.Err() == nil {
+					w.log.Warn("XCLAIM keep-alive failed", obs.String("entry", msg.id), obs.Err(err))
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-finished
+	}
+}
+
+// deadLetterStream moves job to the dead letter list with its failure
+// reason and error class (empty when no handler error decided it),
+// publishing ev with it, and acks its entry.
+func (w *Worker) deadLetterStream(ctx context.Context, msg *streamMessage, job queue.Job, reason string, class ErrorClass, ev *CompletionEvent) {
+	payload, _ := job.Marshal()
+	if err := w.pushOutcome(ctx, w.deadLetterList(ctx, msg.queue), w.deadLetterEntry(payload, reason, class), ev); err != nil {
+		// Leave it pending; it will be claimed and dead-lettered again.
+		w.log.Error("LPUSH DLQ failed", obs.Err(err))
+		obs.RecordError(ctx, err)
+		return
+	}
+	w.ackStream(ctx, msg)
+	obs.JobsDeadLetter.Inc()
+	w.log.Error("job dead-lettered", obs.String("id", job.ID), obs.String("reason", reason), obs.String("entry", msg.id), obs.String("trace_id", job.TraceID), obs.String("span_id", job.SpanID), obs.RequestIDField(ctx))
+}
+
+// requeueRejectedStream is requeueRejected for stream mode: the job is added
+// again at the end of the stream and the rejected entry is acked.
+func (w *Worker) requeueRejectedStream(ctx context.Context, qb *queueBreaker, msg *streamMessage, hbKey string) {
+	if d := breakerSettings(w.cfg).RequeueDelay; d > 0 {
+		select {
+		case <-ctx.Done():
+		case <-time.After(d):
+		}
+	}
+	if err := w.rdb.XAdd(ctx, streamAddArgs(msg.stream, msg.payload)).Err(); err != nil {
+		// Leave it pending for XAUTOCLAIM.
+		w.log.Error("XADD breaker requeue failed", obs.Err(err))
+		return
+	}
+	w.ackStream(ctx, msg)
+	if err := queue.TrimStream(ctx, w.rdb, msg.stream, w.cfg.Worker.Stream.Group, w.cfg.Worker.Stream.MaxLen); err != nil {
+		w.log.Warn("stream trim failed", obs.String("stream", msg.stream), obs.Err(err))
+	}
+	if err := w.rdb.Del(ctx, hbKey).Err(); err != nil {
+		w.log.Error("DEL heartbeat failed", obs.Err(err))
+	}
+	qb.requeued.Add(1)
+	obs.JobsBreakerRequeued.WithLabelValues(msg.queue).Inc()
+}
+
+// ackStream removes msg from the group's pending entries list. The entry
+// itself stays in the stream for replay until queue.TrimStream removes it
+// to keep the stream near worker.stream.max_len.
+func (w *Worker) ackStream(ctx context.Context, msg *streamMessage) {
+	if err := w.rdb.XAck(ctx, msg.stream, w.cfg.Worker.Stream.Group, msg.id).Err(); err != nil {
+		w.log.Error("XACK failed", obs.String("entry", msg.id), obs.Err(err))
+	}
+}
+
+func streamPayload(m redis.XMessage) string {
+	s, _ := m.Values[queue.StreamField].(string)
+	return s
+}
+
+func streamAddArgs(stream, payload string) *redis.XAddArgs {
+	return &redis.XAddArgs{
+		Stream: stream,
+		Values: []interface{}{queue.StreamField, payload},
+	}
+}
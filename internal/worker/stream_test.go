@@ -0,0 +1,149 @@
+//go:build worker_tests
+// +build worker_tests
+
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/flyingrobots/go-redis-work-queue/internal/admin"
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+func setupStreamWorker(t *testing.T) (*Worker, *config.Config, *redis.Client) {
+	t.Helper()
+	mr, _ := miniredis.Run()
+	t.Cleanup(mr.Close)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	cfg, _ := config.Load("nonexistent.yaml")
+	cfg.Worker.Mode = config.ModeStream
+	cfg.Worker.Count = 1
+	cfg.Worker.MaxRetries = 1
+	cfg.Worker.BRPopLPushTimeout = 10 * time.Millisecond
+	cfg.Worker.Stream.ClaimIdle = 30 * time.Millisecond
+	cfg.Worker.Stream.ClaimInterval = 10 * time.Millisecond
+	return New(cfg, rdb, zap.NewNop()), cfg, rdb
+}
+
+func addStreamJob(t *testing.T, rdb *redis.Client, cfg *config.Config, path string) string {
+	t.Helper()
+	payload, _ := queue.NewJob("id-"+path, path, 1, "low", "", "").Marshal()
+	stream := queue.StreamKey(cfg.Worker.Queues["low"])
+	if err := rdb.XAdd(context.Background(), streamAddArgs(stream, payload)).Err(); err != nil {
+		t.Fatal(err)
+	}
+	return stream
+}
+
+func runStreamWorker(t *testing.T, w *Worker, until func() bool) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx) }()
+	for ctx.Err() == nil && !until() {
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func pendingCount(t *testing.T, rdb *redis.Client, stream, group string) int64 {
+	t.Helper()
+	p, err := rdb.XPending(context.Background(), stream, group).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p.Count
+}
+
+func TestStreamModeAcksCompletedJobs(t *testing.T) {
+	w, cfg, rdb := setupStreamWorker(t)
+	stream := addStreamJob(t, rdb, cfg, "/tmp/ok.txt")
+	ctx := context.Background()
+
+	runStreamWorker(t, w, func() bool {
+		n, _ := rdb.LLen(ctx, cfg.Worker.CompletedList).Result()
+		return n == 1
+	})
+	if n, _ := rdb.LLen(ctx, cfg.Worker.CompletedList).Result(); n != 1 {
+		t.Fatalf("expected 1 completed job, got %d", n)
+	}
+	if n := pendingCount(t, rdb, stream, cfg.Worker.Stream.Group); n != 0 {
+		t.Fatalf("expected no pending entries, got %d", n)
+	}
+	// Acked entries stay in the stream for replay.
+	if n, _ := rdb.XLen(ctx, stream).Result(); n != 1 {
+		t.Fatalf("expected the entry to remain in the stream, got %d", n)
+	}
+}
+
+func TestStreamModeRedeliversThenDeadLetters(t *testing.T) {
+	w, cfg, rdb := setupStreamWorker(t)
+	stream := addStreamJob(t, rdb, cfg, "/tmp/fail.txt")
+	ctx := context.Background()
+	attempts := 0
+	w.handler = func(ctx context.Context, job queue.Job) error {
+		attempts++
+		if job.Retries != attempts-1 {
+			t.Errorf("attempt %d saw retries=%d", attempts, job.Retries)
+		}
+		return errJobFailed
+	}
+
+	runStreamWorker(t, w, func() bool {
+		n, _ := rdb.LLen(ctx, cfg.Worker.DeadLetterList).Result()
+		return n == 1
+	})
+	if attempts != 2 {
+		t.Fatalf("expected 2 attempts with max_retries=1, got %d", attempts)
+	}
+	dlq, _ := rdb.LRange(ctx, cfg.Worker.DeadLetterList, 0, -1).Result()
+	if len(dlq) != 1 {
+		t.Fatalf("expected 1 dead-lettered job, got %d", len(dlq))
+	}
+	if job, _ := queue.UnmarshalJob(dlq[0]); job.Retries != 2 {
+		t.Fatalf("expected retries=2 on the dead letter, got %d", job.Retries)
+	}
+	if rep, err := admin.DLQAnalytics(ctx, cfg, rdb, 10); err != nil || rep.Reasons[0].Name != errJobFailed.Error() {
+		t.Fatalf("expected the dead letter grouped by its reason, got %+v, %v", rep, err)
+	}
+	if n := pendingCount(t, rdb, stream, cfg.Worker.Stream.Group); n != 0 {
+		t.Fatalf("expected no pending entries, got %d", n)
+	}
+}
+
+func TestStreamModeClaimsStalledEntries(t *testing.T) {
+	w, cfg, rdb := setupStreamWorker(t)
+	ctx := context.Background()
+	if err := w.ensureGroups(ctx); err != nil {
+		t.Fatal(err)
+	}
+	stream := addStreamJob(t, rdb, cfg, "/tmp/ok.txt")
+	// A consumer reads the entry and dies without acking it.
+	if err := rdb.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group: cfg.Worker.Stream.Group, Consumer: "crashed", Streams: []string{stream, ">"}, Count: 1, Block: -1,
+	}).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	runStreamWorker(t, w, func() bool {
+		n, _ := rdb.LLen(ctx, cfg.Worker.CompletedList).Result()
+		return n == 1
+	})
+	if n, _ := rdb.LLen(ctx, cfg.Worker.CompletedList).Result(); n != 1 {
+		t.Fatalf("expected the stalled job to be completed, got %d", n)
+	}
+	if n := pendingCount(t, rdb, stream, cfg.Worker.Stream.Group); n != 0 {
+		t.Fatalf("expected no pending entries, got %d", n)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/obs"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/redis/go-redis/v9"
+)
+
+// stampScript replaces ARGV[1] at the head of the processing list KEYS[1],
+// where BRPOPLPUSH just put it, with ARGV[2]. It returns 0 and leaves the
+// list alone when the head is something else.
+var stampScript = redis.NewScript(`
+if redis.call('LINDEX', KEYS[1], 0) ~= ARGV[1] then
+  return 0
+end
+redis.call('LSET', KEYS[1], 0, ARGV[2])
+return 1
+`)
+
+// queueTimeouts maps the keys of the queues listed in
+// worker.queue_timeouts to their timeouts.
+func queueTimeouts(cfg *config.Config) map[string]config.QueueTimeouts {
+	out := make(map[string]config.QueueTimeouts, len(cfg.Worker.QueueTimeouts))
+	for p, qt := range cfg.Worker.QueueTimeouts {
+		if key := cfg.Worker.Queues[p]; key != "" {
+			out[key] = qt
+		}
+	}
+	return out
+}
+
+// stampVisibility writes the visibility deadline of srcQueue into the
+// processing list entry of a job just dequeued from it, so the reaper can
+// return the job as soon as the deadline passes. It returns the entry as
+// it now stands, which the caller must use from then on, and how long the
+// heartbeat should live. Queues without a visibility timeout, and entries
+// that cannot be stamped, keep the payload and heartbeat_ttl.
+func (w *Worker) stampVisibility(ctx context.Context, srcQueue, procList, payload string) (string, time.Duration) {
+	vt := w.timeouts[srcQueue].VisibilityTimeout
+	if vt <= 0 {
+		return payload, w.cfg.Worker.HeartbeatTTL
+	}
+	job, err := queue.UnmarshalJob(payload)
+	if err != nil {
+		return payload, w.cfg.Worker.HeartbeatTTL // processJob drops it
+	}
+	job.VisibilityDeadline = time.Now().Add(vt).UTC().Format(time.RFC3339Nano)
+	stamped, err := job.Marshal()
+	if err != nil {
+		return payload, w.cfg.Worker.HeartbeatTTL
+	}
+	if c := w.cfg.Producer.Compression; queue.IsCompressed(payload) {
+		stamped, _ = queue.CompressPayload(stamped, c.Codec, c.MinSize)
+	}
+	ok, err := stampScript.Run(ctx, w.client(srcQueue), []string{procList}, payload, stamped).Int()
+	if err != nil || ok == 0 {
+		w.log.Warn("visibility stamp failed", obs.String("id", job.ID), obs.String("queue", srcQueue), obs.Err(err))
+		return payload, w.cfg.Worker.HeartbeatTTL
+	}
+	return stamped, vt
+}
+
+// jobContext bounds ctx by srcQueue's job_timeout, if it has one.
+func (w *Worker) jobContext(ctx context.Context, srcQueue string) (context.Context, context.CancelFunc) {
+	if jt := w.timeouts[srcQueue].JobTimeout; jt > 0 {
+		return context.WithTimeout(ctx, jt)
+	}
+	return ctx, func() {}
+}
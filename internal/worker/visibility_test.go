@@ -0,0 +1,74 @@
+//go:build worker_tests
+// +build worker_tests
+
+// Copyright 2025 James Ross
+package worker
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+)
+
+func TestStampVisibilityWritesDeadlineIntoProcessingEntry(t *testing.T) {
+	w, cfg, rdb, cleanup := setupWorkerTest(t)
+	defer cleanup()
+	cfg.Worker.QueueTimeouts = map[string]config.QueueTimeouts{"low": {VisibilityTimeout: time.Minute}}
+	w.timeouts = queueTimeouts(cfg)
+	ctx := context.Background()
+	procList := fmt.Sprintf(cfg.Worker.ProcessingListPattern, "w1")
+
+	payload, _ := queue.NewJob("a", "/tmp/ok.txt", 1, "low", "", "").Marshal()
+	rdb.LPush(ctx, procList, payload)
+	stamped, ttl := w.stampVisibility(ctx, cfg.Worker.Queues["low"], procList, payload)
+	if ttl != time.Minute {
+		t.Fatalf("expected the heartbeat to live as long as the visibility timeout, got %s", ttl)
+	}
+	if head, _ := rdb.LIndex(ctx, procList, 0).Result(); head != stamped || stamped == payload {
+		t.Fatalf("expected the entry replaced with the stamped payload, got %s", head)
+	}
+	job, _ := queue.UnmarshalJob(stamped)
+	if job.VisibleAfter(time.Now()) || !job.VisibleAfter(time.Now().Add(time.Minute)) {
+		t.Fatalf("unexpected deadline %q", job.VisibilityDeadline)
+	}
+
+	// queues without a visibility timeout keep the payload and heartbeat_ttl
+	high, _ := queue.NewJob("b", "/tmp/ok.txt", 1, "high", "", "").Marshal()
+	if got, ttl := w.stampVisibility(ctx, cfg.Worker.Queues["high"], procList, high); got != high || ttl != cfg.Worker.HeartbeatTTL {
+		t.Fatalf("expected high left alone, got %s %s", got, ttl)
+	}
+}
+
+func TestJobTimeoutCancelsHandler(t *testing.T) {
+	w, cfg, rdb, cleanup := setupWorkerTest(t)
+	defer cleanup()
+	cfg.Worker.MaxRetries = 0
+	cfg.Worker.QueueTimeouts = map[string]config.QueueTimeouts{"low": {VisibilityTimeout: time.Minute, JobTimeout: 10 * time.Millisecond}}
+	w.timeouts = queueTimeouts(cfg)
+	w.handler = func(ctx context.Context, job queue.Job) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	ctx := context.Background()
+	procList := fmt.Sprintf(cfg.Worker.ProcessingListPattern, "w1")
+	payload, _ := queue.NewJob("a", "/tmp/ok.txt", 1, "low", "", "").Marshal()
+	rdb.LPush(ctx, procList, payload)
+
+	done := make(chan bool, 1)
+	go func() { done <- w.processJob(ctx, "w1", cfg.Worker.Queues["low"], procList, "hb", payload) }()
+	select {
+	case ok := <-done:
+		if ok {
+			t.Fatal("expected the timed out job to fail")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("handler was not canceled by job_timeout")
+	}
+	if n, _ := rdb.LLen(ctx, cfg.Worker.DeadLetterList).Result(); n != 1 {
+		t.Fatalf("expected the job dead-lettered, got %d", n)
+	}
+}
@@ -8,48 +8,104 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/flyingrobots/go-redis-work-queue/internal/breaker"
 	"github.com/flyingrobots/go-redis-work-queue/internal/config"
 	"github.com/flyingrobots/go-redis-work-queue/internal/obs"
+	"github.com/flyingrobots/go-redis-work-queue/internal/producer"
 	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
+	"github.com/flyingrobots/go-redis-work-queue/internal/redisclient"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
 type Worker struct {
-	cfg    *config.Config
-	rdb    *redis.Client
-	log    *zap.Logger
-	cb     *breaker.CircuitBreaker
-	baseID string
+	cfg        *config.Config
+	rdb        *redis.Client
+	router     *redisclient.Router
+	log        *zap.Logger
+	breakers   map[string]*queueBreaker
+	baseID     string
+	handler    Handler            // used when no job types are registered
+	handlers   map[string]Handler // by job type
+	fallback   Handler            // for unregistered job types
+	middleware []HandlerMiddleware
+	paused     pauseCache
+	dedup      map[string]bool
+	coalesce   map[string]bool
+	aging      map[string]bool // queue keys under producer.priority_aging
+	timeouts   map[string]config.QueueTimeouts
+	weights    []int // fair scheduling weight per priority
+	defs       queue.DefinitionCache
+	silence    *silenceTracker    // nil unless worker.silence_alert is enabled
+	children   *producer.Producer // enqueues jobs for Context.Enqueue
+
+	concurrency atomic.Int64
+	latencyMu   sync.Mutex
+	latency     time.Duration // EWMA of job processing time
+
+	live atomic.Pointer[liveSettings] // settings Reload may change
+	pool atomic.Pointer[workerPool]   // set by Run
 }
 
+var (
+	errJobCanceled = errors.New("canceled")
+	errJobFailed   = errors.New("processing_failed")
+)
+
 func New(cfg *config.Config, rdb *redis.Client, log *zap.Logger) *Worker {
-	cb := breaker.New(cfg.CircuitBreaker.Window, cfg.CircuitBreaker.CooldownPeriod, cfg.CircuitBreaker.FailureThreshold, cfg.CircuitBreaker.MinSamples)
 	host, _ := os.Hostname()
 	pid := os.Getpid()
 	now := time.Now().UnixNano()
 	randSfx := fmt.Sprintf("%04x", time.Now().UnixNano()&0xffff)
 	base := fmt.Sprintf("%s-%d-%d-%s", host, pid, now, randSfx)
-	return &Worker{cfg: cfg, rdb: rdb, log: log, cb: cb, baseID: base}
+	w := &Worker{cfg: cfg, rdb: rdb, log: log, breakers: newQueueBreakers(cfg), baseID: base, dedup: dedupQueues(cfg), coalesce: coalesceQueues(cfg), aging: agingQueues(cfg), timeouts: queueTimeouts(cfg), weights: priorityWeights(cfg)}
+	w.handler = simulateJob
+	w.children = producer.New(cfg, rdb, log)
+	if cfg.Worker.SilenceAlert.Enabled {
+		w.silence = newSilenceTracker()
+	}
+	return w
 }
 
 func (w *Worker) Run(ctx context.Context) error {
+	run := w.runOne
+	if w.cfg.Worker.Mode == config.ModeStream {
+		if err := w.ensureGroups(ctx); err != nil {
+			return err
+		}
+		run = w.runStreamOne
+	}
+
+	w.live.CompareAndSwap(nil, newLiveSettings(w.cfg))
+	size := w.settings().count
+	if as := w.cfg.Worker.Autoscale; as.Enabled {
+		size = max(as.MinConcurrency, min(as.MaxConcurrency, size))
+	}
 	var wg sync.WaitGroup
-	for i := 0; i < w.cfg.Worker.Count; i++ {
+	pool := &workerPool{ctx: ctx, wg: &wg, run: run, base: w.baseID, slots: map[int]*workerSlot{}}
+	pool.resize(size)
+	w.pool.Store(pool)
+	w.concurrency.Store(int64(size))
+	obs.WorkerConcurrency.Set(float64(size))
+	if w.cfg.Worker.Autoscale.Enabled {
+		// tracked by wg so the pool cannot grow once Run starts waiting
 		wg.Add(1)
-		id := fmt.Sprintf("%s-%d", w.baseID, i)
-		go func(workerID string) {
+		go func() {
 			defer wg.Done()
-			obs.WorkerActive.Inc()
-			defer obs.WorkerActive.Dec()
-			w.runOne(ctx, workerID)
-		}(id)
+			w.autoscale(ctx, pool)
+		}()
+	}
+
+	if cr := w.cfg.Worker.CompletedRetention; cr.Interval > 0 && (cr.KeepLast > 0 || cr.MaxAge > 0) {
+		go w.trimCompleted(ctx)
+	}
+	if w.cfg.Worker.SilenceAlert.Enabled {
+		go w.watchSilence(ctx)
 	}
 
-	// periodically update breaker state metric
+	// periodically update breaker state metrics
 	go func() {
 		ticker := time.NewTicker(2 * time.Second)
 		defer ticker.Stop()
@@ -58,14 +114,7 @@ func (w *Worker) Run(ctx context.Context) error {
 			case <-ctx.Done():
 				return
 			case <-ticker.C:
-				switch w.cb.State() {
-				case breaker.Closed:
-					obs.CircuitBreakerState.Set(0)
-				case breaker.HalfOpen:
-					obs.CircuitBreakerState.Set(1)
-				case breaker.Open:
-					obs.CircuitBreakerState.Set(2)
-				}
+				w.publishBreakerStates()
 			}
 		}
 	}()
@@ -74,31 +123,41 @@ func (w *Worker) Run(ctx context.Context) error {
 	return nil
 }
 
-func (w *Worker) runOne(ctx context.Context, workerID string) {
+func (w *Worker) runOne(ctx context.Context, workerID string, stop <-chan struct{}) {
 	procList := fmt.Sprintf(w.cfg.Worker.ProcessingListPattern, workerID)
 	hbKey := fmt.Sprintf(w.cfg.Worker.HeartbeatKeyPattern, workerID)
+	credits := make([]int, len(w.cfg.Worker.Priorities))
 
-	for ctx.Err() == nil {
-		if !w.cb.Allow() {
-			time.Sleep(w.cfg.Worker.BreakerPause)
-			continue
-		}
-
-		// fetch by priority using BRPOPLPUSH with short timeout
+	for ctx.Err() == nil && !stopped(stop) {
+		// fetch by priority (or fair scheduling order) using BRPOPLPUSH with
+		// short timeout, skipping queues that are paused, over their rate limit
+		// or whose breaker is open
 		var payload string
 		var srcQueue string
-		for _, p := range w.cfg.Worker.Priorities {
-			key := w.cfg.Worker.Queues[p]
+		polled := 0
+		for _, t := range w.pollTargets(ctx, w.pollOrder(ctx, credits)) {
+			p, key := t.priority, t.key
 			if key == "" {
 				continue
 			}
+			if qb := w.breakers[key]; qb != nil && !qb.cb.Ready() {
+				continue
+			}
+			if w.queuePaused(ctx, key) {
+				continue
+			}
+			if !w.takeToken(ctx, p, key) {
+				continue // over its rate; try the next queue
+			}
+			polled++
 
 			// Start dequeue span
 			deqCtx, deqSpan := obs.StartDequeueSpan(ctx, key)
 
-			v, err := w.rdb.BRPopLPush(deqCtx, key, procList, w.cfg.Worker.BRPopLPushTimeout).Result()
+			v, err := w.client(key).BRPopLPush(deqCtx, key, procList, w.cfg.Worker.BRPopLPushTimeout).Result()
 			if err == redis.Nil {
 				deqSpan.End()
+				w.returnToken(ctx, p, key)
 				continue
 			}
 			if err != nil {
@@ -107,6 +166,7 @@ func (w *Worker) runOne(ctx context.Context, workerID string) {
 				if ctx.Err() != nil {
 					return
 				}
+				w.returnToken(ctx, p, key)
 				w.log.Warn("BRPOPLPUSH error", obs.Err(err))
 				time.Sleep(50 * time.Millisecond)
 				continue
@@ -116,42 +176,100 @@ func (w *Worker) runOne(ctx context.Context, workerID string) {
 			obs.SetSpanSuccess(deqCtx)
 			obs.AddEvent(deqCtx, "job_dequeued", obs.KeyValue("queue", key))
 			deqSpan.End()
+			obs.PriorityServed.WithLabelValues(p).Inc()
 
 			payload = v
 			srcQueue = key
+			w.untrackAging(ctx, srcQueue, payload)
 			break
 		}
 		if payload == "" {
+			if polled == 0 {
+				// every queue is paused, throttled or has its breaker open; back off
+				time.Sleep(w.cfg.Worker.BreakerPause)
+			}
 			continue // timeout across all priorities
 		}
 
+		// heartbeat set, living as long as the job stays invisible
+		payload, hbTTL := w.stampVisibility(ctx, srcQueue, procList, payload)
+		if !w.startJob(ctx, workerID, srcQueue, procList, hbKey, payload, hbTTL) {
+			continue
+		}
+
+		// another worker may have claimed the half-open probe since Ready
+		qb := w.breakers[srcQueue]
+		if qb != nil && !qb.cb.Allow() {
+			w.requeueRejected(ctx, qb, srcQueue, procList, hbKey, payload)
+			continue
+		}
 		obs.JobsConsumed.Inc()
-		// heartbeat set
-		_ = w.rdb.Set(ctx, hbKey, payload, w.cfg.Worker.HeartbeatTTL).Err()
 
-		// measure state transition around Record() to count trips
 		start := time.Now()
 		// process job
 		ok := w.processJob(ctx, workerID, srcQueue, procList, hbKey, payload)
-		obs.JobProcessingDuration.Observe(time.Since(start).Seconds())
-		prev := w.cb.State()
-		w.cb.Record(ok)
-		curr := w.cb.State()
-		if prev != curr && curr == breaker.Open {
-			obs.CircuitBreakerTrips.Inc()
+		elapsed := time.Since(start)
+		obs.JobProcessingDuration.Observe(elapsed.Seconds())
+		w.observeLatency(elapsed)
+		if qb != nil {
+			w.record(qb, srcQueue, ok)
 		}
 	}
 }
 
+// startJob sets the heartbeat of a job just dequeued from srcQueue. On
+// queues with completion dedup it claims the job ID in the same step and
+// returns false when the job must not run: a duplicate, already removed
+// from procList, or a failed claim, which leaves the job to the reaper.
+func (w *Worker) startJob(ctx context.Context, workerID, srcQueue, procList, hbKey, payload string, hbTTL time.Duration) bool {
+	rc := w.client(srcQueue)
+	job, err := queue.UnmarshalJob(payload)
+	if !w.dedup[srcQueue] || err != nil {
+		_ = rc.Set(ctx, hbKey, payload, hbTTL).Err()
+		return true // processJob drops an invalid payload
+	}
+	run, err := w.claimJob(ctx, srcQueue, workerID, procList, hbKey, payload, job.ID, hbTTL)
+	if err != nil {
+		// leave the job for the reaper rather than risk a second run
+		w.log.Error("dedup claim failed", obs.String("id", job.ID), obs.Err(err))
+		return false
+	}
+	if !run {
+		obs.JobsDeduplicated.WithLabelValues(srcQueue).Inc()
+	}
+	return run
+}
+
 func (w *Worker) processJob(ctx context.Context, workerID, srcQueue, procList, hbKey, payload string) bool {
+	rc := w.client(srcQueue)
 	job, err := queue.UnmarshalJob(payload)
 	if err != nil {
 		w.log.Error("invalid job payload", obs.Err(err))
 		// remove from processing to avoid poison pill loop
-		_ = w.rdb.LRem(ctx, procList, 1, payload).Err()
-		_ = w.rdb.Del(ctx, hbKey).Err()
+		_ = rc.LRem(ctx, procList, 1, payload).Err()
+		_ = rc.Del(ctx, hbKey).Err()
 		return false
 	}
+	dedup := w.dedup[srcQueue]
+	// Restore the producer's request ID so handler logs can carry it
+	ctx = obs.ContextWithRequestID(ctx, job.RequestID)
+
+	coalesce := w.coalesce[srcQueue] && job.DedupKey != ""
+	if coalesce {
+		m, err := w.coalesceJob(ctx, srcQueue, job)
+		if err != nil {
+			// coalescing only saves work, so run the job rather than stall it
+			w.log.Warn("coalesce check failed", obs.String("id", job.ID), obs.String("dedup_key", job.DedupKey), obs.Err(err))
+			coalesce = false
+		} else if m != nil {
+			w.completeCoalesced(ctx, workerID, srcQueue, procList, hbKey, payload, job, m)
+			if dedup {
+				w.markDone(ctx, srcQueue, job.ID)
+			}
+			return true
+		}
+	}
+
 	// Start span with job's TraceID/SpanID when available
 	ctx, span := obs.ContextWithJobSpan(ctx, job)
 	defer span.End()
@@ -169,37 +287,16 @@ func (w *Worker) processJob(ctx context.Context, workerID, srcQueue, procList, h
 		obs.KeyValue("worker.id", workerID),
 	)
 
-	// Simulated processing: sleep based on filesize with cancellable timer
-	dur := time.Duration(min64(job.FileSize/1024, 1000)) * time.Millisecond
-	canceled := false
-
+	hctx, result := withResultSlot(ctx)
+	hctx = w.withJobContext(hctx, job)
+	hctx, cancel := w.jobContext(hctx, srcQueue)
 	processingStart := time.Now()
-
-	if dur > 0 {
-		timer := time.NewTimer(dur)
-		defer func() {
-			if !timer.Stop() {
-				<-timer.C
-			}
-		}()
-		select {
-		case <-ctx.Done():
-			canceled = true
-		case <-timer.C:
-		}
-	} else {
-		select {
-		case <-ctx.Done():
-			canceled = true
-		default:
-		}
-	}
-
+	herr := w.chain()(hctx, job)
+	cancel()
 	processingDuration := time.Since(processingStart)
 	obs.AddSpanAttributes(ctx, obs.KeyValue("processing.duration_ms", processingDuration.Milliseconds()))
 
-	// For demonstration, consider processing success unless canceled or filename contains "fail"
-	success := !canceled && !strings.Contains(strings.ToLower(job.FilePath), "fail")
+	success := herr == nil
 
 	if success {
 		// Mark span as successful
@@ -210,18 +307,26 @@ func (w *Worker) processJob(ctx context.Context, workerID, srcQueue, procList, h
 		)
 
 		// complete
-		if err := w.rdb.LPush(ctx, w.cfg.Worker.CompletedList, payload).Err(); err != nil {
+		ev := w.completionEvent(ctx, job, StatusCompleted, srcQueue, workerID, processingDuration, *result)
+		if err := w.pushOutcome(ctx, w.cfg.Worker.CompletedList, payload, ev); err != nil {
 			w.log.Error("LPUSH completed failed", obs.Err(err))
 			obs.RecordError(ctx, err)
 		}
-		if err := w.rdb.LRem(ctx, procList, 1, payload).Err(); err != nil {
+		if dedup {
+			w.markDone(ctx, srcQueue, job.ID)
+		}
+		if coalesce {
+			w.markCoalesced(ctx, srcQueue, job, *result)
+		}
+		if err := rc.LRem(ctx, procList, 1, payload).Err(); err != nil {
 			w.log.Error("LREM processing failed", obs.Err(err))
 		}
-		if err := w.rdb.Del(ctx, hbKey).Err(); err != nil {
+		if err := rc.Del(ctx, hbKey).Err(); err != nil {
 			w.log.Error("DEL heartbeat failed", obs.Err(err))
 		}
 		obs.JobsCompleted.Inc()
-		w.log.Info("job completed", obs.String("id", job.ID), obs.String("trace_id", job.TraceID), obs.String("span_id", job.SpanID), obs.String("worker_id", workerID))
+		w.silence.record(srcQueue)
+		w.log.Info("job completed", obs.String("id", job.ID), obs.String("trace_id", job.TraceID), obs.String("span_id", job.SpanID), obs.String("worker_id", workerID), obs.RequestIDField(ctx))
 		return true
 	}
 
@@ -229,26 +334,30 @@ func (w *Worker) processJob(ctx context.Context, workerID, srcQueue, procList, h
 	obs.JobsFailed.Inc()
 
 	// Record failure in span
-	failureReason := "processing_failed"
-	if canceled {
-		failureReason = "canceled"
-	}
-	obs.RecordError(ctx, errors.New(failureReason))
+	failureReason, class, retryable := w.classifyFailure(herr)
+	obs.RecordError(ctx, herr)
 	obs.AddEvent(ctx, "job.processing.failed",
 		obs.KeyValue("job.id", job.ID),
 		obs.KeyValue("reason", failureReason),
+		obs.KeyValue("error_class", string(class)),
 		obs.KeyValue("retries", job.Retries),
 	)
 
-	job.Retries++
-	// backoff
-	bo := backoff(job.Retries, w.cfg.Worker.Backoff.Base, w.cfg.Worker.Backoff.Max)
-	select {
-	case <-ctx.Done():
-	case <-time.After(bo):
+	if dedup {
+		w.releaseClaim(ctx, srcQueue, job.ID)
+	}
+	if coalesce {
+		w.releaseCoalesce(ctx, srcQueue, job)
 	}
 
-	if job.Retries <= w.cfg.Worker.MaxRetries {
+	job.Retries++
+	if retryable && job.Retries <= w.maxRetries(ctx, srcQueue) {
+		bo := backoff(job.Retries, w.cfg.Worker.Backoff.Base, w.cfg.Worker.Backoff.Max)
+		select {
+		case <-ctx.Done():
+		case <-time.After(bo):
+		}
+
 		obs.JobsRetried.Inc()
 		obs.AddEvent(ctx, "job.retrying",
 			obs.KeyValue("job.id", job.ID),
@@ -256,42 +365,71 @@ func (w *Worker) processJob(ctx context.Context, workerID, srcQueue, procList, h
 			obs.KeyValue("backoff_ms", bo.Milliseconds()),
 		)
 
+		job.VisibilityDeadline = ""
 		payload2, _ := job.Marshal()
-		if err := w.rdb.LPush(ctx, srcQueue, payload2).Err(); err != nil {
+		if c := w.cfg.Producer.Compression; queue.IsCompressed(payload) {
+			payload2, _ = queue.CompressPayload(payload2, c.Codec, c.MinSize)
+		}
+		if err := rc.LPush(ctx, srcQueue, payload2).Err(); err != nil {
 			w.log.Error("LPUSH retry failed", obs.Err(err))
 			obs.RecordError(ctx, err)
 		}
-		if err := w.rdb.LRem(ctx, procList, 1, payload).Err(); err != nil {
+		if err := rc.LRem(ctx, procList, 1, payload).Err(); err != nil {
 			w.log.Error("LREM processing failed", obs.Err(err))
 		}
-		if err := w.rdb.Del(ctx, hbKey).Err(); err != nil {
+		if err := rc.Del(ctx, hbKey).Err(); err != nil {
 			w.log.Error("DEL heartbeat failed", obs.Err(err))
 		}
-		w.log.Warn("job retried", obs.String("id", job.ID), obs.Int("retries", job.Retries), obs.String("trace_id", job.TraceID), obs.String("span_id", job.SpanID), obs.String("worker_id", workerID))
+		w.log.Warn("job retried", obs.String("id", job.ID), obs.Int("retries", job.Retries), obs.String("error_class", string(class)), obs.String("trace_id", job.TraceID), obs.String("span_id", job.SpanID), obs.String("worker_id", workerID), obs.RequestIDField(ctx))
 		return false
 	}
 
 	// dead letter
 	obs.AddEvent(ctx, "job.dead_lettered",
 		obs.KeyValue("job.id", job.ID),
-		obs.KeyValue("max_retries_exceeded", true),
+		obs.KeyValue("max_retries_exceeded", retryable),
 	)
 
-	if err := w.rdb.LPush(ctx, w.cfg.Worker.DeadLetterList, payload).Err(); err != nil {
+	ev := w.completionEvent(ctx, job, StatusDeadLetter, srcQueue, workerID, processingDuration, failureReason)
+	if ev != nil {
+		ev.ErrorClass = string(class)
+	}
+	if err := w.pushOutcome(ctx, w.deadLetterList(ctx, srcQueue), w.deadLetterEntry(payload, failureReason, class), ev); err != nil {
 		w.log.Error("LPUSH DLQ failed", obs.Err(err))
 		obs.RecordError(ctx, err)
 	}
-	if err := w.rdb.LRem(ctx, procList, 1, payload).Err(); err != nil {
+	if err := rc.LRem(ctx, procList, 1, payload).Err(); err != nil {
 		w.log.Error("LREM processing failed", obs.Err(err))
 	}
-	if err := w.rdb.Del(ctx, hbKey).Err(); err != nil {
+	if err := rc.Del(ctx, hbKey).Err(); err != nil {
 		w.log.Error("DEL heartbeat failed", obs.Err(err))
 	}
 	obs.JobsDeadLetter.Inc()
-	w.log.Error("job dead-lettered", obs.String("id", job.ID), obs.String("trace_id", job.TraceID), obs.String("span_id", job.SpanID), obs.String("worker_id", workerID))
+	w.log.Error("job dead-lettered", obs.String("id", job.ID), obs.String("reason", failureReason), obs.String("error_class", string(class)), obs.String("trace_id", job.TraceID), obs.String("span_id", job.SpanID), obs.String("worker_id", workerID), obs.RequestIDField(ctx))
 	return false
 }
 
+// simulateJob is the default handler: it sleeps based on file size (with a
+// cancellable timer) and fails jobs whose path contains "fail".
+func simulateJob(ctx context.Context, job queue.Job) error {
+	dur := time.Duration(min64(job.FileSize/1024, 1000)) * time.Millisecond
+	if dur > 0 {
+		timer := time.NewTimer(dur)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return errJobCanceled
+		case <-timer.C:
+		}
+	} else if ctx.Err() != nil {
+		return errJobCanceled
+	}
+	if strings.Contains(strings.ToLower(job.FilePath), "fail") {
+		return errJobFailed
+	}
+	return nil
+}
+
 func min64(a, b int64) int64 {
 	if a < b {
 		return a
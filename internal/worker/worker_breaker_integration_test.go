@@ -65,7 +65,7 @@ func TestWorkerBreakerTripsAndPausesConsumption(t *testing.T) {
 	deadline := time.Now().Add(2 * time.Second)
 	opened := false
 	for time.Now().Before(deadline) {
-		if w.cb.State() == 2 { // Open
+		if w.Stats().Breakers[cfg.Worker.Queues["low"]].State == "open" {
 			opened = true
 			break
 		}
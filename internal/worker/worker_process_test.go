@@ -12,6 +12,7 @@ import (
 
 	"github.com/alicebob/miniredis/v2"
 	"github.com/flyingrobots/go-redis-work-queue/internal/config"
+	"github.com/flyingrobots/go-redis-work-queue/internal/obs"
 	"github.com/flyingrobots/go-redis-work-queue/internal/queue"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
@@ -79,3 +80,24 @@ func TestProcessJobRetryThenDLQ(t *testing.T) {
 		t.Fatalf("expected DLQ 1, got %d", n)
 	}
 }
+
+func TestProcessJobRestoresRequestID(t *testing.T) {
+	w, cfg, _, cleanup := setupWorkerTest(t)
+	defer cleanup()
+	var got string
+	w.handler = func(ctx context.Context, job queue.Job) error {
+		got = obs.RequestID(ctx)
+		return nil
+	}
+	job := queue.NewJob("id1", "/tmp/ok.txt", 10, "low", "", "")
+	job.RequestID = "req-123"
+	payload, _ := job.Marshal()
+	procList := fmt.Sprintf(cfg.Worker.ProcessingListPattern, "w1")
+	hbKey := fmt.Sprintf(cfg.Worker.HeartbeatKeyPattern, "w1")
+	if !w.processJob(context.Background(), "w1", cfg.Worker.Queues["low"], procList, hbKey, payload) {
+		t.Fatal("expected success")
+	}
+	if got != "req-123" {
+		t.Fatalf("expected handler to see request ID req-123, got %q", got)
+	}
+}
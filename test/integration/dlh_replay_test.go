@@ -8,7 +8,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -128,10 +130,17 @@ type DLHConfig struct {
 
 // ReplayConfig configures replay behavior
 type ReplayConfig struct {
-	BatchSize           int           `json:"batch_size"`
+	BatchSize           int           `json:"batch_size"` // entries per batch; 0 means one batch
 	DelayBetweenBatches time.Duration `json:"delay_between_batches"`
-	MaxConcurrent       int           `json:"max_concurrent"`
-	TimeoutPerItem      time.Duration `json:"timeout_per_item"`
+	MaxConcurrent       int           `json:"max_concurrent"`   // deliveries in flight; 0 means 1
+	TimeoutPerItem      time.Duration `json:"timeout_per_item"` // per delivery; 0 means none
+}
+
+// ReplayResult aggregates the outcome of a batch replay
+type ReplayResult struct {
+	Successful int `json:"successful"`
+	Failed     int `json:"failed"`    // left pending for a later retry, or not updated
+	Exhausted  int `json:"exhausted"` // out of retries
 }
 
 // WebhookDelivery represents a webhook delivery attempt
@@ -402,19 +411,32 @@ func (dlh *DeadLetterHook) ArchiveEntry(ctx context.Context, id string) error {
 
 // ReplayEntry replays a single DLH entry
 func (rm *ReplayManager) ReplayEntry(ctx context.Context, entryID string) error {
+	_, err := rm.replayEntry(ctx, entryID)
+	return err
+}
+
+// replayEntry replays a single DLH entry and returns its new status. The
+// delivery, not the status updates, is bound by TimeoutPerItem.
+func (rm *ReplayManager) replayEntry(ctx context.Context, entryID string) (DLHStatus, error) {
 	entry, err := rm.storage.GetByID(ctx, entryID)
 	if err != nil {
-		return fmt.Errorf("failed to get DLH entry: %w", err)
+		return "", fmt.Errorf("failed to get DLH entry: %w", err)
 	}
 
 	// Update status to replaying
 	err = rm.storage.UpdateStatus(ctx, entryID, DLHStatusReplaying)
 	if err != nil {
-		return fmt.Errorf("failed to update status: %w", err)
+		return "", fmt.Errorf("failed to update status: %w", err)
 	}
 
 	// Attempt delivery
-	err = rm.webhookClient.DeliverWebhook(ctx, entry.URL, "", entry.Payload, entry.Headers)
+	deliverCtx := ctx
+	if rm.config.TimeoutPerItem > 0 {
+		var cancel context.CancelFunc
+		deliverCtx, cancel = context.WithTimeout(ctx, rm.config.TimeoutPerItem)
+		defer cancel()
+	}
+	err = rm.webhookClient.DeliverWebhook(deliverCtx, entry.URL, "", entry.Payload, entry.Headers)
 
 	if err != nil {
 		// Update failure count and status
@@ -432,39 +454,113 @@ func (rm *ReplayManager) ReplayEntry(ctx context.Context, entryID string) error
 
 		// Store updated entry
 		_, err = rm.storage.Store(ctx, *entry)
-		return err
+		return entry.Status, err
 	}
 
 	// Success - mark as completed
-	return rm.storage.UpdateStatus(ctx, entryID, DLHStatusCompleted)
+	return DLHStatusCompleted, rm.storage.UpdateStatus(ctx, entryID, DLHStatusCompleted)
 }
 
-// ReplayBatch replays a batch of DLH entries
+// ReplayBatch replays the pending DLH entries matching filter and returns
+// how many were delivered
 func (rm *ReplayManager) ReplayBatch(ctx context.Context, filter DLHFilter) (int, error) {
+	res, err := rm.ReplayBatchContext(ctx, filter)
+	return res.Successful, err
+}
+
+// ReplayBatchContext replays the pending DLH entries matching filter,
+// oldest first, in batches of BatchSize with DelayBetweenBatches between
+// them. Within a batch up to MaxConcurrent deliveries run at once; entries
+// of the same webhook are still delivered one after another, in order.
+// Cancelling ctx stops the run: no new deliveries start, in-flight ones see
+// the cancellation, and the counts so far are returned with ctx's error.
+func (rm *ReplayManager) ReplayBatchContext(ctx context.Context, filter DLHFilter) (ReplayResult, error) {
+	var res ReplayResult
 	entries, err := rm.storage.List(ctx, filter)
 	if err != nil {
-		return 0, fmt.Errorf("failed to list entries: %w", err)
+		return res, fmt.Errorf("failed to list entries: %w", err)
 	}
 
-	var successful int
-
+	var pending []DLHEntry
 	for _, entry := range entries {
-		if entry.Status != DLHStatusPending {
-			continue
+		if entry.Status == DLHStatusPending {
+			pending = append(pending, entry)
 		}
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		if !pending[i].CreatedAt.Equal(pending[j].CreatedAt) {
+			return pending[i].CreatedAt.Before(pending[j].CreatedAt)
+		}
+		return pending[i].ID < pending[j].ID
+	})
 
-		err := rm.ReplayEntry(ctx, entry.ID)
-		if err == nil {
-			successful++
+	batchSize := rm.config.BatchSize
+	if batchSize <= 0 {
+		batchSize = len(pending)
+	}
+	for start := 0; start < len(pending); start += batchSize {
+		if start > 0 && rm.config.DelayBetweenBatches > 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(rm.config.DelayBetweenBatches):
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return res, err
 		}
+		rm.replayBatch(ctx, pending[start:min(start+batchSize, len(pending))], &res)
+	}
+	return res, ctx.Err()
+}
 
-		// Add delay between replays
-		if rm.config.DelayBetweenBatches > 0 {
-			time.Sleep(rm.config.DelayBetweenBatches)
+// replayBatch delivers batch through a pool of MaxConcurrent workers, one
+// webhook's entries per task, and adds the outcomes to res.
+func (rm *ReplayManager) replayBatch(ctx context.Context, batch []DLHEntry, res *ReplayResult) {
+	var order []string
+	lanes := make(map[string][]string)
+	for _, entry := range batch {
+		if _, ok := lanes[entry.WebhookID]; !ok {
+			order = append(order, entry.WebhookID)
 		}
+		lanes[entry.WebhookID] = append(lanes[entry.WebhookID], entry.ID)
+	}
+
+	tasks := make(chan []string, len(order))
+	for _, webhookID := range order {
+		tasks <- lanes[webhookID]
 	}
+	close(tasks)
 
-	return successful, nil
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	workers := min(max(rm.config.MaxConcurrent, 1), len(order))
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ids := range tasks {
+				for _, id := range ids {
+					if ctx.Err() != nil {
+						return
+					}
+					status, err := rm.replayEntry(ctx, id)
+					mu.Lock()
+					switch {
+					case err != nil:
+						res.Failed++
+					case status == DLHStatusCompleted:
+						res.Successful++
+					case status == DLHStatusExhausted:
+						res.Exhausted++
+					default:
+						res.Failed++
+					}
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
 }
 
 // Integration Tests
@@ -760,6 +856,104 @@ func TestReplayManager_BatchReplay(t *testing.T) {
 	assert.Equal(t, 0, client.GetDeliveryCount("https://example.com/completed"))
 }
 
+// slowWebhookClient takes delay per delivery, or until its context ends,
+// and records the most deliveries it saw in flight at once
+type slowWebhookClient struct {
+	delay       time.Duration
+	inFlight    atomic.Int32
+	maxInFlight atomic.Int32
+	delivered   atomic.Int32
+	mu          sync.Mutex
+	order       map[string][]string // URL -> payloads in delivery order
+}
+
+func (c *slowWebhookClient) DeliverWebhook(ctx context.Context, url, secret string, payload []byte, headers map[string]string) error {
+	n := c.inFlight.Add(1)
+	defer c.inFlight.Add(-1)
+	for {
+		m := c.maxInFlight.Load()
+		if n <= m || c.maxInFlight.CompareAndSwap(m, n) {
+			break
+		}
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(c.delay):
+	}
+	c.mu.Lock()
+	if c.order == nil {
+		c.order = make(map[string][]string)
+	}
+	c.order[url] = append(c.order[url], string(payload))
+	c.mu.Unlock()
+	c.delivered.Add(1)
+	return nil
+}
+
+func storePending(t *testing.T, storage DLHStorage, id, webhookID string, payload string) {
+	_, err := storage.Store(context.Background(), DLHEntry{
+		ID:        id,
+		WebhookID: webhookID,
+		URL:       "https://example.com/" + webhookID,
+		Payload:   json.RawMessage(payload),
+		Status:    DLHStatusPending,
+	})
+	assert.NoError(t, err)
+}
+
+func TestReplayManager_BatchReplayRespectsMaxConcurrent(t *testing.T) {
+	storage := NewInMemoryDLHStorage()
+	client := &slowWebhookClient{delay: 20 * time.Millisecond}
+	rm := NewReplayManager(storage, client, ReplayConfig{BatchSize: 8, MaxConcurrent: 3})
+
+	for i := 0; i < 12; i++ {
+		storePending(t, storage, fmt.Sprintf("dlh_%02d", i), fmt.Sprintf("webhook_%d", i), `{}`)
+	}
+	// three entries of one webhook must arrive in order
+	for i := 0; i < 3; i++ {
+		storePending(t, storage, fmt.Sprintf("dlh_seq_%d", i), "webhook_seq", fmt.Sprintf(`{"seq":%d}`, i))
+		time.Sleep(time.Millisecond)
+	}
+
+	start := time.Now()
+	res, err := rm.ReplayBatchContext(context.Background(), DLHFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, ReplayResult{Successful: 15}, res)
+	assert.Equal(t, int32(3), client.maxInFlight.Load())
+	assert.Less(t, time.Since(start), 15*20*time.Millisecond, "replays should overlap")
+	assert.Equal(t, []string{`{"seq":0}`, `{"seq":1}`, `{"seq":2}`}, client.order["https://example.com/webhook_seq"])
+}
+
+func TestReplayManager_BatchReplayContextCancelAndTimeout(t *testing.T) {
+	storage := NewInMemoryDLHStorage()
+	client := &slowWebhookClient{delay: 20 * time.Millisecond}
+	rm := NewReplayManager(storage, client, ReplayConfig{MaxConcurrent: 2})
+	for i := 0; i < 20; i++ {
+		storePending(t, storage, fmt.Sprintf("dlh_%02d", i), fmt.Sprintf("webhook_%d", i), `{}`)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	res, err := rm.ReplayBatchContext(ctx, DLHFilter{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int(client.delivered.Load()), res.Successful)
+	assert.Greater(t, res.Successful, 0)
+	assert.Less(t, res.Successful+res.Failed, 20, "the run should stop early")
+
+	// a delivery slower than TimeoutPerItem fails and stays pending
+	storage = NewInMemoryDLHStorage()
+	storePending(t, storage, "dlh_slow", "webhook_slow", `{}`)
+	rm = NewReplayManager(storage, &slowWebhookClient{delay: time.Second}, ReplayConfig{TimeoutPerItem: 10 * time.Millisecond})
+	res, err = rm.ReplayBatchContext(context.Background(), DLHFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, ReplayResult{Failed: 1}, res)
+	updated, err := storage.GetByID(context.Background(), "dlh_slow")
+	assert.NoError(t, err)
+	assert.Equal(t, DLHStatusPending, updated.Status)
+	assert.Contains(t, updated.LastError, "deadline exceeded")
+}
+
 func TestDLH_ArchiveEntry(t *testing.T) {
 	storage := NewInMemoryDLHStorage()
 	config := DLHConfig{}
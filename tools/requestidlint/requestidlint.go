@@ -1,10 +1,17 @@
 package requestidlint
 
 import (
+	"bytes"
+	"fmt"
 	"go/ast"
 	"go/constant"
+	"go/format"
+	"go/token"
 	"go/types"
+	"net/http"
+	"strconv"
 	"strings"
+	"unicode"
 
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/ast/astutil"
@@ -44,7 +51,7 @@ func run(pass *analysis.Pass) (interface{}, error) {
 				if len(funcStack) > 0 {
 					currentFunc = funcStack[len(funcStack)-1]
 				}
-				inspectCall(pass, node, currentFunc)
+				inspectCall(pass, file, node, currentFunc)
 			}
 			return true
 		}, func(c *astutil.Cursor) bool {
@@ -60,7 +67,7 @@ func run(pass *analysis.Pass) (interface{}, error) {
 	return nil, nil
 }
 
-func inspectCall(pass *analysis.Pass, call *ast.CallExpr, currentFunc string) {
+func inspectCall(pass *analysis.Pass, file *ast.File, call *ast.CallExpr, currentFunc string) {
 	sel, ok := call.Fun.(*ast.SelectorExpr)
 	if !ok {
 		return
@@ -69,7 +76,14 @@ func inspectCall(pass *analysis.Pass, call *ast.CallExpr, currentFunc string) {
 	// Handle package selector (e.g., http.Error)
 	if obj := pass.TypesInfo.Uses[sel.Sel]; obj != nil {
 		if pkg := obj.Pkg(); pkg != nil && pkg.Path() == "net/http" && obj.Name() == "Error" {
-			pass.Reportf(sel.Sel.Pos(), "use writeError helper to ensure X-Request-ID header is set instead of http.Error")
+			diag := analysis.Diagnostic{
+				Pos:     sel.Sel.Pos(),
+				Message: "use writeError helper to ensure X-Request-ID header is set instead of http.Error",
+			}
+			if fix, ok := httpErrorFix(pass, call); ok {
+				diag.SuggestedFixes = []analysis.SuggestedFix{fix}
+			}
+			pass.Report(diag)
 			return
 		}
 	}
@@ -98,15 +112,192 @@ func inspectCall(pass *analysis.Pass, call *ast.CallExpr, currentFunc string) {
 
 	if v, ok := constant.Int64Val(value); ok && v >= 400 {
 		if _, allowed := allowedWriteHeaderFuncs[currentFunc]; !allowed {
-			pass.Reportf(sel.Sel.Pos(), "use writeError helper to ensure X-Request-ID header is set instead of calling WriteHeader directly")
+			diag := analysis.Diagnostic{
+				Pos:     sel.Sel.Pos(),
+				Message: "use writeError helper to ensure X-Request-ID header is set instead of calling WriteHeader directly",
+			}
+			if fix, ok := writeHeaderFix(pass, file, call, sel, v); ok {
+				diag.SuggestedFixes = []analysis.SuggestedFix{fix}
+			}
+			pass.Report(diag)
 		}
 	}
 }
 
+// httpErrorFix rewrites http.Error(w, msg, status) into writeError(w, status, code, msg).
+func httpErrorFix(pass *analysis.Pass, call *ast.CallExpr) (analysis.SuggestedFix, bool) {
+	if !hasWriteErrorHelper(pass) || len(call.Args) != 3 {
+		return analysis.SuggestedFix{}, false
+	}
+
+	writer, message, status := call.Args[0], call.Args[1], call.Args[2]
+	code := errorCodeFor(pass, status)
+	newText := fmt.Sprintf("writeError(%s, %s, %s, %s)",
+		render(pass.Fset, writer), render(pass.Fset, status), strconv.Quote(code), render(pass.Fset, message))
+
+	return analysis.SuggestedFix{
+		Message: "Replace http.Error with writeError",
+		TextEdits: []analysis.TextEdit{{
+			Pos:     call.Pos(),
+			End:     call.End(),
+			NewText: []byte(newText),
+		}},
+	}, true
+}
+
+// writeHeaderFix rewrites w.WriteHeader(status) into writeError(w, status, code, http.StatusText(status)),
+// adding a net/http import when the file does not already have one. writeError writes its own body, so
+// no fix is offered when the handler goes on to use the writer, such as to write a body of its own.
+func writeHeaderFix(pass *analysis.Pass, file *ast.File, call *ast.CallExpr, sel *ast.SelectorExpr, status int64) (analysis.SuggestedFix, bool) {
+	if !hasWriteErrorHelper(pass) || usedAfter(file, call, sel.X) {
+		return analysis.SuggestedFix{}, false
+	}
+
+	httpName, imported := httpImportName(file)
+	statusText := render(pass.Fset, call.Args[0])
+	code := codeFromStatusText(http.StatusText(int(status)))
+	newText := fmt.Sprintf("writeError(%s, %s, %s, %s.StatusText(%s))",
+		render(pass.Fset, sel.X), statusText, strconv.Quote(code), httpName, statusText)
+
+	edits := []analysis.TextEdit{{
+		Pos:     call.Pos(),
+		End:     call.End(),
+		NewText: []byte(newText),
+	}}
+	if !imported {
+		edits = append(edits, importEdit(file, "net/http"))
+	}
+
+	return analysis.SuggestedFix{
+		Message:   "Replace WriteHeader with writeError",
+		TextEdits: edits,
+	}, true
+}
+
+// usedAfter reports whether writer appears in any statement that follows call within its enclosing function.
+func usedAfter(file *ast.File, call *ast.CallExpr, writer ast.Expr) bool {
+	name := types.ExprString(writer)
+	path, _ := astutil.PathEnclosingInterval(file, call.Pos(), call.End())
+	for i, node := range path {
+		var stmts []ast.Stmt
+		switch n := node.(type) {
+		case *ast.FuncDecl, *ast.FuncLit:
+			return false
+		case *ast.BlockStmt:
+			stmts = n.List
+		case *ast.CaseClause:
+			stmts = n.Body
+		case *ast.CommClause:
+			stmts = n.Body
+		default:
+			continue
+		}
+		if i == 0 {
+			continue
+		}
+		for _, stmt := range stmts {
+			if stmt.Pos() <= path[i-1].Pos() {
+				continue
+			}
+			used := false
+			ast.Inspect(stmt, func(n ast.Node) bool {
+				if expr, ok := n.(ast.Expr); ok && !used && types.ExprString(expr) == name {
+					used = true
+				}
+				return !used
+			})
+			if used {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasWriteErrorHelper reports whether the package declares the writeError helper the fixes call into.
+func hasWriteErrorHelper(pass *analysis.Pass) bool {
+	_, ok := pass.Pkg.Scope().Lookup("writeError").(*types.Func)
+	return ok
+}
+
+// errorCodeFor derives an error code from a constant status expression, falling back to a generic code.
+func errorCodeFor(pass *analysis.Pass, status ast.Expr) string {
+	info, ok := pass.TypesInfo.Types[status]
+	if !ok || info.Value == nil || info.Value.Kind() != constant.Int {
+		return "ERROR"
+	}
+	v, ok := constant.Int64Val(info.Value)
+	if !ok {
+		return "ERROR"
+	}
+	return codeFromStatusText(http.StatusText(int(v)))
+}
+
+// codeFromStatusText converts "Internal Server Error" into "INTERNAL_SERVER_ERROR".
+func codeFromStatusText(text string) string {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(fields) == 0 {
+		return "ERROR"
+	}
+	return strings.ToUpper(strings.Join(fields, "_"))
+}
+
+// httpImportName returns the local name net/http is imported under, if any.
+func httpImportName(file *ast.File) (string, bool) {
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || path != "net/http" {
+			continue
+		}
+		if spec.Name == nil {
+			return "http", true
+		}
+		if spec.Name.Name != "_" && spec.Name.Name != "." {
+			return spec.Name.Name, true
+		}
+	}
+	return "http", false
+}
+
+// importEdit adds an import of path to file, joining an existing import block when there is one.
+func importEdit(file *ast.File, path string) analysis.TextEdit {
+	pos := file.Name.End()
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		if gen.Lparen.IsValid() {
+			return analysis.TextEdit{
+				Pos:     gen.Lparen + 1,
+				End:     gen.Lparen + 1,
+				NewText: []byte("\n\t" + strconv.Quote(path)),
+			}
+		}
+		pos = gen.End()
+	}
+	return analysis.TextEdit{
+		Pos:     pos,
+		End:     pos,
+		NewText: []byte("\n\nimport " + strconv.Quote(path)),
+	}
+}
+
+func render(fset *token.FileSet, node ast.Node) string {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, node); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
 func isHTTPResponseWriter(t types.Type) bool {
 	if t == nil {
 		return false
 	}
+	t = types.Unalias(t)
 
 	if named, ok := t.(*types.Named); ok {
 		if obj := named.Obj(); obj != nil && obj.Pkg() != nil && obj.Pkg().Path() == "net/http" && obj.Name() == "ResponseWriter" {
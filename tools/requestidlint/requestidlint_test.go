@@ -10,3 +10,7 @@ import (
 func TestAnalyzer(t *testing.T) {
 	analysistest.Run(t, analysistest.TestData(), requestidlint.Analyzer, "internal/admin-api/good", "internal/admin-api/bad")
 }
+
+func TestAnalyzerSuggestedFixes(t *testing.T) {
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), requestidlint.Analyzer, "internal/admin-api/fix")
+}
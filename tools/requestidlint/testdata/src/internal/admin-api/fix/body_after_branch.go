@@ -0,0 +1,13 @@
+package fix
+
+import (
+	"fmt"
+	"net/http"
+)
+
+func HandlerBodyAfterBranch(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("brew") == "" {
+		w.WriteHeader(http.StatusBadRequest) // want "use writeError helper to ensure X-Request-ID header is set instead of calling WriteHeader directly"
+	}
+	fmt.Fprintln(w, "nothing to brew")
+}
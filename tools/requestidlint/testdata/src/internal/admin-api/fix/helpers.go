@@ -0,0 +1,9 @@
+package fix
+
+import "net/http"
+
+type responder struct {
+	w http.ResponseWriter
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {}
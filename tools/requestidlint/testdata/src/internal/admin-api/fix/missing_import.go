@@ -0,0 +1,8 @@
+package fix
+
+import "log"
+
+func (rs responder) fail() {
+	rs.w.WriteHeader(503) // want "use writeError helper to ensure X-Request-ID header is set instead of calling WriteHeader directly"
+	log.Print("unavailable")
+}
@@ -0,0 +1,11 @@
+package fix
+
+import (
+	"fmt"
+	"net/http"
+)
+
+func HandlerWriteHeader(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusTeapot) // want "use writeError helper to ensure X-Request-ID header is set instead of calling WriteHeader directly"
+	fmt.Fprintln(w, "short and stout")
+}